@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/vcs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func commentCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Post the cost estimate diff from Infracost JSON files as a pull request or commit comment",
+		Long: `Post the cost estimate diff from Infracost JSON files as a pull request or commit comment.
+
+The VCS platform is detected from the repository URL. Supported platforms are: ` + strings.Join(vcs.CommentDriverNames(), ", ") + `.
+The platform's token is read from its environment variable, e.g. GITHUB_TOKEN, GITLAB_TOKEN, BITBUCKET_TOKEN or GITEA_TOKEN.`,
+		Example: `  Post a comment to a GitHub pull request:
+
+      infracost comment --path out.json --repo-url https://github.com/org/repo --pull-request 3
+
+  Post a comment to a commit of a self-hosted GitLab repo:
+
+      infracost comment --path out.json --repo-url git@git.example.com:org/repo.git --platform gitlab --commit $CI_COMMIT_SHA`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := loadInfracostJSONFiles(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs, output.Options{GroupKey: "filename", GroupLabel: "File"})
+			if err != nil {
+				return err
+			}
+
+			repoURL, _ := cmd.Flags().GetString("repo-url")
+			if repoURL == "" {
+				repoURL = commentRepoURL(combined)
+			}
+			if repoURL == "" {
+				ui.PrintUsageErrorAndExit(cmd, "Could not detect the repository URL, use --repo-url to set it")
+			}
+
+			remote, err := vcs.ParseRemote(repoURL)
+			if err != nil {
+				return err
+			}
+
+			var driver vcs.CommentDriver
+			if platform, _ := cmd.Flags().GetString("platform"); platform != "" {
+				driver, err = vcs.CommentDriverByName(platform)
+			} else {
+				driver, err = vcs.CommentDriverForRemote(remote)
+			}
+			if err != nil {
+				return err
+			}
+
+			target := vcs.CommentTarget{}
+			target.PullRequest, _ = cmd.Flags().GetString("pull-request")
+			if target.PullRequest == "" {
+				target.PullRequest = commentPullRequest(combined)
+			}
+			target.Commit, _ = cmd.Flags().GetString("commit")
+			if target.PullRequest == "" && target.Commit == "" {
+				ui.PrintUsageErrorAndExit(cmd, "Either --pull-request or --commit must be set")
+			}
+
+			// Comments are rendered by the VCS platform so they can't include colors
+			color.NoColor = true
+
+			diff, err := output.ToDiff(combined, output.Options{NoColor: true})
+			if err != nil {
+				return errors.Wrap(err, "Error generating output")
+			}
+
+			comment := vcs.Comment{
+				Summary: output.ToCommentSummary(combined),
+				Output:  string(diff),
+			}
+
+			err = driver.PostComment(remote, target, comment)
+			if err != nil {
+				return err
+			}
+
+			if target.PullRequest != "" {
+				fmt.Fprintf(os.Stderr, "Posted comment to %s pull request %s\n", driver.Name(), target.PullRequest)
+			} else {
+				fmt.Fprintf(os.Stderr, "Posted comment to %s commit %s\n", driver.Name(), target.Commit)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	cmd.Flags().String("repo-url", "", "URL of the repository. Defaults to the repository of the projects in the Infracost JSON")
+	cmd.Flags().String("platform", "", fmt.Sprintf("VCS platform to post the comment to: %s. Defaults to detecting it from the repository URL", strings.Join(vcs.CommentDriverNames(), ", ")))
+	cmd.Flags().String("pull-request", "", "Pull request or merge request number to post the comment to. Defaults to the pull request in the Infracost JSON")
+	cmd.Flags().String("commit", "", "Commit SHA to post the comment to if there is no pull request")
+
+	_ = cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return vcs.CommentDriverNames(), cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// commentRepoURL returns the repository URL from the projects' metadata
+func commentRepoURL(out output.Root) string {
+	if u := os.Getenv("INFRACOST_VCS_REPOSITORY_URL"); u != "" {
+		return u
+	}
+
+	for _, p := range out.Projects {
+		if p.Metadata != nil && p.Metadata.VCSRepoURL != "" {
+			return p.Metadata.VCSRepoURL
+		}
+	}
+
+	return ""
+}
+
+// commentPullRequest returns the pull request number from the projects' metadata
+func commentPullRequest(out output.Root) string {
+	for _, p := range out.Projects {
+		if p.Metadata == nil || p.Metadata.VCSPullRequestURL == "" {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimSuffix(p.Metadata.VCSPullRequestURL, "/"), "/")
+		if _, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			return parts[len(parts)-1]
+		}
+	}
+
+	return ""
+}
@@ -20,10 +20,14 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
       terraform plan -out tfplan.binary
       terraform show -json tfplan.binary > plan.json
-      infracost diff --path plan.json`,
+      infracost diff --path plan.json
+
+  Show the diff in a unified diff format for other diff tools:
+
+      infracost diff --path plan.json --format diff-text | colordiff`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+			if err := checkAPIKey(ctx.Config); err != nil {
 				return err
 			}
 
@@ -42,7 +46,9 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 				ui.PrintUsageErrorAndExit(cmd, err.Error())
 			}
 
-			ctx.Config.Format = "diff"
+			if ctx.Config.Format != "diff-text" {
+				ctx.Config.Format = "diff"
+			}
 
 			return runMain(cmd, ctx)
 		},
@@ -50,6 +56,12 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
 
 	addRunFlags(cmd)
 
+	cmd.Flags().String("format", "diff", "Output format: diff, diff-text")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"diff", "diff-text"}, cobra.ShellCompDirectiveDefault
+	})
+
 	return cmd
 }
 
@@ -82,11 +82,19 @@ func main() {
 
 	rootCmd.PersistentFlags().Bool("no-color", false, "Turn off colored output")
 	rootCmd.PersistentFlags().String("log-level", "", "Log level (trace, debug, info, warn, error, fatal)")
+	rootCmd.PersistentFlags().String("tls-ca-cert-file", "", "Path to a PEM CA bundle to trust for requests to the Cloud Pricing API and Infracost's APIs")
+	rootCmd.PersistentFlags().Bool("tls-insecure-skip-verify", false, "Skip TLS certificate verification for requests to the Cloud Pricing API and Infracost's APIs")
 
 	rootCmd.AddCommand(registerCmd(ctx))
 	rootCmd.AddCommand(diffCmd(ctx))
 	rootCmd.AddCommand(breakdownCmd(ctx))
 	rootCmd.AddCommand(outputCmd(ctx))
+	rootCmd.AddCommand(pricingCmd(ctx))
+	rootCmd.AddCommand(uploadCmd(ctx))
+	rootCmd.AddCommand(commentCmd(ctx))
+	rootCmd.AddCommand(terraformDataSourceCmd(ctx))
+	rootCmd.AddCommand(usageCmd(ctx))
+	rootCmd.AddCommand(sensitivityCmd(ctx))
 	rootCmd.AddCommand(completionCmd())
 
 	rootCmd.SetUsageTemplate(fmt.Sprintf(`%s{{if .Runnable}}
@@ -138,7 +146,17 @@ func startUpdateCheck(ctx *config.RunContext, c chan *update.Info) {
 	}()
 }
 
-func checkAPIKey(apiKey string, apiEndpoint string, defaultEndpoint string) error {
+func checkAPIKey(cfg *config.Config) error {
+	// No API key is needed if the prices are embedded in the binary or looked up from a price database
+	if apiclient.HasEmbeddedPricing() || cfg.IsOffline() {
+		return nil
+	}
+
+	return checkPricingAPIKey(cfg.APIKey, cfg.PricingAPIEndpoint, cfg.DefaultPricingAPIEndpoint)
+}
+
+// checkPricingAPIKey checks there's an API key for commands that always use the Cloud Pricing API
+func checkPricingAPIKey(apiKey string, apiEndpoint string, defaultEndpoint string) error {
 	if apiEndpoint == defaultEndpoint && apiKey == "" {
 		return errors.New(fmt.Sprintf(
 			"No INFRACOST_API_KEY environment variable is set.\nWe run a free Cloud Pricing API, to get an API key run %s",
@@ -209,6 +227,14 @@ func loadGlobalFlags(ctx *config.RunContext, cmd *cobra.Command) error {
 		}
 	}
 
+	if cmd.Flags().Changed("tls-ca-cert-file") {
+		ctx.Config.TLSCACertFile, _ = cmd.Flags().GetString("tls-ca-cert-file")
+	}
+
+	if cmd.Flags().Changed("tls-insecure-skip-verify") {
+		ctx.Config.TLSInsecureSkipVerify, _ = cmd.Flags().GetBool("tls-insecure-skip-verify")
+	}
+
 	if cmd.Flags().Changed("pricing-api-endpoint") {
 		ctx.Config.PricingAPIEndpoint, _ = cmd.Flags().GetString("pricing-api-endpoint")
 	}
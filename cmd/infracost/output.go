@@ -32,39 +32,22 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 
   Merge multiple Infracost JSON files:
 
-      infracost output --format json --path out*.json`,
-		ValidArgs: []string{"--", "-"},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			inputFiles := []string{}
+      infracost output --format json --path out*.json
 
-			paths, _ := cmd.Flags().GetStringArray("path")
-			for _, path := range paths {
-				matches, _ := filepath.Glob(path)
-				inputFiles = append(inputFiles, matches...)
-			}
+  Show the cost diff in a unified diff format for other diff tools:
 
-			inputs := make([]output.ReportInput, 0, len(inputFiles))
-			for _, f := range inputFiles {
-				data, err := ioutil.ReadFile(f)
-				if err != nil {
-					return errors.Wrap(err, "Error reading JSON file")
-				}
+      infracost output --format diff-text --path out.json | colordiff
 
-				j, err := output.Load(data)
-				if err != nil {
-					return errors.Wrap(err, "Error parsing JSON file")
-				}
+  Anonymize an Infracost JSON file to attach to a bug report:
 
-				if !checkOutputVersion(j.Version) {
-					return fmt.Errorf("Invalid Infracost JSON file version. Supported versions are %s ≤ x ≤ %s", minOutputVersion, maxOutputVersion)
-				}
+      infracost output --format json --anonymize --path out.json`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, _ := cmd.Flags().GetStringArray("path")
 
-				inputs = append(inputs, output.ReportInput{
-					Metadata: map[string]string{
-						"filename": f,
-					},
-					Root: j,
-				})
+			inputs, err := loadInfracostJSONFiles(paths)
+			if err != nil {
+				return err
 			}
 
 			format, _ := cmd.Flags().GetString("format")
@@ -97,13 +80,23 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				Fields:           fields,
 			}
 			opts.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+			opts.ShowUsageProvenance, _ = cmd.Flags().GetBool("show-usage-provenance")
+			opts.ReconcileRounding, _ = cmd.Flags().GetBool("reconcile-rounding")
+
+			combined, err := output.Combine(inputs, opts)
+			if err != nil {
+				return err
+			}
 
-			combined := output.Combine(inputs, opts)
+			if anonymize, _ := cmd.Flags().GetBool("anonymize"); anonymize {
+				key, _ := cmd.Flags().GetString("anonymize-key")
+				combined, err = output.Anonymize(combined, []byte(key))
+				if err != nil {
+					return err
+				}
+			}
 
-			var (
-				b   []byte
-				err error
-			)
+			var b []byte
 
 			validFieldsFormats := []string{"table", "html"}
 
@@ -117,6 +110,8 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				b, err = output.ToHTML(combined, opts)
 			case "diff":
 				b, err = output.ToDiff(combined, opts)
+			case "diff-text":
+				b, err = output.ToDiffText(combined, opts)
 			default:
 				b, err = output.ToTable(combined, opts)
 			}
@@ -134,17 +129,57 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
 
-	cmd.Flags().String("format", "table", "Output format: json, diff, table, html")
+	cmd.Flags().String("format", "table", "Output format: json, diff, diff-text, table, html")
 	cmd.Flags().Bool("show-skipped", false, "Show unsupported resources, some of which might be free")
+	cmd.Flags().Bool("show-usage-provenance", false, "Show where the usage for each usage-based cost component came from")
+	cmd.Flags().Bool("reconcile-rounding", false, "Add rounding adjustment lines so the costs add up to the totals. Supported by table and html output formats")
+	cmd.Flags().Bool("anonymize", false, "Replace resource names, tag values and project details with pseudonyms so the output can be shared publicly")
+	cmd.Flags().String("anonymize-key", "", "Secret key for the anonymize pseudonyms, so the same values get the same pseudonyms across runs. A random key is used by default")
 	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
 
 	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"table", "json", "html"}, cobra.ShellCompDirectiveDefault
+		return []string{"table", "json", "html", "diff", "diff-text"}, cobra.ShellCompDirectiveDefault
 	})
 
 	return cmd
 }
 
+// loadInfracostJSONFiles loads the Infracost JSON files matching the paths, which can be globs
+func loadInfracostJSONFiles(paths []string) ([]output.ReportInput, error) {
+	inputFiles := []string{}
+
+	for _, path := range paths {
+		matches, _ := filepath.Glob(path)
+		inputFiles = append(inputFiles, matches...)
+	}
+
+	inputs := make([]output.ReportInput, 0, len(inputFiles))
+	for _, f := range inputFiles {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return inputs, errors.Wrap(err, "Error reading JSON file")
+		}
+
+		j, err := output.Load(data)
+		if err != nil {
+			return inputs, errors.Wrap(err, "Error parsing JSON file")
+		}
+
+		if !checkOutputVersion(j.Version) {
+			return inputs, fmt.Errorf("Invalid Infracost JSON file version. Supported versions are %s ≤ x ≤ %s", minOutputVersion, maxOutputVersion)
+		}
+
+		inputs = append(inputs, output.ReportInput{
+			Metadata: map[string]string{
+				"filename": f,
+			},
+			Root: j,
+		})
+	}
+
+	return inputs, nil
+}
+
 func checkOutputVersion(v string) bool {
 	if !strings.HasPrefix(v, "v") {
 		v = "v" + v
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// The Cloud Pricing API vendor names for the Terraform providers
+var pricingVendorNames = map[string]string{
+	"aws":     "aws",
+	"google":  "gcp",
+	"azurerm": "azure",
+}
+
+// The services that Infracost looks up prices for, which are downloaded if no services are specified
+var pricingServices = map[string][]string{
+	"aws": {
+		"AWSCertificateManager", "AWSCloudFormation", "AWSConfig", "AWSDataTransfer", "AWSDatabaseMigrationSvc",
+		"AWSDirectConnect", "AWSELB", "AWSEvents", "AWSLambda", "AWSQueueService", "AWSSecretsManager",
+		"AWSSystemsManager", "AmazonApiGateway", "AmazonCloudFront", "AmazonCloudWatch", "AmazonDocDB",
+		"AmazonDynamoDB", "AmazonEC2", "AmazonECR", "AmazonECS", "AmazonEFS", "AmazonEI", "AmazonEKS", "AmazonES",
+		"AmazonElastiCache", "AmazonFSx", "AmazonKinesisAnalytics", "AmazonKinesisFirehose", "AmazonLightsail",
+		"AmazonMQ", "AmazonMSK", "AmazonNeptune", "AmazonRDS", "AmazonRedshift", "AmazonRoute53", "AmazonS3",
+		"AmazonSNS", "AmazonStates", "AmazonVPC", "CodeBuild", "awskms", "awswaf",
+	},
+	"gcp": {
+		"BigQuery", "Cloud DNS", "Cloud Functions", "Cloud Key Management Service (KMS)", "Cloud Logging",
+		"Cloud Memorystore for Redis", "Cloud Pub/Sub", "Cloud SQL", "Cloud Storage", "Compute Engine",
+		"Kubernetes Engine", "Stackdriver Monitoring",
+	},
+	"azure": {
+		"API Management", "Application Gateway", "Application Insights", "Automation", "Azure App Service",
+		"Azure Cognitive Search", "Azure Cosmos DB", "Azure DNS", "Azure Database for PostgreSQL", "Azure Databricks",
+		"Azure Firewall", "Azure Kubernetes Service", "Container Registry", "Content Delivery Network", "Event Hubs",
+		"Functions", "HDInsight", "Key Vault", "Load Balancer", "Logic Apps", "NAT Gateway", "Notification Hubs",
+		"Redis Cache", "SQL Database", "Storage", "Virtual Machines", "Virtual Network",
+	},
+}
+
+func pricingCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage prices for offline use",
+		Long:  "Manage prices for offline use",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(pricingDownloadCmd(ctx))
+
+	return cmd
+}
+
+func pricingDownloadCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download a price database so prices can be looked up without the Cloud Pricing API",
+		Long: `Download a price database so prices can be looked up without the Cloud Pricing API.
+
+Set INFRACOST_PRICING_DATABASE to the path of the downloaded file to look up all prices from it.
+This can be used in environments that can't access the Cloud Pricing API.`,
+		Example: `  Download the AWS prices for us-east-1 and eu-west-1:
+
+      infracost pricing download --provider aws --region us-east-1 --region eu-west-1 --output prices.json.gz
+
+  Use the downloaded prices:
+
+      INFRACOST_PRICING_DATABASE=prices.json.gz infracost breakdown --path plan.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkPricingAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			providers, _ := cmd.Flags().GetStringSlice("provider")
+			regions, _ := cmd.Flags().GetStringSlice("region")
+			services, _ := cmd.Flags().GetStringSlice("service")
+			out, _ := cmd.Flags().GetString("output")
+
+			filters, err := pricingDownloadFilters(providers, regions, services)
+			if err != nil {
+				ui.PrintUsageErrorAndExit(cmd, err.Error())
+			}
+
+			c, err := apiclient.NewPricingAPIClient(ctx.Config)
+			if err != nil {
+				return err
+			}
+
+			spinner = ui.NewSpinner("Downloading prices", ui.SpinnerOptions{
+				EnableLogging: ctx.Config.IsLogging(),
+				NoColor:       ctx.Config.NoColor,
+			})
+
+			count, err := c.DownloadPriceDatabase(filters, out, func(f apiclient.PriceDatabaseFilter, n int) {
+				log.Debugf("Downloaded %d products for %s %s %s", n, f.VendorName, f.Service, f.Region)
+			})
+			if err != nil {
+				return errors.Wrap(err, "Error downloading prices")
+			}
+
+			spinner.Success()
+
+			fmt.Fprintf(os.Stderr, "\nSaved %d products to %s\nSet %s to use them.\n",
+				count,
+				ui.PrimaryString(out),
+				ui.PrimaryString(fmt.Sprintf("INFRACOST_PRICING_DATABASE=%s", out)),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("provider", []string{"aws", "google", "azurerm"}, "Terraform providers to download prices for: aws, google, azurerm")
+	cmd.Flags().StringSlice("region", []string{}, "Regions to download prices for, e.g. us-east-1. Global prices are always included")
+	cmd.Flags().StringSlice("service", []string{}, "Cloud Pricing API services to download prices for, e.g. AmazonEC2. Defaults to all the services Infracost supports")
+	cmd.Flags().String("output", "infracost-prices.json.gz", "Path to save the price database to")
+
+	_ = cmd.MarkFlagRequired("region")
+
+	_ = cmd.RegisterFlagCompletionFunc("provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"aws", "google", "azurerm"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// pricingDownloadFilters returns a filter for each service and region of the providers. The
+// global region is added since some services, e.g. Cloud DNS, only have global prices.
+func pricingDownloadFilters(providers []string, regions []string, services []string) ([]apiclient.PriceDatabaseFilter, error) {
+	filters := make([]apiclient.PriceDatabaseFilter, 0)
+
+	if !contains(regions, "global") {
+		regions = append(regions, "global")
+	}
+
+	for _, provider := range providers {
+		vendorName, ok := pricingVendorNames[strings.ToLower(provider)]
+		if !ok {
+			return filters, fmt.Errorf("Invalid provider %s, valid providers are: aws, google, azurerm", provider)
+		}
+
+		providerServices := services
+		if len(providerServices) == 0 {
+			providerServices = pricingServices[vendorName]
+		}
+
+		for _, service := range providerServices {
+			for _, region := range regions {
+				filters = append(filters, apiclient.PriceDatabaseFilter{
+					VendorName: vendorName,
+					Service:    service,
+					Region:     region,
+				})
+			}
+		}
+	}
+
+	return filters, nil
+}
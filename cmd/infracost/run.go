@@ -2,47 +2,95 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/exports"
 	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/policy"
 	"github.com/infracost/infracost/internal/prices"
 	"github.com/infracost/infracost/internal/providers"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/infracost/infracost/internal/usage"
+	"github.com/infracost/infracost/internal/vcs"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
 func addRunFlags(cmd *cobra.Command) {
-	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file")
+	cmd.Flags().StringP("path", "p", "", "Path to the Terraform directory or JSON/plan file, or a git source, e.g. git::https://github.com/org/repo//stacks/prod?ref=main")
 
 	cmd.Flags().String("config-file", "", "Path to Infracost config file. Cannot be used with path, terraform* or usage-file flags")
-	cmd.Flags().String("usage-file", "", "Path to Infracost usage file that specifies values for usage-based resources")
+	cmd.Flags().String("usage-file", "", "Path to Infracost usage file in YAML or JSON that specifies values for usage-based resources")
 
 	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable when path is a Terraform directory")
 	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when path is a Terraform directory")
+	cmd.Flags().StringArray("terraform-var-file", nil, "Path to a Terraform variables file, can be repeated. Applicable when path is a Terraform directory")
+	cmd.Flags().StringArray("terraform-var", nil, "Terraform variable in the form name=value, can be repeated. Applicable when path is a Terraform directory")
+	cmd.Flags().StringArray("terraform-env-file", nil, "Path to a file of TF_VAR_ environment variables, can be repeated. Applicable when path is a Terraform directory")
 
 	cmd.Flags().Bool("show-skipped", false, "Show unsupported resources, some of which might be free")
-
-	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with missing resources, needs usage-file too (experimental)")
-
-	_ = cmd.MarkFlagFilename("path", "json", "tf")
+	cmd.Flags().Bool("show-usage-provenance", false, "Show where the usage for each usage-based cost component came from")
+
+	cmd.Flags().String("usage-from-json", "", "Path to the JSON output of a previous run whose usage is used for the resources, unless the usage-file has their usage")
+	cmd.Flags().String("usage-profile", "", "Built-in usage profile for resources without usage in the usage-file: low, medium or high")
+	cmd.Flags().Bool("sync-usage-file", false, "Sync usage-file with the missing usage keys of the resources, needs usage-file too (experimental)")
+	cmd.Flags().Bool("fetch-usage-from-cloudwatch", false, "Fetch the usage of existing AWS resources from CloudWatch, needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (experimental)")
+	cmd.Flags().Bool("fetch-usage-from-cost-explorer", false, "Fetch the usage of existing AWS resources from Cost Explorer resource level data, needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (experimental)")
+	cmd.Flags().Bool("fetch-usage-from-cloud-monitoring", false, "Fetch the usage of existing Google Cloud resources from Cloud Monitoring, needs the same credentials as the google Terraform provider (experimental)")
+	cmd.Flags().Bool("fetch-usage-from-azure-monitor", false, "Fetch the usage of existing Azure resources from Azure Monitor, needs ARM_TENANT_ID, ARM_CLIENT_ID and ARM_CLIENT_SECRET (experimental)")
+
+	cmd.Flags().Bool("no-cache", false, "Don't use the on-disk caches of prices from the Cloud Pricing API and Terraform plan JSON")
+	cmd.Flags().Bool("refresh-cache", false, "Query all prices from the Cloud Pricing API, regenerate the Terraform plan JSON and refresh the on-disk caches")
+	cmd.Flags().String("price-overrides-file", "", "Path to a file of prices that replace or adjust the prices from the Cloud Pricing API")
+	cmd.Flags().String("currency", "", "ISO 4217 code of the currency to show prices in, e.g. EUR (default \"USD\")")
+
+	_ = cmd.MarkFlagFilename("path", "json", "tf", "tfplan")
 	_ = cmd.MarkFlagFilename("config-file", "yml")
-	_ = cmd.MarkFlagFilename("usage-file", "yml")
+	_ = cmd.MarkFlagFilename("usage-file", "yml", "yaml", "json")
+	_ = cmd.MarkFlagFilename("usage-from-json", "json")
+	_ = cmd.MarkFlagFilename("price-overrides-file", "yml")
+	_ = cmd.MarkFlagFilename("terraform-var-file", "tfvars", "json")
 }
 
 func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	projects := make([]*schema.Project, 0)
 	projectContexts := make([]*config.ProjectContext, 0)
 
-	for _, projectCfg := range runCtx.Config.Projects {
+	policyPacks, err := policy.LoadPacks(runCtx.Config.PolicyPacks, apiclient.NewExternalHTTPClient(runCtx.Config))
+	if err != nil {
+		return errors.Wrap(err, "Error loading policy packs")
+	}
+
+	priceOverrides, err := prices.LoadPriceOverrides(runCtx.Config.PriceOverridesFile)
+	if err != nil {
+		return err
+	}
+
+	replayedUsage, err := usage.LoadReplayedUsage(runCtx.Config.UsageFromJSON)
+	if err != nil {
+		return err
+	}
+
+	projectCfgs, cleanup, err := cloneGitProjects(runCtx.Config)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	for _, projectCfg := range projectCfgs {
 		ctx := config.NewProjectContext(runCtx, projectCfg)
 		runCtx.SetCurrentProjectContext(ctx)
 
@@ -50,7 +98,7 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		if err != nil {
 			m := fmt.Sprintf("%s\n\n", err)
 			m += fmt.Sprintf("Use the %s flag to specify the path to one of the following:\n", ui.PrimaryString("--path"))
-			m += " - Terraform plan JSON file\n - Terraform directory\n - Terraform plan file"
+			m += " - Terraform plan JSON file\n - Terraform directory\n - Terragrunt or CDK for Terraform directory\n - Terraform plan file"
 
 			if cmd.Name() != "diff" {
 				m += "\n - Terraform state JSON file"
@@ -68,7 +116,12 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 			return clierror.NewSanitizedError(errors.New(m), "Cannot use Terraform state JSON with the infracost diff command")
 		}
 
-		m := fmt.Sprintf("Detected %s at %s", provider.DisplayType(), ui.DisplayPath(projectCfg.Path))
+		displayPath := ui.DisplayPath(ctx.ProjectConfig.Path)
+		if ctx.ProjectConfig.SourcePath != "" {
+			displayPath = ctx.ProjectConfig.SourcePath
+		}
+
+		m := fmt.Sprintf("Detected %s at %s", provider.DisplayType(), displayPath)
 		if runCtx.Config.IsLogging() {
 			log.Info(m)
 		} else {
@@ -79,15 +132,38 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		if err != nil {
 			return err
 		}
+
+		baseUsage, err := usage.LoadFromFile(projectCfg.BaseUsageFile, false)
+		if err != nil {
+			return err
+		}
+		u = usage.MergeUsageData(baseUsage, u)
 		if len(u) > 0 {
 			ctx.SetContextValue("hasUsageFile", true)
 		}
 
 		metadata := config.DetectProjectMetadata(ctx)
 		metadata.Type = provider.Type()
+		metadata.UsageProfile = runCtx.Config.UsageProfile
 		provider.AddMetadata(metadata)
 		name := schema.GenerateProjectName(metadata, runCtx.Config.EnableDashboard)
 
+		// The replayed usage is matched to the project by its name, so it's merged once
+		// the name is known. It's applied before the usage profile so the profile is only
+		// used for the resources that didn't have usage in the previous run either.
+		if runCtx.Config.UsageFromJSON != "" {
+			u = usage.MergeUsageData(replayedUsage.ProjectUsage(name), u)
+			ctx.SetContextValue("hasUsageFromJSON", true)
+		}
+
+		if runCtx.Config.UsageProfile != "" {
+			err = usage.ApplyUsageProfile(u, runCtx.Config.UsageProfile)
+			if err != nil {
+				return err
+			}
+			ctx.SetContextValue("usageProfile", runCtx.Config.UsageProfile)
+		}
+
 		project := schema.NewProject(name, metadata)
 		err = provider.LoadResources(project, u)
 		if err != nil {
@@ -97,12 +173,19 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		projects = append(projects, project)
 
 		if runCtx.Config.SyncUsageFile {
-			err = usage.SyncUsageData(project, u, projectCfg.UsageFile)
+			err = usage.SyncUsageDataWithBase(project, projectCfg.UsageFile, baseUsage)
 			if err != nil {
 				return err
 			}
 		}
 
+		if runCtx.Config.SampleSize > 0 {
+			project.Sample(runCtx.Config.SampleSize)
+			if project.Sampling != nil {
+				ctx.SetContextValue("isSampled", true)
+			}
+		}
+
 		if !runCtx.Config.IsLogging() {
 			fmt.Fprintln(os.Stderr, "")
 		}
@@ -114,8 +197,13 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	}
 	spinner := ui.NewSpinner("Calculating monthly cost estimate", spinnerOpts)
 
-	for _, project := range projects {
-		if err := prices.PopulatePrices(runCtx.Config, project); err != nil {
+	for i, project := range projects {
+		// The variants are created before the project is priced, since the resources that
+		// they share with it are copied before they're priced
+		rangeLow, rangeHigh := project.UsageRangeProjects()
+		sensitivityLow, sensitivityHigh := project.UsageSensitivityProjects()
+
+		if err := priceProject(runCtx, projectContexts[i].ProjectConfig, project, priceOverrides); err != nil {
 			spinner.Fail()
 			fmt.Fprintln(os.Stderr, "")
 
@@ -138,15 +226,29 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 			return err
 		}
 
-		schema.CalculateCosts(project)
 		project.CalculateDiff()
+
+		for _, variant := range []*schema.Project{rangeLow, rangeHigh, sensitivityLow, sensitivityHigh} {
+			if variant == nil {
+				continue
+			}
+
+			if err := priceProject(runCtx, projectContexts[i].ProjectConfig, variant, priceOverrides); err != nil {
+				spinner.Fail()
+				return err
+			}
+		}
 	}
 
 	spinner.Success()
 
-	r := output.ToOutputFormat(projects)
+	if cmd.Name() == "sensitivity" {
+		return printSensitivity(runCtx, projects)
+	}
 
-	var err error
+	r := output.ToOutputFormat(projects)
+	r.Currency = runCtx.Config.Currency
+	r.Environments = buildEnvironments(runCtx.Config.Environments, r.Projects)
 
 	c := apiclient.NewDashboardAPIClient(runCtx)
 	r.RunID, err = c.AddRun(runCtx, projectContexts, r)
@@ -161,11 +263,18 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		log.Errorf("Error reporting event: %s", err)
 	}
 
+	err = exports.Export(runCtx.Config.Exports, apiclient.NewExternalHTTPClient(runCtx.Config), projects, r.TimeGenerated)
+	if err != nil {
+		log.Errorf("Error exporting run: %s", err)
+	}
+
 	opts := output.Options{
-		DashboardEnabled: runCtx.Config.EnableDashboard,
-		ShowSkipped:      runCtx.Config.ShowSkipped,
-		NoColor:          runCtx.Config.NoColor,
-		Fields:           runCtx.Config.Fields,
+		DashboardEnabled:    runCtx.Config.EnableDashboard,
+		ShowSkipped:         runCtx.Config.ShowSkipped,
+		ShowUsageProvenance: runCtx.Config.ShowUsageProvenance,
+		ReconcileRounding:   runCtx.Config.ReconcileRounding,
+		NoColor:             runCtx.Config.NoColor,
+		Fields:              runCtx.Config.Fields,
 	}
 
 	var (
@@ -183,6 +292,9 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 	case "diff":
 		b, err = output.ToDiff(r, opts)
 		out = fmt.Sprintf("\n%s", string(b))
+	case "diff-text":
+		b, err = output.ToDiffText(r, opts)
+		out = strings.TrimSuffix(string(b), "\n")
 	default:
 		b, err = output.ToTable(r, opts)
 		out = fmt.Sprintf("\n%s", string(b))
@@ -194,6 +306,37 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 
 	fmt.Printf("%s\n", out)
 
+	for _, v := range policy.CheckThresholds(policyPacks, r.Projects, r.Currency) {
+		ui.PrintWarningf("Policy threshold exceeded for %s", v)
+	}
+
+	return nil
+}
+
+// priceProject prices the resources of a project, or of a project's usage variants, with
+// the pre-pricing mutators, the prices from the Cloud Pricing API, the post-pricing mutators,
+// the savings plans and committed use discounts of the project, and then the discounts and
+// price overrides.
+func priceProject(runCtx *config.RunContext, projectCfg *config.Project, project *schema.Project, priceOverrides []*prices.PriceOverride) error {
+	if err := schema.RunPrePricingMutators(project); err != nil {
+		return err
+	}
+
+	if err := prices.PopulatePrices(runCtx.Config, project); err != nil {
+		return err
+	}
+
+	schema.CalculateCosts(project)
+
+	if err := schema.RunPostPricingMutators(project); err != nil {
+		return err
+	}
+
+	prices.ApplySavingsPlans(project, projectCfg.SavingsPlans)
+	prices.ApplyCommittedUseDiscounts(project, projectCfg.CommittedUseDiscounts)
+	prices.ApplyDiscounts(project, runCtx.Config.Discounts)
+	prices.ApplyPriceOverrides(project, priceOverrides)
+
 	return nil
 }
 
@@ -213,6 +356,9 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		cmd.Flags().Changed("usage-file") ||
 		cmd.Flags().Changed("terraform-plan-flags") ||
 		cmd.Flags().Changed("terraform-workspace") ||
+		cmd.Flags().Changed("terraform-var-file") ||
+		cmd.Flags().Changed("terraform-var") ||
+		cmd.Flags().Changed("terraform-env-file") ||
 		cmd.Flags().Changed("terraform-use-state"))
 
 	if hasConfigFile && hasProjectFlags {
@@ -251,11 +397,66 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 		projectCfg.TerraformPlanFlags, _ = cmd.Flags().GetString("terraform-plan-flags")
 		projectCfg.TerraformWorkspace, _ = cmd.Flags().GetString("terraform-workspace")
 		projectCfg.TerraformUseState, _ = cmd.Flags().GetBool("terraform-use-state")
+		projectCfg.TerraformVarFiles, _ = cmd.Flags().GetStringArray("terraform-var-file")
+		projectCfg.TerraformEnvFiles, _ = cmd.Flags().GetStringArray("terraform-env-file")
+
+		vars, _ := cmd.Flags().GetStringArray("terraform-var")
+		if len(vars) > 0 {
+			projectCfg.TerraformVars = make(map[string]string, len(vars))
+		}
+
+		for _, v := range vars {
+			parts := strings.SplitN(v, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid --terraform-var '%s', expected the form name=value", v))
+			}
+			projectCfg.TerraformVars[parts[0]] = parts[1]
+		}
 	}
 
 	cfg.Format, _ = cmd.Flags().GetString("format")
 	cfg.ShowSkipped, _ = cmd.Flags().GetBool("show-skipped")
+	cfg.ShowUsageProvenance, _ = cmd.Flags().GetBool("show-usage-provenance")
+	cfg.ReconcileRounding, _ = cmd.Flags().GetBool("reconcile-rounding")
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
+	cfg.FetchUsageFromCloudWatch, _ = cmd.Flags().GetBool("fetch-usage-from-cloudwatch")
+	cfg.FetchUsageFromCostExplorer, _ = cmd.Flags().GetBool("fetch-usage-from-cost-explorer")
+	cfg.FetchUsageFromCloudMonitoring, _ = cmd.Flags().GetBool("fetch-usage-from-cloud-monitoring")
+	cfg.FetchUsageFromAzureMonitor, _ = cmd.Flags().GetBool("fetch-usage-from-azure-monitor")
+	cfg.SampleSize, _ = cmd.Flags().GetInt("sample-size")
+	cfg.NoCache, _ = cmd.Flags().GetBool("no-cache")
+	cfg.RefreshCache, _ = cmd.Flags().GetBool("refresh-cache")
+
+	if cmd.Flags().Changed("price-overrides-file") {
+		cfg.PriceOverridesFile, _ = cmd.Flags().GetString("price-overrides-file")
+	}
+
+	if cmd.Flags().Changed("currency") {
+		cfg.Currency, _ = cmd.Flags().GetString("currency")
+	}
+
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	if !currencyRegex.MatchString(cfg.Currency) {
+		ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid currency '%s', expected an ISO 4217 code such as USD or EUR", cfg.Currency))
+	}
+
+	if cmd.Flags().Changed("usage-from-json") {
+		cfg.UsageFromJSON, _ = cmd.Flags().GetString("usage-from-json")
+	}
+
+	if cmd.Flags().Changed("usage-profile") {
+		cfg.UsageProfile, _ = cmd.Flags().GetString("usage-profile")
+	}
+
+	if cfg.UsageProfile != "" {
+		profiles, err := usage.UsageProfileNames()
+		if err != nil {
+			return err
+		}
+		if !contains(profiles, cfg.UsageProfile) {
+			ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid usage profile '%s', expected one of: %s", cfg.UsageProfile, strings.Join(profiles, ", ")))
+		}
+	}
 
 	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
 	validFieldsFormats := []string{"table", "html"}
@@ -321,6 +522,80 @@ func buildRunEnv(runCtx *config.RunContext, projectContexts []*config.ProjectCon
 	return env
 }
 
+// cloneGitProjects clones the projects with a git source path, e.g.
+// git::https://github.com/org/repo//stacks/prod?ref=main, and replaces each of them with the
+// projects that are found in the clone. The returned func removes the clones.
+func cloneGitProjects(cfg *config.Config) ([]*config.Project, func(), error) {
+	cfgs := make([]*config.Project, 0, len(cfg.Projects))
+	tmpDirs := make([]string, 0)
+
+	cleanup := func() {
+		for _, dir := range tmpDirs {
+			os.RemoveAll(dir)
+		}
+	}
+
+	for _, projectCfg := range cfg.Projects {
+		if !vcs.IsGitSource(projectCfg.Path) {
+			cfgs = append(cfgs, projectCfg)
+			continue
+		}
+
+		src, err := vcs.ParseGitSource(projectCfg.Path)
+		if err != nil {
+			return cfgs, cleanup, err
+		}
+
+		tmpDir, err := ioutil.TempDir("", "infracost-git")
+		if err != nil {
+			return cfgs, cleanup, errors.Wrap(err, "Error creating directory for git clone")
+		}
+		tmpDirs = append(tmpDirs, tmpDir)
+
+		m := fmt.Sprintf("Cloning %s", projectCfg.Path)
+		if cfg.IsLogging() {
+			log.Info(m)
+		} else {
+			fmt.Fprintln(os.Stderr, m)
+		}
+
+		repoDir := filepath.Join(tmpDir, "repo")
+		path, err := vcs.Clone(src, repoDir)
+		if err != nil {
+			return cfgs, cleanup, err
+		}
+
+		dirs, err := providers.FindProjectDirs(path)
+		if err != nil {
+			return cfgs, cleanup, errors.Wrapf(err, "Error finding projects in %s", projectCfg.Path)
+		}
+
+		// Let the path be detected as usual if there are no project directories, e.g. if it's a plan JSON file
+		if len(dirs) == 0 {
+			dirs = []string{path}
+		}
+
+		for _, dir := range dirs {
+			c := *projectCfg
+			c.Path = dir
+			c.SourcePath = projectCfg.Path
+
+			if dir != path {
+				subDir, err := filepath.Rel(repoDir, dir)
+				if err == nil {
+					s := src
+					s.SubDir = filepath.ToSlash(subDir)
+					c.SourcePath = s.String()
+				}
+			}
+
+			cfgs = append(cfgs, &c)
+		}
+	}
+
+	return cfgs, cleanup, nil
+}
+
 func unwrapped(err error) error {
 	e := err
 	for errors.Unwrap(e) != nil {
@@ -329,3 +604,23 @@ func unwrapped(err error) error {
 
 	return e
 }
+
+func buildEnvironments(envs []*config.Environment, projects []output.Project) []output.Environment {
+	if len(envs) == 0 {
+		return nil
+	}
+
+	outEnvs := make([]output.Environment, 0, len(envs))
+
+	for _, e := range envs {
+		var budget *decimal.Decimal
+		if e.MonthlyBudget != nil {
+			d := decimal.NewFromFloat(*e.MonthlyBudget)
+			budget = &d
+		}
+
+		outEnvs = append(outEnvs, output.BuildEnvironment(e.Name, e.Projects, budget, projects))
+	}
+
+	return outEnvs
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func sensitivityCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sensitivity",
+		Short: "Rank the usage keys that most affect the monthly cost",
+		Long: `Rank the usage keys that most affect the monthly cost.
+
+Each number usage key of each resource is lowered and raised by the change, and the usage
+keys are ranked by how much the total monthly cost swings between the two. The usage keys at
+the top of the ranking are the ones worth getting right in the usage file.`,
+		Example: `  Rank the usage keys of a Terraform directory:
+
+      infracost sensitivity --path /path/to/code --usage-file infracost-usage.yml
+
+  Lower and raise each usage key by 20%:
+
+      infracost sensitivity --path plan.json --change 20`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config); err != nil {
+				return err
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			change, _ := cmd.Flags().GetFloat64("change")
+			if change <= 0 || change >= 100 {
+				ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid change '%v', expected a percentage between 0 and 100", change))
+			}
+			ctx.Config.UsageSensitivityChange = change / 100
+
+			if ctx.Config.Format != "table" && ctx.Config.Format != "json" {
+				ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid format '%s', expected table or json", ctx.Config.Format))
+			}
+
+			ctx.SetContextValue("outputFormat", ctx.Config.Format)
+
+			err = checkRunConfig(ctx.Config)
+			if err != nil {
+				ui.PrintUsageErrorAndExit(cmd, err.Error())
+			}
+
+			return runMain(cmd, ctx)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().Float64("change", 50, "Percentage that each usage key is lowered and raised by")
+	cmd.Flags().String("format", "table", "Output format: json, table")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+func printSensitivity(runCtx *config.RunContext, projects []*schema.Project) error {
+	r := output.ToSensitivityReport(projects, runCtx.Config.Currency, runCtx.Config.UsageSensitivityChange)
+	opts := output.Options{NoColor: runCtx.Config.NoColor}
+
+	var (
+		b   []byte
+		out string
+		err error
+	)
+
+	switch strings.ToLower(runCtx.Config.Format) {
+	case "json":
+		b, err = output.ToSensitivityJSON(r, opts)
+		out = string(b)
+	default:
+		b, err = output.ToSensitivityTable(r, opts)
+		out = fmt.Sprintf("\n%s", string(b))
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "Error generating output")
+	}
+
+	fmt.Printf("%s\n", out)
+
+	return nil
+}
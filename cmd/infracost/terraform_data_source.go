@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// terraformDataSourceQuery is the query that Terraform's external data source sends on stdin
+type terraformDataSourceQuery struct {
+	// Path is a comma-separated list of Infracost JSON files
+	Path    string `json:"path"`
+	Project string `json:"project"`
+}
+
+func terraformDataSourceCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform-data-source",
+		Short: "Output the totals of Infracost JSON files for Terraform's external data source",
+		Long: `Output the totals of Infracost JSON files for Terraform's external data source.
+
+The query is read from stdin as JSON with the keys "path", a comma-separated list of Infracost JSON
+files, and optionally "project" to only return the totals of that project. The result has the keys
+total_monthly_cost, total_hourly_cost, past_total_monthly_cost, diff_total_monthly_cost,
+resource_count and currency.
+
+The Infracost JSON files should be generated before running Terraform, e.g. with
+infracost breakdown --format json, since running Infracost for the same Terraform
+configuration from inside Terraform would run Terraform again.`,
+		Example: `  Use the estimated monthly cost in Terraform:
+
+      data "external" "infracost" {
+        program = ["infracost", "terraform-data-source"]
+        query = {
+          path = "infracost.json"
+        }
+      }
+
+      locals {
+        monthly_cost = tonumber(data.external.infracost.result.total_monthly_cost)
+      }`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var query terraformDataSourceQuery
+
+			if cmd.Flags().Changed("path") {
+				paths, _ := cmd.Flags().GetStringArray("path")
+				query.Path = strings.Join(paths, ",")
+				query.Project, _ = cmd.Flags().GetString("project")
+			} else {
+				b, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return errors.Wrap(err, "Error reading query")
+				}
+
+				err = json.Unmarshal(b, &query)
+				if err != nil {
+					return errors.Wrap(err, "Invalid query, expected a JSON object")
+				}
+			}
+
+			if query.Path == "" {
+				return errors.New("No path specified, set the path key of the query to the Infracost JSON files")
+			}
+
+			paths := strings.Split(query.Path, ",")
+			for i := range paths {
+				paths[i] = strings.TrimSpace(paths[i])
+			}
+
+			inputs, err := loadInfracostJSONFiles(paths)
+			if err != nil {
+				return err
+			}
+
+			if len(inputs) == 0 {
+				return fmt.Errorf("No Infracost JSON files found at %s", query.Path)
+			}
+
+			combined, err := output.Combine(inputs, output.Options{})
+			if err != nil {
+				return err
+			}
+
+			b, err := output.ToTerraformExternalData(combined, query.Project)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files, instead of reading the query from stdin")
+	cmd.Flags().String("project", "", "Only output the totals of this project")
+
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	return cmd
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func uploadCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Upload Infracost JSON files to an Infracost server to get a shareable link",
+		Long: `Upload Infracost JSON files to an Infracost server to get a shareable link.
+
+The server renders the estimate as an HTML report. Set INFRACOST_SERVER_API_ENDPOINT to the URL of your Infracost server.`,
+		Example: `  Upload an estimate that can be viewed by users of the Infracost server for a week:
+
+      infracost upload --path out.json --expires 168h
+
+  Upload an estimate that can be viewed by anyone with the link:
+
+      infracost upload --path out.json --access public`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ctx.Config.ServerAPIEndpoint == "" {
+				return errors.New("No INFRACOST_SERVER_API_ENDPOINT environment variable is set.\nSet it to the URL of your Infracost server to upload estimates")
+			}
+
+			access, _ := cmd.Flags().GetString("access")
+			if access != apiclient.UploadAccessPrivate && access != apiclient.UploadAccessPublic {
+				ui.PrintUsageErrorAndExit(cmd, fmt.Sprintf("Invalid access %s, valid values are: private, public", access))
+			}
+
+			allowedEmails, _ := cmd.Flags().GetStringSlice("allow-email")
+			if len(allowedEmails) > 0 && access == apiclient.UploadAccessPublic {
+				ui.PrintUsageErrorAndExit(cmd, "allow-email can only be used with private access")
+			}
+
+			paths, _ := cmd.Flags().GetStringArray("path")
+
+			inputs, err := loadInfracostJSONFiles(paths)
+			if err != nil {
+				return err
+			}
+
+			combined, err := output.Combine(inputs, output.Options{GroupKey: "filename", GroupLabel: "File"})
+			if err != nil {
+				return err
+			}
+
+			opts := apiclient.UploadOptions{
+				Access:        access,
+				AllowedEmails: allowedEmails,
+			}
+			opts.ExpiresIn, _ = cmd.Flags().GetDuration("expires")
+
+			r, err := apiclient.NewServerAPIClient(ctx.Config).Upload(combined, opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(r.URL)
+
+			if r.ExpiresAt != nil {
+				fmt.Fprintf(os.Stderr, "The link expires at %s\n", r.ExpiresAt.Local().Format("2006-01-02 15:04 MST"))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayP("path", "p", []string{}, "Path to Infracost JSON files")
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "json")
+
+	cmd.Flags().Duration("expires", 0, "How long the link works for, e.g. 24h. Defaults to the server's expiry")
+	cmd.Flags().String("access", apiclient.UploadAccessPrivate, "Who can view the estimate: private (users of the Infracost server), public (anyone with the link)")
+	cmd.Flags().StringSlice("allow-email", []string{}, "Only allow these users of the Infracost server to view the estimate, can be repeated")
+
+	_ = cmd.RegisterFlagCompletionFunc("access", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{apiclient.UploadAccessPrivate, apiclient.UploadAccessPublic}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
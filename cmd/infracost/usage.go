@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/clierror"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/usage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func usageCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Check usage files",
+		Long:  "Check usage files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(usageLintCmd(ctx))
+	cmd.AddCommand(usageSchemaCmd())
+
+	return cmd
+}
+
+func usageLintCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Find problems with a usage file that skew the estimates of a Terraform project",
+		Long: `Find problems with a usage file that skew the estimates of a Terraform project.
+
+The following problems are reported:
+ - Usage of resources and resource types that aren't in the project, e.g. resources that have been removed or renamed
+ - Resources with usage-based costs that have no usage
+ - Usage keys that are unknown, have values of the wrong type or are deprecated`,
+		Example: `  Lint a usage file for a Terraform directory:
+
+      infracost usage lint --path infracost-usage.yml --terraform-dir .`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			usageFilePath, _ := cmd.Flags().GetString("path")
+
+			projectCfg := &config.Project{}
+			projectCfg.Path, _ = cmd.Flags().GetString("terraform-dir")
+			projectCfg.TerraformPlanFlags, _ = cmd.Flags().GetString("terraform-plan-flags")
+			projectCfg.TerraformWorkspace, _ = cmd.Flags().GetString("terraform-workspace")
+			projectCfg.TerraformVarFiles, _ = cmd.Flags().GetStringArray("terraform-var-file")
+			ctx.Config.Projects = []*config.Project{projectCfg}
+
+			if err := ctx.Config.LoadFromEnv(); err != nil {
+				return err
+			}
+
+			problems, err := lintUsageFile(ctx, projectCfg, usageFilePath)
+			if err != nil {
+				return err
+			}
+
+			if len(problems) == 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "No problems found in %s\n", usageFilePath)
+				return nil
+			}
+
+			for _, p := range problems {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", usageFilePath, p)
+			}
+
+			return clierror.NewSanitizedError(
+				errors.New(fmt.Sprintf("Found %d problems in %s", len(problems), usageFilePath)),
+				"Found problems in usage file",
+			)
+		},
+	}
+
+	cmd.Flags().StringP("path", "p", "", "Path to the Infracost usage file")
+	cmd.Flags().String("terraform-dir", ".", "Path to the Terraform directory or JSON/plan file that the usage file is for")
+	cmd.Flags().String("terraform-plan-flags", "", "Flags to pass to 'terraform plan'. Applicable when terraform-dir is a Terraform directory")
+	cmd.Flags().String("terraform-workspace", "", "Terraform workspace to use. Applicable when terraform-dir is a Terraform directory")
+	cmd.Flags().StringArray("terraform-var-file", nil, "Path to a Terraform variables file, can be repeated. Applicable when terraform-dir is a Terraform directory")
+
+	_ = cmd.MarkFlagRequired("path")
+	_ = cmd.MarkFlagFilename("path", "yml", "yaml", "json")
+	_ = cmd.MarkFlagFilename("terraform-var-file", "tfvars", "json")
+
+	return cmd
+}
+
+func lintUsageFile(runCtx *config.RunContext, projectCfg *config.Project, usageFilePath string) ([]*usage.LintProblem, error) {
+	linter, err := usage.NewLinter(usageFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := config.NewProjectContext(runCtx, projectCfg)
+	runCtx.SetCurrentProjectContext(ctx)
+
+	provider, err := providers.Detect(ctx)
+	if err != nil {
+		m := fmt.Sprintf("%s\n\n", err)
+		m += fmt.Sprintf("Use the %s flag to specify the path to one of the following:\n", ui.PrimaryString("--terraform-dir"))
+		m += " - Terraform plan JSON file\n - Terraform directory\n - Terragrunt or CDK for Terraform directory\n - Terraform plan file"
+		return nil, clierror.NewSanitizedError(errors.New(m), "Could not detect path type")
+	}
+	ctx.SetContextValue("projectType", provider.Type())
+
+	metadata := config.DetectProjectMetadata(ctx)
+	metadata.Type = provider.Type()
+	provider.AddMetadata(metadata)
+
+	project := schema.NewProject(schema.GenerateProjectName(metadata, false), metadata)
+	if err := provider.LoadResources(project, linter.UsageData()); err != nil {
+		return nil, err
+	}
+
+	return linter.Lint(project.Resources)
+}
+
+func usageSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Output the JSON Schema of usage files",
+		Long: `Output the JSON Schema of usage files.
+
+Usage files are checked against this schema when they're loaded. It can also be used by editors to
+check and complete usage files in YAML or JSON while they're being written.`,
+		Example: `  Save the schema to use in an editor:
+
+      infracost usage schema > infracost-usage-schema.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			j, err := usage.JSONSchema()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(j))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
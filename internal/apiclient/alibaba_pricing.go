@@ -0,0 +1,228 @@
+package apiclient
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// AlibabaVendorName is the vendor name of the Alibaba Cloud product filters. Alibaba Cloud
+// prices aren't in the Cloud Pricing API so these queries are sent to the Alibaba Cloud
+// BSS OpenAPI instead.
+const AlibabaVendorName = "alibaba"
+
+const alibabaBSSAPIVersion = "2017-12-14"
+
+// alibabaPricingSource looks up pay-as-you-go prices with the GetPayAsYouGoPrice action
+// of the Alibaba Cloud BSS OpenAPI. The product filter of each query has the product code
+// as the service, and the moduleCode, config and priceType attributes of the module to price,
+// e.g. the InstanceType module of ecs with config InstanceType:ecs.g6.large.
+type alibabaPricingSource struct {
+	endpoint   string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+
+	warnOnce sync.Once
+}
+
+func newAlibabaPricingSource(cfg *config.Config) *alibabaPricingSource {
+	return &alibabaPricingSource{
+		endpoint:   cfg.AlicloudPricingEndpoint,
+		accessKey:  cfg.AlicloudAccessKey,
+		secretKey:  cfg.AlicloudSecretKey,
+		httpClient: NewExternalHTTPClient(cfg),
+	}
+}
+
+func (s *alibabaPricingSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+
+	if s.accessKey == "" || s.secretKey == "" {
+		s.warnOnce.Do(func() {
+			log.Warn("Alibaba Cloud prices are looked up from the Alibaba Cloud BSS OpenAPI, set ALICLOUD_ACCESS_KEY and ALICLOUD_SECRET_KEY to price alicloud resources")
+		})
+
+		for range queries {
+			results = append(results, gjson.Parse(`{"data":{"products":[]}}`))
+		}
+
+		return results, nil
+	}
+
+	log.Debugf("Getting pricing details for %d queries from %s", len(queries), s.endpoint)
+
+	for _, q := range queries {
+		r, err := s.query(q)
+		if err != nil {
+			return []gjson.Result{}, err
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+func (s *alibabaPricingSource) query(q GraphQLQuery) (gjson.Result, error) {
+	productFilter, _ := pricing.QueryFilters(q)
+	if productFilter == nil || productFilter.Service == nil {
+		return gjson.Parse(`{"data":{"products":[]}}`), nil
+	}
+
+	attrs := make(map[string]string)
+	for _, f := range productFilter.AttributeFilters {
+		if f.Value != nil {
+			attrs[f.Key] = *f.Value
+		}
+	}
+
+	params := map[string]string{
+		"Action":                  "GetPayAsYouGoPrice",
+		"ProductCode":             *productFilter.Service,
+		"SubscriptionType":        "PayAsYouGo",
+		"ModuleList.1.ModuleCode": attrs["moduleCode"],
+		"ModuleList.1.Config":     attrs["config"],
+		"ModuleList.1.PriceType":  attrs["priceType"],
+	}
+	if productFilter.Region != nil {
+		params["Region"] = *productFilter.Region
+	}
+
+	body, err := s.doRequest(params)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	resp := gjson.ParseBytes(body)
+	if !resp.Get("Success").Bool() {
+		// An unknown module or config isn't an error, it just means there's no price
+		log.Debugf("No Alibaba Cloud price for %s %s: %s", *productFilter.Service, attrs["config"], resp.Get("Message").String())
+		return gjson.Parse(`{"data":{"products":[]}}`), nil
+	}
+
+	currency := resp.Get("Data.Currency").String()
+	if currency == "" {
+		currency = "CNY"
+	}
+
+	products := make([]interface{}, 0, 1)
+	for _, m := range resp.Get("Data.ModuleDetails.ModuleDetail").Array() {
+		if m.Get("ModuleCode").String() != attrs["moduleCode"] {
+			continue
+		}
+
+		products = append(products, map[string]interface{}{
+			"prices": []interface{}{map[string]interface{}{
+				"priceHash": alibabaPriceHash(*productFilter.Service, params["Region"], attrs),
+				currency:    m.Get("UnitPrice").String(),
+			}},
+		})
+	}
+
+	j, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"products": products}})
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error marshaling Alibaba Cloud price")
+	}
+
+	return gjson.ParseBytes(j), nil
+}
+
+// doRequest signs the request with signature version 1.0 of the Alibaba Cloud RPC APIs.
+func (s *alibabaPricingSource) doRequest(params map[string]string) ([]byte, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return []byte{}, errors.Wrap(err, "Error generating Alibaba Cloud request nonce")
+	}
+
+	params["Format"] = "JSON"
+	params["Version"] = alibabaBSSAPIVersion
+	params["AccessKeyId"] = s.accessKey
+	params["SignatureMethod"] = "HMAC-SHA1"
+	params["SignatureVersion"] = "1.0"
+	params["SignatureNonce"] = hex.EncodeToString(nonce)
+	params["Timestamp"] = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	params["Signature"] = alibabaSignature(http.MethodGet, params, s.secretKey)
+
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+
+	resp, err := s.httpClient.Get(fmt.Sprintf("%s/?%s", strings.TrimSuffix(s.endpoint, "/"), values.Encode()))
+	if err != nil {
+		return []byte{}, &transientError{errors.Wrap(err, "Error contacting the Alibaba Cloud BSS OpenAPI")}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, &APIError{err, "Invalid Alibaba Cloud BSS OpenAPI response"}
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return []byte{}, &APIError{fmt.Errorf("%s", gjson.GetBytes(body, "Message").String()), "Invalid Alibaba Cloud credentials"}
+	}
+
+	if resp.StatusCode >= 500 {
+		return []byte{}, &transientError{fmt.Errorf("Alibaba Cloud BSS OpenAPI error: status code %d", resp.StatusCode)}
+	}
+
+	return body, nil
+}
+
+func alibabaSignature(method string, params map[string]string, secretKey string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "Signature" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, alibabaPercentEncode(k)+"="+alibabaPercentEncode(params[k]))
+	}
+
+	stringToSign := method + "&" + alibabaPercentEncode("/") + "&" + alibabaPercentEncode(strings.Join(pairs, "&"))
+
+	mac := hmac.New(sha1.New, []byte(secretKey+"&"))
+	mac.Write([]byte(stringToSign))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func alibabaPercentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	s = strings.ReplaceAll(s, "%7E", "~")
+
+	return s
+}
+
+func alibabaPriceHash(productCode, region string, attrs map[string]string) string {
+	h := sha1.New() // nolint:gosec
+	h.Write([]byte(strings.Join([]string{productCode, region, attrs["moduleCode"], attrs["config"], attrs["priceType"]}, "|")))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
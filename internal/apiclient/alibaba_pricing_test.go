@@ -0,0 +1,142 @@
+package apiclient
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestAlibabaSignature(t *testing.T) {
+	// The example from the Alibaba Cloud RPC API signature docs
+	params := map[string]string{
+		"AccessKeyId":      "testid",
+		"Action":           "DescribeRegions",
+		"Format":           "XML",
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   "3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf",
+		"SignatureVersion": "1.0",
+		"Timestamp":        "2016-02-23T12:46:24Z",
+		"Version":          "2014-05-26",
+	}
+
+	assert.Equal(t, "OLeaidS1JvxuMvnyHOwuJ+uX5qY=", alibabaSignature(http.MethodGet, params, "testsecret"))
+}
+
+func TestRunQueriesAlibaba(t *testing.T) {
+	var alibabaRequests int
+	alibaba := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alibabaRequests++
+
+		q := r.URL.Query()
+		assert.Equal(t, "GetPayAsYouGoPrice", q.Get("Action"))
+		assert.Equal(t, "ecs", q.Get("ProductCode"))
+		assert.Equal(t, "cn-hangzhou", q.Get("Region"))
+		assert.Equal(t, "InstanceType:ecs.g6.large,IoOptimized:IoOptimized,ImageOs:linux", q.Get("ModuleList.1.Config"))
+		assert.NotEmpty(t, q.Get("Signature"))
+
+		fmt.Fprint(w, `{"Success": true, "Code": "Success", "Data": {"Currency": "CNY", "ModuleDetails": {"ModuleDetail": [{"ModuleCode": "InstanceType", "UnitPrice": 0.78}]}}}`)
+	}))
+	defer alibaba.Close()
+
+	pricingAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"data": {"products": [{"prices": [{"priceHash": "abc", "USD": "0.1"}]}]}}]`)
+	}))
+	defer pricingAPI.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.PricingAPIEndpoint = pricingAPI.URL
+	cfg.AlicloudPricingEndpoint = alibaba.URL
+	cfg.AlicloudAccessKey = "testid"
+	cfg.AlicloudSecretKey = "testsecret"
+	cfg.Currency = "CNY"
+	cfg.CurrencyExchangeRate = 7
+	cfg.NoCache = true
+
+	c, err := NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Region: strPtr("us-east-1")}}}},
+		{Name: "alicloud_instance.b", CostComponents: []*schema.CostComponent{{Name: "b", ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr(AlibabaVendorName),
+			Region:     strPtr("cn-hangzhou"),
+			Service:    strPtr("ecs"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "moduleCode", Value: strPtr("InstanceType")},
+				{Key: "config", Value: strPtr("InstanceType:ecs.g6.large,IoOptimized:IoOptimized,ImageOs:linux")},
+				{Key: "priceType", Value: strPtr("Hour")},
+			},
+		}}}},
+	}
+
+	results, err := c.RunQueries(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 1, alibabaRequests)
+
+	price, err := c.Price(results[0].Result.Get("data.products.0.prices.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.7", price.String())
+
+	price, err = c.Price(results[1].Result.Get("data.products.0.prices.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.78", price.String())
+}
+
+func TestAlibabaPricingSourceNoCredentials(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AlicloudPricingEndpoint = "http://localhost:0"
+
+	results, err := newAlibabaPricingSource(cfg).Query([]GraphQLQuery{{}, {}})
+	require.NoError(t, err)
+	assert.Equal(t, []gjson.Result{gjson.Parse(`{"data":{"products":[]}}`), gjson.Parse(`{"data":{"products":[]}}`)}, results)
+}
+
+func TestAlibabaPricingSourceCACertFile(t *testing.T) {
+	alibaba := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Success": true, "Code": "Success", "Data": {"Currency": "CNY", "ModuleDetails": {"ModuleDetail": [{"ModuleCode": "InstanceType", "UnitPrice": 0.78}]}}}`)
+	}))
+	defer alibaba.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: alibaba.Certificate().Raw}), 0600))
+
+	cfg := config.DefaultConfig()
+	cfg.AlicloudPricingEndpoint = alibaba.URL
+	cfg.AlicloudAccessKey = "testid"
+	cfg.AlicloudSecretKey = "testsecret"
+
+	query := GraphQLQuery{Variables: map[string]interface{}{
+		"productFilter": &schema.ProductFilter{
+			VendorName: strPtr(AlibabaVendorName),
+			Region:     strPtr("cn-hangzhou"),
+			Service:    strPtr("ecs"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "moduleCode", Value: strPtr("InstanceType")},
+				{Key: "config", Value: strPtr("InstanceType:ecs.g6.large")},
+				{Key: "priceType", Value: strPtr("Hour")},
+			},
+		},
+	}}
+
+	// The server's certificate isn't trusted by default
+	_, err := newAlibabaPricingSource(cfg).Query([]GraphQLQuery{query})
+	assert.Error(t, err)
+
+	cfg.TLSCACertFile = caFile
+
+	results, err := newAlibabaPricingSource(cfg).Query([]GraphQLQuery{query})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "0.78", results[0].Get("data.products.0.prices.0.CNY").String())
+}
@@ -8,21 +8,20 @@ import (
 	"net/http"
 
 	"github.com/infracost/infracost/internal/version"
+	"github.com/infracost/infracost/pkg/pricing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
 
 type APIClient struct {
-	endpoint string
-	apiKey   string
-	runID    string
+	endpoint   string
+	apiKey     string
+	runID      string
+	httpClient *http.Client
 }
 
-type GraphQLQuery struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables"`
-}
+type GraphQLQuery = pricing.GraphQLQuery
 
 type APIError struct {
 	err error
@@ -35,6 +34,10 @@ func (e *APIError) Error() string {
 
 type APIErrorResponse struct {
 	Error string `json:"error"`
+	// Errors are returned by GraphQL requests, e.g. if the query is invalid
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
 }
 
 var ErrInvalidAPIKey = errors.New("Invalid API key")
@@ -62,10 +65,14 @@ func (c *APIClient) doRequest(method string, path string, d interface{}) ([]byte
 
 	c.AddAuthHeaders(req)
 
-	client := &http.Client{}
+	client := c.httpClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
-		return []byte{}, errors.Wrap(err, "Error sending API request")
+		return []byte{}, &transientError{errors.Wrap(err, "Error sending API request")}
 	}
 	defer resp.Body.Close()
 
@@ -78,10 +85,21 @@ func (c *APIClient) doRequest(method string, path string, d interface{}) ([]byte
 		var r APIErrorResponse
 
 		err = json.Unmarshal(respBody, &r)
+		if isRetryableStatus(resp.StatusCode) {
+			// Gateways often return HTML error pages so fallback to the status
+			if err != nil || r.Error == "" {
+				r.Error = resp.Status
+			}
+			return []byte{}, &transientError{&APIError{errors.New(r.Error), "Received error from API"}}
+		}
 		if err != nil {
 			return []byte{}, &APIError{err, "Invalid API response"}
 		}
 
+		if r.Error == "" && len(r.Errors) > 0 {
+			r.Error = r.Errors[0].Message
+		}
+
 		if r.Error == "Invalid API key" {
 			return []byte{}, ErrInvalidAPIKey
 		}
@@ -41,8 +41,9 @@ type projectResultInput struct {
 func NewDashboardAPIClient(ctx *config.RunContext) *DashboardAPIClient {
 	return &DashboardAPIClient{
 		APIClient: APIClient{
-			endpoint: ctx.Config.DashboardAPIEndpoint,
-			apiKey:   ctx.Config.APIKey,
+			endpoint:   ctx.Config.DashboardAPIEndpoint,
+			apiKey:     ctx.Config.APIKey,
+			httpClient: newHTTPClient(ctx.Config),
 		},
 		telemetryDisabled: ctx.Config.IsTelemetryDisabled(),
 		dashboardEnabled:  ctx.Config.EnableDashboard,
@@ -114,7 +115,7 @@ func (c *DashboardAPIClient) AddRun(ctx *config.RunContext, projectContexts []*c
 			}
 		}
 	`
-	results, err := c.doQueries([]GraphQLQuery{{q, v}})
+	results, err := c.doQueries([]GraphQLQuery{{Query: q, Variables: v}})
 	if err != nil {
 		return "", err
 	}
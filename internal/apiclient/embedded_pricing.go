@@ -0,0 +1,239 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// embeddedPricingData is set when Infracost is built with the embedpricing build tag. It
+// contains a subset of the Cloud Pricing API products, generated by
+// scripts/generate_embedded_pricing.sh, so prices can be looked up without any network calls.
+var embeddedPricingData []byte
+
+var embeddedProducts []embeddedProduct
+var embeddedProductsErr error
+var loadEmbeddedProductsOnce sync.Once
+
+type embeddedAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type embeddedPrice struct {
+	PriceHash          string `json:"priceHash"`
+	USD                string `json:"USD"`
+	CNY                string `json:"CNY,omitempty"`
+	PurchaseOption     string `json:"purchaseOption"`
+	Unit               string `json:"unit"`
+	Description        string `json:"description"`
+	StartUsageAmount   string `json:"startUsageAmount"`
+	EndUsageAmount     string `json:"endUsageAmount"`
+	TermLength         string `json:"termLength"`
+	TermPurchaseOption string `json:"termPurchaseOption"`
+	TermOfferingClass  string `json:"termOfferingClass"`
+	EffectiveDateStart string `json:"effectiveDateStart,omitempty"`
+}
+
+type embeddedProduct struct {
+	VendorName    string              `json:"vendorName"`
+	Service       string              `json:"service"`
+	ProductFamily string              `json:"productFamily"`
+	Region        string              `json:"region"`
+	Sku           string              `json:"sku"`
+	Attributes    []embeddedAttribute `json:"attributes"`
+	Prices        []embeddedPrice     `json:"prices"`
+}
+
+// HasEmbeddedPricing returns true if Infracost was built with embedded prices.
+func HasEmbeddedPricing() bool {
+	return len(embeddedPricingData) > 0
+}
+
+func loadEmbeddedProducts(data []byte) ([]embeddedProduct, error) {
+	var products []embeddedProduct
+
+	err := json.Unmarshal(data, &products)
+	if err != nil {
+		return products, errors.Wrap(err, "Error parsing embedded pricing data")
+	}
+
+	return products, nil
+}
+
+func queryEmbeddedPricing(queries []GraphQLQuery) ([]gjson.Result, error) {
+	loadEmbeddedProductsOnce.Do(func() {
+		embeddedProducts, embeddedProductsErr = loadEmbeddedProducts(embeddedPricingData)
+	})
+
+	if embeddedProductsErr != nil {
+		return []gjson.Result{}, embeddedProductsErr
+	}
+
+	return queryProducts(embeddedProducts, queries)
+}
+
+// queryProducts returns the results for the queries in the same format as the Cloud Pricing API
+func queryProducts(products []embeddedProduct, queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+
+	for _, q := range queries {
+		productFilter, priceFilter := pricing.QueryFilters(q)
+
+		type resultPrice struct {
+			PriceHash          string `json:"priceHash"`
+			USD                string `json:"USD"`
+			CNY                string `json:"CNY,omitempty"`
+			EffectiveDateStart string `json:"effectiveDateStart,omitempty"`
+		}
+
+		type resultProduct struct {
+			Prices []resultPrice `json:"prices"`
+		}
+
+		matched := make([]resultProduct, 0)
+
+		for _, p := range products {
+			ok, err := productMatches(p, productFilter)
+			if err != nil {
+				return results, err
+			}
+			if !ok {
+				continue
+			}
+
+			prices := make([]resultPrice, 0)
+			for _, price := range p.Prices {
+				ok, err := priceMatches(price, priceFilter)
+				if err != nil {
+					return results, err
+				}
+				if ok {
+					prices = append(prices, resultPrice{PriceHash: price.PriceHash, USD: price.USD, CNY: price.CNY, EffectiveDateStart: price.EffectiveDateStart})
+				}
+			}
+
+			matched = append(matched, resultProduct{Prices: prices})
+		}
+
+		b, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"products": matched,
+			},
+		})
+		if err != nil {
+			return results, errors.Wrap(err, "Error generating embedded pricing result")
+		}
+
+		results = append(results, gjson.ParseBytes(b))
+	}
+
+	return results, nil
+}
+
+func productMatches(p embeddedProduct, f *schema.ProductFilter) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	if !strMatches(p.VendorName, f.VendorName) ||
+		!strMatches(p.Service, f.Service) ||
+		!strMatches(p.ProductFamily, f.ProductFamily) ||
+		!strMatches(p.Region, f.Region) ||
+		!strMatches(p.Sku, f.Sku) {
+		return false, nil
+	}
+
+	for _, af := range f.AttributeFilters {
+		value, found := "", false
+		for _, a := range p.Attributes {
+			if a.Key == af.Key {
+				value, found = a.Value, true
+				break
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+
+		ok, err := valueMatches(value, af.Value, af.ValueRegex)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func priceMatches(p embeddedPrice, f *schema.PriceFilter) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	if !strMatches(p.PurchaseOption, f.PurchaseOption) ||
+		!strMatches(p.Unit, f.Unit) ||
+		!strMatches(p.StartUsageAmount, f.StartUsageAmount) ||
+		!strMatches(p.EndUsageAmount, f.EndUsageAmount) ||
+		!strMatches(p.TermLength, f.TermLength) ||
+		!strMatches(p.TermPurchaseOption, f.TermPurchaseOption) ||
+		!strMatches(p.TermOfferingClass, f.TermOfferingClass) {
+		return false, nil
+	}
+
+	return valueMatches(p.Description, f.Description, f.DescriptionRegex)
+}
+
+func strMatches(v string, filter *string) bool {
+	return filter == nil || v == *filter
+}
+
+func valueMatches(v string, filter *string, regexFilter *string) (bool, error) {
+	if !strMatches(v, filter) {
+		return false, nil
+	}
+
+	if regexFilter == nil {
+		return true, nil
+	}
+
+	r, err := compileFilterRegex(*regexFilter)
+	if err != nil {
+		return false, err
+	}
+
+	return r.MatchString(v), nil
+}
+
+// compileFilterRegex compiles the regex filters used by the Cloud Pricing API which are in
+// the form /<pattern>/<flags>, e.g. /^BoxUsage:t3.micro$/i
+func compileFilterRegex(s string) (*regexp.Regexp, error) {
+	pattern := s
+	flags := ""
+
+	if strings.HasPrefix(s, "/") {
+		i := strings.LastIndex(s, "/")
+		if i > 0 {
+			pattern = s[1:i]
+			flags = s[i+1:]
+		}
+	}
+
+	if strings.Contains(flags, "i") {
+		pattern = "(?i)" + pattern
+	}
+
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Invalid regex filter %s", s))
+	}
+
+	return r, nil
+}
@@ -0,0 +1,15 @@
+//go:build embedpricing
+// +build embedpricing
+
+package apiclient
+
+import (
+	_ "embed" // required for go:embed
+)
+
+//go:embed embedded_pricing.json
+var embeddedPricingJSON []byte
+
+func init() {
+	embeddedPricingData = embeddedPricingJSON
+}
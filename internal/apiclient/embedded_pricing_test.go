@@ -0,0 +1,84 @@
+package apiclient
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestQueryProducts(t *testing.T) {
+	products, err := loadEmbeddedProducts([]byte(`[
+		{
+			"vendorName": "aws",
+			"service": "AmazonEC2",
+			"productFamily": "Compute Instance",
+			"region": "us-east-1",
+			"attributes": [
+				{"key": "instanceType", "value": "t3.micro"},
+				{"key": "usagetype", "value": "BoxUsage:t3.micro"}
+			],
+			"prices": [
+				{"priceHash": "ondemand", "USD": "0.0104", "purchaseOption": "on_demand"},
+				{"priceHash": "reserved", "USD": "0.0063", "purchaseOption": "reserved", "termLength": "1yr"}
+			]
+		},
+		{
+			"vendorName": "aws",
+			"service": "AmazonEC2",
+			"productFamily": "Compute Instance",
+			"region": "us-east-1",
+			"attributes": [
+				{"key": "instanceType", "value": "t3.large"},
+				{"key": "usagetype", "value": "BoxUsage:t3.large"}
+			],
+			"prices": [
+				{"priceHash": "large", "USD": "0.0832", "purchaseOption": "on_demand"}
+			]
+		}
+	]`))
+	require.NoError(t, err)
+
+	c := &PricingAPIClient{}
+	queries := []GraphQLQuery{
+		c.buildQuery(&schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("us-east-1"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/^boxusage:t3.micro$/i")},
+			},
+		}, &schema.PriceFilter{PurchaseOption: strPtr("on_demand")}),
+		c.buildQuery(&schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("m5.large")},
+			},
+		}, nil),
+	}
+
+	results, err := queryProducts(products, queries)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Len(t, results[0].Get("data.products").Array(), 1)
+	assert.Equal(t, "ondemand", results[0].Get("data.products.0.prices.0.priceHash").String())
+	assert.Equal(t, "0.0104", results[0].Get("data.products.0.prices.0.USD").String())
+	assert.Len(t, results[0].Get("data.products.0.prices").Array(), 1)
+
+	assert.Len(t, results[1].Get("data.products").Array(), 0)
+}
+
+func TestCompileFilterRegex(t *testing.T) {
+	r, err := compileFilterRegex("/^BoxUsage/i")
+	require.NoError(t, err)
+	assert.True(t, r.MatchString("boxusage:t3.micro"))
+
+	r, err = compileFilterRegex("/beyond the free tier/")
+	require.NoError(t, err)
+	assert.False(t, r.MatchString("Beyond the free tier"))
+}
@@ -0,0 +1,170 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// IBMVendorName is the vendor name of the IBM Cloud product filters. IBM Cloud prices aren't
+// in the Cloud Pricing API so these queries are looked up from the IBM Cloud Global Catalog.
+const IBMVendorName = "ibm"
+
+// ibmPricingSource looks up prices in the IBM Cloud Global Catalog. The product filter of
+// each query has the catalog name of the service as the service, the name of the plan as the
+// product family and the metric_id attribute of the metric to price, e.g. the
+// GIGABYTE_MONTHS_RAM metric of the standard plan of databases-for-postgresql. The prices
+// of the configured country are used, since IBM Cloud prices are by country not region.
+type ibmPricingSource struct {
+	endpoint   string
+	country    string
+	httpClient *http.Client
+
+	mu sync.Mutex
+	// plans are the prices of the plans that have been looked up, keyed by service and
+	// plan name. An empty result means the plan wasn't found.
+	plans map[string]gjson.Result
+}
+
+func newIBMPricingSource(cfg *config.Config) *ibmPricingSource {
+	return &ibmPricingSource{
+		endpoint:   strings.TrimSuffix(cfg.IBMPricingEndpoint, "/"),
+		country:    cfg.IBMPricingCountry,
+		httpClient: NewExternalHTTPClient(cfg),
+		plans:      make(map[string]gjson.Result),
+	}
+}
+
+func (s *ibmPricingSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	log.Debugf("Getting pricing details for %d queries from the IBM Cloud Global Catalog", len(queries))
+
+	results := make([]gjson.Result, 0, len(queries))
+	for _, q := range queries {
+		r, err := s.query(q)
+		if err != nil {
+			return []gjson.Result{}, err
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+func (s *ibmPricingSource) query(q GraphQLQuery) (gjson.Result, error) {
+	products := make([]interface{}, 0, 1)
+
+	productFilter, _ := pricing.QueryFilters(q)
+	if productFilter != nil && productFilter.Service != nil && productFilter.ProductFamily != nil {
+		metricID := ""
+		for _, f := range productFilter.AttributeFilters {
+			if f.Key == "metric_id" && f.Value != nil {
+				metricID = *f.Value
+			}
+		}
+
+		pricing := s.planPricing(*productFilter.Service, *productFilter.ProductFamily)
+		for _, metric := range pricing.Get("metrics").Array() {
+			if metric.Get("metric_id").String() != metricID {
+				continue
+			}
+
+			prices := make(map[string]interface{})
+			for _, amount := range metric.Get("amounts").Array() {
+				if amount.Get("country").String() != s.country {
+					continue
+				}
+
+				// Only the first tier is used, tiered prices get cheaper with more usage
+				prices[amount.Get("currency").String()] = amount.Get("prices.0.price").String()
+			}
+
+			if len(prices) > 0 {
+				prices["priceHash"] = fmt.Sprintf("%s-%s", metric.Get("part_ref").String(), metricID)
+				products = append(products, map[string]interface{}{"prices": []interface{}{prices}})
+			}
+		}
+	}
+
+	j, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"products": products}})
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error marshaling IBM Cloud price")
+	}
+
+	return gjson.ParseBytes(j), nil
+}
+
+// planPricing returns the pricing of a plan of a service. If it can't be looked up the
+// IBM Cloud resources are left unpriced rather than failing the other resources.
+func (s *ibmPricingSource) planPricing(service, plan string) gjson.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := service + "/" + plan
+	if pricing, ok := s.plans[key]; ok {
+		return pricing
+	}
+
+	pricing, err := s.lookupPlanPricing(service, plan)
+	if err != nil {
+		log.Warnf("Could not get the IBM Cloud prices of the %s plan of %s: %s", plan, service, err)
+	}
+
+	s.plans[key] = pricing
+
+	return pricing
+}
+
+func (s *ibmPricingSource) lookupPlanPricing(service, plan string) (gjson.Result, error) {
+	services, err := s.get(fmt.Sprintf("%s?q=%s&complete=true", s.endpoint, url.QueryEscape("name:"+service)))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	serviceID := services.Get(fmt.Sprintf("resources.#(name==%q).id", service)).String()
+	if serviceID == "" {
+		return gjson.Result{}, fmt.Errorf("service %s not found", service)
+	}
+
+	plans, err := s.get(fmt.Sprintf("%s/%s/plan?complete=true", s.endpoint, url.PathEscape(serviceID)))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	planID := plans.Get(fmt.Sprintf("resources.#(name==%q).id", plan)).String()
+	if planID == "" {
+		return gjson.Result{}, fmt.Errorf("plan %s not found", plan)
+	}
+
+	return s.get(fmt.Sprintf("%s/%s/pricing", s.endpoint, url.PathEscape(planID)))
+}
+
+func (s *ibmPricingSource) get(u string) (gjson.Result, error) {
+	resp, err := s.httpClient.Get(u)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error contacting the IBM Cloud Global Catalog")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error reading the IBM Cloud Global Catalog response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return gjson.Result{}, fmt.Errorf("IBM Cloud Global Catalog returned status code %d", resp.StatusCode)
+	}
+
+	return gjson.ParseBytes(body), nil
+}
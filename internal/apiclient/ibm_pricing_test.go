@@ -0,0 +1,103 @@
+package apiclient
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIBMPricingSource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/":
+			assert.Equal(t, "name:databases-for-postgresql", r.URL.Query().Get("q"))
+			fmt.Fprint(w, `{"resources": [{"name": "databases-for-postgresql", "id": "service-id"}]}`)
+		case "/service-id/plan":
+			fmt.Fprint(w, `{"resources": [{"name": "standard", "id": "plan-id"}]}`)
+		case "/plan-id/pricing":
+			fmt.Fprint(w, `{"metrics": [{"part_ref": "D01", "metric_id": "GIGABYTE_MONTHS_RAM", "amounts": [
+				{"country": "GBR", "currency": "GBP", "prices": [{"quantity_tier": 1, "price": 9.5}]},
+				{"country": "USA", "currency": "USD", "prices": [{"quantity_tier": 1, "price": 12}]}
+			]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.IBMPricingEndpoint = server.URL + "/"
+
+	s := newIBMPricingSource(cfg)
+
+	query := func(metricID string) GraphQLQuery {
+		return GraphQLQuery{Variables: map[string]interface{}{
+			"productFilter": &schema.ProductFilter{
+				VendorName:       strPtr(IBMVendorName),
+				Region:           strPtr("us-south"),
+				Service:          strPtr("databases-for-postgresql"),
+				ProductFamily:    strPtr("standard"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "metric_id", Value: strPtr(metricID)}},
+			},
+		}}
+	}
+
+	results, err := s.Query([]GraphQLQuery{query("GIGABYTE_MONTHS_RAM"), query("GIGABYTE_MONTHS_DISK")})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "12", results[0].Get("data.products.0.prices.0.USD").String())
+	assert.False(t, results[0].Get("data.products.0.prices.0.GBP").Exists())
+	assert.Equal(t, "D01-GIGABYTE_MONTHS_RAM", results[0].Get("data.products.0.prices.0.priceHash").String())
+	assert.Empty(t, results[1].Get("data.products").Array())
+
+	// The plan's pricing is only looked up once
+	assert.Equal(t, []string{"/", "/service-id/plan", "/plan-id/pricing"}, requests)
+}
+
+func TestIBMPricingSourceCACertFile(t *testing.T) {
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"resources": []}`)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	cfg := config.DefaultConfig()
+	cfg.IBMPricingEndpoint = server.URL
+
+	query := GraphQLQuery{Variables: map[string]interface{}{
+		"productFilter": &schema.ProductFilter{
+			VendorName:       strPtr(IBMVendorName),
+			Region:           strPtr("us-south"),
+			Service:          strPtr("cloud-object-storage"),
+			ProductFamily:    strPtr("standard"),
+			AttributeFilters: []*schema.AttributeFilter{{Key: "metric_id", Value: strPtr("STANDARD_STORAGE")}},
+		},
+	}}
+
+	// The server's certificate isn't trusted by default
+	_, err := newIBMPricingSource(cfg).Query([]GraphQLQuery{query})
+	require.NoError(t, err)
+	assert.Equal(t, 0, requests)
+
+	cfg.TLSCACertFile = caFile
+
+	_, err = newIBMPricingSource(cfg).Query([]GraphQLQuery{query})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,149 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// OracleVendorName is the vendor name of the Oracle Cloud Infrastructure product filters.
+// OCI prices aren't in the Cloud Pricing API so these queries are looked up from the
+// public OCI price list instead.
+const OracleVendorName = "oracle"
+
+// oraclePricingSource looks up prices in the OCI price list by the part number in the Sku of
+// the product filter, e.g. B93113 for the E4 Flex OCPUs. OCI prices are the same in every
+// region so the whole price list is downloaded once and the region is ignored.
+type oraclePricingSource struct {
+	endpoint   string
+	currency   string
+	httpClient *http.Client
+
+	once   sync.Once
+	prices map[string]gjson.Result
+}
+
+func newOraclePricingSource(cfg *config.Config) *oraclePricingSource {
+	return &oraclePricingSource{
+		endpoint:   cfg.OCIPricingEndpoint,
+		currency:   cfg.Currency,
+		httpClient: NewExternalHTTPClient(cfg),
+	}
+}
+
+func (s *oraclePricingSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	// If the price list can't be downloaded the OCI resources are left unpriced rather than
+	// failing the resources of the other vendors in the same batch
+	s.once.Do(func() {
+		var err error
+		s.prices, err = s.loadPriceList()
+		if err != nil {
+			log.Warnf("Could not price the OCI resources: %s", err)
+		}
+	})
+
+	log.Debugf("Getting pricing details for %d queries from the OCI price list", len(queries))
+
+	results := make([]gjson.Result, 0, len(queries))
+	for _, q := range queries {
+		r, err := s.query(q)
+		if err != nil {
+			return []gjson.Result{}, err
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+func (s *oraclePricingSource) query(q GraphQLQuery) (gjson.Result, error) {
+	products := make([]interface{}, 0, 1)
+
+	productFilter, priceFilter := pricing.QueryFilters(q)
+
+	var startUsageAmount *string
+	if priceFilter != nil {
+		startUsageAmount = priceFilter.StartUsageAmount
+	}
+
+	if productFilter != nil && productFilter.Sku != nil {
+		if item, ok := s.prices[*productFilter.Sku]; ok {
+			prices := make(map[string]interface{})
+			for _, l := range item.Get("currencyCodeLocalizations").Array() {
+				if p, ok := oraclePayAsYouGoPrice(l.Get("prices").Array(), startUsageAmount); ok {
+					prices[l.Get("currencyCode").String()] = p.Get("value").String()
+				}
+			}
+
+			if len(prices) > 0 {
+				prices["priceHash"] = *productFilter.Sku
+				products = append(products, map[string]interface{}{"prices": []interface{}{prices}})
+			}
+		}
+	}
+
+	j, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"products": products}})
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error marshaling OCI price")
+	}
+
+	return gjson.ParseBytes(j), nil
+}
+
+// oraclePayAsYouGoPrice returns the pay-as-you-go price of a part. Parts with tiered prices
+// have a price for each tier with the usage the tier starts at in rangeMin, so the tier is
+// picked using the startUsageAmount of the price filter.
+func oraclePayAsYouGoPrice(prices []gjson.Result, startUsageAmount *string) (gjson.Result, bool) {
+	for _, p := range prices {
+		if p.Get("model").String() != "PAY_AS_YOU_GO" {
+			continue
+		}
+
+		if startUsageAmount != nil && p.Get("rangeMin").Exists() && p.Get("rangeMin").String() != *startUsageAmount {
+			continue
+		}
+
+		return p, true
+	}
+
+	return gjson.Result{}, false
+}
+
+func (s *oraclePricingSource) loadPriceList() (map[string]gjson.Result, error) {
+	u := fmt.Sprintf("%s?currencyCode=%s", s.endpoint, url.QueryEscape(s.currency))
+
+	log.Debugf("Downloading the OCI price list from %s", u)
+
+	resp, err := s.httpClient.Get(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error downloading the OCI price list")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the OCI price list")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error downloading the OCI price list: status code %d", resp.StatusCode)
+	}
+
+	prices := make(map[string]gjson.Result)
+	for _, item := range gjson.GetBytes(body, "items").Array() {
+		prices[item.Get("partNumber").String()] = item
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,78 @@
+package apiclient
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOraclePricingSource(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "USD", r.URL.Query().Get("currencyCode"))
+
+		fmt.Fprint(w, `{"items": [
+			{"partNumber": "B93113", "currencyCodeLocalizations": [{"currencyCode": "USD", "prices": [{"model": "PAY_AS_YOU_GO", "value": 0.025}]}]},
+			{"partNumber": "B88327", "currencyCodeLocalizations": [{"currencyCode": "USD", "prices": [
+				{"model": "PAY_AS_YOU_GO", "value": 0, "rangeMin": 0, "rangeMax": 10240},
+				{"model": "PAY_AS_YOU_GO", "value": 0.0085, "rangeMin": 10240}
+			]}]}
+		]}`)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.OCIPricingEndpoint = server.URL
+
+	s := newOraclePricingSource(cfg)
+
+	query := func(sku string, startUsageAmount *string) GraphQLQuery {
+		return GraphQLQuery{Variables: map[string]interface{}{
+			"productFilter": &schema.ProductFilter{VendorName: strPtr(OracleVendorName), Region: strPtr("us-ashburn-1"), Sku: strPtr(sku)},
+			"priceFilter":   &schema.PriceFilter{StartUsageAmount: startUsageAmount},
+		}}
+	}
+
+	results, err := s.Query([]GraphQLQuery{query("B93113", nil), query("B88327", strPtr("10240")), query("B00000", nil)})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "0.025", results[0].Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, "B93113", results[0].Get("data.products.0.prices.0.priceHash").String())
+	assert.Equal(t, "0.0085", results[1].Get("data.products.0.prices.0.USD").String())
+	assert.Empty(t, results[2].Get("data.products").Array())
+
+	_, err = s.Query([]GraphQLQuery{query("B93113", nil)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestOraclePricingSourceCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [{"partNumber": "B93113", "currencyCodeLocalizations": [{"currencyCode": "USD", "prices": [{"model": "PAY_AS_YOU_GO", "value": 0.025}]}]}]}`)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	cfg := config.DefaultConfig()
+	cfg.OCIPricingEndpoint = server.URL
+	cfg.TLSCACertFile = caFile
+
+	results, err := newOraclePricingSource(cfg).Query([]GraphQLQuery{{Variables: map[string]interface{}{
+		"productFilter": &schema.ProductFilter{VendorName: strPtr(OracleVendorName), Region: strPtr("us-ashburn-1"), Sku: strPtr("B93113")},
+	}}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "0.025", results[0].Get("data.products.0.prices.0.USD").String())
+}
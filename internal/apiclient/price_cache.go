@@ -0,0 +1,231 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// priceCache caches the results of price queries on disk so repeated runs on the same
+// code don't query the Cloud Pricing API for the same prices. Results are keyed by the
+// endpoint and the normalized query, and expire after the TTL. The results of all the
+// queries of a run are also cached together so an unchanged project is priced from one file.
+type priceCache struct {
+	dir      string
+	endpoint string
+	ttl      time.Duration
+	refresh  bool
+}
+
+func newPriceCache(dir string, endpoint string, ttl time.Duration, refresh bool) *priceCache {
+	return &priceCache{
+		dir:      dir,
+		endpoint: endpoint,
+		ttl:      ttl,
+		refresh:  refresh,
+	}
+}
+
+// get returns the cached result of the query, or false if there isn't one that has not
+// expired. Nothing is returned from the cache when it is being refreshed.
+func (c *priceCache) get(q GraphQLQuery) (gjson.Result, bool) {
+	if c.refresh {
+		return gjson.Result{}, false
+	}
+
+	path, err := c.path(q)
+	if err != nil {
+		log.Debugf("Could not generate price cache path: %s", err)
+		return gjson.Result{}, false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return gjson.Result{}, false
+	}
+
+	j, err := ioutil.ReadFile(path)
+	if err != nil || !gjson.ValidBytes(j) {
+		return gjson.Result{}, false
+	}
+
+	return gjson.ParseBytes(j), true
+}
+
+// set caches the result of the query. Results with errors are not cached.
+func (c *priceCache) set(q GraphQLQuery, r gjson.Result) {
+	if r.Get("errors").Exists() || !r.Get("data").Exists() {
+		return
+	}
+
+	path, err := c.path(q)
+	if err != nil {
+		log.Debugf("Could not generate price cache path: %s", err)
+		return
+	}
+
+	err = writeCacheFile(path, []byte(r.Raw))
+	if err != nil {
+		log.Debugf("Could not cache price query result to %s: %s", path, err)
+	}
+}
+
+// getQuerySet returns the cached results of all the queries of a run in the same order as
+// the queries, or false if the same set of queries hasn't been cached or it has expired.
+// This means re-running an unchanged project doesn't need to look up each query.
+func (c *priceCache) getQuerySet(queries []GraphQLQuery) ([]gjson.Result, bool) {
+	if c.refresh {
+		return nil, false
+	}
+
+	path, order, err := c.querySetPath(queries)
+	if err != nil {
+		log.Debugf("Could not generate price cache path: %s", err)
+		return nil, false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+
+	j, err := ioutil.ReadFile(path)
+	if err != nil || !gjson.ValidBytes(j) {
+		return nil, false
+	}
+
+	cached := gjson.ParseBytes(j).Array()
+	if len(cached) != len(queries) {
+		return nil, false
+	}
+
+	results := make([]gjson.Result, len(queries))
+	for i, r := range cached {
+		results[order[i]] = r
+	}
+
+	return results, true
+}
+
+// setQuerySet caches the results of all the queries of a run. The set isn't cached if
+// any of the results have errors.
+func (c *priceCache) setQuerySet(queries []GraphQLQuery, results []gjson.Result) {
+	if len(results) != len(queries) {
+		return
+	}
+
+	for _, r := range results {
+		if r.Get("errors").Exists() || !r.Get("data").Exists() {
+			return
+		}
+	}
+
+	path, order, err := c.querySetPath(queries)
+	if err != nil {
+		log.Debugf("Could not generate price cache path: %s", err)
+		return
+	}
+
+	raw := make([]string, 0, len(results))
+	for _, i := range order {
+		raw = append(raw, results[i].Raw)
+	}
+
+	err = writeCacheFile(path, []byte("["+strings.Join(raw, ",")+"]"))
+	if err != nil {
+		log.Debugf("Could not cache price query results to %s: %s", path, err)
+	}
+}
+
+func (c *priceCache) path(q GraphQLQuery) (string, error) {
+	k, err := queryKey(q)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.endpoint))
+	h.Write([]byte(k))
+	key := hex.EncodeToString(h.Sum(nil))
+
+	return filepath.Join(c.dir, key[:2], key+".json"), nil
+}
+
+// querySetPath returns the path of the cached results of a set of queries, and the order
+// that the results are stored in. The queries are sorted by their key so the same set of
+// queries has the same path whatever order the resources are in.
+func (c *priceCache) querySetPath(queries []GraphQLQuery) (string, []int, error) {
+	keys := make([]string, len(queries))
+	for i, q := range queries {
+		k, err := queryKey(q)
+		if err != nil {
+			return "", nil, err
+		}
+		keys[i] = k
+	}
+
+	order := make([]int, len(queries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+
+	h := sha256.New()
+	h.Write([]byte(c.endpoint))
+	for _, i := range order {
+		h.Write([]byte(keys[i]))
+		h.Write([]byte("\n"))
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+
+	return filepath.Join(c.dir, "sets", key[:2], key+".json"), order, nil
+}
+
+// writeCacheFile writes to a temp file first since other workers or runs might be
+// reading the same file.
+func writeCacheFile(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	f.Close()
+
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
+
+// queryKey returns a key that is the same for identical queries. The whitespace is
+// normalized so formatting changes to the query don't change the key.
+func queryKey(q GraphQLQuery) (string, error) {
+	j, err := json.Marshal(GraphQLQuery{
+		Query:     strings.Join(strings.Fields(q.Query), " "),
+		Variables: q.Variables,
+	})
+
+	return string(j), err
+}
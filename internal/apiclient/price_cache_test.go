@@ -0,0 +1,184 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestPriceCache(t *testing.T) {
+	c := newPriceCache(t.TempDir(), "https://pricing.example.com", time.Hour, false)
+	q := GraphQLQuery{Query: "query { products }", Variables: map[string]interface{}{"region": "us-east-1"}}
+
+	_, ok := c.get(q)
+	assert.False(t, ok)
+
+	c.set(q, gjson.Parse(`{"data": {"products": [{"prices": [{"USD": "0.01"}]}]}}`))
+
+	r, ok := c.get(q)
+	require.True(t, ok)
+	assert.Equal(t, "0.01", r.Get("data.products.0.prices.0.USD").String())
+
+	// Whitespace in the query doesn't change the key
+	_, ok = c.get(GraphQLQuery{Query: "query {\n  products\n}", Variables: q.Variables})
+	assert.True(t, ok)
+
+	// Other endpoints don't share the cache
+	other := newPriceCache(c.dir, "https://other.example.com", time.Hour, false)
+	_, ok = other.get(q)
+	assert.False(t, ok)
+
+	refresh := newPriceCache(c.dir, c.endpoint, time.Hour, true)
+	_, ok = refresh.get(q)
+	assert.False(t, ok)
+
+	path, err := c.path(q)
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(path, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+
+	_, ok = c.get(q)
+	assert.False(t, ok)
+
+	errQuery := GraphQLQuery{Query: "query { error }"}
+	c.set(errQuery, gjson.Parse(`{"errors": [{"message": "invalid"}]}`))
+	_, ok = c.get(errQuery)
+	assert.False(t, ok)
+}
+
+func TestDoCachedQueries(t *testing.T) {
+	requestedQueries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+		requestedQueries += len(queries)
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{"products": []interface{}{q.Variables["productFilter"]}},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer server.Close()
+
+	c := &PricingAPIClient{
+		APIClient: APIClient{endpoint: server.URL},
+		cache:     newPriceCache(t.TempDir(), server.URL, time.Hour, false),
+	}
+
+	query := func(region string) GraphQLQuery {
+		return c.buildQuery(&schema.ProductFilter{Region: strPtr(region)}, nil)
+	}
+
+	results, err := c.doCachedQueries([]GraphQLQuery{query("us-east-1"), query("eu-west-1")})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requestedQueries)
+	assert.Equal(t, "eu-west-1", results[1].Get("data.products.0.region").String())
+
+	results, err = c.doCachedQueries([]GraphQLQuery{query("eu-west-1"), query("us-west-2"), query("us-east-1")})
+	require.NoError(t, err)
+	assert.Equal(t, 3, requestedQueries)
+	require.Len(t, results, 3)
+	assert.Equal(t, "eu-west-1", results[0].Get("data.products.0.region").String())
+	assert.Equal(t, "us-west-2", results[1].Get("data.products.0.region").String())
+	assert.Equal(t, "us-east-1", results[2].Get("data.products.0.region").String())
+}
+
+func TestPriceCacheQuerySet(t *testing.T) {
+	c := newPriceCache(t.TempDir(), "https://pricing.example.com", time.Hour, false)
+
+	query := func(region string) GraphQLQuery {
+		return GraphQLQuery{Query: "query { products }", Variables: map[string]interface{}{"region": region}}
+	}
+	result := func(region string) gjson.Result {
+		return gjson.Parse(`{"data": {"products": [{"region": "` + region + `"}]}}`)
+	}
+
+	_, ok := c.getQuerySet([]GraphQLQuery{query("us-east-1"), query("eu-west-1")})
+	assert.False(t, ok)
+
+	c.setQuerySet([]GraphQLQuery{query("us-east-1"), query("eu-west-1")}, []gjson.Result{result("us-east-1"), result("eu-west-1")})
+
+	// The same set of queries in a different order returns the results in that order
+	results, ok := c.getQuerySet([]GraphQLQuery{query("eu-west-1"), query("us-east-1")})
+	require.True(t, ok)
+	assert.Equal(t, "eu-west-1", results[0].Get("data.products.0.region").String())
+	assert.Equal(t, "us-east-1", results[1].Get("data.products.0.region").String())
+
+	// A different set of queries isn't cached
+	_, ok = c.getQuerySet([]GraphQLQuery{query("us-east-1")})
+	assert.False(t, ok)
+
+	refresh := newPriceCache(c.dir, c.endpoint, time.Hour, true)
+	_, ok = refresh.getQuerySet([]GraphQLQuery{query("us-east-1"), query("eu-west-1")})
+	assert.False(t, ok)
+
+	// Sets with errors aren't cached
+	c.setQuerySet([]GraphQLQuery{query("us-west-2")}, []gjson.Result{gjson.Parse(`{"errors": [{"message": "invalid"}]}`)})
+	_, ok = c.getQuerySet([]GraphQLQuery{query("us-west-2")})
+	assert.False(t, ok)
+}
+
+func TestRunQueriesCachedQuerySet(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+		requests++
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{"products": []interface{}{q.Variables["productFilter"]}},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := &PricingAPIClient{
+		APIClient: APIClient{endpoint: server.URL},
+		cache:     newPriceCache(dir, server.URL, time.Hour, false),
+	}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Region: strPtr("us-east-1")}}}},
+		{Name: "aws_instance.b", CostComponents: []*schema.CostComponent{{Name: "b", ProductFilter: &schema.ProductFilter{Region: strPtr("eu-west-1")}}}},
+	}
+
+	_, err := c.RunQueries(resources)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// Remove the cached results of the single queries so only the query set is cached
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		if e.Name() != "sets" {
+			require.NoError(t, os.RemoveAll(filepath.Join(dir, e.Name())))
+		}
+	}
+
+	results, err := c.RunQueries([]*schema.Resource{resources[1], resources[0]})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	require.Len(t, results, 2)
+	assert.Equal(t, "b", results[0].CostComponent.Name)
+	assert.Equal(t, "eu-west-1", results[0].Result.Get("data.products.0.region").String())
+	assert.Equal(t, "us-east-1", results[1].Result.Get("data.products.0.region").String())
+}
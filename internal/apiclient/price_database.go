@@ -0,0 +1,156 @@
+package apiclient
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// A price database is a gzipped JSON file of products downloaded from the Cloud Pricing API by
+// infracost pricing download. The products use the same format as the embedded prices, so
+// when a price database is set all the price lookups are answered locally.
+type priceDatabase struct {
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Filters     []PriceDatabaseFilter `json:"filters"`
+	Products    []embeddedProduct     `json:"products"`
+}
+
+// PriceDatabaseFilter selects the products that are downloaded into the price database
+type PriceDatabaseFilter struct {
+	VendorName string `json:"vendorName"`
+	Service    string `json:"service"`
+	Region     string `json:"region"`
+}
+
+var priceDatabases = map[string]*priceDatabase{}
+var priceDatabasesMu sync.Mutex
+
+// DownloadPriceDatabase downloads the products for the filters from the Cloud Pricing API and
+// saves them to the price database at path. The progress func is called after each filter is
+// downloaded with the number of products that matched it.
+func (c *PricingAPIClient) DownloadPriceDatabase(filters []PriceDatabaseFilter, path string, progress func(PriceDatabaseFilter, int)) (int, error) {
+	db := priceDatabase{
+		GeneratedAt: time.Now().UTC(),
+		Filters:     filters,
+		Products:    make([]embeddedProduct, 0),
+	}
+
+	for _, f := range filters {
+		products, err := c.downloadProducts(f)
+		if err != nil {
+			return 0, err
+		}
+
+		db.Products = append(db.Products, products...)
+
+		if progress != nil {
+			progress(f, len(products))
+		}
+	}
+
+	err := savePriceDatabase(path, &db)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(db.Products), nil
+}
+
+func (c *PricingAPIClient) downloadProducts(f PriceDatabaseFilter) ([]embeddedProduct, error) {
+	query := GraphQLQuery{
+		Query: `
+			query($filter: ProductFilter!) {
+				products(filter: $filter) {
+					vendorName service productFamily region sku
+					attributes { key value }
+					prices {
+						priceHash USD CNY purchaseOption unit description startUsageAmount endUsageAmount
+						termLength termPurchaseOption termOfferingClass effectiveDateStart
+					}
+				}
+			}
+		`,
+		Variables: map[string]interface{}{
+			"filter": f,
+		},
+	}
+
+	results, err := c.doQueries([]GraphQLQuery{query})
+	if err != nil {
+		return nil, err
+	}
+
+	var products []embeddedProduct
+
+	if len(results) > 0 {
+		err = json.Unmarshal([]byte(results[0].Get("data.products").Raw), &products)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid products for %s %s %s", f.VendorName, f.Service, f.Region)
+		}
+	}
+
+	return products, nil
+}
+
+func savePriceDatabase(path string, db *priceDatabase) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "Error creating price database")
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+
+	err = json.NewEncoder(gz).Encode(db)
+	if err != nil {
+		return errors.Wrap(err, "Error writing price database")
+	}
+
+	return gz.Close()
+}
+
+func loadPriceDatabase(path string) (*priceDatabase, error) {
+	priceDatabasesMu.Lock()
+	defer priceDatabasesMu.Unlock()
+
+	if db, ok := priceDatabases[path]; ok {
+		return db, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error opening price database")
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid price database %s", path)
+	}
+	defer gz.Close()
+
+	var db priceDatabase
+
+	err = json.NewDecoder(gz).Decode(&db)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid price database %s", path)
+	}
+
+	priceDatabases[path] = &db
+
+	return &db, nil
+}
+
+func queryPriceDatabase(path string, queries []GraphQLQuery) ([]gjson.Result, error) {
+	db, err := loadPriceDatabase(path)
+	if err != nil {
+		return []gjson.Result{}, err
+	}
+
+	return queryProducts(db.Products, queries)
+}
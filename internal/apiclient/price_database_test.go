@@ -0,0 +1,56 @@
+package apiclient
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryPriceDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json.gz")
+
+	err := savePriceDatabase(path, &priceDatabase{
+		Filters: []PriceDatabaseFilter{{VendorName: "aws", Service: "AmazonEC2", Region: "us-east-1"}},
+		Products: []embeddedProduct{
+			{
+				VendorName:    "aws",
+				Service:       "AmazonEC2",
+				ProductFamily: "Compute Instance",
+				Region:        "us-east-1",
+				Attributes:    []embeddedAttribute{{Key: "instanceType", Value: "t3.micro"}},
+				Prices:        []embeddedPrice{{PriceHash: "ondemand", USD: "0.0104", PurchaseOption: "on_demand"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	c := &PricingAPIClient{}
+	queries := []GraphQLQuery{
+		c.buildQuery(&schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("us-east-1"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr("t3.micro")},
+			},
+		}, nil),
+		c.buildQuery(&schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr("eu-west-1"),
+		}, nil),
+	}
+
+	results, err := queryPriceDatabase(path, queries)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "0.0104", results[0].Get("data.products.0.prices.0.USD").String())
+	assert.Len(t, results[1].Get("data.products").Array(), 0)
+}
+
+func TestQueryPriceDatabaseInvalid(t *testing.T) {
+	_, err := queryPriceDatabase(filepath.Join(t.TempDir(), "missing.json.gz"), []GraphQLQuery{})
+	assert.Error(t, err)
+}
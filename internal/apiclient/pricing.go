@@ -1,15 +1,37 @@
 package apiclient
 
 import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/pkg/errors"
 
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
 
 type PricingAPIClient struct {
 	APIClient
+	// source is where the prices are looked up, the Cloud Pricing API is used if it's nil
+	source PricingSource
+	// vendorSources are where the prices of vendors that aren't in the Cloud Pricing API
+	// are looked up, keyed by the vendor name of the product filter
+	vendorSources map[string]PricingSource
+	cache         *priceCache
+	batchSize     int
+	concurrency   int
+	// currency is the currency that prices are returned in. If the prices can't be queried
+	// in the currency then the USD prices are converted using the exchange rate.
+	currency      string
+	exchangeRate  decimal.Decimal
+	queryCurrency bool
 }
 
 type PriceQueryKey struct {
@@ -20,33 +42,357 @@ type PriceQueryKey struct {
 type PriceQueryResult struct {
 	PriceQueryKey
 	Result gjson.Result
+	// Err is set if the price couldn't be fetched because of a transient Cloud Pricing API
+	// error, e.g. the API returned a 5xx response after all the retries.
+	Err error
 }
 
-func NewPricingAPIClient(cfg *config.Config) *PricingAPIClient {
-	return &PricingAPIClient{
-		APIClient{
-			endpoint: cfg.PricingAPIEndpoint,
-			apiKey:   cfg.APIKey,
+func NewPricingAPIClient(cfg *config.Config) (*PricingAPIClient, error) {
+	httpClient, err := newPricingHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &PricingAPIClient{
+		APIClient: APIClient{
+			endpoint:   cfg.PricingAPIEndpoint,
+			apiKey:     cfg.APIKey,
+			httpClient: httpClient,
 		},
+		batchSize:    cfg.PricingAPIBatchSize,
+		concurrency:  cfg.PricingAPIConcurrency,
+		currency:     cfg.Currency,
+		exchangeRate: decimal.NewFromFloat(cfg.CurrencyExchangeRate),
 	}
+
+	switch {
+	case cfg.PricingSource != "":
+		c.source, err = pricing.NewSource(cfg.PricingSource, cfg)
+		if err != nil {
+			return nil, err
+		}
+	case cfg.PricingDatabase != "":
+		c.source = &priceDatabaseSource{path: cfg.PricingDatabase}
+	case HasEmbeddedPricing():
+		c.source = &embeddedPricingSource{}
+	}
+
+	if cfg.PricingSource == "" {
+		c.vendorSources = map[string]PricingSource{
+			AlibabaVendorName: newAlibabaPricingSource(cfg),
+			OracleVendorName:  newOraclePricingSource(cfg),
+			IBMVendorName:     newIBMPricingSource(cfg),
+		}
+	}
+
+	if !c.isUSD() {
+		// Only the Cloud Pricing API can be queried for other currencies, other sources
+		// can still return prices in the currency but otherwise the USD prices are converted
+		c.queryCurrency = c.source == nil
+
+		if cfg.PricingSource == "" && !c.queryCurrency && !c.exchangeRate.IsPositive() {
+			return nil, fmt.Errorf("Offline prices are only available in USD, set currency_exchange_rate to convert them to %s", c.currency)
+		}
+	}
+
+	if !cfg.NoCache && cfg.PricingCacheTTL > 0 {
+		c.cache = newPriceCache(filepath.Join(config.UserCacheDir(), "prices"), cfg.PricingAPIEndpoint, cfg.PricingCacheTTL, cfg.RefreshCache)
+	}
+
+	return c, nil
 }
 
-func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, error) {
-	keys, queries := c.batchQueries(r)
+// RunQueries gets the prices of the cost components of all the resources. Identical
+// queries are only run once, and the queries are sent to the Cloud Pricing API in batches
+// with multiple batches in flight at the same time. If a batch fails with a transient
+// error the results of its queries have Err set instead of failing all the queries.
+func (c *PricingAPIClient) RunQueries(resources []*schema.Resource) ([]PriceQueryResult, error) {
+	keys := make([]PriceQueryKey, 0)
+	queryIndexes := make([]int, 0)
+	queries := make([]GraphQLQuery, 0)
+	seen := make(map[string]int)
+
+	for _, r := range resources {
+		if r.IsSkipped {
+			continue
+		}
+
+		rKeys, rQueries := c.batchQueries(r)
+		for i, q := range rQueries {
+			id, err := queryKey(q)
+			if err != nil {
+				return []PriceQueryResult{}, errors.Wrap(err, "Error generating price query")
+			}
+
+			idx, ok := seen[id]
+			if !ok {
+				idx = len(queries)
+				seen[id] = idx
+				queries = append(queries, q)
+			}
+
+			keys = append(keys, rKeys[i])
+			queryIndexes = append(queryIndexes, idx)
+		}
+	}
 
 	if len(queries) == 0 {
-		log.Debugf("Skipping getting pricing details for %s since there are no queries to run", r.Name)
+		log.Debug("Skipping getting pricing details since there are no queries to run")
 		return []PriceQueryResult{}, nil
 	}
 
-	log.Debugf("Getting pricing details from %s for %s", c.endpoint, r.Name)
+	log.Debugf("Getting pricing details for %d cost components using %d unique queries", len(keys), len(queries))
+
+	results, resultErrs, err := c.runCachedBatches(queries)
+	if c.queryCurrency && c.isUnsupportedCurrency(err, results) {
+		if !c.exchangeRate.IsPositive() {
+			return []PriceQueryResult{}, fmt.Errorf("The Cloud Pricing API does not support %s prices, set currency_exchange_rate to convert the USD prices", c.currency)
+		}
 
-	results, err := c.doQueries(queries)
+		log.Warnf("The Cloud Pricing API does not support %s prices, converting the USD prices using the currency exchange rate", c.currency)
+		c.queryCurrency = false
+		return c.RunQueries(resources)
+	}
 	if err != nil {
 		return []PriceQueryResult{}, err
 	}
 
-	return c.zipQueryResults(keys, results), nil
+	res := make([]PriceQueryResult, 0, len(keys))
+	for i, k := range keys {
+		res = append(res, PriceQueryResult{
+			PriceQueryKey: k,
+			Result:        results[queryIndexes[i]],
+			Err:           resultErrs[queryIndexes[i]],
+		})
+	}
+
+	return res, nil
+}
+
+// runCachedBatches returns the results of all the queries from the price cache if the same
+// set of queries has been run before, e.g. when CI retries a run on an unchanged project,
+// so no requests are sent at all. Otherwise the queries are run in batches and the whole
+// set of results is cached if none of them failed.
+func (c *PricingAPIClient) runCachedBatches(queries []GraphQLQuery) ([]gjson.Result, []error, error) {
+	if c.cache == nil || c.source != nil {
+		return c.runBatches(queries)
+	}
+
+	if results, ok := c.cache.getQuerySet(queries); ok {
+		log.Debugf("Getting pricing details for %d queries from the price cache of a previous run", len(queries))
+		return results, make([]error, len(queries)), nil
+	}
+
+	results, resultErrs, err := c.runBatches(queries)
+	if err != nil {
+		return results, resultErrs, err
+	}
+
+	for _, resultErr := range resultErrs {
+		if resultErr != nil {
+			return results, resultErrs, nil
+		}
+	}
+
+	c.cache.setQuerySet(queries, results)
+
+	return results, resultErrs, nil
+}
+
+// runBatches splits the queries into batches and runs them concurrently. The results are
+// returned in the same order as the queries. Transient errors are returned per query so
+// only the queries in the failed batches are affected, any other error fails all of them.
+func (c *PricingAPIClient) runBatches(queries []GraphQLQuery) ([]gjson.Result, []error, error) {
+	batchSize := c.batchSize
+	if batchSize <= 0 {
+		batchSize = len(queries)
+	}
+
+	type batch struct {
+		start int
+		end   int
+	}
+
+	batches := make([]batch, 0, len(queries)/batchSize+1)
+	for start := 0; start < len(queries); start += batchSize {
+		end := start + batchSize
+		if end > len(queries) {
+			end = len(queries)
+		}
+		batches = append(batches, batch{start, end})
+	}
+
+	numWorkers := c.concurrency
+	if numWorkers <= 0 {
+		numWorkers = defaultConcurrency()
+	}
+	if numWorkers > len(batches) {
+		numWorkers = len(batches)
+	}
+
+	results := make([]gjson.Result, len(queries))
+	resultErrs := make([]error, len(queries))
+	jobs := make(chan batch, len(batches))
+	resultErrors := make(chan error, len(batches))
+
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			for b := range jobs {
+				r, err := c.runQueries(queries[b.start:b.end])
+				if err == nil && len(r) != b.end-b.start {
+					err = &APIError{fmt.Errorf("expected %d results, got %d", b.end-b.start, len(r)), "Invalid API response"}
+				}
+				// Each batch writes to a different part of the results
+				if err == nil {
+					copy(results[b.start:b.end], r)
+				} else if isTransientError(err) {
+					log.Debugf("Error getting pricing details for %d queries: %s", b.end-b.start, err)
+					for i := b.start; i < b.end; i++ {
+						resultErrs[i] = err
+					}
+					err = nil
+				}
+
+				resultErrors <- err
+			}
+		}()
+	}
+
+	for _, b := range batches {
+		jobs <- b
+	}
+	close(jobs)
+
+	var err error
+	for i := 0; i < len(batches); i++ {
+		if batchErr := <-resultErrors; batchErr != nil && err == nil {
+			err = batchErr
+		}
+	}
+	if err != nil {
+		return []gjson.Result{}, []error{}, err
+	}
+
+	return results, resultErrs, nil
+}
+
+// defaultConcurrency is calculated using the following formula:
+// min(max(4, numCPU * 4), 16)
+func defaultConcurrency() int {
+	n := runtime.NumCPU() * 4
+	if n < 4 {
+		n = 4
+	}
+	if n > 16 {
+		n = 16
+	}
+
+	return n
+}
+
+// runQueries looks up the queries from the pricing source, apart from the queries of
+// vendors that have their own source which are sent to that source instead.
+func (c *PricingAPIClient) runQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	sourceIndexes := make(map[PricingSource][]int)
+	sources := make([]PricingSource, 0, 1)
+
+	defaultSource := c.pricingSource()
+	for i, q := range queries {
+		source := defaultSource
+		if productFilter, _ := pricing.QueryFilters(q); productFilter != nil && productFilter.VendorName != nil {
+			if s, ok := c.vendorSources[*productFilter.VendorName]; ok {
+				source = s
+			}
+		}
+
+		if _, ok := sourceIndexes[source]; !ok {
+			sources = append(sources, source)
+		}
+		sourceIndexes[source] = append(sourceIndexes[source], i)
+	}
+
+	if len(sources) == 1 {
+		return sources[0].Query(queries)
+	}
+
+	results := make([]gjson.Result, len(queries))
+	for _, source := range sources {
+		indexes := sourceIndexes[source]
+
+		sourceQueries := make([]GraphQLQuery, 0, len(indexes))
+		for _, i := range indexes {
+			sourceQueries = append(sourceQueries, queries[i])
+		}
+
+		sourceResults, err := source.Query(sourceQueries)
+		if err != nil {
+			return []gjson.Result{}, err
+		}
+		if len(sourceResults) != len(indexes) {
+			return []gjson.Result{}, &APIError{fmt.Errorf("expected %d results, got %d", len(indexes), len(sourceResults)), "Invalid pricing source response"}
+		}
+
+		for j, i := range indexes {
+			results[i] = sourceResults[j]
+		}
+	}
+
+	return results, nil
+}
+
+// pricingSource returns the source that the prices are looked up from.
+func (c *PricingAPIClient) pricingSource() PricingSource {
+	if c.source == nil {
+		return &apiPricingSource{c}
+	}
+
+	return c.source
+}
+
+// doCachedQueries only queries the Cloud Pricing API for the results that aren't in the
+// price cache, and caches their results.
+func (c *PricingAPIClient) doCachedQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	if c.cache == nil {
+		log.Debugf("Getting pricing details for %d queries from %s", len(queries), c.endpoint)
+		return c.doQueries(queries)
+	}
+
+	results := make([]gjson.Result, len(queries))
+	uncachedIndexes := make([]int, 0, len(queries))
+	uncachedQueries := make([]GraphQLQuery, 0, len(queries))
+
+	for i, q := range queries {
+		if cached, ok := c.cache.get(q); ok {
+			results[i] = cached
+			continue
+		}
+
+		uncachedIndexes = append(uncachedIndexes, i)
+		uncachedQueries = append(uncachedQueries, q)
+	}
+
+	if len(uncachedQueries) == 0 {
+		log.Debugf("Getting pricing details for %d queries from the price cache", len(queries))
+		return results, nil
+	}
+
+	log.Debugf("Getting pricing details for %d queries from %s (%d cached)", len(uncachedQueries), c.endpoint, len(queries)-len(uncachedQueries))
+
+	uncachedResults, err := c.doQueries(uncachedQueries)
+	if err != nil {
+		return []gjson.Result{}, err
+	}
+
+	for i, result := range uncachedResults {
+		if i >= len(uncachedIndexes) {
+			break
+		}
+
+		results[uncachedIndexes[i]] = result
+		c.cache.set(uncachedQueries[i], result)
+	}
+
+	return results, nil
 }
 
 func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *schema.PriceFilter) GraphQLQuery {
@@ -54,18 +400,103 @@ func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *sche
 	v["productFilter"] = product
 	v["priceFilter"] = price
 
-	query := `
+	// USD is always queried so it can be converted if the API doesn't have the currency
+	priceFields := "USD"
+	if c.queryCurrency {
+		priceFields += " " + c.currency
+	}
+
+	// The AWS China price lists only have CNY prices
+	if isAWSChinaProduct(product) && !(c.queryCurrency && c.currency == "CNY") {
+		priceFields += " CNY"
+	}
+
+	query := fmt.Sprintf(`
 		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
 			products(filter: $productFilter) {
 				prices(filter: $priceFilter) {
 					priceHash
-					USD
+					effectiveDateStart
+					%s
 				}
 			}
 		}
-	`
+	`, priceFields)
+
+	return GraphQLQuery{Query: query, Variables: v}
+}
+
+// Price returns the price of a price result in the client's currency. The USD price is
+// converted using the exchange rate if the result doesn't have a price in the currency.
+func (c *PricingAPIClient) Price(price gjson.Result) (decimal.Decimal, error) {
+	if price.Get("USD").String() == "" && price.Get("CNY").String() != "" && c.currency != "CNY" {
+		return decimal.Zero, fmt.Errorf("the price is only available in CNY, set currency to CNY to price resources in the AWS China regions and Alibaba Cloud")
+	}
+
+	if !c.isUSD() {
+		if p := price.Get(c.currency); p.Type != gjson.Null && p.String() != "" {
+			return decimal.NewFromString(p.String())
+		}
+
+		if !c.exchangeRate.IsPositive() {
+			return decimal.Zero, fmt.Errorf("no %s price and no currency exchange rate is set", c.currency)
+		}
+	}
+
+	usd, err := decimal.NewFromString(price.Get("USD").String())
+	if err != nil || c.isUSD() {
+		return usd, err
+	}
+
+	return usd.Mul(c.exchangeRate), nil
+}
+
+// EffectiveDate returns the date that a price result has been effective since, or nil if
+// the result doesn't have one, e.g. price databases downloaded by older versions.
+func EffectiveDate(price gjson.Result) *time.Time {
+	s := price.Get("effectiveDateStart").String()
+	if s == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		log.Debugf("Invalid effectiveDateStart %s: %s", s, err)
+		return nil
+	}
+
+	return &t
+}
+
+func isAWSChinaProduct(product *schema.ProductFilter) bool {
+	return product != nil && product.VendorName != nil && *product.VendorName == "aws" &&
+		product.Region != nil && strings.HasPrefix(*product.Region, "cn-")
+}
+
+func (c *PricingAPIClient) isUSD() bool {
+	return c.currency == "" || c.currency == "USD"
+}
+
+// isUnsupportedCurrency returns true if the request or any of the results failed since
+// the Cloud Pricing API doesn't have the currency field.
+func (c *PricingAPIClient) isUnsupportedCurrency(err error, results []gjson.Result) bool {
+	unsupported := func(msg string) bool {
+		return strings.Contains(msg, fmt.Sprintf(`Cannot query field "%s"`, c.currency))
+	}
+
+	if err != nil {
+		return unsupported(err.Error())
+	}
+
+	for _, r := range results {
+		for _, e := range r.Get("errors").Array() {
+			if unsupported(e.Get("message").String()) {
+				return true
+			}
+		}
+	}
 
-	return GraphQLQuery{query, v}
+	return false
 }
 
 // Batch all the queries for this resource so we can use one GraphQL call.
@@ -88,16 +519,3 @@ func (c *PricingAPIClient) batchQueries(r *schema.Resource) ([]PriceQueryKey, []
 
 	return keys, queries
 }
-
-func (c *PricingAPIClient) zipQueryResults(k []PriceQueryKey, r []gjson.Result) []PriceQueryResult {
-	res := make([]PriceQueryResult, 0, len(k))
-
-	for i, k := range k {
-		res = append(res, PriceQueryResult{
-			PriceQueryKey: k,
-			Result:        r[i],
-		})
-	}
-
-	return res
-}
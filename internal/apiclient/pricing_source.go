@@ -0,0 +1,40 @@
+package apiclient
+
+import (
+	"github.com/infracost/infracost/pkg/pricing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// PricingSource looks up the prices for price queries. Other programs can register their
+// own sources with pricing.RegisterSource.
+type PricingSource = pricing.Source
+
+// apiPricingSource queries the Cloud Pricing API, using the price cache if it's enabled.
+type apiPricingSource struct {
+	c *PricingAPIClient
+}
+
+func (s *apiPricingSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	return s.c.doCachedQueries(queries)
+}
+
+// priceDatabaseSource looks up the prices in a price database downloaded by
+// infracost pricing download.
+type priceDatabaseSource struct {
+	path string
+}
+
+func (s *priceDatabaseSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	log.Debugf("Getting pricing details for %d queries from price database %s", len(queries), s.path)
+	return queryPriceDatabase(s.path, queries)
+}
+
+// embeddedPricingSource looks up the prices embedded in the binary.
+type embeddedPricingSource struct{}
+
+func (s *embeddedPricingSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	log.Debugf("Getting pricing details for %d queries from embedded pricing", len(queries))
+	return queryEmbeddedPricing(queries)
+}
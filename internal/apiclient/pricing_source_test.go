@@ -0,0 +1,54 @@
+package apiclient
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/pkg/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type rateCardSource struct {
+	prices map[string]string
+}
+
+func (s *rateCardSource) Query(queries []pricing.GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+	for _, q := range queries {
+		productFilter, _ := pricing.QueryFilters(q)
+		results = append(results, gjson.Parse(`{"data": {"products": [{"prices": [{"priceHash": "rate-card", "USD": "`+s.prices[*productFilter.Region]+`"}]}]}}`))
+	}
+
+	return results, nil
+}
+
+func TestPricingSource(t *testing.T) {
+	pricing.RegisterSource("test-rate-card", func(cfg *pricing.Config) (pricing.Source, error) {
+		return &rateCardSource{prices: map[string]string{"us-east-1": "0.1", "eu-west-1": "0.2"}}, nil
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.PricingSource = "test-rate-card"
+	cfg.PricingAPIEndpoint = "http://localhost:0"
+
+	c, err := NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{{Name: "a", ProductFilter: &schema.ProductFilter{Region: strPtr("us-east-1")}}}},
+		{Name: "aws_instance.b", CostComponents: []*schema.CostComponent{{Name: "b", ProductFilter: &schema.ProductFilter{Region: strPtr("eu-west-1")}}}},
+	}
+
+	results, err := c.RunQueries(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "0.1", results[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, "0.2", results[1].Result.Get("data.products.0.prices.0.USD").String())
+
+	cfg.PricingSource = "missing"
+	_, err = NewPricingAPIClient(cfg)
+	assert.EqualError(t, err, "Unknown pricing source missing. Registered sources: test-rate-card")
+}
@@ -0,0 +1,181 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestRunQueriesBatches(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	requestedQueries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		mu.Lock()
+		requests++
+		requestedQueries += len(queries)
+		mu.Unlock()
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{"products": []interface{}{q.Variables["productFilter"]}},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer server.Close()
+
+	c := &PricingAPIClient{
+		APIClient:   APIClient{endpoint: server.URL},
+		batchSize:   2,
+		concurrency: 2,
+	}
+
+	component := func(region string) *schema.CostComponent {
+		return &schema.CostComponent{Name: region, ProductFilter: &schema.ProductFilter{Region: strPtr(region)}}
+	}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{component("us-east-1"), component("eu-west-1")}},
+		{Name: "aws_instance.b", CostComponents: []*schema.CostComponent{component("us-east-1"), component("us-west-2")}},
+		{Name: "aws_instance.c", CostComponents: []*schema.CostComponent{component("ap-south-1")}},
+		{Name: "aws_instance.skipped", IsSkipped: true, CostComponents: []*schema.CostComponent{component("sa-east-1")}},
+	}
+
+	results, err := c.RunQueries(resources)
+	require.NoError(t, err)
+
+	// The duplicate us-east-1 query is only sent once, in batches of 2
+	assert.Equal(t, 4, requestedQueries)
+	assert.Equal(t, 2, requests)
+
+	require.Len(t, results, 5)
+	for _, r := range results {
+		assert.Equal(t, r.CostComponent.Name, r.Result.Get("data.products.0.region").String(), r.Resource.Name)
+	}
+}
+
+func TestRunQueriesInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c := &PricingAPIClient{APIClient: APIClient{endpoint: server.URL}}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{{Name: "Instance usage", ProductFilter: &schema.ProductFilter{}}}},
+	}
+
+	_, err := c.RunQueries(resources)
+	assert.EqualError(t, err, "Invalid API response: expected 1 results, got 0")
+}
+
+func TestRunQueriesCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		// The API doesn't support GBP prices, and has no EUR rate for eu-west-1
+		if strings.Contains(queries[0].Query, "GBP") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Cannot query field \"GBP\" on type \"Price\"."}]}`))
+			return
+		}
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			price := map[string]interface{}{"priceHash": "hash", "USD": "1.5"}
+			if strings.Contains(q.Query, "EUR") && q.Variables["productFilter"].(map[string]interface{})["region"] == "us-east-1" {
+				price["EUR"] = "1.25"
+			}
+
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{"products": []interface{}{map[string]interface{}{"prices": []interface{}{price}}}},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer server.Close()
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{
+			{Name: "us-east-1", ProductFilter: &schema.ProductFilter{Region: strPtr("us-east-1")}},
+			{Name: "eu-west-1", ProductFilter: &schema.ProductFilter{Region: strPtr("eu-west-1")}},
+		}},
+	}
+
+	prices := func(c *PricingAPIClient) []string {
+		results, err := c.RunQueries(resources)
+		require.NoError(t, err)
+
+		p := make([]string, 0, len(results))
+		for _, r := range results {
+			price, err := c.Price(r.Result.Get("data.products.0.prices.0"))
+			require.NoError(t, err)
+			p = append(p, price.String())
+		}
+		return p
+	}
+
+	// Prices without the currency are converted from USD
+	c := &PricingAPIClient{
+		APIClient:     APIClient{endpoint: server.URL},
+		currency:      "EUR",
+		exchangeRate:  decimal.NewFromFloat(0.8),
+		queryCurrency: true,
+	}
+	assert.Equal(t, []string{"1.25", "1.2"}, prices(c))
+
+	c = &PricingAPIClient{
+		APIClient:     APIClient{endpoint: server.URL},
+		currency:      "GBP",
+		exchangeRate:  decimal.NewFromInt(2),
+		queryCurrency: true,
+	}
+	assert.Equal(t, []string{"3", "3"}, prices(c))
+	assert.False(t, c.queryCurrency)
+
+	c = &PricingAPIClient{
+		APIClient:     APIClient{endpoint: server.URL},
+		currency:      "GBP",
+		queryCurrency: true,
+	}
+	_, err := c.RunQueries(resources)
+	assert.EqualError(t, err, "The Cloud Pricing API does not support GBP prices, set currency_exchange_rate to convert the USD prices")
+}
+
+func TestPriceAWSChina(t *testing.T) {
+	china := &schema.ProductFilter{VendorName: strPtr("aws"), Region: strPtr("cn-north-1")}
+	price := gjson.Parse(`{"USD": "", "CNY": "0.84"}`)
+
+	c := &PricingAPIClient{currency: "USD"}
+	assert.Contains(t, c.buildQuery(china, nil).Query, "USD CNY")
+	assert.NotContains(t, c.buildQuery(&schema.ProductFilter{VendorName: strPtr("aws"), Region: strPtr("us-east-1")}, nil).Query, "CNY")
+
+	_, err := c.Price(price)
+	assert.EqualError(t, err, "the price is only available in CNY, set currency to CNY to price resources in the AWS China regions and Alibaba Cloud")
+
+	c = &PricingAPIClient{currency: "CNY", queryCurrency: true}
+	assert.Equal(t, 1, strings.Count(c.buildQuery(china, nil).Query, "CNY"))
+
+	p, err := c.Price(price)
+	require.NoError(t, err)
+	assert.Equal(t, "0.84", p.String())
+}
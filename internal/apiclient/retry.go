@@ -0,0 +1,227 @@
+package apiclient
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	retryMinWait = 500 * time.Millisecond
+	retryMaxWait = 30 * time.Second
+
+	// Retry-After headers asking us to wait longer than this are capped so a misbehaving
+	// server can't stall the run
+	retryAfterMaxWait = 60 * time.Second
+
+	// The circuit opens after this many consecutive failed requests and lets a single
+	// request through after the cooldown to check if the API has recovered
+	circuitBreakerThreshold = 5
+	circuitBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without sending the request when the Cloud Pricing API has
+// failed too many times in a row.
+var ErrCircuitOpen = errors.New("Cloud Pricing API is unavailable, too many requests have failed")
+
+// transientError is an error that might succeed if the request is sent again later, e.g. a
+// 5xx response or a network error. These errors only affect the resources in the failed
+// request rather than failing the whole run.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+func isTransientError(err error) bool {
+	var t *transientError
+	return errors.Is(err, ErrCircuitOpen) || errors.As(err, &t)
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryTransport retries requests that fail with network errors, 429 or 5xx responses
+// using exponential backoff with full jitter. Retry-After headers are respected. The
+// response of the last attempt is returned once the retries are exhausted.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	breaker    *circuitBreaker
+
+	// sleep is overridden in tests
+	sleep func(d time.Duration)
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int) *retryTransport {
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		breaker:    newCircuitBreaker(circuitBreakerThreshold, circuitBreakerCooldown),
+		sleep:      time.Sleep,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("Error retrying request: request body can't be re-read")
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.Wrap(err, "Error retrying request")
+			}
+
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			t.breaker.success()
+			return resp, nil
+		}
+
+		if attempt >= t.maxRetries || req.Context().Err() != nil {
+			t.breaker.failure()
+			return resp, err
+		}
+
+		wait := backoff(attempt)
+		if err != nil {
+			log.Debugf("Retrying Cloud Pricing API request in %s: %s", wait, err)
+		} else {
+			if d, ok := retryAfter(resp); ok {
+				wait = d
+			}
+			log.Debugf("Retrying Cloud Pricing API request in %s: %s", wait, resp.Status)
+
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		t.sleep(wait)
+	}
+}
+
+// backoff returns a random wait between 0 and minWait * 2^attempt, capped at maxWait
+func backoff(attempt int) time.Duration {
+	max := retryMaxWait
+	if attempt < 16 {
+		if d := retryMinWait << uint(attempt); d < max {
+			max = d
+		}
+	}
+
+	// #nosec G404 jitter doesn't need a secure random number
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	} else {
+		return 0, false
+	}
+
+	if d < 0 {
+		d = 0
+	}
+	if d > retryAfterMaxWait {
+		d = retryAfterMaxWait
+	}
+
+	return d, true
+}
+
+// circuitBreaker stops requests being sent after a number of consecutive failures so a
+// run doesn't keep waiting on an API that is down.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// probing is true while the single request after the cooldown is in flight
+	probing bool
+
+	// now is overridden in tests
+	now func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			log.Warnf("Cloud Pricing API failed %d times in a row, pausing requests for %s", b.threshold, b.cooldown)
+		}
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,214 @@
+package apiclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noSleepRetryTransport(maxRetries int, waits *[]time.Duration) *retryTransport {
+	t := newRetryTransport(http.DefaultTransport, maxRetries)
+	t.sleep = func(d time.Duration) {
+		*waits = append(*waits, d)
+	}
+	return t
+}
+
+func TestRetryTransportRetriesServerErrors(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "query", string(body))
+
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: noSleepRetryTransport(3, &waits)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("query"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	require.Len(t, waits, 2)
+	assert.True(t, waits[0] < retryMinWait)
+	assert.Equal(t, 7*time.Second, waits[1])
+}
+
+func TestRetryTransportDoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: noSleepRetryTransport(3, &waits)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRetryTransportReturnsLastResponse(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: noSleepRetryTransport(2, &waits)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		d := backoff(attempt)
+		assert.True(t, d >= 0)
+		assert.True(t, d < retryMaxWait)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+
+	_, ok := retryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "120")
+	d, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, retryAfterMaxWait, d)
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	d, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = retryAfter(resp)
+	assert.False(t, ok)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.allow())
+	b.failure()
+	assert.True(t, b.allow())
+	b.failure()
+
+	// Open
+	assert.False(t, b.allow())
+
+	// Half-open lets a single request through after the cooldown
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	assert.False(t, b.allow())
+
+	// The probe failed so it opens again
+	b.failure()
+	assert.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	b.success()
+
+	// Closed
+	assert.True(t, b.allow())
+	assert.True(t, b.allow())
+}
+
+func TestRetryTransportCircuitOpen(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	rt := noSleepRetryTransport(0, &waits)
+	client := &http.Client{Transport: rt}
+
+	for i := 0; i < circuitBreakerThreshold; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err := client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+}
+
+func TestRunQueriesTransientErrors(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first batch, succeed for the second
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("<html>Service Unavailable</html>"))
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"data": {"products": []}}]`))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	c := &PricingAPIClient{
+		APIClient:   APIClient{endpoint: server.URL, httpClient: &http.Client{Transport: noSleepRetryTransport(0, &waits)}},
+		batchSize:   1,
+		concurrency: 1,
+	}
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{{Name: "Instance usage", ProductFilter: &schema.ProductFilter{Region: strPtr("us-east-1")}}}},
+		{Name: "aws_instance.b", CostComponents: []*schema.CostComponent{{Name: "Instance usage", ProductFilter: &schema.ProductFilter{Region: strPtr("eu-west-1")}}}},
+	}
+
+	results, err := c.RunQueries(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.EqualError(t, results[0].Err, "Received error from API: 503 Service Unavailable")
+	assert.NoError(t, results[1].Err)
+}
@@ -0,0 +1,82 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/pkg/errors"
+)
+
+// Access levels of uploaded estimates
+const (
+	// UploadAccessPrivate estimates can only be viewed by users of the Infracost server
+	UploadAccessPrivate = "private"
+	// UploadAccessPublic estimates can be viewed by anyone with the link
+	UploadAccessPublic = "public"
+)
+
+// ServerAPIClient talks to a self-hosted Infracost server that renders uploaded estimates
+// as HTML reports at shareable URLs.
+type ServerAPIClient struct {
+	APIClient
+}
+
+type UploadOptions struct {
+	// ExpiresIn is how long the link works for, or 0 to use the server's default
+	ExpiresIn time.Duration
+	Access    string
+	// AllowedEmails limits private estimates to these users of the Infracost server
+	AllowedEmails []string
+}
+
+type UploadResponse struct {
+	URL       string     `json:"url"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type uploadInput struct {
+	Report           output.Root `json:"report"`
+	ExpiresInSeconds int64       `json:"expiresInSeconds,omitempty"`
+	Access           string      `json:"access"`
+	AllowedEmails    []string    `json:"allowedEmails,omitempty"`
+}
+
+func NewServerAPIClient(cfg *config.Config) *ServerAPIClient {
+	return &ServerAPIClient{
+		APIClient: APIClient{
+			endpoint:   cfg.ServerAPIEndpoint,
+			apiKey:     cfg.APIKey,
+			httpClient: newHTTPClient(cfg),
+		},
+	}
+}
+
+// Upload uploads the estimate and returns the URL of its HTML report
+func (c *ServerAPIClient) Upload(out output.Root, opts UploadOptions) (UploadResponse, error) {
+	d := uploadInput{
+		Report:           out,
+		ExpiresInSeconds: int64(opts.ExpiresIn / time.Second),
+		Access:           opts.Access,
+		AllowedEmails:    opts.AllowedEmails,
+	}
+
+	respBody, err := c.doRequest("POST", "/reports", d)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	var r UploadResponse
+
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return r, errors.Wrap(err, "Invalid response from API")
+	}
+
+	if r.URL == "" {
+		return r, errors.New("Invalid response from API: no report URL")
+	}
+
+	return r, nil
+}
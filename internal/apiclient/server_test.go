@@ -0,0 +1,58 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerAPIClientUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/reports", r.URL.Path)
+		assert.Equal(t, "abc", r.Header.Get("X-Api-Key"))
+
+		var d map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&d))
+
+		assert.Equal(t, "private", d["access"])
+		assert.Equal(t, float64(86400), d["expiresInSeconds"])
+		assert.Equal(t, []interface{}{"alice@example.com"}, d["allowedEmails"])
+		assert.Equal(t, "0.2", d["report"].(map[string]interface{})["version"])
+
+		_, _ = w.Write([]byte(`{"url": "https://infracost.example.com/reports/123", "expiresAt": "2021-06-02T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	c := NewServerAPIClient(&config.Config{ServerAPIEndpoint: server.URL, APIKey: "abc"})
+
+	r, err := c.Upload(output.Root{Version: "0.2"}, UploadOptions{
+		ExpiresIn:     24 * time.Hour,
+		Access:        UploadAccessPrivate,
+		AllowedEmails: []string{"alice@example.com"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://infracost.example.com/reports/123", r.URL)
+	require.NotNil(t, r.ExpiresAt)
+	assert.Equal(t, time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC), r.ExpiresAt.UTC())
+}
+
+func TestServerAPIClientUploadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error": "Public reports are disabled"}`))
+	}))
+	defer server.Close()
+
+	c := NewServerAPIClient(&config.Config{ServerAPIEndpoint: server.URL})
+
+	_, err := c.Upload(output.Root{}, UploadOptions{Access: UploadAccessPublic})
+	assert.EqualError(t, err, "Received error from API: Public reports are disabled")
+}
@@ -0,0 +1,306 @@
+package apiclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Access tokens are refreshed this long before they expire so they don't expire in-flight
+const oauthTokenExpiryDelta = 30 * time.Second
+
+var insecureSkipVerifyWarning sync.Once
+
+// newHTTPTransport returns the transport used for requests to Infracost's APIs. Requests
+// use the proxy set by the HTTPS_PROXY, HTTP_PROXY and NO_PROXY env vars. Since many
+// enterprise networks intercept TLS, a custom CA bundle can be trusted in addition to the
+// system CAs, or certificate verification can be turned off.
+func newHTTPTransport(cfg *config.Config) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+
+	if cfg.TLSCACertFile == "" && !cfg.TLSInsecureSkipVerify {
+		return t, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.TLSCACertFile != "" {
+		pool, err := loadCertPool(cfg.TLSCACertFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSInsecureSkipVerify {
+		insecureSkipVerifyWarning.Do(func() {
+			log.Warn("TLS certificate verification is disabled, this should only be used for testing")
+		})
+
+		tlsConfig.InsecureSkipVerify = true // #nosec G402
+	}
+
+	t.TLSClientConfig = tlsConfig
+
+	return t, nil
+}
+
+// newHTTPClient returns a client using the shared transport. If the transport can't be
+// created the error is logged and the default client is used.
+func newHTTPClient(cfg *config.Config) *http.Client {
+	t, err := newHTTPTransport(cfg)
+	if err != nil {
+		log.Warnf("Error configuring HTTP client: %s", err)
+		return nil
+	}
+
+	return &http.Client{Transport: t}
+}
+
+// NewExternalHTTPClient returns the HTTP client for services other than Infracost's, e.g. the
+// pricing APIs of other cloud vendors, exporters and policy registries. It uses the shared
+// transport so the proxy and CA certificate settings apply, but not the Cloud Pricing API's
+// headers, credentials or retries. If the transport can't be created the error is logged and
+// the default transport is used.
+func NewExternalHTTPClient(cfg *config.Config) *http.Client {
+	c := &http.Client{Timeout: 30 * time.Second}
+
+	t, err := newHTTPTransport(cfg)
+	if err != nil {
+		log.Warnf("Error configuring HTTP client: %s", err)
+		return c
+	}
+
+	c.Transport = t
+
+	return c
+}
+
+// loadCertPool returns the system CAs plus the CAs in the PEM files
+func loadCertPool(paths ...string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, path := range paths {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading CA certificate %s", path)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Invalid CA certificate %s", path)
+		}
+	}
+
+	return pool, nil
+}
+
+// newPricingHTTPClient returns the HTTP client for the Cloud Pricing API. Failed requests
+// are retried, and for a self-hosted Cloud Pricing API the client adds mutual TLS, custom
+// headers and OAuth2 tokens if they are configured.
+func newPricingHTTPClient(cfg *config.Config) (*http.Client, error) {
+	t, err := newPricingTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: newRetryTransport(t, cfg.PricingAPIMaxRetries)}, nil
+}
+
+// newPricingTransport returns the transport for mutual TLS, custom headers or OAuth2 tokens.
+// It returns the shared transport if none of these are configured.
+func newPricingTransport(cfg *config.Config) (http.RoundTripper, error) {
+	base, err := newHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hasTLS := cfg.PricingAPIClientCert != "" || cfg.PricingAPIClientKey != "" || cfg.PricingAPICACert != ""
+
+	if !hasTLS && len(cfg.PricingAPIHeaders) == 0 && cfg.PricingAPIOAuthTokenURL == "" {
+		return base, nil
+	}
+
+	if hasTLS {
+		tlsConfig, err := pricingTLSConfig(cfg, base.TLSClientConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		base.TLSClientConfig = tlsConfig
+	}
+
+	t := &pricingTransport{
+		base:    base,
+		headers: cfg.PricingAPIHeaders,
+	}
+
+	if cfg.PricingAPIOAuthTokenURL != "" {
+		t.token = &oauthClientCredentials{
+			client:       &http.Client{Transport: base},
+			tokenURL:     cfg.PricingAPIOAuthTokenURL,
+			clientID:     cfg.PricingAPIOAuthClientID,
+			clientSecret: cfg.PricingAPIOAuthClientSecret,
+			scopes:       cfg.PricingAPIOAuthScopes,
+		}
+	}
+
+	return t, nil
+}
+
+// pricingTLSConfig adds the Cloud Pricing API client certificate and CA certificate to the
+// shared TLS config.
+func pricingTLSConfig(cfg *config.Config, shared *tls.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if shared != nil {
+		tlsConfig = shared.Clone()
+	}
+
+	if cfg.PricingAPIClientCert != "" || cfg.PricingAPIClientKey != "" {
+		if cfg.PricingAPIClientCert == "" || cfg.PricingAPIClientKey == "" {
+			return nil, errors.New("Both INFRACOST_PRICING_API_CLIENT_CERT and INFRACOST_PRICING_API_CLIENT_KEY must be set for mutual TLS")
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.PricingAPIClientCert, cfg.PricingAPIClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error loading Cloud Pricing API client certificate")
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.PricingAPICACert != "" {
+		caFiles := []string{cfg.PricingAPICACert}
+		if cfg.TLSCACertFile != "" {
+			caFiles = append(caFiles, cfg.TLSCACertFile)
+		}
+
+		pool, err := loadCertPool(caFiles...)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// pricingTransport adds the custom headers and the OAuth2 bearer token to each request
+type pricingTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+	token   *oauthClientCredentials
+}
+
+func (t *pricingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	if t.token != nil {
+		token, err := t.token.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// oauthClientCredentials gets access tokens using the OAuth2 client credentials grant and
+// caches them until they expire. Tokens without an expiry are cached for the whole run.
+type oauthClientCredentials struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (o *oauthClientCredentials) Token() (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.accessToken != "" && (o.expiry.IsZero() || time.Now().Add(oauthTokenExpiryDelta).Before(o.expiry)) {
+		return o.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.scopes) > 0 {
+		form.Set("scope", strings.Join(o.scopes, " "))
+	}
+
+	req, err := http.NewRequest("POST", o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, "Error generating OAuth2 token request")
+	}
+
+	req.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Error requesting OAuth2 token")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid OAuth2 token response")
+	}
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Error requesting OAuth2 token: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var r oauthTokenResponse
+
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid OAuth2 token response")
+	}
+
+	if r.AccessToken == "" {
+		return "", errors.New("Invalid OAuth2 token response: no access_token")
+	}
+
+	o.accessToken = r.AccessToken
+	o.expiry = time.Time{}
+	if r.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+
+	return o.accessToken, nil
+}
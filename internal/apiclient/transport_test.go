@@ -0,0 +1,146 @@
+package apiclient
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricingAPIClientAuth(t *testing.T) {
+	tokenRequests := 0
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+
+		clientID, clientSecret, _ := r.BasicAuth()
+		assert.Equal(t, "infracost", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.Equal(t, "prices:read", r.FormValue("scope"))
+
+		_, _ = w.Write([]byte(`{"access_token": "token123", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token123", r.Header.Get("Authorization"))
+		assert.Equal(t, "gateway-value", r.Header.Get("X-Gateway"))
+		assert.Equal(t, "abc", r.Header.Get("X-Api-Key"))
+
+		_, _ = w.Write([]byte(`[{"data": {"products": []}}]`))
+	}))
+	defer apiServer.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.APIKey = "abc"
+	cfg.NoCache = true
+	cfg.PricingAPIEndpoint = apiServer.URL
+	cfg.PricingAPIHeaders = map[string]string{"X-Gateway": "gateway-value"}
+	cfg.PricingAPIOAuthTokenURL = tokenServer.URL
+	cfg.PricingAPIOAuthClientID = "infracost"
+	cfg.PricingAPIOAuthClientSecret = "secret"
+	cfg.PricingAPIOAuthScopes = []string{"prices:read"}
+
+	c, err := NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		results, err := c.doQueries([]GraphQLQuery{{Query: "{}"}})
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	}
+
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestPricingAPIClientInvalidTLS(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.PricingAPIClientCert = "client.pem"
+
+	_, err := NewPricingAPIClient(cfg)
+	assert.EqualError(t, err, "Both INFRACOST_PRICING_API_CLIENT_CERT and INFRACOST_PRICING_API_CLIENT_KEY must be set for mutual TLS")
+
+	cfg = config.DefaultConfig()
+	cfg.PricingAPICACert = "missing-ca.pem"
+
+	_, err = NewPricingAPIClient(cfg)
+	assert.Error(t, err)
+}
+
+func TestPricingAPIClientDefault(t *testing.T) {
+	c, err := NewPricingAPIClient(config.DefaultConfig())
+	require.NoError(t, err)
+
+	rt, ok := c.httpClient.Transport.(*retryTransport)
+	require.True(t, ok)
+	assert.Equal(t, 3, rt.maxRetries)
+
+	base, ok := rt.base.(*http.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, base.Proxy)
+}
+
+func TestHTTPTransportCACertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"data": {"products": []}}]`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig()
+	cfg.NoCache = true
+	cfg.PricingAPIEndpoint = server.URL
+	cfg.PricingAPIMaxRetries = 0
+
+	// The server's certificate isn't trusted by default
+	c, err := NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+	_, err = c.doQueries([]GraphQLQuery{{Query: "{}"}})
+	assert.Error(t, err)
+
+	cfg.TLSCACertFile = caFile
+
+	c, err = NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+	results, err := c.doQueries([]GraphQLQuery{{Query: "{}"}})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestHTTPTransportInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"apiKey": "abc"}`))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.DashboardAPIEndpoint = server.URL
+	cfg.TLSInsecureSkipVerify = true
+
+	c := NewDashboardAPIClient(&config.RunContext{Config: cfg})
+	r, err := c.CreateAPIKey("name", "email@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", r.APIKey)
+}
+
+func TestHTTPTransportInvalidCACertFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a cert"), 0600))
+
+	cfg := config.DefaultConfig()
+	cfg.TLSCACertFile = caFile
+
+	_, err := NewPricingAPIClient(cfg)
+	assert.EqualError(t, err, fmt.Sprintf("Invalid CA certificate %s", caFile))
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -21,6 +22,92 @@ type Project struct {
 	TerraformCloudToken string `yaml:"terraform_cloud_token,omitempty" envconfig:"INFRACOST_TERRAFORM_CLOUD_TOKEN"`
 	UsageFile           string `yaml:"usage_file,omitempty" ignored:"true"`
 	TerraformUseState   bool   `yaml:"terraform_use_state,omitempty" ignored:"true"`
+	// BaseUsageFile is a usage file shared by several projects, e.g. with the usage of the
+	// resource types of a monorepo. The project's usage file is merged over it.
+	BaseUsageFile string `yaml:"base_usage_file,omitempty" ignored:"true"`
+	// CountEstimate is the number of instances to assume for resources whose count or
+	// for_each isn't known until apply. The count_estimate in the usage file overrides it.
+	CountEstimate int `yaml:"count_estimate,omitempty" ignored:"true"`
+	// ReservedInstances are the Reserved Instance options assumed for the EC2, RDS,
+	// ElastiCache, Redshift, Azure VM and Azure SQL resources of the project. The
+	// reserved_instance_* usage keys of a resource override them.
+	ReservedInstances *ReservedInstances `yaml:"reserved_instances,omitempty" ignored:"true"`
+	// AzureHybridBenefit assumes Azure Hybrid Benefit for the Windows VMs and SQL databases
+	// of the project. The azure_hybrid_benefit usage key of a resource overrides it.
+	AzureHybridBenefit bool `yaml:"azure_hybrid_benefit,omitempty" ignored:"true"`
+	// SavingsPlans are the AWS Savings Plans that are applied to the on-demand compute
+	// costs of the project after it is priced.
+	SavingsPlans []*SavingsPlan `yaml:"savings_plans,omitempty" ignored:"true"`
+	// CommittedUseDiscounts are the GCP commitments that are applied to the on-demand
+	// Compute Engine and GKE costs of the project after it is priced.
+	CommittedUseDiscounts []*CommittedUseDiscount `yaml:"committed_use_discounts,omitempty" ignored:"true"`
+	// OperatingSchedules are the schedules of the EC2, RDS, Compute Engine and Azure VM
+	// resources of the project with matching tags. The operating_schedule usage key of a
+	// resource overrides them.
+	OperatingSchedules []*OperatingSchedule `yaml:"operating_schedules,omitempty" ignored:"true"`
+	// TerraformVarFiles, TerraformVars and TerraformEnvFiles are passed to terraform plan
+	// when Infracost runs Terraform for a directory. TerraformEnvFiles are dotenv files
+	// containing TF_VAR_ environment variables.
+	TerraformVarFiles []string          `yaml:"terraform_var_files,omitempty" ignored:"true"`
+	TerraformVars     map[string]string `yaml:"terraform_vars,omitempty" ignored:"true"`
+	TerraformEnvFiles []string          `yaml:"terraform_env_files,omitempty" ignored:"true"`
+	// SourcePath is the path that was specified for projects that are cloned from a git
+	// source, since Path is set to the directory in the clone.
+	SourcePath string `yaml:"-" ignored:"true"`
+}
+
+// ReservedInstances are the Reserved Instance options of a project. The values are the
+// same as the reserved_instance_type, reserved_instance_term and
+// reserved_instance_payment_option usage keys.
+type ReservedInstances struct {
+	Type          string `yaml:"type,omitempty"`
+	Term          string `yaml:"term,omitempty"`
+	PaymentOption string `yaml:"payment_option,omitempty"`
+}
+
+// OperatingSchedule is the schedule that resources with all of the tags run on, e.g.
+// weekdays 08:00-20:00 for resources tagged with environment: dev. The schedule is in the
+// same format as the operating_schedule usage key.
+type OperatingSchedule struct {
+	Tags     map[string]string `yaml:"tags"`
+	Schedule string            `yaml:"schedule"`
+}
+
+// SavingsPlan is an AWS Compute or EC2 Instance Savings Plan. Savings Plan rates aren't
+// available from the Cloud Pricing API so the discount off the on-demand rates is set in
+// the config file, e.g. from the AWS Savings Plans pricing page.
+type SavingsPlan struct {
+	// Type is compute or ec2_instance
+	Type string `yaml:"type"`
+	Term string `yaml:"term,omitempty"`
+	// HourlyCommitment is the amount in USD per hour committed to the plan
+	HourlyCommitment float64 `yaml:"hourly_commitment"`
+	DiscountPercent  float64 `yaml:"discount_percent"`
+	// Region and InstanceFamily are required for EC2 Instance Savings Plans
+	Region         string `yaml:"region,omitempty"`
+	InstanceFamily string `yaml:"instance_family,omitempty"`
+}
+
+// CommittedUseDiscount is a GCP resource-based commitment for vCPUs and memory in a region.
+// Usage above the commitment is priced on-demand.
+type CommittedUseDiscount struct {
+	Region string `yaml:"region"`
+	// Term is 1_year or 3_year
+	Term     string  `yaml:"term"`
+	VCPUs    float64 `yaml:"vcpus"`
+	MemoryGB float64 `yaml:"memory_gb"`
+	// MachineSeries limits the commitment to a machine series, e.g. n1 or e2
+	MachineSeries string `yaml:"machine_series,omitempty"`
+	// DiscountPercent overrides the general-purpose discount of the term
+	DiscountPercent float64 `yaml:"discount_percent,omitempty"`
+}
+
+// Environment groups projects, possibly across different cloud providers,
+// so their costs can be reported together.
+type Environment struct {
+	Name          string   `yaml:"name"`
+	Projects      []string `yaml:"projects"`
+	MonthlyBudget *float64 `yaml:"monthly_budget,omitempty"`
 }
 
 type Config struct { // nolint:golint
@@ -35,13 +122,132 @@ type Config struct { // nolint:golint
 	PricingAPIEndpoint        string `yaml:"pricing_api_endpoint,omitempty" envconfig:"INFRACOST_PRICING_API_ENDPOINT"`
 	DefaultPricingAPIEndpoint string `yaml:"default_pricing_api_endpoint,omitempty" envconfig:"INFRACOST_DEFAULT_PRICING_API_ENDPOINT"`
 	DashboardAPIEndpoint      string `yaml:"dashboard_api_endpoint,omitempty" envconfig:"INFRACOST_DASHBOARD_API_ENDPOINT"`
+	ServerAPIEndpoint         string `yaml:"server_api_endpoint,omitempty" envconfig:"INFRACOST_SERVER_API_ENDPOINT"`
 	EnableDashboard           bool   `yaml:"enable_dashboard,omitempty" envconfig:"INFRACOST_ENABLE_DASHBOARD"`
-
-	Projects      []*Project `yaml:"projects" ignored:"true"`
-	Format        string     `yaml:"format,omitempty" ignored:"true"`
-	ShowSkipped   bool       `yaml:"show_skipped,omitempty" ignored:"true"`
-	SyncUsageFile bool       `yaml:"sync_usage_file,omitempty" ignored:"true"`
-	Fields        []string   `yaml:"fields,omitempty" ignored:"true"`
+	// TLSCACertFile is a PEM CA bundle that is trusted in addition to the system CAs for
+	// requests to the Cloud Pricing API and Infracost's other APIs, for networks that
+	// intercept TLS. TLSInsecureSkipVerify turns off certificate verification altogether.
+	// Proxies are set with the standard HTTPS_PROXY and NO_PROXY env vars.
+	TLSCACertFile         string `yaml:"tls_ca_cert_file,omitempty" envconfig:"INFRACOST_TLS_CA_CERT_FILE"`
+	TLSInsecureSkipVerify bool   `yaml:"tls_insecure_skip_verify,omitempty" envconfig:"INFRACOST_TLS_INSECURE_SKIP_VERIFY"`
+	// PricingDatabase is the path to a price database downloaded with infracost pricing download.
+	// When it's set all prices are looked up from it instead of the Cloud Pricing API.
+	PricingDatabase string `yaml:"pricing_database,omitempty" envconfig:"INFRACOST_PRICING_DATABASE"`
+	// PricingSource is the name of a pricing source registered with pricing.RegisterSource
+	// that prices are looked up from instead of the Cloud Pricing API, e.g. an internal rate card.
+	PricingSource string `yaml:"pricing_source,omitempty" envconfig:"INFRACOST_PRICING_SOURCE"`
+	// Alibaba Cloud prices aren't in the Cloud Pricing API so they're looked up from the
+	// Alibaba Cloud BSS OpenAPI at AlicloudPricingEndpoint using these credentials, which
+	// are the same env vars as the alicloud Terraform provider uses.
+	AlicloudAccessKey       string `envconfig:"ALICLOUD_ACCESS_KEY"`
+	AlicloudSecretKey       string `envconfig:"ALICLOUD_SECRET_KEY"`
+	AlicloudPricingEndpoint string `yaml:"alicloud_pricing_endpoint,omitempty" envconfig:"INFRACOST_ALICLOUD_PRICING_ENDPOINT"`
+	// OCIPricingEndpoint is the OCI price list API that Oracle Cloud Infrastructure prices
+	// are looked up from, since they aren't in the Cloud Pricing API either.
+	OCIPricingEndpoint string `yaml:"oci_pricing_endpoint,omitempty" envconfig:"INFRACOST_OCI_PRICING_ENDPOINT"`
+	// IBMPricingEndpoint is the IBM Cloud Global Catalog API that IBM Cloud prices are looked
+	// up from. IBM Cloud prices are by country, IBMPricingCountry is the ISO 3166 alpha-3 code
+	// of the country whose prices are used, e.g. USA.
+	IBMPricingEndpoint string `yaml:"ibm_pricing_endpoint,omitempty" envconfig:"INFRACOST_IBM_PRICING_ENDPOINT"`
+	IBMPricingCountry  string `yaml:"ibm_pricing_country,omitempty" envconfig:"INFRACOST_IBM_PRICING_COUNTRY"`
+	// AWSCloudWatchEndpoint replaces the regional CloudWatch endpoints that the usage of AWS
+	// resources is fetched from with FetchUsageFromCloudWatch, e.g. for a VPC endpoint.
+	AWSCloudWatchEndpoint string `yaml:"aws_cloudwatch_endpoint,omitempty" envconfig:"INFRACOST_AWS_CLOUDWATCH_ENDPOINT"`
+	// AWSCostExplorerEndpoint replaces the Cost Explorer endpoint that the usage of AWS
+	// resources is fetched from with FetchUsageFromCostExplorer.
+	AWSCostExplorerEndpoint string `yaml:"aws_cost_explorer_endpoint,omitempty" envconfig:"INFRACOST_AWS_COST_EXPLORER_ENDPOINT"`
+	// GoogleMonitoringEndpoint replaces the Cloud Monitoring endpoint that the usage of Google
+	// Cloud resources is fetched from with FetchUsageFromCloudMonitoring.
+	GoogleMonitoringEndpoint string `yaml:"google_monitoring_endpoint,omitempty" envconfig:"INFRACOST_GOOGLE_MONITORING_ENDPOINT"`
+	// AzureMonitorEndpoint replaces the Azure Resource Manager endpoint that the usage of Azure
+	// resources is fetched from with FetchUsageFromAzureMonitor.
+	AzureMonitorEndpoint string `yaml:"azure_monitor_endpoint,omitempty" envconfig:"INFRACOST_AZURE_MONITOR_ENDPOINT"`
+	// PricingCacheTTL is how long prices from the Cloud Pricing API are cached on disk, e.g. 24h.
+	// Setting it to 0 disables the cache.
+	PricingCacheTTL time.Duration `yaml:"pricing_cache_ttl,omitempty" envconfig:"INFRACOST_PRICING_CACHE_TTL"`
+	// PricingAPIBatchSize is the number of price queries sent in each request to the Cloud
+	// Pricing API and PricingAPIConcurrency is how many requests are sent at the same time.
+	PricingAPIBatchSize   int `yaml:"pricing_api_batch_size,omitempty" envconfig:"INFRACOST_PRICING_API_BATCH_SIZE"`
+	PricingAPIConcurrency int `yaml:"pricing_api_concurrency,omitempty" envconfig:"INFRACOST_PRICING_API_CONCURRENCY"`
+	// PricingAPIMaxRetries is how many times a request to the Cloud Pricing API is retried
+	// if it fails with a network error, 429 or 5xx response.
+	PricingAPIMaxRetries int `yaml:"pricing_api_max_retries,omitempty" envconfig:"INFRACOST_PRICING_API_MAX_RETRIES"`
+	// Currency is the ISO 4217 code of the currency that prices are requested in from the
+	// Cloud Pricing API. CurrencyExchangeRate is the number of units of the currency per USD,
+	// which is used to convert the USD prices if the API doesn't have the currency.
+	Currency             string  `yaml:"currency,omitempty" envconfig:"INFRACOST_CURRENCY"`
+	CurrencyExchangeRate float64 `yaml:"currency_exchange_rate,omitempty" envconfig:"INFRACOST_CURRENCY_EXCHANGE_RATE"`
+	// UsageProfile is the name of the built-in usage profile, e.g. low, medium or high, whose
+	// usage is used for the resources that don't have usage in the usage file.
+	UsageProfile string `yaml:"usage_profile,omitempty" envconfig:"INFRACOST_USAGE_PROFILE"`
+	// UsageFromJSON is the JSON output of a previous run whose usage is replayed for the
+	// usage keys that aren't in the usage file, e.g. to compare branches with the same usage.
+	UsageFromJSON string `yaml:"usage_from_json,omitempty" ignored:"true"`
+	// UsageSensitivityChange is the fraction that infracost sensitivity lowers and raises
+	// each usage key by, e.g. 0.5. Resources are only created with the changed usage if
+	// it's set.
+	UsageSensitivityChange float64 `yaml:"usage_sensitivity_change,omitempty" ignored:"true"`
+	// PriceOverridesFile is a YAML file of prices that replace or adjust the prices from
+	// the Cloud Pricing API, e.g. for internal chargeback rates.
+	PriceOverridesFile string `yaml:"price_overrides_file,omitempty" envconfig:"INFRACOST_PRICE_OVERRIDES_FILE"`
+	// PriceMaxAge is how long ago a price can have become effective before its cost component
+	// gets a stale price warning, e.g. 8760h. Setting it to 0 disables the warnings.
+	PriceMaxAge time.Duration `yaml:"price_max_age,omitempty" envconfig:"INFRACOST_PRICE_MAX_AGE"`
+	// These configure how Infracost connects to a self-hosted Cloud Pricing API behind a
+	// corporate gateway. The client cert and key are used for mutual TLS, the CA cert is used
+	// to verify the server, and the headers are added to every request, e.g. name:value,name2:value2.
+	PricingAPIClientCert string            `yaml:"pricing_api_client_cert,omitempty" envconfig:"INFRACOST_PRICING_API_CLIENT_CERT"`
+	PricingAPIClientKey  string            `yaml:"pricing_api_client_key,omitempty" envconfig:"INFRACOST_PRICING_API_CLIENT_KEY"`
+	PricingAPICACert     string            `yaml:"pricing_api_ca_cert,omitempty" envconfig:"INFRACOST_PRICING_API_CA_CERT"`
+	PricingAPIHeaders    map[string]string `yaml:"pricing_api_headers,omitempty" envconfig:"INFRACOST_PRICING_API_HEADERS"`
+	// If a token URL is set then an OAuth2 access token is requested using the client
+	// credentials grant and sent as a bearer token to the Cloud Pricing API.
+	PricingAPIOAuthTokenURL     string   `yaml:"pricing_api_oauth_token_url,omitempty" envconfig:"INFRACOST_PRICING_API_OAUTH_TOKEN_URL"`
+	PricingAPIOAuthClientID     string   `yaml:"pricing_api_oauth_client_id,omitempty" envconfig:"INFRACOST_PRICING_API_OAUTH_CLIENT_ID"`
+	PricingAPIOAuthClientSecret string   `envconfig:"INFRACOST_PRICING_API_OAUTH_CLIENT_SECRET"`
+	PricingAPIOAuthScopes       []string `yaml:"pricing_api_oauth_scopes,omitempty" envconfig:"INFRACOST_PRICING_API_OAUTH_SCOPES"`
+	// FallbackRegions maps a Terraform provider, e.g. aws, to the region whose prices are
+	// used when a price isn't available in the resource's region.
+	FallbackRegions map[string]string `yaml:"fallback_regions,omitempty" ignored:"true"`
+
+	Projects            []*Project     `yaml:"projects" ignored:"true"`
+	Environments        []*Environment `yaml:"environments,omitempty" ignored:"true"`
+	Exports             *Exports       `yaml:"exports,omitempty" ignored:"true"`
+	PolicyPacks         []*PolicyPack  `yaml:"policy_packs,omitempty" ignored:"true"`
+	Discounts           []*Discount    `yaml:"discounts,omitempty" ignored:"true"`
+	Format              string         `yaml:"format,omitempty" ignored:"true"`
+	ShowSkipped         bool           `yaml:"show_skipped,omitempty" ignored:"true"`
+	ShowUsageProvenance bool           `yaml:"show_usage_provenance,omitempty" ignored:"true"`
+	ReconcileRounding   bool           `yaml:"reconcile_rounding,omitempty" ignored:"true"`
+	SyncUsageFile       bool           `yaml:"sync_usage_file,omitempty" ignored:"true"`
+	Fields              []string       `yaml:"fields,omitempty" ignored:"true"`
+	SampleSize          int            `yaml:"sample_size,omitempty" ignored:"true"`
+	NoCache             bool           `yaml:"no_cache,omitempty" ignored:"true"`
+	RefreshCache        bool           `yaml:"refresh_cache,omitempty" ignored:"true"`
+
+	// FetchUsageFromCloudWatch fetches the usage of existing AWS resources from their
+	// CloudWatch metrics. The usage file takes precedence over the metrics.
+	FetchUsageFromCloudWatch bool `yaml:"fetch_usage_from_cloudwatch,omitempty" ignored:"true"`
+	// FetchUsageFromCostExplorer fetches the usage of existing AWS resources from their
+	// resource level Cost & Usage data in Cost Explorer. CloudWatch metrics take precedence.
+	FetchUsageFromCostExplorer bool `yaml:"fetch_usage_from_cost_explorer,omitempty" ignored:"true"`
+	// FetchUsageFromCloudMonitoring fetches the usage of existing Google Cloud resources from
+	// their Cloud Monitoring metrics.
+	FetchUsageFromCloudMonitoring bool `yaml:"fetch_usage_from_cloud_monitoring,omitempty" ignored:"true"`
+	// FetchUsageFromAzureMonitor fetches the usage of existing Azure resources from their
+	// Azure Monitor metrics.
+	FetchUsageFromAzureMonitor bool `yaml:"fetch_usage_from_azure_monitor,omitempty" ignored:"true"`
+	// PrometheusUsage fetches the usage of resources from the results of PromQL queries.
+	// The usage file takes precedence over the query results.
+	PrometheusUsage *PrometheusUsage `yaml:"prometheus_usage,omitempty" ignored:"true"`
+	// DatadogUsage fetches the usage of resources from the results of Datadog metric queries.
+	// The usage file takes precedence over the query results.
+	DatadogUsage *DatadogUsage `yaml:"datadog_usage,omitempty" ignored:"true"`
+	// RemoteUsage fetches the usage of resources from an HTTP service.
+	RemoteUsage *RemoteUsage `yaml:"remote_usage,omitempty" ignored:"true"`
+	// KubernetesUsage fetches the node counts of Kubernetes node pools from the metrics of
+	// their clusters.
+	KubernetesUsage *KubernetesUsage `yaml:"kubernetes_usage,omitempty" ignored:"true"`
 }
 
 func init() {
@@ -59,9 +265,18 @@ func DefaultConfig() *Config {
 		DefaultPricingAPIEndpoint: "https://pricing.api.infracost.io",
 		PricingAPIEndpoint:        "https://pricing.api.infracost.io",
 		DashboardAPIEndpoint:      "https://dashboard.api.infracost.io",
+		AlicloudPricingEndpoint:   "https://business.aliyuncs.com",
+		OCIPricingEndpoint:        "https://apexapps.oracle.com/pls/apex/cetools/api/v1/products/",
+		IBMPricingEndpoint:        "https://globalcatalog.cloud.ibm.com/api/v1",
+		IBMPricingCountry:         "USA",
 
 		Projects: []*Project{{}},
 
+		PricingCacheTTL:      24 * time.Hour,
+		PricingAPIBatchSize:  100,
+		PricingAPIMaxRetries: 3,
+		Currency:             "USD",
+
 		Format: "table",
 		Fields: []string{"monthlyQuantity", "unit", "monthlyCost"},
 	}
@@ -74,6 +289,42 @@ func (c *Config) LoadFromConfigFile(path string) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	c.Environments = cfgFile.Environments
+	c.Exports = cfgFile.Exports
+	c.PolicyPacks = cfgFile.PolicyPacks
+	c.Discounts = cfgFile.Discounts
+	c.FallbackRegions = cfgFile.FallbackRegions
+	c.PrometheusUsage = cfgFile.PrometheusUsage
+	c.DatadogUsage = cfgFile.DatadogUsage
+	c.RemoteUsage = cfgFile.RemoteUsage
+	c.KubernetesUsage = cfgFile.KubernetesUsage
+
+	if cfgFile.PriceOverridesFile != "" {
+		c.PriceOverridesFile = cfgFile.PriceOverridesFile
+	}
+
+	if cfgFile.PriceMaxAge != 0 {
+		c.PriceMaxAge = cfgFile.PriceMaxAge
+	}
+
+	if cfgFile.Currency != "" {
+		c.Currency = cfgFile.Currency
+	}
+	if cfgFile.CurrencyExchangeRate != 0 {
+		c.CurrencyExchangeRate = cfgFile.CurrencyExchangeRate
+	}
+
+	if cfgFile.UsageProfile != "" {
+		c.UsageProfile = cfgFile.UsageProfile
+	}
+
+	// Flags take precedence over the config file
+	if c.TLSCACertFile == "" {
+		c.TLSCACertFile = cfgFile.TLSCACertFile
+	}
+	if cfgFile.TLSInsecureSkipVerify {
+		c.TLSInsecureSkipVerify = true
+	}
 
 	// Reload the environment to overwrite any of the config file configs
 	err = c.LoadFromEnv()
@@ -160,7 +411,13 @@ func (c *Config) IsSelfHosted() bool {
 }
 
 func (c *Config) IsTelemetryDisabled() bool {
-	return c.IsSelfHosted() && IsFalsy(os.Getenv("INFRACOST_SELF_HOSTED_TELEMETRY"))
+	return c.IsOffline() || (c.IsSelfHosted() && IsFalsy(os.Getenv("INFRACOST_SELF_HOSTED_TELEMETRY")))
+}
+
+// IsOffline returns true if prices are looked up from a price database, in which case no
+// requests should be made to Infracost's APIs since they might not be reachable.
+func (c *Config) IsOffline() bool {
+	return c.PricingDatabase != ""
 }
 
 func IsTest() bool {
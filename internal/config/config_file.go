@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v2"
@@ -14,8 +18,29 @@ const minConfigFileVersion = "0.1"
 const maxConfigFileVersion = "0.1"
 
 type ConfigFileSpec struct { // nolint:golint
-	Version  string     `yaml:"version"`
-	Projects []*Project `yaml:"projects" ignored:"true"`
+	Version      string         `yaml:"version"`
+	Projects     []*Project     `yaml:"projects" ignored:"true"`
+	Environments []*Environment `yaml:"environments,omitempty" ignored:"true"`
+	Exports      *Exports       `yaml:"exports,omitempty" ignored:"true"`
+	PolicyPacks  []*PolicyPack  `yaml:"policy_packs,omitempty" ignored:"true"`
+	Discounts    []*Discount    `yaml:"discounts,omitempty" ignored:"true"`
+
+	FallbackRegions map[string]string `yaml:"fallback_regions,omitempty" ignored:"true"`
+
+	PriceOverridesFile string        `yaml:"price_overrides_file,omitempty" ignored:"true"`
+	PriceMaxAge        time.Duration `yaml:"price_max_age,omitempty" ignored:"true"`
+
+	Currency             string  `yaml:"currency,omitempty" ignored:"true"`
+	CurrencyExchangeRate float64 `yaml:"currency_exchange_rate,omitempty" ignored:"true"`
+
+	UsageProfile    string           `yaml:"usage_profile,omitempty" ignored:"true"`
+	PrometheusUsage *PrometheusUsage `yaml:"prometheus_usage,omitempty" ignored:"true"`
+	DatadogUsage    *DatadogUsage    `yaml:"datadog_usage,omitempty" ignored:"true"`
+	RemoteUsage     *RemoteUsage     `yaml:"remote_usage,omitempty" ignored:"true"`
+	KubernetesUsage *KubernetesUsage `yaml:"kubernetes_usage,omitempty" ignored:"true"`
+
+	TLSCACertFile         string `yaml:"tls_ca_cert_file,omitempty" ignored:"true"`
+	TLSInsecureSkipVerify bool   `yaml:"tls_insecure_skip_verify,omitempty" ignored:"true"`
 }
 
 func LoadConfigFile(path string) (ConfigFileSpec, error) {
@@ -39,9 +64,180 @@ func LoadConfigFile(path string) (ConfigFileSpec, error) {
 		return cfgFile, fmt.Errorf("Invalid config file version. Supported versions are %s ≤ x ≤ %s", minConfigFileVersion, maxConfigFileVersion)
 	}
 
+	err = checkProjects(cfgFile)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	err = checkEnvironments(cfgFile)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	err = checkExports(cfgFile.Exports)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	err = checkDiscounts(cfgFile.Discounts)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	err = checkPrometheusUsage(cfgFile.PrometheusUsage)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	err = checkDatadogUsage(cfgFile.DatadogUsage)
+	if err != nil {
+		return cfgFile, err
+	}
+
+	if cfgFile.RemoteUsage != nil && cfgFile.RemoteUsage.URL == "" {
+		return cfgFile, errors.New("The remote_usage in the config file must have a url")
+	}
+
+	err = checkKubernetesUsage(cfgFile.KubernetesUsage)
+	if err != nil {
+		return cfgFile, err
+	}
+
 	return cfgFile, nil
 }
 
+// checkProjects checks that no project is specified more than once, since its costs would be
+// counted twice in the totals, and that the operating schedules of the projects are valid
+func checkProjects(cfgFile ConfigFileSpec) error {
+	for i, p := range cfgFile.Projects {
+		for _, other := range cfgFile.Projects[:i] {
+			if reflect.DeepEqual(p, other) {
+				return fmt.Errorf("The project %s is specified more than once in the config file. Remove the duplicate or use a different terraform_workspace, terraform_var_files or usage_file for each one", p.Path)
+			}
+		}
+
+		for _, s := range p.OperatingSchedules {
+			if len(s.Tags) == 0 {
+				return fmt.Errorf("The operating_schedules of the project %s must have tags", p.Path)
+			}
+
+			if _, err := schema.ParseOperatingSchedule(s.Schedule); err != nil {
+				return fmt.Errorf("The operating_schedules of the project %s are invalid. %s", p.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkEnvironments(cfgFile ConfigFileSpec) error {
+	projectPaths := make(map[string]bool, len(cfgFile.Projects))
+	for _, p := range cfgFile.Projects {
+		projectPaths[filepath.Clean(p.Path)] = true
+	}
+
+	names := make(map[string]bool, len(cfgFile.Environments))
+
+	for _, e := range cfgFile.Environments {
+		if e.Name == "" {
+			return errors.New("Environments in the config file must have a name")
+		}
+
+		if names[e.Name] {
+			return fmt.Errorf("Environment %s is defined more than once in the config file", e.Name)
+		}
+		names[e.Name] = true
+
+		for _, path := range e.Projects {
+			if !projectPaths[filepath.Clean(path)] {
+				return fmt.Errorf("Environment %s includes project %s which is not defined in the config file projects", e.Name, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkExports(exports *Exports) error {
+	if exports == nil {
+		return nil
+	}
+
+	if e := exports.InfluxDB; e != nil && (e.URL == "" || e.Org == "" || e.Bucket == "") {
+		return errors.New("The influxdb export in the config file must have a url, org and bucket")
+	}
+
+	if e := exports.Timestream; e != nil && (e.Region == "" || e.Database == "" || e.Table == "") {
+		return errors.New("The timestream export in the config file must have a region, database and table")
+	}
+
+	return nil
+}
+
+func checkDiscounts(discounts []*Discount) error {
+	for _, d := range discounts {
+		if d.Percent <= 0 || d.Percent > 100 {
+			return fmt.Errorf("Discounts in the config file must have a percent greater than 0 and at most 100, got %v", d.Percent)
+		}
+	}
+
+	return nil
+}
+
+func checkPrometheusUsage(p *PrometheusUsage) error {
+	if p == nil {
+		return nil
+	}
+
+	if p.URL == "" {
+		return errors.New("The prometheus_usage in the config file must have a url")
+	}
+
+	for _, q := range p.Queries {
+		if q.ResourceType == "" || q.UsageKey == "" || q.Query == "" {
+			return errors.New("The prometheus_usage queries in the config file must have a resource_type, usage_key and query")
+		}
+	}
+
+	return nil
+}
+
+func checkDatadogUsage(d *DatadogUsage) error {
+	if d == nil {
+		return nil
+	}
+
+	for _, q := range d.Queries {
+		if q.ResourceType == "" || q.UsageKey == "" || q.Query == "" {
+			return errors.New("The datadog_usage queries in the config file must have a resource_type, usage_key and query")
+		}
+
+		switch q.Reducer {
+		case "", "sum", "avg", "min", "max", "last":
+		default:
+			return fmt.Errorf("Invalid reducer %s in the datadog_usage queries in the config file, expected sum, avg, min, max or last", q.Reducer)
+		}
+	}
+
+	return nil
+}
+
+func checkKubernetesUsage(k *KubernetesUsage) error {
+	if k == nil {
+		return nil
+	}
+
+	if k.URL == "" {
+		return errors.New("The kubernetes_usage in the config file must have a url")
+	}
+
+	if k.TargetUtilization < 0 || k.TargetUtilization > 1 {
+		return errors.New("The kubernetes_usage target_utilization in the config file must be between 0 and 1, e.g. 0.8")
+	}
+
+	return nil
+}
+
 func checkVersion(v string) bool {
 	if !strings.HasPrefix(v, "v") {
 		v = "v" + v
@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// DatadogUsage configures the Datadog metric queries whose results are used as the usage of
+// resources, e.g. the request count of a service as the monthly_requests of its load
+// balancer. Site is the Datadog site, e.g. datadoghq.eu, and defaults to datadoghq.com.
+// Lookback is the period the queries are run over and defaults to 720h. If APIKey or AppKey
+// are not set the DD_API_KEY and DD_APP_KEY environment variables are used.
+type DatadogUsage struct {
+	Site     string               `yaml:"site,omitempty"`
+	APIKey   string               `yaml:"api_key,omitempty"`
+	AppKey   string               `yaml:"app_key,omitempty"`
+	Lookback time.Duration        `yaml:"lookback,omitempty"`
+	Queries  []*DatadogUsageQuery `yaml:"queries"`
+}
+
+// DatadogUsageQuery is a Datadog metric query for a usage key of a resource type. The query
+// is a Go template that's run for each resource of the type, with the resource's Address,
+// Type and Name, and the attr function to get its Terraform attributes, e.g.
+// sum:trace.http.request.hits{service:{{ attr "name" }}}.as_count(). The points of each series
+// are reduced with the Reducer, which can be sum, avg, min, max or last and defaults to avg,
+// and the series are summed.
+type DatadogUsageQuery struct {
+	ResourceType string `yaml:"resource_type"`
+	UsageKey     string `yaml:"usage_key"`
+	Query        string `yaml:"query"`
+	Reducer      string `yaml:"reducer,omitempty"`
+}
@@ -0,0 +1,14 @@
+package config
+
+// Discount is a percentage off the list prices of the cost components that match all of
+// its set fields, e.g. a negotiated enterprise discount for a cloud provider or service.
+// Fields that aren't set match everything.
+type Discount struct {
+	// Provider is the Terraform provider of the resource, e.g. aws, google or azurerm
+	Provider string `yaml:"provider,omitempty"`
+	// Service is the service of the price, e.g. AmazonEC2
+	Service      string  `yaml:"service,omitempty"`
+	Region       string  `yaml:"region,omitempty"`
+	ResourceType string  `yaml:"resource_type,omitempty"`
+	Percent      float64 `yaml:"percent"`
+}
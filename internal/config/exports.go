@@ -0,0 +1,24 @@
+package config
+
+// Exports configures the time-series databases that the totals of each run are pushed to.
+type Exports struct {
+	InfluxDB   *InfluxDBExport   `yaml:"influxdb,omitempty"`
+	Timestream *TimestreamExport `yaml:"timestream,omitempty"`
+}
+
+// InfluxDBExport writes the run totals to an InfluxDB 2.x bucket using the line protocol.
+// If Token is not set the INFRACOST_INFLUXDB_TOKEN environment variable is used.
+type InfluxDBExport struct {
+	URL    string `yaml:"url"`
+	Org    string `yaml:"org"`
+	Bucket string `yaml:"bucket"`
+	Token  string `yaml:"token,omitempty"`
+}
+
+// TimestreamExport writes the run totals to an AWS Timestream table. The AWS credentials
+// are read from the standard AWS_* environment variables.
+type TimestreamExport struct {
+	Region   string `yaml:"region"`
+	Database string `yaml:"database"`
+	Table    string `yaml:"table"`
+}
@@ -0,0 +1,16 @@
+package config
+
+// KubernetesUsage configures the Prometheus server with the kube-state-metrics of the
+// clusters, which is queried for the average node count and CPU utilization of the node
+// pools of EKS, GKE and AKS clusters. The node pools are priced with the average node count
+// instead of their declared sizes, or with the node count that gets the average CPU
+// requests to TargetUtilization if it's set, e.g. 0.8. Lookback is how far back the
+// averages are taken over and defaults to 30d. The node pool labels must be allowed with
+// kube-state-metrics' --metric-labels-allowlist. If Token is not set the
+// INFRACOST_KUBERNETES_USAGE_TOKEN environment variable is used.
+type KubernetesUsage struct {
+	URL               string  `yaml:"url"`
+	Token             string  `yaml:"token,omitempty"`
+	Lookback          string  `yaml:"lookback,omitempty"`
+	TargetUtilization float64 `yaml:"target_utilization,omitempty"`
+}
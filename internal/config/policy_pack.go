@@ -0,0 +1,8 @@
+package config
+
+// PolicyPack references a versioned policy pack that is installed when Infracost runs.
+// The source is either a git URL, e.g. git::https://github.com/org/packs.git//finops?ref=v1.2.0,
+// or an OCI artifact, e.g. oci://ghcr.io/org/finops-pack:1.2.0.
+type PolicyPack struct {
+	Source string `yaml:"source"`
+}
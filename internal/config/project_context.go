@@ -54,12 +54,20 @@ func DetectProjectMetadata(ctx *ProjectContext) *schema.ProjectMetadata {
 		vcsSubPath = gitSubPath(ctx.ProjectConfig.Path)
 	}
 
+	detectionReason, _ := ctx.contextVals["detectionReason"].(string)
+
+	path := ctx.ProjectConfig.Path
+	if ctx.ProjectConfig.SourcePath != "" {
+		path = ctx.ProjectConfig.SourcePath
+	}
+
 	return &schema.ProjectMetadata{
-		Path:               ctx.ProjectConfig.Path,
+		Path:               path,
 		VCSRepoURL:         vcsRepoURL,
 		VCSSubPath:         vcsSubPath,
 		VCSPullRequestURL:  vcsPullRequestURL,
 		TerraformWorkspace: terraformWorkspace,
+		DetectionReason:    detectionReason,
 	}
 }
 
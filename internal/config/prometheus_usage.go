@@ -0,0 +1,20 @@
+package config
+
+// PrometheusUsage configures the PromQL queries whose results are used as the usage of
+// resources, e.g. the request rate of an API as the monthly_requests of its API Gateway.
+// If Token is not set the INFRACOST_PROMETHEUS_TOKEN environment variable is used.
+type PrometheusUsage struct {
+	URL     string                  `yaml:"url"`
+	Token   string                  `yaml:"token,omitempty"`
+	Queries []*PrometheusUsageQuery `yaml:"queries"`
+}
+
+// PrometheusUsageQuery is a PromQL query for a usage key of a resource type. The query is a
+// Go template that's run for each resource of the type, with the resource's Address, Type
+// and Name, and the attr function to get its Terraform attributes, e.g.
+// sum(increase(api_requests_total{api="{{ attr "name" }}"}[30d])).
+type PrometheusUsageQuery struct {
+	ResourceType string `yaml:"resource_type"`
+	UsageKey     string `yaml:"usage_key"`
+	Query        string `yaml:"query"`
+}
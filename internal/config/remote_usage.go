@@ -0,0 +1,11 @@
+package config
+
+// RemoteUsage configures an HTTP service that's asked for the usage of each resource during a
+// run, so usage that comes from internal telemetry doesn't need to be committed in usage
+// files. If ResourceTypes is empty the service is asked about all resources. If Token is not
+// set the INFRACOST_REMOTE_USAGE_TOKEN environment variable is used.
+type RemoteUsage struct {
+	URL           string   `yaml:"url"`
+	Token         string   `yaml:"token,omitempty"`
+	ResourceTypes []string `yaml:"resource_types,omitempty"`
+}
@@ -27,6 +27,16 @@ func userConfigDir() string {
 	return dir
 }
 
+// UserCacheDir returns the directory used to cache data between runs
+func UserCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(userConfigDir(), ".cache")
+	}
+
+	return filepath.Join(dir, "infracost")
+}
+
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
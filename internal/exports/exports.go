@@ -0,0 +1,201 @@
+package exports
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// Point is a single time-series data point
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]float64
+	Time        time.Time
+}
+
+type Exporter interface {
+	Name() string
+	Export(points []Point) error
+}
+
+// Export pushes the totals and per-service costs of the run to the configured exporters
+// using the HTTP client. All exporters are run even if one fails and the errors are combined.
+func Export(cfg *config.Exports, httpClient *http.Client, projects []*schema.Project, t time.Time) error {
+	exporters := newExporters(cfg, httpClient)
+	if len(exporters) == 0 {
+		return nil
+	}
+
+	points := BuildPoints(projects, t)
+
+	var errMsgs []string
+
+	for _, e := range exporters {
+		log.Debugf("Exporting %d points to %s", len(points), e.Name())
+
+		err := e.Export(points)
+		if err != nil {
+			errMsgs = append(errMsgs, errors.Wrapf(err, "Error exporting to %s", e.Name()).Error())
+		}
+	}
+
+	if len(errMsgs) > 0 {
+		return errors.New(strings.Join(errMsgs, "\n"))
+	}
+
+	return nil
+}
+
+func newExporters(cfg *config.Exports, httpClient *http.Client) []Exporter {
+	exporters := make([]Exporter, 0)
+
+	if cfg == nil {
+		return exporters
+	}
+
+	if cfg.InfluxDB != nil {
+		exporters = append(exporters, NewInfluxDBExporter(cfg.InfluxDB, httpClient))
+	}
+
+	if cfg.Timestream != nil {
+		exporters = append(exporters, NewTimestreamExporter(cfg.Timestream, httpClient))
+	}
+
+	return exporters
+}
+
+// BuildPoints returns the overall totals, the totals for each project and the cost of
+// each service in each project. The service is taken from the cost component's product
+// filter, e.g. AmazonEC2.
+func BuildPoints(projects []*schema.Project, t time.Time) []Point {
+	points := make([]Point, 0)
+
+	totalHourlyCost := decimal.Zero
+	totalMonthlyCost := decimal.Zero
+
+	for _, project := range projects {
+		hourlyCost := decimal.Zero
+		monthlyCost := decimal.Zero
+		resourceCount := 0
+
+		for _, r := range project.Resources {
+			if r.IsSkipped {
+				continue
+			}
+
+			resourceCount++
+
+			if r.HourlyCost != nil {
+				hourlyCost = hourlyCost.Add(*r.HourlyCost)
+			}
+			if r.MonthlyCost != nil {
+				monthlyCost = monthlyCost.Add(*r.MonthlyCost)
+			}
+		}
+
+		totalHourlyCost = totalHourlyCost.Add(hourlyCost)
+		totalMonthlyCost = totalMonthlyCost.Add(monthlyCost)
+
+		points = append(points, Point{
+			Measurement: "infracost_project",
+			Tags:        map[string]string{"project": project.Name},
+			Fields: map[string]float64{
+				"hourly_cost":    toFloat(hourlyCost),
+				"monthly_cost":   toFloat(monthlyCost),
+				"resource_count": float64(resourceCount),
+			},
+			Time: t,
+		})
+
+		serviceCosts := projectServiceCosts(project)
+
+		services := make([]string, 0, len(serviceCosts))
+		for s := range serviceCosts {
+			services = append(services, s)
+		}
+		sort.Strings(services)
+
+		for _, s := range services {
+			points = append(points, Point{
+				Measurement: "infracost_service",
+				Tags:        map[string]string{"project": project.Name, "service": s},
+				Fields:      map[string]float64{"monthly_cost": toFloat(serviceCosts[s])},
+				Time:        t,
+			})
+		}
+	}
+
+	points = append(points, Point{
+		Measurement: "infracost_total",
+		Tags:        map[string]string{},
+		Fields: map[string]float64{
+			"hourly_cost":  toFloat(totalHourlyCost),
+			"monthly_cost": toFloat(totalMonthlyCost),
+		},
+		Time: t,
+	})
+
+	return points
+}
+
+func projectServiceCosts(project *schema.Project) map[string]decimal.Decimal {
+	costs := make(map[string]decimal.Decimal)
+
+	for _, r := range project.Resources {
+		if r.IsSkipped {
+			continue
+		}
+
+		resources := append([]*schema.Resource{r}, r.FlattenedSubResources()...)
+
+		for _, res := range resources {
+			for _, c := range res.CostComponents {
+				if c.MonthlyCost == nil {
+					continue
+				}
+
+				service := "unknown"
+				if c.ProductFilter != nil && c.ProductFilter.Service != nil {
+					service = *c.ProductFilter.Service
+				}
+
+				costs[service] = costs[service].Add(*c.MonthlyCost)
+			}
+		}
+	}
+
+	return costs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedFieldKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func toFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
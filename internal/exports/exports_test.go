@@ -0,0 +1,134 @@
+package exports
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func testProjects() []*schema.Project {
+	project := schema.NewProject("infracost/prod", &schema.ProjectMetadata{})
+	project.Resources = []*schema.Resource{
+		{
+			Name:        "aws_instance.web",
+			HourlyCost:  decimalPtr(decimal.NewFromFloat(0.1)),
+			MonthlyCost: decimalPtr(decimal.NewFromInt(73)),
+			CostComponents: []*schema.CostComponent{
+				{
+					ProductFilter: &schema.ProductFilter{Service: strPtr("AmazonEC2")},
+					MonthlyCost:   decimalPtr(decimal.NewFromInt(70)),
+				},
+			},
+			SubResources: []*schema.Resource{
+				{
+					Name: "root_block_device",
+					CostComponents: []*schema.CostComponent{
+						{
+							ProductFilter: &schema.ProductFilter{Service: strPtr("AmazonEC2")},
+							MonthlyCost:   decimalPtr(decimal.NewFromInt(3)),
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "aws_foo.bar",
+			IsSkipped: true,
+		},
+	}
+
+	return []*schema.Project{project}
+}
+
+func TestBuildPoints(t *testing.T) {
+	ts := time.Unix(1600000000, 0)
+	points := BuildPoints(testProjects(), ts)
+
+	require.Len(t, points, 3)
+	assert.Equal(t, "infracost_project", points[0].Measurement)
+	assert.Equal(t, float64(73), points[0].Fields["monthly_cost"])
+	assert.Equal(t, float64(1), points[0].Fields["resource_count"])
+	assert.Equal(t, "infracost_service", points[1].Measurement)
+	assert.Equal(t, "AmazonEC2", points[1].Tags["service"])
+	assert.Equal(t, float64(73), points[1].Fields["monthly_cost"])
+	assert.Equal(t, "infracost_total", points[2].Measurement)
+	assert.Equal(t, float64(73), points[2].Fields["monthly_cost"])
+}
+
+func TestToLineProtocol(t *testing.T) {
+	points := []Point{
+		{
+			Measurement: "infracost_service",
+			Tags:        map[string]string{"project": "my project", "service": "AmazonEC2"},
+			Fields:      map[string]float64{"monthly_cost": 12.5},
+			Time:        time.Unix(1600000000, 0),
+		},
+		{
+			Measurement: "infracost_total",
+			Fields:      map[string]float64{"monthly_cost": 20, "hourly_cost": 0.5},
+			Time:        time.Unix(1600000000, 0),
+		},
+	}
+
+	assert.Equal(t,
+		"infracost_service,project=my\\ project,service=AmazonEC2 monthly_cost=12.5 1600000000\n"+
+			"infracost_total hourly_cost=0.5,monthly_cost=20 1600000000\n",
+		ToLineProtocol(points),
+	)
+}
+
+func TestToTimestreamRecords(t *testing.T) {
+	points := []Point{
+		{
+			Measurement: "infracost_total",
+			Tags:        map[string]string{},
+			Fields:      map[string]float64{"monthly_cost": 20, "hourly_cost": 0.5},
+			Time:        time.Unix(1600000000, 0),
+		},
+	}
+
+	records := ToTimestreamRecords(points)
+	require.Len(t, records, 2)
+	assert.Equal(t, "infracost_total.hourly_cost", records[0].MeasureName)
+	assert.Equal(t, "0.5", records[0].MeasureValue)
+	assert.Equal(t, "1600000000000", records[0].Time)
+	assert.Equal(t, []timestreamDimension{{Name: "source", Value: "infracost"}}, records[0].Dimensions)
+}
+
+func TestInfluxDBExporter(t *testing.T) {
+	var body string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	points := []Point{{Measurement: "infracost_total", Fields: map[string]float64{"monthly_cost": 1}, Time: time.Unix(1, 0)}}
+
+	// The server's certificate is only trusted by its own client, which stands in for the
+	// client with the configured CA certificates
+	e := NewInfluxDBExporter(&config.InfluxDBExport{URL: srv.URL, Token: "secret"}, srv.Client())
+	require.NoError(t, e.Export(points))
+	assert.Equal(t, ToLineProtocol(points), body)
+
+	e = NewInfluxDBExporter(&config.InfluxDBExport{URL: srv.URL, Token: "secret"}, &http.Client{})
+	assert.Error(t, e.Export(points))
+}
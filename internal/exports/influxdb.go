@@ -0,0 +1,96 @@
+package exports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/pkg/errors"
+)
+
+var influxDBEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+var influxDBMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+
+type InfluxDBExporter struct {
+	cfg        *config.InfluxDBExport
+	httpClient *http.Client
+}
+
+func NewInfluxDBExporter(cfg *config.InfluxDBExport, httpClient *http.Client) *InfluxDBExporter {
+	return &InfluxDBExporter{cfg: cfg, httpClient: httpClient}
+}
+
+func (e *InfluxDBExporter) Name() string {
+	return "InfluxDB"
+}
+
+func (e *InfluxDBExporter) Export(points []Point) error {
+	token := e.cfg.Token
+	if token == "" {
+		token = os.Getenv("INFRACOST_INFLUXDB_TOKEN")
+	}
+
+	q := url.Values{}
+	q.Set("org", e.cfg.Org)
+	q.Set("bucket", e.cfg.Bucket)
+	q.Set("precision", "s")
+
+	endpoint := fmt.Sprintf("%s/api/v2/write?%s", strings.TrimSuffix(e.cfg.URL, "/"), q.Encode())
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBufferString(ToLineProtocol(points)))
+	if err != nil {
+		return errors.Wrap(err, "Error generating request")
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Token %s", token))
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("Invalid response %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// ToLineProtocol formats the points using the InfluxDB line protocol with second precision
+func ToLineProtocol(points []Point) string {
+	var b strings.Builder
+
+	for _, p := range points {
+		b.WriteString(influxDBMeasurementEscaper.Replace(p.Measurement))
+
+		for _, k := range sortedKeys(p.Tags) {
+			if p.Tags[k] == "" {
+				continue
+			}
+			fmt.Fprintf(&b, ",%s=%s", influxDBEscaper.Replace(k), influxDBEscaper.Replace(p.Tags[k]))
+		}
+
+		for i, k := range sortedFieldKeys(p.Fields) {
+			sep := ","
+			if i == 0 {
+				sep = " "
+			}
+			fmt.Fprintf(&b, "%s%s=%s", sep, influxDBEscaper.Replace(k), strconv.FormatFloat(p.Fields[k], 'f', -1, 64))
+		}
+
+		fmt.Fprintf(&b, " %d\n", p.Time.Unix())
+	}
+
+	return b.String()
+}
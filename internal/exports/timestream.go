@@ -0,0 +1,168 @@
+package exports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/pkg/errors"
+)
+
+// Timestream allows at most 100 records per WriteRecords request
+var timestreamMaxRecords = 100
+
+type timestreamDimension struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type timestreamRecord struct {
+	Dimensions       []timestreamDimension `json:"Dimensions"`
+	MeasureName      string                `json:"MeasureName"`
+	MeasureValue     string                `json:"MeasureValue"`
+	MeasureValueType string                `json:"MeasureValueType"`
+	Time             string                `json:"Time"`
+	TimeUnit         string                `json:"TimeUnit"`
+}
+
+type TimestreamExporter struct {
+	cfg        *config.TimestreamExport
+	httpClient *http.Client
+}
+
+func NewTimestreamExporter(cfg *config.TimestreamExport, httpClient *http.Client) *TimestreamExporter {
+	return &TimestreamExporter{cfg: cfg, httpClient: httpClient}
+}
+
+func (e *TimestreamExporter) Name() string {
+	return "Timestream"
+}
+
+func (e *TimestreamExporter) Export(points []Point) error {
+	creds := awsauth.CredentialsFromEnv()
+	if !creds.IsSet() {
+		return errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	endpoint, err := e.describeEndpoint(creds)
+	if err != nil {
+		return err
+	}
+
+	records := ToTimestreamRecords(points)
+
+	for i := 0; i < len(records); i += timestreamMaxRecords {
+		end := i + timestreamMaxRecords
+		if end > len(records) {
+			end = len(records)
+		}
+
+		_, err = e.call(creds, endpoint, "WriteRecords", map[string]interface{}{
+			"DatabaseName": e.cfg.Database,
+			"TableName":    e.cfg.Table,
+			"Records":      records[i:end],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeEndpoint uses Timestream's endpoint discovery to find the ingestion endpoint
+func (e *TimestreamExporter) describeEndpoint(creds awsauth.Credentials) (string, error) {
+	body, err := e.call(creds, fmt.Sprintf("ingest.timestream.%s.amazonaws.com", e.cfg.Region), "DescribeEndpoints", map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Endpoints []struct {
+			Address string `json:"Address"`
+		} `json:"Endpoints"`
+	}
+
+	err = json.Unmarshal(body, &resp)
+	if err != nil || len(resp.Endpoints) == 0 || resp.Endpoints[0].Address == "" {
+		return "", errors.New("Could not discover the Timestream ingestion endpoint")
+	}
+
+	return resp.Endpoints[0].Address, nil
+}
+
+func (e *TimestreamExporter) call(creds awsauth.Credentials, host string, action string, payload interface{}) ([]byte, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Error generating request body")
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("https://%s/", host), bytes.NewBuffer(reqBody))
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Error generating request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", fmt.Sprintf("Timestream_20181101.%s", action))
+
+	awsauth.SignRequest(req, reqBody, creds, e.cfg.Region, "timestream", time.Now().UTC())
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Error sending request")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Invalid response")
+	}
+
+	if resp.StatusCode >= 300 {
+		return respBody, errors.Errorf("Invalid response from %s %d: %s", action, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return respBody, nil
+}
+
+// ToTimestreamRecords converts each field of the points to a Timestream record. The measure
+// name is the point's measurement and the field name, e.g. infracost_project.monthly_cost.
+func ToTimestreamRecords(points []Point) []timestreamRecord {
+	records := make([]timestreamRecord, 0, len(points))
+
+	for _, p := range points {
+		dims := make([]timestreamDimension, 0, len(p.Tags))
+		for _, k := range sortedKeys(p.Tags) {
+			// Timestream does not allow empty dimension values
+			if p.Tags[k] == "" {
+				continue
+			}
+			dims = append(dims, timestreamDimension{Name: k, Value: p.Tags[k]})
+		}
+
+		// Timestream requires at least one dimension
+		if len(dims) == 0 {
+			dims = append(dims, timestreamDimension{Name: "source", Value: "infracost"})
+		}
+
+		for _, k := range sortedFieldKeys(p.Fields) {
+			records = append(records, timestreamRecord{
+				Dimensions:       dims,
+				MeasureName:      fmt.Sprintf("%s.%s", p.Measurement, k),
+				MeasureValue:     strconv.FormatFloat(p.Fields[k], 'f', -1, 64),
+				MeasureValueType: "DOUBLE",
+				Time:             strconv.FormatInt(p.Time.UnixNano()/int64(time.Millisecond), 10),
+				TimeUnit:         "MILLISECONDS",
+			})
+		}
+	}
+
+	return records
+}
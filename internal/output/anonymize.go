@@ -0,0 +1,237 @@
+package output
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Resource metadata keys that don't identify the user so are left as they are
+var anonymizeSafeMetadataKeys = []string{"region", "moduleVersion", "moduleVersionConstraint", "countEstimate", "countEstimateSource"}
+
+// anonymizeKeyLength is the length in bytes of the random keys for the pseudonyms
+const anonymizeKeyLength = 32
+
+// anonymizer replaces values with pseudonyms that are an HMAC of the value, so they can't
+// be reversed by hashing a dictionary of likely values without the key.
+type anonymizer struct {
+	key []byte
+}
+
+// Anonymize returns a copy of the output with the resource names, tags, metadata and
+// project identifiers replaced with pseudonyms. The pseudonyms are derived from an HMAC of
+// the original value with the key, so the same value is always replaced with the same
+// pseudonym, even across multiple files. If the key is empty a random key is used, so the
+// pseudonyms are only consistent within the output. The resource types, structure and costs
+// are unchanged.
+func Anonymize(out Root, key []byte) (Root, error) {
+	if len(key) == 0 {
+		key = make([]byte, anonymizeKeyLength)
+		if _, err := rand.Read(key); err != nil {
+			return out, errors.Wrap(err, "Error generating anonymize key")
+		}
+	}
+
+	a := anonymizer{key: key}
+	anon := out
+
+	if anon.RunID != "" {
+		anon.RunID = a.pseudonym("run", anon.RunID)
+	}
+
+	anon.Projects = make([]Project, 0, len(out.Projects))
+	for _, p := range out.Projects {
+		anon.Projects = append(anon.Projects, a.anonymizeProject(p))
+	}
+
+	if out.Environments != nil {
+		anon.Environments = make([]Environment, 0, len(out.Environments))
+		for _, e := range out.Environments {
+			e.Name = a.pseudonym("environment", e.Name)
+
+			projects := make([]string, 0, len(e.Projects))
+			for _, p := range e.Projects {
+				projects = append(projects, a.pseudonym("project", p))
+			}
+			e.Projects = projects
+
+			anon.Environments = append(anon.Environments, e)
+		}
+	}
+
+	return anon, nil
+}
+
+func (a anonymizer) anonymizeProject(p Project) Project {
+	p.Name = a.pseudonym("project", p.Name)
+
+	if p.Metadata != nil {
+		m := *p.Metadata
+		m.Path = a.pseudonymIfSet("path", m.Path)
+		m.VCSRepoURL = a.pseudonymIfSet("repo", m.VCSRepoURL)
+		m.VCSSubPath = a.pseudonymIfSet("path", m.VCSSubPath)
+		m.VCSPullRequestURL = a.pseudonymIfSet("pr", m.VCSPullRequestURL)
+		m.TerraformWorkspace = a.pseudonymIfSet("workspace", m.TerraformWorkspace)
+		m.DetectionReason = ""
+
+		m.TerraformVarFiles = a.pseudonymSlice("path", m.TerraformVarFiles)
+		m.TerraformVarNames = a.pseudonymSlice("var", m.TerraformVarNames)
+
+		p.Metadata = &m
+	}
+
+	p.PastBreakdown = a.anonymizeBreakdown(p.PastBreakdown)
+	p.Breakdown = a.anonymizeBreakdown(p.Breakdown)
+	p.Diff = a.anonymizeBreakdown(p.Diff)
+
+	return p
+}
+
+func (a anonymizer) anonymizeBreakdown(b *Breakdown) *Breakdown {
+	if b == nil {
+		return nil
+	}
+
+	anon := *b
+	anon.Resources = make([]Resource, 0, len(b.Resources))
+
+	for _, r := range b.Resources {
+		anon.Resources = append(anon.Resources, a.anonymizeResource(r, true))
+	}
+
+	sortResources(anon.Resources, "")
+
+	return &anon
+}
+
+func (a anonymizer) anonymizeResource(r Resource, isTopLevel bool) Resource {
+	// Sub-resource names are set by Infracost, e.g. root_block_device, so only the
+	// top-level resource names which come from the user's code are changed
+	if isTopLevel {
+		r.Name = a.anonymizeAddress(r.Name)
+	}
+
+	if r.Tags != nil {
+		tags := make(map[string]string, len(r.Tags))
+		for k, v := range r.Tags {
+			tags[k] = a.pseudonym("tag", v)
+		}
+		r.Tags = tags
+	}
+
+	if r.Metadata != nil {
+		metadata := make(map[string]string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			if contains(anonymizeSafeMetadataKeys, k) {
+				metadata[k] = v
+			} else {
+				metadata[k] = a.pseudonym(k, v)
+			}
+		}
+		r.Metadata = metadata
+	}
+
+	subresources := make([]Resource, 0, len(r.SubResources))
+	for _, s := range r.SubResources {
+		subresources = append(subresources, a.anonymizeResource(s, false))
+	}
+	r.SubResources = subresources
+
+	return r
+}
+
+// anonymizeAddress replaces the module and resource names in a Terraform address while
+// keeping the resource type and numeric indexes, e.g. module.vpc.aws_nat_gateway.nat[0]
+// becomes module.module_1a2b3c4d.aws_nat_gateway.name_5e6f7a8b[0].
+func (a anonymizer) anonymizeAddress(addr string) string {
+	parts := splitAddress(addr)
+
+	for i := 0; i < len(parts); i++ {
+		switch {
+		case parts[i] == "module" && i+1 < len(parts):
+			parts[i+1] = a.anonymizeAddressLabel("module", parts[i+1])
+			i++
+		case parts[i] == "data" && i+2 < len(parts):
+			parts[i+2] = a.anonymizeAddressLabel("name", parts[i+2])
+			i += 2
+		case i+1 < len(parts):
+			parts[i+1] = a.anonymizeAddressLabel("name", parts[i+1])
+			i++
+		default:
+			parts[i] = a.anonymizeAddressLabel("name", parts[i])
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// anonymizeAddressLabel replaces the name and any string index of an address label
+func (a anonymizer) anonymizeAddressLabel(kind string, label string) string {
+	name := label
+	index := ""
+
+	if i := strings.Index(label, "["); i != -1 {
+		name = label[:i]
+		index = label[i:]
+	}
+
+	if strings.HasPrefix(index, `["`) && strings.HasSuffix(index, `"]`) {
+		index = `["` + a.pseudonym("key", index[2:len(index)-2]) + `"]`
+	}
+
+	return a.pseudonym(kind, name) + index
+}
+
+// splitAddress splits the address on dots that are not inside an index
+func splitAddress(addr string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	start := 0
+
+	for i, c := range addr {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, addr[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, addr[start:])
+}
+
+func (a anonymizer) pseudonym(kind string, v string) string {
+	h := hmac.New(sha256.New, a.key)
+	h.Write([]byte(v))
+	return kind + "_" + hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+func (a anonymizer) pseudonymIfSet(kind string, v string) string {
+	if v == "" {
+		return ""
+	}
+
+	return a.pseudonym(kind, v)
+}
+
+func (a anonymizer) pseudonymSlice(kind string, vals []string) []string {
+	if vals == nil {
+		return nil
+	}
+
+	anon := make([]string, 0, len(vals))
+	for _, v := range vals {
+		anon = append(anon, a.pseudonym(kind, v))
+	}
+
+	return anon
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizeAddress(t *testing.T) {
+	a := anonymizer{key: []byte("key")}
+	pseudonym := a.pseudonym
+
+	tests := []struct {
+		addr     string
+		expected string
+	}{
+		{"aws_instance.web", "aws_instance." + pseudonym("name", "web")},
+		{"aws_instance.web[0]", "aws_instance." + pseudonym("name", "web") + "[0]"},
+		{"aws_instance.web[\"a.b\"]", "aws_instance." + pseudonym("name", "web") + "[\"" + pseudonym("key", "a.b") + "\"]"},
+		{"module.vpc.aws_nat_gateway.nat[0]", "module." + pseudonym("module", "vpc") + ".aws_nat_gateway." + pseudonym("name", "nat") + "[0]"},
+		{"data.aws_ami.ubuntu", "data.aws_ami." + pseudonym("name", "ubuntu")},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, a.anonymizeAddress(tt.addr), tt.addr)
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	cost := decimal.NewFromInt(42)
+
+	out := Root{
+		Projects: []Project{
+			{
+				Name:     "acme/infra/prod",
+				Metadata: &schema.ProjectMetadata{Path: "prod", VCSRepoURL: "https://github.com/acme/infra"},
+				Breakdown: &Breakdown{
+					TotalMonthlyCost: &cost,
+					Resources: []Resource{
+						{
+							Name:        "aws_instance.web",
+							Tags:        map[string]string{"Owner": "alice"},
+							Metadata:    map[string]string{"region": "us-east-1", "account": "123456789012"},
+							MonthlyCost: &cost,
+							SubResources: []Resource{
+								{Name: "root_block_device"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Environments: []Environment{
+			{Name: "production", Projects: []string{"acme/infra/prod"}},
+		},
+	}
+
+	key := []byte("key")
+	pseudonym := anonymizer{key: key}.pseudonym
+
+	anon, err := Anonymize(out, key)
+	require.NoError(t, err)
+
+	p := anon.Projects[0]
+	assert.Equal(t, pseudonym("project", "acme/infra/prod"), p.Name)
+	assert.Equal(t, []string{p.Name}, anon.Environments[0].Projects)
+	assert.Equal(t, pseudonym("path", "prod"), p.Metadata.Path)
+	assert.Equal(t, "", anon.Projects[0].Metadata.VCSSubPath)
+
+	require.Len(t, p.Breakdown.Resources, 1)
+	r := p.Breakdown.Resources[0]
+	assert.Equal(t, "aws_instance."+pseudonym("name", "web"), r.Name)
+	assert.Equal(t, pseudonym("tag", "alice"), r.Tags["Owner"])
+	assert.Equal(t, "us-east-1", r.Metadata["region"])
+	assert.NotContains(t, r.Metadata["account"], "123456789012")
+	assert.Equal(t, "root_block_device", r.SubResources[0].Name)
+	assert.True(t, cost.Equal(*r.MonthlyCost))
+	assert.True(t, cost.Equal(*p.Breakdown.TotalMonthlyCost))
+
+	// The original output is unchanged and the pseudonyms are deterministic for the key
+	assert.Equal(t, "aws_instance.web", out.Projects[0].Breakdown.Resources[0].Name)
+	assert.Equal(t, "alice", out.Projects[0].Breakdown.Resources[0].Tags["Owner"])
+	again, err := Anonymize(out, key)
+	require.NoError(t, err)
+	assert.Equal(t, anon, again)
+
+	// The pseudonyms aren't a plain hash of the value, and without a key they're random
+	sum := sha256.Sum256([]byte("web"))
+	assert.NotEqual(t, "aws_instance.name_"+hex.EncodeToString(sum[:])[:8], r.Name)
+
+	random, err := Anonymize(out, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, r.Name, random.Projects[0].Breakdown.Resources[0].Name)
+}
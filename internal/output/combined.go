@@ -2,9 +2,12 @@ package output
 
 import (
 	"encoding/json"
+	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 )
 
 type ReportInput struct {
@@ -18,18 +21,63 @@ func Load(data []byte) (Root, error) {
 	return out, err
 }
 
-func Combine(inputs []ReportInput, opts Options) Root {
+// Combine merges the inputs into a single output. A project that is in more than one input,
+// e.g. if the same file is passed twice, is only included once so its costs aren't counted
+// twice. If the duplicate projects have different costs then an error is returned since
+// there's no way to tell which one is correct.
+func Combine(inputs []ReportInput, opts Options) (Root, error) {
 	var combined Root
 
 	var totalHourlyCost *decimal.Decimal
 	var totalMonthlyCost *decimal.Decimal
 
 	projects := make([]Project, 0)
+	environments := make([]Environment, 0)
 	summaries := make([]*Summary, 0, len(inputs))
 
+	// The totals of the duplicate projects are removed from the combined totals
+	var duplicateHourlyCost *decimal.Decimal
+	var duplicateMonthlyCost *decimal.Decimal
+
+	seen := make(map[string]int)
+	hasDuplicates := false
+
 	for _, input := range inputs {
+		currency := input.Root.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+
+		if combined.Currency == "" {
+			combined.Currency = currency
+		} else if combined.Currency != currency {
+			return combined, fmt.Errorf("Cannot combine outputs in different currencies, %s is in %s and the other files are in %s", input.Metadata["filename"], currency, combined.Currency)
+		}
+
+		for _, project := range input.Root.Projects {
+			key := projectKey(project)
+
+			if i, ok := seen[key]; ok {
+				if !sameProjectCosts(projects[i], project) {
+					return combined, fmt.Errorf("Project %s is included more than once with different costs, in %s and %s. Remove one of the files or use a different path for each project so their costs aren't counted twice", project.Name, inputFilename(inputs, projects[i]), input.Metadata["filename"])
+				}
+
+				log.Warnf("Project %s is included more than once, it will only be counted once", project.Name)
+				hasDuplicates = true
+
+				if project.Breakdown != nil {
+					duplicateHourlyCost = addDecimalPtrs(duplicateHourlyCost, project.Breakdown.TotalHourlyCost)
+					duplicateMonthlyCost = addDecimalPtrs(duplicateMonthlyCost, project.Breakdown.TotalMonthlyCost)
+				}
+
+				continue
+			}
+
+			seen[key] = len(projects)
+			projects = append(projects, project)
+		}
 
-		projects = append(projects, input.Root.Projects...)
+		environments = append(environments, input.Root.Environments...)
 
 		summaries = append(summaries, input.Root.Summary)
 
@@ -49,12 +97,90 @@ func Combine(inputs []ReportInput, opts Options) Root {
 		}
 	}
 
+	if hasDuplicates {
+		totalHourlyCost = subtractDecimalPtrs(totalHourlyCost, duplicateHourlyCost)
+		totalMonthlyCost = subtractDecimalPtrs(totalMonthlyCost, duplicateMonthlyCost)
+
+		// The summaries of the inputs include the duplicates so use the project summaries instead
+		projectSummaries := make([]*Summary, 0, len(projects))
+		for _, p := range projects {
+			if p.Summary == nil {
+				projectSummaries = nil
+				break
+			}
+			projectSummaries = append(projectSummaries, p.Summary)
+		}
+
+		if projectSummaries != nil {
+			summaries = projectSummaries
+		}
+	}
+
 	combined.Version = outputVersion
 	combined.Projects = projects
+	if len(environments) > 0 {
+		combined.Environments = environments
+	}
 	combined.TotalHourlyCost = totalHourlyCost
 	combined.TotalMonthlyCost = totalMonthlyCost
 	combined.TimeGenerated = time.Now()
 	combined.Summary = MergeSummaries(summaries)
 
-	return combined
+	return combined, nil
+}
+
+// projectKey identifies a project by its name, path and workspace
+func projectKey(p Project) string {
+	if p.Metadata == nil {
+		return p.Name
+	}
+
+	return fmt.Sprintf("%s|%s|%s", p.Name, filepath.Clean(p.Metadata.Path), p.Metadata.TerraformWorkspace)
+}
+
+func sameProjectCosts(a Project, b Project) bool {
+	totals := func(p Project) []*decimal.Decimal {
+		t := make([]*decimal.Decimal, 0, 3)
+		for _, b := range []*Breakdown{p.PastBreakdown, p.Breakdown, p.Diff} {
+			if b == nil {
+				t = append(t, nil)
+			} else {
+				t = append(t, b.TotalMonthlyCost)
+			}
+		}
+		return t
+	}
+
+	ta := totals(a)
+	tb := totals(b)
+
+	for i := range ta {
+		if (ta[i] == nil) != (tb[i] == nil) || (ta[i] != nil && !ta[i].Equal(*tb[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func inputFilename(inputs []ReportInput, project Project) string {
+	key := projectKey(project)
+
+	for _, input := range inputs {
+		for _, p := range input.Root.Projects {
+			if projectKey(p) == key {
+				return input.Metadata["filename"]
+			}
+		}
+	}
+
+	return ""
+}
+
+func subtractDecimalPtrs(a *decimal.Decimal, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil || b == nil {
+		return a
+	}
+
+	return decimalPtr(a.Sub(*b))
 }
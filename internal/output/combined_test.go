@@ -0,0 +1,86 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCombineInput(filename string, projects ...Project) ReportInput {
+	total := decimal.Zero
+	for _, p := range projects {
+		total = total.Add(*p.Breakdown.TotalMonthlyCost)
+	}
+
+	return ReportInput{
+		Metadata: map[string]string{"filename": filename},
+		Root:     Root{Projects: projects, TotalMonthlyCost: &total},
+	}
+}
+
+func testCombineProject(name string, path string, monthlyCost int64) Project {
+	return Project{
+		Name:      name,
+		Metadata:  &schema.ProjectMetadata{Path: path},
+		Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(monthlyCost))},
+		Summary:   &Summary{},
+	}
+}
+
+func TestCombine(t *testing.T) {
+	inputs := []ReportInput{
+		testCombineInput("prod.json", testCombineProject("prod", "aws/prod", 100)),
+		testCombineInput("dev.json", testCombineProject("dev", "aws/dev", 10)),
+	}
+
+	combined, err := Combine(inputs, Options{})
+	require.NoError(t, err)
+	assert.Len(t, combined.Projects, 2)
+	assert.Equal(t, "110", combined.TotalMonthlyCost.String())
+}
+
+func TestCombineDuplicateProjects(t *testing.T) {
+	inputs := []ReportInput{
+		testCombineInput("prod.json", testCombineProject("prod", "aws/prod", 100)),
+		testCombineInput("all.json", testCombineProject("prod", "aws/prod/", 100), testCombineProject("dev", "aws/dev", 10)),
+	}
+
+	combined, err := Combine(inputs, Options{})
+	require.NoError(t, err)
+	require.Len(t, combined.Projects, 2)
+	assert.Equal(t, "prod", combined.Projects[0].Name)
+	assert.Equal(t, "dev", combined.Projects[1].Name)
+	assert.Equal(t, "110", combined.TotalMonthlyCost.String())
+}
+
+func TestCombineConflictingDuplicateProjects(t *testing.T) {
+	inputs := []ReportInput{
+		testCombineInput("prod.json", testCombineProject("prod", "aws/prod", 100)),
+		testCombineInput("prod-new.json", testCombineProject("prod", "aws/prod", 150)),
+	}
+
+	_, err := Combine(inputs, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Project prod is included more than once with different costs, in prod.json and prod-new.json")
+}
+
+func TestCombineDifferentCurrencies(t *testing.T) {
+	eur := testCombineInput("eur.json", testCombineProject("prod", "aws/prod", 100))
+	eur.Root.Currency = "EUR"
+
+	inputs := []ReportInput{
+		eur,
+		testCombineInput("usd.json", testCombineProject("dev", "aws/dev", 10)),
+	}
+
+	_, err := Combine(inputs, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usd.json is in USD and the other files are in EUR")
+
+	combined, err := Combine(inputs[:1], Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", combined.Currency)
+}
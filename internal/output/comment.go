@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ToCommentSummary returns the Markdown summary of the monthly cost change that is posted
+// as a pull request comment.
+func ToCommentSummary(out Root) string {
+	pastTotal := decimal.Zero
+	total := decimal.Zero
+
+	for _, p := range out.Projects {
+		if p.PastBreakdown != nil && p.PastBreakdown.TotalMonthlyCost != nil {
+			pastTotal = pastTotal.Add(*p.PastBreakdown.TotalMonthlyCost)
+		}
+
+		if p.Breakdown != nil && p.Breakdown.TotalMonthlyCost != nil {
+			total = total.Add(*p.Breakdown.TotalMonthlyCost)
+		}
+	}
+
+	diff := total.Sub(pastTotal)
+
+	change := "increase"
+	emoji := "📈"
+	if diff.IsNegative() {
+		change = "decrease"
+		emoji = "📉"
+	}
+
+	abs := diff.Abs()
+	amount := formatCost2DP(out.Currency, &abs)
+	if percent := formatPercentChange(&pastTotal, &total); percent != "" {
+		amount += fmt.Sprintf(" (%s)", percent)
+	}
+
+	s := fmt.Sprintf("💰 Infracost estimate: **monthly cost will %s by %s** %s\n\n", change, amount, emoji)
+	s += fmt.Sprintf("Previous monthly cost: %s\n", formatCost2DP(out.Currency, &pastTotal))
+	s += fmt.Sprintf("New monthly cost: %s", formatCost2DP(out.Currency, &total))
+
+	return s
+}
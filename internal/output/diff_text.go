@@ -0,0 +1,121 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ToDiffText renders the cost diff in a unified diff format, similar to a Terraform plan,
+// so it can be piped into tools that review or colorize diffs. Each changed resource is a
+// hunk with the previous costs on - lines and the planned costs on + lines. The output is
+// never colored since the tools reading it add their own colors.
+func ToDiffText(out Root, opts Options) ([]byte, error) {
+	var b strings.Builder
+
+	for _, project := range out.Projects {
+		if project.Diff == nil || len(project.Diff.Resources) == 0 {
+			continue
+		}
+
+		label := project.Label(opts.DashboardEnabled)
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n", label, label)
+
+		var pastResources, resources []Resource
+		if project.PastBreakdown != nil {
+			pastResources = project.PastBreakdown.Resources
+		}
+		if project.Breakdown != nil {
+			resources = project.Breakdown.Resources
+		}
+
+		for _, diffResource := range project.Diff.Resources {
+			oldResource := findResourceByName(pastResources, diffResource.Name)
+			newResource := findResourceByName(resources, diffResource.Name)
+
+			fmt.Fprintf(&b, "@@ %s %s @@\n", diffResource.Name, diffTextCostChange(out.Currency, diffResource.MonthlyCost))
+			resourceToDiffText(out.Currency, &b, diffResource, oldResource, newResource, "")
+		}
+
+		var oldCost, newCost *decimal.Decimal
+		if project.PastBreakdown != nil {
+			oldCost = project.PastBreakdown.TotalMonthlyCost
+		}
+		if project.Breakdown != nil {
+			newCost = project.Breakdown.TotalMonthlyCost
+		}
+
+		fmt.Fprintf(&b, "@@ Monthly cost change %s @@\n", diffTextCostChange(out.Currency, project.Diff.TotalMonthlyCost))
+		fmt.Fprintf(&b, "-Total monthly cost  %s\n", formatCost(out.Currency, oldCost))
+		fmt.Fprintf(&b, "+Total monthly cost  %s\n", formatCost(out.Currency, newCost))
+	}
+
+	return []byte(b.String()), nil
+}
+
+func resourceToDiffText(currency string, b *strings.Builder, diffResource Resource, oldResource *Resource, newResource *Resource, indent string) {
+	if oldResource != nil {
+		fmt.Fprintf(b, "-%s%s%s\n", indent, diffResource.Name, diffTextCost(currency, oldResource.MonthlyCost))
+	}
+	if newResource != nil {
+		fmt.Fprintf(b, "+%s%s%s\n", indent, diffResource.Name, diffTextCost(currency, newResource.MonthlyCost))
+	}
+
+	for _, diffComponent := range diffResource.CostComponents {
+		var oldComponent, newComponent *CostComponent
+
+		if oldResource != nil {
+			oldComponent = findCostComponentByName(oldResource.CostComponents, diffComponent.Name)
+		}
+		if newResource != nil {
+			newComponent = findCostComponentByName(newResource.CostComponents, diffComponent.Name)
+		}
+
+		if oldComponent != nil {
+			fmt.Fprintf(b, "-%s    %s%s\n", indent, diffComponent.Name, diffTextComponentCost(currency, *oldComponent))
+		}
+		if newComponent != nil {
+			fmt.Fprintf(b, "+%s    %s%s\n", indent, diffComponent.Name, diffTextComponentCost(currency, *newComponent))
+		}
+	}
+
+	for _, diffSubResource := range diffResource.SubResources {
+		var oldSubResource, newSubResource *Resource
+
+		if oldResource != nil {
+			oldSubResource = findResourceByName(oldResource.SubResources, diffSubResource.Name)
+		}
+		if newResource != nil {
+			newSubResource = findResourceByName(newResource.SubResources, diffSubResource.Name)
+		}
+
+		resourceToDiffText(currency, b, diffSubResource, oldSubResource, newSubResource, indent+"    ")
+	}
+}
+
+func diffTextCost(currency string, d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return "  " + formatCost(currency, d)
+}
+
+// diffTextComponentCost returns the monthly cost of the component, or the price if the
+// cost depends on usage that isn't set
+func diffTextComponentCost(currency string, c CostComponent) string {
+	if c.MonthlyCost == nil {
+		return fmt.Sprintf("  %s per %s (depends on usage)", formatPrice(currency, c.Price), c.Unit)
+	}
+
+	return "  " + formatCost(currency, c.MonthlyCost)
+}
+
+func diffTextCostChange(currency string, d *decimal.Decimal) string {
+	if d == nil {
+		return "(depends on usage)"
+	}
+
+	return formatCostChange(currency, d)
+}
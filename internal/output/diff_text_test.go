@@ -0,0 +1,106 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDiffTextComponent(name string, monthlyCost int64) CostComponent {
+	return CostComponent{
+		Name:        name,
+		Unit:        "hours",
+		Price:       decimal.NewFromFloat(0.01),
+		MonthlyCost: decimalPtr(decimal.NewFromInt(monthlyCost)),
+	}
+}
+
+func TestToDiffText(t *testing.T) {
+	past := &Breakdown{
+		Resources: []Resource{
+			{
+				Name:           "aws_instance.web",
+				MonthlyCost:    decimalPtr(decimal.NewFromInt(20)),
+				CostComponents: []CostComponent{testDiffTextComponent("Instance usage (t3.small)", 20)},
+			},
+			{
+				Name:           "aws_instance.old",
+				MonthlyCost:    decimalPtr(decimal.NewFromInt(5)),
+				CostComponents: []CostComponent{testDiffTextComponent("Instance usage (t3.nano)", 5)},
+			},
+		},
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(25)),
+	}
+
+	planned := &Breakdown{
+		Resources: []Resource{
+			{
+				Name:           "aws_instance.web",
+				MonthlyCost:    decimalPtr(decimal.NewFromInt(40)),
+				CostComponents: []CostComponent{testDiffTextComponent("Instance usage (t3.medium)", 40)},
+			},
+			{
+				Name: "aws_lambda_function.fn",
+				CostComponents: []CostComponent{
+					{Name: "Requests", Unit: "1M requests", Price: decimal.NewFromFloat(0.2)},
+				},
+			},
+		},
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(40)),
+	}
+
+	diff := &Breakdown{
+		Resources: []Resource{
+			{
+				Name:        "aws_instance.old",
+				MonthlyCost: decimalPtr(decimal.NewFromInt(-5)),
+			},
+			{
+				Name:        "aws_instance.web",
+				MonthlyCost: decimalPtr(decimal.NewFromInt(20)),
+				CostComponents: []CostComponent{
+					testDiffTextComponent("Instance usage (t3.medium)", 40),
+					testDiffTextComponent("Instance usage (t3.small)", -20),
+				},
+			},
+			{
+				Name: "aws_lambda_function.fn",
+				CostComponents: []CostComponent{
+					{Name: "Requests", Unit: "1M requests", Price: decimal.NewFromFloat(0.2)},
+				},
+			},
+		},
+		TotalMonthlyCost: decimalPtr(decimal.NewFromInt(15)),
+	}
+
+	out := Root{
+		Projects: []Project{
+			{Name: "infracost/example", PastBreakdown: past, Breakdown: planned, Diff: diff},
+			{Name: "infracost/unchanged", PastBreakdown: past, Breakdown: past, Diff: &Breakdown{}},
+		},
+	}
+
+	b, err := ToDiffText(out, Options{})
+	require.NoError(t, err)
+
+	expected := `--- infracost/example
++++ infracost/example
+@@ aws_instance.old -$5.00 @@
+-aws_instance.old  $5.00
+@@ aws_instance.web +$20.00 @@
+-aws_instance.web  $20.00
++aws_instance.web  $40.00
++    Instance usage (t3.medium)  $40.00
+-    Instance usage (t3.small)  $20.00
+@@ aws_lambda_function.fn (depends on usage) @@
++aws_lambda_function.fn
++    Requests  $0.20 per 1M requests (depends on usage)
+@@ Monthly cost change +$15.00 @@
+-Total monthly cost  $25.00
++Total monthly cost  $40.00
+`
+
+	assert.Equal(t, expected, string(b))
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/shopspring/decimal"
+)
+
+// Environment rolls up the costs of a group of projects, which can be from
+// different cloud providers, e.g. a prod environment made up of an AWS project
+// and an Azure DR project.
+type Environment struct {
+	Name                 string           `json:"name"`
+	Projects             []string         `json:"projects"`
+	PastTotalMonthlyCost *decimal.Decimal `json:"pastTotalMonthlyCost"`
+	TotalMonthlyCost     *decimal.Decimal `json:"totalMonthlyCost"`
+	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
+	MonthlyBudget        *decimal.Decimal `json:"monthlyBudget,omitempty"`
+	OverBudget           bool             `json:"overBudget"`
+}
+
+// BuildEnvironment calculates the rolled-up costs for the projects with the given paths.
+// Projects are matched by their path, or by their name if no project has that path.
+func BuildEnvironment(name string, projectPaths []string, monthlyBudget *decimal.Decimal, projects []Project) Environment {
+	env := Environment{
+		Name:          name,
+		Projects:      make([]string, 0, len(projectPaths)),
+		MonthlyBudget: monthlyBudget,
+	}
+
+	for _, path := range projectPaths {
+		project := findProjectByPath(projects, path)
+		if project == nil {
+			continue
+		}
+
+		env.Projects = append(env.Projects, project.Name)
+
+		if project.PastBreakdown != nil {
+			env.PastTotalMonthlyCost = addDecimalPtrs(env.PastTotalMonthlyCost, project.PastBreakdown.TotalMonthlyCost)
+		}
+
+		if project.Breakdown != nil {
+			env.TotalMonthlyCost = addDecimalPtrs(env.TotalMonthlyCost, project.Breakdown.TotalMonthlyCost)
+		}
+
+		if project.Diff != nil {
+			env.DiffTotalMonthlyCost = addDecimalPtrs(env.DiffTotalMonthlyCost, project.Diff.TotalMonthlyCost)
+		}
+	}
+
+	if monthlyBudget != nil && env.TotalMonthlyCost != nil {
+		env.OverBudget = env.TotalMonthlyCost.GreaterThan(*monthlyBudget)
+	}
+
+	return env
+}
+
+func findProjectByPath(projects []Project, path string) *Project {
+	for i, p := range projects {
+		if p.Metadata != nil && filepath.Clean(p.Metadata.Path) == filepath.Clean(path) {
+			return &projects[i]
+		}
+	}
+
+	for i, p := range projects {
+		if p.Name == path {
+			return &projects[i]
+		}
+	}
+
+	return nil
+}
+
+func addDecimalPtrs(a *decimal.Decimal, b *decimal.Decimal) *decimal.Decimal {
+	if b == nil {
+		return a
+	}
+
+	if a == nil {
+		return decimalPtr(*b)
+	}
+
+	return decimalPtr(a.Add(*b))
+}
+
+func environmentsToTable(currency string, envs []Environment) string {
+	s := fmt.Sprintf("%s\n\n", ui.BoldString("Environments:"))
+
+	for _, env := range envs {
+		s += fmt.Sprintf("%s %s %s\n",
+			ui.BoldString(env.Name),
+			formatCost2DP(currency, env.TotalMonthlyCost),
+			ui.FaintStringf("(%d projects)", len(env.Projects)),
+		)
+		s += budgetStatus(currency, env)
+	}
+
+	return s
+}
+
+func environmentsToDiff(currency string, envs []Environment) string {
+	s := fmt.Sprintf("%s\n\n", ui.BoldString("Environments:"))
+
+	for _, env := range envs {
+		s += fmt.Sprintf("%s\nAmount:  %s %s\n",
+			ui.BoldString(fmt.Sprintf("Monthly cost change for %s", env.Name)),
+			formatCostChange(currency, env.DiffTotalMonthlyCost),
+			ui.FaintStringf("(%s -> %s)", formatCost(currency, env.PastTotalMonthlyCost), formatCost(currency, env.TotalMonthlyCost)),
+		)
+
+		percent := formatPercentChange(env.PastTotalMonthlyCost, env.TotalMonthlyCost)
+		if percent != "" {
+			s += fmt.Sprintf("Percent: %s\n", percent)
+		}
+
+		s += budgetStatus(currency, env)
+	}
+
+	return s
+}
+
+func budgetStatus(currency string, env Environment) string {
+	if env.MonthlyBudget == nil {
+		return ""
+	}
+
+	if env.OverBudget {
+		return fmt.Sprintf("%s %s\n", ui.WarningString("Over monthly budget of"), formatCost2DP(currency, env.MonthlyBudget))
+	}
+
+	return ui.FaintStringf("Within monthly budget of %s\n", formatCost2DP(currency, env.MonthlyBudget))
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildEnvironment(t *testing.T) {
+	projects := []Project{
+		{
+			Name:          "infracost/infracost/aws/prod",
+			Metadata:      &schema.ProjectMetadata{Path: "aws/prod"},
+			PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+			Breakdown:     &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+			Diff:          &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+		},
+		{
+			Name:      "infracost/infracost/azure/prod-dr",
+			Metadata:  &schema.ProjectMetadata{Path: "azure/prod-dr"},
+			Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(20))},
+		},
+		{
+			Name:      "infracost/infracost/aws/dev",
+			Metadata:  &schema.ProjectMetadata{Path: "aws/dev"},
+			Breakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(1000))},
+		},
+	}
+
+	env := BuildEnvironment("prod", []string{"./aws/prod", "azure/prod-dr", "missing"}, decimalPtr(decimal.NewFromInt(160)), projects)
+
+	assert.Equal(t, []string{"infracost/infracost/aws/prod", "infracost/infracost/azure/prod-dr"}, env.Projects)
+	assert.Equal(t, "100", env.PastTotalMonthlyCost.String())
+	assert.Equal(t, "170", env.TotalMonthlyCost.String())
+	assert.Equal(t, "50", env.DiffTotalMonthlyCost.String())
+	assert.True(t, env.OverBudget)
+
+	env = BuildEnvironment("dev", []string{"infracost/infracost/aws/dev"}, nil, projects)
+
+	assert.Equal(t, "1000", env.TotalMonthlyCost.String())
+	assert.Nil(t, env.PastTotalMonthlyCost)
+	assert.False(t, env.OverBudget)
+}
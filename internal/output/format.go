@@ -15,7 +15,40 @@ func formatQuantity(q *decimal.Decimal) string {
 	return humanize.CommafWithDigits(f, 4)
 }
 
-func formatCost(d *decimal.Decimal) string {
+// currencySymbols are the symbols of the common currencies. Other currencies are shown
+// with their code.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+	"KRW": "₩",
+	"BRL": "R$",
+	"AUD": "A$",
+	"CAD": "CA$",
+	"NZD": "NZ$",
+	"HKD": "HK$",
+	"SGD": "S$",
+}
+
+// currencySymbol returns the symbol that is prefixed to amounts in the currency. The
+// currency is empty for outputs from versions without multi-currency support, which were
+// always in USD.
+func currencySymbol(currency string) string {
+	if currency == "" {
+		return "$"
+	}
+
+	if sym, ok := currencySymbols[currency]; ok {
+		return sym
+	}
+
+	return currency + " "
+}
+
+func formatCost(currency string, d *decimal.Decimal) string {
 	if d == nil {
 		return "-"
 	}
@@ -27,10 +60,16 @@ func formatCost(d *decimal.Decimal) string {
 		s = humanize.FormatFloat("#,###.", f)
 	}
 
-	return "$" + s
+	return currencySymbol(currency) + s
+}
+
+// FormatCost2DP formats the cost with the symbol of the currency and two decimal places, as
+// the costs are shown in the table output.
+func FormatCost2DP(currency string, d *decimal.Decimal) string {
+	return formatCost2DP(currency, d)
 }
 
-func formatCost2DP(d *decimal.Decimal) string {
+func formatCost2DP(currency string, d *decimal.Decimal) string {
 	if d == nil {
 		return "-"
 	}
@@ -38,16 +77,16 @@ func formatCost2DP(d *decimal.Decimal) string {
 	f, _ := d.Float64()
 
 	s := humanize.FormatFloat("#,###.##", f)
-	return "$" + s
+	return currencySymbol(currency) + s
 }
 
-func formatPrice(d decimal.Decimal) string {
+func formatPrice(currency string, d decimal.Decimal) string {
 	if d.LessThan(decimal.NewFromFloat(0.1)) {
-		return "$" + d.String()
+		return currencySymbol(currency) + d.String()
 	}
 
 	f, _ := d.Float64()
 
 	s := humanize.FormatFloat("#,###.##", f)
-	return "$" + s
+	return currencySymbol(currency) + s
 }
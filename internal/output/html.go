@@ -8,9 +8,14 @@ import (
 	"strings"
 
 	"github.com/Masterminds/sprig"
+	"github.com/shopspring/decimal"
 )
 
 func ToHTML(out Root, opts Options) ([]byte, error) {
+	if opts.ReconcileRounding {
+		out = reconcileRounding(out)
+	}
+
 	var buf bytes.Buffer
 	bufw := bufio.NewWriter(&buf)
 
@@ -25,9 +30,13 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 			safe = strings.ReplaceAll(safe, "\n", "<br />")
 			return template.HTML(safe) // nolint:gosec
 		},
-		"contains":       contains,
-		"formatCost2DP":  formatCost2DP,
-		"formatPrice":    formatPrice,
+		"contains": contains,
+		"formatCost2DP": func(d *decimal.Decimal) string {
+			return formatCost2DP(out.Currency, d)
+		},
+		"formatPrice": func(d decimal.Decimal) string {
+			return formatPrice(out.Currency, d)
+		},
 		"formatQuantity": formatQuantity,
 		"projectLabel": func(p Project) string {
 			return p.Label(opts.DashboardEnabled)
@@ -15,7 +15,9 @@ var outputVersion = "0.2"
 type Root struct {
 	Version          string           `json:"version"`
 	RunID            string           `json:"runId,omitempty"`
+	Currency         string           `json:"currency"`
 	Projects         []Project        `json:"projects"`
+	Environments     []Environment    `json:"environments,omitempty"`
 	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
 	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
 	TimeGenerated    time.Time        `json:"timeGenerated"`
@@ -24,13 +26,16 @@ type Root struct {
 }
 
 type Project struct {
-	Name          string                  `json:"name"`
-	Metadata      *schema.ProjectMetadata `json:"metadata"`
-	PastBreakdown *Breakdown              `json:"pastBreakdown"`
-	Breakdown     *Breakdown              `json:"breakdown"`
-	Diff          *Breakdown              `json:"diff"`
-	Summary       *Summary                `json:"summary"`
-	fullSummary   *Summary
+	Name                  string                                 `json:"name"`
+	Metadata              *schema.ProjectMetadata                `json:"metadata"`
+	PastBreakdown         *Breakdown                             `json:"pastBreakdown"`
+	Breakdown             *Breakdown                             `json:"breakdown"`
+	Diff                  *Breakdown                             `json:"diff"`
+	Summary               *Summary                               `json:"summary"`
+	Sampling              *schema.Sampling                       `json:"sampling,omitempty"`
+	SavingsPlans          []*schema.SavingsPlanCoverage          `json:"savingsPlans,omitempty"`
+	CommittedUseDiscounts []*schema.CommittedUseDiscountCoverage `json:"committedUseDiscounts,omitempty"`
+	fullSummary           *Summary
 }
 
 func (p *Project) Label(dashboardEnabled bool) string {
@@ -44,6 +49,10 @@ type Breakdown struct {
 	Resources        []Resource       `json:"resources"`
 	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
 	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+	// TotalMonthlyCostLow and TotalMonthlyCostHigh are the total monthly cost with the low
+	// and high values of the usage ranges, if any of the resources have them.
+	TotalMonthlyCostLow  *decimal.Decimal `json:"totalMonthlyCostLow,omitempty"`
+	TotalMonthlyCostHigh *decimal.Decimal `json:"totalMonthlyCostHigh,omitempty"`
 }
 
 type CostComponent struct {
@@ -52,8 +61,14 @@ type CostComponent struct {
 	HourlyQuantity  *decimal.Decimal `json:"hourlyQuantity"`
 	MonthlyQuantity *decimal.Decimal `json:"monthlyQuantity"`
 	Price           decimal.Decimal  `json:"price"`
+	// DiscountedPrice is the price after monthly discounts, e.g. GCP sustained use discounts
+	DiscountedPrice *decimal.Decimal `json:"discountedPrice,omitempty"`
 	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
 	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	UsageProvenance string           `json:"usageProvenance,omitempty"`
+	PurchaseOption  string           `json:"purchaseOption,omitempty"`
+	// PriceEffectiveDate is when the price from the Cloud Pricing API became effective
+	PriceEffectiveDate *time.Time `json:"priceEffectiveDate,omitempty"`
 }
 
 type Resource struct {
@@ -64,6 +79,13 @@ type Resource struct {
 	MonthlyCost    *decimal.Decimal  `json:"monthlyCost"`
 	CostComponents []CostComponent   `json:"costComponents,omitempty"`
 	SubResources   []Resource        `json:"subresources,omitempty"`
+	Warnings       []*schema.Warning `json:"warnings,omitempty"`
+	// Usage is the usage the resource was priced with, which --usage-from-json replays
+	Usage map[string]interface{} `json:"usage,omitempty"`
+	// MonthlyCostLow and MonthlyCostHigh are the monthly cost with the low and high values
+	// of the ranges in the resource's usage, if it has any. MonthlyCost is the expected cost.
+	MonthlyCostLow  *decimal.Decimal `json:"monthlyCostLow,omitempty"`
+	MonthlyCostHigh *decimal.Decimal `json:"monthlyCostHigh,omitempty"`
 }
 
 type Summary struct {
@@ -73,6 +95,7 @@ type Summary struct {
 	TotalUnsupportedResources *int            `json:"totalUnsupportedResources,omitempty"`
 	TotalNoPriceResources     *int            `json:"totalNoPriceResources,omitempty"`
 	TotalResources            *int            `json:"totalResources,omitempty"`
+	TotalWarnings             *int            `json:"totalWarnings,omitempty"`
 }
 
 type SummaryOptions struct {
@@ -81,12 +104,14 @@ type SummaryOptions struct {
 }
 
 type Options struct {
-	DashboardEnabled bool
-	NoColor          bool
-	ShowSkipped      bool
-	GroupLabel       string
-	GroupKey         string
-	Fields           []string
+	DashboardEnabled    bool
+	NoColor             bool
+	ShowSkipped         bool
+	ShowUsageProvenance bool
+	ReconcileRounding   bool
+	GroupLabel          string
+	GroupKey            string
+	Fields              []string
 }
 
 func outputBreakdown(resources []*schema.Resource) *Breakdown {
@@ -102,11 +127,14 @@ func outputBreakdown(resources []*schema.Resource) *Breakdown {
 	sortResources(arr, "")
 
 	totalMonthlyCost, totalHourlyCost := calculateTotalCosts(arr)
+	totalMonthlyCostLow, totalMonthlyCostHigh := calculateTotalCostRange(arr)
 
 	return &Breakdown{
-		Resources:        arr,
-		TotalHourlyCost:  totalMonthlyCost,
-		TotalMonthlyCost: totalHourlyCost,
+		Resources:            arr,
+		TotalHourlyCost:      totalMonthlyCost,
+		TotalMonthlyCost:     totalHourlyCost,
+		TotalMonthlyCostLow:  totalMonthlyCostLow,
+		TotalMonthlyCostHigh: totalMonthlyCostHigh,
 	}
 }
 
@@ -114,14 +142,23 @@ func outputResource(r *schema.Resource) Resource {
 	comps := make([]CostComponent, 0, len(r.CostComponents))
 	for _, c := range r.CostComponents {
 
+		var discountedPrice *decimal.Decimal
+		if c.MonthlyDiscountPerc != 0 {
+			discountedPrice = decimalPtr(c.UnitMultiplierPrice().Mul(decimal.NewFromFloat(1 - c.MonthlyDiscountPerc)))
+		}
+
 		comps = append(comps, CostComponent{
-			Name:            c.Name,
-			Unit:            c.Unit,
-			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
-			MonthlyQuantity: c.UnitMultiplierMonthlyQuantity(),
-			Price:           c.UnitMultiplierPrice(),
-			HourlyCost:      c.HourlyCost,
-			MonthlyCost:     c.MonthlyCost,
+			Name:               c.Name,
+			Unit:               c.Unit,
+			HourlyQuantity:     c.UnitMultiplierHourlyQuantity(),
+			MonthlyQuantity:    c.UnitMultiplierMonthlyQuantity(),
+			Price:              c.UnitMultiplierPrice(),
+			DiscountedPrice:    discountedPrice,
+			HourlyCost:         c.HourlyCost,
+			MonthlyCost:        c.MonthlyCost,
+			UsageProvenance:    string(c.UsageProvenance),
+			PurchaseOption:     c.PurchaseOption,
+			PriceEffectiveDate: c.PriceEffectiveDate(),
 		})
 	}
 
@@ -130,14 +167,29 @@ func outputResource(r *schema.Resource) Resource {
 		subresources = append(subresources, outputResource(s))
 	}
 
+	metadata := make(map[string]string, len(r.Metadata))
+	for k, v := range r.Metadata {
+		metadata[k] = v
+	}
+
+	var monthlyCostLow, monthlyCostHigh *decimal.Decimal
+	if r.UsageRange != nil {
+		monthlyCostLow = r.UsageRange.Low.MonthlyCost
+		monthlyCostHigh = r.UsageRange.High.MonthlyCost
+	}
+
 	return Resource{
-		Name:           r.Name,
-		Metadata:       map[string]string{},
-		Tags:           r.Tags,
-		HourlyCost:     r.HourlyCost,
-		MonthlyCost:    r.MonthlyCost,
-		CostComponents: comps,
-		SubResources:   subresources,
+		Name:            r.Name,
+		Metadata:        metadata,
+		Tags:            r.Tags,
+		HourlyCost:      r.HourlyCost,
+		MonthlyCost:     r.MonthlyCost,
+		CostComponents:  comps,
+		SubResources:    subresources,
+		Warnings:        r.Warnings,
+		Usage:           r.Usage,
+		MonthlyCostLow:  monthlyCostLow,
+		MonthlyCostHigh: monthlyCostHigh,
 	}
 }
 
@@ -175,7 +227,7 @@ func ToOutputFormat(projects []*schema.Project) Root {
 		}
 
 		summary := BuildSummary(project.Resources, SummaryOptions{
-			OnlyFields: []string{"UnsupportedResourceCounts"},
+			OnlyFields: []string{"UnsupportedResourceCounts", "TotalWarnings"},
 		})
 		summaries = append(summaries, summary)
 
@@ -183,13 +235,16 @@ func ToOutputFormat(projects []*schema.Project) Root {
 		fullSummaries = append(fullSummaries, fullSummary)
 
 		outProjects = append(outProjects, Project{
-			Name:          project.Name,
-			Metadata:      project.Metadata,
-			PastBreakdown: pastBreakdown,
-			Breakdown:     breakdown,
-			Diff:          diff,
-			Summary:       summary,
-			fullSummary:   fullSummary,
+			Name:                  project.Name,
+			Metadata:              project.Metadata,
+			PastBreakdown:         pastBreakdown,
+			Breakdown:             breakdown,
+			Diff:                  diff,
+			Summary:               summary,
+			Sampling:              project.Sampling,
+			SavingsPlans:          project.SavingsPlans,
+			CommittedUseDiscounts: project.CommittedUseDiscounts,
+			fullSummary:           fullSummary,
 		})
 	}
 
@@ -206,6 +261,18 @@ func ToOutputFormat(projects []*schema.Project) Root {
 	return out
 }
 
+func (r *Root) warningsMessage() string {
+	if r.Summary == nil || r.Summary.TotalWarnings == nil || *r.Summary.TotalWarnings == 0 {
+		return ""
+	}
+
+	if *r.Summary.TotalWarnings == 1 {
+		return "1 cost component has a pricing warning, e.g. a price from a fallback region, see the JSON output for details."
+	}
+
+	return fmt.Sprintf("%d cost components have pricing warnings, e.g. prices from a fallback region, see the JSON output for details.", *r.Summary.TotalWarnings)
+}
+
 func (r *Root) unsupportedResourcesMessage(showSkipped bool) string {
 	if r.Summary == nil {
 		return ""
@@ -264,12 +331,15 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) *Summary {
 	totalSupportedResources := 0
 	totalUnsupportedResources := 0
 	totalNoPriceResources := 0
+	totalWarnings := 0
 
 	for _, r := range resources {
 		if !opts.IncludeUnsupportedProviders && !terraform.HasSupportedProvider(r.ResourceType) {
 			continue
 		}
 
+		totalWarnings += countWarnings(r)
+
 		if r.NoPrice {
 			totalNoPriceResources++
 		} else if r.IsSkipped {
@@ -309,10 +379,21 @@ func BuildSummary(resources []*schema.Resource, opts SummaryOptions) *Summary {
 	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "Total") {
 		s.TotalResources = &totalResources
 	}
+	if len(opts.OnlyFields) == 0 || contains(opts.OnlyFields, "TotalWarnings") {
+		s.TotalWarnings = &totalWarnings
+	}
 
 	return s
 }
 
+func countWarnings(r *schema.Resource) int {
+	count := len(r.Warnings)
+	for _, s := range r.SubResources {
+		count += countWarnings(s)
+	}
+	return count
+}
+
 func MergeSummaries(summaries []*Summary) *Summary {
 	merged := &Summary{}
 
@@ -327,6 +408,7 @@ func MergeSummaries(summaries []*Summary) *Summary {
 		merged.TotalUnsupportedResources = addIntPtrs(merged.TotalUnsupportedResources, s.TotalUnsupportedResources)
 		merged.TotalNoPriceResources = addIntPtrs(merged.TotalNoPriceResources, s.TotalNoPriceResources)
 		merged.TotalResources = addIntPtrs(merged.TotalResources, s.TotalResources)
+		merged.TotalWarnings = addIntPtrs(merged.TotalWarnings, s.TotalWarnings)
 	}
 
 	return merged
@@ -357,6 +439,43 @@ func calculateTotalCosts(resources []Resource) (*decimal.Decimal, *decimal.Decim
 	return totalHourlyCost, totalMonthlyCost
 }
 
+// calculateTotalCostRange returns the total monthly cost of the resources with the low and
+// high values of their usage ranges. The expected cost is used for the resources without
+// usage ranges. It returns nil if none of the resources have usage ranges.
+func calculateTotalCostRange(resources []Resource) (*decimal.Decimal, *decimal.Decimal) {
+	hasRange := false
+	low, high := decimal.Zero, decimal.Zero
+
+	for _, r := range resources {
+		if r.MonthlyCostLow != nil || r.MonthlyCostHigh != nil {
+			hasRange = true
+		}
+
+		if c := firstDecimal(r.MonthlyCostLow, r.MonthlyCost); c != nil {
+			low = low.Add(*c)
+		}
+		if c := firstDecimal(r.MonthlyCostHigh, r.MonthlyCost); c != nil {
+			high = high.Add(*c)
+		}
+	}
+
+	if !hasRange {
+		return nil, nil
+	}
+
+	return &low, &high
+}
+
+func firstDecimal(values ...*decimal.Decimal) *decimal.Decimal {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
 func sortResources(resources []Resource, groupKey string) {
 	sort.Slice(resources, func(i, j int) bool {
 		// If an empty group key is passed just sort by name
@@ -3,6 +3,7 @@ package output
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
 	"gopkg.in/go-playground/assert.v1"
 )
@@ -32,3 +33,49 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestOutputResourceDiscountedPrice(t *testing.T) {
+	c := &schema.CostComponent{
+		Name:                "Instance usage",
+		UnitMultiplier:      decimal.NewFromInt(1),
+		HourlyQuantity:      decimalPtr(decimal.NewFromInt(1)),
+		MonthlyDiscountPerc: 0.3,
+	}
+	c.SetPrice(decimal.NewFromInt(2))
+
+	r := outputResource(&schema.Resource{Name: "google_compute_instance.vm", CostComponents: []*schema.CostComponent{c}})
+	assert.Equal(t, "2", r.CostComponents[0].Price.String())
+	assert.Equal(t, "1.4", r.CostComponents[0].DiscountedPrice.String())
+
+	c.MonthlyDiscountPerc = 0
+	r = outputResource(&schema.Resource{Name: "google_compute_instance.vm", CostComponents: []*schema.CostComponent{c}})
+	assert.Equal(t, true, r.CostComponents[0].DiscountedPrice == nil)
+}
+
+func TestFormatCostCurrency(t *testing.T) {
+	cost := decimalPtr(decimal.NewFromFloat(1234.5))
+
+	assert.Equal(t, "$1,235", formatCost("", cost))
+	assert.Equal(t, "$1,234.50", formatCost2DP("USD", cost))
+	assert.Equal(t, "€1,234.50", formatCost2DP("EUR", cost))
+	assert.Equal(t, "CHF 1,234.50", formatCost2DP("CHF", cost))
+	assert.Equal(t, "£0.05", formatPrice("GBP", decimal.NewFromFloat(0.05)))
+}
+
+func TestBuildSummaryWarnings(t *testing.T) {
+	sub := &schema.Resource{Name: "root_block_device"}
+	sub.AddWarning(schema.WarningPriceNotFound, "Storage", "No products found, using 0.00")
+
+	r := &schema.Resource{Name: "aws_instance.web", ResourceType: "aws_instance", SubResources: []*schema.Resource{sub}}
+	r.AddWarning(schema.WarningRegionFallback, "Instance usage", "No price found in ap-southeast-5, using the price from us-east-1")
+
+	s := BuildSummary([]*schema.Resource{r, {Name: "aws_s3_bucket.b", ResourceType: "aws_s3_bucket"}}, SummaryOptions{OnlyFields: []string{"TotalWarnings"}})
+	assert.Equal(t, 2, *s.TotalWarnings)
+	assert.Equal(t, true, s.TotalResources == nil)
+
+	merged := MergeSummaries([]*Summary{s, s})
+	assert.Equal(t, 4, *merged.TotalWarnings)
+
+	root := Root{Summary: merged}
+	assert.Equal(t, "4 cost components have pricing warnings, e.g. prices from a fallback region, see the JSON output for details.", root.warningsMessage())
+}
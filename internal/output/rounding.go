@@ -0,0 +1,104 @@
+package output
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+const roundingAdjustmentName = "Rounding adjustment"
+
+// reconcileRounding returns a copy of the output with rounding adjustment lines added so
+// the costs shown in cents add up: the cost components of each resource sum to the
+// resource's cost and the resources sum to the project total. Each adjustment has the
+// residual from rounding the costs separately. Only the monthly costs are reconciled.
+func reconcileRounding(out Root) Root {
+	projects := make([]Project, 0, len(out.Projects))
+
+	for _, p := range out.Projects {
+		if p.Breakdown != nil {
+			b := reconcileBreakdownRounding(*p.Breakdown)
+			p.Breakdown = &b
+		}
+
+		projects = append(projects, p)
+	}
+
+	out.Projects = projects
+
+	return out
+}
+
+func reconcileBreakdownRounding(b Breakdown) Breakdown {
+	resources := make([]Resource, 0, len(b.Resources)+1)
+	resourcesTotal := decimal.Zero
+
+	for _, r := range b.Resources {
+		r = reconcileResourceRounding(r)
+
+		if r.MonthlyCost != nil {
+			resourcesTotal = resourcesTotal.Add(roundCents(*r.MonthlyCost))
+		}
+
+		resources = append(resources, r)
+	}
+
+	if b.TotalMonthlyCost != nil {
+		residual := roundCents(*b.TotalMonthlyCost).Sub(resourcesTotal)
+		if !residual.IsZero() {
+			resources = append(resources, Resource{
+				Name:           roundingAdjustmentName,
+				MonthlyCost:    &residual,
+				CostComponents: []CostComponent{roundingAdjustmentCostComponent(residual)},
+			})
+		}
+	}
+
+	b.Resources = resources
+
+	return b
+}
+
+func reconcileResourceRounding(r Resource) Resource {
+	if r.MonthlyCost == nil {
+		return r
+	}
+
+	residual := roundCents(*r.MonthlyCost).Sub(sumRoundedCostComponents(r))
+	if residual.IsZero() {
+		return r
+	}
+
+	costComponents := make([]CostComponent, 0, len(r.CostComponents)+1)
+	costComponents = append(costComponents, r.CostComponents...)
+	r.CostComponents = append(costComponents, roundingAdjustmentCostComponent(residual))
+
+	return r
+}
+
+// sumRoundedCostComponents returns the sum of the rounded costs of the resource's cost
+// components, including the cost components of its sub-resources
+func sumRoundedCostComponents(r Resource) decimal.Decimal {
+	sum := decimal.Zero
+
+	for _, c := range r.CostComponents {
+		if c.MonthlyCost != nil {
+			sum = sum.Add(roundCents(*c.MonthlyCost))
+		}
+	}
+
+	for _, s := range r.SubResources {
+		sum = sum.Add(sumRoundedCostComponents(s))
+	}
+
+	return sum
+}
+
+func roundingAdjustmentCostComponent(residual decimal.Decimal) CostComponent {
+	return CostComponent{
+		Name:        roundingAdjustmentName,
+		MonthlyCost: &residual,
+	}
+}
+
+func roundCents(d decimal.Decimal) decimal.Decimal {
+	return d.Round(2)
+}
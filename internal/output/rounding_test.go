@@ -0,0 +1,84 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRoundingComponent(monthlyCost string) CostComponent {
+	return CostComponent{
+		Name:        "Component",
+		MonthlyCost: decimalPtr(decimal.RequireFromString(monthlyCost)),
+	}
+}
+
+func TestReconcileRounding(t *testing.T) {
+	out := Root{
+		Projects: []Project{
+			{
+				Name: "infracost/example",
+				Breakdown: &Breakdown{
+					Resources: []Resource{
+						{
+							// 0.004 + 0.004 + 0.004 rounds to $0.00 per component but $0.01 in total
+							Name:        "aws_instance.web",
+							MonthlyCost: decimalPtr(decimal.RequireFromString("0.012")),
+							CostComponents: []CostComponent{
+								testRoundingComponent("0.004"),
+								testRoundingComponent("0.004"),
+							},
+							SubResources: []Resource{
+								{Name: "root_block_device", CostComponents: []CostComponent{testRoundingComponent("0.004")}},
+							},
+						},
+						{
+							Name:           "aws_instance.exact",
+							MonthlyCost:    decimalPtr(decimal.RequireFromString("1.50")),
+							CostComponents: []CostComponent{testRoundingComponent("1.50")},
+						},
+						{
+							Name:           "aws_lambda_function.fn",
+							CostComponents: []CostComponent{{Name: "Requests"}},
+						},
+						{
+							Name:           "aws_instance.app",
+							MonthlyCost:    decimalPtr(decimal.RequireFromString("0.004")),
+							CostComponents: []CostComponent{testRoundingComponent("0.004")},
+						},
+						{
+							Name:           "aws_instance.worker",
+							MonthlyCost:    decimalPtr(decimal.RequireFromString("0.004")),
+							CostComponents: []CostComponent{testRoundingComponent("0.004")},
+						},
+					},
+					TotalMonthlyCost: decimalPtr(decimal.RequireFromString("1.52")),
+				},
+			},
+		},
+	}
+
+	reconciled := reconcileRounding(out)
+	resources := reconciled.Projects[0].Breakdown.Resources
+
+	require.Len(t, resources, 6)
+
+	web := resources[0]
+	require.Len(t, web.CostComponents, 3)
+	assert.Equal(t, roundingAdjustmentName, web.CostComponents[2].Name)
+	assert.Equal(t, "0.01", web.CostComponents[2].MonthlyCost.String())
+
+	assert.Len(t, resources[1].CostComponents, 1)
+	assert.Len(t, resources[2].CostComponents, 1)
+
+	// The resources round to $0.01 + $1.50 + $0.00 + $0.00 so $0.01 is added to the project
+	adjustment := resources[5]
+	assert.Equal(t, roundingAdjustmentName, adjustment.Name)
+	assert.Equal(t, "0.01", adjustment.MonthlyCost.String())
+
+	// The original output isn't changed
+	assert.Len(t, out.Projects[0].Breakdown.Resources, 5)
+	assert.Len(t, out.Projects[0].Breakdown.Resources[0].CostComponents, 2)
+}
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/shopspring/decimal"
+)
+
+// sensitivityBarWidth is the width of the bar of the usage key with the largest swing.
+const sensitivityBarWidth = 20
+
+// SensitivityReport is how much the total monthly cost changes when each usage key of each
+// resource is lowered and raised, ranked from the usage key that changes it the most.
+type SensitivityReport struct {
+	Currency         string             `json:"currency"`
+	Change           float64            `json:"change"`
+	TotalMonthlyCost *decimal.Decimal   `json:"totalMonthlyCost"`
+	Sensitivities    []UsageSensitivity `json:"sensitivities"`
+}
+
+// UsageSensitivity is the total monthly cost with the usage key of the resource lowered and
+// raised. The swing is the difference between them.
+type UsageSensitivity struct {
+	Project              string           `json:"project"`
+	Resource             string           `json:"resource"`
+	UsageKey             string           `json:"usageKey"`
+	Value                interface{}      `json:"value"`
+	TotalMonthlyCostLow  *decimal.Decimal `json:"totalMonthlyCostLow"`
+	TotalMonthlyCostHigh *decimal.Decimal `json:"totalMonthlyCostHigh"`
+	Swing                *decimal.Decimal `json:"swing"`
+}
+
+// ToSensitivityReport ranks the usage keys of the projects' resources by how much lowering
+// and raising them changes the total monthly cost. The usage keys that don't change it,
+// e.g. ones the resource doesn't use, are left out.
+func ToSensitivityReport(projects []*schema.Project, currency string, change float64) SensitivityReport {
+	total := decimal.Zero
+	for _, p := range projects {
+		for _, r := range p.Resources {
+			total = total.Add(monthlyCostOrZero(r))
+		}
+	}
+
+	sensitivities := make([]UsageSensitivity, 0)
+
+	for _, p := range projects {
+		for _, r := range p.Resources {
+			for k, v := range r.UsageSensitivity {
+				cost := monthlyCostOrZero(r)
+				low := total.Sub(cost).Add(monthlyCostOrZero(v.Low))
+				high := total.Sub(cost).Add(monthlyCostOrZero(v.High))
+				swing := high.Sub(low).Abs()
+
+				if swing.IsZero() {
+					continue
+				}
+
+				sensitivities = append(sensitivities, UsageSensitivity{
+					Project:              p.Name,
+					Resource:             r.Name,
+					UsageKey:             k,
+					Value:                r.Usage[k],
+					TotalMonthlyCostLow:  &low,
+					TotalMonthlyCostHigh: &high,
+					Swing:                &swing,
+				})
+			}
+		}
+	}
+
+	sort.Slice(sensitivities, func(i, j int) bool {
+		a, b := sensitivities[i], sensitivities[j]
+		if !a.Swing.Equal(*b.Swing) {
+			return a.Swing.GreaterThan(*b.Swing)
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+
+		return a.UsageKey < b.UsageKey
+	})
+
+	return SensitivityReport{
+		Currency:         currency,
+		Change:           change,
+		TotalMonthlyCost: &total,
+		Sensitivities:    sensitivities,
+	}
+}
+
+func ToSensitivityJSON(r SensitivityReport, opts Options) ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// ToSensitivityTable outputs the ranked usage keys with a bar for the swing of each, like a
+// tornado chart.
+func ToSensitivityTable(r SensitivityReport, opts Options) ([]byte, error) {
+	if len(r.Sensitivities) == 0 {
+		return []byte("No usage keys change the total monthly cost\n"), nil
+	}
+
+	change := decimal.NewFromFloat(r.Change * 100).String()
+
+	t := table.NewWriter()
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Format.Header = text.FormatDefault
+
+	t.AppendHeader(table.Row{
+		ui.UnderlineString("Resource"),
+		ui.UnderlineString("Usage key"),
+		ui.UnderlineString("Value"),
+		ui.UnderlineString(fmt.Sprintf("Total at -%s%%", change)),
+		ui.UnderlineString(fmt.Sprintf("Total at +%s%%", change)),
+		ui.UnderlineString("Swing"),
+		"",
+	})
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, Align: text.AlignLeft, AlignHeader: text.AlignLeft},
+		{Number: 2, Align: text.AlignLeft, AlignHeader: text.AlignLeft},
+		{Number: 3, Align: text.AlignRight, AlignHeader: text.AlignRight},
+		{Number: 4, Align: text.AlignRight, AlignHeader: text.AlignRight},
+		{Number: 5, Align: text.AlignRight, AlignHeader: text.AlignRight},
+		{Number: 6, Align: text.AlignRight, AlignHeader: text.AlignRight},
+		{Number: 7, Align: text.AlignLeft, AlignHeader: text.AlignLeft},
+	})
+
+	maxSwing := r.Sensitivities[0].Swing
+	for _, s := range r.Sensitivities {
+		width := int(s.Swing.Div(*maxSwing).Mul(decimal.NewFromInt(sensitivityBarWidth)).Ceil().IntPart())
+
+		t.AppendRow(table.Row{
+			s.Resource,
+			s.UsageKey,
+			fmt.Sprintf("%v", s.Value),
+			formatCost2DP(r.Currency, s.TotalMonthlyCostLow),
+			formatCost2DP(r.Currency, s.TotalMonthlyCostHigh),
+			formatCost2DP(r.Currency, s.Swing),
+			strings.Repeat("█", width),
+		})
+	}
+
+	out := fmt.Sprintf("%s %s\n\n%s\n",
+		ui.BoldString("Total monthly cost:"),
+		formatCost2DP(r.Currency, r.TotalMonthlyCost),
+		t.Render(),
+	)
+
+	return []byte(out), nil
+}
+
+func monthlyCostOrZero(r *schema.Resource) decimal.Decimal {
+	if r == nil || r.MonthlyCost == nil {
+		return decimal.Zero
+	}
+
+	return *r.MonthlyCost
+}
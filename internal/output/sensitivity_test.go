@@ -0,0 +1,58 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSensitivityReport(t *testing.T) {
+	resourceWithCost := func(name string, cost int64) *schema.Resource {
+		return &schema.Resource{Name: name, MonthlyCost: decimalPtr(decimal.NewFromInt(cost))}
+	}
+
+	fn := resourceWithCost("aws_lambda_function.fn", 100)
+	fn.Usage = map[string]interface{}{"monthly_requests": 1000000, "request_duration_ms": 500, "count_estimate": 1}
+	fn.UsageSensitivity = map[string]*schema.ResourceUsageRange{
+		"monthly_requests":    {Low: resourceWithCost(fn.Name, 60), High: resourceWithCost(fn.Name, 140)},
+		"request_duration_ms": {Low: resourceWithCost(fn.Name, 80), High: resourceWithCost(fn.Name, 120)},
+		"count_estimate":      {Low: resourceWithCost(fn.Name, 100), High: resourceWithCost(fn.Name, 100)},
+	}
+
+	bucket := resourceWithCost("aws_s3_bucket.bucket", 50)
+	bucket.Usage = map[string]interface{}{"standard.storage_gb": 1000}
+	bucket.UsageSensitivity = map[string]*schema.ResourceUsageRange{
+		"standard.storage_gb": {Low: resourceWithCost(bucket.Name, 25), High: resourceWithCost(bucket.Name, 75)},
+	}
+
+	project := schema.NewProject("proj", &schema.ProjectMetadata{})
+	project.Resources = []*schema.Resource{fn, bucket, resourceWithCost("aws_instance.web", 200)}
+
+	r := ToSensitivityReport([]*schema.Project{project}, "USD", 0.5)
+	assert.Equal(t, "350", r.TotalMonthlyCost.String())
+
+	// The usage keys that don't change the cost are left out
+	require.Len(t, r.Sensitivities, 3)
+
+	expected := []struct {
+		resource, key, low, high, swing string
+	}{
+		{"aws_lambda_function.fn", "monthly_requests", "310", "390", "80"},
+		{"aws_s3_bucket.bucket", "standard.storage_gb", "325", "375", "50"},
+		{"aws_lambda_function.fn", "request_duration_ms", "330", "370", "40"},
+	}
+
+	for i, e := range expected {
+		s := r.Sensitivities[i]
+		assert.Equal(t, e.resource, s.Resource)
+		assert.Equal(t, e.key, s.UsageKey)
+		assert.Equal(t, e.low, s.TotalMonthlyCostLow.String())
+		assert.Equal(t, e.high, s.TotalMonthlyCostHigh.String())
+		assert.Equal(t, e.swing, s.Swing.String())
+	}
+
+	assert.Equal(t, 1000000, r.Sensitivities[0].Value)
+}
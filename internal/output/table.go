@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -12,6 +13,10 @@ import (
 func ToTable(out Root, opts Options) ([]byte, error) {
 	var tableLen int
 
+	if opts.ReconcileRounding {
+		out = reconcileRounding(out)
+	}
+
 	s := ""
 
 	hasNilCosts := false
@@ -38,7 +43,12 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 			hasNilCosts = true
 		}
 
-		tableOut := tableForBreakdown(*project.Breakdown, opts.Fields, includeProjectTotals)
+		breakdown := *project.Breakdown
+		if opts.ShowUsageProvenance {
+			breakdown = breakdownWithUsageProvenance(breakdown)
+		}
+
+		tableOut := tableForBreakdown(out.Currency, breakdown, opts.Fields, includeProjectTotals)
 
 		// Get the last table length so we can align the overall total with it
 		if i == len(out.Projects)-1 {
@@ -49,6 +59,18 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 
 		s += "\n"
 
+		if project.Sampling != nil {
+			s += samplingToTable(out.Currency, project.Sampling)
+		}
+
+		if len(project.SavingsPlans) > 0 {
+			s += savingsPlansToTable(out.Currency, project.SavingsPlans)
+		}
+
+		if len(project.CommittedUseDiscounts) > 0 {
+			s += committedUseDiscountsToTable(out.Currency, project.CommittedUseDiscounts)
+		}
+
 		if i != len(out.Projects)-1 {
 			s += "\n"
 		}
@@ -58,16 +80,22 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += "\n"
 	}
 
-	totalOut := formatCost2DP(out.TotalMonthlyCost)
+	totalOut := formatCost2DP(out.Currency, out.TotalMonthlyCost)
 
 	s += fmt.Sprintf("%s%s",
 		ui.BoldString(" OVERALL TOTAL"),
 		fmt.Sprintf("%*s ", tableLen-15, totalOut), // pad based on the last line length
 	)
 
+	if len(out.Environments) > 0 {
+		s += "\n----------------------------------\n"
+		s += strings.TrimSuffix(environmentsToTable(out.Currency, out.Environments), "\n")
+	}
+
 	unsupportedMsg := out.unsupportedResourcesMessage(opts.ShowSkipped)
+	warningsMsg := out.warningsMessage()
 
-	if hasNilCosts || unsupportedMsg != "" {
+	if hasNilCosts || unsupportedMsg != "" || warningsMsg != "" {
 		s += "\n----------------------------------"
 	}
 
@@ -76,7 +104,7 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 			ui.LinkString("https://infracost.io/usage-file"),
 		)
 
-		if unsupportedMsg != "" {
+		if unsupportedMsg != "" || warningsMsg != "" {
 			s += "\n"
 		}
 	}
@@ -85,10 +113,14 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		s += "\n" + unsupportedMsg
 	}
 
+	if warningsMsg != "" {
+		s += "\n" + warningsMsg
+	}
+
 	return []byte(s), nil
 }
 
-func tableForBreakdown(breakdown Breakdown, fields []string, includeTotal bool) string {
+func tableForBreakdown(currency string, breakdown Breakdown, fields []string, includeTotal bool) string {
 	t := table.NewWriter()
 	t.Style().Options.DrawBorder = false
 	t.Style().Options.SeparateColumns = false
@@ -165,8 +197,8 @@ func tableForBreakdown(breakdown Breakdown, fields []string, includeTotal bool)
 	for _, r := range breakdown.Resources {
 		t.AppendRow(table.Row{ui.BoldString(r.Name)})
 
-		buildCostComponentRows(t, r.CostComponents, "", len(r.SubResources) > 0, fields)
-		buildSubResourceRows(t, r.SubResources, "", fields)
+		buildCostComponentRows(currency, t, r.CostComponents, "", len(r.SubResources) > 0, fields)
+		buildSubResourceRows(currency, t, r.SubResources, "", fields)
 
 		t.AppendRow(table.Row{""})
 	}
@@ -178,14 +210,14 @@ func tableForBreakdown(breakdown Breakdown, fields []string, includeTotal bool)
 		for q := 0; q < numOfFields; q++ {
 			totalCostRow = append(totalCostRow, "")
 		}
-		totalCostRow = append(totalCostRow, formatCost2DP(breakdown.TotalMonthlyCost))
+		totalCostRow = append(totalCostRow, formatCost2DP(currency, breakdown.TotalMonthlyCost))
 		t.AppendRow(totalCostRow)
 	}
 
 	return t.Render()
 }
 
-func buildSubResourceRows(t table.Writer, subresources []Resource, prefix string, fields []string) {
+func buildSubResourceRows(currency string, t table.Writer, subresources []Resource, prefix string, fields []string) {
 	for i, r := range subresources {
 		labelPrefix := prefix + "├─"
 		nextPrefix := prefix + "│  "
@@ -196,12 +228,12 @@ func buildSubResourceRows(t table.Writer, subresources []Resource, prefix string
 
 		t.AppendRow(table.Row{fmt.Sprintf("%s %s", ui.FaintString(labelPrefix), r.Name)})
 
-		buildCostComponentRows(t, r.CostComponents, nextPrefix, len(r.SubResources) > 0, fields)
-		buildSubResourceRows(t, r.SubResources, nextPrefix, fields)
+		buildCostComponentRows(currency, t, r.CostComponents, nextPrefix, len(r.SubResources) > 0, fields)
+		buildSubResourceRows(currency, t, r.SubResources, nextPrefix, fields)
 	}
 }
 
-func buildCostComponentRows(t table.Writer, costComponents []CostComponent, prefix string, hasSubResources bool, fields []string) {
+func buildCostComponentRows(currency string, t table.Writer, costComponents []CostComponent, prefix string, hasSubResources bool, fields []string) {
 	for i, c := range costComponents {
 		labelPrefix := prefix + "├─"
 		if !hasSubResources && i == len(costComponents)-1 {
@@ -212,7 +244,7 @@ func buildCostComponentRows(t table.Writer, costComponents []CostComponent, pref
 
 		if c.MonthlyCost == nil {
 			price := fmt.Sprintf("Monthly cost depends on usage: %s per %s",
-				formatPrice(c.Price),
+				formatPrice(currency, c.Price),
 				c.Unit,
 			)
 
@@ -227,7 +259,11 @@ func buildCostComponentRows(t table.Writer, costComponents []CostComponent, pref
 			tableRow = append(tableRow, label)
 
 			if contains(fields, "price") {
-				tableRow = append(tableRow, formatPrice(c.Price))
+				price := formatPrice(currency, c.Price)
+				if c.DiscountedPrice != nil {
+					price = fmt.Sprintf("%s (%s discounted)", price, formatPrice(currency, *c.DiscountedPrice))
+				}
+				tableRow = append(tableRow, price)
 			}
 			if contains(fields, "monthlyQuantity") {
 				tableRow = append(tableRow, formatQuantity(c.MonthlyQuantity))
@@ -236,13 +272,117 @@ func buildCostComponentRows(t table.Writer, costComponents []CostComponent, pref
 				tableRow = append(tableRow, c.Unit)
 			}
 			if contains(fields, "hourlyCost") {
-				tableRow = append(tableRow, formatCost2DP(c.HourlyCost))
+				tableRow = append(tableRow, formatCost2DP(currency, c.HourlyCost))
 			}
 			if contains(fields, "monthlyCost") {
-				tableRow = append(tableRow, formatCost2DP(c.MonthlyCost))
+				tableRow = append(tableRow, formatCost2DP(currency, c.MonthlyCost))
 			}
 
 			t.AppendRow(tableRow)
 		}
 	}
 }
+
+// breakdownWithUsageProvenance returns a copy of the breakdown with the usage provenance
+// added to the names of the usage-based cost components.
+func breakdownWithUsageProvenance(breakdown Breakdown) Breakdown {
+	resources := make([]Resource, 0, len(breakdown.Resources))
+	for _, r := range breakdown.Resources {
+		resources = append(resources, resourceWithUsageProvenance(r))
+	}
+
+	breakdown.Resources = resources
+
+	return breakdown
+}
+
+func resourceWithUsageProvenance(r Resource) Resource {
+	costComponents := make([]CostComponent, 0, len(r.CostComponents))
+	for _, c := range r.CostComponents {
+		if c.UsageProvenance != "" {
+			c.Name = fmt.Sprintf("%s %s", c.Name, ui.FaintStringf("[usage: %s]", c.UsageProvenance))
+		}
+		costComponents = append(costComponents, c)
+	}
+
+	subResources := make([]Resource, 0, len(r.SubResources))
+	for _, s := range r.SubResources {
+		subResources = append(subResources, resourceWithUsageProvenance(s))
+	}
+
+	r.CostComponents = costComponents
+	r.SubResources = subResources
+
+	return r
+}
+
+func savingsPlansToTable(currency string, coverages []*schema.SavingsPlanCoverage) string {
+	s := "\n"
+
+	for _, c := range coverages {
+		label := strings.ReplaceAll(c.Type, "_", " ")
+		if c.Term != "" {
+			label += ", " + strings.ReplaceAll(c.Term, "_", " ")
+		}
+
+		s += fmt.Sprintf("%s %s covered, %s on-demand, saving %s\n",
+			ui.BoldString(fmt.Sprintf("Savings Plan (%s):", label)),
+			formatCost2DP(currency, c.CoveredCost),
+			formatCost2DP(currency, c.OnDemandCost),
+			formatCost2DP(currency, c.Savings),
+		)
+
+		if c.UnusedCommitment != nil && c.UnusedCommitment.IsPositive() {
+			s += ui.FaintStringf("  %s of the %s monthly commitment is unused, it is not included in the total.\n", formatCost2DP(currency, c.UnusedCommitment), formatCost2DP(currency, c.MonthlyCommitment))
+		}
+	}
+
+	return s
+}
+
+func committedUseDiscountsToTable(currency string, coverages []*schema.CommittedUseDiscountCoverage) string {
+	s := "\n"
+
+	for _, c := range coverages {
+		label := fmt.Sprintf("%s, %s", c.Region, strings.ReplaceAll(c.Term, "_", " "))
+		if c.MachineSeries != "" {
+			label = fmt.Sprintf("%s, %s", c.MachineSeries, label)
+		}
+
+		s += fmt.Sprintf("%s %s of %s vCPUs and %s of %s GB memory covered, saving %s\n",
+			ui.BoldString(fmt.Sprintf("Committed use discount (%s):", label)),
+			formatQuantity(&c.CoveredVCPUs),
+			formatQuantity(&c.VCPUs),
+			formatQuantity(&c.CoveredMemoryGB),
+			formatQuantity(&c.MemoryGB),
+			formatCost2DP(currency, c.Savings),
+		)
+
+		if c.OverflowVCPUs.IsPositive() || c.OverflowMemoryGB.IsPositive() {
+			s += ui.FaintStringf("  %s vCPUs and %s GB memory above the commitment are priced on-demand.\n", formatQuantity(&c.OverflowVCPUs), formatQuantity(&c.OverflowMemoryGB))
+		}
+	}
+
+	return s
+}
+
+func samplingToTable(currency string, sampling *schema.Sampling) string {
+	s := fmt.Sprintf("\n%s %s %s\n",
+		ui.WarningString("Sampled estimate:"),
+		formatCost2DP(currency, sampling.EstimatedMonthlyCost),
+		ui.FaintStringf("(95%% confidence interval %s - %s)", formatCost2DP(currency, sampling.LowerMonthlyCost), formatCost2DP(currency, sampling.UpperMonthlyCost)),
+	)
+
+	for _, t := range sampling.ResourceTypes {
+		s += fmt.Sprintf("  %d of %d %s priced, estimated at %s\n",
+			t.SampleCount,
+			t.TotalCount,
+			t.ResourceType,
+			formatCost2DP(currency, t.EstimatedMonthlyCost),
+		)
+	}
+
+	s += ui.FaintString("  Only a sample of resources were priced, the breakdown above does not include all resources.\n")
+
+	return s
+}
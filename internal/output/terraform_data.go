@@ -0,0 +1,72 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ToTerraformExternalData returns the totals as the JSON object expected by Terraform's
+// external data source, which only supports string values. If project is set only the
+// totals of that project are returned.
+func ToTerraformExternalData(out Root, project string) ([]byte, error) {
+	projects := out.Projects
+
+	if project != "" {
+		projects = nil
+		for _, p := range out.Projects {
+			if p.Name == project {
+				projects = append(projects, p)
+			}
+		}
+
+		if len(projects) == 0 {
+			return []byte{}, fmt.Errorf("Project %s not found in the Infracost JSON", project)
+		}
+	}
+
+	var totalHourlyCost, totalMonthlyCost, pastTotalMonthlyCost *decimal.Decimal
+	resourceCount := 0
+
+	for _, p := range projects {
+		if p.Breakdown != nil {
+			totalHourlyCost = addDecimalPtrs(totalHourlyCost, p.Breakdown.TotalHourlyCost)
+			totalMonthlyCost = addDecimalPtrs(totalMonthlyCost, p.Breakdown.TotalMonthlyCost)
+			resourceCount += len(p.Breakdown.Resources)
+		}
+
+		if p.PastBreakdown != nil {
+			pastTotalMonthlyCost = addDecimalPtrs(pastTotalMonthlyCost, p.PastBreakdown.TotalMonthlyCost)
+		}
+	}
+
+	d := map[string]string{
+		"total_hourly_cost":       terraformDataDecimal(totalHourlyCost),
+		"total_monthly_cost":      terraformDataDecimal(totalMonthlyCost),
+		"past_total_monthly_cost": terraformDataDecimal(pastTotalMonthlyCost),
+		"diff_total_monthly_cost": "",
+		"resource_count":          fmt.Sprintf("%d", resourceCount),
+		"currency":                "USD",
+	}
+
+	if out.Currency != "" {
+		d["currency"] = out.Currency
+	}
+
+	if totalMonthlyCost != nil && pastTotalMonthlyCost != nil {
+		d["diff_total_monthly_cost"] = terraformDataDecimal(decimalPtr(totalMonthlyCost.Sub(*pastTotalMonthlyCost)))
+	}
+
+	return json.Marshal(d)
+}
+
+// terraformDataDecimal returns the value rounded to 2 decimal places, or an empty string
+// if it is nil since the external data source doesn't support null values
+func terraformDataDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+
+	return d.StringFixed(2)
+}
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTerraformExternalData(t *testing.T) {
+	out := Root{
+		Projects: []Project{
+			{
+				Name:          "infracost/infracost/prod",
+				PastBreakdown: &Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+				Breakdown: &Breakdown{
+					Resources:        []Resource{{Name: "aws_instance.web"}, {Name: "aws_db_instance.db"}},
+					TotalHourlyCost:  decimalPtr(decimal.NewFromFloat(0.2)),
+					TotalMonthlyCost: decimalPtr(decimal.NewFromFloat(146.005)),
+				},
+			},
+			{
+				Name: "infracost/infracost/dev",
+				Breakdown: &Breakdown{
+					Resources:        []Resource{{Name: "aws_instance.web"}},
+					TotalHourlyCost:  decimalPtr(decimal.NewFromFloat(0.1)),
+					TotalMonthlyCost: decimalPtr(decimal.NewFromInt(73)),
+				},
+			},
+		},
+	}
+
+	b, err := ToTerraformExternalData(out, "")
+	require.NoError(t, err)
+
+	var d map[string]string
+	require.NoError(t, json.Unmarshal(b, &d))
+
+	assert.Equal(t, map[string]string{
+		"total_hourly_cost":       "0.30",
+		"total_monthly_cost":      "219.01",
+		"past_total_monthly_cost": "100.00",
+		"diff_total_monthly_cost": "119.01",
+		"resource_count":          "3",
+		"currency":                "USD",
+	}, d)
+
+	b, err = ToTerraformExternalData(out, "infracost/infracost/dev")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &d))
+
+	assert.Equal(t, "73.00", d["total_monthly_cost"])
+	assert.Equal(t, "", d["past_total_monthly_cost"])
+	assert.Equal(t, "", d["diff_total_monthly_cost"])
+	assert.Equal(t, "1", d["resource_count"])
+
+	_, err = ToTerraformExternalData(out, "missing")
+	assert.EqualError(t, err, "Project missing not found in the Infracost JSON")
+}
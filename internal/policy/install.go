@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/infracost/infracost/internal/vcs"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Install installs the policy pack from the source into the cache directory and loads it.
+// Sources should reference a fixed version, e.g. a git tag or an OCI tag, since a pack
+// that is already installed is not downloaded again. OCI artifacts are downloaded with the
+// HTTP client.
+func Install(source string, cacheDir string, httpClient *http.Client) (*Pack, error) {
+	h := sha256.Sum256([]byte(source))
+	dir := filepath.Join(cacheDir, hex.EncodeToString(h[:])[:16])
+
+	if p, err := LoadPack(dir); err == nil {
+		log.Debugf("Using installed policy pack %s from %s", p, dir)
+		p.Source = source
+		return p, nil
+	}
+
+	err := os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating policy pack directory")
+	}
+
+	tmpDir, err := ioutil.TempDir(cacheDir, "install")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating policy pack directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var packDir string
+
+	switch {
+	case strings.HasPrefix(source, "oci://"):
+		packDir, err = installOCI(strings.TrimPrefix(source, "oci://"), tmpDir, httpClient)
+	case vcs.IsGitSource(source):
+		packDir, err = installGit(source, tmpDir)
+	default:
+		err = errors.Errorf("Unsupported policy pack source %s, the source must start with git:: or oci://", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the pack is valid before moving it into place
+	if _, err = LoadPack(packDir); err != nil {
+		return nil, err
+	}
+
+	_ = os.RemoveAll(dir)
+
+	err = os.Rename(packDir, dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error installing policy pack")
+	}
+
+	p, err := LoadPack(dir)
+	if err != nil {
+		return nil, err
+	}
+	p.Source = source
+
+	log.Debugf("Installed policy pack %s from %s", p, source)
+
+	return p, nil
+}
+
+// installGit clones the git source into dir and returns the directory of the pack. The source
+// can include a subdirectory and a ref, e.g. https://github.com/org/packs.git//finops?ref=v1.2.0
+func installGit(source string, dir string) (string, error) {
+	src, err := vcs.ParseGitSource(source)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid policy pack source")
+	}
+
+	packDir, err := vcs.Clone(src, filepath.Join(dir, "repo"))
+	if err != nil {
+		return "", errors.Wrap(err, "Error cloning policy pack")
+	}
+
+	return packDir, nil
+}
@@ -0,0 +1,299 @@
+package policy
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+var wwwAuthenticateParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+type ociReference struct {
+	Host       string
+	Repository string
+	Reference  string
+}
+
+type ociClient struct {
+	ref        ociReference
+	token      string
+	httpClient *http.Client
+}
+
+// installOCI downloads the policy pack layer of the OCI artifact and extracts it into dir.
+// Registries that need authentication use the INFRACOST_OCI_USERNAME and INFRACOST_OCI_PASSWORD
+// environment variables.
+func installOCI(source string, dir string, httpClient *http.Client) (string, error) {
+	ref, err := parseOCIReference(source)
+	if err != nil {
+		return "", err
+	}
+
+	c := &ociClient{ref: ref, httpClient: httpClient}
+
+	b, err := c.get(fmt.Sprintf("/manifests/%s", ref.Reference), strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return "", errors.Wrapf(err, "Error getting policy pack manifest for %s", source)
+	}
+
+	var manifest struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"layers"`
+	}
+
+	err = json.Unmarshal(b, &manifest)
+	if err != nil || len(manifest.Layers) == 0 {
+		return "", errors.Errorf("Invalid policy pack manifest for %s", source)
+	}
+
+	layer := manifest.Layers[0]
+	for _, l := range manifest.Layers {
+		if strings.Contains(l.MediaType, "tar") {
+			layer = l
+			break
+		}
+	}
+
+	b, err = c.get(fmt.Sprintf("/blobs/%s", layer.Digest), "")
+	if err != nil {
+		return "", errors.Wrapf(err, "Error downloading policy pack %s", source)
+	}
+
+	if err = checkDigest(b, layer.Digest); err != nil {
+		return "", err
+	}
+
+	packDir := filepath.Join(dir, "pack")
+
+	err = extractTar(b, packDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error extracting policy pack %s", source)
+	}
+
+	return packDir, nil
+}
+
+func parseOCIReference(source string) (ociReference, error) {
+	parts := strings.SplitN(source, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ociReference{}, errors.Errorf("Invalid OCI reference %s, expected oci://<registry>/<repository>:<tag>", source)
+	}
+
+	ref := ociReference{Host: parts[0], Repository: parts[1], Reference: "latest"}
+
+	if i := strings.Index(ref.Repository, "@"); i != -1 {
+		ref.Reference = ref.Repository[i+1:]
+		ref.Repository = ref.Repository[:i]
+	} else if i := strings.LastIndex(ref.Repository, ":"); i != -1 {
+		ref.Reference = ref.Repository[i+1:]
+		ref.Repository = ref.Repository[:i]
+	}
+
+	return ref, nil
+}
+
+func (c *ociClient) get(path string, accept string) ([]byte, error) {
+	resp, err := c.doGet(path, accept)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		resp.Body.Close()
+
+		c.token, err = c.fetchToken(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return []byte{}, err
+		}
+
+		resp, err = c.doGet(path, accept)
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "Invalid registry response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return []byte{}, errors.Errorf("Invalid registry response %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	return b, nil
+}
+
+func (c *ociClient) doGet(path string, accept string) (*http.Response, error) {
+	u := fmt.Sprintf("https://%s/v2/%s%s", c.ref.Host, c.ref.Repository, path)
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating request")
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending registry request")
+	}
+
+	return resp, nil
+}
+
+// fetchToken gets a bearer token using the registry's WWW-Authenticate challenge
+func (c *ociClient) fetchToken(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", errors.New("Registry requires an unsupported authentication method")
+	}
+
+	params := map[string]string{}
+	for _, m := range wwwAuthenticateParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+
+	if params["realm"] == "" {
+		return "", errors.New("Registry authentication challenge has no realm")
+	}
+
+	q := url.Values{}
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		q.Set("scope", params["scope"])
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", params["realm"], q.Encode()), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "Error generating request")
+	}
+
+	if username := os.Getenv("INFRACOST_OCI_USERNAME"); username != "" {
+		req.SetBasicAuth(username, os.Getenv("INFRACOST_OCI_PASSWORD"))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sending registry authentication request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Registry authentication failed with status %d, check INFRACOST_OCI_USERNAME and INFRACOST_OCI_PASSWORD", resp.StatusCode)
+	}
+
+	var r struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid registry authentication response")
+	}
+
+	if r.Token != "" {
+		return r.Token, nil
+	}
+
+	return r.AccessToken, nil
+}
+
+func checkDigest(b []byte, digest string) error {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || parts[0] != "sha256" {
+		return errors.Errorf("Unsupported digest %s", digest)
+	}
+
+	h := sha256.Sum256(b)
+	if hex.EncodeToString(h[:]) != parts[1] {
+		return errors.Errorf("Policy pack digest does not match %s", digest)
+	}
+
+	return nil
+}
+
+// extractTar extracts the tar, or gzipped tar, archive into dir
+func extractTar(b []byte, dir string) error {
+	var r io.Reader = bufio.NewReader(bytes.NewReader(b))
+
+	if len(b) > 2 && b[0] == 0x1f && b[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if path != filepath.Clean(dir) && !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return errors.Errorf("Invalid path %s in archive", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(f, tr) // nolint:gosec
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.MkdirAll(dir, 0700)
+}
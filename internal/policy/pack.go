@@ -0,0 +1,90 @@
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// The manifest file that must be in the root of a policy pack
+var packManifestFile = "infracost-policy.yml"
+
+// The policy file extensions that are distributed with a pack
+var policyFileExts = []string{".rego", ".cel"}
+
+// Thresholds are cost limits that are checked for each project
+type Thresholds struct {
+	MaxMonthlyCost                *float64 `yaml:"max_monthly_cost,omitempty"`
+	MaxMonthlyCostIncrease        *float64 `yaml:"max_monthly_cost_increase,omitempty"`
+	MaxMonthlyCostIncreasePercent *float64 `yaml:"max_monthly_cost_increase_percent,omitempty"`
+}
+
+// Pack is an installed policy pack
+type Pack struct {
+	Name        string     `yaml:"name"`
+	Version     string     `yaml:"version"`
+	Thresholds  Thresholds `yaml:"thresholds"`
+	Source      string     `yaml:"-"`
+	Dir         string     `yaml:"-"`
+	PolicyFiles []string   `yaml:"-"`
+}
+
+func (p *Pack) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+
+	return fmt.Sprintf("%s@%s", p.Name, p.Version)
+}
+
+// LoadPack loads the policy pack installed in dir
+func LoadPack(dir string) (*Pack, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, packManifestFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Policy pack in %s has no %s", dir, packManifestFile)
+	}
+
+	p := &Pack{}
+
+	err = yaml.UnmarshalStrict(b, p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing %s", packManifestFile)
+	}
+
+	if p.Name == "" {
+		return nil, errors.Errorf("Policy pack in %s must have a name", dir)
+	}
+
+	p.Dir = dir
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		for _, ext := range policyFileExts {
+			if !info.IsDir() && filepath.Ext(path) == ext {
+				rel, _ := filepath.Rel(dir, path)
+				p.PolicyFiles = append(p.PolicyFiles, rel)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading policy pack %s", p.Name)
+	}
+
+	sort.Strings(p.PolicyFiles)
+
+	return p, nil
+}
@@ -0,0 +1,199 @@
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infracost/infracost/internal/output"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testManifest = `name: finops
+version: 1.2.0
+thresholds:
+  max_monthly_cost: 100
+  max_monthly_cost_increase_percent: 20
+`
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+func TestParseOCIReference(t *testing.T) {
+	ref, err := parseOCIReference("ghcr.io/org/finops-pack:1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, ociReference{Host: "ghcr.io", Repository: "org/finops-pack", Reference: "1.2.0"}, ref)
+
+	ref, err = parseOCIReference("localhost:5000/finops-pack")
+	require.NoError(t, err)
+	assert.Equal(t, ociReference{Host: "localhost:5000", Repository: "finops-pack", Reference: "latest"}, ref)
+
+	ref, err = parseOCIReference("ghcr.io/org/finops-pack@sha256:abc")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abc", ref.Reference)
+
+	_, err = parseOCIReference("ghcr.io")
+	assert.Error(t, err)
+}
+
+func testPackTar(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range map[string]string{
+		"infracost-policy.yml":   testManifest,
+		"policies/tagging.rego":  "package infracost",
+		"policies/instances.cel": "true",
+		"policies/README.md":     "docs",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func TestExtractTarAndLoadPack(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pack")
+	require.NoError(t, extractTar(testPackTar(t), dir))
+
+	p, err := LoadPack(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "finops@1.2.0", p.String())
+	assert.Equal(t, float64(100), *p.Thresholds.MaxMonthlyCost)
+	assert.Equal(t, []string{"policies/instances.cel", "policies/tagging.rego"}, p.PolicyFiles)
+}
+
+func TestInstallOCI(t *testing.T) {
+	blob := testPackTar(t)
+	h := sha256.Sum256(blob)
+	digest := "sha256:" + hex.EncodeToString(h[:])
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/org/finops-pack/manifests/1.2.0":
+			fmt.Fprintf(w, `{"layers": [{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": "%s"}]}`, digest)
+		case "/v2/org/finops-pack/blobs/" + digest:
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	source := strings.TrimPrefix(srv.URL, "https://") + "/org/finops-pack:1.2.0"
+
+	// The registry's certificate is only trusted by its own client, which stands in for the
+	// client with the configured CA certificates
+	packDir, err := installOCI(source, t.TempDir(), srv.Client())
+	require.NoError(t, err)
+
+	p, err := LoadPack(packDir)
+	require.NoError(t, err)
+	assert.Equal(t, "finops@1.2.0", p.String())
+
+	_, err = installOCI(source, t.TempDir(), &http.Client{})
+	assert.Error(t, err)
+}
+
+func TestExtractTarInvalidPath(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0600, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	assert.Error(t, extractTar(buf.Bytes(), filepath.Join(t.TempDir(), "pack")))
+}
+
+func TestInstallGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "finops"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoDir, "finops", "infracost-policy.yml"), []byte(testManifest), 0600))
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Add pack"},
+		{"tag", "v1.2.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	cacheDir := t.TempDir()
+	source := "git::file://" + repoDir + "//finops?ref=v1.2.0"
+
+	p, err := Install(source, cacheDir, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "finops@1.2.0", p.String())
+	assert.Equal(t, source, p.Source)
+
+	// The second install should use the cached pack
+	p, err = Install(source, cacheDir, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "finops", p.Name)
+}
+
+func TestCheckThresholds(t *testing.T) {
+	maxCost := 100.0
+	maxIncreasePercent := 20.0
+
+	packs := []*Pack{
+		{
+			Name:    "finops",
+			Version: "1.2.0",
+			Thresholds: Thresholds{
+				MaxMonthlyCost:                &maxCost,
+				MaxMonthlyCostIncreasePercent: &maxIncreasePercent,
+			},
+		},
+	}
+
+	projects := []output.Project{
+		{
+			Name:          "prod",
+			PastBreakdown: &output.Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(100))},
+			Breakdown:     &output.Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150))},
+			Diff:          &output.Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(50))},
+		},
+		{
+			Name:      "dev",
+			Breakdown: &output.Breakdown{TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+		},
+	}
+
+	violations := CheckThresholds(packs, projects, "USD")
+	require.Len(t, violations, 2)
+	assert.Equal(t, "prod (finops@1.2.0): monthly cost $150.00 is over the maximum of $100.00", violations[0].String())
+	assert.Equal(t, "prod (finops@1.2.0): monthly cost increase of 50% is over the maximum of 20%", violations[1].String())
+
+	violations = CheckThresholds(packs, projects, "EUR")
+	require.Len(t, violations, 2)
+	assert.Equal(t, "prod (finops@1.2.0): monthly cost €150.00 is over the maximum of €100.00", violations[0].String())
+}
@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/output"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// Violation is a project that is over one of a policy pack's thresholds
+type Violation struct {
+	Pack    string
+	Project string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (%s): %s", v.Project, v.Pack, v.Message)
+}
+
+// LoadPacks installs and loads the policy packs referenced in the config, downloading them
+// with the HTTP client.
+func LoadPacks(packCfgs []*config.PolicyPack, httpClient *http.Client) ([]*Pack, error) {
+	cacheDir := filepath.Join(config.UserCacheDir(), "policy_packs")
+	packs := make([]*Pack, 0, len(packCfgs))
+
+	for _, c := range packCfgs {
+		p, err := Install(c.Source, cacheDir, httpClient)
+		if err != nil {
+			return packs, err
+		}
+
+		if len(p.PolicyFiles) > 0 {
+			log.Warnf("Policy pack %s contains OPA/CEL policies which are not evaluated yet, only its thresholds are checked", p)
+		}
+
+		packs = append(packs, p)
+	}
+
+	return packs, nil
+}
+
+// CheckThresholds returns the projects that are over the thresholds of the policy packs. The
+// costs in the messages are formatted in the currency of the projects' costs.
+func CheckThresholds(packs []*Pack, projects []output.Project, currency string) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, pack := range packs {
+		t := pack.Thresholds
+
+		for _, project := range projects {
+			var cost, pastCost, diff *decimal.Decimal
+
+			if project.Breakdown != nil {
+				cost = project.Breakdown.TotalMonthlyCost
+			}
+			if project.PastBreakdown != nil {
+				pastCost = project.PastBreakdown.TotalMonthlyCost
+			}
+			if project.Diff != nil {
+				diff = project.Diff.TotalMonthlyCost
+			}
+
+			add := func(msg string, a ...interface{}) {
+				violations = append(violations, Violation{
+					Pack:    pack.String(),
+					Project: project.Name,
+					Message: fmt.Sprintf(msg, a...),
+				})
+			}
+
+			if t.MaxMonthlyCost != nil && cost != nil && cost.GreaterThan(decimal.NewFromFloat(*t.MaxMonthlyCost)) {
+				maxCost := decimal.NewFromFloat(*t.MaxMonthlyCost)
+				add("monthly cost %s is over the maximum of %s", output.FormatCost2DP(currency, cost), output.FormatCost2DP(currency, &maxCost))
+			}
+
+			if t.MaxMonthlyCostIncrease != nil && diff != nil && diff.GreaterThan(decimal.NewFromFloat(*t.MaxMonthlyCostIncrease)) {
+				maxIncrease := decimal.NewFromFloat(*t.MaxMonthlyCostIncrease)
+				add("monthly cost increase %s is over the maximum of %s", output.FormatCost2DP(currency, diff), output.FormatCost2DP(currency, &maxIncrease))
+			}
+
+			if t.MaxMonthlyCostIncreasePercent != nil && diff != nil && pastCost != nil && pastCost.IsPositive() {
+				percent := diff.Div(*pastCost).Mul(decimal.NewFromInt(100))
+				if percent.GreaterThan(decimal.NewFromFloat(*t.MaxMonthlyCostIncreasePercent)) {
+					add("monthly cost increase of %s%% is over the maximum of %s%%", percent.StringFixed(0), decimal.NewFromFloat(*t.MaxMonthlyCostIncreasePercent).String())
+				}
+			}
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,268 @@
+package prices
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// The general-purpose discounts of resource-based commitments by term
+var committedUseDiscountPercents = map[string]float64{
+	"1_year": 37,
+	"3_year": 55,
+}
+
+// vcpuMemoryPriceRatio is how many GB of memory cost the same as one vCPU for the
+// general-purpose machine types. Instances are priced per machine type rather than per
+// vCPU and GB, so this is used to split their price when only some of their vCPUs or
+// memory are covered by a commitment.
+var vcpuMemoryPriceRatio = decimal.NewFromFloat(7.5)
+
+// memoryPerVCPU is the GB of memory per vCPU of the predefined machine types by machine
+// series and class. Shared-core machine types aren't eligible for commitments.
+var memoryPerVCPU = map[string]map[string]float64{
+	"n1":  {"standard": 3.75, "highmem": 6.5, "highcpu": 0.9},
+	"n2":  {"standard": 4, "highmem": 8, "highcpu": 1},
+	"n2d": {"standard": 4, "highmem": 8, "highcpu": 1},
+	"e2":  {"standard": 4, "highmem": 8, "highcpu": 1},
+	"c2":  {"standard": 4},
+	"c2d": {"standard": 4, "highmem": 8, "highcpu": 2},
+	"t2d": {"standard": 4},
+}
+
+// committedUseCost tracks how much of a cost component's vCPUs and memory are covered by
+// commitments. The vCPUs and memory are averaged over the month, so an instance that only
+// runs for some of the month's hours uses that share of a commitment.
+type committedUseCost struct {
+	// hourlyQuantity is the average number of instances running over the month
+	hourlyQuantity decimal.Decimal
+	vcpus        decimal.Decimal
+	memoryGB     decimal.Decimal
+	coveredVCPUs decimal.Decimal
+	coveredMemGB decimal.Decimal
+	// coveredRate is the sum of the covered fraction of the instance multiplied by the
+	// rate of the commitment that covers it
+	coveredFraction decimal.Decimal
+	coveredRate     decimal.Decimal
+}
+
+// ApplyCommittedUseDiscounts covers the vCPUs and memory of the on-demand Compute Engine
+// and GKE instances of the project with the GCP commitments, and adds the coverage of
+// each commitment to the project. The covered usage is priced at the commitment's
+// discount, and usage above the commitments stays on-demand with sustained use discounts.
+// The costs of the project must already be calculated.
+func ApplyCommittedUseDiscounts(project *schema.Project, commitments []*config.CommittedUseDiscount) {
+	commitments = validCommittedUseDiscounts(commitments)
+	if len(commitments) == 0 {
+		return
+	}
+
+	applyCommittedUseDiscounts(project.PastResources, commitments)
+	project.CommittedUseDiscounts = applyCommittedUseDiscounts(project.Resources, commitments)
+
+	schema.CalculateCosts(project)
+}
+
+func validCommittedUseDiscounts(commitments []*config.CommittedUseDiscount) []*config.CommittedUseDiscount {
+	valid := make([]*config.CommittedUseDiscount, 0, len(commitments))
+
+	for _, c := range commitments {
+		switch {
+		case c.Region == "":
+			log.Warnf("Committed use discounts require a region, ignoring commitment")
+		case committedUseDiscountPercents[c.Term] == 0:
+			log.Warnf("Invalid term for %s committed use discount, ignoring commitment. Expected: 1_year, 3_year. Got: %s", c.Region, c.Term)
+		case c.VCPUs < 0 || c.MemoryGB < 0 || (c.VCPUs == 0 && c.MemoryGB == 0):
+			log.Warnf("Committed use discount for %s must have vcpus or memory_gb, ignoring commitment", c.Region)
+		case c.DiscountPercent < 0 || c.DiscountPercent >= 100:
+			log.Warnf("Invalid discount_percent for %s committed use discount, ignoring commitment. Expected a number between 0 and 100. Got: %v", c.Region, c.DiscountPercent)
+		default:
+			valid = append(valid, c)
+		}
+	}
+
+	return valid
+}
+
+func applyCommittedUseDiscounts(resources []*schema.Resource, commitments []*config.CommittedUseDiscount) []*schema.CommittedUseDiscountCoverage {
+	var components []*schema.CostComponent
+	for _, r := range resources {
+		components = append(components, flattenCostComponents(r)...)
+	}
+
+	costs := make(map[*schema.CostComponent]*committedUseCost)
+	for _, c := range components {
+		if cost := newCommittedUseCost(c); cost != nil {
+			costs[c] = cost
+		}
+	}
+
+	coverages := make([]*schema.CommittedUseDiscountCoverage, 0, len(commitments))
+
+	for _, commitment := range commitments {
+		discountPercent := commitment.DiscountPercent
+		if discountPercent == 0 {
+			discountPercent = committedUseDiscountPercents[commitment.Term]
+		}
+		discount := decimal.NewFromFloat(discountPercent).Div(decimal.NewFromInt(100))
+
+		coverage := &schema.CommittedUseDiscountCoverage{
+			Region:        commitment.Region,
+			Term:          commitment.Term,
+			MachineSeries: commitment.MachineSeries,
+			VCPUs:         decimal.NewFromFloat(commitment.VCPUs),
+			MemoryGB:      decimal.NewFromFloat(commitment.MemoryGB),
+		}
+		leftVCPUs := coverage.VCPUs
+		leftMemGB := coverage.MemoryGB
+		savings := decimal.Zero
+
+		for _, c := range components {
+			cost := costs[c]
+			if cost == nil || !committedUseDiscountEligible(commitment, c) {
+				continue
+			}
+
+			vcpus := decimal.Min(leftVCPUs, cost.vcpus.Sub(cost.coveredVCPUs))
+			memGB := decimal.Min(leftMemGB, cost.memoryGB.Sub(cost.coveredMemGB))
+			leftVCPUs = leftVCPUs.Sub(vcpus)
+			leftMemGB = leftMemGB.Sub(memGB)
+			cost.coveredVCPUs = cost.coveredVCPUs.Add(vcpus)
+			cost.coveredMemGB = cost.coveredMemGB.Add(memGB)
+
+			coverage.CoveredVCPUs = coverage.CoveredVCPUs.Add(vcpus)
+			coverage.CoveredMemoryGB = coverage.CoveredMemoryGB.Add(memGB)
+			coverage.OverflowVCPUs = coverage.OverflowVCPUs.Add(cost.vcpus.Sub(cost.coveredVCPUs))
+			coverage.OverflowMemoryGB = coverage.OverflowMemoryGB.Add(cost.memoryGB.Sub(cost.coveredMemGB))
+
+			fraction := cost.fraction(vcpus, memGB)
+			cost.coveredFraction = cost.coveredFraction.Add(fraction)
+			cost.coveredRate = cost.coveredRate.Add(fraction.Mul(decimal.NewFromInt(1).Sub(discount)))
+
+			// The covered usage would otherwise have had the sustained use discount
+			sustainedUseDiscount := decimal.NewFromFloat(c.MonthlyDiscountPerc)
+			listCost := c.Price().Mul(cost.hourlyQuantity).Mul(schema.HourToMonthUnitMultiplier)
+			savings = savings.Add(listCost.Mul(fraction).Mul(discount.Sub(sustainedUseDiscount)))
+		}
+
+		coverage.Savings = &savings
+		coverages = append(coverages, coverage)
+	}
+
+	for c, cost := range costs {
+		if cost.coveredFraction.IsZero() {
+			continue
+		}
+
+		// The hourly costs don't include sustained use discounts, so the price is set from
+		// the commitment rates and the monthly discount is adjusted so that only the
+		// on-demand usage has the sustained use discount
+		onDemand := decimal.NewFromInt(1).Sub(cost.coveredFraction)
+		priceRate := cost.coveredRate.Add(onDemand)
+		monthlyRate := cost.coveredRate.Add(onDemand.Mul(decimal.NewFromFloat(1 - c.MonthlyDiscountPerc)))
+
+		c.SetPrice(c.Price().Mul(priceRate))
+		c.MonthlyDiscountPerc, _ = decimal.NewFromInt(1).Sub(monthlyRate.Div(priceRate)).Float64()
+	}
+
+	return coverages
+}
+
+// newCommittedUseCost returns the vCPUs and memory of an on-demand Compute Engine instance
+// cost component, or nil if it isn't eligible for commitments. Instances with monthly_hrs
+// only have a monthly quantity, so their vCPUs and memory are prorated over the month.
+func newCommittedUseCost(c *schema.CostComponent) *committedUseCost {
+	var hourlyQuantity decimal.Decimal
+	switch {
+	case c.HourlyQuantity != nil:
+		hourlyQuantity = *c.HourlyQuantity
+	case c.MonthlyQuantity != nil:
+		hourlyQuantity = c.MonthlyQuantity.Div(schema.HourToMonthUnitMultiplier)
+	}
+
+	if !hourlyQuantity.IsPositive() || c.PurchaseOption != "on_demand" ||
+		c.ProductFilter == nil || c.ProductFilter.Service == nil || c.ProductFilter.ProductFamily == nil ||
+		*c.ProductFilter.Service != "Compute Engine" || *c.ProductFilter.ProductFamily != "Compute Instance" {
+		return nil
+	}
+
+	vcpus, memGB, ok := machineTypeResources(componentMachineType(c))
+	if !ok {
+		return nil
+	}
+
+	return &committedUseCost{
+		hourlyQuantity: hourlyQuantity,
+		vcpus:          decimal.NewFromFloat(vcpus).Mul(hourlyQuantity),
+		memoryGB:       decimal.NewFromFloat(memGB).Mul(hourlyQuantity),
+	}
+}
+
+// fraction returns the fraction of the instance price that the vCPUs and memory make up
+func (c *committedUseCost) fraction(vcpus, memGB decimal.Decimal) decimal.Decimal {
+	total := c.vcpus.Mul(vcpuMemoryPriceRatio).Add(c.memoryGB)
+	if total.IsZero() {
+		return decimal.Zero
+	}
+
+	return vcpus.Mul(vcpuMemoryPriceRatio).Add(memGB).Div(total)
+}
+
+func committedUseDiscountEligible(commitment *config.CommittedUseDiscount, c *schema.CostComponent) bool {
+	if c.ProductFilter.Region == nil || *c.ProductFilter.Region != commitment.Region {
+		return false
+	}
+
+	if commitment.MachineSeries == "" {
+		return true
+	}
+
+	series := strings.SplitN(componentMachineType(c), "-", 2)[0]
+	return strings.EqualFold(series, commitment.MachineSeries)
+}
+
+// componentMachineType returns the machine type from the product filter of a Compute
+// Engine instance cost component, which is matched with a case-insensitive regex.
+func componentMachineType(c *schema.CostComponent) string {
+	for _, f := range c.ProductFilter.AttributeFilters {
+		if f.Key != "machineType" {
+			continue
+		}
+
+		if f.Value != nil {
+			return strings.ToLower(*f.Value)
+		}
+
+		if f.ValueRegex != nil {
+			return strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(*f.ValueRegex, "/"), "/i"))
+		}
+	}
+
+	return ""
+}
+
+// machineTypeResources returns the vCPUs and GB of memory of a predefined machine type,
+// e.g. n1-standard-4 has 4 vCPUs and 15 GB
+func machineTypeResources(machineType string) (float64, float64, bool) {
+	parts := strings.Split(machineType, "-")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+
+	memPerVCPU, ok := memoryPerVCPU[parts[0]][parts[1]]
+	if !ok {
+		return 0, 0, false
+	}
+
+	vcpus, err := strconv.Atoi(parts[2])
+	if err != nil || vcpus <= 0 {
+		return 0, 0, false
+	}
+
+	return float64(vcpus), float64(vcpus) * memPerVCPU, true
+}
@@ -0,0 +1,121 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGCEComponent(region, machineType, purchaseOption string, monthlyDiscountPerc float64) *schema.CostComponent {
+	c := testCostComponent("Compute Engine", "Compute Instance", purchaseOption, region, "", 1)
+	regex := "/" + machineType + "/i"
+	c.ProductFilter.AttributeFilters = []*schema.AttributeFilter{{Key: "machineType", ValueRegex: &regex}}
+	c.MonthlyDiscountPerc = monthlyDiscountPerc
+
+	return c
+}
+
+func TestMachineTypeResources(t *testing.T) {
+	vcpus, memGB, ok := machineTypeResources("n1-standard-4")
+	assert.True(t, ok)
+	assert.Equal(t, 4.0, vcpus)
+	assert.Equal(t, 15.0, memGB)
+
+	vcpus, memGB, ok = machineTypeResources("e2-highcpu-8")
+	assert.True(t, ok)
+	assert.Equal(t, 8.0, vcpus)
+	assert.Equal(t, 8.0, memGB)
+
+	for _, m := range []string{"e2-medium", "f1-micro", "custom-2-4096", "m1-ultramem-40"} {
+		_, _, ok = machineTypeResources(m)
+		assert.False(t, ok, m)
+	}
+}
+
+func TestApplyCommittedUseDiscounts(t *testing.T) {
+	// n1-standard-4 has 4 vCPUs and 15 GB
+	covered := testGCEComponent("us-central1", "n1-standard-4", "on_demand", 0.3)
+	overflow := testGCEComponent("us-central1", "n1-standard-4", "on_demand", 0.3)
+	otherRegion := testGCEComponent("europe-west1", "n1-standard-4", "on_demand", 0.3)
+	preemptible := testGCEComponent("us-central1", "n1-standard-4", "preemptible", 0)
+	n2 := testGCEComponent("us-central1", "n2-standard-4", "on_demand", 0.2)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "google_compute_instance.covered", CostComponents: []*schema.CostComponent{covered}},
+			{Name: "google_compute_instance.overflow", CostComponents: []*schema.CostComponent{overflow}},
+			{Name: "google_compute_instance.other_region", CostComponents: []*schema.CostComponent{otherRegion}},
+			{Name: "google_compute_instance.preemptible", CostComponents: []*schema.CostComponent{preemptible}},
+			{Name: "google_compute_instance.n2", CostComponents: []*schema.CostComponent{n2}},
+		},
+	}
+	schema.CalculateCosts(project)
+
+	ApplyCommittedUseDiscounts(project, []*config.CommittedUseDiscount{
+		{Region: "us-central1", Term: "1_year", VCPUs: 6, MemoryGB: 22.5, MachineSeries: "n1"},
+		{Region: "us-central1", Term: "2_year", VCPUs: 4},
+	})
+
+	// The first instance is fully covered at the 1 year rate with no sustained use discount
+	assert.Equal(t, "0.63", covered.HourlyCost.String())
+	assert.Equal(t, "459.9", covered.MonthlyCost.Round(6).String())
+
+	// Half of the second instance is covered, the rest is on-demand with the sustained use discount
+	assert.Equal(t, "0.815", overflow.HourlyCost.String())
+	assert.Equal(t, "485.45", overflow.MonthlyCost.Round(6).String())
+
+	assert.Equal(t, "1", otherRegion.HourlyCost.String())
+	assert.Equal(t, "1", preemptible.HourlyCost.String())
+	assert.Equal(t, "1", n2.HourlyCost.String())
+
+	if assert.Len(t, project.CommittedUseDiscounts, 1) {
+		c := project.CommittedUseDiscounts[0]
+		assert.Equal(t, "6", c.CoveredVCPUs.String())
+		assert.Equal(t, "22.5", c.CoveredMemoryGB.String())
+		assert.Equal(t, "2", c.OverflowVCPUs.String())
+		assert.Equal(t, "7.5", c.OverflowMemoryGB.String())
+		// 1.5 instances covered at a 37% discount instead of the 30% sustained use discount
+		assert.Equal(t, "76.65", c.Savings.Round(6).String())
+	}
+}
+
+func TestApplyCommittedUseDiscountsMonthlyHours(t *testing.T) {
+	// Instances with monthly_hrs only have a monthly quantity, so this one uses half of the
+	// commitment's vCPUs and memory over the month
+	partTime := testGCEComponent("us-central1", "n1-standard-4", "on_demand", 0)
+	monthlyHours := decimal.NewFromInt(365)
+	partTime.HourlyQuantity = nil
+	partTime.MonthlyQuantity = &monthlyHours
+	fullTime := testGCEComponent("us-central1", "n1-standard-4", "on_demand", 0.3)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "google_compute_instance.part_time", CostComponents: []*schema.CostComponent{partTime}},
+			{Name: "google_compute_instance.full_time", CostComponents: []*schema.CostComponent{fullTime}},
+		},
+	}
+
+	ApplyCommittedUseDiscounts(project, []*config.CommittedUseDiscount{
+		{Region: "us-central1", Term: "1_year", VCPUs: 4, MemoryGB: 15, MachineSeries: "n1"},
+	})
+
+	assert.Equal(t, "0.63", partTime.Price().String())
+	assert.Equal(t, "229.95", partTime.MonthlyCost.Round(6).String())
+
+	// The rest of the commitment covers half of the full-time instance
+	assert.Equal(t, "0.815", fullTime.HourlyCost.String())
+	assert.Equal(t, "485.45", fullTime.MonthlyCost.Round(6).String())
+
+	if assert.Len(t, project.CommittedUseDiscounts, 1) {
+		c := project.CommittedUseDiscounts[0]
+		assert.Equal(t, "4", c.CoveredVCPUs.String())
+		assert.Equal(t, "15", c.CoveredMemoryGB.String())
+		assert.Equal(t, "2", c.OverflowVCPUs.String())
+		assert.Equal(t, "7.5", c.OverflowMemoryGB.String())
+		// 365 hours covered instead of on-demand, and 365 hours at 37% instead of 30%
+		assert.Equal(t, "160.6", c.Savings.Round(6).String())
+	}
+}
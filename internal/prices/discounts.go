@@ -0,0 +1,77 @@
+package prices
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// ApplyDiscounts reduces the prices of the cost components of the project by the first
+// discount in the list that matches them, so the costs reflect negotiated discounts
+// instead of list prices. The costs of the project are recalculated afterwards.
+func ApplyDiscounts(project *schema.Project, discounts []*config.Discount) {
+	if len(discounts) == 0 {
+		return
+	}
+
+	for _, r := range project.AllResources() {
+		applyResourceDiscounts(r, r.ResourceType, discounts)
+	}
+
+	schema.CalculateCosts(project)
+}
+
+// applyResourceDiscounts applies the discounts to the cost components of the resource and
+// its sub-resources. Sub-resources are matched using the type of their parent resource.
+func applyResourceDiscounts(r *schema.Resource, resourceType string, discounts []*config.Discount) {
+	for _, c := range r.CostComponents {
+		d := matchDiscount(discounts, resourceType, c)
+		if d == nil {
+			continue
+		}
+
+		log.Debugf("Applying %v%% discount to %s %s", d.Percent, r.Name, c.Name)
+
+		multiplier := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(d.Percent).Div(decimal.NewFromInt(100)))
+		c.SetPrice(c.Price().Mul(multiplier))
+	}
+
+	for _, s := range r.SubResources {
+		applyResourceDiscounts(s, resourceType, discounts)
+	}
+}
+
+func matchDiscount(discounts []*config.Discount, resourceType string, c *schema.CostComponent) *config.Discount {
+	var service, region string
+	if c.ProductFilter != nil {
+		if c.ProductFilter.Service != nil {
+			service = *c.ProductFilter.Service
+		}
+		if c.ProductFilter.Region != nil {
+			region = *c.ProductFilter.Region
+		}
+	}
+
+	for _, d := range discounts {
+		if d.Provider != "" && !strings.HasPrefix(resourceType, d.Provider+"_") {
+			continue
+		}
+		if d.Service != "" && !strings.EqualFold(d.Service, service) {
+			continue
+		}
+		if d.Region != "" && d.Region != region {
+			continue
+		}
+		if d.ResourceType != "" && d.ResourceType != resourceType {
+			continue
+		}
+
+		return d
+	}
+
+	return nil
+}
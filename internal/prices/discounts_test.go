@@ -0,0 +1,48 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDiscounts(t *testing.T) {
+	ec2 := testCostComponent("AmazonEC2", "Compute Instance", "on_demand", "us-east-1", "m5.large", 1)
+	ebs := testCostComponent("AmazonEC2", "Storage", "", "us-east-1", "", 1)
+	rds := testCostComponent("AmazonRDS", "Database Instance", "on_demand", "eu-west-1", "db.m5.large", 1)
+	gcp := testCostComponent("Compute Engine", "Compute Instance", "on_demand", "us-central1", "n1-standard-1", 1)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{
+				Name:           "aws_instance.web",
+				ResourceType:   "aws_instance",
+				CostComponents: []*schema.CostComponent{ec2},
+				SubResources: []*schema.Resource{
+					{Name: "root_block_device", CostComponents: []*schema.CostComponent{ebs}},
+				},
+			},
+			{Name: "aws_db_instance.db", ResourceType: "aws_db_instance", CostComponents: []*schema.CostComponent{rds}},
+			{Name: "google_compute_instance.vm", ResourceType: "google_compute_instance", CostComponents: []*schema.CostComponent{gcp}},
+		},
+	}
+
+	ApplyDiscounts(project, []*config.Discount{
+		{Provider: "aws", Region: "eu-west-1", Percent: 50},
+		{ResourceType: "aws_instance", Percent: 20},
+		{Provider: "aws", Percent: 10},
+	})
+
+	// The first matching discount is applied and sub-resources use the type of their parent
+	assert.Equal(t, "0.8", ec2.Price().String())
+	assert.Equal(t, "0.8", ebs.Price().String())
+	assert.Equal(t, "0.5", rds.Price().String())
+	assert.Equal(t, "1", gcp.Price().String())
+	assert.Equal(t, "1.6", project.Resources[0].HourlyCost.String())
+
+	ApplyDiscounts(project, []*config.Discount{{Service: "compute engine", Percent: 100}})
+	assert.True(t, gcp.Price().Equal(decimal.Zero))
+}
@@ -0,0 +1,147 @@
+package prices
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const priceOverridesFileVersion = "0.1"
+
+// PriceOverride replaces or adjusts the price of the cost components that match it, e.g.
+// for internal chargeback rates or private marketplace pricing.
+type PriceOverride struct {
+	ResourceType string `yaml:"resource_type"`
+	// CostComponent is the name of the cost component, * matches any characters
+	CostComponent string `yaml:"cost_component"`
+	// Region only matches the cost components in this region if it is set
+	Region string `yaml:"region,omitempty"`
+	// Price replaces the price per unit shown in the output
+	Price *float64 `yaml:"price,omitempty"`
+	// Multiplier adjusts the price, e.g. 1.1 adds 10%
+	Multiplier *float64 `yaml:"multiplier,omitempty"`
+
+	costComponentRegex *regexp.Regexp
+}
+
+type priceOverridesFile struct {
+	Version        string           `yaml:"version"`
+	PriceOverrides []*PriceOverride `yaml:"price_overrides"`
+}
+
+// LoadPriceOverrides loads the price overrides from the YAML file at filename. No overrides
+// are returned if filename is empty.
+func LoadPriceOverrides(filename string) ([]*PriceOverride, error) {
+	if filename == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading price overrides file")
+	}
+
+	var f priceOverridesFile
+	err = yaml.Unmarshal(b, &f)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing price overrides file")
+	}
+
+	if f.Version != priceOverridesFileVersion {
+		return nil, fmt.Errorf("Invalid price overrides file version. Supported versions are %s", priceOverridesFileVersion)
+	}
+
+	for i, o := range f.PriceOverrides {
+		if o.ResourceType == "" || o.CostComponent == "" {
+			return nil, fmt.Errorf("Price override %d must have a resource_type and cost_component", i+1)
+		}
+
+		if (o.Price == nil) == (o.Multiplier == nil) {
+			return nil, fmt.Errorf("Price override %d for %s must have either a price or a multiplier", i+1, o.ResourceType)
+		}
+
+		o.costComponentRegex = costComponentPatternRegex(o.CostComponent)
+	}
+
+	return f.PriceOverrides, nil
+}
+
+// ApplyPriceOverrides sets the prices of the cost components of the project using the
+// first override that matches them. The costs of the project are recalculated afterwards.
+func ApplyPriceOverrides(project *schema.Project, overrides []*PriceOverride) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	for _, r := range project.AllResources() {
+		applyResourcePriceOverrides(r, r.ResourceType, overrides)
+	}
+
+	schema.CalculateCosts(project)
+}
+
+// applyResourcePriceOverrides applies the overrides to the cost components of the resource
+// and its sub-resources. Sub-resources are matched using the type of their parent resource.
+func applyResourcePriceOverrides(r *schema.Resource, resourceType string, overrides []*PriceOverride) {
+	for _, c := range r.CostComponents {
+		o := matchPriceOverride(overrides, resourceType, c)
+		if o == nil {
+			continue
+		}
+
+		log.Debugf("Overriding price of %s %s", r.Name, c.Name)
+
+		if o.Price != nil {
+			price := decimal.NewFromFloat(*o.Price)
+			if !c.UnitMultiplier.IsZero() {
+				price = price.Div(c.UnitMultiplier)
+			}
+			c.SetPrice(price)
+		} else {
+			c.SetPrice(c.Price().Mul(decimal.NewFromFloat(*o.Multiplier)))
+		}
+	}
+
+	for _, s := range r.SubResources {
+		applyResourcePriceOverrides(s, resourceType, overrides)
+	}
+}
+
+// costComponentPatternRegex returns a regex for the pattern where * matches any characters.
+// Cost component names often contain / so path.Match can't be used.
+func costComponentPatternRegex(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func matchPriceOverride(overrides []*PriceOverride, resourceType string, c *schema.CostComponent) *PriceOverride {
+	for _, o := range overrides {
+		if o.ResourceType != resourceType {
+			continue
+		}
+
+		if !o.costComponentRegex.MatchString(c.Name) {
+			continue
+		}
+
+		if o.Region != "" && (c.ProductFilter == nil || c.ProductFilter.Region == nil || *c.ProductFilter.Region != o.Region) {
+			continue
+		}
+
+		return o
+	}
+
+	return nil
+}
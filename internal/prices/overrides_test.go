@@ -0,0 +1,95 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePriceOverrides(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "price_overrides.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+
+	return filename
+}
+
+func TestLoadPriceOverrides(t *testing.T) {
+	overrides, err := LoadPriceOverrides("")
+	assert.NoError(t, err)
+	assert.Nil(t, overrides)
+
+	overrides, err = LoadPriceOverrides("../../infracost-price-overrides-example.yml")
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 3)
+
+	_, err = LoadPriceOverrides(writePriceOverrides(t, "version: 0.2\n"))
+	assert.EqualError(t, err, "Invalid price overrides file version. Supported versions are 0.1")
+
+	_, err = LoadPriceOverrides(writePriceOverrides(t, `version: 0.1
+price_overrides:
+  - resource_type: aws_instance
+    cost_component: Instance usage*
+    price: 0.1
+    multiplier: 2
+`))
+	assert.EqualError(t, err, "Price override 1 for aws_instance must have either a price or a multiplier")
+
+	_, err = LoadPriceOverrides(writePriceOverrides(t, `version: 0.1
+price_overrides:
+  - cost_component: Instance usage*
+    price: 0.1
+`))
+	assert.EqualError(t, err, "Price override 1 must have a resource_type and cost_component")
+}
+
+func TestApplyPriceOverrides(t *testing.T) {
+	overrides, err := LoadPriceOverrides(writePriceOverrides(t, `version: 0.1
+price_overrides:
+  - resource_type: aws_instance
+    cost_component: Instance usage (Linux/UNIX, on-demand, m5.large)
+    region: eu-west-1
+    price: 0.5
+  - resource_type: aws_instance
+    cost_component: Instance usage*
+    multiplier: 2
+  - resource_type: aws_instance
+    cost_component: Storage*
+    price: 0.2
+`))
+	require.NoError(t, err)
+
+	euInstance := testCostComponent("AmazonEC2", "Compute Instance", "on_demand", "eu-west-1", "m5.large", 1)
+	euInstance.Name = "Instance usage (Linux/UNIX, on-demand, m5.large)"
+	usInstance := testCostComponent("AmazonEC2", "Compute Instance", "on_demand", "us-east-1", "m5.large", 1)
+	usInstance.Name = "Instance usage (Linux/UNIX, on-demand, m5.large)"
+	storage := testCostComponent("AmazonEC2", "Storage", "", "us-east-1", "", 1)
+	storage.Name = "Storage (general purpose SSD, gp2)"
+	storage.UnitMultiplier = decimal.NewFromInt(1000)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.eu", ResourceType: "aws_instance", CostComponents: []*schema.CostComponent{euInstance}},
+			{
+				Name:           "aws_instance.us",
+				ResourceType:   "aws_instance",
+				CostComponents: []*schema.CostComponent{usInstance},
+				SubResources: []*schema.Resource{
+					{Name: "root_block_device", CostComponents: []*schema.CostComponent{storage}},
+				},
+			},
+		},
+	}
+
+	ApplyPriceOverrides(project, overrides)
+
+	assert.Equal(t, "0.5", euInstance.Price().String())
+	assert.Equal(t, "2", usInstance.Price().String())
+	// The override price is the price per unit shown in the output
+	assert.Equal(t, "0.2", storage.UnitMultiplierPrice().String())
+	assert.Equal(t, "0.5", project.Resources[0].HourlyCost.String())
+}
@@ -1,10 +1,12 @@
 package prices
 
 import (
-	"runtime"
+	"fmt"
+	"time"
 
 	"github.com/infracost/infracost/internal/apiclient"
 	"github.com/infracost/infracost/internal/config"
+	awsresources "github.com/infracost/infracost/internal/resources/aws"
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/shopspring/decimal"
@@ -15,75 +17,196 @@ import (
 func PopulatePrices(cfg *config.Config, project *schema.Project) error {
 	resources := project.AllResources()
 
-	c := apiclient.NewPricingAPIClient(cfg)
+	c, err := apiclient.NewPricingAPIClient(cfg)
+	if err != nil {
+		return err
+	}
 
-	err := GetPricesConcurrent(c, resources)
+	err = GetPrices(c, resources, cfg.FallbackRegions)
 	if err != nil {
 		return err
 	}
+
+	if cfg.PriceMaxAge > 0 {
+		checkPriceAges(resources, cfg.PriceMaxAge, time.Now())
+	}
+
 	return nil
 }
 
-// GetPricesConcurrent gets the prices of all resources concurrently.
-// Concurrency level is calculated using the following formula:
-// max(min(4, numCPU * 4), 16)
-func GetPricesConcurrent(c *apiclient.PricingAPIClient, resources []*schema.Resource) error {
-	// Set the number of workers
-	numWorkers := 4
-	numCPU := runtime.NumCPU()
-	if numCPU*4 > numWorkers {
-		numWorkers = numCPU * 4
-	}
-	if numWorkers > 16 {
-		numWorkers = 16
-	}
-	numJobs := len(resources)
-	jobs := make(chan *schema.Resource, numJobs)
-	resultErrors := make(chan error, numJobs)
-
-	// Fire up the workers
-	for i := 0; i < numWorkers; i++ {
-		go func(jobs <-chan *schema.Resource, resultErrors chan<- error) {
-			for r := range jobs {
-				err := GetPrices(c, r)
-				resultErrors <- err
+// checkPriceAges adds a warning to the resources with prices that became effective longer
+// than maxAge ago, since the estimate may be stale if the prices haven't been updated.
+func checkPriceAges(resources []*schema.Resource, maxAge time.Duration, now time.Time) {
+	stale := 0
+
+	var check func(r *schema.Resource)
+	check = func(r *schema.Resource) {
+		for _, c := range r.CostComponents {
+			t := c.PriceEffectiveDate()
+			if t == nil || now.Sub(*t) <= maxAge {
+				continue
 			}
-		}(jobs, resultErrors)
+
+			r.AddWarning(schema.WarningStalePrice, c.Name, fmt.Sprintf("Price has been effective since %s, longer than the price max age of %s", t.Format("2006-01-02"), maxAge))
+			stale++
+		}
+
+		for _, s := range r.SubResources {
+			check(s)
+		}
 	}
 
-	// Feed the workers the jobs of getting prices
 	for _, r := range resources {
-		jobs <- r
+		check(r)
 	}
 
-	// Get the result of the jobs
-	for i := 0; i < numJobs; i++ {
-		err := <-resultErrors
+	if stale > 0 {
+		log.Warnf("%d prices became effective longer than %s ago, the estimate may be stale.", stale, maxAge)
+	}
+}
+
+// The vendor names of the Cloud Pricing API products for each Terraform provider, used to
+// find the fallback region of a cost component.
+var providerVendorNames = map[string]string{
+	"aws":      "aws",
+	"google":   "gcp",
+	"azurerm":  "azure",
+	"alicloud": "alibaba",
+	"oci":      "oracle",
+	"ibm":      "ibm",
+}
+
+// GetPrices gets the prices of all the resources. The queries for all the resources are
+// batched by the client, and the prices are set once all the results have been returned.
+// If a price isn't available in the region of a cost component and the provider has a
+// fallback region, the price from the fallback region is used instead.
+func GetPrices(c *apiclient.PricingAPIClient, resources []*schema.Resource, fallbackRegions map[string]string) error {
+	results, err := c.RunQueries(resources)
+	if err != nil {
+		return err
+	}
+
+	failed := make(map[*schema.Resource]bool)
+	missing := make([]apiclient.PriceQueryResult, 0)
+
+	for _, r := range results {
+		if r.Err != nil {
+			setPricingError(r.Resource, r.CostComponent, r.Err)
+			failed[r.Resource] = true
+			continue
+		}
+
+		if fallbackRegion(r.CostComponent, fallbackRegions) != "" && !hasPrice(r.Result) {
+			missing = append(missing, r)
+			continue
+		}
+
+		setCostComponentPrice(c, r.Resource, r.CostComponent, r.Result)
+	}
+
+	if len(missing) > 0 {
+		err = getFallbackPrices(c, missing, fallbackRegions)
 		if err != nil {
 			return err
 		}
 	}
+
+	if len(failed) > 0 {
+		log.Warnf("Could not get prices for %d resources from the Cloud Pricing API, their costs are incomplete. Re-run to try again.", len(failed))
+	}
+
 	return nil
 }
 
-func GetPrices(c *apiclient.PricingAPIClient, r *schema.Resource) error {
-	if r.IsSkipped {
-		return nil
+// getFallbackPrices queries the prices of the cost components that had no price in their
+// region again using the fallback region of their provider. The resource is given a warning
+// so it's clear the cost is an estimate from a different region.
+func getFallbackPrices(c *apiclient.PricingAPIClient, missing []apiclient.PriceQueryResult, fallbackRegions map[string]string) error {
+	fallbackResources := make([]*schema.Resource, 0, len(missing))
+	originals := make(map[*schema.CostComponent]apiclient.PriceQueryResult, len(missing))
+
+	for _, r := range missing {
+		region := fallbackRegion(r.CostComponent, fallbackRegions)
+
+		productFilter := *r.CostComponent.ProductFilter
+		productFilter.Region = &region
+
+		fallback := *r.CostComponent
+		fallback.ProductFilter = &productFilter
+
+		fallbackResources = append(fallbackResources, &schema.Resource{
+			Name:           r.Resource.Name,
+			CostComponents: []*schema.CostComponent{&fallback},
+		})
+		originals[&fallback] = r
 	}
 
-	results, err := c.RunQueries(r)
+	results, err := c.RunQueries(fallbackResources)
 	if err != nil {
 		return err
 	}
 
-	for _, r := range results {
-		setCostComponentPrice(r.Resource, r.CostComponent, r.Result)
+	for _, fr := range results {
+		r := originals[fr.CostComponent]
+
+		if fr.Err != nil || !hasPrice(fr.Result) {
+			setCostComponentPrice(c, r.Resource, r.CostComponent, r.Result)
+			continue
+		}
+
+		region := *r.CostComponent.ProductFilter.Region
+		fallback := *fr.CostComponent.ProductFilter.Region
+
+		log.Warnf("No price found for %s %s in %s, using the price from %s", r.Resource.Name, r.CostComponent.Name, region, fallback)
+		r.Resource.AddWarning(schema.WarningRegionFallback, r.CostComponent.Name, fmt.Sprintf("No price found in %s, using the price from %s", region, fallback))
+
+		setCostComponentPrice(c, r.Resource, r.CostComponent, fr.Result)
 	}
 
 	return nil
 }
 
-func setCostComponentPrice(r *schema.Resource, c *schema.CostComponent, res gjson.Result) {
+// fallbackRegion returns the fallback region for the cost component, or an empty string
+// if there isn't one or the cost component is already in it.
+func fallbackRegion(c *schema.CostComponent, fallbackRegions map[string]string) string {
+	if c.ProductFilter == nil || c.ProductFilter.Region == nil || c.ProductFilter.VendorName == nil {
+		return ""
+	}
+
+	for provider, region := range fallbackRegions {
+		if providerVendorNames[provider] != *c.ProductFilter.VendorName || region == *c.ProductFilter.Region {
+			continue
+		}
+
+		// AWS partitions have separate price lists, so prices from a region in another
+		// partition would be wrong
+		if provider == "aws" && awsresources.RegionPartition(region) != awsresources.RegionPartition(*c.ProductFilter.Region) {
+			return ""
+		}
+
+		return region
+	}
+
+	return ""
+}
+
+func hasPrice(res gjson.Result) bool {
+	products := res.Get("data.products").Array()
+	return len(products) > 0 && len(products[0].Get("prices").Array()) > 0
+}
+
+// setPricingError annotates the resource with the error so the rest of the run can
+// continue. The cost component's price is left as 0.
+func setPricingError(r *schema.Resource, c *schema.CostComponent, err error) {
+	log.Debugf("Error getting price for %s %s: %s", r.Name, c.Name, err)
+
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string)
+	}
+	r.Metadata["pricingError"] = err.Error()
+}
+
+func setCostComponentPrice(client *apiclient.PricingAPIClient, r *schema.Resource, c *schema.CostComponent, res gjson.Result) {
 	var p decimal.Decimal
 
 	products := res.Get("data.products").Array()
@@ -95,6 +218,7 @@ func setCostComponentPrice(r *schema.Resource, c *schema.CostComponent, res gjso
 		}
 
 		log.Warnf("No products found for %s %s, using 0.00", r.Name, c.Name)
+		r.AddWarning(schema.WarningPriceNotFound, c.Name, "No products found, using 0.00")
 		c.SetPrice(decimal.Zero)
 		return
 	}
@@ -111,6 +235,7 @@ func setCostComponentPrice(r *schema.Resource, c *schema.CostComponent, res gjso
 		}
 
 		log.Warnf("No prices found for %s %s, using 0.00", r.Name, c.Name)
+		r.AddWarning(schema.WarningPriceNotFound, c.Name, "No prices found, using 0.00")
 		c.SetPrice(decimal.Zero)
 		return
 	}
@@ -119,13 +244,15 @@ func setCostComponentPrice(r *schema.Resource, c *schema.CostComponent, res gjso
 	}
 
 	var err error
-	p, err = decimal.NewFromString(prices[0].Get("USD").String())
+	p, err = client.Price(prices[0])
 	if err != nil {
-		log.Warnf("Error converting price (using 0.00) '%v': %s", prices[0].Get("USD").String(), err.Error())
+		log.Warnf("Error converting price (using 0.00) for %s %s: %s", r.Name, c.Name, err.Error())
+		setPricingError(r, c, err)
 		c.SetPrice(decimal.Zero)
 		return
 	}
 
 	c.SetPrice(p)
 	c.SetPriceHash(prices[0].Get("priceHash").String())
+	c.SetPriceEffectiveDate(apiclient.EffectiveDate(prices[0]))
 }
@@ -0,0 +1,113 @@
+package prices
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPricesFallbackRegion(t *testing.T) {
+	// Only us-east-1 has prices
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []apiclient.GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+
+		results := make([]interface{}, 0, len(queries))
+		for _, q := range queries {
+			products := []interface{}{}
+			if q.Variables["productFilter"].(map[string]interface{})["region"] == "us-east-1" {
+				products = append(products, map[string]interface{}{
+					"prices": []interface{}{map[string]interface{}{"priceHash": "abc", "USD": "0.5", "effectiveDateStart": "2021-03-01T00:00:00.000Z"}},
+				})
+			}
+
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{"products": products},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(results))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.PricingAPIEndpoint = server.URL
+	cfg.NoCache = true
+
+	c, err := apiclient.NewPricingAPIClient(cfg)
+	require.NoError(t, err)
+
+	component := func(vendor, region string) *schema.CostComponent {
+		return &schema.CostComponent{
+			Name:          "Instance usage",
+			ProductFilter: &schema.ProductFilter{VendorName: strPtr(vendor), Region: strPtr(region)},
+		}
+	}
+
+	inRegion := component("aws", "us-east-1")
+	fallback := component("aws", "ap-southeast-5")
+	noFallback := component("gcp", "us-central1")
+
+	resources := []*schema.Resource{
+		{Name: "aws_instance.a", CostComponents: []*schema.CostComponent{inRegion}},
+		{Name: "aws_instance.b", CostComponents: []*schema.CostComponent{fallback}},
+		{Name: "google_compute_instance.c", CostComponents: []*schema.CostComponent{noFallback}},
+	}
+
+	err = GetPrices(c, resources, map[string]string{"aws": "us-east-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.5", inRegion.Price().String())
+	assert.Equal(t, "2021-03-01", inRegion.PriceEffectiveDate().Format("2006-01-02"))
+	assert.Empty(t, resources[0].Warnings)
+
+	assert.Equal(t, "0.5", fallback.Price().String())
+	assert.Equal(t, "abc", fallback.PriceHash())
+	assert.Equal(t, "ap-southeast-5", *fallback.ProductFilter.Region)
+	assert.Equal(t, []*schema.Warning{
+		{Code: schema.WarningRegionFallback, Message: "No price found in ap-southeast-5, using the price from us-east-1", CostComponent: "Instance usage"},
+	}, resources[1].Warnings)
+
+	assert.Equal(t, "0", noFallback.Price().String())
+	assert.Equal(t, []*schema.Warning{
+		{Code: schema.WarningPriceNotFound, Message: "No products found, using 0.00", CostComponent: "Instance usage"},
+	}, resources[2].Warnings)
+}
+
+func TestCheckPriceAges(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	recent := now.AddDate(0, -1, 0)
+
+	component := func(name string, effectiveDate *time.Time) *schema.CostComponent {
+		c := &schema.CostComponent{Name: name}
+		c.SetPriceEffectiveDate(effectiveDate)
+		return c
+	}
+
+	sub := &schema.Resource{Name: "root_block_device", CostComponents: []*schema.CostComponent{component("Storage", &old)}}
+	r := &schema.Resource{
+		Name:           "aws_instance.web",
+		CostComponents: []*schema.CostComponent{component("Instance usage", &recent), component("CPU credits", nil)},
+		SubResources:   []*schema.Resource{sub},
+	}
+
+	checkPriceAges([]*schema.Resource{r}, 365*24*time.Hour, now)
+
+	assert.Empty(t, r.Warnings)
+	assert.Equal(t, []*schema.Warning{
+		{Code: schema.WarningStalePrice, Message: "Price has been effective since 2020-01-01, longer than the price max age of 8760h0m0s", CostComponent: "Storage"},
+	}, sub.Warnings)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,175 @@
+package prices
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// savingsPlanCost is the hourly cost of a cost component split into the usage that is
+// still charged at the on-demand rate and the usage covered by Savings Plans.
+type savingsPlanCost struct {
+	onDemand decimal.Decimal
+	covered  decimal.Decimal
+}
+
+// ApplySavingsPlans discounts the eligible on-demand compute cost components of the
+// project up to the hourly commitment of each Savings Plan, and adds the coverage of each
+// plan to the project. The costs of the project must already be calculated. The past and
+// current resources are covered separately, so the diff is the change in cost after the
+// discounts.
+func ApplySavingsPlans(project *schema.Project, plans []*config.SavingsPlan) {
+	plans = validSavingsPlans(plans)
+	if len(plans) == 0 {
+		return
+	}
+
+	applySavingsPlans(project.PastResources, plans)
+	project.SavingsPlans = applySavingsPlans(project.Resources, plans)
+
+	schema.CalculateCosts(project)
+}
+
+// validSavingsPlans returns the valid plans in the order AWS applies them, EC2 Instance
+// Savings Plans first since they have larger discounts than Compute Savings Plans.
+func validSavingsPlans(plans []*config.SavingsPlan) []*config.SavingsPlan {
+	valid := make([]*config.SavingsPlan, 0, len(plans))
+
+	for _, p := range plans {
+		switch {
+		case p.Type != "compute" && p.Type != "ec2_instance":
+			log.Warnf("Invalid savings plan type, ignoring savings plan. Expected: compute, ec2_instance. Got: %s", p.Type)
+		case p.HourlyCommitment <= 0:
+			log.Warnf("Invalid hourly_commitment for %s savings plan, ignoring savings plan. Expected a number greater than 0. Got: %v", p.Type, p.HourlyCommitment)
+		case p.DiscountPercent <= 0 || p.DiscountPercent >= 100:
+			log.Warnf("Invalid discount_percent for %s savings plan, ignoring savings plan. Expected a number between 0 and 100. Got: %v", p.Type, p.DiscountPercent)
+		case p.Type == "ec2_instance" && (p.Region == "" || p.InstanceFamily == ""):
+			log.Warnf("EC2 Instance savings plans require a region and instance_family, ignoring savings plan")
+		default:
+			valid = append(valid, p)
+		}
+	}
+
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].Type == "ec2_instance" && valid[j].Type != "ec2_instance"
+	})
+
+	return valid
+}
+
+func applySavingsPlans(resources []*schema.Resource, plans []*config.SavingsPlan) []*schema.SavingsPlanCoverage {
+	var components []*schema.CostComponent
+	for _, r := range resources {
+		components = append(components, flattenCostComponents(r)...)
+	}
+
+	costs := make(map[*schema.CostComponent]*savingsPlanCost)
+	coverages := make([]*schema.SavingsPlanCoverage, 0, len(plans))
+
+	for _, p := range plans {
+		rate := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(p.DiscountPercent).Div(decimal.NewFromInt(100)))
+		commitment := decimal.NewFromFloat(p.HourlyCommitment)
+		left := commitment
+		covered := decimal.Zero
+		onDemand := decimal.Zero
+
+		for _, c := range components {
+			if !savingsPlanEligible(p, c) {
+				continue
+			}
+
+			cost, ok := costs[c]
+			if !ok {
+				cost = &savingsPlanCost{onDemand: *c.HourlyCost}
+				costs[c] = cost
+			}
+
+			// The commitment is spent at the Savings Plan rate, so it covers more usage
+			// than its on-demand value
+			planCost := decimal.Min(left, cost.onDemand.Mul(rate))
+			left = left.Sub(planCost)
+
+			cost.onDemand = cost.onDemand.Sub(planCost.Div(rate))
+			cost.covered = cost.covered.Add(planCost)
+
+			covered = covered.Add(planCost)
+			onDemand = onDemand.Add(cost.onDemand)
+		}
+
+		coverages = append(coverages, &schema.SavingsPlanCoverage{
+			Type:              p.Type,
+			Term:              p.Term,
+			MonthlyCommitment: monthlyCost(commitment),
+			CoveredCost:       monthlyCost(covered),
+			OnDemandCost:      monthlyCost(onDemand),
+			Savings:           monthlyCost(covered.Div(rate).Sub(covered)),
+			UnusedCommitment:  monthlyCost(left),
+		})
+	}
+
+	// Scale the prices so the costs are calculated from the blended rate of the covered
+	// and on-demand usage
+	for c, cost := range costs {
+		blended := cost.onDemand.Add(cost.covered)
+		c.SetPrice(c.Price().Mul(blended).Div(*c.HourlyCost))
+	}
+
+	return coverages
+}
+
+// savingsPlanEligible returns true if the cost component is on-demand compute that the
+// plan applies to. Compute Savings Plans apply to EC2 instances in any region and to
+// Fargate, EC2 Instance Savings Plans to the EC2 instances of one family in one region.
+func savingsPlanEligible(p *config.SavingsPlan, c *schema.CostComponent) bool {
+	if c.HourlyCost == nil || !c.HourlyCost.IsPositive() || c.ProductFilter == nil ||
+		c.ProductFilter.Service == nil || c.ProductFilter.ProductFamily == nil {
+		return false
+	}
+
+	service := *c.ProductFilter.Service
+	productFamily := *c.ProductFilter.ProductFamily
+
+	if service == "AmazonECS" && productFamily == "Compute" {
+		return p.Type == "compute"
+	}
+
+	if service != "AmazonEC2" || productFamily != "Compute Instance" || c.PurchaseOption != "on_demand" {
+		return false
+	}
+
+	if p.Type == "compute" {
+		return true
+	}
+
+	if c.ProductFilter.Region == nil || *c.ProductFilter.Region != p.Region {
+		return false
+	}
+
+	for _, f := range c.ProductFilter.AttributeFilters {
+		if f.Key == "instanceType" && f.Value != nil {
+			return strings.SplitN(*f.Value, ".", 2)[0] == p.InstanceFamily
+		}
+	}
+
+	return false
+}
+
+func flattenCostComponents(r *schema.Resource) []*schema.CostComponent {
+	components := append([]*schema.CostComponent{}, r.CostComponents...)
+
+	for _, s := range r.SubResources {
+		components = append(components, flattenCostComponents(s)...)
+	}
+
+	return components
+}
+
+func monthlyCost(hourly decimal.Decimal) *decimal.Decimal {
+	m := hourly.Mul(schema.HourToMonthUnitMultiplier)
+	return &m
+}
@@ -0,0 +1,101 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCostComponent(service, productFamily, purchaseOption, region, instanceType string, price float64) *schema.CostComponent {
+	quantity := decimal.NewFromInt(1)
+
+	c := &schema.CostComponent{
+		Name:           "Instance usage",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: &quantity,
+		ProductFilter: &schema.ProductFilter{
+			Service:       &service,
+			ProductFamily: &productFamily,
+			Region:        &region,
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: &instanceType},
+			},
+		},
+		PurchaseOption: purchaseOption,
+	}
+	c.SetPrice(decimal.NewFromFloat(price))
+
+	return c
+}
+
+func TestApplySavingsPlans(t *testing.T) {
+	m5 := testCostComponent("AmazonEC2", "Compute Instance", "on_demand", "us-east-1", "m5.large", 1)
+	c5 := testCostComponent("AmazonEC2", "Compute Instance", "on_demand", "eu-west-1", "c5.large", 1)
+	spot := testCostComponent("AmazonEC2", "Compute Instance", "spot", "us-east-1", "m5.large", 1)
+	rds := testCostComponent("AmazonRDS", "Database Instance", "on_demand", "us-east-1", "db.m5.large", 1)
+
+	project := &schema.Project{
+		Resources: []*schema.Resource{
+			{Name: "aws_instance.m5", CostComponents: []*schema.CostComponent{m5}},
+			{Name: "aws_instance.c5", CostComponents: []*schema.CostComponent{c5}},
+			{Name: "aws_instance.spot", CostComponents: []*schema.CostComponent{spot}},
+			{Name: "aws_db_instance.db", CostComponents: []*schema.CostComponent{rds}},
+		},
+	}
+	schema.CalculateCosts(project)
+
+	ApplySavingsPlans(project, []*config.SavingsPlan{
+		{Type: "compute", Term: "1_year", HourlyCommitment: 0.6, DiscountPercent: 25},
+		{Type: "ec2_instance", Term: "3_year", HourlyCommitment: 0.5, DiscountPercent: 50, Region: "us-east-1", InstanceFamily: "m5"},
+		{Type: "unknown", HourlyCommitment: 1, DiscountPercent: 10},
+	})
+
+	// The EC2 Instance plan is applied first and covers all of m5 for 0.5, then the Compute
+	// plan covers 0.8 of c5's on-demand usage for 0.6
+	assert.Equal(t, "0.5", m5.HourlyCost.String())
+	assert.Equal(t, "0.8", c5.HourlyCost.Round(6).String())
+	assert.Equal(t, "1", spot.HourlyCost.String())
+	assert.Equal(t, "1", rds.HourlyCost.String())
+
+	if assert.Len(t, project.SavingsPlans, 2) {
+		ec2 := project.SavingsPlans[0]
+		assert.Equal(t, "ec2_instance", ec2.Type)
+		assert.Equal(t, "365", ec2.CoveredCost.String())
+		assert.Equal(t, "0", ec2.OnDemandCost.String())
+		assert.Equal(t, "365", ec2.Savings.String())
+		assert.Equal(t, "0", ec2.UnusedCommitment.String())
+
+		compute := project.SavingsPlans[1]
+		assert.Equal(t, "compute", compute.Type)
+		assert.Equal(t, "438", compute.CoveredCost.Round(6).String())
+		assert.Equal(t, "146", compute.OnDemandCost.Round(6).String())
+		assert.Equal(t, "146", compute.Savings.Round(6).String())
+		assert.Equal(t, "0", compute.UnusedCommitment.Round(6).String())
+	}
+}
+
+func TestApplySavingsPlansUnusedCommitment(t *testing.T) {
+	c := testCostComponent("AmazonECS", "Compute", "", "us-east-1", "", 1)
+	past := testCostComponent("AmazonECS", "Compute", "", "us-east-1", "", 1)
+
+	project := &schema.Project{
+		PastResources: []*schema.Resource{{Name: "aws_ecs_service.svc", CostComponents: []*schema.CostComponent{past}}},
+		Resources:     []*schema.Resource{{Name: "aws_ecs_service.svc", CostComponents: []*schema.CostComponent{c}}},
+	}
+	schema.CalculateCosts(project)
+
+	ApplySavingsPlans(project, []*config.SavingsPlan{
+		{Type: "compute", HourlyCommitment: 2, DiscountPercent: 20},
+	})
+
+	assert.Equal(t, "0.8", c.HourlyCost.String())
+	assert.Equal(t, "0.8", past.HourlyCost.String())
+
+	if assert.Len(t, project.SavingsPlans, 1) {
+		assert.Equal(t, "876", project.SavingsPlans[0].UnusedCommitment.String())
+		assert.Equal(t, "1460", project.SavingsPlans[0].MonthlyCommitment.String())
+	}
+}
@@ -38,6 +38,10 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 			}
 		}
 
+		if u != nil {
+			u.ResetAccessed()
+		}
+
 		res := registryItem.RFunc(d, u)
 		if res != nil {
 			res.ResourceType = d.Type
@@ -56,6 +60,14 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	}
 }
 
+// setUsageProvenance sets the usage provenance of the cost components of the resource,
+// which must have just been created with the usage data.
+func (p *Parser) setUsageProvenance(d *schema.ResourceData, r *schema.Resource, u *schema.UsageData) {
+	schema.SetUsageProvenance(r, u, func(probe *schema.UsageData) *schema.Resource {
+		return p.createResource(d, probe)
+	})
+}
+
 func (p *Parser) parseTemplate(t *cloudformation.Template, usage map[string]*schema.UsageData) ([]*schema.Resource, []*schema.Resource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
@@ -64,20 +76,11 @@ func (p *Parser) parseTemplate(t *cloudformation.Template, usage map[string]*sch
 
 	for name, d := range t.Resources {
 		tags := map[string]string{} // TODO: Where do I get tags?
-		var usageData *schema.UsageData
-
-		if ud := usage[name]; ud != nil {
-			usageData = ud
-		} else if strings.HasSuffix(name, "]") {
-			lastIndexOfOpenBracket := strings.LastIndex(name, "[")
-
-			if arrayUsageData := usage[fmt.Sprintf("%s[*]", name[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
-				usageData = arrayUsageData
-			}
-		}
+		usageData := schema.FindUsageData(usage, name)
 		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, tags, d)
 
 		if r := p.createResource(resourceData, usageData); r != nil {
+			p.setUsageProvenance(resourceData, r, usageData)
 			resources = append(resources, r)
 		}
 	}
@@ -93,6 +96,7 @@ func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schem
 			if strings.HasPrefix(k, fmt.Sprintf("%s.", t)) {
 				d := schema.NewResourceData(t, "global", k, map[string]string{}, gjson.Result{})
 				if r := p.createResource(d, v); r != nil {
+					p.setUsageProvenance(d, r, v)
 					resources = append(resources, r)
 				}
 			}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/awslabs/goformation/v4"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
@@ -13,38 +15,136 @@ import (
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
 )
 
 func Detect(ctx *config.ProjectContext) (schema.Provider, error) {
 	path := ctx.ProjectConfig.Path
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("No such file or directory %s", path)
 	}
 
+	if err == nil && info.IsDir() {
+		return detectDir(ctx, path)
+	}
+
+	return detectFile(ctx, path)
+}
+
+func detectFile(ctx *config.ProjectContext, path string) (schema.Provider, error) {
 	if isCloudFormationTemplate(path) {
+		setDetectionReason(ctx, fmt.Sprintf("%s is a CloudFormation template", path))
 		return cloudformation.NewTemplateProvider(ctx), nil
 	}
 
 	if isTerraformPlanJSON(path) {
+		setDetectionReason(ctx, fmt.Sprintf("%s is a Terraform plan JSON file", path))
 		return terraform.NewPlanJSONProvider(ctx), nil
 	}
 
 	if isTerraformStateJSON(path) {
+		setDetectionReason(ctx, fmt.Sprintf("%s is a Terraform state JSON file", path))
 		return terraform.NewStateJSONProvider(ctx), nil
 	}
 
 	if isTerraformPlan(path) {
+		setDetectionReason(ctx, fmt.Sprintf("%s is a Terraform plan file", path))
 		return terraform.NewPlanProvider(ctx), nil
 	}
 
+	return nil, fmt.Errorf("Could not detect path type for %s", path)
+}
+
+// detectDir checks the contents of a directory to choose the provider. CDK for Terraform
+// and Terragrunt directories are checked first since they can also contain HCL files.
+// Directories without any Terraform files are checked for a single plan or state JSON file.
+func detectDir(ctx *config.ProjectContext, path string) (schema.Provider, error) {
+	if isCDKTFDir(path) {
+		stackDir, err := findCDKTFStackDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		setProjectPath(ctx, stackDir)
+		setDetectionReason(ctx, fmt.Sprintf("%s contains CDK for Terraform output %s", path, stackDir))
+		return terraform.NewDirProvider(ctx), nil
+	}
+
+	if isTerragruntDir(path) {
+		if ctx.ProjectConfig.TerraformBinary == "" {
+			cfg := *ctx.ProjectConfig
+			cfg.TerraformBinary = "terragrunt"
+			ctx.ProjectConfig = &cfg
+		}
+
+		setDetectionReason(ctx, fmt.Sprintf("%s contains terragrunt.hcl", path))
+		return terraform.NewDirProvider(ctx), nil
+	}
+
 	if isTerraformDir(path) {
+		setDetectionReason(ctx, fmt.Sprintf("%s contains Terraform HCL files", path))
 		return terraform.NewDirProvider(ctx), nil
 	}
 
+	jsonPath, err := findTerraformJSONFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if jsonPath != "" {
+		setProjectPath(ctx, jsonPath)
+		return detectFile(ctx, jsonPath)
+	}
+
 	return nil, fmt.Errorf("Could not detect path type for %s", path)
 }
 
+// FindProjectDirs returns the path if it is a Terraform, Terragrunt or CDK for Terraform
+// directory, otherwise it returns the project directories under the path. Hidden directories
+// and directories named modules are skipped since they don't contain projects.
+func FindProjectDirs(path string) ([]string, error) {
+	dirs := make([]string, 0)
+
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if p != path && (strings.HasPrefix(name, ".") || name == "modules" || name == "node_modules") {
+			return filepath.SkipDir
+		}
+
+		if isCDKTFDir(p) || isTerragruntDir(p) || isTerraformDir(p) {
+			dirs = append(dirs, p)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return dirs, err
+}
+
+func setDetectionReason(ctx *config.ProjectContext, reason string) {
+	log.Debugf("Detected project type: %s", reason)
+	ctx.SetContextValue("detectionReason", reason)
+}
+
+// setProjectPath sets the path to use for the project without changing the
+// original project config.
+func setProjectPath(ctx *config.ProjectContext, path string) {
+	cfg := *ctx.ProjectConfig
+	cfg.Path = path
+	ctx.ProjectConfig = &cfg
+}
+
 func isTerraformPlanJSON(path string) bool {
 	b, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -117,3 +217,77 @@ func isCloudFormationTemplate(path string) bool {
 
 	return false
 }
+
+func isTerragruntDir(path string) bool {
+	_, err := os.Stat(filepath.Join(path, "terragrunt.hcl"))
+	return err == nil
+}
+
+func isCDKTFDir(path string) bool {
+	for _, name := range []string{"cdktf.json", "cdktf.out"} {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findCDKTFStackDir returns the directory of the synthesized CDK for Terraform stack.
+// Older versions of cdktf write a single stack to cdktf.out, newer versions write each
+// stack to cdktf.out/stacks/<stack name>.
+func findCDKTFStackDir(path string) (string, error) {
+	outDir := filepath.Join(path, "cdktf.out")
+
+	if _, err := os.Stat(filepath.Join(outDir, "cdk.tf.json")); err == nil {
+		return outDir, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(outDir, "stacks", "*", "cdk.tf.json"))
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("No CDK for Terraform output found in %s. Run cdktf synth before running Infracost", path)
+	}
+
+	if len(matches) > 1 {
+		stacks := make([]string, 0, len(matches))
+		for _, m := range matches {
+			stacks = append(stacks, filepath.Dir(m))
+		}
+
+		return "", fmt.Errorf("Multiple CDK for Terraform stacks found in %s, set the path to one of:\n%s", path, strings.Join(stacks, "\n"))
+	}
+
+	return filepath.Dir(matches[0]), nil
+}
+
+// findTerraformJSONFile returns the plan or state JSON file in the directory. An empty path
+// is returned if there are none, and an error is returned if it is ambiguous which to use.
+func findTerraformJSONFile(path string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return "", err
+	}
+
+	var planFiles, stateFiles []string
+
+	for _, m := range matches {
+		if isTerraformPlanJSON(m) {
+			planFiles = append(planFiles, m)
+		} else if isTerraformStateJSON(m) {
+			stateFiles = append(stateFiles, m)
+		}
+	}
+
+	for _, files := range [][]string{planFiles, stateFiles} {
+		if len(files) == 1 {
+			return files[0], nil
+		}
+
+		if len(files) > 1 {
+			return "", fmt.Errorf("Multiple Terraform JSON files found in %s, set the path to one of:\n%s", path, strings.Join(files, "\n"))
+		}
+	}
+
+	return "", nil
+}
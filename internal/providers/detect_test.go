@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func TestDetectDir(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		expectedType string
+		expectedPath string
+		expectedBin  string
+	}{
+		{
+			name:         "terraform",
+			files:        map[string]string{"main.tf": ""},
+			expectedType: "terraform_dir",
+			expectedPath: ".",
+		},
+		{
+			name:         "terragrunt",
+			files:        map[string]string{"terragrunt.hcl": ""},
+			expectedType: "terraform_dir",
+			expectedPath: ".",
+			expectedBin:  "terragrunt",
+		},
+		{
+			name: "cdktf",
+			files: map[string]string{
+				"cdktf.json":                       "{}",
+				"cdktf.out/stacks/dev/cdk.tf.json": "{}",
+			},
+			expectedType: "terraform_dir",
+			expectedPath: "cdktf.out/stacks/dev",
+		},
+		{
+			name: "plan json",
+			files: map[string]string{
+				"package.json": `{"name": "test"}`,
+				"plan.json":    `{"format_version": "0.1", "planned_values": {}}`,
+			},
+			expectedType: "terraform_plan_json",
+			expectedPath: "plan.json",
+		},
+		{
+			name:         "state json",
+			files:        map[string]string{"state.json": `{"format_version": "0.1", "values": {}}`},
+			expectedType: "terraform_state_json",
+			expectedPath: "state.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(dir, name), content)
+			}
+
+			ctx := config.EmptyProjectContext()
+			ctx.ProjectConfig.Path = dir
+
+			provider, err := Detect(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedType, provider.Type())
+			assert.Equal(t, filepath.Join(dir, tt.expectedPath), ctx.ProjectConfig.Path)
+			assert.Equal(t, tt.expectedBin, ctx.ProjectConfig.TerraformBinary)
+			assert.NotEmpty(t, ctx.ContextValues()["detectionReason"])
+		})
+	}
+}
+
+func TestDetectDirMultipleCDKTFStacks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "cdktf.out/stacks/dev/cdk.tf.json"), "{}")
+	writeFile(t, filepath.Join(dir, "cdktf.out/stacks/prod/cdk.tf.json"), "{}")
+
+	ctx := config.EmptyProjectContext()
+	ctx.ProjectConfig.Path = dir
+
+	_, err := Detect(ctx)
+	assert.Error(t, err)
+}
+
+func TestFindProjectDirs(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, f := range []string{
+		"stacks/prod/main.tf",
+		"stacks/prod/nested/main.tf",
+		"stacks/dev/terragrunt.hcl",
+		"modules/vpc/main.tf",
+		".github/workflows/main.tf",
+		"README.md",
+	} {
+		writeFile(t, filepath.Join(dir, f), "")
+	}
+
+	dirs, err := FindProjectDirs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "stacks/dev"), filepath.Join(dir, "stacks/prod")}, dirs)
+
+	dirs, err = FindProjectDirs(filepath.Join(dir, "stacks/prod"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "stacks/prod")}, dirs)
+}
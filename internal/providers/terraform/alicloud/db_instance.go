@@ -0,0 +1,56 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetDBInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "alicloud_db_instance",
+		RFunc: NewDBInstance,
+		Notes: []string{
+			"Subscription (Prepaid) instances are priced at the pay-as-you-go rates.",
+		},
+	}
+}
+
+func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	engine := strings.ToLower(d.Get("engine").String())
+	engineVersion := d.Get("engine_version").String()
+	instanceType := d.Get("instance_type").String()
+
+	storageType := d.Get("db_instance_storage_type").String()
+	if storageType == "" {
+		storageType = "local_ssd"
+	}
+
+	storage := d.Get("instance_storage").Int()
+
+	config := fmt.Sprintf("Engine:%s,EngineVersion:%s,Region:%s", engine, engineVersion, region)
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("Database instance (pay-as-you-go, %s)", instanceType),
+				Unit:           "hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+				ProductFilter:  productFilter("rds", region, "DBInstanceClass", fmt.Sprintf("DBInstanceClass:%s,%s", instanceType, config), "Hour"),
+			},
+			{
+				// Like the ECS disks the storage is priced for its whole size per hour
+				Name:           fmt.Sprintf("Storage (%s, %d GB)", storageType, storage),
+				Unit:           "hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+				ProductFilter:  productFilter("rds", region, "DBInstanceStorage", fmt.Sprintf("DBInstanceStorage:%d,DBInstanceStorageType:%s,%s", storage, storageType, config), "Hour"),
+			},
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewDBInstance(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  string
+		filters map[string][4]string
+	}{
+		{
+			name:   "default storage type",
+			values: `{"region": "cn-hangzhou", "engine": "MySQL", "engine_version": "8.0", "instance_type": "rds.mysql.s2.large", "instance_storage": 100}`,
+			filters: map[string][4]string{
+				"Database instance (pay-as-you-go, rds.mysql.s2.large)": {"rds", "DBInstanceClass", "DBInstanceClass:rds.mysql.s2.large,Engine:mysql,EngineVersion:8.0,Region:cn-hangzhou", "Hour"},
+				"Storage (local_ssd, 100 GB)":                           {"rds", "DBInstanceStorage", "DBInstanceStorage:100,DBInstanceStorageType:local_ssd,Engine:mysql,EngineVersion:8.0,Region:cn-hangzhou", "Hour"},
+			},
+		},
+		{
+			name:   "ESSD storage",
+			values: `{"region": "cn-shanghai", "engine": "PostgreSQL", "engine_version": "13.0", "instance_type": "pg.n2.medium.2c", "instance_storage": 50, "db_instance_storage_type": "cloud_essd"}`,
+			filters: map[string][4]string{
+				"Database instance (pay-as-you-go, pg.n2.medium.2c)": {"rds", "DBInstanceClass", "DBInstanceClass:pg.n2.medium.2c,Engine:postgresql,EngineVersion:13.0,Region:cn-shanghai", "Hour"},
+				"Storage (cloud_essd, 50 GB)":                        {"rds", "DBInstanceStorage", "DBInstanceStorage:50,DBInstanceStorageType:cloud_essd,Engine:postgresql,EngineVersion:13.0,Region:cn-shanghai", "Hour"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("alicloud_db_instance", "alicloud", "alicloud_db_instance.db", nil, gjson.Parse(tt.values))
+			r := NewDBInstance(d, nil)
+
+			assert.Equal(t, tt.filters, componentFilters(r))
+			for _, c := range r.CostComponents {
+				assert.Equal(t, "1", c.HourlyQuantity.String())
+			}
+		})
+	}
+}
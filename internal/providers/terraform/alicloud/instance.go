@@ -0,0 +1,128 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+var defaultSystemDiskSize = 40
+
+func GetInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "alicloud_instance",
+		RFunc: NewInstance,
+		Notes: []string{
+			"Subscription (PrePaid) instances are priced at the pay-as-you-go rates.",
+		},
+	}
+}
+
+func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	costComponents := []*schema.CostComponent{
+		instanceCostComponent(d, region),
+		diskCostComponent(region, "System disk", "SystemDisk", d.Get("system_disk_category").String(), systemDiskSize(d)),
+	}
+
+	for i, disk := range d.Get("data_disks").Array() {
+		size := disk.Get("size").Int()
+		if size == 0 {
+			continue
+		}
+
+		costComponents = append(costComponents, diskCostComponent(region, fmt.Sprintf("Data disk %d", i+1), "DataDisk", disk.Get("category").String(), size))
+	}
+
+	if c := internetBandwidthCostComponent(d, u, region); c != nil {
+		costComponents = append(costComponents, c)
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func instanceCostComponent(d *schema.ResourceData, region string) *schema.CostComponent {
+	instanceType := d.Get("instance_type").String()
+
+	// Windows public images have IDs starting with win, e.g. win2019_1809_x64_dtc_en-us_40G_alibase_20210916.vhd
+	os := "linux"
+	if strings.HasPrefix(d.Get("image_id").String(), "win") {
+		os = "windows"
+	}
+
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("Instance usage (%s, pay-as-you-go, %s)", strings.Title(os), instanceType),
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter:  productFilter("ecs", region, "InstanceType", fmt.Sprintf("InstanceType:%s,IoOptimized:IoOptimized,ImageOs:%s", instanceType, os), "Hour"),
+	}
+}
+
+func systemDiskSize(d *schema.ResourceData) int64 {
+	if d.Get("system_disk_size").Exists() {
+		return d.Get("system_disk_size").Int()
+	}
+
+	return int64(defaultSystemDiskSize)
+}
+
+// diskCostComponent is the cost of a cloud disk. The pay-as-you-go disk prices are for the
+// whole disk per hour rather than per GB, so the size is part of the module config.
+func diskCostComponent(region, name, moduleCode, category string, size int64) *schema.CostComponent {
+	if category == "" {
+		category = "cloud_efficiency"
+	}
+
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("%s (%s, %d GB)", name, category, size),
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter:  productFilter("ecs", region, moduleCode, fmt.Sprintf("%[1]s.Category:%[2]s,%[1]s.Size:%[3]d", moduleCode, category, size), "Hour"),
+	}
+}
+
+// internetBandwidthCostComponent is the cost of the public bandwidth of the instance, which
+// is either charged by the hour for the maximum bandwidth or by the outbound traffic.
+func internetBandwidthCostComponent(d *schema.ResourceData, u *schema.UsageData, region string) *schema.CostComponent {
+	bandwidth := d.Get("internet_max_bandwidth_out").Int()
+	if bandwidth == 0 {
+		return nil
+	}
+
+	if strings.EqualFold(d.Get("internet_charge_type").String(), "PayByBandwidth") {
+		return &schema.CostComponent{
+			Name:           fmt.Sprintf("Internet bandwidth (%d Mbps)", bandwidth),
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  productFilter("ecs", region, "InternetMaxBandwidthOut", fmt.Sprintf("InternetMaxBandwidthOut:%d", bandwidth*1024), "Hour"),
+		}
+	}
+
+	return outboundTrafficCostComponent(u, "ecs", region, "InternetMaxBandwidthOut", fmt.Sprintf("InternetMaxBandwidthOut:%d,InternetMaxBandwidthOut.IsFlowType:1", bandwidth*1024))
+}
+
+// outboundTrafficCostComponent is the cost of the outbound internet traffic of a product
+// that charges by traffic, from the monthly_outbound_data_transfer_gb usage key.
+func outboundTrafficCostComponent(u *schema.UsageData, productCode, region, moduleCode, config string) *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if u != nil && u.Get("monthly_outbound_data_transfer_gb").Exists() {
+		quantity = decimalPtr(decimal.NewFromFloat(u.Get("monthly_outbound_data_transfer_gb").Float()))
+	}
+
+	return &schema.CostComponent{
+		Name:            "Outbound internet data transfer",
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter:   productFilter(productCode, region, moduleCode, config, "Usage"),
+	}
+}
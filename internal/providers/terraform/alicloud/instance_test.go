@@ -0,0 +1,60 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewInstance(t *testing.T) {
+	d := schema.NewResourceData("alicloud_instance", "alicloud", "alicloud_instance.web", nil, gjson.Parse(`{
+		"region": "cn-shanghai",
+		"instance_type": "ecs.g6.large",
+		"image_id": "win2019_1809_x64_dtc_en-us_40G_alibase_20210916.vhd",
+		"system_disk_category": "cloud_essd",
+		"data_disks": [{"category": "cloud_ssd", "size": 100}],
+		"internet_max_bandwidth_out": 10
+	}`))
+	u := schema.NewUsageData("alicloud_instance.web", schema.ParseAttributes(map[string]interface{}{
+		"monthly_outbound_data_transfer_gb": 50,
+	}))
+
+	r := NewInstance(d, u)
+
+	names := make([]string, 0, len(r.CostComponents))
+	configs := make([]string, 0, len(r.CostComponents))
+	for _, c := range r.CostComponents {
+		names = append(names, c.Name)
+		configs = append(configs, *c.ProductFilter.AttributeFilters[1].Value)
+		assert.Equal(t, "alibaba", *c.ProductFilter.VendorName)
+		assert.Equal(t, "cn-shanghai", *c.ProductFilter.Region)
+	}
+
+	assert.Equal(t, []string{
+		"Instance usage (Windows, pay-as-you-go, ecs.g6.large)",
+		"System disk (cloud_essd, 40 GB)",
+		"Data disk 1 (cloud_ssd, 100 GB)",
+		"Outbound internet data transfer",
+	}, names)
+	assert.Equal(t, []string{
+		"InstanceType:ecs.g6.large,IoOptimized:IoOptimized,ImageOs:windows",
+		"SystemDisk.Category:cloud_essd,SystemDisk.Size:40",
+		"DataDisk.Category:cloud_ssd,DataDisk.Size:100",
+		"InternetMaxBandwidthOut:10240,InternetMaxBandwidthOut.IsFlowType:1",
+	}, configs)
+	assert.Equal(t, "50", r.CostComponents[3].MonthlyQuantity.String())
+}
+
+// componentFilters returns the product code, module code, config and price type of the
+// price of each cost component of the resource by name.
+func componentFilters(r *schema.Resource) map[string][4]string {
+	filters := make(map[string][4]string)
+	for _, c := range r.CostComponents {
+		f := c.ProductFilter
+		filters[c.Name] = [4]string{*f.Service, *f.AttributeFilters[0].Value, *f.AttributeFilters[1].Value, *f.AttributeFilters[2].Value}
+	}
+
+	return filters
+}
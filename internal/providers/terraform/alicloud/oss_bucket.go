@@ -0,0 +1,65 @@
+package alicloud
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetOSSBucketRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "alicloud_oss_bucket",
+		RFunc: NewOSSBucket,
+	}
+}
+
+func NewOSSBucket(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	storageClass := d.Get("storage_class").String()
+	if storageClass == "" {
+		storageClass = "Standard"
+	}
+
+	var storageGB, putRequests, getRequests *decimal.Decimal
+	if u != nil {
+		if u.Get("storage_gb").Exists() {
+			storageGB = decimalPtr(decimal.NewFromFloat(u.Get("storage_gb").Float()))
+		}
+		if u.Get("monthly_put_requests").Exists() {
+			putRequests = decimalPtr(decimal.NewFromInt(u.Get("monthly_put_requests").Int()))
+		}
+		if u.Get("monthly_get_requests").Exists() {
+			getRequests = decimalPtr(decimal.NewFromInt(u.Get("monthly_get_requests").Int()))
+		}
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            fmt.Sprintf("Storage (%s)", storageClass),
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: storageGB,
+				ProductFilter:   productFilter("oss", region, "Storage", fmt.Sprintf("StorageClass:%s", storageClass), "Usage"),
+			},
+			{
+				Name:            "PUT requests",
+				Unit:            "10k requests",
+				UnitMultiplier:  decimal.NewFromInt(10000),
+				MonthlyQuantity: putRequests,
+				ProductFilter:   productFilter("oss", region, "PutRequest", fmt.Sprintf("StorageClass:%s", storageClass), "Usage"),
+			},
+			{
+				Name:            "GET requests",
+				Unit:            "10k requests",
+				UnitMultiplier:  decimal.NewFromInt(10000),
+				MonthlyQuantity: getRequests,
+				ProductFilter:   productFilter("oss", region, "GetRequest", fmt.Sprintf("StorageClass:%s", storageClass), "Usage"),
+			},
+			outboundTrafficCostComponent(u, "oss", region, "NetworkOut", "NetworkOut:Internet"),
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewOSSBucket(t *testing.T) {
+	d := schema.NewResourceData("alicloud_oss_bucket", "alicloud", "alicloud_oss_bucket.bucket", nil, gjson.Parse(`{"region": "cn-beijing", "storage_class": "IA"}`))
+	u := schema.NewUsageData("alicloud_oss_bucket.bucket", schema.ParseAttributes(map[string]interface{}{
+		"storage_gb":                        1000,
+		"monthly_put_requests":              20000,
+		"monthly_get_requests":              100000,
+		"monthly_outbound_data_transfer_gb": 50,
+	}))
+
+	r := NewOSSBucket(d, u)
+
+	assert.Equal(t, map[string][4]string{
+		"Storage (IA)":                    {"oss", "Storage", "StorageClass:IA", "Usage"},
+		"PUT requests":                    {"oss", "PutRequest", "StorageClass:IA", "Usage"},
+		"GET requests":                    {"oss", "GetRequest", "StorageClass:IA", "Usage"},
+		"Outbound internet data transfer": {"oss", "NetworkOut", "NetworkOut:Internet", "Usage"},
+	}, componentFilters(r))
+
+	quantities := make(map[string]string)
+	for _, c := range r.CostComponents {
+		quantities[c.Name] = c.MonthlyQuantity.String()
+	}
+
+	assert.Equal(t, map[string]string{
+		"Storage (IA)":                    "1000",
+		"PUT requests":                    "20000",
+		"GET requests":                    "100000",
+		"Outbound internet data transfer": "50",
+	}, quantities)
+}
+
+func TestNewOSSBucketWithoutUsage(t *testing.T) {
+	d := schema.NewResourceData("alicloud_oss_bucket", "alicloud", "alicloud_oss_bucket.bucket", nil, gjson.Parse(`{"region": "cn-beijing"}`))
+	r := NewOSSBucket(d, nil)
+
+	assert.Equal(t, "Storage (Standard)", r.CostComponents[0].Name)
+	assert.Equal(t, "StorageClass:Standard", *r.CostComponents[0].ProductFilter.AttributeFilters[1].Value)
+	for _, c := range r.CostComponents {
+		assert.Nil(t, c.MonthlyQuantity)
+	}
+}
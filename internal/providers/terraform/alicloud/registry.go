@@ -0,0 +1,35 @@
+package alicloud
+
+import "github.com/infracost/infracost/internal/schema"
+
+var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
+	GetDBInstanceRegistryItem(),
+	GetInstanceRegistryItem(),
+	GetOSSBucketRegistryItem(),
+	GetSLBRegistryItem(),
+	GetSLBLoadBalancerRegistryItem(),
+}
+
+// FreeResources grouped alphabetically
+var FreeResources []string = []string{
+	"alicloud_db_account",
+	"alicloud_db_account_privilege",
+	"alicloud_db_database",
+	"alicloud_key_pair",
+	"alicloud_oss_bucket_object",
+	"alicloud_ram_policy",
+	"alicloud_ram_role",
+	"alicloud_ram_role_policy_attachment",
+	"alicloud_ram_user",
+	"alicloud_security_group",
+	"alicloud_security_group_rule",
+	"alicloud_slb_acl",
+	"alicloud_slb_attachment",
+	"alicloud_slb_backend_server",
+	"alicloud_slb_listener",
+	"alicloud_slb_server_group",
+	"alicloud_vpc",
+	"alicloud_vswitch",
+}
+
+var UsageOnlyResources []string = []string{}
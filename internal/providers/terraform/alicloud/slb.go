@@ -0,0 +1,66 @@
+package alicloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetSLBRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "alicloud_slb",
+		RFunc: NewSLB,
+	}
+}
+
+// alicloud_slb_load_balancer replaced alicloud_slb in newer versions of the provider
+func GetSLBLoadBalancerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "alicloud_slb_load_balancer",
+		RFunc: NewSLB,
+	}
+}
+
+func NewSLB(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	// The spec attribute was renamed from specification to load_balancer_spec
+	spec := d.Get("load_balancer_spec").String()
+	if spec == "" {
+		spec = d.Get("specification").String()
+	}
+
+	costComponents := []*schema.CostComponent{
+		{
+			Name:           "Instance fee",
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  productFilter("slb", region, "InstanceRent", "InstanceRent:1", "Hour"),
+		},
+	}
+
+	if spec != "" {
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:           fmt.Sprintf("Specification fee (%s)", spec),
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  productFilter("slb", region, "LoadBalancerSpec", fmt.Sprintf("LoadBalancerSpec:%s", spec), "Hour"),
+		})
+	}
+
+	// Only internet-facing load balancers have traffic fees, PayByTraffic is the default
+	addressType := d.Get("address_type").String()
+	internetChargeType := d.Get("internet_charge_type").String()
+	if !strings.EqualFold(addressType, "intranet") && (internetChargeType == "" || strings.EqualFold(internetChargeType, "PayByTraffic")) {
+		costComponents = append(costComponents, outboundTrafficCostComponent(u, "slb", region, "InternetTraffic", "InternetTraffic:1"))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
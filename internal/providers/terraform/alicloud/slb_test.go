@@ -0,0 +1,63 @@
+package alicloud
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewSLB(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  string
+		filters map[string][4]string
+	}{
+		{
+			name:   "intranet",
+			values: `{"region": "cn-hangzhou", "load_balancer_spec": "slb.s2.small", "address_type": "intranet"}`,
+			filters: map[string][4]string{
+				"Instance fee":                     {"slb", "InstanceRent", "InstanceRent:1", "Hour"},
+				"Specification fee (slb.s2.small)": {"slb", "LoadBalancerSpec", "LoadBalancerSpec:slb.s2.small", "Hour"},
+			},
+		},
+		{
+			name:   "internet with the deprecated specification attribute",
+			values: `{"region": "cn-hangzhou", "specification": "slb.s1.small", "address_type": "internet"}`,
+			filters: map[string][4]string{
+				"Instance fee":                     {"slb", "InstanceRent", "InstanceRent:1", "Hour"},
+				"Specification fee (slb.s1.small)": {"slb", "LoadBalancerSpec", "LoadBalancerSpec:slb.s1.small", "Hour"},
+				"Outbound internet data transfer":  {"slb", "InternetTraffic", "InternetTraffic:1", "Usage"},
+			},
+		},
+		{
+			name:   "internet paid by bandwidth",
+			values: `{"region": "cn-hangzhou", "address_type": "internet", "internet_charge_type": "PayByBandwidth"}`,
+			filters: map[string][4]string{
+				"Instance fee": {"slb", "InstanceRent", "InstanceRent:1", "Hour"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("alicloud_slb_load_balancer", "alicloud", "alicloud_slb_load_balancer.lb", nil, gjson.Parse(tt.values))
+			r := NewSLB(d, nil)
+
+			assert.Equal(t, tt.filters, componentFilters(r))
+		})
+	}
+}
+
+func TestNewSLBOutboundTraffic(t *testing.T) {
+	d := schema.NewResourceData("alicloud_slb", "alicloud", "alicloud_slb.lb", nil, gjson.Parse(`{"region": "cn-hangzhou", "address_type": "internet"}`))
+	u := schema.NewUsageData("alicloud_slb.lb", schema.ParseAttributes(map[string]interface{}{
+		"monthly_outbound_data_transfer_gb": 200,
+	}))
+
+	r := NewSLB(d, u)
+
+	assert.Len(t, r.CostComponents, 2)
+	assert.Equal(t, "200", r.CostComponents[1].MonthlyQuantity.String())
+}
@@ -0,0 +1,32 @@
+package alicloud
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+// productFilter returns the filter for the price of a module of an Alibaba Cloud product
+// from the GetPayAsYouGoPrice action of the BSS OpenAPI, e.g. the InstanceType module of ecs.
+// The config is the module's comma separated key:value pairs and the price type is Hour for
+// prices that are charged by the hour, or Usage for prices charged by the amount used.
+func productFilter(productCode, region, moduleCode, config, priceType string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("alibaba"),
+		Region:     strPtr(region),
+		Service:    strPtr(productCode),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "moduleCode", Value: strPtr(moduleCode)},
+			{Key: "config", Value: strPtr(config)},
+			{Key: "priceType", Value: strPtr(priceType)},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetAppSyncGraphQLAPIRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_appsync_graphql_api",
+		RFunc: NewAppSyncGraphQLAPI,
+	}
+}
+
+func NewAppSyncGraphQLAPI(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var monthlyOperations, monthlyRealtimeUpdates, monthlyConnectionMinutes *decimal.Decimal
+
+	if u != nil && u.Get("monthly_query_mutation_operations").Exists() {
+		monthlyOperations = decimalPtr(decimal.NewFromInt(u.Get("monthly_query_mutation_operations").Int()))
+	}
+
+	if u != nil && u.Get("monthly_realtime_updates").Exists() {
+		monthlyRealtimeUpdates = decimalPtr(decimal.NewFromInt(u.Get("monthly_realtime_updates").Int()))
+	}
+
+	if u != nil && u.Get("monthly_connection_mins").Exists() {
+		monthlyConnectionMinutes = decimalPtr(decimal.NewFromInt(u.Get("monthly_connection_mins").Int()))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			appSyncCostComponent(region, "Query and data modification operations", "operations", "Request", monthlyOperations),
+			appSyncCostComponent(region, "Real-time updates", "updates", "RealTimeUpdates", monthlyRealtimeUpdates),
+			appSyncCostComponent(region, "Connection duration", "minutes", "ConnectionMinutes", monthlyConnectionMinutes),
+		},
+	}
+}
+
+func appSyncCostComponent(region string, displayName string, unit string, usageType string, monthlyQuantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            displayName,
+		Unit:            "1M " + unit,
+		UnitMultiplier:  decimal.NewFromInt(1000000),
+		MonthlyQuantity: monthlyQuantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AWSAppSync"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/AppSync-%s/i", usageType))},
+			},
+		},
+	}
+}
@@ -125,7 +125,7 @@ func newLaunchConfiguration(name string, d *schema.ResourceData, u *schema.Usage
 	return &schema.Resource{
 		Name:           name,
 		SubResources:   subResources,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
 	}
 }
 
@@ -196,6 +196,8 @@ func newLaunchTemplate(name string, d *schema.ResourceData, u *schema.UsageData,
 		r.CostComponents = append([]*schema.CostComponent{c}, r.CostComponents...)
 	}
 
+	r.CostComponents = reservedUpfrontCostComponents(r.CostComponents)
+
 	return r
 }
 
@@ -46,7 +46,7 @@ func NewBackupVault(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 
 	for _, d := range data {
 		if u != nil && u.Get(d.ref).Type != gjson.Null {
-			d.qty = decimalPtr(decimal.NewFromInt(u.Get(d.ref).Int()))
+			d.qty = decimalPtr(decimal.NewFromFloat(u.Get(d.ref).Float()))
 		}
 
 		costComponents = append(costComponents, backupVaultCostComponent(region, d))
@@ -65,7 +65,7 @@ func NewBackupVault(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 
 	for _, d := range additData {
 		if u != nil && u.Get(d.ref).Type != gjson.Null {
-			d.qty = decimalPtr(decimal.NewFromInt(u.Get(d.ref).Int()))
+			d.qty = decimalPtr(decimal.NewFromFloat(u.Get(d.ref).Float()))
 		}
 
 		costComponents = append(costComponents, additionalBackupVaultCostComponent(region, d))
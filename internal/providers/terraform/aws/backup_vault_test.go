@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestBackupVault(t *testing.T) {
@@ -14,3 +18,30 @@ func TestBackupVault(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "backup_vault_test")
 }
+
+func TestBackupVaultFractionalUsage(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_backup_vault", "aws", "aws_backup_vault.vault", nil, gjson.Parse(`{
+		"region": "us-east-1"
+	}`))
+
+	u := schema.NewUsageData("aws_backup_vault.vault", schema.ParseAttributes(map[string]interface{}{
+		"monthly_efs_warm_backup_gb":   0.5,
+		"monthly_fsx_lustre_backup_gb": 12.25,
+	}))
+
+	r := aws.NewBackupVault(d, u)
+
+	quantities := make(map[string]string)
+	for _, c := range r.CostComponents {
+		if c.MonthlyQuantity != nil {
+			quantities[c.Name] = c.MonthlyQuantity.String()
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"EFS backup (warm)":     "0.5",
+		"FSx for lustre backup": "12.25",
+	}, quantities)
+}
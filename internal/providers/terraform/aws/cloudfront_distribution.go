@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/resources"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
 )
@@ -14,14 +15,12 @@ type regionData struct {
 	usageKey       string
 }
 
-type usageFilterData struct {
-	usageNumber int64
-	usageName   string
-}
-
 func GetCloudfrontDistributionRegistryItem() *schema.RegistryItem {
 	return &schema.RegistryItem{
-		Name:  "aws_cloudfront_distribution",
+		Name: "aws_cloudfront_distribution",
+		Notes: []string{
+			"Lambda@Edge requests and duration are priced using the us-east-1 prices, wherever the edge locations that run the functions are.",
+		},
 		RFunc: NewCloudfrontDistribution,
 	}
 }
@@ -43,9 +42,30 @@ func NewCloudfrontDistribution(d *schema.ResourceData, u *schema.UsageData) *sch
 		},
 	}
 	resource.CostComponents = append(resource.CostComponents, invalidationRequests(u)...)
+
+	if cloudfrontHasAssociation(d, "function_association") {
+		resource.CostComponents = append(resource.CostComponents, functionInvocations(u))
+	}
+
+	if cloudfrontHasAssociation(d, "lambda_function_association") {
+		resource.SubResources = append(resource.SubResources, lambdaEdge(u))
+	}
+
 	return resource
 }
 
+// cloudfrontHasAssociation returns true if any of the cache behaviors has the given function association.
+func cloudfrontHasAssociation(d *schema.ResourceData, key string) bool {
+	behaviors := append(d.Get("default_cache_behavior").Array(), d.Get("ordered_cache_behavior").Array()...)
+	for _, behavior := range behaviors {
+		if len(behavior.Get(key).Array()) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 func regionalDataOutToInternet(u *schema.UsageData) *schema.Resource {
 	resource := &schema.Resource{
 		Name:         "Data transfer out to internet",
@@ -95,55 +115,22 @@ func regionalDataOutToInternet(u *schema.UsageData) *schema.Resource {
 		},
 	}
 
-	usageFilters := []*usageFilterData{
-		{
-			usageNumber: 10240,
-			usageName:   "first 10TB",
-		},
-		{
-			usageNumber: 51200,
-			usageName:   "next 40TB",
-		},
-		{
-			usageNumber: 153600,
-			usageName:   "next 100TB",
-		},
-		{
-			usageNumber: 512000,
-			usageName:   "next 350TB",
-		},
-		{
-			usageNumber: 1048576,
-			usageName:   "next 524TB",
-		},
-		{
-			usageNumber: 5242880,
-			usageName:   "next 4PB",
-		},
-		{
-			usageNumber: 0,
-			usageName:   "over 5PB",
-		},
+	tiers := []resources.Tier{
+		{Name: "first 10TB", EndUsageAmount: 10240},
+		{Name: "next 40TB", EndUsageAmount: 51200},
+		{Name: "next 100TB", EndUsageAmount: 153600},
+		{Name: "next 350TB", EndUsageAmount: 512000},
+		{Name: "next 524TB", EndUsageAmount: 1048576},
+		{Name: "next 4PB", EndUsageAmount: 5242880},
+		{Name: "over 5PB"},
 	}
 
 	// Because india has different usage amounts
-	indiaUsageFilters := []*usageFilterData{
-		{
-			usageNumber: 10240,
-			usageName:   "first 10TB",
-		},
-		{
-			usageNumber: 51200,
-			usageName:   "next 40TB",
-		},
-		{
-			usageNumber: 153600,
-			usageName:   "next 100TB",
-		},
-		{
-			usageNumber: 0,
-			usageName:   "over 150TB",
-		},
+	indiaTiers := []resources.Tier{
+		{Name: "first 10TB", EndUsageAmount: 10240},
+		{Name: "next 40TB", EndUsageAmount: 51200},
+		{Name: "next 100TB", EndUsageAmount: 153600},
+		{Name: "over 150TB"},
 	}
 
 	for _, regData := range regionsData {
@@ -151,44 +138,22 @@ func regionalDataOutToInternet(u *schema.UsageData) *schema.Resource {
 		apiRegion := regData.priceRegion
 		usageKey := regData.usageKey
 
-		var usage int64
-		var used int64
-		var lastEndUsageAmount int64
+		var quantity *decimal.Decimal
 		if u != nil && u.Get(usageKey).Exists() {
-			usage = u.Get(usageKey).Int()
+			quantity = decimalPtr(decimal.NewFromInt(u.Get(usageKey).Int()))
 		}
 
-		selectedUsageFilters := usageFilters
+		selectedTiers := tiers
 		if strings.ToLower(apiRegion) == "india" {
-			selectedUsageFilters = indiaUsageFilters
+			selectedTiers = indiaTiers
 		}
-		for idx, usageFilter := range selectedUsageFilters {
-			usageName := usageFilter.usageName
-			endUsageAmount := usageFilter.usageNumber
-			var quantity *decimal.Decimal
-			if endUsageAmount != 0 && usage >= endUsageAmount {
-				used = endUsageAmount - used
-				lastEndUsageAmount = endUsageAmount
-				quantity = decimalPtr(decimal.NewFromInt(used))
-			} else if usage > lastEndUsageAmount {
-				used = usage - lastEndUsageAmount
-				lastEndUsageAmount = endUsageAmount
-				quantity = decimalPtr(decimal.NewFromInt(used))
-			}
-			var usageFilter string
-			if endUsageAmount != 0 {
-				usageFilter = fmt.Sprint(endUsageAmount)
-			} else {
-				usageFilter = "Inf"
-			}
-			if quantity == nil && idx > 0 {
-				continue
-			}
-			resource.CostComponents = append(resource.CostComponents, &schema.CostComponent{
-				Name:            fmt.Sprintf("%v (%v)", awsRegion, usageName),
+
+		resource.CostComponents = append(resource.CostComponents, resources.TieredCostComponents(quantity, selectedTiers, func(tier resources.TierQuantity) *schema.CostComponent {
+			return &schema.CostComponent{
+				Name:            fmt.Sprintf("%v (%v)", awsRegion, tier.Name),
 				Unit:            "GB",
 				UnitMultiplier:  decimal.NewFromInt(1),
-				MonthlyQuantity: quantity,
+				MonthlyQuantity: tier.Quantity,
 				ProductFilter: &schema.ProductFilter{
 					VendorName: strPtr("aws"),
 					Service:    strPtr("AmazonCloudFront"),
@@ -198,11 +163,10 @@ func regionalDataOutToInternet(u *schema.UsageData) *schema.Resource {
 					},
 				},
 				PriceFilter: &schema.PriceFilter{
-					EndUsageAmount: strPtr(usageFilter),
+					EndUsageAmount: strPtr(tier.EndUsageAmountFilter()),
 				},
-			})
-		}
-
+			}
+		})...)
 	}
 
 	return resource
@@ -626,3 +590,85 @@ func customSSLCertificate(u *schema.UsageData) *schema.CostComponent {
 		},
 	}
 }
+
+func functionInvocations(u *schema.UsageData) *schema.CostComponent {
+	var quantity *decimal.Decimal
+	if u != nil && u.Get("monthly_function_invocations").Exists() {
+		quantity = decimalPtr(decimal.NewFromInt(u.Get("monthly_function_invocations").Int()))
+	}
+	return &schema.CostComponent{
+		Name:            "CloudFront Functions invocations",
+		Unit:            "1M invocations",
+		UnitMultiplier:  decimal.NewFromInt(1000000),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Service:    strPtr("AmazonCloudFront"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/Executions-CloudFrontFunctions/")},
+			},
+		},
+	}
+}
+
+func lambdaEdge(u *schema.UsageData) *schema.Resource {
+	memorySize := decimal.NewFromInt(128)
+	if u != nil && u.Get("lambda_edge_memory_mb").Exists() {
+		memorySize = decimal.NewFromInt(u.Get("lambda_edge_memory_mb").Int())
+	}
+
+	averageRequestDuration := decimal.NewFromInt(1)
+	if u != nil && u.Get("lambda_edge_request_duration_ms").Exists() {
+		averageRequestDuration = decimal.NewFromFloat(u.Get("lambda_edge_request_duration_ms").Float())
+	}
+
+	var monthlyRequests, gbSeconds *decimal.Decimal
+	if u != nil && u.Get("monthly_lambda_edge_requests").Exists() {
+		monthlyRequests = decimalPtr(decimal.NewFromInt(u.Get("monthly_lambda_edge_requests").Int()))
+
+		// Duration is rounded up to the closest 1ms
+		gb := memorySize.Div(decimal.NewFromInt(1024))
+		seconds := averageRequestDuration.Ceil().Div(decimal.NewFromInt(1000))
+		gbSeconds = decimalPtr(monthlyRequests.Mul(gb).Mul(seconds))
+	}
+
+	// Lambda@Edge functions are always created in us-east-1, so the us-east-1 prices are used
+	// wherever they run
+	return &schema.Resource{
+		Name: "Lambda@Edge",
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Requests",
+				Unit:            "1M requests",
+				UnitMultiplier:  decimal.NewFromInt(1000000),
+				MonthlyQuantity: monthlyRequests,
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("aws"),
+					Region:        strPtr("us-east-1"),
+					Service:       strPtr("AWSLambda"),
+					ProductFamily: strPtr("Serverless"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "group", Value: strPtr("AWS-Lambda-Edge-Requests")},
+						{Key: "usagetype", ValueRegex: strPtr("/Lambda-Edge-Request/")},
+					},
+				},
+			},
+			{
+				Name:            "Duration",
+				Unit:            "GB-seconds",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: gbSeconds,
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("aws"),
+					Region:        strPtr("us-east-1"),
+					Service:       strPtr("AWSLambda"),
+					ProductFamily: strPtr("Serverless"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "group", Value: strPtr("AWS-Lambda-Edge-Duration")},
+						{Key: "usagetype", ValueRegex: strPtr("/Lambda-Edge-GB-Second/")},
+					},
+				},
+			},
+		},
+	}
+}
@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestCloudfrontDistributionGoldenFile(t *testing.T) {
@@ -14,3 +18,48 @@ func TestCloudfrontDistributionGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "cloudfront_distribution_test")
 }
+
+func TestCloudfrontDistributionFunctionAssociations(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_cloudfront_distribution", "aws", "aws_cloudfront_distribution.distribution", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"default_cache_behavior": [{"function_association": [{"event_type": "viewer-request"}]}],
+		"ordered_cache_behavior": [{}, {"lambda_function_association": [{"event_type": "origin-request"}]}]
+	}`))
+
+	u := schema.NewUsageData("aws_cloudfront_distribution.distribution", schema.ParseAttributes(map[string]interface{}{
+		"monthly_function_invocations":    5000000,
+		"monthly_lambda_edge_requests":    2000000,
+		"lambda_edge_request_duration_ms": 49.5,
+		"lambda_edge_memory_mb":           512,
+	}))
+
+	r := aws.NewCloudfrontDistribution(d, u)
+
+	invocations := r.CostComponents[len(r.CostComponents)-1]
+	assert.Equal(t, "CloudFront Functions invocations", invocations.Name)
+	assert.Equal(t, "5000000", invocations.MonthlyQuantity.String())
+
+	lambdaEdge := r.SubResources[len(r.SubResources)-1]
+	assert.Equal(t, "Lambda@Edge", lambdaEdge.Name)
+	assert.Equal(t, "2000000", lambdaEdge.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "50000", lambdaEdge.CostComponents[1].MonthlyQuantity.String())
+}
+
+func TestCloudfrontDistributionWithoutFunctionAssociations(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_cloudfront_distribution", "aws", "aws_cloudfront_distribution.distribution", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"default_cache_behavior": [{"target_origin_id": "origin"}]
+	}`))
+
+	r := aws.NewCloudfrontDistribution(d, nil)
+	for _, c := range r.CostComponents {
+		assert.NotEqual(t, "CloudFront Functions invocations", c.Name)
+	}
+	for _, s := range r.SubResources {
+		assert.NotEqual(t, "Lambda@Edge", s.Name)
+	}
+}
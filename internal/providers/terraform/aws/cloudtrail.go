@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetCloudtrailRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_cloudtrail",
+		Notes: []string{
+			"The first copy of management events in each region is free, set monthly_additional_management_events in the usage file for the trails that deliver additional copies.",
+		},
+		RFunc: NewCloudtrail,
+	}
+}
+
+func NewCloudtrail(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	includeManagementEvents := true
+	includeDataEvents := len(d.Get("advanced_event_selector").Array()) > 0
+
+	// Trails log management events unless all of their event selectors exclude them
+	if eventSelectors := d.Get("event_selector").Array(); len(eventSelectors) > 0 {
+		includeManagementEvents = false
+		for _, selector := range eventSelectors {
+			if selector.Get("include_management_events").Type == gjson.Null || selector.Get("include_management_events").Bool() {
+				includeManagementEvents = true
+			}
+			if len(selector.Get("data_resource").Array()) > 0 {
+				includeDataEvents = true
+			}
+		}
+	}
+
+	costComponents := []*schema.CostComponent{}
+
+	if includeManagementEvents {
+		var monthlyManagementEvents *decimal.Decimal
+		if u != nil && u.Get("monthly_additional_management_events").Type != gjson.Null {
+			monthlyManagementEvents = decimalPtr(decimal.NewFromInt(u.Get("monthly_additional_management_events").Int()))
+		}
+
+		costComponents = append(costComponents, cloudtrailEventsCostComponent(region, "Additional management events", "PaidEventsRecorded", monthlyManagementEvents))
+	}
+
+	if includeDataEvents {
+		var monthlyDataEvents *decimal.Decimal
+		if u != nil && u.Get("monthly_data_events").Type != gjson.Null {
+			monthlyDataEvents = decimalPtr(decimal.NewFromInt(u.Get("monthly_data_events").Int()))
+		}
+
+		costComponents = append(costComponents, cloudtrailEventsCostComponent(region, "Data events", "DataEventsRecorded", monthlyDataEvents))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func cloudtrailEventsCostComponent(region, name, usageType string, monthlyEvents *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "100k events",
+		UnitMultiplier:  decimal.NewFromInt(100000),
+		MonthlyQuantity: monthlyEvents,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AWSCloudTrail"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/%s$/", usageType))},
+			},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetCloudtrailEventDataStoreRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_cloudtrail_event_data_store",
+		Notes: []string{"Storage beyond the retention period included with ingestion and queries are not supported yet."},
+		RFunc: NewCloudtrailEventDataStore,
+	}
+}
+
+func NewCloudtrailEventDataStore(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var monthlyIngestedGb *decimal.Decimal
+	if u != nil && u.Get("monthly_ingested_gb").Type != gjson.Null {
+		monthlyIngestedGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_ingested_gb").Float()))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Data ingested",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: monthlyIngestedGb,
+				ProductFilter: &schema.ProductFilter{
+					VendorName: strPtr("aws"),
+					Region:     strPtr(region),
+					Service:    strPtr("AWSCloudTrail"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "usagetype", ValueRegex: strPtr("/IngestionBytes$/")},
+					},
+				},
+			},
+		},
+	}
+}
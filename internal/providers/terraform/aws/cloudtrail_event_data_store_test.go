@@ -0,0 +1,30 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestCloudtrailEventDataStore(t *testing.T) {
+	t.Parallel()
+
+	// aws_cloudtrail_event_data_store was added in AWS provider v4, after the version the golden file tests use
+	d := schema.NewResourceData("aws_cloudtrail_event_data_store", "aws", "aws_cloudtrail_event_data_store.store", nil, gjson.Parse(`{
+		"region": "us-east-1"
+	}`))
+
+	r := aws.NewCloudtrailEventDataStore(d, nil)
+	assert.Len(t, r.CostComponents, 1)
+	assert.Nil(t, r.CostComponents[0].MonthlyQuantity)
+
+	u := schema.NewUsageData("aws_cloudtrail_event_data_store.store", schema.ParseAttributes(map[string]interface{}{
+		"monthly_ingested_gb": 12.5,
+	}))
+
+	r = aws.NewCloudtrailEventDataStore(d, u)
+	assert.Equal(t, "12.5", r.CostComponents[0].MonthlyQuantity.String())
+}
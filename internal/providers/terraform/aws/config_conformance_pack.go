@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+	"github.com/shopspring/decimal"
+)
+
+func GetConfigConformancePackItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_config_conformance_pack",
+		RFunc: NewConfigConformancePack,
+	}
+}
+
+func NewConfigConformancePack(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: conformancePackCostComponents(region, u),
+	}
+}
+
+// conformancePackCostComponents returns the cost components of the rule evaluations of
+// conformance packs, which are priced separately from the ones of the other config rules.
+func conformancePackCostComponents(region string, u *schema.UsageData) []*schema.CostComponent {
+	costComponents := []*schema.CostComponent{}
+
+	if u != nil && u.Get("monthly_rule_evaluations").Exists() {
+		monthlyEvaluations := decimal.NewFromInt(u.Get("monthly_rule_evaluations").Int())
+
+		evaluationsLimits := []int{1000000, 24000000}
+
+		evaluationsTiers := usage.CalculateTierBuckets(monthlyEvaluations, evaluationsLimits)
+
+		if evaluationsTiers[0].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, conformancePackEvaluationsCostComponent(region, "Rule evaluations (first 1M)", "0", &evaluationsTiers[0]))
+		}
+		if evaluationsTiers[1].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, conformancePackEvaluationsCostComponent(region, "Rule evaluations (next 24M)", "1000000", &evaluationsTiers[1]))
+		}
+		if evaluationsTiers[2].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, conformancePackEvaluationsCostComponent(region, "Rule evaluations (over 25M)", "25000000", &evaluationsTiers[2]))
+		}
+	} else {
+		var unknown *decimal.Decimal
+
+		costComponents = append(costComponents, conformancePackEvaluationsCostComponent(region, "Rule evaluations (first 1M)", "0", unknown))
+	}
+
+	return costComponents
+}
+
+func conformancePackEvaluationsCostComponent(region string, displayName string, usageTier string, monthlyQuantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            displayName,
+		Unit:            "evaluations",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: monthlyQuantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AWSConfig"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/ConformancePackEvaluation/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr(usageTier),
+		},
+	}
+}
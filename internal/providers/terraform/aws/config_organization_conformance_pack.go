@@ -0,0 +1,21 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetConfigOrganizationConformancePackItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_config_organization_conformance_pack",
+		RFunc: NewConfigOrganizationConformancePack,
+	}
+}
+
+func NewConfigOrganizationConformancePack(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: conformancePackCostComponents(region, u),
+	}
+}
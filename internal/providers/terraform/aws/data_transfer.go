@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/resources"
 	"github.com/infracost/infracost/internal/schema"
-	"github.com/infracost/infracost/internal/usage"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 )
@@ -39,18 +39,6 @@ var regionMapping = map[string]string{
 	"af-south-1":      "Africa (Cape Town)",
 }
 
-type dataTransferRegionUsageFilterData struct {
-	usageName      string
-	tierCapacity   int64
-	endUsageNumber int64
-}
-
-type UsageStepsFilterData struct {
-	usageName   string
-	usageFilter string
-	quantity    *decimal.Decimal
-}
-
 func GetDataTransferRegistryItem() *schema.RegistryItem {
 	return &schema.RegistryItem{
 		Name:  "aws_data_transfer",
@@ -122,7 +110,7 @@ func NewDataTransfer(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 	}
 
 	if outboundInternetGb != nil {
-		costComponents = append(costComponents, outboundInternet(fromLocation, outboundInternetGb.IntPart())...)
+		costComponents = append(costComponents, outboundInternet(fromLocation, decimalPtr(decimal.NewFromInt(outboundInternetGb.IntPart())))...)
 	}
 
 	if outboundUsEastGb != nil {
@@ -169,89 +157,30 @@ func NewDataTransfer(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 	}
 }
 
-func usageStepsFilterHelper(usageFiltersData []*dataTransferRegionUsageFilterData, usageAmount int64) []*UsageStepsFilterData {
-	results := make([]*UsageStepsFilterData, 0)
-	if len(usageFiltersData) == 1 {
-		quantity := decimal.NewFromInt(usageAmount)
-		results = append(results, &UsageStepsFilterData{
-			usageName:   usageFiltersData[0].usageName,
-			usageFilter: "Inf",
-			quantity:    &quantity,
-		})
-		return results
-	}
-	tierLimits := make([]int, len(usageFiltersData)-1)
-	for idx, usageFilter := range usageFiltersData {
-		if usageFilter.tierCapacity > 0 {
-			tierLimits[idx] = int(usageFilter.tierCapacity)
-		}
-	}
-	tiers := usage.CalculateTierBuckets(decimal.NewFromInt(usageAmount), tierLimits)
-	for idx, usageFilter := range usageFiltersData {
-		if tiers[idx].Equals(decimal.Zero) {
-			break
-		}
-		filter := fmt.Sprint(usageFilter.endUsageNumber)
-		if usageFilter.endUsageNumber == 0 {
-			filter = "Inf"
-		}
-		results = append(results, &UsageStepsFilterData{
-			usageName:   usageFilter.usageName,
-			usageFilter: filter,
-			quantity:    &tiers[idx],
-		})
+func outboundInternet(fromLocation string, networkUsage *decimal.Decimal) []*schema.CostComponent {
+	tiers := []resources.Tier{
+		{Name: "first 10TB", EndUsageAmount: 10240},
+		{Name: "next 40TB", EndUsageAmount: 51200},
+		{Name: "next 100TB", EndUsageAmount: 153600},
+		{Name: "over 150TB"},
 	}
-	return results
-}
 
-func outboundInternet(fromLocation string, networkUsage int64) []*schema.CostComponent {
-	costComponents := make([]*schema.CostComponent, 0)
-	defaultUsageFiltersData := []*dataTransferRegionUsageFilterData{
-		{
-			usageName:      "first 10TB",
-			tierCapacity:   10240,
-			endUsageNumber: 10240,
-		},
-		{
-			usageName:      "next 40TB",
-			tierCapacity:   40960,
-			endUsageNumber: 51200,
-		},
-		{
-			usageName:      "next 100TB",
-			tierCapacity:   102400,
-			endUsageNumber: 153600,
-		},
-		{
-			usageName:      "over 150TB",
-			tierCapacity:   0,
-			endUsageNumber: 0,
-		},
-	}
-	chinaUsageFiltersData := []*dataTransferRegionUsageFilterData{
-		{
-			usageName:      "",
-			tierCapacity:   0,
-			endUsageNumber: 0,
-		},
-	}
-	usageFiltersData := defaultUsageFiltersData
 	chinaLocations := map[string]struct{}{"China (Beijing)": {}, "China (Ningxia)": {}}
 	if _, ok := chinaLocations[fromLocation]; ok {
-		usageFiltersData = chinaUsageFiltersData
+		tiers = []resources.Tier{{}}
 	}
-	for _, usageStep := range usageStepsFilterHelper(usageFiltersData, networkUsage) {
-		var name string
-		if usageStep.usageName == "" {
-			name = "Outbound data transfer to Internet"
-		} else {
-			name = fmt.Sprintf("Outbound data transfer to Internet (%s)", usageStep.usageName)
+
+	return resources.TieredCostComponents(networkUsage, tiers, func(tier resources.TierQuantity) *schema.CostComponent {
+		name := "Outbound data transfer to Internet"
+		if tier.Name != "" {
+			name = fmt.Sprintf("Outbound data transfer to Internet (%s)", tier.Name)
 		}
-		costComponents = append(costComponents, &schema.CostComponent{
+
+		return &schema.CostComponent{
 			Name:            name,
 			Unit:            "GB",
 			UnitMultiplier:  decimal.NewFromInt(1),
-			MonthlyQuantity: usageStep.quantity,
+			MonthlyQuantity: tier.Quantity,
 			ProductFilter: &schema.ProductFilter{
 				VendorName:    strPtr("aws"),
 				Service:       strPtr("AWSDataTransfer"),
@@ -262,9 +191,8 @@ func outboundInternet(fromLocation string, networkUsage int64) []*schema.CostCom
 				},
 			},
 			PriceFilter: &schema.PriceFilter{
-				EndUsageAmount: strPtr(usageStep.usageFilter),
+				EndUsageAmount: strPtr(tier.EndUsageAmountFilter()),
 			},
-		})
-	}
-	return costComponents
+		}
+	})
 }
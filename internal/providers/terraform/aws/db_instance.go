@@ -108,9 +108,20 @@ func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource
 		})
 	}
 
+	instanceName := "Database instance"
+	purchaseOption := "on_demand"
+	instancePriceFilter := &schema.PriceFilter{
+		PurchaseOption: strPtr("on_demand"),
+	}
+	if term, paymentOption, ok := reservedTermUsage(d.Address, u); ok {
+		instanceName = "Database instance (reserved)"
+		purchaseOption = "reserved"
+		instancePriceFilter = reservedPriceFilter(term, paymentOption)
+	}
+
 	costComponents := []*schema.CostComponent{
 		{
-			Name:           "Database instance",
+			Name:           instanceName,
 			Unit:           "hours",
 			UnitMultiplier: decimal.NewFromInt(1),
 			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
@@ -121,9 +132,8 @@ func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource
 				ProductFamily:    strPtr("Database Instance"),
 				AttributeFilters: instanceAttributeFilters,
 			},
-			PriceFilter: &schema.PriceFilter{
-				PurchaseOption: strPtr("on_demand"),
-			},
+			PriceFilter:    instancePriceFilter,
+			PurchaseOption: purchaseOption,
 		},
 		{
 			Name:            "Database storage",
@@ -163,6 +173,6 @@ func NewDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource
 
 	return &schema.Resource{
 		Name:           d.Address,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
 	}
 }
@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestDBInstanceGoldenFile(t *testing.T) {
@@ -14,3 +18,45 @@ func TestDBInstanceGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "db_instance_test")
 }
+
+func TestDBInstanceReserved(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_db_instance", "aws", "aws_db_instance.db", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"engine": "mysql",
+		"instance_class": "db.t3.large",
+		"allocated_storage": 20
+	}`))
+
+	r := aws.NewDBInstance(d, nil)
+	assert.Equal(t, "Database instance", r.CostComponents[0].Name)
+	assert.Equal(t, "on_demand", *r.CostComponents[0].PriceFilter.PurchaseOption)
+
+	u := schema.NewUsageData("aws_db_instance.db", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term":           "1_year",
+		"reserved_instance_payment_option": "all_upfront",
+	}))
+
+	r = aws.NewDBInstance(d, u)
+	assert.Len(t, r.CostComponents, 3)
+
+	c := r.CostComponents[0]
+	assert.Equal(t, "Database instance (reserved)", c.Name)
+	assert.Equal(t, "reserved", c.PurchaseOption)
+	assert.Nil(t, c.PriceFilter.PurchaseOption)
+	assert.Equal(t, "1yr", *c.PriceFilter.TermLength)
+	assert.Equal(t, "All Upfront", *c.PriceFilter.TermPurchaseOption)
+
+	assert.Equal(t, "Reserved upfront fee (1yr, amortized)", r.CostComponents[1].Name)
+	assert.Equal(t, "Database storage", r.CostComponents[2].Name)
+
+	// Invalid options are ignored
+	u = schema.NewUsageData("aws_db_instance.db", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term":           "2_year",
+		"reserved_instance_payment_option": "all_upfront",
+	}))
+
+	r = aws.NewDBInstance(d, u)
+	assert.Equal(t, "Database instance", r.CostComponents[0].Name)
+}
@@ -41,7 +41,15 @@ func NewDXConnection(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 
 	virtualInterfaceType := "private"
 	if u != nil && u.Get("dx_virtual_interface_type").Exists() {
-		virtualInterfaceType = u.Get("dx_virtual_interface_type").String()
+		switch t := strings.ToLower(u.Get("dx_virtual_interface_type").String()); t {
+		case "private", "public":
+			virtualInterfaceType = t
+		case "transit":
+			// Data transferred out over transit virtual interfaces is charged at the private rates
+			virtualInterfaceType = "private"
+		default:
+			log.Warnf("Invalid dx_virtual_interface_type for %s, defaulting to private. Expected: private, public, transit. Got: %s", d.Address, t)
+		}
 	}
 
 	return &schema.Resource{
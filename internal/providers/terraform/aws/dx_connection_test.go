@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestDXConnectionGoldenFile(t *testing.T) {
@@ -14,3 +18,32 @@ func TestDXConnectionGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "dx_connection_test")
 }
+
+func TestDXConnectionVirtualInterfaceType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		interfaceType string
+		expected      string
+	}{
+		{"public", "/public/i"},
+		{"Transit", "/private/i"},
+		{"unknown", "/private/i"},
+	}
+
+	for _, tt := range tests {
+		d := schema.NewResourceData("aws_dx_connection", "aws", "aws_dx_connection.dx", nil, gjson.Parse(`{
+			"region": "us-east-1",
+			"bandwidth": "1Gbps",
+			"location": "EqDC2"
+		}`))
+
+		u := schema.NewUsageData("aws_dx_connection.dx", schema.ParseAttributes(map[string]interface{}{
+			"dx_virtual_interface_type": tt.interfaceType,
+		}))
+
+		r := aws.NewDXConnection(d, u)
+		filters := r.CostComponents[1].ProductFilter.AttributeFilters
+		assert.Equal(t, tt.expected, *filters[len(filters)-1].ValueRegex, tt.interfaceType)
+	}
+}
@@ -33,7 +33,8 @@ func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 		costComponents = append(costComponents, efsStorageCostComponent("Storage (standard)", region, "-TimedStorage-ByteHrs", gbStorage))
 	}
 
-	if d.Get("provisioned_throughput_in_mibps").Exists() && d.Get("provisioned_throughput_in_mibps").Type != gjson.Null {
+	// provisioned_throughput_in_mibps is kept when switching back to bursting, so check the mode too
+	if d.Get("throughput_mode").String() == "provisioned" && d.Get("provisioned_throughput_in_mibps").Type != gjson.Null {
 		throughput := decimal.NewFromFloat(d.Get("provisioned_throughput_in_mibps").Float())
 		provisionedThroughput := calculateProvisionedThroughput(gbStorage, throughput)
 
@@ -54,8 +55,7 @@ func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 		})
 	}
 
-	if len(d.Get("lifecycle_policy").Array()) > 0 {
-
+	if efsInfrequentAccessEnabled(d) {
 		var infrequentAccessReadGbRequests *decimal.Decimal
 		if u != nil && u.Get("monthly_infrequent_access_read_gb").Type != gjson.Null {
 			infrequentAccessReadGbRequests = decimalPtr(decimal.NewFromFloat(u.Get("monthly_infrequent_access_read_gb").Float()))
@@ -111,6 +111,18 @@ func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resou
 	}
 }
 
+// efsInfrequentAccessEnabled returns true if the lifecycle policy moves files to the
+// Infrequent Access storage class. A policy can also only move them back to the primary one.
+func efsInfrequentAccessEnabled(d *schema.ResourceData) bool {
+	for _, policy := range d.Get("lifecycle_policy").Array() {
+		if policy.Get("transition_to_ia").String() != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func calculateProvisionedThroughput(gbStorage *decimal.Decimal, throughput decimal.Decimal) *decimal.Decimal {
 	if gbStorage == nil {
 		gbStorage = &decimal.Zero
@@ -125,9 +137,9 @@ func calculateProvisionedThroughput(gbStorage *decimal.Decimal, throughput decim
 
 	return &decimal.Zero
 }
+
 func efsStorageCostComponent(name, region, usagetype string, quantity *decimal.Decimal) *schema.CostComponent {
 	return &schema.CostComponent{
-
 		Name:            name,
 		Unit:            "GB",
 		UnitMultiplier:  decimal.NewFromInt(1),
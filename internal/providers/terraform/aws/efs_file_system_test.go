@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestNewEFSFileSystemStandardStorage(t *testing.T) {
@@ -14,3 +18,38 @@ func TestNewEFSFileSystemStandardStorage(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "efs_file_system_test")
 }
+
+func TestNewEFSFileSystemLifecyclePolicy(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_efs_file_system", "aws", "aws_efs_file_system.fs", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"lifecycle_policy": [{"transition_to_primary_storage_class": "AFTER_1_ACCESS"}]
+	}`))
+
+	r := aws.NewEFSFileSystem(d, nil)
+	assert.Len(t, r.CostComponents, 1)
+	assert.Equal(t, "Storage (standard)", r.CostComponents[0].Name)
+
+	d = schema.NewResourceData("aws_efs_file_system", "aws", "aws_efs_file_system.fs", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"lifecycle_policy": [{"transition_to_primary_storage_class": "AFTER_1_ACCESS"}, {"transition_to_ia": "AFTER_30_DAYS"}]
+	}`))
+
+	r = aws.NewEFSFileSystem(d, nil)
+	assert.Len(t, r.CostComponents, 4)
+	assert.Equal(t, "Storage (standard, infrequent access)", r.CostComponents[1].Name)
+}
+
+func TestNewEFSFileSystemBurstingThroughput(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_efs_file_system", "aws", "aws_efs_file_system.fs", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"throughput_mode": "bursting",
+		"provisioned_throughput_in_mibps": 100
+	}`))
+
+	r := aws.NewEFSFileSystem(d, nil)
+	assert.Len(t, r.CostComponents, 1)
+}
@@ -12,12 +12,55 @@ func GetNewEKSFargateProfileItem() *schema.RegistryItem {
 	}
 }
 
+// fargatePodSize is a vCPU size that Fargate runs pods with and the memory sizes in GB that
+// it can have, from minMemoryGB to maxMemoryGB in steps of memoryStepGB.
+type fargatePodSize struct {
+	vcpu         float64
+	minMemoryGB  float64
+	maxMemoryGB  float64
+	memoryStepGB float64
+}
+
+// fargatePodSizes are the vCPU and memory combinations that Fargate runs pods with, from
+// the smallest. The 0.25 vCPU size only has 0.5, 1 and 2 GB of memory.
+var fargatePodSizes = []fargatePodSize{
+	{0.25, 0.5, 0.5, 0.5},
+	{0.25, 1, 2, 1},
+	{0.5, 1, 4, 1},
+	{1, 2, 8, 1},
+	{2, 4, 16, 1},
+	{4, 8, 30, 1},
+	{8, 16, 60, 4},
+	{16, 32, 120, 8},
+}
+
+// NewEKSFargateProfile prices the pods that run on the profile. Fargate charges for the vCPU
+// and memory of each pod, so they're set with the pods, pod_vcpu and pod_memory_gb usage
+// keys, which default to a single pod with 1 vCPU and 1 GB. The vCPU and memory of the pods
+// are rounded up to the smallest Fargate size that they fit in.
 func NewEKSFargateProfile(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	region := d.Get("region").String()
 	costComponents := make([]*schema.CostComponent, 0)
 
-	costComponents = append(costComponents, memoryCostComponent(d, region))
-	costComponents = append(costComponents, vcpuCostComponent(d, region))
+	pods := decimal.NewFromInt(1)
+	vcpu := decimal.NewFromInt(1)
+	memoryGB := decimal.NewFromInt(1)
+	if u != nil {
+		if u.Get("pods").Exists() {
+			pods = decimal.NewFromFloat(u.Get("pods").Float())
+		}
+		if u.Get("pod_vcpu").Exists() {
+			vcpu = decimal.NewFromFloat(u.Get("pod_vcpu").Float())
+		}
+		if u.Get("pod_memory_gb").Exists() {
+			memoryGB = decimal.NewFromFloat(u.Get("pod_memory_gb").Float())
+		}
+	}
+
+	vcpu, memoryGB = fargatePodSizeFor(vcpu, memoryGB)
+
+	costComponents = append(costComponents, memoryCostComponent(d, region, pods.Mul(memoryGB)))
+	costComponents = append(costComponents, vcpuCostComponent(d, region, pods.Mul(vcpu)))
 
 	return &schema.Resource{
 		Name:           d.Address,
@@ -25,12 +68,38 @@ func NewEKSFargateProfile(d *schema.ResourceData, u *schema.UsageData) *schema.R
 	}
 }
 
-func memoryCostComponent(d *schema.ResourceData, region string) *schema.CostComponent {
+// fargatePodSizeFor returns the vCPU and memory of the smallest Fargate size that a pod with
+// the vCPU and memory fits in. The values are returned as they are if the pod is larger than
+// all of the sizes.
+func fargatePodSizeFor(vcpu decimal.Decimal, memoryGB decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	for _, size := range fargatePodSizes {
+		sizeVCPU := decimal.NewFromFloat(size.vcpu)
+		minMemoryGB := decimal.NewFromFloat(size.minMemoryGB)
+		maxMemoryGB := decimal.NewFromFloat(size.maxMemoryGB)
+
+		if vcpu.GreaterThan(sizeVCPU) || memoryGB.GreaterThan(maxMemoryGB) {
+			continue
+		}
+
+		if memoryGB.LessThanOrEqual(minMemoryGB) {
+			return sizeVCPU, minMemoryGB
+		}
+
+		step := decimal.NewFromFloat(size.memoryStepGB)
+		steps := memoryGB.Sub(minMemoryGB).Div(step).Ceil()
+
+		return sizeVCPU, minMemoryGB.Add(steps.Mul(step))
+	}
+
+	return vcpu, memoryGB
+}
+
+func memoryCostComponent(d *schema.ResourceData, region string, memoryGB decimal.Decimal) *schema.CostComponent {
 	return &schema.CostComponent{
 		Name:           "Per GB per hour",
 		Unit:           "GB",
 		UnitMultiplier: schema.HourToMonthUnitMultiplier,
-		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		HourlyQuantity: decimalPtr(memoryGB),
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
 			Region:        strPtr(region),
@@ -46,12 +115,12 @@ func memoryCostComponent(d *schema.ResourceData, region string) *schema.CostComp
 	}
 }
 
-func vcpuCostComponent(d *schema.ResourceData, region string) *schema.CostComponent {
+func vcpuCostComponent(d *schema.ResourceData, region string, vcpu decimal.Decimal) *schema.CostComponent {
 	return &schema.CostComponent{
 		Name:           "Per vCPU per hour",
 		Unit:           "CPU",
 		UnitMultiplier: schema.HourToMonthUnitMultiplier,
-		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		HourlyQuantity: decimalPtr(vcpu),
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
 			Region:        strPtr(region),
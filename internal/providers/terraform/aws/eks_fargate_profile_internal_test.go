@@ -0,0 +1,39 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFargatePodSizeFor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		vcpu             float64
+		memoryGB         float64
+		expectedVCPU     float64
+		expectedMemoryGB float64
+	}{
+		{0.25, 0.5, 0.25, 0.5},
+		{0.1, 0.3, 0.25, 0.5},
+		{0.25, 0.75, 0.25, 1},
+		{0.25, 1.5, 0.25, 2},
+		{0.25, 3, 0.5, 3},
+		{0.5, 1, 0.5, 1},
+		{1, 1, 1, 2},
+		{1, 2.5, 1, 3},
+		{1.5, 4, 2, 4},
+		{4, 31, 8, 32},
+		{8, 17, 8, 20},
+		{16, 33, 16, 40},
+		{32, 64, 32, 64},
+	}
+
+	for _, test := range tests {
+		vcpu, memoryGB := fargatePodSizeFor(decimal.NewFromFloat(test.vcpu), decimal.NewFromFloat(test.memoryGB))
+		assert.Equal(t, decimal.NewFromFloat(test.expectedVCPU).String(), vcpu.String(), "vcpu for %v vCPU and %v GB", test.vcpu, test.memoryGB)
+		assert.Equal(t, decimal.NewFromFloat(test.expectedMemoryGB).String(), memoryGB.String(), "memory for %v vCPU and %v GB", test.vcpu, test.memoryGB)
+	}
+}
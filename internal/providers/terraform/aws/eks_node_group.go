@@ -24,6 +24,9 @@ func NewEKSNodeGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 
 	scalingConfig := d.Get("scaling_config").Array()[0]
 	desiredSize := scalingConfig.Get("desired_size").Int()
+	if u != nil && u.Get("instances").Exists() {
+		desiredSize = u.Get("instances").Int()
+	}
 	purchaseOptionLabel := "on_demand"
 	if d.Get("capacity_type").String() != "" {
 		purchaseOptionLabel = strings.ToLower(d.Get("capacity_type").String())
@@ -94,7 +97,7 @@ func NewEKSNodeGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 
 	return &schema.Resource{
 		Name:           d.Address,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
 		SubResources:   subResources,
 	}
 }
@@ -44,9 +44,20 @@ func newElasticacheResource(d *schema.ResourceData, u *schema.UsageData, nodeTyp
 		snapShotRetentionLimit = decimal.NewFromInt(d.Get("snapshot_retention_limit").Int())
 	}
 
+	purchaseOptionLabel := "on-demand"
+	purchaseOption := "on_demand"
+	priceFilter := &schema.PriceFilter{
+		PurchaseOption: strPtr("on_demand"),
+	}
+	if term, paymentOption, ok := reservedTermUsage(d.Address, u); ok {
+		purchaseOptionLabel = "reserved"
+		purchaseOption = "reserved"
+		priceFilter = reservedPriceFilter(term, paymentOption)
+	}
+
 	costComponents := []*schema.CostComponent{
 		{
-			Name:           fmt.Sprintf("Elasticache (on-demand, %s)", nodeType),
+			Name:           fmt.Sprintf("Elasticache (%s, %s)", purchaseOptionLabel, nodeType),
 			Unit:           "hours",
 			UnitMultiplier: decimal.NewFromInt(1),
 			HourlyQuantity: decimalPtr(cacheNodes),
@@ -61,9 +72,8 @@ func newElasticacheResource(d *schema.ResourceData, u *schema.UsageData, nodeTyp
 					{Key: "cacheEngine", Value: strPtr(strings.Title(cacheEngine))},
 				},
 			},
-			PriceFilter: &schema.PriceFilter{
-				PurchaseOption: strPtr("on_demand"),
-			},
+			PriceFilter:    priceFilter,
+			PurchaseOption: purchaseOption,
 		},
 	}
 
@@ -92,6 +102,6 @@ func newElasticacheResource(d *schema.ResourceData, u *schema.UsageData, nodeTyp
 
 	return &schema.Resource{
 		Name:           d.Address,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
 	}
 }
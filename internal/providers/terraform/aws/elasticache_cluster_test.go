@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestElastiCacheCluster(t *testing.T) {
@@ -14,3 +18,28 @@ func TestElastiCacheCluster(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "elasticache_cluster_test")
 }
+
+func TestElastiCacheClusterReserved(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_elasticache_cluster", "aws", "aws_elasticache_cluster.cache", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"engine": "redis",
+		"node_type": "cache.m4.large",
+		"num_cache_nodes": 2
+	}`))
+
+	u := schema.NewUsageData("aws_elasticache_cluster.cache", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term":           "3_year",
+		"reserved_instance_payment_option": "partial_upfront",
+	}))
+
+	r := aws.NewElastiCacheCluster(d, u)
+	assert.Len(t, r.CostComponents, 2)
+	assert.Equal(t, "Elasticache (reserved, cache.m4.large)", r.CostComponents[0].Name)
+	assert.Equal(t, "3yr", *r.CostComponents[0].PriceFilter.TermLength)
+
+	// The amortized upfront fee has the same count as the hourly cost component
+	upfront := r.CostComponents[1]
+	assert.Equal(t, "2", upfront.UnitMultiplierMonthlyQuantity().Round(6).String())
+}
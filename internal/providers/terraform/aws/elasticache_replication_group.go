@@ -22,11 +22,20 @@ func NewElastiCacheReplicationGroup(d *schema.ResourceData, u *schema.UsageData)
 		cacheEngine = d.Get("engine").String()
 	}
 
-	if d.Get("cluster_mode").Exists() {
+	// AWS provider v4 replaced the cluster_mode block with the num_node_groups and
+	// replicas_per_node_group attributes, and number_cache_clusters with num_cache_clusters
+	switch {
+	case d.Get("cluster_mode.0").Exists():
 		nodeGroups := decimal.NewFromInt(d.Get("cluster_mode.0.num_node_groups").Int())
 		shards := decimal.NewFromInt(d.Get("cluster_mode.0.replicas_per_node_group").Int())
 		cacheNodes = nodeGroups.Mul(shards).Add(nodeGroups)
-	} else {
+	case d.Get("num_node_groups").Int() > 0:
+		nodeGroups := decimal.NewFromInt(d.Get("num_node_groups").Int())
+		shards := decimal.NewFromInt(d.Get("replicas_per_node_group").Int())
+		cacheNodes = nodeGroups.Mul(shards).Add(nodeGroups)
+	case d.Get("num_cache_clusters").Exists():
+		cacheNodes = decimal.NewFromInt(d.Get("num_cache_clusters").Int())
+	default:
 		cacheNodes = decimal.NewFromInt(d.Get("number_cache_clusters").Int())
 	}
 
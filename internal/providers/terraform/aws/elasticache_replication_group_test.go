@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestElastiCacheReplicationGroup(t *testing.T) {
@@ -14,3 +18,32 @@ func TestElastiCacheReplicationGroup(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "elasticache_replication_group_test")
 }
+
+func TestElastiCacheReplicationGroupProviderV4(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		values   string
+		expected string
+	}{
+		{
+			name:     "cluster mode",
+			values:   `{"region": "us-east-1", "node_type": "cache.m4.large", "cluster_mode": [], "num_node_groups": 4, "replicas_per_node_group": 3}`,
+			expected: "16",
+		},
+		{
+			name:     "non-cluster mode",
+			values:   `{"region": "us-east-1", "node_type": "cache.m4.large", "cluster_mode": [], "num_cache_clusters": 3}`,
+			expected: "3",
+		},
+	}
+
+	for _, tt := range tests {
+		d := schema.NewResourceData("aws_elasticache_replication_group", "aws", "aws_elasticache_replication_group.group", nil, gjson.Parse(tt.values))
+
+		r := aws.NewElastiCacheReplicationGroup(d, nil)
+		assert.Equal(t, "Elasticache (on-demand, cache.m4.large)", r.CostComponents[0].Name, tt.name)
+		assert.Equal(t, tt.expected, r.CostComponents[0].HourlyQuantity.String(), tt.name)
+	}
+}
@@ -17,9 +17,13 @@ func GetElasticsearchDomainRegistryItem() *schema.RegistryItem {
 }
 
 func NewElasticsearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
-	region := d.Get("region").String()
+	return newSearchDomain(d, u, "m4.large.elasticsearch")
+}
 
-	defaultInstanceType := "m4.large.elasticsearch"
+// newSearchDomain returns the resource of an Elasticsearch or OpenSearch domain, which are
+// priced the same apart from the suffix of their instance types.
+func newSearchDomain(d *schema.ResourceData, u *schema.UsageData, defaultInstanceType string) *schema.Resource {
+	region := d.Get("region").String()
 
 	instanceType := defaultInstanceType
 	instanceCount := int64(1)
@@ -82,6 +86,7 @@ func NewElasticsearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema
 
 		ebsTypeMap := map[string]string{
 			"gp2":      "GP2",
+			"gp3":      "GP3",
 			"io1":      "PIOPS-Storage",
 			"standard": "Magnetic",
 		}
@@ -201,6 +206,33 @@ func NewElasticsearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema
 		})
 	}
 
+	if ultrawarmEnabled {
+		var storageGB *decimal.Decimal
+		if u != nil && u.Get("ultrawarm_storage_gb").Exists() {
+			storageGB = decimalPtr(decimal.NewFromFloat(u.Get("ultrawarm_storage_gb").Float()))
+		}
+
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:            "UltraWarm storage",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: storageGB,
+			ProductFilter: &schema.ProductFilter{
+				VendorName:    strPtr("aws"),
+				Region:        strPtr(region),
+				Service:       strPtr("AmazonES"),
+				ProductFamily: strPtr("Elastic Search Volume"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "usagetype", ValueRegex: strPtr("/ES:Managed-Storage/")},
+					{Key: "storageMedia", Value: strPtr("Managed-Storage")},
+				},
+			},
+			PriceFilter: &schema.PriceFilter{
+				PurchaseOption: strPtr("on_demand"),
+			},
+		})
+	}
+
 	return &schema.Resource{
 		Name:           d.Address,
 		CostComponents: costComponents,
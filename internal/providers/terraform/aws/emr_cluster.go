@@ -0,0 +1,130 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetEMRClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_emr_cluster",
+		Notes: []string{
+			"Instance fleets are priced using the first of their instance type configs.",
+			"Spot instances are priced using the current spot price rather than the bid price.",
+		},
+		RFunc: NewEMRCluster,
+	}
+}
+
+func NewEMRCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	subResources := make([]*schema.Resource, 0)
+
+	for _, role := range []string{"master", "core"} {
+		if group := d.Get(fmt.Sprintf("%s_instance_group.0", role)); group.Exists() {
+			subResources = append(subResources, &schema.Resource{
+				Name:           fmt.Sprintf("%s_instance_group", role),
+				CostComponents: emrInstanceGroupCostComponents(d, u, group),
+			})
+		}
+
+		if fleet := d.Get(fmt.Sprintf("%s_instance_fleet.0", role)); fleet.Exists() {
+			subResources = append(subResources, &schema.Resource{
+				Name:           fmt.Sprintf("%s_instance_fleet", role),
+				CostComponents: emrInstanceFleetCostComponents(d, u, fleet),
+			})
+		}
+	}
+
+	return &schema.Resource{
+		Name:         d.Address,
+		SubResources: subResources,
+	}
+}
+
+func emrInstanceGroupCostComponents(d *schema.ResourceData, u *schema.UsageData, group gjson.Result) []*schema.CostComponent {
+	count := int64(1)
+	if group.Get("instance_count").Exists() {
+		count = group.Get("instance_count").Int()
+	}
+
+	// Instance groups with a bid price run on spot instances
+	if group.Get("bid_price").String() != "" {
+		return emrNodeCostComponents(d, u, group.Get("instance_type").String(), 0, count, group.Get("ebs_config").Array())
+	}
+
+	return emrNodeCostComponents(d, u, group.Get("instance_type").String(), count, 0, group.Get("ebs_config").Array())
+}
+
+// emrInstanceFleetCostComponents returns the cost components of the instances needed to
+// meet the target capacities of the fleet, using its first instance type config.
+func emrInstanceFleetCostComponents(d *schema.ResourceData, u *schema.UsageData, fleet gjson.Result) []*schema.CostComponent {
+	config := fleet.Get("instance_type_configs.0")
+	if !config.Exists() {
+		return []*schema.CostComponent{}
+	}
+
+	weight := decimal.NewFromInt(1)
+	if config.Get("weighted_capacity").Int() > 0 {
+		weight = decimal.NewFromInt(config.Get("weighted_capacity").Int())
+	}
+
+	onDemandCount := decimal.NewFromInt(fleet.Get("target_on_demand_capacity").Int()).Div(weight).Ceil().IntPart()
+	spotCount := decimal.NewFromInt(fleet.Get("target_spot_capacity").Int()).Div(weight).Ceil().IntPart()
+
+	return emrNodeCostComponents(d, u, config.Get("instance_type").String(), onDemandCount, spotCount, config.Get("ebs_config").Array())
+}
+
+// emrNodeCostComponents returns the EC2 instance hours, EMR surcharge and EBS volumes of a
+// group of EMR nodes of the same instance type.
+func emrNodeCostComponents(d *schema.ResourceData, u *schema.UsageData, instanceType string, onDemandCount, spotCount int64, ebsConfigs []gjson.Result) []*schema.CostComponent {
+	region := d.Get("region").String()
+	costComponents := make([]*schema.CostComponent, 0)
+
+	if onDemandCount > 0 {
+		costComponents = append(costComponents, computeCostComponent(d, u, "on_demand", instanceType, "Shared", onDemandCount))
+	}
+	if spotCount > 0 {
+		costComponents = append(costComponents, computeCostComponent(d, u, "spot", instanceType, "Shared", spotCount))
+	}
+
+	count := onDemandCount + spotCount
+	if count == 0 {
+		return costComponents
+	}
+
+	costComponents = append(costComponents, &schema.CostComponent{
+		Name:           fmt.Sprintf("EMR surcharge (%s)", instanceType),
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(count)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(region),
+			Service:       strPtr("ElasticMapReduce"),
+			ProductFamily: strPtr("Elastic Map Reduce Instance"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "instanceType", Value: strPtr(instanceType)},
+				{Key: "softwareType", Value: strPtr("EMR")},
+			},
+		},
+	})
+
+	// Each node has the volumes of each of the EBS configs
+	for _, ebs := range ebsConfigs {
+		volumes := int64(1)
+		if ebs.Get("volumes_per_instance").Int() > 0 {
+			volumes = ebs.Get("volumes_per_instance").Int()
+		}
+		total := decimal.NewFromInt(volumes * count)
+
+		gbVal := decimal.NewFromFloat(ebs.Get("size").Float()).Mul(total)
+		iopsVal := decimal.NewFromFloat(ebs.Get("iops").Float()).Mul(total)
+
+		costComponents = append(costComponents, ebsVolumeCostComponents(region, ebs.Get("type").String(), nil, gbVal, iopsVal, nil)...)
+	}
+
+	return costComponents
+}
@@ -0,0 +1,61 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestEMRCluster(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "emr_cluster_test")
+}
+
+func TestEMRClusterSpot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_emr_cluster", "aws", "aws_emr_cluster.cluster", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"master_instance_group": [{"instance_type": "m5.xlarge", "instance_count": 1}],
+		"core_instance_group": [{"instance_type": "c5.2xlarge", "instance_count": 2, "bid_price": "0.30"}],
+		"core_instance_fleet": []
+	}`))
+
+	r := aws.NewEMRCluster(d, nil)
+	assert.Len(t, r.SubResources, 2)
+
+	core := r.SubResources[1]
+	assert.Equal(t, "core_instance_group", core.Name)
+	assert.Equal(t, "Instance usage (Linux/UNIX, spot, c5.2xlarge)", core.CostComponents[0].Name)
+	assert.Equal(t, "2", core.CostComponents[0].HourlyQuantity.String())
+
+	// The surcharge is charged for spot instances too
+	assert.Equal(t, "EMR surcharge (c5.2xlarge)", core.CostComponents[1].Name)
+	assert.Equal(t, "2", core.CostComponents[1].HourlyQuantity.String())
+}
+
+func TestEMRInstanceFleetSpot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_emr_instance_fleet", "aws", "aws_emr_instance_fleet.task", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"target_on_demand_capacity": 2,
+		"target_spot_capacity": 5,
+		"instance_type_configs": [{"instance_type": "m5.xlarge", "weighted_capacity": 2}]
+	}`))
+
+	r := aws.NewEMRInstanceFleet(d, nil)
+	assert.Len(t, r.CostComponents, 3)
+	assert.Equal(t, "1", r.CostComponents[0].HourlyQuantity.String())
+	assert.Equal(t, "Instance usage (Linux/UNIX, spot, m5.xlarge)", r.CostComponents[1].Name)
+	assert.Equal(t, "3", r.CostComponents[1].HourlyQuantity.String())
+	assert.Equal(t, "4", r.CostComponents[2].HourlyQuantity.String())
+}
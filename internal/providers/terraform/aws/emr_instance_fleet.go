@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEMRInstanceFleetRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_emr_instance_fleet",
+		Notes: []string{
+			"Instance fleets are priced using the first of their instance type configs.",
+		},
+		RFunc: NewEMRInstanceFleet,
+	}
+}
+
+func NewEMRInstanceFleet(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: emrInstanceFleetCostComponents(d, u, d.RawValues),
+	}
+}
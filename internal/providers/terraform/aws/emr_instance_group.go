@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetEMRInstanceGroupRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_emr_instance_group",
+		Notes: []string{
+			"Spot instances are priced using the current spot price rather than the bid price.",
+		},
+		RFunc: NewEMRInstanceGroup,
+	}
+}
+
+func NewEMRInstanceGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: emrInstanceGroupCostComponents(d, u, d.RawValues),
+	}
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetFSXLustreFSRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_fsx_lustre_file_system",
+		Notes: []string{"The SSD cache of HDD file systems is not supported yet."},
+		RFunc: NewFSXLustreFS,
+	}
+}
+
+func NewFSXLustreFS(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	deploymentType := strings.ToUpper(d.Get("deployment_type").String())
+	if deploymentType == "" {
+		deploymentType = "SCRATCH_1"
+	}
+	isPersistent := strings.HasPrefix(deploymentType, "PERSISTENT")
+
+	storageType := strings.ToUpper(d.Get("storage_type").String())
+	if storageType == "" {
+		storageType = "SSD"
+	}
+
+	storageSize := decimalPtr(decimal.NewFromInt(d.Get("storage_capacity").Int()))
+
+	// Scratch file systems are priced the same regardless of their throughput
+	name := fmt.Sprintf("%s storage (scratch)", storageType)
+	throughput := "N/A"
+	if isPersistent {
+		throughput = d.Get("per_unit_storage_throughput").String()
+		name = fmt.Sprintf("%s storage (persistent, %s MBps/TiB)", storageType, throughput)
+	}
+
+	costComponents := []*schema.CostComponent{
+		{
+			Name:            name,
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: storageSize,
+			ProductFilter: &schema.ProductFilter{
+				VendorName:    strPtr("aws"),
+				Region:        strPtr(region),
+				Service:       strPtr("AmazonFSx"),
+				ProductFamily: strPtr("Storage"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "fileSystemType", Value: strPtr("Lustre")},
+					{Key: "storageType", Value: strPtr(storageType)},
+					{Key: "throughputCapacity", Value: strPtr(throughput)},
+				},
+			},
+		},
+	}
+
+	// Only persistent file systems can be backed up
+	if isPersistent {
+		var backupStorage *decimal.Decimal
+		if u != nil && u.Get("backup_storage_gb").Exists() {
+			backupStorage = decimalPtr(decimal.NewFromInt(u.Get("backup_storage_gb").Int()))
+		}
+
+		costComponents = append(costComponents, backupStorageCapacity(region, "Lustre", "", backupStorage))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetFSXOntapFSRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_fsx_ontap_file_system",
+		RFunc: NewFSXOntapFS,
+	}
+}
+
+func NewFSXOntapFS(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	deploymentOption := fsxDeploymentOption(d.Get("deployment_type").String())
+	storageCapacityGb := d.Get("storage_capacity").Int()
+	throughput := decimalPtr(decimal.NewFromInt(d.Get("throughput_capacity").Int()))
+	storageSize := decimalPtr(decimal.NewFromInt(storageCapacityGb))
+
+	var capacityPoolStorage, backupStorage *decimal.Decimal
+	if u != nil && u.Get("capacity_pool_storage_gb").Exists() {
+		capacityPoolStorage = decimalPtr(decimal.NewFromFloat(u.Get("capacity_pool_storage_gb").Float()))
+	}
+	if u != nil && u.Get("backup_storage_gb").Exists() {
+		backupStorage = decimalPtr(decimal.NewFromInt(u.Get("backup_storage_gb").Int()))
+	}
+
+	costComponents := []*schema.CostComponent{
+		storageCapacity(region, "ONTAP", deploymentOption, false, storageSize),
+	}
+
+	// 3 IOPS per GB of SSD storage are included, only the IOPS above that are charged
+	if strings.ToUpper(d.Get("disk_iops_configuration.0.mode").String()) == "USER_PROVISIONED" {
+		provisionedIOPS := d.Get("disk_iops_configuration.0.iops").Int() - 3*storageCapacityGb
+		if provisionedIOPS > 0 {
+			costComponents = append(costComponents, &schema.CostComponent{
+				Name:            "Provisioned SSD IOPS",
+				Unit:            "IOPS",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: decimalPtr(decimal.NewFromInt(provisionedIOPS)),
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("aws"),
+					Region:        strPtr(region),
+					Service:       strPtr("AmazonFSx"),
+					ProductFamily: strPtr("Provisioned IOPS"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "fileSystemType", Value: strPtr("ONTAP")},
+						{Key: "deploymentOption", Value: strPtr(deploymentOption)},
+					},
+				},
+			})
+		}
+	}
+
+	costComponents = append(costComponents,
+		throughputCapacity(region, "ONTAP", deploymentOption, throughput),
+		&schema.CostComponent{
+			Name:            "Capacity pool storage",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: capacityPoolStorage,
+			ProductFilter: &schema.ProductFilter{
+				VendorName:    strPtr("aws"),
+				Region:        strPtr(region),
+				Service:       strPtr("AmazonFSx"),
+				ProductFamily: strPtr("Storage"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "fileSystemType", Value: strPtr("ONTAP")},
+					{Key: "deploymentOption", Value: strPtr(deploymentOption)},
+					{Key: "storageType", Value: strPtr("Capacity Pool")},
+				},
+			},
+		},
+		backupStorageCapacity(region, "ONTAP", deploymentOption, backupStorage),
+	)
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
@@ -0,0 +1,55 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestFSXOntapFS(t *testing.T) {
+	t.Parallel()
+
+	// aws_fsx_ontap_file_system was added in AWS provider v3.63, after the version the golden file tests use
+	d := schema.NewResourceData("aws_fsx_ontap_file_system", "aws", "aws_fsx_ontap_file_system.fs", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"storage_capacity": 1024,
+		"deployment_type": "MULTI_AZ_1",
+		"throughput_capacity": 512,
+		"disk_iops_configuration": [{"mode": "USER_PROVISIONED", "iops": 4000}]
+	}`))
+
+	u := schema.NewUsageData("aws_fsx_ontap_file_system.fs", schema.ParseAttributes(map[string]interface{}{
+		"capacity_pool_storage_gb": 5000,
+		"backup_storage_gb":        2000,
+	}))
+
+	r := aws.NewFSXOntapFS(d, u)
+	assert.Len(t, r.CostComponents, 5)
+	assert.Equal(t, "SSD storage", r.CostComponents[0].Name)
+	assert.Equal(t, "1024", r.CostComponents[0].MonthlyQuantity.String())
+	assert.Equal(t, "Provisioned SSD IOPS", r.CostComponents[1].Name)
+	assert.Equal(t, "928", r.CostComponents[1].MonthlyQuantity.String())
+	assert.Equal(t, "512", r.CostComponents[2].MonthlyQuantity.String())
+	assert.Equal(t, "5000", r.CostComponents[3].MonthlyQuantity.String())
+	assert.Equal(t, "2000", r.CostComponents[4].MonthlyQuantity.String())
+}
+
+func TestFSXOntapFSAutomaticIOPS(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_fsx_ontap_file_system", "aws", "aws_fsx_ontap_file_system.fs", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"storage_capacity": 1024,
+		"deployment_type": "SINGLE_AZ_1",
+		"throughput_capacity": 128,
+		"disk_iops_configuration": [{"mode": "AUTOMATIC"}]
+	}`))
+
+	r := aws.NewFSXOntapFS(d, nil)
+	assert.Len(t, r.CostComponents, 4)
+	assert.Equal(t, "Throughput capacity", r.CostComponents[1].Name)
+	assert.Nil(t, r.CostComponents[2].MonthlyQuantity)
+}
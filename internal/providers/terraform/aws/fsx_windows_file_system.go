@@ -18,7 +18,7 @@ func GetFSXWindowsFSRegistryItem() *schema.RegistryItem {
 
 func NewFSXWindowsFS(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	region := d.Get("region").String()
-	isMultiAZ := strings.Contains(d.Get("deployment_type").String(), "MULTI_AZ")
+	deploymentOption := fsxDeploymentOption(d.Get("deployment_type").String())
 	isHDD := strings.ToLower(d.Get("storage_type").String()) == "hdd"
 	throughput := decimalPtr(decimal.NewFromInt(d.Get("throughput_capacity").Int()))
 	storageSize := decimalPtr(decimal.NewFromInt(d.Get("storage_capacity").Int()))
@@ -31,19 +31,25 @@ func NewFSXWindowsFS(d *schema.ResourceData, u *schema.UsageData) *schema.Resour
 	return &schema.Resource{
 		Name: d.Address,
 		CostComponents: []*schema.CostComponent{
-			throughputCapacity(region, isMultiAZ, throughput),
-			storageCapacity(region, isMultiAZ, isHDD, storageSize),
-			backupStorageCapacity(region, isMultiAZ, backupStorage),
+			throughputCapacity(region, "Windows", deploymentOption, throughput),
+			storageCapacity(region, "Windows", deploymentOption, isHDD, storageSize),
+			backupStorageCapacity(region, "Windows", deploymentOption, backupStorage),
 		},
 	}
 }
 
-func storageCapacity(region string, isMultiAZ, isHDD bool, storageSize *decimal.Decimal) *schema.CostComponent {
-	deploymentOption := "Single-AZ"
-	if isMultiAZ {
-		deploymentOption = "Multi-AZ"
+// fsxDeploymentOption returns the deployment option of the prices of Windows File Server
+// and ONTAP file systems, e.g. Multi-AZ for MULTI_AZ_1.
+func fsxDeploymentOption(deploymentType string) string {
+	if strings.Contains(strings.ToUpper(deploymentType), "MULTI_AZ") {
+		return "Multi-AZ"
 	}
-	storageType := "SDD"
+
+	return "Single-AZ"
+}
+
+func storageCapacity(region, fileSystemType, deploymentOption string, isHDD bool, storageSize *decimal.Decimal) *schema.CostComponent {
+	storageType := "SSD"
 	if isHDD {
 		storageType = "HDD"
 	}
@@ -58,6 +64,7 @@ func storageCapacity(region string, isMultiAZ, isHDD bool, storageSize *decimal.
 			Service:       strPtr("AmazonFSx"),
 			ProductFamily: strPtr("Storage"),
 			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "fileSystemType", Value: strPtr(fileSystemType)},
 				{Key: "deploymentOption", Value: strPtr(deploymentOption)},
 				{Key: "storageType", Value: strPtr(storageType)},
 			},
@@ -65,11 +72,7 @@ func storageCapacity(region string, isMultiAZ, isHDD bool, storageSize *decimal.
 	}
 }
 
-func throughputCapacity(region string, isMultiAZ bool, throughput *decimal.Decimal) *schema.CostComponent {
-	deploymentOption := "Single-AZ"
-	if isMultiAZ {
-		deploymentOption = "Multi-AZ"
-	}
+func throughputCapacity(region, fileSystemType, deploymentOption string, throughput *decimal.Decimal) *schema.CostComponent {
 	return &schema.CostComponent{
 		Name:            "Throughput capacity",
 		Unit:            "MBps",
@@ -81,31 +84,35 @@ func throughputCapacity(region string, isMultiAZ bool, throughput *decimal.Decim
 			Service:       strPtr("AmazonFSx"),
 			ProductFamily: strPtr("Provisioned Throughput"),
 			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "fileSystemType", Value: strPtr(fileSystemType)},
 				{Key: "deploymentOption", Value: strPtr(deploymentOption)},
 			},
 		},
 	}
 }
 
-func backupStorageCapacity(region string, isMultiAZ bool, backupStorage *decimal.Decimal) *schema.CostComponent {
-	deploymentOption := "Single-AZ"
-	if isMultiAZ {
-		deploymentOption = "Multi-AZ"
+func backupStorageCapacity(region, fileSystemType, deploymentOption string, backupStorage *decimal.Decimal) *schema.CostComponent {
+	attributeFilters := []*schema.AttributeFilter{
+		{Key: "fileSystemType", Value: strPtr(fileSystemType)},
+		{Key: "usagetype", ValueRegex: strPtr("/BackupUsage/")},
 	}
+
+	// Lustre backups are priced the same for all the deployment types
+	if deploymentOption != "" {
+		attributeFilters = append(attributeFilters, &schema.AttributeFilter{Key: "deploymentOption", Value: strPtr(deploymentOption)})
+	}
+
 	return &schema.CostComponent{
 		Name:            "Backup storage",
 		Unit:            "GB",
 		UnitMultiplier:  decimal.NewFromInt(1),
 		MonthlyQuantity: backupStorage,
 		ProductFilter: &schema.ProductFilter{
-			VendorName:    strPtr("aws"),
-			Region:        strPtr(region),
-			Service:       strPtr("AmazonFSx"),
-			ProductFamily: strPtr("Storage"),
-			AttributeFilters: []*schema.AttributeFilter{
-				{Key: "deploymentOption", Value: strPtr(deploymentOption)},
-				{Key: "usagetype", ValueRegex: strPtr("/BackupUsage/")},
-			},
+			VendorName:       strPtr("aws"),
+			Region:           strPtr(region),
+			Service:          strPtr("AmazonFSx"),
+			ProductFamily:    strPtr("Storage"),
+			AttributeFilters: attributeFilters,
 		},
 	}
 }
@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// globalAcceleratorEdgeLocations maps the usage values of the edge location region to the
+// locations of the DT-Premium prices.
+var globalAcceleratorEdgeLocations = map[string]string{
+	"north_america": "North America",
+	"europe":        "Europe",
+	"south_korea":   "South Korea",
+	"india":         "India",
+	"asia_pacific":  "Asia Pacific",
+	"australia":     "Australia",
+	"middle_east":   "Middle East",
+	"south_america": "South America",
+	"africa":        "Africa",
+}
+
+func GetGlobalAcceleratorRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_globalaccelerator_accelerator",
+		Notes: []string{
+			"DT-Premium is charged for the data transferred in the dominant direction over the month, rather than each hour.",
+		},
+		RFunc: NewGlobalAccelerator,
+	}
+}
+
+func NewGlobalAccelerator(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	costComponents := []*schema.CostComponent{
+		{
+			Name:           "Fixed accelerator fee",
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter: &schema.ProductFilter{
+				VendorName: strPtr("aws"),
+				Service:    strPtr("AWSGlobalAccelerator"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "usagetype", ValueRegex: strPtr("/Accelerator-Hours/")},
+				},
+			},
+		},
+	}
+
+	// The endpoints are usually in the same region as the provider
+	endpointRegion := d.Get("region").String()
+	if u != nil && u.Get("endpoint_region").Exists() {
+		endpointRegion = strings.ToLower(u.Get("endpoint_region").String())
+	}
+
+	fromLocation, ok := regionMapping[endpointRegion]
+	if !ok {
+		log.Warnf("Skipping DT-Premium for %s. Could not find mapping for region %s", d.Address, endpointRegion)
+		return &schema.Resource{
+			Name:           d.Address,
+			CostComponents: costComponents,
+		}
+	}
+
+	edgeLocation := "north_america"
+	if u != nil && u.Get("edge_location_region").Exists() {
+		edgeLocation = strings.ToLower(u.Get("edge_location_region").String())
+	}
+
+	toLocation, ok := globalAcceleratorEdgeLocations[edgeLocation]
+	if !ok {
+		log.Warnf("Invalid edge_location_region for %s, defaulting to north_america. Got: %s", d.Address, edgeLocation)
+		toLocation = globalAcceleratorEdgeLocations["north_america"]
+	}
+
+	// DT-Premium is only charged for the data transferred in the dominant direction
+	var dominantGb *decimal.Decimal
+	name := fmt.Sprintf("DT-Premium (%s to %s)", endpointRegion, toLocation)
+	if u != nil && (u.Get("monthly_inbound_data_transfer_gb").Exists() || u.Get("monthly_outbound_data_transfer_gb").Exists()) {
+		inbound := decimal.NewFromFloat(u.Get("monthly_inbound_data_transfer_gb").Float())
+		outbound := decimal.NewFromFloat(u.Get("monthly_outbound_data_transfer_gb").Float())
+
+		dominantGb = decimalPtr(outbound)
+		if inbound.GreaterThan(outbound) {
+			dominantGb = decimalPtr(inbound)
+			name = fmt.Sprintf("DT-Premium (%s to %s)", toLocation, endpointRegion)
+		}
+	}
+
+	costComponents = append(costComponents, &schema.CostComponent{
+		Name:            name,
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: dominantGb,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Service:       strPtr("AWSGlobalAccelerator"),
+			ProductFamily: strPtr("Data Transfer"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "fromLocation", Value: strPtr(fromLocation)},
+				{Key: "toLocation", Value: strPtr(toLocation)},
+			},
+		},
+	})
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
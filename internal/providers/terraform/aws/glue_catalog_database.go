@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// The first million objects stored and the first million requests each month are free.
+var glueCatalogFreeTier = decimal.NewFromInt(1000000)
+
+func GetGlueCatalogDatabaseRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_glue_catalog_database",
+		RFunc: NewGlueCatalogDatabase,
+	}
+}
+
+func NewGlueCatalogDatabase(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var monthlyObjects, monthlyRequests *decimal.Decimal
+	if u != nil && u.Get("monthly_objects").Type != gjson.Null {
+		monthlyObjects = glueCatalogBillableQuantity(u.Get("monthly_objects").Int())
+	}
+	if u != nil && u.Get("monthly_requests").Type != gjson.Null {
+		monthlyRequests = glueCatalogBillableQuantity(u.Get("monthly_requests").Int())
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Storage",
+				Unit:            "100k objects",
+				UnitMultiplier:  decimal.NewFromInt(100000),
+				MonthlyQuantity: monthlyObjects,
+				ProductFilter:   glueProductFilter(region, "Catalog-Storage"),
+			},
+			{
+				Name:            "Requests",
+				Unit:            "1M requests",
+				UnitMultiplier:  decimal.NewFromInt(1000000),
+				MonthlyQuantity: monthlyRequests,
+				ProductFilter:   glueProductFilter(region, "Catalog-Request"),
+			},
+		},
+	}
+}
+
+func glueCatalogBillableQuantity(quantity int64) *decimal.Decimal {
+	billable := decimal.NewFromInt(quantity).Sub(glueCatalogFreeTier)
+	if billable.IsNegative() {
+		billable = decimal.Zero
+	}
+
+	return decimalPtr(billable)
+}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetGlueCrawlerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_glue_crawler",
+		RFunc: NewGlueCrawler,
+	}
+}
+
+func NewGlueCrawler(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var monthlyDPUHours *decimal.Decimal
+	if u != nil && u.Get("monthly_dpu_hours").Type != gjson.Null {
+		monthlyDPUHours = decimalPtr(decimal.NewFromFloat(u.Get("monthly_dpu_hours").Float()))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Duration",
+				Unit:            "DPU-hours",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: monthlyDPUHours,
+				ProductFilter:   glueProductFilter(region, "Crawler-DPU-Hour"),
+			},
+		},
+	}
+}
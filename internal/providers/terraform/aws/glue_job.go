@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// glueWorkerTypeDPUs is the number of DPUs of each of the workers of a Glue job.
+var glueWorkerTypeDPUs = map[string]decimal.Decimal{
+	"standard": decimal.NewFromInt(1),
+	"g.025x":   decimal.NewFromFloat(0.25),
+	"g.1x":     decimal.NewFromInt(1),
+	"g.2x":     decimal.NewFromInt(2),
+	"g.4x":     decimal.NewFromInt(4),
+	"g.8x":     decimal.NewFromInt(8),
+}
+
+func GetGlueJobRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_glue_job",
+		RFunc: NewGlueJob,
+	}
+}
+
+func NewGlueJob(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	jobType := strings.ToLower(d.Get("command.0.name").String())
+	if jobType == "" {
+		jobType = "glueetl"
+	}
+
+	name := "ETL jobs"
+	usageType := "ETL-DPU-Hour"
+	switch jobType {
+	case "gluestreaming":
+		name = "Streaming ETL jobs"
+	case "pythonshell":
+		name = "Python shell jobs"
+		usageType = "ETL-PythonShell-DPU-Hour"
+	}
+
+	dpus := glueJobDPUs(d, jobType)
+
+	var monthlyDPUHours *decimal.Decimal
+	if u != nil && u.Get("monthly_hours").Type != gjson.Null {
+		monthlyDPUHours = decimalPtr(decimal.NewFromFloat(u.Get("monthly_hours").Float()).Mul(dpus))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            name,
+				Unit:            "DPU-hours",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: monthlyDPUHours,
+				ProductFilter:   glueProductFilter(region, usageType),
+			},
+		},
+	}
+}
+
+// glueJobDPUs returns the DPUs the job runs with, using Glue's defaults of 10 DPUs for
+// Spark jobs and 0.0625 DPU for Python shell jobs when they aren't set.
+func glueJobDPUs(d *schema.ResourceData, jobType string) decimal.Decimal {
+	if d.Get("number_of_workers").Type != gjson.Null {
+		workerDPUs, ok := glueWorkerTypeDPUs[strings.ToLower(d.Get("worker_type").String())]
+		if !ok {
+			workerDPUs = decimal.NewFromInt(1)
+		}
+		return workerDPUs.Mul(decimal.NewFromInt(d.Get("number_of_workers").Int()))
+	}
+
+	if d.Get("max_capacity").Type != gjson.Null {
+		return decimal.NewFromFloat(d.Get("max_capacity").Float())
+	}
+
+	if jobType == "pythonshell" {
+		return decimal.NewFromFloat(0.0625)
+	}
+
+	return decimal.NewFromInt(10)
+}
+
+func glueProductFilter(region, usageType string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Region:     strPtr(region),
+		Service:    strPtr("AWSGlue"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/%s$/i", usageType))},
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetGuardDutyDetectorRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_guardduty_detector",
+		Notes: []string{"S3 protection and EKS protection are not supported yet."},
+		RFunc: NewGuardDutyDetector,
+	}
+}
+
+func NewGuardDutyDetector(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	// Suspended detectors don't analyze any data
+	if d.Get("enable").Type != gjson.Null && !d.Get("enable").Bool() {
+		return &schema.Resource{
+			NoPrice:   true,
+			IsSkipped: true,
+		}
+	}
+
+	region := d.Get("region").String()
+
+	var costComponents []*schema.CostComponent
+
+	if u != nil && u.Get("monthly_cloudtrail_events").Exists() {
+		monthlyEvents := decimal.NewFromInt(u.Get("monthly_cloudtrail_events").Int())
+
+		eventsLimits := []int{500000000, 4500000000}
+		eventsTiers := usage.CalculateTierBuckets(monthlyEvents, eventsLimits)
+
+		costComponents = append(costComponents, guardDutyEventsCostComponent(region, "CloudTrail management events (first 500M)", "0", &eventsTiers[0]))
+
+		if eventsTiers[1].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, guardDutyEventsCostComponent(region, "CloudTrail management events (next 4.5B)", "500000000", &eventsTiers[1]))
+		}
+
+		if eventsTiers[2].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, guardDutyEventsCostComponent(region, "CloudTrail management events (over 5B)", "5000000000", &eventsTiers[2]))
+		}
+	} else {
+		costComponents = append(costComponents, guardDutyEventsCostComponent(region, "CloudTrail management events (first 500M)", "0", nil))
+	}
+
+	// VPC flow logs and DNS logs are analyzed together, so they share the same tiers
+	if u != nil && (u.Get("monthly_vpc_flow_logs_gb").Exists() || u.Get("monthly_dns_logs_gb").Exists()) {
+		monthlyLogsGb := decimal.NewFromFloat(u.Get("monthly_vpc_flow_logs_gb").Float()).Add(decimal.NewFromFloat(u.Get("monthly_dns_logs_gb").Float()))
+
+		logsLimits := []int{500, 2000, 7500}
+		logsTiers := usage.CalculateTierBuckets(monthlyLogsGb, logsLimits)
+
+		costComponents = append(costComponents, guardDutyLogsCostComponent(region, "VPC flow logs and DNS logs analyzed (first 500GB)", "0", &logsTiers[0]))
+
+		if logsTiers[1].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, guardDutyLogsCostComponent(region, "VPC flow logs and DNS logs analyzed (next 2000GB)", "500", &logsTiers[1]))
+		}
+
+		if logsTiers[2].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, guardDutyLogsCostComponent(region, "VPC flow logs and DNS logs analyzed (next 7500GB)", "2500", &logsTiers[2]))
+		}
+
+		if logsTiers[3].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, guardDutyLogsCostComponent(region, "VPC flow logs and DNS logs analyzed (over 10000GB)", "10000", &logsTiers[3]))
+		}
+	} else {
+		costComponents = append(costComponents, guardDutyLogsCostComponent(region, "VPC flow logs and DNS logs analyzed (first 500GB)", "0", nil))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func guardDutyEventsCostComponent(region string, displayName string, usageTier string, quantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            displayName,
+		Unit:            "1M events",
+		UnitMultiplier:  decimal.NewFromInt(1000000),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AmazonGuardDuty"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/PaidEventsAnalyzed/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr(usageTier),
+		},
+	}
+}
+
+func guardDutyLogsCostComponent(region string, displayName string, usageTier string, quantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            displayName,
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AmazonGuardDuty"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/PaidLogsAnalyzed/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr(usageTier),
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestGuardDutyDetectorGoldenFile(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "guardduty_detector_test")
+}
+
+func TestGuardDutyDetectorDisabled(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_guardduty_detector", "aws", "aws_guardduty_detector.detector", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"enable": false
+	}`))
+
+	r := aws.NewGuardDutyDetector(d, nil)
+	assert.True(t, r.IsSkipped)
+	assert.True(t, r.NoPrice)
+}
@@ -18,15 +18,54 @@ func GetInstanceRegistryItem() *schema.RegistryItem {
 		Name: "aws_instance",
 		Notes: []string{
 			"Costs associated with marketplace AMIs are not supported.",
-			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file.",
+			"For non-standard Linux AMIs such as Windows and RHEL, the operating system should be specified in usage file if the instance doesn't use an aws_ami data source.",
 			"EC2 detailed monitoring assumes the standard 7 metrics and the lowest tier of prices for CloudWatch.",
 			"If a root volume is not specified then an 8Gi gp2 volume is assumed.",
+			"Spot instances are priced using the current spot price, set purchase_option in the usage file to override the market type.",
 		},
-		RFunc: NewInstance,
+		RFunc:               NewInstance,
+		ReferenceAttributes: []string{"ami"},
+	}
+}
+
+func GetSpotInstanceRequestRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_spot_instance_request",
+		Notes: []string{
+			"Costs associated with marketplace AMIs are not supported.",
+			"The current spot price is used rather than the spot_price bid.",
+			"If a root volume is not specified then an 8Gi gp2 volume is assumed.",
+		},
+		RFunc:               NewSpotInstanceRequest,
+		ReferenceAttributes: []string{"ami"},
 	}
 }
 
 func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	purchaseOption := "on_demand"
+	if strings.ToLower(d.Get("instance_market_options.0.market_type").String()) == "spot" {
+		purchaseOption = "spot"
+	}
+
+	return newInstance(d, u, purchaseOption)
+}
+
+func NewSpotInstanceRequest(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	return newInstance(d, u, "spot")
+}
+
+func newInstance(d *schema.ResourceData, u *schema.UsageData, purchaseOption string) *schema.Resource {
+	// Allow the market type to be specified in the usage data, e.g. to see the cost of
+	// running the instance as a spot instance
+	if u != nil && u.Get("purchase_option").Exists() {
+		switch o := strings.ToLower(u.Get("purchase_option").String()); o {
+		case "on_demand", "spot":
+			purchaseOption = o
+		default:
+			log.Warnf("Invalid purchase_option for %s, ignoring. Expected: on_demand, spot. Got: %s", d.Address, o)
+		}
+	}
+
 	tenancy := "Shared"
 	if strings.ToLower(d.Get("tenancy").String()) == "host" {
 		log.Warnf("Skipping resource %s. Infracost currently does not support host tenancy for AWS EC2 instances", d.Address)
@@ -42,7 +81,7 @@ func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	subResources = append(subResources, newRootBlockDevice(d.Get("root_block_device.0"), region))
 	subResources = append(subResources, newEbsBlockDevices(d.Get("ebs_block_device"), region)...)
 
-	costComponents := []*schema.CostComponent{computeCostComponent(d, u, "on_demand", instanceType, tenancy, 1)}
+	costComponents := []*schema.CostComponent{computeCostComponent(d, u, purchaseOption, instanceType, tenancy, 1)}
 	if d.Get("ebs_optimized").Bool() {
 		costComponents = append(costComponents, ebsOptimizedCostComponent(d))
 	}
@@ -60,8 +99,27 @@ func NewInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
 	return &schema.Resource{
 		Name:           d.Address,
 		SubResources:   subResources,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
+	}
+}
+
+// amiOperatingSystem returns the operating system of the aws_ami data source that the
+// instance references, or an empty string if it can't be found
+func amiOperatingSystem(d *schema.ResourceData) string {
+	for _, ami := range d.References("ami") {
+		details := strings.ToLower(ami.Get("platform_details").String())
+
+		switch {
+		case strings.Contains(details, "windows") || strings.ToLower(ami.Get("platform").String()) == "windows":
+			return "windows"
+		case strings.Contains(details, "red hat"):
+			return "rhel"
+		case strings.Contains(details, "suse"):
+			return "suse"
+		}
 	}
+
+	return ""
 }
 
 func computeCostComponent(d *schema.ResourceData, u *schema.UsageData, purchaseOption, instanceType, tenancy string, desiredSize int64) *schema.CostComponent {
@@ -75,28 +133,32 @@ func computeCostComponent(d *schema.ResourceData, u *schema.UsageData, purchaseO
 	osLabel := "Linux/UNIX"
 	operatingSystem := "Linux"
 
-	// Allow the operating system to be specified in the usage data until we can support it from the AMI directly.
+	os := amiOperatingSystem(d)
+
+	// Allow the operating system to be specified in the usage data since we can't always get it from the AMI
 	if u != nil && u.Get("operating_system").Exists() {
-		os := strings.ToLower(u.Get("operating_system").String())
-		switch os {
-		case "windows":
-			osLabel = "Windows"
-			operatingSystem = "Windows"
-		case "rhel":
-			osLabel = "RHEL"
-			operatingSystem = "RHEL"
-		case "suse":
-			osLabel = "SUSE"
-			operatingSystem = "SUSE"
-		default:
-			if os != "linux" {
-				log.Warnf("Unrecognized operating system %s, defaulting to Linux/UNIX", os)
-			}
+		os = strings.ToLower(u.Get("operating_system").String())
+	}
+
+	switch os {
+	case "windows":
+		osLabel = "Windows"
+		operatingSystem = "Windows"
+	case "rhel":
+		osLabel = "RHEL"
+		operatingSystem = "RHEL"
+	case "suse":
+		osLabel = "SUSE"
+		operatingSystem = "SUSE"
+	default:
+		if os != "" && os != "linux" {
+			log.Warnf("Unrecognized operating system %s, defaulting to Linux/UNIX", os)
 		}
 	}
 
+	// Reserved Instances only apply to on-demand capacity
 	var reservedType, reservedTerm, reservedPaymentOption string
-	if u != nil && u.Get("reserved_instance_type").Type != gjson.Null &&
+	if purchaseOption == "on_demand" && u != nil && u.Get("reserved_instance_type").Type != gjson.Null &&
 		u.Get("reserved_instance_term").Type != gjson.Null &&
 		u.Get("reserved_instance_payment_option").Type != gjson.Null {
 
@@ -136,6 +198,7 @@ func computeCostComponent(d *schema.ResourceData, u *schema.UsageData, purchaseO
 		PriceFilter: &schema.PriceFilter{
 			PurchaseOption: &purchaseOption,
 		},
+		PurchaseOption: purchaseOption,
 	}
 }
 
@@ -159,16 +222,8 @@ func validateReserveInstanceParams(typeName, term, option string) (bool, string)
 }
 
 func reservedInstanceCostComponent(region, osLabel, purchaseOptionLabel, reservedType, reservedTerm, reservedPaymentOption, tenancy, instanceType, operatingSystem string, count int64) *schema.CostComponent {
-	reservedTermName := map[string]string{
-		"1_year": "1yr",
-		"3_year": "3yr",
-	}[reservedTerm]
-
-	reservedPaymentOptionName := map[string]string{
-		"no_upfront":      "No Upfront",
-		"partial_upfront": "Partial Upfront",
-		"all_upfront":     "All Upfront",
-	}[reservedPaymentOption]
+	priceFilter := reservedPriceFilter(reservedTerm, reservedPaymentOption)
+	priceFilter.TermOfferingClass = &reservedType
 
 	return &schema.CostComponent{
 		Name:           fmt.Sprintf("Instance usage (%s, %s, %s)", osLabel, purchaseOptionLabel, instanceType),
@@ -188,12 +243,8 @@ func reservedInstanceCostComponent(region, osLabel, purchaseOptionLabel, reserve
 				{Key: "capacitystatus", Value: strPtr("Used")},
 			},
 		},
-		PriceFilter: &schema.PriceFilter{
-			StartUsageAmount:   strPtr("0"),
-			TermOfferingClass:  &reservedType,
-			TermLength:         &reservedTermName,
-			TermPurchaseOption: &reservedPaymentOptionName,
-		},
+		PriceFilter:    priceFilter,
+		PurchaseOption: "reserved",
 	}
 }
 
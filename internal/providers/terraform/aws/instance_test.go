@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestInstanceGoldenFile(t *testing.T) {
@@ -14,3 +18,87 @@ func TestInstanceGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "instance_test")
 }
+
+func TestInstanceSpot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_instance", "aws", "aws_instance.spot", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"instance_type": "m5.large",
+		"instance_market_options": [{"market_type": "spot"}]
+	}`))
+
+	r := aws.NewInstance(d, nil)
+	c := r.CostComponents[0]
+	assert.Equal(t, "Instance usage (Linux/UNIX, spot, m5.large)", c.Name)
+	assert.Equal(t, "spot", c.PurchaseOption)
+	assert.Equal(t, "spot", *c.PriceFilter.PurchaseOption)
+
+	// The usage file overrides the market type and reserved options don't apply to spot
+	u := schema.NewUsageData("aws_instance.spot", schema.ParseAttributes(map[string]interface{}{
+		"purchase_option":                  "on_demand",
+		"reserved_instance_type":           "standard",
+		"reserved_instance_term":           "1_year",
+		"reserved_instance_payment_option": "no_upfront",
+	}))
+
+	r = aws.NewInstance(d, u)
+	assert.Equal(t, "reserved", r.CostComponents[0].PurchaseOption)
+
+	r = aws.NewSpotInstanceRequest(schema.NewResourceData("aws_spot_instance_request", "aws", "aws_spot_instance_request.spot", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"instance_type": "m5.large",
+		"spot_price": "0.05"
+	}`)), u)
+	assert.Equal(t, "Instance usage (Linux/UNIX, reserved, m5.large)", r.CostComponents[0].Name)
+	assert.Equal(t, "reserved", r.CostComponents[0].PurchaseOption)
+
+	r = aws.NewSpotInstanceRequest(schema.NewResourceData("aws_spot_instance_request", "aws", "aws_spot_instance_request.spot", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"instance_type": "m5.large"
+	}`)), nil)
+	assert.Equal(t, "spot", r.CostComponents[0].PurchaseOption)
+}
+
+func TestInstanceReservedUpfront(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_instance", "aws", "aws_instance.reserved", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"instance_type": "m5.large"
+	}`))
+
+	u := schema.NewUsageData("aws_instance.reserved", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_type":           "convertible",
+		"reserved_instance_term":           "3_year",
+		"reserved_instance_payment_option": "partial_upfront",
+	}))
+
+	r := aws.NewInstance(d, u)
+	assert.Len(t, r.CostComponents, 2)
+
+	hourly := r.CostComponents[0]
+	assert.Equal(t, "Hrs", *hourly.PriceFilter.Unit)
+	assert.Equal(t, "convertible", *hourly.PriceFilter.TermOfferingClass)
+	assert.Equal(t, "3yr", *hourly.PriceFilter.TermLength)
+	assert.Equal(t, "Partial Upfront", *hourly.PriceFilter.TermPurchaseOption)
+
+	upfront := r.CostComponents[1]
+	assert.Equal(t, "Reserved upfront fee (3yr, amortized)", upfront.Name)
+	assert.Equal(t, "reserved", upfront.PurchaseOption)
+	assert.Equal(t, "Quantity", *upfront.PriceFilter.Unit)
+	assert.Equal(t, "convertible", *upfront.PriceFilter.TermOfferingClass)
+	assert.Equal(t, "Partial Upfront", *upfront.PriceFilter.TermPurchaseOption)
+	assert.Equal(t, hourly.ProductFilter, upfront.ProductFilter)
+	assert.Equal(t, "1", upfront.UnitMultiplierMonthlyQuantity().Round(6).String())
+
+	// No upfront reservations don't have an upfront fee
+	u = schema.NewUsageData("aws_instance.reserved", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_type":           "standard",
+		"reserved_instance_term":           "1_year",
+		"reserved_instance_payment_option": "no_upfront",
+	}))
+
+	r = aws.NewInstance(d, u)
+	assert.Len(t, r.CostComponents, 1)
+}
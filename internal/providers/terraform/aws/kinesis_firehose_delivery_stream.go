@@ -41,7 +41,9 @@ func NewKinesisFirehoseDeliveryStream(d *schema.ResourceData, u *schema.UsageDat
 		costComponents = append(costComponents, kinesisFirehoseCostComponent("first 500TB", region, "0", "512000", unknown))
 	}
 
-	if d.Get("extended_s3_configuration.0.data_format_conversion_configuration.0.enabled").Type != gjson.True {
+	// Format conversion is enabled by default when the stream has a conversion configuration
+	conversion := d.Get("extended_s3_configuration.0.data_format_conversion_configuration.0")
+	if conversion.Exists() && conversion.Get("enabled").Type != gjson.False {
 		costComponents = append(costComponents, kinesisFirehoseConversionCostComponent(region, monthlyDataIngestedGb))
 	}
 
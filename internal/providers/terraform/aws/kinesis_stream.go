@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetKinesisStreamRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_kinesis_stream",
+		RFunc: NewKinesisStream,
+	}
+}
+
+func NewKinesisStream(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	if d.Get("stream_mode_details.0.stream_mode").String() == "ON_DEMAND" {
+		return &schema.Resource{
+			Name:           d.Address,
+			CostComponents: kinesisStreamOnDemandCostComponents(region, u),
+		}
+	}
+
+	shards := decimal.NewFromInt(d.Get("shard_count").Int())
+
+	var monthlyPutUnits *decimal.Decimal
+	if u != nil && u.Get("monthly_put_units").Type != gjson.Null {
+		monthlyPutUnits = decimalPtr(decimal.NewFromInt(u.Get("monthly_put_units").Int()))
+	}
+
+	costComponents := []*schema.CostComponent{
+		kinesisStreamShardHoursCostComponent("Shard hours", region, "Storage-ShardHour", shards),
+		{
+			Name:            "PUT payload units",
+			Unit:            "1M units",
+			UnitMultiplier:  decimal.NewFromInt(1000000),
+			MonthlyQuantity: monthlyPutUnits,
+			ProductFilter:   kinesisStreamProductFilter(region, "PutRequestPayloadUnits"),
+		},
+	}
+
+	// Data is kept for 24 hours by default, retaining it for longer is charged per shard hour
+	if d.Get("retention_period").Int() > 24 {
+		costComponents = append(costComponents, kinesisStreamShardHoursCostComponent("Extended retention", region, "Extended-ShardHour", shards))
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func kinesisStreamOnDemandCostComponents(region string, u *schema.UsageData) []*schema.CostComponent {
+	var monthlyDataIngestedGb, monthlyDataRetrievedGb *decimal.Decimal
+	if u != nil && u.Get("monthly_data_ingested_gb").Type != gjson.Null {
+		monthlyDataIngestedGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_ingested_gb").Float()))
+	}
+	if u != nil && u.Get("monthly_data_retrieved_gb").Type != gjson.Null {
+		monthlyDataRetrievedGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_retrieved_gb").Float()))
+	}
+
+	return []*schema.CostComponent{
+		{
+			Name:           "Stream hours (on-demand)",
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  kinesisStreamProductFilter(region, "OnDemand-StreamHour"),
+		},
+		{
+			Name:            "Data ingested (on-demand)",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: monthlyDataIngestedGb,
+			ProductFilter:   kinesisStreamProductFilter(region, "OnDemand-BilledIncomingBytes"),
+		},
+		{
+			Name:            "Data retrieved (on-demand)",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: monthlyDataRetrievedGb,
+			ProductFilter:   kinesisStreamProductFilter(region, "OnDemand-BilledOutgoingBytes"),
+		},
+	}
+}
+
+func kinesisStreamShardHoursCostComponent(name, region, usageType string, shards decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:           name,
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(shards),
+		ProductFilter:  kinesisStreamProductFilter(region, usageType),
+	}
+}
+
+func kinesisStreamProductFilter(region, usageType string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName:    strPtr("aws"),
+		Region:        strPtr(region),
+		Service:       strPtr("AmazonKinesis"),
+		ProductFamily: strPtr("Kinesis Streams"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/%s$/", usageType))},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestKinesisStream(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "kinesis_stream_test")
+}
+
+func TestKinesisStreamOnDemand(t *testing.T) {
+	t.Parallel()
+
+	// stream_mode_details was added in AWS provider v3.71, after the version the golden file tests use
+	d := schema.NewResourceData("aws_kinesis_stream", "aws", "aws_kinesis_stream.stream", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"retention_period": 24,
+		"stream_mode_details": [{"stream_mode": "ON_DEMAND"}]
+	}`))
+
+	u := schema.NewUsageData("aws_kinesis_stream.stream", schema.ParseAttributes(map[string]interface{}{
+		"monthly_data_ingested_gb":  100,
+		"monthly_data_retrieved_gb": 200,
+	}))
+
+	r := aws.NewKinesisStream(d, u)
+	assert.Len(t, r.CostComponents, 3)
+	assert.Equal(t, "Stream hours (on-demand)", r.CostComponents[0].Name)
+	assert.Equal(t, "1", r.CostComponents[0].HourlyQuantity.String())
+	assert.Equal(t, "100", r.CostComponents[1].MonthlyQuantity.String())
+	assert.Equal(t, "200", r.CostComponents[2].MonthlyQuantity.String())
+}
@@ -0,0 +1,16 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetOpensearchDomainRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_opensearch_domain",
+		RFunc: NewOpensearchDomain,
+	}
+}
+
+func NewOpensearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	return newSearchDomain(d, u, "m4.large.search")
+}
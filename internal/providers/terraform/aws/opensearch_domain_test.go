@@ -0,0 +1,56 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestOpensearchDomain(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_opensearch_domain", "aws", "aws_opensearch_domain.domain", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"cluster_config": [{
+			"instance_type": "r6g.large.search",
+			"instance_count": 3,
+			"dedicated_master_enabled": true,
+			"dedicated_master_type": "m6g.large.search",
+			"warm_enabled": true,
+			"warm_count": 2,
+			"warm_type": "ultrawarm1.medium.search"
+		}],
+		"ebs_options": [{"ebs_enabled": true, "volume_size": 100, "volume_type": "gp3"}]
+	}`))
+
+	u := schema.NewUsageData("aws_opensearch_domain.domain", schema.ParseAttributes(map[string]interface{}{
+		"ultrawarm_storage_gb": 500,
+	}))
+
+	r := aws.NewOpensearchDomain(d, u)
+
+	names := make([]string, 0, len(r.CostComponents))
+	for _, c := range r.CostComponents {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{
+		"Instance (on-demand, r6g.large.search)",
+		"Storage (gp3)",
+		"Dedicated master (on-demand, m6g.large.search)",
+		"UltraWarm instance (on-demand, ultrawarm1.medium.search)",
+		"UltraWarm storage",
+	}, names)
+
+	assert.Equal(t, "3", r.CostComponents[0].HourlyQuantity.String())
+	assert.Equal(t, "GP3", *r.CostComponents[1].ProductFilter.AttributeFilters[1].Value)
+	assert.Equal(t, "3", r.CostComponents[2].HourlyQuantity.String())
+	assert.Equal(t, "500", r.CostComponents[4].MonthlyQuantity.String())
+
+	// The default instance type is the OpenSearch one
+	d = schema.NewResourceData("aws_opensearch_domain", "aws", "aws_opensearch_domain.default", nil, gjson.Parse(`{"region": "us-east-1"}`))
+	r = aws.NewOpensearchDomain(d, nil)
+	assert.Equal(t, "Instance (on-demand, m4.large.search)", r.CostComponents[0].Name)
+}
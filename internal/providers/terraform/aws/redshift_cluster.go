@@ -25,9 +25,20 @@ func NewRedshiftCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Res
 		numberOfNodes = d.Get("number_of_nodes").Int()
 	}
 
+	purchaseOptionLabel := "on-demand"
+	purchaseOption := "on_demand"
+	priceFilter := &schema.PriceFilter{
+		PurchaseOption: strPtr("on_demand"),
+	}
+	if term, paymentOption, ok := reservedTermUsage(d.Address, u); ok {
+		purchaseOptionLabel = "reserved"
+		purchaseOption = "reserved"
+		priceFilter = reservedPriceFilter(term, paymentOption)
+	}
+
 	costComponents := []*schema.CostComponent{
 		{
-			Name:           fmt.Sprintf("Cluster usage (%s, %s)", "on-demand", nodeType),
+			Name:           fmt.Sprintf("Cluster usage (%s, %s)", purchaseOptionLabel, nodeType),
 			Unit:           "hours",
 			UnitMultiplier: decimal.NewFromInt(1),
 			HourlyQuantity: decimalPtr(decimal.NewFromInt(numberOfNodes)),
@@ -40,9 +51,8 @@ func NewRedshiftCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Res
 					{Key: "instanceType", ValueRegex: strPtr(fmt.Sprintf("/%s/i", nodeType))},
 				},
 			},
-			PriceFilter: &schema.PriceFilter{
-				PurchaseOption: strPtr("on_demand"),
-			},
+			PriceFilter:    priceFilter,
+			PurchaseOption: purchaseOption,
 		},
 	}
 
@@ -89,7 +99,7 @@ func NewRedshiftCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Res
 
 	return &schema.Resource{
 		Name:           d.Address,
-		CostComponents: costComponents,
+		CostComponents: reservedUpfrontCostComponents(costComponents),
 	}
 }
 
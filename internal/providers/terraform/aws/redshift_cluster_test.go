@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestRedshiftClusterGoldenFile(t *testing.T) {
@@ -14,3 +18,28 @@ func TestRedshiftClusterGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "redshift_cluster_test")
 }
+
+func TestRedshiftClusterReserved(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_redshift_cluster", "aws", "aws_redshift_cluster.cluster", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"node_type": "ra3.4xlarge",
+		"number_of_nodes": 2
+	}`))
+
+	u := schema.NewUsageData("aws_redshift_cluster.cluster", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term":           "1_year",
+		"reserved_instance_payment_option": "all_upfront",
+	}))
+
+	r := aws.NewRedshiftCluster(d, u)
+	assert.Equal(t, "Cluster usage (reserved, ra3.4xlarge)", r.CostComponents[0].Name)
+	assert.Equal(t, "1yr", *r.CostComponents[0].PriceFilter.TermLength)
+	assert.Equal(t, "All Upfront", *r.CostComponents[0].PriceFilter.TermPurchaseOption)
+
+	// The amortized upfront fee follows the cluster usage, with the same count of nodes
+	upfront := r.CostComponents[1]
+	assert.Equal(t, "Reserved upfront fee (1yr, amortized)", upfront.Name)
+	assert.Equal(t, "2", upfront.UnitMultiplierMonthlyQuantity().Round(6).String())
+}
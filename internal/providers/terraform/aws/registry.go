@@ -6,6 +6,7 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetAPIGatewayRestAPIRegistryItem(),
 	GetAPIGatewayStageRegistryItem(),
 	GetAPIGatewayv2ApiRegistryItem(),
+	GetAppSyncGraphQLAPIRegistryItem(),
 	GetAutoscalingGroupRegistryItem(),
 	GetACMCertificate(),
 	GetACMPCACertificateAuthorityRegistryItem(),
@@ -13,13 +14,17 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetCloudFormationStackRegistryItem(),
 	GetCloudFormationStackSetRegistryItem(),
 	GetCloudfrontDistributionRegistryItem(),
+	GetCloudtrailRegistryItem(),
+	GetCloudtrailEventDataStoreRegistryItem(),
 	GetCloudwatchDashboardRegistryItem(),
 	GetCloudwatchEventBusItem(),
 	GetCloudwatchLogGroupItem(),
 	GetCloudwatchMetricAlarmRegistryItem(),
 	GetCodebuildProjectRegistryItem(),
 	GetConfigRuleItem(),
+	GetConfigConformancePackItem(),
 	GetConfigurationRecorderItem(),
+	GetConfigOrganizationConformancePackItem(),
 	GetConfigOrganizationCustomRuleItem(),
 	GetConfigOrganizationManagedRuleItem(),
 	GetDataTransferRegistryItem(),
@@ -47,12 +52,23 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetElastiCacheReplicationGroupItem(),
 	GetElasticsearchDomainRegistryItem(),
 	GetELBRegistryItem(),
+	GetEMRClusterRegistryItem(),
+	GetEMRInstanceFleetRegistryItem(),
+	GetEMRInstanceGroupRegistryItem(),
+	GetFSXLustreFSRegistryItem(),
+	GetFSXOntapFSRegistryItem(),
 	GetFSXWindowsFSRegistryItem(),
+	GetGlobalAcceleratorRegistryItem(),
+	GetGlueCatalogDatabaseRegistryItem(),
+	GetGlueCrawlerRegistryItem(),
+	GetGlueJobRegistryItem(),
+	GetGuardDutyDetectorRegistryItem(),
 	GetInstanceRegistryItem(),
 	GetKinesisAnalyticsApplicationRegistryItem(),
 	GetKinesisDataAnalyticsRegistryItem(),
 	GetKinesisDataAnalyticsSnapshotRegistryItem(),
 	GetKinesisFirehoseDeliveryStreamRegistryItem(),
+	GetKinesisStreamRegistryItem(),
 	GetLambdaFunctionRegistryItem(),
 	GetLBRegistryItem(),
 	GetLightsailInstanceRegistryItem(),
@@ -60,22 +76,29 @@ var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
 	GetALBRegistryItem(),
 	GetMQBrokerRegistryItem(),
 	GetNATGatewayRegistryItem(),
+	GetOpensearchDomainRegistryItem(),
 	GetRDSClusterRegistryItem(),
 	GetRDSClusterInstanceRegistryItem(),
 	GetRedshiftClusterRegistryItem(),
 	GetRoute53HealthCheck(),
 	GetRoute53ResolverEndpointRegistryItem(),
+	GetRoute53ResolverFirewallDomainListRegistryItem(),
+	GetRoute53ResolverFirewallRuleGroupAssociationRegistryItem(),
 	GetRoute53RecordRegistryItem(),
 	GetRoute53ZoneRegistryItem(),
 	GetS3BucketRegistryItem(),
 	GetS3BucketAnalyticsConfigurationRegistryItem(),
 	GetS3BucketInventoryRegistryItem(),
 	GetSecretsManagerSecret(),
+	GetSecurityHubAccountRegistryItem(),
 	GetSSMActivationRegistryItem(),
 	GetSSMParameterRegistryItem(),
 	GetSNSTopicRegistryItem(),
 	GetSNSTopicSubscriptionRegistryItem(),
 	GetSQSQueueRegistryItem(),
+	GetShieldProtectionRegistryItem(),
+	GetSpotInstanceRequestRegistryItem(),
+	GetTransferServerRegistryItem(),
 	GetNeptuneClusterRegistryItem(),
 	GetNeptuneClusterInstanceRegistryItem(),
 	GetNeptuneClusterSnapshotRegistryItem(),
@@ -131,6 +154,14 @@ var FreeResources []string = []string{
 	"aws_apigatewayv2_stage",
 	"aws_apigatewayv2_vpc_link",
 
+	// AWS AppSync
+	"aws_appsync_api_key",
+	"aws_appsync_datasource",
+	"aws_appsync_domain_name",
+	"aws_appsync_domain_name_api_association",
+	"aws_appsync_function",
+	"aws_appsync_resolver",
+
 	// AWS Backup
 	"aws_backup_global_settings",
 	"aws_backup_plan",
@@ -218,6 +249,38 @@ var FreeResources []string = []string{
 	"aws_elasticache_security_group",
 	"aws_elasticache_subnet_group",
 
+	// AWS EMR
+	"aws_emr_managed_scaling_policy",
+	"aws_emr_security_configuration",
+
+	// AWS Global Accelerator
+	"aws_globalaccelerator_endpoint_group",
+	"aws_globalaccelerator_listener",
+
+	// AWS Glue
+	"aws_glue_catalog_table",
+	"aws_glue_classifier",
+	"aws_glue_connection",
+	"aws_glue_data_catalog_encryption_settings",
+	"aws_glue_partition",
+	"aws_glue_registry",
+	"aws_glue_resource_policy",
+	"aws_glue_schema",
+	"aws_glue_security_configuration",
+	"aws_glue_trigger",
+	"aws_glue_user_defined_function",
+	"aws_glue_workflow",
+
+	// AWS GuardDuty
+	"aws_guardduty_filter",
+	"aws_guardduty_invite_accepter",
+	"aws_guardduty_ipset",
+	"aws_guardduty_member",
+	"aws_guardduty_organization_admin_account",
+	"aws_guardduty_organization_configuration",
+	"aws_guardduty_publishing_destination",
+	"aws_guardduty_threatintelset",
+
 	// AWS IAM aws_iam_* resources
 	"aws_iam_access_key",
 	"aws_iam_account_alias",
@@ -290,10 +353,14 @@ var FreeResources []string = []string{
 	"aws_lightsail_static_ip_attachment",
 	"aws_mq_configuration",
 	"aws_msk_configuration",
+	"aws_opensearch_domain_policy",
 	"aws_rds_cluster_endpoint",
 	"aws_rds_cluster_parameter_group",
 	"aws_resourcegroups_group",
 	"aws_route53_resolver_dnssec_config",
+	"aws_route53_resolver_firewall_config",
+	"aws_route53_resolver_firewall_rule",
+	"aws_route53_resolver_firewall_rule_group",
 	"aws_route53_resolver_query_log_config",
 	"aws_route53_resolver_query_log_config_association",
 	"aws_route53_resolver_rule",
@@ -323,9 +390,24 @@ var FreeResources []string = []string{
 	"aws_secretsmanager_secret_rotation",
 	"aws_secretsmanager_secret_version",
 
+	// AWS Security Hub
+	"aws_securityhub_action_target",
+	"aws_securityhub_insight",
+	"aws_securityhub_invite_accepter",
+	"aws_securityhub_member",
+	"aws_securityhub_organization_admin_account",
+	"aws_securityhub_organization_configuration",
+	"aws_securityhub_product_subscription",
+	"aws_securityhub_standards_control",
+	"aws_securityhub_standards_subscription",
+
 	// AWS Service Discovery Service
 	"aws_service_discovery_service",
 
+	// AWS Shield
+	"aws_shield_protection_group",
+	"aws_shield_protection_health_check_association",
+
 	// AWS SNS
 	"aws_sns_platform_application",
 	"aws_sns_sms_preferences",
@@ -340,6 +422,11 @@ var FreeResources []string = []string{
 	"aws_ssm_patch_group",
 	"aws_ssm_resource_data_sync",
 
+	// AWS Transfer Family
+	"aws_transfer_access",
+	"aws_transfer_ssh_key",
+	"aws_transfer_user",
+
 	// AWS VPC
 	"aws_customer_gateway",
 	"aws_default_network_acl",
@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+var reservedTermNames = map[string]string{
+	"1_year": "1yr",
+	"3_year": "3yr",
+}
+
+var reservedTermMonths = map[string]int64{
+	"1yr": 12,
+	"3yr": 36,
+}
+
+var reservedPaymentOptionNames = map[string]string{
+	"no_upfront":      "No Upfront",
+	"partial_upfront": "Partial Upfront",
+	"all_upfront":     "All Upfront",
+}
+
+// reservedTermUsage returns the reserved_instance_term and reserved_instance_payment_option
+// usage keys for resources like RDS and ElastiCache whose reservations don't have an
+// offering class. ok is false if the keys aren't set or are invalid.
+func reservedTermUsage(address string, u *schema.UsageData) (term, paymentOption string, ok bool) {
+	if u == nil || u.Get("reserved_instance_term").Type == gjson.Null || u.Get("reserved_instance_payment_option").Type == gjson.Null {
+		return "", "", false
+	}
+
+	term = u.Get("reserved_instance_term").String()
+	paymentOption = u.Get("reserved_instance_payment_option").String()
+
+	if _, valid := reservedTermNames[term]; !valid {
+		log.Warnf("Invalid reserved_instance_term for %s, ignoring reserved options. Expected: 1_year, 3_year. Got: %s", address, term)
+		return "", "", false
+	}
+
+	if _, valid := reservedPaymentOptionNames[paymentOption]; !valid {
+		log.Warnf("Invalid reserved_instance_payment_option for %s, ignoring reserved options. Expected: no_upfront, partial_upfront, all_upfront. Got: %s", address, paymentOption)
+		return "", "", false
+	}
+
+	return term, paymentOption, true
+}
+
+// reservedPriceFilter returns the price filter for the hourly rate of a reservation. The
+// upfront fee of the reservation is priced separately by reservedUpfrontCostComponents.
+func reservedPriceFilter(term, paymentOption string) *schema.PriceFilter {
+	return &schema.PriceFilter{
+		Unit:               strPtr("Hrs"),
+		StartUsageAmount:   strPtr("0"),
+		TermLength:         strPtr(reservedTermNames[term]),
+		TermPurchaseOption: strPtr(reservedPaymentOptionNames[paymentOption]),
+	}
+}
+
+// reservedUpfrontCostComponents adds a cost component after each reserved cost component
+// that has a partial or all upfront payment option. The upfront fee is amortized over the
+// term of the reservation so it is shown as a monthly cost, with the same count as the
+// hourly cost component.
+func reservedUpfrontCostComponents(costComponents []*schema.CostComponent) []*schema.CostComponent {
+	result := make([]*schema.CostComponent, 0, len(costComponents))
+
+	for _, c := range costComponents {
+		result = append(result, c)
+
+		if c.PurchaseOption != "reserved" || c.PriceFilter == nil || c.PriceFilter.TermLength == nil ||
+			c.PriceFilter.TermPurchaseOption == nil || *c.PriceFilter.TermPurchaseOption == "No Upfront" {
+			continue
+		}
+
+		months, ok := reservedTermMonths[*c.PriceFilter.TermLength]
+		if !ok {
+			continue
+		}
+
+		count := decimal.NewFromInt(1)
+		if c.HourlyQuantity != nil {
+			count = *c.HourlyQuantity
+		}
+
+		perMonth := decimal.NewFromInt(1).Div(decimal.NewFromInt(months))
+
+		result = append(result, &schema.CostComponent{
+			Name:            fmt.Sprintf("Reserved upfront fee (%s, amortized)", *c.PriceFilter.TermLength),
+			Unit:            "months",
+			UnitMultiplier:  perMonth,
+			MonthlyQuantity: decimalPtr(count.Mul(perMonth)),
+			ProductFilter:   c.ProductFilter,
+			PriceFilter: &schema.PriceFilter{
+				Unit:               strPtr("Quantity"),
+				TermOfferingClass:  c.PriceFilter.TermOfferingClass,
+				TermLength:         c.PriceFilter.TermLength,
+				TermPurchaseOption: c.PriceFilter.TermPurchaseOption,
+			},
+			PurchaseOption: "reserved",
+		})
+	}
+
+	return result
+}
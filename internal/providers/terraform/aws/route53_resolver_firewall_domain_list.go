@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetRoute53ResolverFirewallDomainListRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_route53_resolver_firewall_domain_list",
+		RFunc: NewRoute53ResolverFirewallDomainList,
+	}
+}
+
+func NewRoute53ResolverFirewallDomainList(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	// Domains can also be imported from a file outside of Terraform
+	domains := decimal.NewFromInt(int64(len(d.Get("domains").Array())))
+	if u != nil && u.Get("domains").Type != gjson.Null {
+		domains = decimal.NewFromInt(u.Get("domains").Int())
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "Domains",
+				Unit:            "domains",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: decimalPtr(domains),
+				ProductFilter: &schema.ProductFilter{
+					VendorName: strPtr("aws"),
+					Region:     strPtr(region),
+					Service:    strPtr("AmazonRoute53"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "usagetype", ValueRegex: strPtr("/DNS-Firewall-Domains/")},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetRoute53ResolverFirewallRuleGroupAssociationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_route53_resolver_firewall_rule_group_association",
+		RFunc: NewRoute53ResolverFirewallRuleGroupAssociation,
+	}
+}
+
+func NewRoute53ResolverFirewallRuleGroupAssociation(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var monthlyQueries *decimal.Decimal
+	if u != nil && u.Get("monthly_queries").Type != gjson.Null {
+		monthlyQueries = decimalPtr(decimal.NewFromInt(u.Get("monthly_queries").Int()))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            "DNS queries",
+				Unit:            "1M queries",
+				UnitMultiplier:  decimal.NewFromInt(1000000),
+				MonthlyQuantity: monthlyQueries,
+				ProductFilter: &schema.ProductFilter{
+					VendorName: strPtr("aws"),
+					Region:     strPtr(region),
+					Service:    strPtr("AmazonRoute53"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "usagetype", ValueRegex: strPtr("/DNS-Firewall-Queries/")},
+					},
+				},
+			},
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
 )
@@ -21,33 +23,46 @@ func NewSecretsManagerSecret(d *schema.ResourceData, u *schema.UsageData) *schem
 		monthlyRequests = decimalPtr(decimal.NewFromInt(u.Get("monthly_requests").Int()))
 	}
 
+	costComponents := []*schema.CostComponent{
+		secretsManagerSecretCostComponent("Secret", region),
+	}
+
+	// Each replica is charged as a separate secret in its region
+	for _, replica := range d.Get("replica").Array() {
+		replicaRegion := replica.Get("region").String()
+		costComponents = append(costComponents, secretsManagerSecretCostComponent(fmt.Sprintf("Replica secret (%s)", replicaRegion), replicaRegion))
+	}
+
+	costComponents = append(costComponents, &schema.CostComponent{
+		Name:            "API requests",
+		Unit:            "10k requests",
+		UnitMultiplier:  decimal.NewFromInt(10000),
+		MonthlyQuantity: monthlyRequests,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(region),
+			Service:       strPtr("AWSSecretsManager"),
+			ProductFamily: strPtr("API Request"),
+		},
+	})
+
 	return &schema.Resource{
-		Name: d.Address,
-		CostComponents: []*schema.CostComponent{
-			{
-				Name:            "Secret",
-				Unit:            "months",
-				UnitMultiplier:  decimal.NewFromInt(1),
-				MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-				ProductFilter: &schema.ProductFilter{
-					VendorName:    strPtr("aws"),
-					Region:        strPtr(region),
-					Service:       strPtr("AWSSecretsManager"),
-					ProductFamily: strPtr("Secret"),
-				},
-			},
-			{
-				Name:            "API requests",
-				Unit:            "10k requests",
-				UnitMultiplier:  decimal.NewFromInt(10000),
-				MonthlyQuantity: monthlyRequests,
-				ProductFilter: &schema.ProductFilter{
-					VendorName:    strPtr("aws"),
-					Region:        strPtr(region),
-					Service:       strPtr("AWSSecretsManager"),
-					ProductFamily: strPtr("API Request"),
-				},
-			},
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func secretsManagerSecretCostComponent(name, region string) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            "months",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("aws"),
+			Region:        strPtr(region),
+			Service:       strPtr("AWSSecretsManager"),
+			ProductFamily: strPtr("Secret"),
 		},
 	}
 }
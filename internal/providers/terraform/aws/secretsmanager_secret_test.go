@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestAwsSecretsManagerSecretGoldenFile(t *testing.T) {
@@ -14,3 +18,22 @@ func TestAwsSecretsManagerSecretGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "secretsmanager_secret_test")
 }
+
+func TestAwsSecretsManagerSecretReplicas(t *testing.T) {
+	t.Parallel()
+
+	// replica was added in AWS provider v3.64, after the version the golden file tests use
+	d := schema.NewResourceData("aws_secretsmanager_secret", "aws", "aws_secretsmanager_secret.secret", nil, gjson.Parse(`{
+		"region": "us-east-1",
+		"replica": [{"region": "eu-west-1"}, {"region": "ap-southeast-2"}]
+	}`))
+
+	r := aws.NewSecretsManagerSecret(d, nil)
+	assert.Len(t, r.CostComponents, 4)
+	assert.Equal(t, "Secret", r.CostComponents[0].Name)
+	assert.Equal(t, "Replica secret (eu-west-1)", r.CostComponents[1].Name)
+	assert.Equal(t, "eu-west-1", *r.CostComponents[1].ProductFilter.Region)
+	assert.Equal(t, "Replica secret (ap-southeast-2)", r.CostComponents[2].Name)
+	assert.Equal(t, "ap-southeast-2", *r.CostComponents[2].ProductFilter.Region)
+	assert.Equal(t, "API requests", r.CostComponents[3].Name)
+}
@@ -0,0 +1,90 @@
+package aws
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+	"github.com/shopspring/decimal"
+)
+
+func GetSecurityHubAccountRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_securityhub_account",
+		RFunc: NewSecurityHubAccount,
+	}
+}
+
+func NewSecurityHubAccount(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	var costComponents []*schema.CostComponent
+
+	if u != nil && u.Get("monthly_security_checks").Exists() {
+		monthlyChecks := decimal.NewFromInt(u.Get("monthly_security_checks").Int())
+
+		checksLimits := []int{100000, 400000}
+		checksTiers := usage.CalculateTierBuckets(monthlyChecks, checksLimits)
+
+		costComponents = append(costComponents, securityHubChecksCostComponent(region, "Security checks (first 100K)", "0", &checksTiers[0]))
+
+		if checksTiers[1].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, securityHubChecksCostComponent(region, "Security checks (next 400K)", "100000", &checksTiers[1]))
+		}
+
+		if checksTiers[2].GreaterThan(decimal.NewFromInt(0)) {
+			costComponents = append(costComponents, securityHubChecksCostComponent(region, "Security checks (over 500K)", "500000", &checksTiers[2]))
+		}
+	} else {
+		costComponents = append(costComponents, securityHubChecksCostComponent(region, "Security checks (first 100K)", "0", nil))
+	}
+
+	// The first 10K finding ingestion events are free each month
+	var monthlyPaidEvents *decimal.Decimal
+	if u != nil && u.Get("monthly_finding_ingestion_events").Exists() {
+		monthlyEvents := decimal.NewFromInt(u.Get("monthly_finding_ingestion_events").Int())
+		eventsTiers := usage.CalculateTierBuckets(monthlyEvents, []int{10000})
+		monthlyPaidEvents = &eventsTiers[1]
+	}
+
+	costComponents = append(costComponents, &schema.CostComponent{
+		Name:            "Finding ingestion events (over 10K)",
+		Unit:            "100K events",
+		UnitMultiplier:  decimal.NewFromInt(100000),
+		MonthlyQuantity: monthlyPaidEvents,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AWSSecurityHub"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/PaidFindingsIngestion/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr("10000"),
+		},
+	})
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func securityHubChecksCostComponent(region string, displayName string, usageTier string, quantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            displayName,
+		Unit:            "10K checks",
+		UnitMultiplier:  decimal.NewFromInt(10000),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName: strPtr("aws"),
+			Region:     strPtr(region),
+			Service:    strPtr("AWSSecurityHub"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "usagetype", ValueRegex: strPtr("/PaidComplianceCheck/")},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr(usageTier),
+		},
+	}
+}
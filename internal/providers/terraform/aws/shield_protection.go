@@ -0,0 +1,128 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// shieldProtectedResourceTypes maps the types of the resources that Shield Advanced can
+// protect to the labels of their data transfer out prices.
+var shieldProtectedResourceTypes = map[string]string{
+	"aws_cloudfront_distribution":       "CloudFront",
+	"aws_lb":                            "ELB",
+	"aws_alb":                           "ELB",
+	"aws_elb":                           "ELB",
+	"aws_eip":                           "EIP",
+	"aws_globalaccelerator_accelerator": "GlobalAccelerator",
+}
+
+// shieldARNServices maps the services of the ARNs of the protected resources to the labels
+// of their data transfer out prices, for when the resource isn't in the plan.
+var shieldARNServices = map[string]string{
+	"cloudfront":           "CloudFront",
+	"elasticloadbalancing": "ELB",
+	"ec2":                  "EIP",
+	"globalaccelerator":    "GlobalAccelerator",
+}
+
+func GetShieldProtectionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "aws_shield_protection",
+		Notes: []string{
+			"The subscription fee is charged once for the AWS organization, so it's split across the Shield protections in the project. Set protected_resources in the usage file to split it across more resources.",
+			"Data transfer out is priced using the lowest tier.",
+		},
+		RFunc:               NewShieldProtection,
+		ReferenceAttributes: []string{"resource_arn"},
+	}
+}
+
+func NewShieldProtection(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	protectedResources := decimal.NewFromInt(1)
+	if u != nil && u.Get("protected_resources").Int() > 0 {
+		protectedResources = decimal.NewFromInt(u.Get("protected_resources").Int())
+	}
+
+	costComponents := []*schema.CostComponent{
+		{
+			Name:            "Subscription",
+			Unit:            "months",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(1).Div(protectedResources)),
+			ProductFilter: &schema.ProductFilter{
+				VendorName: strPtr("aws"),
+				Service:    strPtr("AWSShield"),
+				AttributeFilters: []*schema.AttributeFilter{
+					{Key: "usagetype", ValueRegex: strPtr("/Shield-Monthly-Fee/")},
+				},
+			},
+		},
+	}
+
+	// Route 53 hosted zones don't have data transfer out charges
+	label := shieldProtectedResourceLabel(d)
+	if label == "" {
+		return &schema.Resource{
+			Name:           d.Address,
+			CostComponents: costComponents,
+		}
+	}
+
+	var monthlyDataTransferOutGb *decimal.Decimal
+	if u != nil && u.Get("monthly_data_transfer_out_gb").Type != gjson.Null {
+		monthlyDataTransferOutGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_transfer_out_gb").Float()))
+	}
+
+	productFilter := &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Service:    strPtr("AWSShield"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/%s-DataTransfer-Out/i", label))},
+		},
+	}
+
+	// CloudFront and Global Accelerator are global, the others are priced by region
+	if label == "ELB" || label == "EIP" {
+		productFilter.Region = strPtr(region)
+	}
+
+	costComponents = append(costComponents, &schema.CostComponent{
+		Name:            fmt.Sprintf("Data transfer out (%s)", label),
+		Unit:            "GB",
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: monthlyDataTransferOutGb,
+		ProductFilter:   productFilter,
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr("0"),
+		},
+	})
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+// shieldProtectedResourceLabel returns the label of the data transfer out prices of the
+// protected resource, or an empty string if it doesn't have any.
+func shieldProtectedResourceLabel(d *schema.ResourceData) string {
+	for _, ref := range d.References("resource_arn") {
+		if label, ok := shieldProtectedResourceTypes[ref.Type]; ok {
+			return label
+		}
+	}
+
+	// ARNs look like arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/example
+	parts := strings.Split(d.Get("resource_arn").String(), ":")
+	if len(parts) > 2 {
+		return shieldARNServices[parts[2]]
+	}
+
+	return ""
+}
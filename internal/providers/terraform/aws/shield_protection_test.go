@@ -0,0 +1,35 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestShieldProtection(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "shield_protection_test")
+}
+
+func TestShieldProtectionReferencedResource(t *testing.T) {
+	t.Parallel()
+
+	// The ARN of a resource that's created in the same plan isn't known yet
+	d := schema.NewResourceData("aws_shield_protection", "aws", "aws_shield_protection.distribution", nil, gjson.Parse(`{
+		"region": "us-east-1"
+	}`))
+	d.AddReference("resource_arn", schema.NewResourceData("aws_cloudfront_distribution", "aws", "aws_cloudfront_distribution.example", nil, gjson.Parse(`{}`)))
+
+	r := aws.NewShieldProtection(d, nil)
+	assert.Len(t, r.CostComponents, 2)
+	assert.Equal(t, "Data transfer out (CloudFront)", r.CostComponents[1].Name)
+	assert.Nil(t, r.CostComponents[1].ProductFilter.Region)
+}
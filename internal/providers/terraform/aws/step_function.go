@@ -1,11 +1,12 @@
 package aws
 
 import (
+	"strings"
+
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/usage"
 	"github.com/shopspring/decimal"
 	"github.com/tidwall/gjson"
-	"strings"
 )
 
 func GetStepFunctionRegistryItem() *schema.RegistryItem {
@@ -72,7 +73,7 @@ func stepFunctionStandardCostComponent(region string, quantity *decimal.Decimal)
 	return &schema.CostComponent{
 		Name:            "Transitions",
 		Unit:            "1K transitions",
-		UnitMultiplier:  decimal.NewFromInt(2),
+		UnitMultiplier:  decimal.NewFromInt(1000),
 		MonthlyQuantity: quantity,
 		ProductFilter: &schema.ProductFilter{
 			VendorName:    strPtr("aws"),
@@ -0,0 +1,20 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_appsync_graphql_api" "with_usage" {
+  name                = "with-usage"
+  authentication_type = "API_KEY"
+}
+
+resource "aws_appsync_graphql_api" "without_usage" {
+  name                = "without-usage"
+  authentication_type = "API_KEY"
+}
@@ -0,0 +1,50 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_cloudtrail" "management_events" {
+  name           = "management-events"
+  s3_bucket_name = "fake"
+}
+
+resource "aws_cloudtrail" "management_events_withUsage" {
+  name           = "management-events-with-usage"
+  s3_bucket_name = "fake"
+}
+
+resource "aws_cloudtrail" "s3_data_events" {
+  name           = "s3-data-events"
+  s3_bucket_name = "fake"
+
+  event_selector {
+    read_write_type           = "All"
+    include_management_events = false
+
+    data_resource {
+      type   = "AWS::S3::Object"
+      values = ["arn:aws:s3:::"]
+    }
+  }
+}
+
+resource "aws_cloudtrail" "all_events_withUsage" {
+  name           = "all-events-with-usage"
+  s3_bucket_name = "fake"
+
+  event_selector {
+    read_write_type           = "All"
+    include_management_events = true
+
+    data_resource {
+      type   = "AWS::Lambda::Function"
+      values = ["arn:aws:lambda"]
+    }
+  }
+}
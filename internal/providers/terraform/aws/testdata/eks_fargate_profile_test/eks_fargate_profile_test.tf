@@ -28,3 +28,14 @@ resource "aws_eks_fargate_profile" "example" {
     namespace = "example"
   }
 }
+
+resource "aws_eks_fargate_profile" "with_usage" {
+  cluster_name           = aws_eks_cluster.example.name
+  fargate_profile_name   = "with_usage"
+  pod_execution_role_arn = "arn:aws:iam::123456789012:role/Example"
+  subnet_ids             = ["subnet_id"]
+
+  selector {
+    namespace = "api"
+  }
+}
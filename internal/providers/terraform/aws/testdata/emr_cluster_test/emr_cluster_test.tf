@@ -0,0 +1,79 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_emr_cluster" "instance_groups" {
+  name          = "instance-groups"
+  release_label = "emr-6.3.0"
+  service_role  = "arn:aws:iam::123456789012:role/emr"
+
+  master_instance_group {
+    instance_type = "m5.xlarge"
+
+    ebs_config {
+      size                 = 32
+      type                 = "gp2"
+      volumes_per_instance = 2
+    }
+  }
+
+  core_instance_group {
+    instance_type  = "c5.2xlarge"
+    instance_count = 2
+
+    ebs_config {
+      size = 64
+      type = "gp2"
+    }
+  }
+}
+
+resource "aws_emr_cluster" "instance_fleets" {
+  name          = "instance-fleets"
+  release_label = "emr-6.3.0"
+  service_role  = "arn:aws:iam::123456789012:role/emr"
+
+  master_instance_fleet {
+    target_on_demand_capacity = 1
+
+    instance_type_configs {
+      instance_type = "m5.xlarge"
+    }
+  }
+
+  core_instance_fleet {
+    target_on_demand_capacity = 4
+
+    instance_type_configs {
+      instance_type     = "c5.2xlarge"
+      weighted_capacity = 2
+
+      ebs_config {
+        size = 100
+        type = "gp2"
+      }
+    }
+  }
+}
+
+resource "aws_emr_instance_group" "task" {
+  cluster_id     = aws_emr_cluster.instance_groups.id
+  instance_type  = "c5.2xlarge"
+  instance_count = 3
+}
+
+resource "aws_emr_instance_fleet" "task" {
+  cluster_id                = aws_emr_cluster.instance_fleets.id
+  target_on_demand_capacity = 2
+
+  instance_type_configs {
+    instance_type = "m5.xlarge"
+  }
+}
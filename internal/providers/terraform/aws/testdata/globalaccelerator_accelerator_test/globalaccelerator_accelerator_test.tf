@@ -0,0 +1,22 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_globalaccelerator_accelerator" "with_usage" {
+  name = "with-usage"
+}
+
+resource "aws_globalaccelerator_accelerator" "without_usage" {
+  name = "without-usage"
+}
+
+resource "aws_globalaccelerator_accelerator" "mostly_inbound" {
+  name = "mostly-inbound"
+}
@@ -0,0 +1,30 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_glue_crawler" "with_usage" {
+  database_name = "example"
+  name          = "with-usage"
+  role          = "arn:aws:iam::123456789012:role/glue"
+
+  s3_target {
+    path = "s3://example/data"
+  }
+}
+
+resource "aws_glue_crawler" "without_usage" {
+  database_name = "example"
+  name          = "without-usage"
+  role          = "arn:aws:iam::123456789012:role/glue"
+
+  s3_target {
+    path = "s3://example/data"
+  }
+}
@@ -0,0 +1,21 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_kinesis_stream" "with_usage" {
+  name             = "with-usage"
+  shard_count      = 2
+  retention_period = 48
+}
+
+resource "aws_kinesis_stream" "without_usage" {
+  name        = "without-usage"
+  shard_count = 1
+}
@@ -0,0 +1,19 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_route53_resolver_firewall_domain_list" "example" {
+  name    = "example"
+  domains = ["example.com.", "example.net.", "example.org."]
+}
+
+resource "aws_route53_resolver_firewall_domain_list" "imported" {
+  name = "imported"
+}
@@ -0,0 +1,32 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_vpc" "example" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_route53_resolver_firewall_rule_group" "example" {
+  name = "example"
+}
+
+resource "aws_route53_resolver_firewall_rule_group_association" "with_usage" {
+  name                   = "with_usage"
+  firewall_rule_group_id = aws_route53_resolver_firewall_rule_group.example.id
+  priority               = 100
+  vpc_id                 = aws_vpc.example.id
+}
+
+resource "aws_route53_resolver_firewall_rule_group_association" "without_usage" {
+  name                   = "without_usage"
+  firewall_rule_group_id = aws_route53_resolver_firewall_rule_group.example.id
+  priority               = 101
+  vpc_id                 = aws_vpc.example.id
+}
@@ -0,0 +1,25 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_shield_protection" "load_balancer" {
+  name         = "load-balancer"
+  resource_arn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/example/1234567890abcdef"
+}
+
+resource "aws_shield_protection" "hosted_zone" {
+  name         = "hosted-zone"
+  resource_arn = "arn:aws:route53:::hostedzone/Z1234567890ABC"
+}
+
+resource "aws_shield_protection" "without_usage" {
+  name         = "without-usage"
+  resource_arn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/other/1234567890abcdef"
+}
@@ -0,0 +1,24 @@
+provider "aws" {
+  region                      = "us-east-1"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+  skip_get_ec2_platforms      = true
+  skip_region_validation      = true
+  access_key                  = "mock_access_key"
+  secret_key                  = "mock_secret_key"
+}
+
+resource "aws_transfer_server" "sftp" {
+}
+
+resource "aws_transfer_server" "multiple_protocols" {
+  protocols       = ["SFTP", "FTPS"]
+  certificate   = "arn:aws:acm:us-east-1:123456789012:certificate/fake"
+  endpoint_type   = "VPC"
+
+  endpoint_details {
+    vpc_id     = "vpc-12345678"
+    subnet_ids = ["subnet-12345678"]
+  }
+}
@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+func GetTransferServerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "aws_transfer_server",
+		RFunc: NewTransferServer,
+	}
+}
+
+func NewTransferServer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	// Servers use SFTP unless other protocols are set
+	protocols := []string{"SFTP"}
+	if len(d.Get("protocols").Array()) > 0 {
+		protocols = make([]string, 0)
+		for _, p := range d.Get("protocols").Array() {
+			protocols = append(protocols, strings.ToUpper(p.String()))
+		}
+		sort.Strings(protocols)
+	}
+
+	costComponents := make([]*schema.CostComponent, 0)
+
+	// Each protocol that's enabled is charged by the hour
+	for _, protocol := range protocols {
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:           fmt.Sprintf("%s protocol enabled", protocol),
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  transferProductFilter(region, fmt.Sprintf("%s-Hours", protocol)),
+		})
+	}
+
+	var monthlyDataUploadedGb, monthlyDataDownloadedGb *decimal.Decimal
+	if u != nil && u.Get("monthly_data_uploaded_gb").Type != gjson.Null {
+		monthlyDataUploadedGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_uploaded_gb").Float()))
+	}
+	if u != nil && u.Get("monthly_data_downloaded_gb").Type != gjson.Null {
+		monthlyDataDownloadedGb = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_downloaded_gb").Float()))
+	}
+
+	costComponents = append(costComponents,
+		&schema.CostComponent{
+			Name:            "Data uploaded",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: monthlyDataUploadedGb,
+			ProductFilter:   transferProductFilter(region, "Upload-Bytes"),
+		},
+		&schema.CostComponent{
+			Name:            "Data downloaded",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: monthlyDataDownloadedGb,
+			ProductFilter:   transferProductFilter(region, "Download-Bytes"),
+		},
+	)
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func transferProductFilter(region, usageType string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Region:     strPtr(region),
+		Service:    strPtr("AWSTransfer"),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "usagetype", ValueRegex: strPtr(fmt.Sprintf("/%s$/i", usageType))},
+		},
+	}
+}
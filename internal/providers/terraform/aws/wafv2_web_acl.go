@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
 	"github.com/tidwall/gjson"
@@ -20,28 +22,44 @@ func NewWafv2WebACL(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 	region := d.Get("region").String()
 
 	var costComponents []*schema.CostComponent
-	var ruleGroupRules, managedRuleGroupRules, monthlyRequests, rule *decimal.Decimal
-	var sumForRules decimal.Decimal
+	var monthlyRequests *decimal.Decimal
 
 	costComponents = append(costComponents, wafWebACLUsageCostComponent(
 		region,
 		"Web ACL usage",
 		"months",
-		"USE1-WebACLV2",
+		wafv2UsageType("WebACLV2"),
 		1,
 		decimalPtr(decimal.NewFromInt(1)),
 	))
-	if d.Get("rule.0.action").Type != gjson.Null {
-		rules := d.Get("rule.0.action").Array()
-		rule = decimalPtr(decimal.NewFromInt(int64(len(rules))))
-	}
-	if u != nil && u.Get("rule_group_rules").Type != gjson.Null && u.Get("managed_rule_group_rules").Type != gjson.Null {
-		ruleGroupRules = decimalPtr(decimal.NewFromInt(u.Get("rule_group_rules").Int()))
-		managedRuleGroupRules = decimalPtr(decimal.NewFromInt(u.Get("managed_rule_group_rules").Int()))
-		sumForRules = ruleGroupRules.Add(*managedRuleGroupRules)
-		if rule.IsPositive() {
-			sumForRules = sumForRules.Add(*rule)
+
+	// Rules that reference rule groups are charged as rule groups, the rest as rules
+	var rules, ruleGroups, managedRuleGroups int64
+	for _, rule := range d.Get("rule").Array() {
+		statement := rule.Get("statement.0")
+		isGroup := false
+
+		if statement.Get("rule_group_reference_statement.0").Exists() {
+			ruleGroups++
+			isGroup = true
+		}
+		if statement.Get("managed_rule_group_statement.0").Exists() {
+			managedRuleGroups++
+			isGroup = true
 		}
+
+		if !isGroup {
+			rules++
+		}
+	}
+
+	// The rules in the rule groups are charged too
+	sumForRules := decimal.NewFromInt(rules)
+	if u != nil && u.Get("rule_group_rules").Type != gjson.Null {
+		sumForRules = sumForRules.Add(decimal.NewFromInt(u.Get("rule_group_rules").Int()))
+	}
+	if u != nil && u.Get("managed_rule_group_rules").Type != gjson.Null {
+		sumForRules = sumForRules.Add(decimal.NewFromInt(u.Get("managed_rule_group_rules").Int()))
 	}
 
 	if sumForRules.IsPositive() {
@@ -49,49 +67,31 @@ func NewWafv2WebACL(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 			region,
 			"Rules",
 			"months",
-			"USE1-RuleV2",
+			wafv2UsageType("RuleV2"),
 			1,
 			&sumForRules,
 		))
 	}
 
-	if d.Get("rule.0.statement.0.rule_group_reference_statement").Type != gjson.Null {
-		counter := 0
-		if d.Get("rule").Type != gjson.Null {
-			rules := d.Get("rule").Array()
-			for _, rule := range rules {
-				if rule.Get("statement").Type != gjson.Null {
-					statements := rule.Get("statement").Array()
-					for _, statement := range statements {
-						if statement.Get("rule_group_reference_statement").Type != gjson.Null {
-							counter++
-						}
-					}
-				}
-			}
-		}
-
-		if counter > 0 {
-			costComponents = append(costComponents, wafWebACLUsageCostComponent(
-				region,
-				"Rule groups",
-				"months",
-				"USE1-RuleV2",
-				1,
-				decimalPtr(decimal.NewFromInt(int64(counter))),
-			))
-		}
+	if ruleGroups > 0 {
+		costComponents = append(costComponents, wafWebACLUsageCostComponent(
+			region,
+			"Rule groups",
+			"months",
+			wafv2UsageType("RuleV2"),
+			1,
+			decimalPtr(decimal.NewFromInt(ruleGroups)),
+		))
 	}
-	manageQuantity := d.Get("rule.0.statement.0.managed_rule_group_statement.0.name").Array()
 
-	if len(manageQuantity) > 0 {
+	if managedRuleGroups > 0 {
 		costComponents = append(costComponents, wafWebACLUsageCostComponent(
 			region,
 			"Managed rule groups",
 			"months",
-			"USE1-RuleV2",
+			wafv2UsageType("RuleV2"),
 			1,
-			decimalPtr(decimal.NewFromInt(int64(len(manageQuantity)))),
+			decimalPtr(decimal.NewFromInt(managedRuleGroups)),
 		))
 	}
 
@@ -103,7 +103,7 @@ func NewWafv2WebACL(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 		region,
 		"Requests",
 		"1M requests",
-		"USE1-RequestV2-Tier1",
+		wafv2UsageType("RequestV2-Tier1"),
 		1000000,
 		monthlyRequests,
 	))
@@ -113,3 +113,9 @@ func NewWafv2WebACL(d *schema.ResourceData, u *schema.UsageData) *schema.Resourc
 		CostComponents: costComponents,
 	}
 }
+
+// wafv2UsageType returns the usage type of the WAFv2 prices in any region, since they're
+// prefixed with the region's code, e.g. USE1-WebACLV2.
+func wafv2UsageType(usageType string) string {
+	return fmt.Sprintf("([A-Z0-9]+-)?%s", usageType)
+}
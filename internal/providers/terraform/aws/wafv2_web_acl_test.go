@@ -3,7 +3,11 @@ package aws_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestWafV2WebAclGoldenFile(t *testing.T) {
@@ -14,3 +18,42 @@ func TestWafV2WebAclGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "wafv2_web_acl_test")
 }
+
+func TestWafV2WebAclRules(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("aws_wafv2_web_acl", "aws", "aws_wafv2_web_acl.acl", nil, gjson.Parse(`{
+		"region": "eu-west-1",
+		"rule": [
+			{"statement": [{"ip_set_reference_statement": [{"arn": "arn"}]}]},
+			{"statement": [{"rate_based_statement": [{"limit": 100}]}]},
+			{"statement": [{"managed_rule_group_statement": [{"name": "AWSManagedRulesCommonRuleSet"}]}]},
+			{"statement": [{"managed_rule_group_statement": [{"name": "AWSManagedRulesSQLiRuleSet"}]}]},
+			{"statement": [{"rule_group_reference_statement": [{"arn": "arn"}]}]}
+		]
+	}`))
+
+	// Only one of the rule group usage keys is set
+	u := schema.NewUsageData("aws_wafv2_web_acl.acl", schema.ParseAttributes(map[string]interface{}{
+		"rule_group_rules": 5,
+	}))
+
+	r := aws.NewWafv2WebACL(d, u)
+
+	quantities := make(map[string]string)
+	for _, c := range r.CostComponents {
+		if c.MonthlyQuantity != nil {
+			quantities[c.Name] = c.MonthlyQuantity.String()
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"Web ACL usage":       "1",
+		"Rules":               "7",
+		"Rule groups":         "1",
+		"Managed rule groups": "2",
+	}, quantities)
+
+	// The usage types match the prices of any region, not only us-east-1
+	assert.Equal(t, "/^([A-Z0-9]+-)?WebACLV2$/i", *r.CostComponents[0].ProductFilter.AttributeFilters[0].ValueRegex)
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/infracost/infracost/internal/resources"
 	"github.com/infracost/infracost/internal/schema"
-	"github.com/infracost/infracost/internal/usage"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
@@ -97,13 +97,6 @@ func NewAzureRMCDNEndpoint(d *schema.ResourceData, u *schema.UsageData) *schema.
 }
 
 func cdnOutboundDataCostComponents(region, sku string, u *schema.UsageData) []*schema.CostComponent {
-	costComponents := []*schema.CostComponent{}
-
-	type dataTier struct {
-		name       string
-		startUsage string
-	}
-
 	var name, productName, skuName, meterName string
 	if s := strings.Split(sku, "_"); len(s) == 2 {
 		productName = fmt.Sprintf("Azure CDN from %s", s[1])
@@ -115,14 +108,14 @@ func cdnOutboundDataCostComponents(region, sku string, u *schema.UsageData) []*s
 		}
 	}
 
-	data := []dataTier{
-		{name: fmt.Sprintf("%s%s", name, "first 10TB)"), startUsage: "0"},
-		{name: fmt.Sprintf("%s%s", name, "next 40TB)"), startUsage: "10000"},
-		{name: fmt.Sprintf("%s%s", name, "next 100TB)"), startUsage: "50000"},
-		{name: fmt.Sprintf("%s%s", name, "next 350TB)"), startUsage: "150000"},
-		{name: fmt.Sprintf("%s%s", name, "next 500TB)"), startUsage: "500000"},
-		{name: fmt.Sprintf("%s%s", name, "next 4000TB)"), startUsage: "1000000"},
-		{name: fmt.Sprintf("%s%s", name, "over 5000TB)"), startUsage: "5000000"},
+	tiers := []resources.Tier{
+		{Name: "first 10TB", EndUsageAmount: 10000},
+		{Name: "next 40TB", EndUsageAmount: 50000},
+		{Name: "next 100TB", EndUsageAmount: 150000},
+		{Name: "next 350TB", EndUsageAmount: 500000},
+		{Name: "next 500TB", EndUsageAmount: 1000000},
+		{Name: "next 4000TB", EndUsageAmount: 5000000},
+		{Name: "over 5000TB"},
 	}
 
 	meterName = fmt.Sprintf("%s Data Transfer", skuName)
@@ -130,53 +123,30 @@ func cdnOutboundDataCostComponents(region, sku string, u *schema.UsageData) []*s
 	var monthlyOutboundGb *decimal.Decimal
 	if u != nil && u.Get("monthly_outbound_gb").Type != gjson.Null {
 		monthlyOutboundGb = decimalPtr(decimal.NewFromInt(u.Get("monthly_outbound_gb").Int()))
-		tierLimits := []int{10000, 40000, 100000, 350000, 500000, 4000000}
-		tiers := usage.CalculateTierBuckets(*monthlyOutboundGb, tierLimits)
+	}
 
-		for i, d := range data {
-			if tiers[i].GreaterThan(decimal.Zero) {
-				costComponents = append(costComponents, cdnCostComponent(
-					d.name,
-					"GB",
-					region,
-					productName,
-					skuName,
-					meterName,
-					d.startUsage,
-					decimalPtr(tiers[i])))
-			}
-		}
-	} else {
-		costComponents = append(costComponents, cdnCostComponent(
-			data[0].name,
+	return resources.TieredCostComponents(monthlyOutboundGb, tiers, func(tier resources.TierQuantity) *schema.CostComponent {
+		return cdnCostComponent(
+			fmt.Sprintf("%s%s)", name, tier.Name),
 			"GB",
 			region,
 			productName,
 			skuName,
 			meterName,
-			data[0].startUsage,
-			nil))
-	}
-
-	return costComponents
+			tier.StartUsageAmountFilter(),
+			tier.Quantity)
+	})
 }
 
 func cdnAccelerationDataTransfersCostComponents(region, sku string, d *schema.ResourceData, u *schema.UsageData) []*schema.CostComponent {
-	costComponents := []*schema.CostComponent{}
-
-	type dataTier struct {
-		name       string
-		startUsage string
-	}
-
 	name := "Acceleration outbound data transfer "
 
-	data := []dataTier{
-		{name: fmt.Sprintf("%s%s", name, "(first 50TB)"), startUsage: "0"},
-		{name: fmt.Sprintf("%s%s", name, "(next 100TB)"), startUsage: "50000"},
-		{name: fmt.Sprintf("%s%s", name, "(next 350TB)"), startUsage: "150000"},
-		{name: fmt.Sprintf("%s%s", name, "(next 500TB)"), startUsage: "500000"},
-		{name: fmt.Sprintf("%s%s", name, "(over 1000TB)"), startUsage: "1000000"},
+	tiers := []resources.Tier{
+		{Name: "first 50TB", EndUsageAmount: 50000},
+		{Name: "next 100TB", EndUsageAmount: 150000},
+		{Name: "next 350TB", EndUsageAmount: 500000},
+		{Name: "next 500TB", EndUsageAmount: 1000000},
+		{Name: "over 1000TB"},
 	}
 
 	var productName, skuName, meterName string
@@ -189,35 +159,19 @@ func cdnAccelerationDataTransfersCostComponents(region, sku string, d *schema.Re
 	var monthlyOutboundGb *decimal.Decimal
 	if u != nil && u.Get("monthly_outbound_gb").Type != gjson.Null {
 		monthlyOutboundGb = decimalPtr(decimal.NewFromInt(u.Get("monthly_outbound_gb").Int()))
-		tierLimits := []int{50000, 100000, 350000, 500000, 1000000}
-		tiers := usage.CalculateTierBuckets(*monthlyOutboundGb, tierLimits)
+	}
 
-		for i, d := range data {
-			if tiers[i].GreaterThan(decimal.Zero) {
-				costComponents = append(costComponents, cdnCostComponent(
-					d.name,
-					"GB",
-					region,
-					productName,
-					skuName,
-					meterName,
-					d.startUsage,
-					decimalPtr(tiers[i])))
-			}
-		}
-	} else {
-		costComponents = append(costComponents, cdnCostComponent(
-			data[0].name,
+	return resources.TieredCostComponents(monthlyOutboundGb, tiers, func(tier resources.TierQuantity) *schema.CostComponent {
+		return cdnCostComponent(
+			fmt.Sprintf("%s(%s)", name, tier.Name),
 			"GB",
 			region,
 			productName,
 			skuName,
 			meterName,
-			data[0].startUsage,
-			nil))
-	}
-
-	return costComponents
+			tier.StartUsageAmountFilter(),
+			tier.Quantity)
+	})
 }
 
 func cdnCostComponent(name, unit, region, productName, skuName, meterName, startUsage string, quantity *decimal.Decimal) *schema.CostComponent {
@@ -40,7 +40,9 @@ func aksClusterNodePool(name, region string, n gjson.Result, nodeCount decimal.D
 		Name: name,
 	}
 	instanceType := n.Get("vm_size").String()
-	costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType))
+	purchaseOption := virtualMachinePurchaseOption(name, n.Get("priority").String(), u)
+	term := reservationTerm(name, purchaseOption, u)
+	costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, purchaseOption, term))
 	mainResource.CostComponents = costComponents
 	schema.MultiplyQuantities(mainResource, nodeCount)
 
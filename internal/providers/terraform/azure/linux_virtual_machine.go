@@ -7,6 +7,7 @@ import (
 	"github.com/infracost/infracost/internal/schema"
 
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 )
 
 func GetAzureRMLinuxVirtualMachineRegistryItem() *schema.RegistryItem {
@@ -15,7 +16,7 @@ func GetAzureRMLinuxVirtualMachineRegistryItem() *schema.RegistryItem {
 		RFunc: NewAzureRMLinuxVirtualMachine,
 		Notes: []string{
 			"Non-standard images such as RHEL are not supported.",
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Low priority instances are not supported.",
 		},
 	}
 }
@@ -25,7 +26,10 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 
 	instanceType := d.Get("size").String()
 
-	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType)}
+	purchaseOption := virtualMachinePurchaseOption(d.Address, d.Get("priority").String(), u)
+	term := reservationTerm(d.Address, purchaseOption, u)
+
+	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, purchaseOption, term)}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -45,15 +49,53 @@ func NewAzureRMLinuxVirtualMachine(d *schema.ResourceData, u *schema.UsageData)
 	}
 }
 
-func linuxVirtualMachineCostComponent(region string, instanceType string) *schema.CostComponent {
-	purchaseOption := "Consumption"
-	purchaseOptionLabel := "pay as you go"
+// virtualMachinePurchaseOption returns spot if the VM's priority is Spot or it is set in the
+// usage data, otherwise on_demand
+func virtualMachinePurchaseOption(address string, priority string, u *schema.UsageData) string {
+	purchaseOption := "on_demand"
+	if strings.ToLower(priority) == "spot" {
+		purchaseOption = "spot"
+	}
+
+	if u != nil && u.Get("purchase_option").Exists() {
+		switch o := strings.ToLower(u.Get("purchase_option").String()); o {
+		case "on_demand", "spot":
+			purchaseOption = o
+		default:
+			log.Warnf("Invalid purchase_option for %s, ignoring. Expected: on_demand, spot. Got: %s", address, o)
+		}
+	}
+
+	return purchaseOption
+}
+
+// virtualMachineSkuNameRegex matches the regular or the Spot SKUs
+func virtualMachineSkuNameRegex(purchaseOption string) string {
+	if purchaseOption == "spot" {
+		return "/ Spot$/i"
+	}
+
+	return "/^(?!.*(Low Priority|Spot)$).*$/i"
+}
 
+// linuxVirtualMachineCostComponent returns the cost component of a Linux VM. The VM is
+// priced at the reservation rate if reservationTerm is set.
+func linuxVirtualMachineCostComponent(region string, instanceType string, purchaseOption string, reservationTerm string) *schema.CostComponent {
 	productNameRe := "/Virtual Machines .* Series$/"
 	if strings.HasPrefix(instanceType, "Basic_") {
 		productNameRe = "/Virtual Machines .* Series Basic$/"
 	}
 
+	if reservationTerm != "" {
+		return reservedVirtualMachineCostComponent(region, instanceType, productNameRe, "reserved "+reservationTermLabels[reservationTerm], reservationTerm)
+	}
+
+	priceOption := "Consumption"
+	purchaseOptionLabel := "pay as you go"
+	if purchaseOption == "spot" {
+		purchaseOptionLabel = "spot"
+	}
+
 	return &schema.CostComponent{
 		Name:           fmt.Sprintf("Instance usage (%s, %s)", purchaseOptionLabel, instanceType),
 		Unit:           "hours",
@@ -65,14 +107,41 @@ func linuxVirtualMachineCostComponent(region string, instanceType string) *schem
 			Service:       strPtr("Virtual Machines"),
 			ProductFamily: strPtr("Compute"),
 			AttributeFilters: []*schema.AttributeFilter{
-				{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")},
+				{Key: "skuName", ValueRegex: strPtr(virtualMachineSkuNameRegex(purchaseOption))},
 				{Key: "armSkuName", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", instanceType))},
 				{Key: "productName", ValueRegex: strPtr(productNameRe)},
 			},
 		},
 		PriceFilter: &schema.PriceFilter{
-			PurchaseOption: strPtr(purchaseOption),
+			PurchaseOption: strPtr(priceOption),
 			Unit:           strPtr("1 Hour"),
 		},
+		PurchaseOption: purchaseOption,
+	}
+}
+
+// reservedVirtualMachineCostComponent returns the cost component of a reserved VM.
+// Reservations only cover the compute, so they use the prices of the Linux products.
+func reservedVirtualMachineCostComponent(region, instanceType, productNameRe, label, reservationTerm string) *schema.CostComponent {
+	unitMultiplier := reservationUnitMultiplier(reservationTerm)
+
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("Instance usage (%s, %s)", label, instanceType),
+		Unit:           "hours",
+		UnitMultiplier: unitMultiplier,
+		HourlyQuantity: decimalPtr(unitMultiplier),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("azure"),
+			Region:        strPtr(region),
+			Service:       strPtr("Virtual Machines"),
+			ProductFamily: strPtr("Compute"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "skuName", ValueRegex: strPtr(virtualMachineSkuNameRegex("reserved"))},
+				{Key: "armSkuName", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", instanceType))},
+				{Key: "productName", ValueRegex: strPtr(productNameRe)},
+			},
+		},
+		PriceFilter:    reservationPriceFilter(reservationTerm),
+		PurchaseOption: "reserved",
 	}
 }
@@ -18,7 +18,10 @@ func NewAzureRMLinuxVirtualMachineScaleSet(d *schema.ResourceData, u *schema.Usa
 
 	instanceType := d.Get("sku").String()
 
-	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType)}
+	purchaseOption := virtualMachinePurchaseOption(d.Address, d.Get("priority").String(), u)
+	term := reservationTerm(d.Address, purchaseOption, u)
+
+	costComponents := []*schema.CostComponent{linuxVirtualMachineCostComponent(region, instanceType, purchaseOption, term)}
 	subResources := make([]*schema.Resource, 0)
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
@@ -3,7 +3,12 @@ package azure_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestAzureRMLinuxVirtualMachineGoldenFile(t *testing.T) {
@@ -14,3 +19,65 @@ func TestAzureRMLinuxVirtualMachineGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "linux_virtual_machine_test")
 }
+
+func TestAzureRMLinuxVirtualMachineSpot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("azurerm_linux_virtual_machine", "azurerm", "azurerm_linux_virtual_machine.spot", nil, gjson.Parse(`{
+		"location": "eastus",
+		"size": "Standard_D2s_v3",
+		"priority": "Spot"
+	}`))
+
+	r := azure.NewAzureRMLinuxVirtualMachine(d, nil)
+
+	c := r.CostComponents[0]
+	assert.Equal(t, "Instance usage (spot, Standard_D2s_v3)", c.Name)
+	assert.Equal(t, "spot", c.PurchaseOption)
+	assert.Equal(t, "Consumption", *c.PriceFilter.PurchaseOption)
+	assert.Equal(t, "/ Spot$/i", *c.ProductFilter.AttributeFilters[0].ValueRegex)
+
+	u := schema.NewUsageData("azurerm_linux_virtual_machine.spot", schema.ParseAttributes(map[string]interface{}{
+		"purchase_option": "on_demand",
+	}))
+
+	r = azure.NewAzureRMLinuxVirtualMachine(d, u)
+	assert.Equal(t, "Instance usage (pay as you go, Standard_D2s_v3)", r.CostComponents[0].Name)
+	assert.Equal(t, "on_demand", r.CostComponents[0].PurchaseOption)
+}
+
+func TestAzureRMLinuxVirtualMachineReserved(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("azurerm_linux_virtual_machine", "azurerm", "azurerm_linux_virtual_machine.reserved", nil, gjson.Parse(`{
+		"location": "eastus",
+		"size": "Standard_D2s_v3"
+	}`))
+	u := schema.NewUsageData("azurerm_linux_virtual_machine.reserved", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term": "1_year",
+	}))
+
+	r := azure.NewAzureRMLinuxVirtualMachine(d, u)
+
+	c := r.CostComponents[0]
+	assert.Equal(t, "Instance usage (reserved 1yr, Standard_D2s_v3)", c.Name)
+	assert.Equal(t, "reserved", c.PurchaseOption)
+	assert.Equal(t, "Reservation", *c.PriceFilter.PurchaseOption)
+	assert.Equal(t, "1 Year", *c.PriceFilter.TermLength)
+
+	// The price of the term is amortized over its hours, so the output shows 730 hours
+	// each month at the price divided by the hours of the term
+	c.SetPrice(decimal.NewFromInt(8760))
+	schema.CalculateCosts(&schema.Project{Resources: []*schema.Resource{r}})
+	assert.Equal(t, "1", c.HourlyCost.Round(6).String())
+	assert.Equal(t, "730", c.MonthlyQuantity.Div(c.UnitMultiplier).Round(6).String())
+
+	// Spot instances can't be reserved
+	spot := schema.NewResourceData("azurerm_linux_virtual_machine", "azurerm", "azurerm_linux_virtual_machine.reserved", nil, gjson.Parse(`{
+		"location": "eastus",
+		"size": "Standard_D2s_v3",
+		"priority": "Spot"
+	}`))
+	r = azure.NewAzureRMLinuxVirtualMachine(spot, u)
+	assert.Equal(t, "Instance usage (spot, Standard_D2s_v3)", r.CostComponents[0].Name)
+}
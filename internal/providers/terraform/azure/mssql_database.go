@@ -141,6 +141,10 @@ func vCorePurchaseCostComponents(d *schema.ResourceData, u *schema.UsageData, sk
 	skuName := mssqlSkuName(cores, zoneRedundant)
 
 	if strings.ToLower(tier) == "general purpose - serverless" {
+		if u != nil && u.Get("reserved_instance_term").Type != gjson.Null {
+			log.Warnf("Serverless databases can't be reserved, ignoring reserved_instance_term for %s", d.Address)
+		}
+
 		var vCoreHours *decimal.Decimal
 		if u != nil && u.Get("monthly_vcore_hours").Exists() {
 			vCoreHours = decimalPtr(decimal.NewFromInt(u.Get("monthly_vcore_hours").Int()))
@@ -166,6 +170,8 @@ func vCorePurchaseCostComponents(d *schema.ResourceData, u *schema.UsageData, sk
 				PurchaseOption: strPtr("Consumption"),
 			},
 		})
+	} else if term := reservationTerm(d.Address, "on_demand", u); term != "" {
+		costComponents = append(costComponents, mssqlReservedComputeCostComponent(region, sku, serviceName, productNameRegex, cores, term))
 	} else {
 		name := fmt.Sprintf("Compute (provisioned, %s)", sku)
 		log.Warnf("'Multiple products found' are safe to ignore for '%s' due to limitations in the Azure API.", name)
@@ -203,7 +209,7 @@ func vCorePurchaseCostComponents(d *schema.ResourceData, u *schema.UsageData, sk
 		if d.Get("license_type").Type != gjson.Null {
 			licenseType = d.Get("license_type").String()
 		}
-		if strings.ToLower(licenseType) == "licenseincluded" {
+		if !hybridBenefit(strings.ToLower(licenseType) != "licenseincluded", u) {
 			costComponents = append(costComponents, sqlLicenseCostComponent(region, cores, serviceName, tier))
 		}
 	}
@@ -264,6 +270,33 @@ func mssqlSkuName(cores string, zoneRedundant bool) string {
 	return sku
 }
 
+// mssqlReservedComputeCostComponent returns the cost component of the provisioned compute
+// of a database with reserved capacity. Reserved capacity is priced per vCore, and zone
+// redundant databases use the same reservations.
+func mssqlReservedComputeCostComponent(region, sku, serviceName, productNameRegex, cores, term string) *schema.CostComponent {
+	coresNum, _ := strconv.ParseInt(cores, 10, 64)
+	unitMultiplier := reservationUnitMultiplier(term)
+
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("Compute (provisioned, reserved %s, %s)", reservationTermLabels[term], sku),
+		Unit:           "vCore-hours",
+		UnitMultiplier: unitMultiplier,
+		HourlyQuantity: decimalPtr(unitMultiplier.Mul(decimal.NewFromInt(coresNum))),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("azure"),
+			Region:        strPtr(region),
+			Service:       strPtr(serviceName),
+			ProductFamily: strPtr("Databases"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "productName", ValueRegex: strPtr(productNameRegex)},
+				{Key: "skuName", Value: strPtr("vCore")},
+			},
+		},
+		PriceFilter:    reservationPriceFilter(term),
+		PurchaseOption: "reserved",
+	}
+}
+
 func sqlLicenseCostComponent(region, cores, serviceName, tier string) *schema.CostComponent {
 	licenseRegion := "Global"
 	if strings.Contains(region, "usgov") {
@@ -3,7 +3,11 @@ package azure_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestMSSQLDatabase(t *testing.T) {
@@ -14,3 +18,36 @@ func TestMSSQLDatabase(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "mssql_database_test")
 }
+
+func TestMSSQLDatabaseReservedHybridBenefit(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("azurerm_mssql_database", "azurerm", "azurerm_mssql_database.db", nil, gjson.Parse(`{
+		"sku_name": "GP_Gen5_4",
+		"region": "eastus"
+	}`))
+
+	names := func(r *schema.Resource) []string {
+		var n []string
+		for _, c := range r.CostComponents {
+			n = append(n, c.Name)
+		}
+		return n
+	}
+
+	r := azure.NewAzureRMMSSQLDatabase(d, nil)
+	assert.Contains(t, names(r), "Compute (provisioned, GP_Gen5_4)")
+	assert.Contains(t, names(r), "SQL license")
+
+	u := schema.NewUsageData("azurerm_mssql_database.db", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term": "1_year",
+		"azure_hybrid_benefit":   true,
+	}))
+	r = azure.NewAzureRMMSSQLDatabase(d, u)
+	assert.Contains(t, names(r), "Compute (provisioned, reserved 1yr, GP_Gen5_4)")
+	assert.NotContains(t, names(r), "SQL license")
+
+	c := r.CostComponents[0]
+	assert.Equal(t, "Reservation", *c.PriceFilter.PurchaseOption)
+	assert.Equal(t, "4", c.HourlyQuantity.Div(c.UnitMultiplier).Round(6).String())
+}
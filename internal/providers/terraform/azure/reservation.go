@@ -0,0 +1,83 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+var reservationTermLengths = map[string]string{
+	"1_year": "1 Year",
+	"3_year": "3 Years",
+}
+
+var reservationTermLabels = map[string]string{
+	"1_year": "1yr",
+	"3_year": "3yr",
+}
+
+// The reservation prices are for the whole term, so they are spread over the hours of the
+// term to get an hourly rate that is comparable with pay as you go.
+var reservationTermHours = map[string]int64{
+	"1_year": 8760,
+	"3_year": 26280,
+}
+
+// reservationTerm returns the reserved_instance_term from the usage data, or an empty
+// string if the resource isn't reserved. Spot instances can't be reserved.
+func reservationTerm(address string, purchaseOption string, u *schema.UsageData) string {
+	if u == nil || u.Get("reserved_instance_term").Type == gjson.Null {
+		return ""
+	}
+
+	term := u.Get("reserved_instance_term").String()
+	if _, ok := reservationTermLengths[term]; !ok {
+		log.Warnf("Invalid reserved_instance_term for %s, ignoring. Expected: 1_year, 3_year. Got: %s", address, term)
+		return ""
+	}
+
+	if purchaseOption == "spot" {
+		log.Warnf("Spot instances can't be reserved, ignoring reserved_instance_term for %s", address)
+		return ""
+	}
+
+	return term
+}
+
+// reservationPriceFilter returns the price filter for the total price of a reservation.
+// The prices have a unit of 1 Hour even though they are for the whole term.
+func reservationPriceFilter(term string) *schema.PriceFilter {
+	return &schema.PriceFilter{
+		PurchaseOption: strPtr("Reservation"),
+		Unit:           strPtr("1 Hour"),
+		TermLength:     strPtr(reservationTermLengths[term]),
+	}
+}
+
+// reservationUnitMultiplier is the unit multiplier of the hourly quantity of a reservation,
+// which amortizes the price of the term over its hours.
+func reservationUnitMultiplier(term string) decimal.Decimal {
+	return decimal.NewFromInt(1).Div(decimal.NewFromInt(reservationTermHours[term]))
+}
+
+// hybridBenefit returns whether Azure Hybrid Benefit is used for the resource. The
+// azure_hybrid_benefit usage key overrides whether it is enabled in the resource's
+// license_type.
+func hybridBenefit(enabled bool, u *schema.UsageData) bool {
+	if u != nil && u.Get("azure_hybrid_benefit").Type != gjson.Null {
+		return u.Get("azure_hybrid_benefit").Bool()
+	}
+
+	return enabled
+}
+
+// windowsHybridBenefit returns whether Azure Hybrid Benefit is used for a Windows VM with
+// the license_type
+func windowsHybridBenefit(licenseType string, u *schema.UsageData) bool {
+	l := strings.ToLower(licenseType)
+	return hybridBenefit(l == "windows_client" || l == "windows_server", u)
+}
@@ -30,11 +30,13 @@ func NewAzureRMVirtualMachine(d *schema.ResourceData, u *schema.UsageData) *sche
 		os = "Windows"
 	}
 
+	term := reservationTerm(d.Address, "on_demand", u)
+
 	if strings.ToLower(os) == "windows" {
 		licenseType := d.Get("license_type").String()
-		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType))
+		costComponents = append(costComponents, windowsVirtualMachineCostComponent(d.Address, region, instanceType, "on_demand", term, windowsHybridBenefit(licenseType, u)))
 	} else {
-		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType))
+		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, "on_demand", term))
 	}
 
 	costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -42,8 +42,10 @@ func NewAzureRMVirtualMachineScaleSet(d *schema.ResourceData, u *schema.UsageDat
 		}
 	}
 
+	term := reservationTerm(d.Address, "on_demand", u)
+
 	if strings.ToLower(os) == "linux" {
-		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType))
+		costComponents = append(costComponents, linuxVirtualMachineCostComponent(region, instanceType, "on_demand", term))
 	}
 
 	if strings.ToLower(os) == "windows" {
@@ -51,7 +53,7 @@ func NewAzureRMVirtualMachineScaleSet(d *schema.ResourceData, u *schema.UsageDat
 		if d.Get("license_type").Type != gjson.Null {
 			licenseType = d.Get("license_type").String()
 		}
-		costComponents = append(costComponents, windowsVirtualMachineCostComponent(region, instanceType, licenseType))
+		costComponents = append(costComponents, windowsVirtualMachineCostComponent(d.Address, region, instanceType, "on_demand", term, windowsHybridBenefit(licenseType, u)))
 	}
 
 	r := &schema.Resource{
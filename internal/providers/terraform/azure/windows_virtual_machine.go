@@ -6,6 +6,7 @@ import (
 
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 )
 
 func GetAzureRMWindowsVirtualMachineRegistryItem() *schema.RegistryItem {
@@ -13,7 +14,9 @@ func GetAzureRMWindowsVirtualMachineRegistryItem() *schema.RegistryItem {
 		Name:  "azurerm_windows_virtual_machine",
 		RFunc: NewAzureRMWindowsVirtualMachine,
 		Notes: []string{
-			"Low priority, Spot and Reserved instances are not supported.",
+			"Low priority instances are not supported.",
+			"Azure Hybrid Benefit is not applied to Spot instances.",
+			"Reserved instances are only supported with Azure Hybrid Benefit, since reservations don't cover the Windows license.",
 		},
 	}
 }
@@ -24,7 +27,10 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 	instanceType := d.Get("size").String()
 	licenseType := d.Get("license_type").String()
 
-	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType)}
+	purchaseOption := virtualMachinePurchaseOption(d.Address, d.Get("priority").String(), u)
+	term := reservationTerm(d.Address, purchaseOption, u)
+
+	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(d.Address, region, instanceType, purchaseOption, term, windowsHybridBenefit(licenseType, u))}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -44,8 +50,20 @@ func NewAzureRMWindowsVirtualMachine(d *schema.ResourceData, u *schema.UsageData
 	}
 }
 
-func windowsVirtualMachineCostComponent(region string, instanceType string, licenseType string) *schema.CostComponent {
-	purchaseOption := "Consumption"
+// windowsVirtualMachineCostComponent returns the cost component of a Windows VM. The VM is
+// priced at the reservation rate if reservationTerm is set and it uses Azure Hybrid Benefit.
+func windowsVirtualMachineCostComponent(address string, region string, instanceType string, purchaseOption string, reservationTerm string, hybridBenefit bool) *schema.CostComponent {
+	if reservationTerm != "" {
+		if hybridBenefit {
+			// With Azure Hybrid Benefit only the compute is paid for, which the reservation covers
+			productNameRe := "/Virtual Machines .* Series$/"
+			return reservedVirtualMachineCostComponent(region, instanceType, productNameRe, "hybrid benefit, reserved "+reservationTermLabels[reservationTerm], reservationTerm)
+		}
+
+		log.Warnf("Reserved instances are only supported for Windows VMs with Azure Hybrid Benefit, using pay as you go prices for %s", address)
+	}
+
+	priceOption := "Consumption"
 	purchaseOptionLabel := "pay as you go"
 
 	productNameRe := "/Virtual Machines .* Series Windows$/"
@@ -53,9 +71,11 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 		productNameRe = "/Virtual Machines .* Series Basic Windows$/"
 	}
 
-	// Handle Azure Hybrid Benefit
-	if strings.ToLower(licenseType) == "windows_client" || strings.ToLower(licenseType) == "windows_server" {
-		purchaseOption = "DevTestConsumption"
+	// Handle Azure Hybrid Benefit, which isn't applied to Spot instances
+	if purchaseOption == "spot" {
+		purchaseOptionLabel = "spot"
+	} else if hybridBenefit {
+		priceOption = "DevTestConsumption"
 		purchaseOptionLabel = "hybrid benefit"
 	}
 
@@ -70,14 +90,15 @@ func windowsVirtualMachineCostComponent(region string, instanceType string, lice
 			Service:       strPtr("Virtual Machines"),
 			ProductFamily: strPtr("Compute"),
 			AttributeFilters: []*schema.AttributeFilter{
-				{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")},
+				{Key: "skuName", ValueRegex: strPtr(virtualMachineSkuNameRegex(purchaseOption))},
 				{Key: "armSkuName", ValueRegex: strPtr(fmt.Sprintf("/^%s$/i", instanceType))},
 				{Key: "productName", ValueRegex: strPtr(productNameRe)},
 			},
 		},
 		PriceFilter: &schema.PriceFilter{
-			PurchaseOption: strPtr(purchaseOption),
+			PurchaseOption: strPtr(priceOption),
 			Unit:           strPtr("1 Hour"),
 		},
+		PurchaseOption: purchaseOption,
 	}
 }
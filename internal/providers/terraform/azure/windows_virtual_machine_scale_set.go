@@ -19,7 +19,10 @@ func NewAzureRMWindowsVirtualMachineScaleSet(d *schema.ResourceData, u *schema.U
 	instanceType := d.Get("sku").String()
 	licenseType := d.Get("license_type").String()
 
-	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(region, instanceType, licenseType)}
+	purchaseOption := virtualMachinePurchaseOption(d.Address, d.Get("priority").String(), u)
+	term := reservationTerm(d.Address, purchaseOption, u)
+
+	costComponents := []*schema.CostComponent{windowsVirtualMachineCostComponent(d.Address, region, instanceType, purchaseOption, term, windowsHybridBenefit(licenseType, u))}
 
 	if d.Get("additional_capabilities.0.ultra_ssd_enabled").Bool() {
 		costComponents = append(costComponents, ultraSSDReservationCostComponent(region))
@@ -3,7 +3,11 @@ package azure_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestAzureRMWindowsVirtualMachineGoldenFile(t *testing.T) {
@@ -14,3 +18,40 @@ func TestAzureRMWindowsVirtualMachineGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "windows_virtual_machine_test")
 }
+
+func TestAzureRMWindowsVirtualMachineHybridBenefit(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("azurerm_windows_virtual_machine", "azurerm", "azurerm_windows_virtual_machine.vm", nil, gjson.Parse(`{
+		"location": "eastus",
+		"size": "Standard_D2s_v3"
+	}`))
+
+	r := azure.NewAzureRMWindowsVirtualMachine(d, nil)
+	assert.Equal(t, "Instance usage (pay as you go, Standard_D2s_v3)", r.CostComponents[0].Name)
+
+	u := schema.NewUsageData("azurerm_windows_virtual_machine.vm", schema.ParseAttributes(map[string]interface{}{
+		"azure_hybrid_benefit": true,
+	}))
+	r = azure.NewAzureRMWindowsVirtualMachine(d, u)
+	assert.Equal(t, "Instance usage (hybrid benefit, Standard_D2s_v3)", r.CostComponents[0].Name)
+	assert.Equal(t, "DevTestConsumption", *r.CostComponents[0].PriceFilter.PurchaseOption)
+
+	// Reservations with Azure Hybrid Benefit use the reservation prices of the Linux products
+	u = schema.NewUsageData("azurerm_windows_virtual_machine.vm", schema.ParseAttributes(map[string]interface{}{
+		"azure_hybrid_benefit":   true,
+		"reserved_instance_term": "3_year",
+	}))
+	r = azure.NewAzureRMWindowsVirtualMachine(d, u)
+	c := r.CostComponents[0]
+	assert.Equal(t, "Instance usage (hybrid benefit, reserved 3yr, Standard_D2s_v3)", c.Name)
+	assert.Equal(t, "3 Years", *c.PriceFilter.TermLength)
+	assert.Equal(t, "/Virtual Machines .* Series$/", *c.ProductFilter.AttributeFilters[2].ValueRegex)
+
+	// Reservations don't cover the Windows license, so pay as you go is used without it
+	u = schema.NewUsageData("azurerm_windows_virtual_machine.vm", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term": "3_year",
+	}))
+	r = azure.NewAzureRMWindowsVirtualMachine(d, u)
+	assert.Equal(t, "Instance usage (pay as you go, Standard_D2s_v3)", r.CostComponents[0].Name)
+}
@@ -21,6 +21,7 @@ type CmdOptions struct {
 	Dir                 string
 	TerraformWorkspace  string
 	TerraformConfigFile string
+	Env                 []string
 }
 
 type CmdError struct {
@@ -52,6 +53,8 @@ func Cmd(opts *CmdOptions, args ...string) ([]byte, error) {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("TF_CLI_CONFIG_FILE=%s", opts.TerraformConfigFile))
 	}
 
+	cmd.Env = append(cmd.Env, opts.Env...)
+
 	logWriter := &cmdLogWriter{
 		logger: log.StandardLogger().WithField("binary", "terraform"),
 		level:  log.DebugLevel,
@@ -0,0 +1,163 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tidwall/gjson"
+)
+
+// References that are always known during the plan, so a count or for_each that only uses
+// these can be evaluated by Terraform.
+var knownRefPrefixes = []string{"var.", "count.", "each.", "path.", "terraform."}
+
+// addEstimatedResources adds the instances of resources that have a count or for_each that
+// isn't known until apply. Terraform doesn't include these in the plan, so the number of
+// instances is taken from the count_estimate usage key of the resource, e.g. setting
+// count_estimate: 3 for aws_instance.web[*] adds aws_instance.web[0] to aws_instance.web[2],
+// or from the count_estimate of the project in the config file. The instances are created
+// with the values from the resource config that are known, and the estimate is added to
+// their metadata so the assumption is visible in the output.
+func (p *Parser) addEstimatedResources(resData map[string]*schema.ResourceData, usage map[string]*schema.UsageData, providerConf, conf, rootConf, vars gjson.Result, modulePrefix string) {
+	for _, c := range conf.Get("resources").Array() {
+		countExpr := c.Get("count_expression")
+		if !countExpr.Exists() {
+			countExpr = c.Get("for_each_expression")
+		}
+
+		if c.Get("mode").String() != "managed" || !countExpr.Exists() {
+			continue
+		}
+
+		addr := fmt.Sprintf("%s%s", modulePrefix, c.Get("address").String())
+		if hasResourceInstances(resData, addr) {
+			continue
+		}
+
+		estimate, source := countEstimate(usage, addr)
+		if estimate < 0 {
+			if !hasUnknownRefs(countExpr) {
+				continue
+			}
+
+			if p.ctx.ProjectConfig.CountEstimate <= 0 {
+				log.Warnf("The count or for_each of %s isn't known until apply so it is not included in the estimate. Set count_estimate for %s[*] in the usage file to include it.", addr, addr)
+				continue
+			}
+
+			estimate, source = int64(p.ctx.ProjectConfig.CountEstimate), "config_file"
+		}
+
+		t := c.Get("type").String()
+		provider := resolveProvider(addr, providerConf, vars, t, c)
+
+		v, _ := json.Marshal(expressionValues(c.Get("expressions"), vars)) // TODO: unhandled error
+		vals := schema.AddRawValue(gjson.ParseBytes(v), "region", provider.Region)
+
+		log.Debugf("Adding %d estimated instances of %s", estimate, addr)
+
+		for i := int64(0); i < estimate; i++ {
+			instanceAddr := fmt.Sprintf("%s[%d]", addr, i)
+
+			d := schema.NewResourceData(t, c.Get("provider_config_key").String(), instanceAddr, parseTags(t, vals), vals)
+			d.Metadata = parseModuleMetadata(rootConf, p.moduleManifest, instanceAddr)
+			if d.Metadata == nil {
+				d.Metadata = make(map[string]string)
+			}
+			d.Metadata["countEstimate"] = strconv.FormatInt(estimate, 10)
+			d.Metadata["countEstimateSource"] = source
+			addProviderMetadata(d, provider, provider.Region)
+
+			resData[instanceAddr] = d
+		}
+	}
+
+	for name, m := range conf.Get("module_calls").Map() {
+		p.addEstimatedResources(resData, usage, providerConf, m.Get("module"), rootConf, vars, fmt.Sprintf("%smodule.%s.", modulePrefix, name))
+	}
+}
+
+func hasResourceInstances(resData map[string]*schema.ResourceData, addr string) bool {
+	for a := range resData {
+		if a == addr || strings.HasPrefix(a, addr+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countEstimate returns the count_estimate for the resource from the usage data or -1 if
+// there isn't one
+func countEstimate(usage map[string]*schema.UsageData, addr string) (int64, string) {
+	for _, k := range []string{fmt.Sprintf("%s[*]", addr), addr} {
+		if u := schema.FindUsageData(usage, k); u != nil && u.Get("count_estimate").Exists() {
+			return u.Get("count_estimate").Int(), "usage_file"
+		}
+	}
+
+	return -1, ""
+}
+
+func hasUnknownRefs(expr gjson.Result) bool {
+	for _, ref := range expr.Get("references").Array() {
+		known := false
+
+		for _, prefix := range knownRefPrefixes {
+			if strings.HasPrefix(ref.String(), prefix) {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expressionValues returns the values of the config expressions that are constants or
+// variables. Nested blocks are returned as lists of objects like they are in the plan.
+func expressionValues(expr gjson.Result, vars gjson.Result) interface{} {
+	if expr.IsArray() {
+		vals := make([]interface{}, 0, len(expr.Array()))
+		for _, e := range expr.Array() {
+			vals = append(vals, expressionValues(e, vars))
+		}
+
+		return vals
+	}
+
+	if !expr.IsObject() {
+		return nil
+	}
+
+	if v := expr.Get("constant_value"); v.Exists() {
+		return v.Value()
+	}
+
+	if refs := expr.Get("references"); refs.Exists() {
+		ref := refs.Get("0").String()
+		if strings.HasPrefix(ref, "var.") && len(refs.Array()) == 1 {
+			return vars.Get(fmt.Sprintf("%s.value", strings.TrimPrefix(ref, "var."))).Value()
+		}
+
+		return nil
+	}
+
+	vals := make(map[string]interface{})
+	for k, e := range expr.Map() {
+		if v := expressionValues(e, vars); v != nil {
+			vals[k] = v
+		}
+	}
+
+	return vals
+}
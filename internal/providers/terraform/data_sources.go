@@ -0,0 +1,332 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tidwall/gjson"
+)
+
+type dataSourceResolver func(conf gjson.Result, vars gjson.Result, region string) map[string]interface{}
+
+// Data sources that affect pricing, e.g. the instance type or the zone of a resource.
+// Terraform doesn't read data sources during the plan if they depend on values that are
+// only known after apply, so these resolve the attributes from the data source config.
+var dataSourceResolvers = map[string]dataSourceResolver{
+	"aws_ami":                        resolveAWSAMI,
+	"aws_availability_zone":          resolveAWSAvailabilityZone,
+	"aws_availability_zones":         resolveAWSAvailabilityZones,
+	"aws_ec2_instance_type":          resolveAWSEC2InstanceType,
+	"aws_ec2_instance_type_offering": resolveAWSEC2InstanceTypeOffering,
+	"aws_region":                     resolveAWSRegion,
+	"google_compute_image":           resolveGoogleComputeImage,
+	"google_compute_zones":           resolveGoogleComputeZones,
+}
+
+// resolveDataSources adds the values of the data sources that Terraform didn't read during
+// the plan, and sets any unknown resource attributes that reference a data source to the
+// value of the data source.
+func (p *Parser) resolveDataSources(resData map[string]*schema.ResourceData, providerConf, conf, vars gjson.Result) {
+	addDataSources(resData, providerConf, conf, vars, "")
+
+	for _, d := range resData {
+		if strings.HasPrefix(addressResourcePart(d.Address), "data.") {
+			continue
+		}
+
+		expressions := getConfJSON(conf, d.Address).Get("expressions")
+		walkExpressions(expressions, []string{}, func(path []string, refs []string) {
+			resolveDataSourceRef(resData, d, path, refs)
+		})
+	}
+}
+
+func addDataSources(resData map[string]*schema.ResourceData, providerConf, conf, vars gjson.Result, modulePrefix string) {
+	for _, c := range conf.Get("resources").Array() {
+		t := c.Get("type").String()
+
+		resolver, ok := dataSourceResolvers[t]
+		if c.Get("mode").String() != "data" || !ok {
+			continue
+		}
+
+		addr := fmt.Sprintf("%s%s", modulePrefix, c.Get("address").String())
+		region := resolveProvider(addr, providerConf, vars, t, c).Region
+
+		vals := resolver(c, vars, region)
+		vals["region"] = region
+
+		found := false
+
+		for _, d := range resData {
+			if d.Address != addr && !strings.HasPrefix(d.Address, addr+"[") {
+				continue
+			}
+
+			found = true
+
+			// Only set the attributes that Terraform didn't already read
+			for k, v := range vals {
+				if !d.Get(k).Exists() || d.Get(k).Type == gjson.Null {
+					d.Set(k, v)
+				}
+			}
+		}
+
+		if !found {
+			log.Debugf("Resolving %s from its config since it was not read during the plan", addr)
+
+			j, _ := json.Marshal(vals) // TODO: unhandled error
+			resData[addr] = schema.NewResourceData(t, c.Get("provider_config_key").String(), addr, map[string]string{}, gjson.ParseBytes(j))
+		}
+	}
+
+	for name, m := range conf.Get("module_calls").Map() {
+		addDataSources(resData, providerConf, m.Get("module"), vars, fmt.Sprintf("%smodule.%s.", modulePrefix, name))
+	}
+}
+
+// walkExpressions calls fn with the attribute path and references of each expression,
+// including the expressions in nested blocks, e.g. boot_disk.0.initialize_params.0.image
+func walkExpressions(expr gjson.Result, path []string, fn func(path []string, refs []string)) {
+	if expr.IsArray() {
+		for i, e := range expr.Array() {
+			walkExpressions(e, appendPath(path, strconv.Itoa(i)), fn)
+		}
+		return
+	}
+
+	if !expr.IsObject() {
+		return
+	}
+
+	if refs := expr.Get("references"); refs.Exists() {
+		r := make([]string, 0, len(refs.Array()))
+		for _, ref := range refs.Array() {
+			r = append(r, ref.String())
+		}
+
+		fn(path, r)
+		return
+	}
+
+	if expr.Get("constant_value").Exists() {
+		return
+	}
+
+	for k, e := range expr.Map() {
+		walkExpressions(e, appendPath(path, k), fn)
+	}
+}
+
+func appendPath(path []string, key string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), key)
+}
+
+func resolveDataSourceRef(resData map[string]*schema.ResourceData, d *schema.ResourceData, path []string, refs []string) {
+	attrPath := strings.Join(path, ".")
+	if v := d.Get(attrPath); v.Exists() && v.Type != gjson.Null {
+		return
+	}
+
+	for _, ref := range refs {
+		// The reference should include the attribute, e.g. data.aws_ami.ubuntu.id
+		p := strings.Split(ref, ".")
+		if p[0] != "data" || len(p) < 4 {
+			continue
+		}
+
+		dataAddr := fmt.Sprintf("%s%s", addressModulePart(d.Address), strings.Join(p[:3], "."))
+		dataD, ok := resData[dataAddr]
+		if !ok {
+			dataD, ok = resData[fmt.Sprintf("%s[0]", dataAddr)]
+		}
+		if !ok {
+			continue
+		}
+
+		v := dataD.Get(strings.Join(p[3:], "."))
+		if !v.Exists() || v.Type == gjson.Null {
+			continue
+		}
+
+		// The references don't include the index so if a list is used for a singular
+		// attribute, e.g. availability_zone = data.aws_availability_zones.names[count.index],
+		// use the count index or the first item.
+		if v.IsArray() && !strings.HasSuffix(path[len(path)-1], "s") {
+			items := v.Array()
+			if len(items) == 0 {
+				continue
+			}
+
+			i := 0
+			if containsString(refs, "count.index") && addressCountIndex(d.Address) > 0 {
+				i = addressCountIndex(d.Address) % len(items)
+			}
+			v = items[i]
+		}
+
+		log.Debugf("Setting %s.%s from %s", d.Address, attrPath, dataAddr)
+		d.RawValues = setRawValuePath(d.RawValues, path, v.Value())
+
+		return
+	}
+}
+
+// setRawValuePath sets the value at the path, creating any objects or lists in the path
+// that don't exist.
+func setRawValuePath(r gjson.Result, path []string, v interface{}) gjson.Result {
+	var j interface{}
+
+	_ = json.Unmarshal([]byte(r.Raw), &j) // TODO: unhandled error
+
+	mj, _ := json.Marshal(setValuePath(j, path, v)) // TODO: unhandled error
+
+	return gjson.ParseBytes(mj)
+}
+
+func setValuePath(j interface{}, path []string, v interface{}) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+
+	if i, err := strconv.Atoi(path[0]); err == nil {
+		a, _ := j.([]interface{})
+		for len(a) <= i {
+			a = append(a, nil)
+		}
+
+		a[i] = setValuePath(a[i], path[1:], v)
+
+		return a
+	}
+
+	m, ok := j.(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+	}
+
+	m[path[0]] = setValuePath(m[path[0]], path[1:], v)
+
+	return m
+}
+
+func resolveAWSAMI(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	terms := []string{parseExpressionValue(conf.Get("expressions.name_regex"), vars)}
+
+	for _, f := range conf.Get("expressions.filter").Array() {
+		if !containsString([]string{"name", "description", "platform", "platform-details"}, parseExpressionValue(f.Get("name"), vars)) {
+			continue
+		}
+
+		for _, v := range f.Get("values.constant_value").Array() {
+			terms = append(terms, v.String())
+		}
+	}
+
+	s := strings.ToLower(strings.Join(terms, " "))
+
+	switch {
+	case strings.Contains(s, "windows"):
+		return map[string]interface{}{"platform": "windows", "platform_details": "Windows"}
+	case strings.Contains(s, "rhel") || strings.Contains(s, "red hat"):
+		return map[string]interface{}{"platform_details": "Red Hat Enterprise Linux"}
+	case strings.Contains(s, "suse") || strings.Contains(s, "sles"):
+		return map[string]interface{}{"platform_details": "SUSE Linux"}
+	}
+
+	return map[string]interface{}{"platform_details": "Linux/UNIX"}
+}
+
+func resolveAWSAvailabilityZone(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	name := parseExpressionValue(conf.Get("expressions.name"), vars)
+	if name == "" {
+		name = fmt.Sprintf("%sa", region)
+	}
+
+	return map[string]interface{}{"name": name}
+}
+
+// resolveAWSAvailabilityZones assumes the region has at least three zones, which is true
+// for all the AWS regions that aren't local or wavelength zones.
+func resolveAWSAvailabilityZones(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	names := make([]string, 0, 3)
+	for _, suffix := range []string{"a", "b", "c"} {
+		names = append(names, fmt.Sprintf("%s%s", region, suffix))
+	}
+
+	return map[string]interface{}{"names": names}
+}
+
+func resolveAWSEC2InstanceType(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	vals := map[string]interface{}{}
+
+	if v := parseExpressionValue(conf.Get("expressions.instance_type"), vars); v != "" {
+		vals["instance_type"] = v
+	}
+
+	return vals
+}
+
+func resolveAWSEC2InstanceTypeOffering(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	vals := map[string]interface{}{}
+
+	// The first preferred instance type is used if it's offered, which we can't check
+	if v := conf.Get("expressions.preferred_instance_types.constant_value.0"); v.Exists() {
+		vals["instance_type"] = v.String()
+	}
+
+	return vals
+}
+
+func resolveAWSRegion(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	name := parseExpressionValue(conf.Get("expressions.name"), vars)
+	if name == "" {
+		name = region
+	}
+
+	return map[string]interface{}{"name": name}
+}
+
+func resolveGoogleComputeImage(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	vals := map[string]interface{}{}
+
+	project := parseExpressionValue(conf.Get("expressions.project"), vars)
+	family := parseExpressionValue(conf.Get("expressions.family"), vars)
+	name := parseExpressionValue(conf.Get("expressions.name"), vars)
+
+	if project != "" {
+		vals["project"] = project
+	}
+
+	switch {
+	case name != "":
+		vals["name"] = name
+		vals["self_link"] = fmt.Sprintf("projects/%s/global/images/%s", project, name)
+	case family != "":
+		vals["family"] = family
+		vals["self_link"] = fmt.Sprintf("projects/%s/global/images/family/%s", project, family)
+	}
+
+	return vals
+}
+
+// resolveGoogleComputeZones assumes the region has the a, b and c zones, which is true
+// for most Google Cloud regions.
+func resolveGoogleComputeZones(conf gjson.Result, vars gjson.Result, region string) map[string]interface{} {
+	if r := parseExpressionValue(conf.Get("expressions.region"), vars); r != "" {
+		region = r
+	}
+
+	names := make([]string, 0, 3)
+	for _, suffix := range []string{"a", "b", "c"} {
+		names = append(names, fmt.Sprintf("%s-%s", region, suffix))
+	}
+
+	return map[string]interface{}{"names": names}
+}
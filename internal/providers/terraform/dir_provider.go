@@ -9,12 +9,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/infracost/infracost/internal/clierror"
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
+	"github.com/joho/godotenv"
 	"github.com/kballard/go-shellquote"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
@@ -34,6 +36,10 @@ type DirProvider struct {
 	TerraformBinary     string
 	TerraformCloudHost  string
 	TerraformCloudToken string
+	VarFiles            []string
+	Vars                map[string]string
+	EnvFiles            []string
+	terraformVersion    string
 }
 
 func NewDirProvider(ctx *config.ProjectContext) schema.Provider {
@@ -56,6 +62,9 @@ func NewDirProvider(ctx *config.ProjectContext) schema.Provider {
 		TerraformBinary:     terraformBinary,
 		TerraformCloudHost:  ctx.ProjectConfig.TerraformCloudHost,
 		TerraformCloudToken: ctx.ProjectConfig.TerraformCloudToken,
+		VarFiles:            ctx.ProjectConfig.TerraformVarFiles,
+		Vars:                ctx.ProjectConfig.TerraformVars,
+		EnvFiles:            ctx.ProjectConfig.TerraformEnvFiles,
 	}
 }
 
@@ -88,6 +97,7 @@ func (p *DirProvider) checks() error {
 	version := shortTerraformVersion(fullVersion)
 	p.ctx.SetContextValue("terraformFullVersion", fullVersion)
 	p.ctx.SetContextValue("terraformVersion", version)
+	p.terraformVersion = version
 
 	if v, ok := checkTerraformVersion(version, fullVersion); !ok {
 		return errors.Errorf("Terraform %s is not supported. Please use Terraform version >= %s.", v, minTerraformVer)
@@ -111,6 +121,87 @@ func (p *DirProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 	}
 
 	metadata.TerraformWorkspace = terraformWorkspace
+	metadata.TerraformVarFiles = p.VarFiles
+
+	varNames, err := p.varNames()
+	if err != nil {
+		log.Debugf("Could not read Terraform variable names for %s: %s", p.Path, err)
+	}
+	metadata.TerraformVarNames = varNames
+}
+
+// varNames returns the sorted names of the variables set by the inline variables and
+// environment files, so it can be checked that two runs used the same variables without
+// including the values which might be sensitive.
+func (p *DirProvider) varNames() ([]string, error) {
+	names := make([]string, 0, len(p.Vars))
+	for k := range p.Vars {
+		names = append(names, k)
+	}
+
+	envVars, err := p.loadEnvFiles()
+	for _, e := range envVars {
+		name := strings.TrimPrefix(strings.SplitN(e, "=", 2)[0], "TF_VAR_")
+		if _, ok := p.Vars[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, err
+}
+
+// loadEnvFiles returns the TF_VAR_ environment variables from the environment files.
+func (p *DirProvider) loadEnvFiles() ([]string, error) {
+	if len(p.EnvFiles) == 0 {
+		return nil, nil
+	}
+
+	vals, err := godotenv.Read(p.EnvFiles...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading Terraform environment file")
+	}
+
+	env := make([]string, 0, len(vals))
+	for k, v := range vals {
+		if !strings.HasPrefix(k, "TF_VAR_") {
+			log.Debugf("Ignoring %s from Terraform environment files since it is not a TF_VAR_ variable", k)
+			continue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(env)
+
+	return env, nil
+}
+
+// varArgs returns the -var-file and -var arguments for terraform plan
+func (p *DirProvider) varArgs() ([]string, error) {
+	args := make([]string, 0, len(p.VarFiles)+len(p.Vars))
+
+	for _, f := range p.VarFiles {
+		// Terraform runs in the project directory so relative paths need to be made absolute
+		absPath, err := filepath.Abs(f)
+		if err != nil {
+			return args, errors.Wrapf(err, "Error finding Terraform var file %s", f)
+		}
+
+		args = append(args, fmt.Sprintf("-var-file=%s", absPath))
+	}
+
+	names := make([]string, 0, len(p.Vars))
+	for k := range p.Vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		args = append(args, fmt.Sprintf("-var=%s=%s", k, p.Vars[k]))
+	}
+
+	return args, nil
 }
 
 func (p *DirProvider) LoadResources(project *schema.Project, usage map[string]*schema.UsageData) error {
@@ -200,6 +291,11 @@ func (p *DirProvider) buildCommandOpts() (*CmdOptions, error) {
 
 	opts.TerraformConfigFile = cfgFile
 
+	opts.Env, err = p.loadEnvFiles()
+	if err != nil {
+		return opts, err
+	}
+
 	return opts, nil
 }
 
@@ -218,7 +314,13 @@ func (p *DirProvider) runPlan(opts *CmdOptions, initOnFail bool) (string, []byte
 		return "", planJSON, errors.Wrap(err, "Error parsing terraform plan flags")
 	}
 
+	varArgs, err := p.varArgs()
+	if err != nil {
+		return "", planJSON, err
+	}
+
 	args := []string{"plan", "-input=false", "-lock=false", "-no-color"}
+	args = append(args, varArgs...)
 	args = append(args, flags...)
 	_, err = Cmd(opts, append(args, fmt.Sprintf("-out=%s", f.Name()))...)
 
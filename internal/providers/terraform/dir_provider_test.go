@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirProviderVars(t *testing.T) {
+	dir := t.TempDir()
+
+	envFile := filepath.Join(dir, "prod.env")
+	err := ioutil.WriteFile(envFile, []byte("TF_VAR_region=us-east-1\nTF_VAR_instance_type=m5.large\nAWS_PROFILE=prod\n"), 0600)
+	require.NoError(t, err)
+
+	varFile := filepath.Join(dir, "prod.tfvars")
+
+	p := &DirProvider{
+		VarFiles: []string{varFile},
+		Vars:     map[string]string{"name": "web", "instance_type": "t3.micro"},
+		EnvFiles: []string{envFile},
+	}
+
+	args, err := p.varArgs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"-var-file=" + varFile,
+		"-var=instance_type=t3.micro",
+		"-var=name=web",
+	}, args)
+
+	env, err := p.loadEnvFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TF_VAR_instance_type=m5.large", "TF_VAR_region=us-east-1"}, env)
+
+	names, err := p.varNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"instance_type", "name", "region"}, names)
+}
@@ -0,0 +1,201 @@
+package terraform
+
+import (
+	"os"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	awsusage "github.com/infracost/infracost/internal/usage/aws"
+	azureusage "github.com/infracost/infracost/internal/usage/azure"
+	datadogusage "github.com/infracost/infracost/internal/usage/datadog"
+	googleusage "github.com/infracost/infracost/internal/usage/google"
+	prometheususage "github.com/infracost/infracost/internal/usage/prometheus"
+	remoteusage "github.com/infracost/infracost/internal/usage/remote"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/tidwall/gjson"
+)
+
+// usageFetcher is a source of the actual usage of resources during a run, e.g. a cloud API
+// or an internal usage service
+type usageFetcher interface {
+	SupportsResourceType(resourceType string) bool
+	FetchUsage(d *schema.ResourceData) (map[string]interface{}, error)
+}
+
+type namedUsageFetcher struct {
+	name       string
+	provenance schema.UsageProvenance
+	fetcher    usageFetcher
+}
+
+// newUsageFetchers returns the usage fetchers that are enabled in the config, in order of
+// precedence.
+func newUsageFetchers(ctx *config.ProjectContext) []namedUsageFetcher {
+	fetchers := make([]namedUsageFetcher, 0)
+	if ctx.RunContext == nil {
+		return fetchers
+	}
+
+	cfg := ctx.RunContext.Config
+
+	// The queries are configured for specific resource types so they take precedence over
+	// the usage that's fetched for all the resources of a cloud
+	if p := cfg.PrometheusUsage; p != nil {
+		token := p.Token
+		if token == "" {
+			token = os.Getenv("INFRACOST_PROMETHEUS_TOKEN")
+		}
+
+		queries := make([]prometheususage.Query, 0, len(p.Queries))
+		for _, q := range p.Queries {
+			queries = append(queries, prometheususage.Query{ResourceType: q.ResourceType, UsageKey: q.UsageKey, Query: q.Query})
+		}
+
+		c, err := prometheususage.NewQueryUsage(p.URL, token, queries)
+		if err != nil {
+			log.Warnf("Not fetching usage from Prometheus: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Prometheus", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	if dd := cfg.DatadogUsage; dd != nil {
+		apiKey, appKey := dd.APIKey, dd.AppKey
+		if apiKey == "" {
+			apiKey = os.Getenv("DD_API_KEY")
+		}
+		if appKey == "" {
+			appKey = os.Getenv("DD_APP_KEY")
+		}
+
+		queries := make([]datadogusage.Query, 0, len(dd.Queries))
+		for _, q := range dd.Queries {
+			queries = append(queries, datadogusage.Query{ResourceType: q.ResourceType, UsageKey: q.UsageKey, Query: q.Query, Reducer: q.Reducer})
+		}
+
+		c, err := datadogusage.NewQueryUsage(dd.Site, apiKey, appKey, dd.Lookback, queries)
+		if err != nil {
+			log.Warnf("Not fetching usage from Datadog: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Datadog", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	if k := cfg.KubernetesUsage; k != nil {
+		token := k.Token
+		if token == "" {
+			token = os.Getenv("INFRACOST_KUBERNETES_USAGE_TOKEN")
+		}
+
+		c, err := prometheususage.NewNodePoolUsage(k.URL, token, k.Lookback, k.TargetUtilization)
+		if err != nil {
+			log.Warnf("Not fetching node pool usage from Kubernetes metrics: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Kubernetes metrics", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	if r := cfg.RemoteUsage; r != nil {
+		token := r.Token
+		if token == "" {
+			token = os.Getenv("INFRACOST_REMOTE_USAGE_TOKEN")
+		}
+
+		c := remoteusage.NewServiceUsage(r.URL, token, r.ResourceTypes)
+		fetchers = append(fetchers, namedUsageFetcher{"the usage service", schema.UsageProvenanceCloudMetric, c})
+	}
+
+	if cfg.FetchUsageFromCloudWatch {
+		c, err := awsusage.NewCloudWatchUsage(cfg.AWSCloudWatchEndpoint)
+		if err != nil {
+			log.Warnf("Not fetching usage from CloudWatch: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"CloudWatch", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	if cfg.FetchUsageFromCostExplorer {
+		c, err := awsusage.NewCostExplorerUsage(cfg.AWSCostExplorerEndpoint)
+		if err != nil {
+			log.Warnf("Not fetching usage from Cost Explorer: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Cost Explorer", schema.UsageProvenanceBillingData, c})
+		}
+	}
+
+	if cfg.FetchUsageFromCloudMonitoring {
+		c, err := googleusage.NewMonitoringUsage(cfg.GoogleMonitoringEndpoint)
+		if err != nil {
+			log.Warnf("Not fetching usage from Cloud Monitoring: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Cloud Monitoring", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	if cfg.FetchUsageFromAzureMonitor {
+		c, err := azureusage.NewMonitorUsage(cfg.AzureMonitorEndpoint)
+		if err != nil {
+			log.Warnf("Not fetching usage from Azure Monitor: %s", err)
+		} else {
+			fetchers = append(fetchers, namedUsageFetcher{"Azure Monitor", schema.UsageProvenanceCloudMetric, c})
+		}
+	}
+
+	return fetchers
+}
+
+// withFetchedUsage returns the usage data of the resource with the usage from the enabled
+// usage fetchers added. The usage keys that are set for the resource in the usage file are
+// kept, so they can be used to override the fetched usage, then the usage from the first
+// fetcher that has a key is used.
+func (p *Parser) withFetchedUsage(d *schema.ResourceData, u *schema.UsageData) *schema.UsageData {
+	for _, f := range p.usageFetchers {
+		if !f.fetcher.SupportsResourceType(d.Type) {
+			continue
+		}
+
+		fetched, err := f.fetcher.FetchUsage(d)
+		if err != nil {
+			log.Warnf("Could not fetch the usage of %s from %s: %s", d.Address, f.name, err)
+		}
+		if len(fetched) == 0 {
+			continue
+		}
+
+		u = mergeFetchedUsage(d, u, fetched, f.provenance)
+	}
+
+	return u
+}
+
+func mergeFetchedUsage(d *schema.ResourceData, u *schema.UsageData, fetched map[string]interface{}, provenance schema.UsageProvenance) *schema.UsageData {
+	address := d.Address
+	attributes := make(map[string]gjson.Result)
+	if u != nil {
+		address = u.Address
+		for k, v := range u.Attributes {
+			attributes[k] = v
+		}
+	}
+
+	fetchedKeys := make([]string, 0, len(fetched))
+	for k, v := range schema.ParseAttributes(fetched) {
+		if attributes[k].Type == gjson.Null {
+			attributes[k] = v
+			fetchedKeys = append(fetchedKeys, k)
+		}
+	}
+
+	merged := schema.NewUsageData(address, attributes)
+	if u != nil {
+		for k := range u.Attributes {
+			merged.CopyKey(u, k)
+		}
+	}
+	for _, k := range fetchedKeys {
+		merged.SetProvenance(k, provenance)
+	}
+
+	return merged
+}
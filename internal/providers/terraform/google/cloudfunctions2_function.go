@@ -0,0 +1,206 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// cloudFunctions2DefaultCPU maps the memory of 2nd gen functions in MiB to the vCPUs they
+// get when available_cpu isn't set.
+var cloudFunctions2DefaultCPU = map[int64]decimal.Decimal{
+	128:   decimal.RequireFromString("0.083"),
+	256:   decimal.RequireFromString("0.167"),
+	512:   decimal.RequireFromString("0.333"),
+	1024:  decimal.RequireFromString("0.583"),
+	2048:  decimal.NewFromInt(1),
+	4096:  decimal.NewFromInt(2),
+	8192:  decimal.NewFromInt(2),
+	16384: decimal.NewFromInt(4),
+	32768: decimal.NewFromInt(8),
+}
+
+// The monthly free tier of Cloud Run, shared by all the Cloud Run services and 2nd gen
+// functions in the billing account
+var (
+	cloudRunFreeCPU      = decimal.NewFromInt(180000)
+	cloudRunFreeMemory   = decimal.NewFromInt(360000)
+	cloudRunFreeRequests = decimal.NewFromInt(2000000)
+	cloudRunFreeEgress   = decimal.NewFromInt(1)
+)
+
+func GetCloudFunctions2RegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "google_cloudfunctions2_function",
+		RFunc: NewCloudFunctions2,
+		Notes: []string{
+			"2nd gen functions run on Cloud Run and are priced as Cloud Run services.",
+			"The free tier is shared by all the Cloud Run services and functions in the billing account so it's only applied if free_tier_applies is set in the usage file, otherwise all usage is priced using the paid tier.",
+			"Idle minimum instances are not supported.",
+		},
+	}
+}
+
+func NewCloudFunctions2(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("location").String()
+
+	memoryMiB := int64(256)
+	if d.Get("service_config.0.available_memory").Exists() {
+		if m, ok := parseCloudFunctions2Memory(d.Get("service_config.0.available_memory").String()); ok {
+			memoryMiB = m
+		} else {
+			log.Warnf("Unable to parse available_memory for %s, using 256M", d.Address)
+		}
+	}
+
+	cpu := cloudFunctions2CPU(memoryMiB)
+	if d.Get("service_config.0.available_cpu").Exists() {
+		if c, err := decimal.NewFromString(d.Get("service_config.0.available_cpu").String()); err == nil {
+			cpu = c
+		}
+	}
+
+	requestDuration := decimal.NewFromInt(100)
+	if u != nil && u.Get("request_duration_ms").Exists() {
+		// Round up to nearest 100ms
+		requestDuration = decimal.NewFromInt(u.Get("request_duration_ms").Int()).Div(decimal.NewFromInt(100)).Ceil().Mul(decimal.NewFromFloat(100))
+	}
+	seconds := requestDuration.Div(decimal.NewFromInt(1000))
+
+	var invocations, monthlyCPUUsage, monthlyMemoryUsage *decimal.Decimal
+	if u != nil && u.Get("monthly_function_invocations").Exists() {
+		invocations = decimalPtr(decimal.NewFromInt(u.Get("monthly_function_invocations").Int()))
+		monthlyCPUUsage = decimalPtr(invocations.Mul(cpu).Mul(seconds))
+		monthlyMemoryUsage = decimalPtr(invocations.Mul(decimal.NewFromInt(memoryMiB).Div(decimal.NewFromInt(1024))).Mul(seconds))
+	}
+
+	var networkEgress *decimal.Decimal
+	if u != nil && u.Get("monthly_outbound_data_gb").Exists() {
+		networkEgress = decimalPtr(decimal.NewFromInt(u.Get("monthly_outbound_data_gb").Int()))
+	}
+
+	if u != nil && u.Get("free_tier_applies").Bool() {
+		monthlyCPUUsage = cloudFunctionsBillableQuantity(monthlyCPUUsage, cloudRunFreeCPU)
+		monthlyMemoryUsage = cloudFunctionsBillableQuantity(monthlyMemoryUsage, cloudRunFreeMemory)
+		invocations = cloudFunctionsBillableQuantity(invocations, cloudRunFreeRequests)
+		networkEgress = cloudFunctionsBillableQuantity(networkEgress, cloudRunFreeEgress)
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			cloudRunCostComponent(region, "CPU", "vCPU-seconds", "CPU Allocation Time", cloudRunFreeCPU, monthlyCPUUsage),
+			cloudRunCostComponent(region, "Memory", "GiB-seconds", "Memory Allocation Time", cloudRunFreeMemory, monthlyMemoryUsage),
+			{
+				Name:            "Invocations",
+				Unit:            "1M invocations",
+				UnitMultiplier:  decimal.NewFromInt(1000000),
+				MonthlyQuantity: invocations,
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("gcp"),
+					Region:        strPtr("global"),
+					Service:       strPtr("Cloud Run"),
+					ProductFamily: strPtr("ApplicationServices"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "description", Value: strPtr("Requests")},
+					},
+				},
+				PriceFilter: &schema.PriceFilter{
+					StartUsageAmount: strPtr("2000000"), // use the non-free tier
+				},
+			},
+			{
+				Name:            "Outbound data transfer",
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: networkEgress,
+				ProductFilter: &schema.ProductFilter{
+					VendorName:    strPtr("gcp"),
+					Region:        strPtr(region),
+					Service:       strPtr("Cloud Run"),
+					ProductFamily: strPtr("ApplicationServices"),
+					AttributeFilters: []*schema.AttributeFilter{
+						{Key: "description", ValueRegex: strPtr("/^Network Internet Egress/")},
+					},
+				},
+				PriceFilter: &schema.PriceFilter{
+					StartUsageAmount: strPtr("1"), // use the non-free tier
+				},
+			},
+		},
+	}
+}
+
+// cloudRunCostComponent returns a Cloud Run cost component priced using the paid tier, which
+// starts after the free tier.
+func cloudRunCostComponent(region, name, unit, description string, freeTier decimal.Decimal, quantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:            name,
+		Unit:            unit,
+		UnitMultiplier:  decimal.NewFromInt(1),
+		MonthlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(region),
+			Service:       strPtr("Cloud Run"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: strPtr(fmt.Sprintf("/^%s/", description))},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr(freeTier.String()),
+		},
+	}
+}
+
+// cloudFunctions2CPU returns the default vCPUs of the memory size, or of the next larger
+// one if it's not one of the standard sizes.
+func cloudFunctions2CPU(memoryMiB int64) decimal.Decimal {
+	sizes := make([]int64, 0, len(cloudFunctions2DefaultCPU))
+	for size := range cloudFunctions2DefaultCPU {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+
+	for _, size := range sizes {
+		if memoryMiB <= size {
+			return cloudFunctions2DefaultCPU[size]
+		}
+	}
+
+	return cloudFunctions2DefaultCPU[sizes[len(sizes)-1]]
+}
+
+// parseCloudFunctions2Memory parses memory sizes like 256M or 1Gi into MiB. The pricing of the
+// memory sizes treats M and G the same as Mi and Gi.
+func parseCloudFunctions2Memory(memory string) (int64, bool) {
+	units := []struct {
+		suffix string
+		mib    decimal.Decimal
+	}{
+		{"Gi", decimal.NewFromInt(1024)},
+		{"Mi", decimal.NewFromInt(1)},
+		{"G", decimal.NewFromInt(1024)},
+		{"M", decimal.NewFromInt(1)},
+	}
+
+	for _, unit := range units {
+		if !strings.HasSuffix(memory, unit.suffix) {
+			continue
+		}
+
+		v, err := decimal.NewFromString(strings.TrimSuffix(memory, unit.suffix))
+		if err != nil {
+			return 0, false
+		}
+
+		return v.Mul(unit.mib).Round(0).IntPart(), true
+	}
+
+	return 0, false
+}
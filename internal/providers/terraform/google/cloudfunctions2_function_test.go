@@ -0,0 +1,49 @@
+package google_test
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/providers/terraform/google"
+	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestCloudFunctions2(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	tftest.GoldenFileResourceTests(t, "cloudfunctions2_function_test")
+}
+
+func TestCloudFunctions2DefaultCPU(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		memory      string
+		expectedCPU string
+	}{
+		{"256M", "0.0167"},
+		{"512Mi", "0.0333"},
+		{"768M", "0.0583"},
+		{"2G", "0.1"},
+	}
+
+	for _, test := range tests {
+		d := schema.NewResourceData("google_cloudfunctions2_function", "google", "google_cloudfunctions2_function.function", nil, gjson.Parse(`{
+			"location": "us-central1",
+			"service_config": [{"available_memory": "`+test.memory+`"}]
+		}`))
+
+		u := schema.NewUsageData("google_cloudfunctions2_function.function", schema.ParseAttributes(map[string]interface{}{
+			"monthly_function_invocations": 1,
+		}))
+
+		// 1 invocation of the default 100ms
+		r := google.NewCloudFunctions2(d, u)
+		assert.Equal(t, test.expectedCPU, r.CostComponents[0].MonthlyQuantity.String(), test.memory)
+	}
+}
@@ -5,10 +5,21 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// The monthly free tier of 1st gen functions, shared by all the functions in the billing account
+var (
+	cloudFunctionsFreeCPU         = decimal.NewFromInt(200000)
+	cloudFunctionsFreeMemory      = decimal.NewFromInt(400000)
+	cloudFunctionsFreeInvocations = decimal.NewFromInt(2000000)
+	cloudFunctionsFreeEgress      = decimal.NewFromInt(5)
+)
+
 func GetCloudFunctionsRegistryItem() *schema.RegistryItem {
 	return &schema.RegistryItem{
 		Name:  "google_cloudfunctions_function",
 		RFunc: NewCloudFunctions,
+		Notes: []string{
+			"The free tier is shared by all the functions in the billing account so it's only applied if free_tier_applies is set in the usage file, otherwise all usage is priced using the paid tier.",
+		},
 	}
 }
 
@@ -27,6 +38,7 @@ func NewCloudFunctions(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 		1024: decimal.NewFromInt(1400),
 		2048: decimal.NewFromInt(2400),
 		4096: decimal.NewFromInt(4800),
+		8192: decimal.NewFromInt(4800),
 	}
 
 	cpuSize := cpuMapping[int(memorySize.IntPart())]
@@ -49,6 +61,13 @@ func NewCloudFunctions(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 		networkEgrees = decimalPtr(decimal.NewFromInt(u.Get("monthly_outbound_data_gb").Int()))
 	}
 
+	if u != nil && u.Get("free_tier_applies").Bool() {
+		monthlyCPUUsage = cloudFunctionsBillableQuantity(monthlyCPUUsage, cloudFunctionsFreeCPU)
+		monthlyMemoryUsage = cloudFunctionsBillableQuantity(monthlyMemoryUsage, cloudFunctionsFreeMemory)
+		invocations = cloudFunctionsBillableQuantity(invocations, cloudFunctionsFreeInvocations)
+		networkEgrees = cloudFunctionsBillableQuantity(networkEgrees, cloudFunctionsFreeEgress)
+	}
+
 	return &schema.Resource{
 		Name: d.Address,
 		CostComponents: []*schema.CostComponent{
@@ -66,6 +85,9 @@ func NewCloudFunctions(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 						{Key: "description", Value: strPtr("CPU Time")},
 					},
 				},
+				PriceFilter: &schema.PriceFilter{
+					StartUsageAmount: strPtr("200000"), // use the non-free tier
+				},
 			},
 			{
 				Name:            "Memory",
@@ -81,6 +103,9 @@ func NewCloudFunctions(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 						{Key: "description", Value: strPtr("Memory Time")},
 					},
 				},
+				PriceFilter: &schema.PriceFilter{
+					StartUsageAmount: strPtr("400000"), // use the non-free tier
+				},
 			},
 			{
 				Name:            "Invocations",
@@ -122,6 +147,21 @@ func NewCloudFunctions(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 	}
 }
 
+// cloudFunctionsBillableQuantity returns the quantity after the free tier, or nil if the
+// quantity isn't known.
+func cloudFunctionsBillableQuantity(quantity *decimal.Decimal, free decimal.Decimal) *decimal.Decimal {
+	if quantity == nil {
+		return nil
+	}
+
+	billable := quantity.Sub(free)
+	if billable.IsNegative() {
+		billable = decimal.Zero
+	}
+
+	return decimalPtr(billable)
+}
+
 func calculateGBSeconds(memorySize decimal.Decimal, averageRequestDuration decimal.Decimal, monthlyRequests decimal.Decimal) decimal.Decimal {
 	gb := memorySize.Div(decimal.NewFromInt(1024))
 	seconds := averageRequestDuration.Div(decimal.NewFromInt(1000))
@@ -2,9 +2,11 @@ package google
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 
 	"github.com/shopspring/decimal"
@@ -15,10 +17,11 @@ func GetComputeInstanceRegistryItem() *schema.RegistryItem {
 		Name:  "google_compute_instance",
 		RFunc: NewComputeInstance,
 		Notes: []string{
-			"Sustained use discounts are applied to monthly costs, but not to hourly costs.",
+			"Sustained use discounts are applied to monthly costs, but not to hourly costs. They are based on the monthly_hrs usage, or the whole month if it isn't set.",
 			"Costs associated with non-standard Linux images, such as Windows and RHEL are not supported.",
 			"Custom machine types are not supported.",
 			"Sole-tenant VMs are not supported.",
+			"Spot VMs are priced using preemptible VM prices.",
 		},
 	}
 }
@@ -37,11 +40,29 @@ func NewComputeInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Res
 	}
 
 	purchaseOption := "on_demand"
-	if d.Get("scheduling.0.preemptible").Bool() {
+	if strings.ToUpper(d.Get("scheduling.0.provisioning_model").String()) == "SPOT" {
+		purchaseOption = "spot"
+	} else if d.Get("scheduling.0.preemptible").Bool() {
 		purchaseOption = "preemptible"
 	}
 
-	costComponents := []*schema.CostComponent{computeCostComponent(region, machineType, purchaseOption)}
+	// Allow the purchase option to be specified in the usage data, e.g. to see the cost of
+	// running the instance as a spot VM
+	if u != nil && u.Get("purchase_option").Exists() {
+		switch o := strings.ToLower(u.Get("purchase_option").String()); o {
+		case "on_demand", "preemptible", "spot":
+			purchaseOption = o
+		default:
+			log.Warnf("Invalid purchase_option for %s, ignoring. Expected: on_demand, preemptible, spot. Got: %s", d.Address, o)
+		}
+	}
+
+	var monthlyHours *decimal.Decimal
+	if u != nil && u.Get("monthly_hrs").Exists() {
+		monthlyHours = decimalPtr(decimal.NewFromFloat(u.Get("monthly_hrs").Float()))
+	}
+
+	costComponents := []*schema.CostComponent{computeCostComponent(region, machineType, purchaseOption, monthlyHours)}
 
 	if d.Get("boot_disk.0.initialize_params.0").Exists() {
 		costComponents = append(costComponents, bootDisk(region, d.Get("boot_disk.0.initialize_params.0")))
@@ -62,18 +83,20 @@ func NewComputeInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Res
 	}
 }
 
-func computeCostComponent(region, machineType string, purchaseOption string) *schema.CostComponent {
+// computeCostComponent returns the cost component for the machine type. If monthlyHours is
+// nil the instance is assumed to run for the whole month.
+func computeCostComponent(region, machineType string, purchaseOption string, monthlyHours *decimal.Decimal) *schema.CostComponent {
+	hours := schema.HourToMonthUnitMultiplier
+	if monthlyHours != nil {
+		hours = *monthlyHours
+	}
+
 	sustainedUseDiscount := 0.0
 	if strings.ToLower(purchaseOption) == "on_demand" {
-		switch strings.ToLower(strings.Split(machineType, "-")[0]) {
-		case "c2", "n2", "n2d":
-			sustainedUseDiscount = 0.2
-		case "n1", "f1", "g1", "m1":
-			sustainedUseDiscount = 0.3
-		}
+		sustainedUseDiscount = sustainedUseDiscountPerc(machineType, hours)
 	}
 
-	return &schema.CostComponent{
+	c := &schema.CostComponent{
 		Name:                fmt.Sprintf("Instance usage (Linux/UNIX, %s, %s)", purchaseOptionLabel(purchaseOption), machineType),
 		Unit:                "hours",
 		UnitMultiplier:      decimal.NewFromInt(1),
@@ -89,9 +112,52 @@ func computeCostComponent(region, machineType string, purchaseOption string) *sc
 			},
 		},
 		PriceFilter: &schema.PriceFilter{
-			PurchaseOption: strPtr(purchaseOption),
+			PurchaseOption: strPtr(pricePurchaseOption(purchaseOption)),
 		},
+		PurchaseOption: purchaseOption,
+	}
+
+	if monthlyHours != nil {
+		c.HourlyQuantity = nil
+		c.MonthlyQuantity = monthlyHours
 	}
+
+	return c
+}
+
+// sustainedUseDiscountRates are the rates of the base price that each quarter of the month
+// an instance runs for is charged at, by machine family.
+var sustainedUseDiscountRates = map[string][]float64{
+	"n1":  {1, 0.8, 0.6, 0.4},
+	"f1":  {1, 0.8, 0.6, 0.4},
+	"g1":  {1, 0.8, 0.6, 0.4},
+	"m1":  {1, 0.8, 0.6, 0.4},
+	"c2":  {1, 13.0 / 15, 11.0 / 15, 0.6},
+	"n2":  {1, 13.0 / 15, 11.0 / 15, 0.6},
+	"n2d": {1, 13.0 / 15, 11.0 / 15, 0.6},
+}
+
+// sustainedUseDiscountPerc returns the sustained use discount for the machine type running
+// for monthlyHours, e.g. 0.3 for an n1 instance running for the whole month. Machine
+// families that aren't eligible, e.g. e2, have no discount.
+func sustainedUseDiscountPerc(machineType string, monthlyHours decimal.Decimal) float64 {
+	rates, ok := sustainedUseDiscountRates[strings.ToLower(strings.Split(machineType, "-")[0])]
+	if !ok || !monthlyHours.IsPositive() {
+		return 0
+	}
+
+	hours, _ := monthlyHours.Float64()
+	month, _ := schema.HourToMonthUnitMultiplier.Float64()
+	hours = math.Min(hours, month)
+	quarter := month / float64(len(rates))
+
+	charged := 0.0
+	for i, rate := range rates {
+		tierHours := math.Min(math.Max(hours-float64(i)*quarter, 0), quarter)
+		charged += tierHours * rate
+	}
+
+	return math.Round((1-charged/hours)*10000) / 10000
 }
 
 func bootDisk(region string, initializeParams gjson.Result) *schema.CostComponent {
@@ -107,7 +173,7 @@ func bootDisk(region string, initializeParams gjson.Result) *schema.CostComponen
 
 func scratchDisk(region string, purchaseOption string, count int) *schema.CostComponent {
 	descRegex := "/^SSD backed Local Storage( in .*)?$/"
-	if strings.ToLower(purchaseOption) == "preemptible" {
+	if pricePurchaseOption(purchaseOption) == "preemptible" {
 		descRegex = "/^SSD backed Local Storage attached to Preemptible VMs/"
 	}
 
@@ -157,7 +223,7 @@ func guestAccelerator(region string, purchaseOption string, guestAccel gjson.Res
 	}
 
 	descRegex := fmt.Sprintf("/^%s running/", descPrefix)
-	if strings.ToLower(purchaseOption) == "preemptible" {
+	if pricePurchaseOption(purchaseOption) == "preemptible" {
 		descRegex = fmt.Sprintf("/^%s attached to preemptible VMs running/", descPrefix)
 	}
 
@@ -193,5 +259,16 @@ func purchaseOptionLabel(purchaseOption string) string {
 	return map[string]string{
 		"on_demand":   "on-demand",
 		"preemptible": "preemptible",
+		"spot":        "spot",
 	}[purchaseOption]
 }
+
+// pricePurchaseOption returns the purchase option of the prices. Spot VMs use the same
+// prices as preemptible VMs.
+func pricePurchaseOption(purchaseOption string) string {
+	if purchaseOption == "spot" {
+		return "preemptible"
+	}
+
+	return purchaseOption
+}
@@ -1,9 +1,14 @@
 package google_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/google"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestComputeInstance(t *testing.T) {
@@ -14,3 +19,72 @@ func TestComputeInstance(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "compute_instance_test")
 }
+
+func TestComputeInstanceSpot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("google_compute_instance", "google", "google_compute_instance.spot", nil, gjson.Parse(`{
+		"region": "us-central1",
+		"machine_type": "n1-standard-1",
+		"scheduling": [{"provisioning_model": "SPOT", "preemptible": true}],
+		"scratch_disk": [{"interface": "NVME"}]
+	}`))
+
+	r := google.NewComputeInstance(d, nil)
+
+	c := r.CostComponents[0]
+	assert.Equal(t, "Instance usage (Linux/UNIX, spot, n1-standard-1)", c.Name)
+	assert.Equal(t, "spot", c.PurchaseOption)
+	assert.Equal(t, "preemptible", *c.PriceFilter.PurchaseOption)
+	assert.Equal(t, 0.0, c.MonthlyDiscountPerc)
+
+	assert.Equal(t, "/^SSD backed Local Storage attached to Preemptible VMs/", *r.CostComponents[1].ProductFilter.AttributeFilters[0].ValueRegex)
+
+	u := schema.NewUsageData("google_compute_instance.spot", schema.ParseAttributes(map[string]interface{}{
+		"purchase_option": "on_demand",
+	}))
+
+	r = google.NewComputeInstance(d, u)
+	assert.Equal(t, "on_demand", r.CostComponents[0].PurchaseOption)
+	assert.Equal(t, 0.3, r.CostComponents[0].MonthlyDiscountPerc)
+}
+
+func TestComputeInstanceSustainedUseDiscount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		machineType  string
+		monthlyHours interface{}
+		discount     float64
+	}{
+		{"n1-standard-1", nil, 0.3},
+		{"n1-standard-1", 730, 0.3},
+		{"n1-standard-1", 182.5, 0},
+		{"n1-standard-1", 365, 0.1},
+		{"n1-standard-1", 547.5, 0.2},
+		{"n2-standard-2", nil, 0.2},
+		{"n2d-standard-2", 365, 0.0667},
+		{"e2-medium", nil, 0},
+	}
+
+	for _, tt := range tests {
+		attrs := map[string]interface{}{}
+		if tt.monthlyHours != nil {
+			attrs["monthly_hrs"] = tt.monthlyHours
+		}
+
+		d := schema.NewResourceData("google_compute_instance", "google", "google_compute_instance.vm", nil, gjson.Parse(`{
+			"region": "us-central1",
+			"machine_type": "`+tt.machineType+`"
+		}`))
+		u := schema.NewUsageData("google_compute_instance.vm", schema.ParseAttributes(attrs))
+
+		c := google.NewComputeInstance(d, u).CostComponents[0]
+		assert.Equal(t, tt.discount, c.MonthlyDiscountPerc, "%s %v", tt.machineType, tt.monthlyHours)
+
+		if tt.monthlyHours != nil {
+			assert.Nil(t, c.HourlyQuantity)
+			assert.Equal(t, fmt.Sprintf("%v", tt.monthlyHours), c.MonthlyQuantity.String())
+		}
+	}
+}
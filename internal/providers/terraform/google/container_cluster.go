@@ -18,6 +18,7 @@ func GetContainerClusterRegistryItem() *schema.RegistryItem {
 			"Sustained use discounts are applied to monthly costs, but not to hourly costs.",
 			"Costs associated with non-standard Linux images, such as Windows and RHEL are not supported.",
 			"Custom machine types are not supported.",
+			"The free tier, which covers the cluster management fee of one zonal or Autopilot cluster per billing account, is not applied.",
 		},
 	}
 }
@@ -30,6 +31,17 @@ func NewContainerCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Re
 		description = "Zonal Kubernetes Clusters"
 	}
 
+	// Autopilot clusters are always regional, and are charged for the resources requested by their pods rather than their nodes
+	if d.Get("enable_autopilot").Bool() {
+		costComponents := []*schema.CostComponent{clusterManagementFeeCostComponent(description)}
+		costComponents = append(costComponents, autopilotCostComponents(region, u)...)
+
+		return &schema.Resource{
+			Name:           d.Address,
+			CostComponents: costComponents,
+		}
+	}
+
 	subResources := make([]*schema.Resource, 0)
 
 	if !d.Get("remove_default_node_pool").Bool() {
@@ -71,32 +83,72 @@ func NewContainerCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Re
 		}
 	}
 
-	costComponents := []*schema.CostComponent{
-		{
-			Name:           "Cluster management fee",
-			Unit:           "hours",
-			UnitMultiplier: decimal.NewFromInt(1),
-			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
-			ProductFilter: &schema.ProductFilter{
-				VendorName:    strPtr("gcp"),
-				Region:        strPtr("global"),
-				Service:       strPtr("Kubernetes Engine"),
-				ProductFamily: strPtr("Compute"),
-				AttributeFilters: []*schema.AttributeFilter{
-					{Key: "description", Value: strPtr(description)},
-				},
-			},
-			PriceFilter: &schema.PriceFilter{
-				StartUsageAmount: strPtr("0"),
-				EndUsageAmount:   strPtr(""),
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: []*schema.CostComponent{clusterManagementFeeCostComponent(description)},
+		SubResources:   subResources,
+	}
+}
+
+func clusterManagementFeeCostComponent(description string) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:           "Cluster management fee",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr("global"),
+			Service:       strPtr("Kubernetes Engine"),
+			ProductFamily: strPtr("Compute"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", Value: strPtr(description)},
 			},
 		},
+		PriceFilter: &schema.PriceFilter{
+			StartUsageAmount: strPtr("0"),
+			EndUsageAmount:   strPtr(""),
+		},
+	}
+}
+
+func autopilotCostComponents(region string, u *schema.UsageData) []*schema.CostComponent {
+	var vcpu, memoryGB, ephemeralStorageGB *decimal.Decimal
+	if u != nil && u.Get("autopilot_vcpu_count").Exists() {
+		vcpu = decimalPtr(decimal.NewFromFloat(u.Get("autopilot_vcpu_count").Float()))
+	}
+	if u != nil && u.Get("autopilot_memory_gb").Exists() {
+		memoryGB = decimalPtr(decimal.NewFromFloat(u.Get("autopilot_memory_gb").Float()))
+	}
+	if u != nil && u.Get("autopilot_ephemeral_storage_gb").Exists() {
+		ephemeralStorageGB = decimalPtr(decimal.NewFromFloat(u.Get("autopilot_ephemeral_storage_gb").Float()))
 	}
 
-	return &schema.Resource{
-		Name:           d.Address,
-		CostComponents: costComponents,
-		SubResources:   subResources,
+	return []*schema.CostComponent{
+		autopilotCostComponent(region, "Autopilot vCPU", "CPU", "mCPU", vcpu),
+		autopilotCostComponent(region, "Autopilot memory", "GB", "Memory", memoryGB),
+		autopilotCostComponent(region, "Autopilot ephemeral storage", "GB", "Ephemeral Storage", ephemeralStorageGB),
+	}
+}
+
+func autopilotCostComponent(region, name, unit, resourceType string, quantity *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:           name,
+		Unit:           unit,
+		UnitMultiplier: schema.HourToMonthUnitMultiplier,
+		HourlyQuantity: quantity,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(region),
+			Service:       strPtr("Kubernetes Engine"),
+			ProductFamily: strPtr("Compute"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: strPtr(fmt.Sprintf("/^Autopilot Pod %s Requests/", resourceType))},
+			},
+		},
+		PriceFilter: &schema.PriceFilter{
+			PurchaseOption: strPtr("OnDemand"),
+		},
 	}
 }
 
@@ -3,7 +3,11 @@ package google_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/google"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestContainerClusterGoldenFile(t *testing.T) {
@@ -14,3 +18,27 @@ func TestContainerClusterGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "container_cluster_test")
 }
+
+func TestContainerClusterAutopilot(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("google_container_cluster", "google", "google_container_cluster.autopilot", nil, gjson.Parse(`{
+		"location": "us-central1",
+		"enable_autopilot": true
+	}`))
+
+	u := schema.NewUsageData("google_container_cluster.autopilot", schema.ParseAttributes(map[string]interface{}{
+		"autopilot_vcpu_count":           2.5,
+		"autopilot_memory_gb":            10,
+		"autopilot_ephemeral_storage_gb": 20,
+	}))
+
+	r := google.NewContainerCluster(d, u)
+	assert.Empty(t, r.SubResources)
+	assert.Len(t, r.CostComponents, 4)
+	assert.Equal(t, "Cluster management fee", r.CostComponents[0].Name)
+	assert.Equal(t, "Autopilot vCPU", r.CostComponents[1].Name)
+	assert.Equal(t, "2.5", r.CostComponents[1].HourlyQuantity.String())
+	assert.Equal(t, "10", r.CostComponents[2].HourlyQuantity.String())
+	assert.Equal(t, "20", r.CostComponents[3].HourlyQuantity.String())
+}
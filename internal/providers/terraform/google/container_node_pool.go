@@ -88,6 +88,11 @@ func newNodePool(address string, d gjson.Result, countPerZoneOverride *int64, cl
 
 	nodeCount := decimal.NewFromInt(zones * countPerZone)
 
+	// The total limits apply to the node pool as a whole rather than to each zone
+	if countPerZoneOverride == nil && d.Get("autoscaling.0.total_min_node_count").Type != gjson.Null {
+		nodeCount = decimal.NewFromInt(d.Get("autoscaling.0.total_min_node_count").Int())
+	}
+
 	r := &schema.Resource{
 		Name:           address,
 		CostComponents: nodePoolCostComponents(region, d.Get("node_config.0")),
@@ -105,7 +110,9 @@ func nodePoolCostComponents(region string, nodeConfig gjson.Result) []*schema.Co
 	}
 
 	purchaseOption := "on_demand"
-	if nodeConfig.Get("preemptible").Bool() {
+	if nodeConfig.Get("spot").Bool() {
+		purchaseOption = "spot"
+	} else if nodeConfig.Get("preemptible").Bool() {
 		purchaseOption = "preemptible"
 	}
 
@@ -120,7 +127,7 @@ func nodePoolCostComponents(region string, nodeConfig gjson.Result) []*schema.Co
 	}
 
 	costComponents := []*schema.CostComponent{
-		computeCostComponent(region, machineType, purchaseOption),
+		computeCostComponent(region, machineType, purchaseOption, nil),
 		computeDisk(region, diskType, &diskSize),
 	}
 
@@ -3,7 +3,11 @@ package google_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/google"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestContainerNodePoolGoldenFile(t *testing.T) {
@@ -14,3 +18,22 @@ func TestContainerNodePoolGoldenFile(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "container_node_pool_test")
 }
+
+func TestContainerNodePoolTotalMinNodeCount(t *testing.T) {
+	t.Parallel()
+
+	d := schema.NewResourceData("google_container_node_pool", "google", "google_container_node_pool.pool", nil, gjson.Parse(`{
+		"location": "us-central1",
+		"autoscaling": [{"total_min_node_count": 4, "total_max_node_count": 10}]
+	}`))
+
+	r := google.NewContainerNodePool(d, nil)
+	assert.Equal(t, "4", r.CostComponents[0].HourlyQuantity.String())
+
+	u := schema.NewUsageData("google_container_node_pool.pool", schema.ParseAttributes(map[string]interface{}{
+		"nodes": 2,
+	}))
+
+	r = google.NewContainerNodePool(d, u)
+	assert.Equal(t, "6", r.CostComponents[0].HourlyQuantity.String())
+}
@@ -22,7 +22,7 @@ func GetSQLInstanceRegistryItem() *schema.RegistryItem {
 		Name:  "google_sql_database_instance",
 		RFunc: NewSQLInstance,
 		Notes: []string{
-			"Cloud SQL network, SQL Server license, 1-3 years commitments costs are not yet supported.",
+			"Cloud SQL network, 1-3 years commitments costs are not yet supported.",
 		},
 	}
 }
@@ -78,6 +78,12 @@ func sqlDatabaseInstanceCostComponents(d *schema.ResourceData, u *schema.UsageDa
 		memory := decimalPtr(decimal.NewFromInt32(int32(ram)).Div(decimal.NewFromInt(1024)))
 
 		costComponents = append(costComponents, memoryCostComponent(region, tier, availabilityType, dbType, memory))
+
+		if dbType == SQLServer {
+			if edition := sqlServerEdition(dbVersion); edition != "" {
+				costComponents = append(costComponents, sqlServerLicenseCostComponent(region, edition, vCPU))
+			}
+		}
 	} else if strings.Contains(tier, "db-n1-") && dbType == MySQL {
 		costComponents = append(costComponents, sharedSQLInstance(tier, availabilityType, dbType, region))
 	}
@@ -146,6 +152,40 @@ func cpuCostComponent(region string, tier string, availabilityType string, dbTyp
 	}
 }
 
+// sqlServerLicenseCostComponent returns the license cost component of SQL Server instances,
+// which is charged per vCPU for all the editions apart from Express.
+func sqlServerLicenseCostComponent(region string, edition string, vCPU *decimal.Decimal) *schema.CostComponent {
+	return &schema.CostComponent{
+		Name:           fmt.Sprintf("SQL Server license (%s)", strings.ToLower(edition)),
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: vCPU,
+		ProductFilter: &schema.ProductFilter{
+			VendorName:    strPtr("gcp"),
+			Region:        strPtr(region),
+			Service:       strPtr("Cloud SQL"),
+			ProductFamily: strPtr("ApplicationServices"),
+			AttributeFilters: []*schema.AttributeFilter{
+				{Key: "description", ValueRegex: strPtr(fmt.Sprintf("/SQL Server %s License/i", edition))},
+			},
+		},
+	}
+}
+
+// sqlServerEdition returns the edition of SQL Server database versions, e.g. Standard for
+// SQLSERVER_2017_STANDARD, or an empty string for the Express edition which is free.
+func sqlServerEdition(dbVersion string) string {
+	editions := map[string]string{
+		"STANDARD":   "Standard",
+		"ENTERPRISE": "Enterprise",
+		"WEB":        "Web",
+	}
+
+	parts := strings.Split(dbVersion, "_")
+
+	return editions[parts[len(parts)-1]]
+}
+
 func sharedSQLInstance(tier, availabilityType string, dbType SQLInstanceDBType, region string) *schema.CostComponent {
 	resourceGroup := sqlInstanceTierToResourceGroup(tier)
 	descriptionRegex := "/" + sqlInstanceAvDBTypeToDescription(availabilityType, dbType)
@@ -3,7 +3,11 @@ package google_test
 import (
 	"testing"
 
+	"github.com/infracost/infracost/internal/providers/terraform/google"
 	"github.com/infracost/infracost/internal/providers/terraform/tftest"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 func TestNewSQLInstance(t *testing.T) {
@@ -14,3 +18,41 @@ func TestNewSQLInstance(t *testing.T) {
 
 	tftest.GoldenFileResourceTests(t, "sql_database_instance_test")
 }
+
+func TestSQLInstanceSQLServerLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		databaseVersion string
+		expectedName    string
+	}{
+		{"SQLSERVER_2019_STANDARD", "SQL Server license (standard)"},
+		{"SQLSERVER_2019_WEB", "SQL Server license (web)"},
+		{"SQLSERVER_2019_EXPRESS", ""},
+	}
+
+	for _, test := range tests {
+		d := schema.NewResourceData("google_sql_database_instance", "google", "google_sql_database_instance.sql_server", nil, gjson.Parse(`{
+			"region": "us-central1",
+			"database_version": "`+test.databaseVersion+`",
+			"settings": [{"tier": "db-custom-4-15360"}]
+		}`))
+
+		r := google.NewSQLInstance(d, nil)
+
+		var license *schema.CostComponent
+		for _, c := range r.CostComponents {
+			if c.Name == test.expectedName {
+				license = c
+			}
+		}
+
+		if test.expectedName == "" {
+			assert.Len(t, r.CostComponents, 4, test.databaseVersion)
+			continue
+		}
+
+		assert.NotNil(t, license, test.databaseVersion)
+		assert.Equal(t, "4", license.HourlyQuantity.String())
+	}
+}
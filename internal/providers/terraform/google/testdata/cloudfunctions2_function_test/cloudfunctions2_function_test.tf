@@ -0,0 +1,32 @@
+provider "google" {
+  credentials = "{\"type\":\"service_account\"}"
+  region      = "us-central1"
+}
+
+resource "google_cloudfunctions2_function" "function" {
+  name        = "function-test"
+  location    = "us-central1"
+  description = "My function"
+}
+
+resource "google_cloudfunctions2_function" "my_function" {
+  name        = "function-test"
+  location    = "us-central1"
+  description = "My function"
+
+  service_config {
+    available_memory = "1Gi"
+    available_cpu    = "1"
+  }
+}
+
+resource "google_cloudfunctions2_function" "with_free_tier" {
+  name        = "function-test"
+  location    = "us-central1"
+  description = "My function"
+
+  service_config {
+    available_memory = "1Gi"
+    available_cpu    = "1"
+  }
+}
@@ -14,4 +14,11 @@ resource "google_cloudfunctions_function" "my_function" {
   description         = "My function"
   runtime             = "nodejs10"
   available_memory_mb = 256
-}
\ No newline at end of file
+}
+
+resource "google_cloudfunctions_function" "with_free_tier" {
+  name                = "function-test"
+  description         = "My function"
+  runtime             = "nodejs10"
+  available_memory_mb = 256
+}
@@ -0,0 +1,60 @@
+package ibm
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetContainerVpcClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "ibm_container_vpc_cluster",
+		RFunc: NewContainerVpcCluster,
+		Notes: []string{
+			"Red Hat OpenShift license costs are not included.",
+		},
+	}
+}
+
+func GetContainerVpcWorkerPoolRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "ibm_container_vpc_worker_pool",
+		RFunc: NewContainerVpcCluster,
+		Notes: []string{
+			"Red Hat OpenShift license costs are not included.",
+		},
+	}
+}
+
+// NewContainerVpcCluster prices the worker nodes of the default worker pool of a cluster or
+// of an additional worker pool. The pool has worker_count workers in each of its zones.
+func NewContainerVpcCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	flavor := d.Get("flavor").String()
+
+	workersPerZone := d.Get("worker_count").Int()
+	if workersPerZone == 0 {
+		workersPerZone = 1
+	}
+
+	zones := int64(len(d.Get("zones").Array()))
+	if zones == 0 {
+		zones = 1
+	}
+
+	workers := decimal.NewFromInt(workersPerZone * zones)
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("Worker nodes (%s)", flavor),
+				Unit:           "hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(workers),
+				ProductFilter:  productFilter(region, "containers-kubernetes", flavor, "INSTANCE_HOURS"),
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package ibm
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewContainerVpcCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  string
+		workers string
+	}{
+		{
+			name:    "multizone",
+			values:  `{"region": "us-south", "flavor": "bx2.4x16", "worker_count": 2, "zones": [{"name": "us-south-1"}, {"name": "us-south-2"}, {"name": "us-south-3"}]}`,
+			workers: "6",
+		},
+		{
+			name:    "default worker count",
+			values:  `{"region": "us-south", "flavor": "bx2.4x16", "zones": [{"name": "us-south-1"}, {"name": "us-south-2"}]}`,
+			workers: "2",
+		},
+		{
+			name:    "without zones",
+			values:  `{"region": "us-south", "flavor": "bx2.4x16", "worker_count": 3}`,
+			workers: "3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("ibm_container_vpc_cluster", "ibm", "ibm_container_vpc_cluster.cluster", nil, gjson.Parse(tt.values))
+			r := NewContainerVpcCluster(d, nil)
+
+			assert.Len(t, r.CostComponents, 1)
+			assert.Equal(t, "Worker nodes (bx2.4x16)", r.CostComponents[0].Name)
+			assert.Equal(t, tt.workers, r.CostComponents[0].HourlyQuantity.String())
+
+			f := r.CostComponents[0].ProductFilter
+			assert.Equal(t, "containers-kubernetes", *f.Service)
+			assert.Equal(t, "bx2.4x16", *f.ProductFamily)
+			assert.Equal(t, "INSTANCE_HOURS", *f.AttributeFilters[0].Value)
+		})
+	}
+}
+
+func TestContainerVpcWorkerPoolRegistryItem(t *testing.T) {
+	d := schema.NewResourceData("ibm_container_vpc_worker_pool", "ibm", "ibm_container_vpc_worker_pool.pool", nil, gjson.Parse(`{"region": "eu-de", "flavor": "cx2.8x16", "worker_count": 1, "zones": [{"name": "eu-de-1"}]}`))
+	r := GetContainerVpcWorkerPoolRegistryItem().RFunc(d, nil)
+
+	assert.Len(t, r.CostComponents, 1)
+	assert.Equal(t, "1", r.CostComponents[0].HourlyQuantity.String())
+}
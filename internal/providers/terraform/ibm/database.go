@@ -0,0 +1,73 @@
+package ibm
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	defaultDatabaseMemoryMB = 2048
+	defaultDatabaseDiskMB   = 10240
+)
+
+func GetDatabaseRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "ibm_database",
+		RFunc: NewDatabase,
+	}
+}
+
+// NewDatabase prices an IBM Cloud Databases deployment, e.g. databases-for-postgresql, for
+// the memory, disk and dedicated cores allocated to all of its members.
+func NewDatabase(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	service := d.Get("service").String()
+
+	plan := d.Get("plan").String()
+	if plan == "" {
+		plan = "standard"
+	}
+
+	memoryMB := int64(defaultDatabaseMemoryMB)
+	if d.Get("members_memory_allocation_mb").Exists() {
+		memoryMB = d.Get("members_memory_allocation_mb").Int()
+	}
+
+	diskMB := int64(defaultDatabaseDiskMB)
+	if d.Get("members_disk_allocation_mb").Exists() {
+		diskMB = d.Get("members_disk_allocation_mb").Int()
+	}
+
+	costComponents := []*schema.CostComponent{
+		{
+			Name:            "Memory",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(memoryMB).Div(decimal.NewFromInt(1024))),
+			ProductFilter:   productFilter(region, service, plan, "GIGABYTE_MONTHS_RAM"),
+		},
+		{
+			Name:            "Disk",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(diskMB).Div(decimal.NewFromInt(1024))),
+			ProductFilter:   productFilter(region, service, plan, "GIGABYTE_MONTHS_DISK"),
+		},
+	}
+
+	// Deployments use shared cores unless dedicated cores are allocated
+	if cores := d.Get("members_cpu_allocation_count").Int(); cores > 0 {
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:            "Dedicated cores",
+			Unit:            "cores",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(cores)),
+			ProductFilter:   productFilter(region, service, plan, "VIRTUAL_PROCESSOR_CORES"),
+		})
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
@@ -0,0 +1,30 @@
+package ibm
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewDatabase(t *testing.T) {
+	d := schema.NewResourceData("ibm_database", "ibm", "ibm_database.db", nil, gjson.Parse(`{
+		"region": "eu-gb",
+		"service": "databases-for-postgresql",
+		"plan": "standard",
+		"members_memory_allocation_mb": 4096,
+		"members_cpu_allocation_count": 6
+	}`))
+
+	r := NewDatabase(d, nil)
+
+	components := make(map[string]string)
+	for _, c := range r.CostComponents {
+		components[c.Name] = c.MonthlyQuantity.String()
+		assert.Equal(t, "databases-for-postgresql", *c.ProductFilter.Service)
+		assert.Equal(t, "standard", *c.ProductFilter.ProductFamily)
+	}
+
+	assert.Equal(t, map[string]string{"Memory": "4", "Disk": "10", "Dedicated cores": "6"}, components)
+}
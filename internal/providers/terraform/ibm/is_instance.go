@@ -0,0 +1,60 @@
+package ibm
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// VPC instance profiles and IKS worker flavors are named after their vCPUs and memory, e.g.
+// bx2-2x8 or bx2.4x16
+var profileRegex = regexp.MustCompile(`^[a-z0-9]+[-.](\d+)x(\d+)`)
+
+func GetISInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "ibm_is_instance",
+		RFunc: NewISInstance,
+	}
+}
+
+func NewISInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: vpcInstanceCostComponents(d.Address, region, d.Get("profile").String(), decimal.NewFromInt(1)),
+	}
+}
+
+// vpcInstanceCostComponents returns the vCPU and memory costs of count VPC virtual servers
+// with the profile.
+func vpcInstanceCostComponents(address, region, profile string, count decimal.Decimal) []*schema.CostComponent {
+	m := profileRegex.FindStringSubmatch(profile)
+	if m == nil {
+		log.Warnf("Skipping the instance cost of %s. Infracost doesn't support the %s profile.", address, profile)
+		return []*schema.CostComponent{}
+	}
+
+	vcpus, _ := decimal.NewFromString(m[1])
+	memory, _ := decimal.NewFromString(m[2])
+
+	return []*schema.CostComponent{
+		{
+			Name:           fmt.Sprintf("vCPUs (%s)", profile),
+			Unit:           "vCPU-hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(vcpus.Mul(count)),
+			ProductFilter:  productFilter(region, "is.instance", "gen2-instance", "VCPU_HOURS"),
+		},
+		{
+			Name:           fmt.Sprintf("Memory (%s)", profile),
+			Unit:           "GB-hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(memory.Mul(count)),
+			ProductFilter:  productFilter(region, "is.instance", "gen2-instance", "MEMORY_HOURS"),
+		},
+	}
+}
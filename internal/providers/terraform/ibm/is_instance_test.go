@@ -0,0 +1,33 @@
+package ibm
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewISInstance(t *testing.T) {
+	d := schema.NewResourceData("ibm_is_instance", "ibm", "ibm_is_instance.vsi", nil, gjson.Parse(`{"region": "us-south", "profile": "bx2-4x16"}`))
+	r := NewISInstance(d, nil)
+
+	components := make(map[string]string)
+	metrics := make(map[string]string)
+	for _, c := range r.CostComponents {
+		components[c.Name] = c.HourlyQuantity.String()
+		metrics[c.Name] = *c.ProductFilter.AttributeFilters[0].Value
+		assert.Equal(t, "is.instance", *c.ProductFilter.Service)
+		assert.Equal(t, "gen2-instance", *c.ProductFilter.ProductFamily)
+	}
+
+	assert.Equal(t, map[string]string{"vCPUs (bx2-4x16)": "4", "Memory (bx2-4x16)": "16"}, components)
+	assert.Equal(t, map[string]string{"vCPUs (bx2-4x16)": "VCPU_HOURS", "Memory (bx2-4x16)": "MEMORY_HOURS"}, metrics)
+}
+
+func TestNewISInstanceUnsupportedProfile(t *testing.T) {
+	d := schema.NewResourceData("ibm_is_instance", "ibm", "ibm_is_instance.vsi", nil, gjson.Parse(`{"region": "us-south", "profile": "custom"}`))
+	r := NewISInstance(d, nil)
+
+	assert.Empty(t, r.CostComponents)
+}
@@ -0,0 +1,29 @@
+package ibm
+
+import "github.com/infracost/infracost/internal/schema"
+
+var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
+	GetContainerVpcClusterRegistryItem(),
+	GetContainerVpcWorkerPoolRegistryItem(),
+	GetDatabaseRegistryItem(),
+	GetISInstanceRegistryItem(),
+	GetResourceInstanceRegistryItem(),
+}
+
+// FreeResources grouped alphabetically
+var FreeResources []string = []string{
+	"ibm_iam_access_group",
+	"ibm_iam_access_group_members",
+	"ibm_iam_access_group_policy",
+	"ibm_iam_service_id",
+	"ibm_iam_service_policy",
+	"ibm_is_security_group",
+	"ibm_is_security_group_rule",
+	"ibm_is_ssh_key",
+	"ibm_is_subnet",
+	"ibm_is_vpc",
+	"ibm_resource_group",
+	"ibm_resource_key",
+}
+
+var UsageOnlyResources []string = []string{}
@@ -0,0 +1,94 @@
+package ibm
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+func GetResourceInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "ibm_resource_instance",
+		RFunc: NewResourceInstance,
+		Notes: []string{
+			"Only Cloud Object Storage instances are supported.",
+		},
+	}
+}
+
+// NewResourceInstance prices the service instances created with ibm_resource_instance. The
+// service is set by the service attribute, e.g. cloud-object-storage.
+func NewResourceInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	service := d.Get("service").String()
+	plan := d.Get("plan").String()
+
+	if plan == "lite" {
+		return &schema.Resource{
+			NoPrice:   true,
+			IsSkipped: true,
+		}
+	}
+
+	if service != "cloud-object-storage" {
+		return &schema.Resource{
+			Name:        d.Address,
+			IsSkipped:   true,
+			SkipMessage: fmt.Sprintf("The %s service is not supported", service),
+		}
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: cloudObjectStorageCostComponents(d.Get("region").String(), plan, u),
+	}
+}
+
+func cloudObjectStorageCostComponents(region, plan string, u *schema.UsageData) []*schema.CostComponent {
+	var storageGB, classA, classB, retrievalGB *decimal.Decimal
+	if u != nil {
+		if u.Get("storage_gb").Exists() {
+			storageGB = decimalPtr(decimal.NewFromFloat(u.Get("storage_gb").Float()))
+		}
+		if u.Get("monthly_class_a_operations").Exists() {
+			classA = decimalPtr(decimal.NewFromInt(u.Get("monthly_class_a_operations").Int()))
+		}
+		if u.Get("monthly_class_b_operations").Exists() {
+			classB = decimalPtr(decimal.NewFromInt(u.Get("monthly_class_b_operations").Int()))
+		}
+		if u.Get("monthly_data_retrieval_gb").Exists() {
+			retrievalGB = decimalPtr(decimal.NewFromFloat(u.Get("monthly_data_retrieval_gb").Float()))
+		}
+	}
+
+	return []*schema.CostComponent{
+		{
+			Name:            "Storage",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: storageGB,
+			ProductFilter:   productFilter(region, "cloud-object-storage", plan, "STANDARD_STORAGE"),
+		},
+		{
+			Name:            "Class A operations",
+			Unit:            "1k operations",
+			UnitMultiplier:  decimal.NewFromInt(1000),
+			MonthlyQuantity: classA,
+			ProductFilter:   productFilter(region, "cloud-object-storage", plan, "STANDARD_CLASS_A_CALLS"),
+		},
+		{
+			Name:            "Class B operations",
+			Unit:            "10k operations",
+			UnitMultiplier:  decimal.NewFromInt(10000),
+			MonthlyQuantity: classB,
+			ProductFilter:   productFilter(region, "cloud-object-storage", plan, "STANDARD_CLASS_B_CALLS"),
+		},
+		{
+			Name:            "Data retrieval",
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: retrievalGB,
+			ProductFilter:   productFilter(region, "cloud-object-storage", plan, "STANDARD_RETRIEVAL"),
+		},
+	}
+}
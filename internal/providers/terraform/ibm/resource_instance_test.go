@@ -0,0 +1,57 @@
+package ibm
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewResourceInstanceCloudObjectStorage(t *testing.T) {
+	d := schema.NewResourceData("ibm_resource_instance", "ibm", "ibm_resource_instance.cos", nil, gjson.Parse(`{"region": "global", "service": "cloud-object-storage", "plan": "standard"}`))
+	u := schema.NewUsageData("ibm_resource_instance.cos", schema.ParseAttributes(map[string]interface{}{
+		"storage_gb":                 1000,
+		"monthly_class_a_operations": 50000,
+		"monthly_class_b_operations": 200000,
+	}))
+
+	r := NewResourceInstance(d, u)
+
+	components := make(map[string]string)
+	metrics := make(map[string]string)
+	for _, c := range r.CostComponents {
+		components[c.Name] = "-"
+		if c.MonthlyQuantity != nil {
+			components[c.Name] = c.MonthlyQuantity.String()
+		}
+		metrics[c.Name] = *c.ProductFilter.AttributeFilters[0].Value
+		assert.Equal(t, "cloud-object-storage", *c.ProductFilter.Service)
+		assert.Equal(t, "standard", *c.ProductFilter.ProductFamily)
+	}
+
+	assert.Equal(t, map[string]string{
+		"Storage":            "1000",
+		"Class A operations": "50000",
+		"Class B operations": "200000",
+		"Data retrieval":     "-",
+	}, components)
+	assert.Equal(t, map[string]string{
+		"Storage":            "STANDARD_STORAGE",
+		"Class A operations": "STANDARD_CLASS_A_CALLS",
+		"Class B operations": "STANDARD_CLASS_B_CALLS",
+		"Data retrieval":     "STANDARD_RETRIEVAL",
+	}, metrics)
+}
+
+func TestNewResourceInstanceSkipped(t *testing.T) {
+	d := schema.NewResourceData("ibm_resource_instance", "ibm", "ibm_resource_instance.cos", nil, gjson.Parse(`{"service": "cloud-object-storage", "plan": "lite"}`))
+	r := NewResourceInstance(d, nil)
+	assert.True(t, r.NoPrice)
+	assert.True(t, r.IsSkipped)
+
+	d = schema.NewResourceData("ibm_resource_instance", "ibm", "ibm_resource_instance.kms", nil, gjson.Parse(`{"service": "kms", "plan": "tiered-pricing"}`))
+	r = NewResourceInstance(d, nil)
+	assert.True(t, r.IsSkipped)
+	assert.Equal(t, "The kms service is not supported", r.SkipMessage)
+}
@@ -0,0 +1,30 @@
+package ibm
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+// productFilter returns the filter for the price of a metric of a plan in the IBM Cloud
+// Global Catalog, e.g. the GIGABYTE_MONTHS_RAM metric of the standard plan of
+// databases-for-postgresql. IBM Cloud prices are by country so the region isn't used.
+func productFilter(region, service, plan, metricID string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName:    strPtr("ibm"),
+		Region:        strPtr(region),
+		Service:       strPtr(service),
+		ProductFamily: strPtr(plan),
+		AttributeFilters: []*schema.AttributeFilter{
+			{Key: "metric_id", Value: strPtr(metricID)},
+		},
+	}
+}
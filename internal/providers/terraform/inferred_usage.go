@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/tidwall/gjson"
+)
+
+// usageInferenceRules derive usage keys of a resource type from its attributes or the other
+// resources of the project, for the usage that the resource would otherwise be priced
+// without. The rules only return the keys that can be inferred from what's set.
+var usageInferenceRules = map[string]func(d *schema.ResourceData, resData map[string]*schema.ResourceData) map[string]interface{}{
+	"aws_autoscaling_group":                inferAutoscalingGroupUsage,
+	"aws_shield_protection":                inferShieldProtectionUsage,
+	"azurerm_kubernetes_cluster":           inferKubernetesClusterUsage,
+	"azurerm_kubernetes_cluster_node_pool": inferKubernetesClusterNodePoolUsage,
+}
+
+// withInferredUsage returns the usage data of the resource with the usage that's inferred
+// from its attributes and the project's other resources added. The usage keys that are set in the usage file or fetched from
+// a cloud API are kept, since they're the actual usage rather than an estimate from config.
+func (p *Parser) withInferredUsage(d *schema.ResourceData, resData map[string]*schema.ResourceData, u *schema.UsageData) *schema.UsageData {
+	rule, ok := usageInferenceRules[d.Type]
+	if !ok {
+		return u
+	}
+
+	inferred := rule(d, resData)
+	if len(inferred) == 0 {
+		return u
+	}
+
+	return mergeFetchedUsage(d, u, inferred, schema.UsageProvenanceInferred)
+}
+
+// inferAutoscalingGroupUsage infers the instances of an autoscaling group without a
+// desired_capacity from its min_size, since AWS starts the group with that many.
+func inferAutoscalingGroupUsage(d *schema.ResourceData, resData map[string]*schema.ResourceData) map[string]interface{} {
+	if d.Get("desired_capacity").Type != gjson.Null || d.Get("min_size").Type == gjson.Null {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"instances": d.Get("min_size").Int(),
+	}
+}
+
+// inferShieldProtectionUsage infers the resources that share the organization's Shield
+// Advanced subscription from the Shield protections in the project, so the fee is only
+// counted once for them.
+func inferShieldProtectionUsage(d *schema.ResourceData, resData map[string]*schema.ResourceData) map[string]interface{} {
+	count := 0
+	for _, r := range resData {
+		if r.Type == "aws_shield_protection" {
+			count++
+		}
+	}
+
+	return map[string]interface{}{
+		"protected_resources": count,
+	}
+}
+
+// inferKubernetesClusterUsage infers the nodes of the default node pool of an AKS cluster
+// that autoscales without a node_count from its min_count.
+func inferKubernetesClusterUsage(d *schema.ResourceData, resData map[string]*schema.ResourceData) map[string]interface{} {
+	nodes, ok := autoscalingNodePoolNodes(d, "default_node_pool.0.")
+	if !ok {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"default_node_pool": map[string]interface{}{
+			"nodes": nodes,
+		},
+	}
+}
+
+// inferKubernetesClusterNodePoolUsage infers the nodes of an AKS node pool that autoscales
+// without a node_count from its min_count.
+func inferKubernetesClusterNodePoolUsage(d *schema.ResourceData, resData map[string]*schema.ResourceData) map[string]interface{} {
+	nodes, ok := autoscalingNodePoolNodes(d, "")
+	if !ok {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"nodes": nodes,
+	}
+}
+
+func autoscalingNodePoolNodes(d *schema.ResourceData, prefix string) (int64, bool) {
+	if !d.Get(prefix+"enable_auto_scaling").Bool() || d.Get(prefix+"node_count").Type != gjson.Null || d.Get(prefix+"min_count").Type == gjson.Null {
+		return 0, false
+	}
+
+	return d.Get(prefix + "min_count").Int(), true
+}
@@ -0,0 +1,144 @@
+package terraform
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+var defaultModuleRegistryHost = "registry.terraform.io"
+
+// Matches registry module sources in the form [<HOSTNAME>/]<NAMESPACE>/<NAME>/<PROVIDER>[//<SUBDIR>]
+var registrySourceRegex = regexp.MustCompile(`^(?:([a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)+)/)?([a-zA-Z0-9][a-zA-Z0-9-_]*)/([a-zA-Z0-9][a-zA-Z0-9-_]*)/([a-zA-Z0-9]+)(?://.*)?$`)
+
+// Matches exact version constraints, e.g. "1.2.3" or "= 1.2.3"
+var exactVersionRegex = regexp.MustCompile(`^=?\s*v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z-.]+)?)$`)
+
+// Hosts that Terraform treats as shorthands for VCS sources rather than registries
+var vcsShorthandHosts = []string{"github.com", "bitbucket.org"}
+
+type registryModule struct {
+	Host      string
+	Namespace string
+	Name      string
+	Provider  string
+}
+
+func (m registryModule) Source() string {
+	return strings.Join([]string{m.Host, m.Namespace, m.Name, m.Provider}, "/")
+}
+
+// moduleManifestEntry is an entry in the .terraform/modules/modules.json file that
+// Terraform writes when the modules are installed.
+type moduleManifestEntry struct {
+	Key     string `json:"Key"`
+	Source  string `json:"Source"`
+	Version string `json:"Version"`
+}
+
+// parseRegistryModuleSource parses a module source and returns the registry module
+// if the source is a registry address.
+func parseRegistryModuleSource(source string) (registryModule, bool) {
+	m := registrySourceRegex.FindStringSubmatch(source)
+	if m == nil {
+		return registryModule{}, false
+	}
+
+	host := strings.ToLower(m[1])
+	if containsString(vcsShorthandHosts, host) {
+		return registryModule{}, false
+	}
+
+	if host == "" {
+		host = defaultModuleRegistryHost
+	}
+
+	return registryModule{
+		Host:      host,
+		Namespace: m[2],
+		Name:      m[3],
+		Provider:  m[4],
+	}, true
+}
+
+// loadModuleManifest loads the installed module versions for the Terraform directory at
+// path. If the path is a file then the parent directory is used.
+func loadModuleManifest(path string) map[string]moduleManifestEntry {
+	manifest := make(map[string]moduleManifestEntry)
+
+	if path == "" {
+		return manifest
+	}
+
+	dir := path
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, ".terraform", "modules", "modules.json"))
+	if err != nil {
+		return manifest
+	}
+
+	var j struct {
+		Modules []moduleManifestEntry `json:"Modules"`
+	}
+
+	err = json.Unmarshal(b, &j)
+	if err != nil {
+		log.Debugf("Could not parse Terraform modules manifest for %s: %s", dir, err)
+		return manifest
+	}
+
+	for _, e := range j.Modules {
+		manifest[e.Key] = e
+	}
+
+	return manifest
+}
+
+// parseModuleMetadata returns the metadata for the registry module that the resource at addr
+// is in, including its registry source and version. Local submodules of a registry module,
+// e.g. module.eks.module.node_groups, are part of the nearest registry module they're in.
+func parseModuleMetadata(conf gjson.Result, manifest map[string]moduleManifestEntry, addr string) map[string]string {
+	modNames := getModuleNames(addr)
+
+	var modConf gjson.Result
+	var regModule registryModule
+	found := false
+	for ; len(modNames) > 0; modNames = modNames[:len(modNames)-1] {
+		modConf = getModuleConfJSON(conf, modNames)
+		if regModule, found = parseRegistryModuleSource(modConf.Get("source").String()); found {
+			break
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	metadata := map[string]string{
+		"moduleName":   strings.Join(modNames, "."),
+		"moduleSource": regModule.Source(),
+	}
+
+	constraint := modConf.Get("version_constraint").String()
+
+	if e, ok := manifest[strings.Join(modNames, ".")]; ok && e.Version != "" {
+		metadata["moduleVersion"] = e.Version
+	} else if m := exactVersionRegex.FindStringSubmatch(strings.TrimSpace(constraint)); m != nil {
+		metadata["moduleVersion"] = m[1]
+	}
+
+	if constraint != "" {
+		metadata["moduleVersionConstraint"] = constraint
+	}
+
+	return metadata
+}
@@ -0,0 +1,87 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestParseRegistryModuleSource(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+		ok       bool
+	}{
+		{"terraform-aws-modules/eks/aws", "registry.terraform.io/terraform-aws-modules/eks/aws", true},
+		{"terraform-aws-modules/vpc/aws//modules/vpc-endpoints", "registry.terraform.io/terraform-aws-modules/vpc/aws", true},
+		{"app.terraform.io/example-corp/k8s-cluster/azurerm", "app.terraform.io/example-corp/k8s-cluster/azurerm", true},
+		{"./modules/vpc", "", false},
+		{"../vpc", "", false},
+		{"github.com/hashicorp/example", "", false},
+		{"git::https://example.com/vpc.git", "", false},
+		{"s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip", "", false},
+	}
+
+	for _, test := range tests {
+		m, ok := parseRegistryModuleSource(test.source)
+		assert.Equal(t, test.ok, ok, test.source)
+		if ok {
+			assert.Equal(t, test.expected, m.Source())
+		}
+	}
+}
+
+func TestParseModuleMetadata(t *testing.T) {
+	conf := gjson.Parse(`{
+		"module_calls": {
+			"eks": {
+				"source": "terraform-aws-modules/eks/aws",
+				"version_constraint": "17.1.0",
+				"module": {
+					"module_calls": {
+						"node_groups": {
+							"source": "./modules/node_groups"
+						}
+					}
+				}
+			},
+			"vpc": {
+				"source": "terraform-aws-modules/vpc/aws",
+				"version_constraint": "~> 3.0"
+			},
+			"local": {
+				"source": "./modules/local"
+			}
+		}
+	}`)
+
+	manifest := map[string]moduleManifestEntry{
+		"vpc": {Key: "vpc", Source: "registry.terraform.io/terraform-aws-modules/vpc/aws", Version: "3.2.0"},
+	}
+
+	assert.Equal(t, map[string]string{
+		"moduleName":              "eks",
+		"moduleSource":            "registry.terraform.io/terraform-aws-modules/eks/aws",
+		"moduleVersion":           "17.1.0",
+		"moduleVersionConstraint": "17.1.0",
+	}, parseModuleMetadata(conf, manifest, "module.eks.aws_eks_cluster.this[0]"))
+
+	assert.Equal(t, map[string]string{
+		"moduleName":              "vpc",
+		"moduleSource":            "registry.terraform.io/terraform-aws-modules/vpc/aws",
+		"moduleVersion":           "3.2.0",
+		"moduleVersionConstraint": "~> 3.0",
+	}, parseModuleMetadata(conf, manifest, "module.vpc.aws_nat_gateway.this[0]"))
+
+	// Local submodules of registry modules use the registry module's metadata
+	assert.Equal(t, map[string]string{
+		"moduleName":              "eks",
+		"moduleSource":            "registry.terraform.io/terraform-aws-modules/eks/aws",
+		"moduleVersion":           "17.1.0",
+		"moduleVersionConstraint": "17.1.0",
+	}, parseModuleMetadata(conf, manifest, "module.eks.module.node_groups.aws_eks_node_group.workers"))
+
+	assert.Nil(t, parseModuleMetadata(conf, manifest, "module.local.aws_instance.web"))
+	assert.Nil(t, parseModuleMetadata(conf, manifest, "aws_instance.web"))
+}
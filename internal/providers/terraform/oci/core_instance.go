@@ -0,0 +1,119 @@
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+type flexShapeParts struct {
+	ocpu   string
+	memory string
+	// defaultMemoryPerOCPU is the memory in GB per OCPU that an instance has if
+	// memory_in_gbs isn't set
+	defaultMemoryPerOCPU int64
+}
+
+// The price list parts of the OCPUs and memory of the flexible shapes
+var flexShapes = map[string]flexShapeParts{
+	"VM.Standard.E3.Flex": {"B92306", "B92307", 16},
+	"VM.Standard.E4.Flex": {"B93113", "B93114", 16},
+	"VM.Standard3.Flex":   {"B94176", "B94177", 16},
+	"VM.Optimized3.Flex":  {"B93311", "B93312", 14},
+	"VM.Standard.A1.Flex": {"B93297", "B93298", 6},
+}
+
+// The X7 fixed shapes are charged per OCPU with the memory included
+const standard2OCPUPart = "B88514"
+
+var standard2ShapeRegex = regexp.MustCompile(`^VM\.Standard2\.(\d+)$`)
+
+func GetCoreInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "oci_core_instance",
+		RFunc: NewCoreInstance,
+		Notes: []string{
+			"Only the flexible shapes and the VM.Standard2 shapes are supported.",
+			"Always Free shapes, e.g. VM.Standard.E2.1.Micro, have no instance cost.",
+		},
+	}
+}
+
+func NewCoreInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+	shape := d.Get("shape").String()
+
+	costComponents := instanceCostComponents(d, region, shape)
+
+	bootVolumeSize := int64(defaultBootVolumeSize)
+	if d.Get("source_details.0.boot_volume_size_in_gbs").Exists() {
+		bootVolumeSize = d.Get("source_details.0.boot_volume_size_in_gbs").Int()
+	}
+
+	bootVolumeVPUs := int64(defaultVolumeVPUsPerGB)
+	if d.Get("source_details.0.boot_volume_vpus_per_gb").Exists() {
+		bootVolumeVPUs = d.Get("source_details.0.boot_volume_vpus_per_gb").Int()
+	}
+
+	costComponents = append(costComponents, volumeCostComponents(region, "Boot volume", bootVolumeSize, bootVolumeVPUs)...)
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
+
+func instanceCostComponents(d *schema.ResourceData, region, shape string) []*schema.CostComponent {
+	if parts, ok := flexShapes[shape]; ok {
+		ocpus := d.Get("shape_config.0.ocpus").Float()
+		if ocpus == 0 {
+			ocpus = 1
+		}
+
+		memory := float64(parts.defaultMemoryPerOCPU) * ocpus
+		if d.Get("shape_config.0.memory_in_gbs").Exists() {
+			memory = d.Get("shape_config.0.memory_in_gbs").Float()
+		}
+
+		return []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("OCPUs (%s)", shape),
+				Unit:           "OCPU-hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromFloat(ocpus)),
+				ProductFilter:  productFilter(region, parts.ocpu),
+			},
+			{
+				Name:           fmt.Sprintf("Memory (%s)", shape),
+				Unit:           "GB-hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromFloat(memory)),
+				ProductFilter:  productFilter(region, parts.memory),
+			},
+		}
+	}
+
+	if m := standard2ShapeRegex.FindStringSubmatch(shape); m != nil {
+		ocpus, _ := strconv.ParseInt(m[1], 10, 64)
+
+		return []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("OCPUs (%s)", shape),
+				Unit:           "OCPU-hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromInt(ocpus)),
+				ProductFilter:  productFilter(region, standard2OCPUPart),
+			},
+		}
+	}
+
+	if shape != "VM.Standard.E2.1.Micro" {
+		log.Warnf("Skipping the instance cost of %s. Infracost doesn't support the %s shape.", d.Address, shape)
+	}
+
+	return []*schema.CostComponent{}
+}
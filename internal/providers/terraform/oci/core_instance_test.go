@@ -0,0 +1,81 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewCoreInstance(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     string
+		components map[string]string
+	}{
+		{
+			name:   "flex shape with default memory",
+			values: `{"shape": "VM.Standard.E4.Flex", "shape_config": [{"ocpus": 2}]}`,
+			components: map[string]string{
+				"OCPUs (VM.Standard.E4.Flex)":                "2",
+				"Memory (VM.Standard.E4.Flex)":               "32",
+				"Boot volume storage":                        "47",
+				"Boot volume performance units (10 VPUs/GB)": "470",
+			},
+		},
+		{
+			name:   "fixed shape with lower cost boot volume",
+			values: `{"shape": "VM.Standard2.4", "source_details": [{"boot_volume_size_in_gbs": 100, "boot_volume_vpus_per_gb": 0}]}`,
+			components: map[string]string{
+				"OCPUs (VM.Standard2.4)": "4",
+				"Boot volume storage":    "100",
+			},
+		},
+		{
+			name:   "always free shape",
+			values: `{"shape": "VM.Standard.E2.1.Micro", "source_details": [{"boot_volume_vpus_per_gb": 0}]}`,
+			components: map[string]string{
+				"Boot volume storage": "47",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("oci_core_instance", "oci", "oci_core_instance.web", nil, gjson.Parse(tt.values))
+			r := NewCoreInstance(d, nil)
+
+			assert.Equal(t, tt.components, componentQuantities(r))
+		})
+	}
+}
+
+// componentQuantities returns the hourly or monthly quantities of the cost components of the
+// resource by name, or "-" if they don't have one.
+func componentQuantities(r *schema.Resource) map[string]string {
+	components := make(map[string]string)
+	for _, c := range r.CostComponents {
+		q := c.HourlyQuantity
+		if q == nil {
+			q = c.MonthlyQuantity
+		}
+
+		components[c.Name] = "-"
+		if q != nil {
+			components[c.Name] = q.String()
+		}
+	}
+
+	return components
+}
+
+// componentParts returns the price list parts of the cost components of the resource by name.
+func componentParts(r *schema.Resource) map[string]string {
+	parts := make(map[string]string)
+	for _, c := range r.CostComponents {
+		parts[c.Name] = *c.ProductFilter.Sku
+	}
+
+	return parts
+}
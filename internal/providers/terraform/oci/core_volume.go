@@ -0,0 +1,70 @@
+package oci
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	blockVolumeStoragePart          = "B91961"
+	blockVolumePerformanceUnitsPart = "B91962"
+
+	defaultVolumeSize      = 1024
+	defaultVolumeVPUsPerGB = 10
+	defaultBootVolumeSize  = 47
+)
+
+func GetCoreVolumeRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "oci_core_volume",
+		RFunc: NewCoreVolume,
+	}
+}
+
+func NewCoreVolume(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	size := int64(defaultVolumeSize)
+	if d.Get("size_in_gbs").Exists() {
+		size = d.Get("size_in_gbs").Int()
+	}
+
+	vpus := int64(defaultVolumeVPUsPerGB)
+	if d.Get("vpus_per_gb").Exists() {
+		vpus = d.Get("vpus_per_gb").Int()
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: volumeCostComponents(region, "Block volume", size, vpus),
+	}
+}
+
+// volumeCostComponents returns the storage and performance unit costs of a block or boot
+// volume. The performance units are charged per GB for each VPU, and volumes with the
+// Lower Cost performance level (0 VPUs/GB) only pay for the storage.
+func volumeCostComponents(region, name string, size, vpus int64) []*schema.CostComponent {
+	costComponents := []*schema.CostComponent{
+		{
+			Name:            fmt.Sprintf("%s storage", name),
+			Unit:            "GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(size)),
+			ProductFilter:   productFilter(region, blockVolumeStoragePart),
+		},
+	}
+
+	if vpus > 0 {
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:            fmt.Sprintf("%s performance units (%d VPUs/GB)", name, vpus),
+			Unit:            "VPU-GB",
+			UnitMultiplier:  decimal.NewFromInt(1),
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(size * vpus)),
+			ProductFilter:   productFilter(region, blockVolumePerformanceUnitsPart),
+		})
+	}
+
+	return costComponents
+}
@@ -0,0 +1,60 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewCoreVolume(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     string
+		components map[string]string
+	}{
+		{
+			name:   "default size and performance",
+			values: `{}`,
+			components: map[string]string{
+				"Block volume storage":                        "1024",
+				"Block volume performance units (10 VPUs/GB)": "10240",
+			},
+		},
+		{
+			name:   "higher performance",
+			values: `{"size_in_gbs": 200, "vpus_per_gb": 20}`,
+			components: map[string]string{
+				"Block volume storage":                        "200",
+				"Block volume performance units (20 VPUs/GB)": "4000",
+			},
+		},
+		{
+			name:   "lower cost",
+			values: `{"size_in_gbs": 50, "vpus_per_gb": 0}`,
+			components: map[string]string{
+				"Block volume storage": "50",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("oci_core_volume", "oci", "oci_core_volume.data", nil, gjson.Parse(tt.values))
+			r := NewCoreVolume(d, nil)
+
+			assert.Equal(t, tt.components, componentQuantities(r))
+		})
+	}
+}
+
+func TestNewCoreVolumeParts(t *testing.T) {
+	d := schema.NewResourceData("oci_core_volume", "oci", "oci_core_volume.data", nil, gjson.Parse(`{"size_in_gbs": 100}`))
+	r := NewCoreVolume(d, nil)
+
+	assert.Equal(t, map[string]string{
+		"Block volume storage":                        "B91961",
+		"Block volume performance units (10 VPUs/GB)": "B91962",
+	}, componentParts(r))
+}
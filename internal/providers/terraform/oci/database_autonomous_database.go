@@ -0,0 +1,88 @@
+package oci
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+const autonomousDatabaseStoragePart = "B90455"
+
+// The price list parts of the OCPUs of the workload types, with the license included and BYOL
+var autonomousDatabaseOCPUParts = map[string][2]string{
+	"OLTP": {"B90453", "B90454"},
+	"DW":   {"B89040", "B89039"},
+}
+
+func GetDatabaseAutonomousDatabaseRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "oci_database_autonomous_database",
+		RFunc: NewDatabaseAutonomousDatabase,
+		Notes: []string{
+			"Only the OCPU compute model is supported.",
+			"AJD and APEX workloads are priced as OLTP.",
+		},
+	}
+}
+
+func NewDatabaseAutonomousDatabase(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	if d.Get("is_free_tier").Bool() {
+		return &schema.Resource{
+			NoPrice:   true,
+			IsSkipped: true,
+		}
+	}
+
+	if d.Get("compute_model").String() == "ECPU" {
+		return &schema.Resource{
+			Name:        d.Address,
+			IsSkipped:   true,
+			SkipMessage: "The ECPU compute model is not supported",
+		}
+	}
+
+	region := d.Get("region").String()
+
+	workload := d.Get("db_workload").String()
+	parts, ok := autonomousDatabaseOCPUParts[workload]
+	if !ok {
+		workload = "OLTP"
+		parts = autonomousDatabaseOCPUParts[workload]
+	}
+
+	licenseName, ocpuPart := "license included", parts[0]
+	if d.Get("license_model").String() == "BRING_YOUR_OWN_LICENSE" {
+		licenseName, ocpuPart = "BYOL", parts[1]
+	}
+
+	ocpus := d.Get("cpu_core_count").Int()
+	if ocpus == 0 {
+		ocpus = 1
+	}
+
+	storage := d.Get("data_storage_size_in_tbs").Int()
+	if storage == 0 {
+		storage = 1
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:           fmt.Sprintf("OCPUs (%s, %s)", workload, licenseName),
+				Unit:           "OCPU-hours",
+				UnitMultiplier: decimal.NewFromInt(1),
+				HourlyQuantity: decimalPtr(decimal.NewFromInt(ocpus)),
+				ProductFilter:  productFilter(region, ocpuPart),
+			},
+			{
+				Name:            "Storage",
+				Unit:            "TB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: decimalPtr(decimal.NewFromInt(storage)),
+				ProductFilter:   productFilter(region, autonomousDatabaseStoragePart),
+			},
+		},
+	}
+}
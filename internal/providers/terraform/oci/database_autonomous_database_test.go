@@ -0,0 +1,77 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewDatabaseAutonomousDatabase(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     string
+		components map[string]string
+		parts      map[string]string
+	}{
+		{
+			name:   "default OLTP",
+			values: `{}`,
+			components: map[string]string{
+				"OCPUs (OLTP, license included)": "1",
+				"Storage":                        "1",
+			},
+			parts: map[string]string{
+				"OCPUs (OLTP, license included)": "B90453",
+				"Storage":                        "B90455",
+			},
+		},
+		{
+			name:   "data warehouse with BYOL",
+			values: `{"db_workload": "DW", "license_model": "BRING_YOUR_OWN_LICENSE", "cpu_core_count": 4, "data_storage_size_in_tbs": 2}`,
+			components: map[string]string{
+				"OCPUs (DW, BYOL)": "4",
+				"Storage":          "2",
+			},
+			parts: map[string]string{
+				"OCPUs (DW, BYOL)": "B89039",
+				"Storage":          "B90455",
+			},
+		},
+		{
+			name:   "JSON workload priced as OLTP",
+			values: `{"db_workload": "AJD", "cpu_core_count": 2}`,
+			components: map[string]string{
+				"OCPUs (OLTP, license included)": "2",
+				"Storage":                        "1",
+			},
+			parts: map[string]string{
+				"OCPUs (OLTP, license included)": "B90453",
+				"Storage":                        "B90455",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("oci_database_autonomous_database", "oci", "oci_database_autonomous_database.db", nil, gjson.Parse(tt.values))
+			r := NewDatabaseAutonomousDatabase(d, nil)
+
+			assert.Equal(t, tt.components, componentQuantities(r))
+			assert.Equal(t, tt.parts, componentParts(r))
+		})
+	}
+}
+
+func TestNewDatabaseAutonomousDatabaseSkipped(t *testing.T) {
+	d := schema.NewResourceData("oci_database_autonomous_database", "oci", "oci_database_autonomous_database.db", nil, gjson.Parse(`{"is_free_tier": true}`))
+	r := NewDatabaseAutonomousDatabase(d, nil)
+	assert.True(t, r.NoPrice)
+	assert.True(t, r.IsSkipped)
+
+	d = schema.NewResourceData("oci_database_autonomous_database", "oci", "oci_database_autonomous_database.db", nil, gjson.Parse(`{"compute_model": "ECPU"}`))
+	r = NewDatabaseAutonomousDatabase(d, nil)
+	assert.True(t, r.IsSkipped)
+	assert.Equal(t, "The ECPU compute model is not supported", r.SkipMessage)
+}
@@ -0,0 +1,69 @@
+package oci
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	loadBalancerBasePart      = "B93030"
+	loadBalancerBandwidthPart = "B93031"
+)
+
+// The fixed shapes, e.g. 100Mbps, are named after their bandwidth
+var loadBalancerFixedShapeRegex = regexp.MustCompile(`^(\d+)Mbps$`)
+
+func GetLoadBalancerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "oci_load_balancer_load_balancer",
+		RFunc: NewLoadBalancer,
+		Notes: []string{
+			"Fixed shapes are priced as flexible load balancers with the same bandwidth.",
+		},
+	}
+}
+
+// oci_load_balancer is the deprecated name of oci_load_balancer_load_balancer
+func GetLoadBalancerDeprecatedRegistryItem() *schema.RegistryItem {
+	item := GetLoadBalancerRegistryItem()
+	item.Name = "oci_load_balancer"
+	return item
+}
+
+func NewLoadBalancer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	// Flexible load balancers are charged for their minimum bandwidth
+	bandwidth := d.Get("shape_details.0.minimum_bandwidth_in_mbps").Int()
+	if m := loadBalancerFixedShapeRegex.FindStringSubmatch(d.Get("shape").String()); m != nil {
+		bandwidth, _ = strconv.ParseInt(m[1], 10, 64)
+	}
+
+	costComponents := []*schema.CostComponent{
+		{
+			Name:           "Load balancer",
+			Unit:           "hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+			ProductFilter:  productFilter(region, loadBalancerBasePart),
+		},
+	}
+
+	if bandwidth > 0 {
+		costComponents = append(costComponents, &schema.CostComponent{
+			Name:           "Bandwidth",
+			Unit:           "Mbps-hours",
+			UnitMultiplier: decimal.NewFromInt(1),
+			HourlyQuantity: decimalPtr(decimal.NewFromInt(bandwidth)),
+			ProductFilter:  productFilter(region, loadBalancerBandwidthPart),
+		})
+	}
+
+	return &schema.Resource{
+		Name:           d.Address,
+		CostComponents: costComponents,
+	}
+}
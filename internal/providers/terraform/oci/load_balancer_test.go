@@ -0,0 +1,55 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     string
+		components map[string]string
+	}{
+		{
+			name:   "flexible shape",
+			values: `{"shape": "flexible", "shape_details": [{"minimum_bandwidth_in_mbps": 50, "maximum_bandwidth_in_mbps": 200}]}`,
+			components: map[string]string{
+				"Load balancer": "1",
+				"Bandwidth":     "50",
+			},
+		},
+		{
+			name:   "fixed shape",
+			values: `{"shape": "400Mbps"}`,
+			components: map[string]string{
+				"Load balancer": "1",
+				"Bandwidth":     "400",
+			},
+		},
+		{
+			name:   "flexible shape with 10 Mbps free bandwidth",
+			values: `{"shape": "flexible", "shape_details": [{"minimum_bandwidth_in_mbps": 0, "maximum_bandwidth_in_mbps": 10}]}`,
+			components: map[string]string{
+				"Load balancer": "1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("oci_load_balancer_load_balancer", "oci", "oci_load_balancer_load_balancer.lb", nil, gjson.Parse(tt.values))
+			r := NewLoadBalancer(d, nil)
+
+			assert.Equal(t, tt.components, componentQuantities(r))
+		})
+	}
+}
+
+func TestLoadBalancerDeprecatedRegistryItem(t *testing.T) {
+	assert.Equal(t, "oci_load_balancer", GetLoadBalancerDeprecatedRegistryItem().Name)
+	assert.Equal(t, "oci_load_balancer_load_balancer", GetLoadBalancerRegistryItem().Name)
+}
@@ -0,0 +1,68 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	objectStorageRequestsPart = "B91627"
+)
+
+// The price list parts of the storage of each storage tier
+var objectStorageTierParts = map[string]string{
+	"standard": "B91628",
+	"archive":  "B91633",
+}
+
+func GetObjectStorageBucketRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "oci_objectstorage_bucket",
+		RFunc: NewObjectStorageBucket,
+	}
+}
+
+func NewObjectStorageBucket(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := d.Get("region").String()
+
+	tier := d.Get("storage_tier").String()
+	if tier == "" {
+		tier = "Standard"
+	}
+
+	storagePart, ok := objectStorageTierParts[strings.ToLower(tier)]
+	if !ok {
+		storagePart = objectStorageTierParts["standard"]
+	}
+
+	var storageGB, requests *decimal.Decimal
+	if u != nil && u.Get("storage_gb").Exists() {
+		storageGB = decimalPtr(decimal.NewFromFloat(u.Get("storage_gb").Float()))
+	}
+	if u != nil && u.Get("monthly_requests").Exists() {
+		requests = decimalPtr(decimal.NewFromInt(u.Get("monthly_requests").Int()))
+	}
+
+	return &schema.Resource{
+		Name: d.Address,
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:            fmt.Sprintf("Storage (%s)", strings.ToLower(tier)),
+				Unit:            "GB",
+				UnitMultiplier:  decimal.NewFromInt(1),
+				MonthlyQuantity: storageGB,
+				ProductFilter:   productFilter(region, storagePart),
+			},
+			{
+				Name:            "Requests",
+				Unit:            "10k requests",
+				UnitMultiplier:  decimal.NewFromInt(10000),
+				MonthlyQuantity: requests,
+				ProductFilter:   productFilter(region, objectStorageRequestsPart),
+			},
+		},
+	}
+}
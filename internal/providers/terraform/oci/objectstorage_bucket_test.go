@@ -0,0 +1,61 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewObjectStorageBucket(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     string
+		usage      map[string]interface{}
+		components map[string]string
+		parts      map[string]string
+	}{
+		{
+			name:   "without usage",
+			values: `{}`,
+			components: map[string]string{
+				"Storage (standard)": "-",
+				"Requests":           "-",
+			},
+			parts: map[string]string{
+				"Storage (standard)": "B91628",
+				"Requests":           "B91627",
+			},
+		},
+		{
+			name:   "archive tier with usage",
+			values: `{"storage_tier": "Archive"}`,
+			usage:  map[string]interface{}{"storage_gb": 500, "monthly_requests": 100000},
+			components: map[string]string{
+				"Storage (archive)": "500",
+				"Requests":          "100000",
+			},
+			parts: map[string]string{
+				"Storage (archive)": "B91633",
+				"Requests":          "B91627",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewResourceData("oci_objectstorage_bucket", "oci", "oci_objectstorage_bucket.bucket", nil, gjson.Parse(tt.values))
+
+			var u *schema.UsageData
+			if tt.usage != nil {
+				u = schema.NewUsageData("oci_objectstorage_bucket.bucket", schema.ParseAttributes(tt.usage))
+			}
+
+			r := NewObjectStorageBucket(d, u)
+
+			assert.Equal(t, tt.components, componentQuantities(r))
+			assert.Equal(t, tt.parts, componentParts(r))
+		})
+	}
+}
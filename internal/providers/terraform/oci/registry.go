@@ -0,0 +1,35 @@
+package oci
+
+import "github.com/infracost/infracost/internal/schema"
+
+var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
+	GetCoreInstanceRegistryItem(),
+	GetCoreVolumeRegistryItem(),
+	GetDatabaseAutonomousDatabaseRegistryItem(),
+	GetLoadBalancerRegistryItem(),
+	GetLoadBalancerDeprecatedRegistryItem(),
+	GetObjectStorageBucketRegistryItem(),
+}
+
+// FreeResources grouped alphabetically
+var FreeResources []string = []string{
+	"oci_core_default_route_table",
+	"oci_core_default_security_list",
+	"oci_core_internet_gateway",
+	"oci_core_route_table",
+	"oci_core_security_list",
+	"oci_core_subnet",
+	"oci_core_vcn",
+	"oci_core_volume_attachment",
+	"oci_identity_compartment",
+	"oci_identity_group",
+	"oci_identity_policy",
+	"oci_identity_user",
+	"oci_load_balancer_backend",
+	"oci_load_balancer_backend_set",
+	"oci_load_balancer_certificate",
+	"oci_load_balancer_listener",
+	"oci_objectstorage_object",
+}
+
+var UsageOnlyResources []string = []string{}
@@ -0,0 +1,25 @@
+package oci
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
+
+// productFilter returns the filter for the price of a part in the OCI price list, e.g.
+// B91961 for block volume storage. OCI prices are the same in every region.
+func productFilter(region, partNumber string) *schema.ProductFilter {
+	return &schema.ProductFilter{
+		VendorName: strPtr("oracle"),
+		Region:     strPtr(region),
+		Sku:        strPtr(partNumber),
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/infracost/infracost/internal/config"
+	awsresources "github.com/infracost/infracost/internal/resources/aws"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -18,9 +19,12 @@ import (
 // These show differently in the plan JSON for Terraform 0.12 and 0.13.
 var infracostProviderNames = []string{"infracost", "registry.terraform.io/infracost/infracost"}
 var defaultProviderRegions = map[string]string{
-	"aws":     "us-east-1",
-	"google":  "us-central1",
-	"azurerm": "eastus",
+	"aws":      "us-east-1",
+	"google":   "us-central1",
+	"azurerm":  "eastus",
+	"alicloud": "cn-hangzhou",
+	"oci":      "us-ashburn-1",
+	"ibm":      "us-south",
 }
 
 // ARN attribute mapping for resources that don't have a standard 'arn' attribute
@@ -38,11 +42,17 @@ var arnAttributeMap = map[string]string{
 }
 
 type Parser struct {
-	ctx *config.ProjectContext
+	ctx            *config.ProjectContext
+	moduleManifest map[string]moduleManifestEntry
+	usageFetchers  []namedUsageFetcher
 }
 
 func NewParser(ctx *config.ProjectContext) *Parser {
-	return &Parser{ctx}
+	return &Parser{
+		ctx:            ctx,
+		moduleManifest: loadModuleManifest(ctx.ProjectConfig.Path),
+		usageFetchers:  newUsageFetchers(ctx),
+	}
 }
 
 func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
@@ -51,6 +61,21 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	if isAwsChina(d) {
 		p.ctx.SetContextValue("isAWSChina", true)
 	}
+	if isAwsGovCloud(d) {
+		p.ctx.SetContextValue("isAWSGovCloud", true)
+	}
+
+	if err := validateAwsRegion(d); err != nil {
+		log.Warnf("Skipping resource %s. %s", d.Address, err)
+		return &schema.Resource{
+			Name:         d.Address,
+			ResourceType: d.Type,
+			Tags:         d.Tags,
+			Metadata:     d.Metadata,
+			IsSkipped:    true,
+			SkipMessage:  err.Error(),
+		}
+	}
 
 	if registryItem, ok := (*registryMap)[d.Type]; ok {
 		if registryItem.NoPrice {
@@ -58,16 +83,33 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 				Name:         d.Address,
 				ResourceType: d.Type,
 				Tags:         d.Tags,
+				Metadata:     d.Metadata,
 				IsSkipped:    true,
 				NoPrice:      true,
 				SkipMessage:  "Free resource.",
 			}
 		}
 
+		if u != nil {
+			u.ResetAccessed()
+		}
+
 		res := registryItem.RFunc(d, u)
 		if res != nil {
 			res.ResourceType = d.Type
 			res.Tags = d.Tags
+			res.Metadata = d.Metadata
+			if u != nil && len(u.Attributes) > 0 {
+				res.Usage = u.Values()
+			}
+			if u != nil && containsString(operatingScheduleResourceTypes, d.Type) && u.Get("operating_schedule").Exists() {
+				s, err := schema.ParseOperatingSchedule(u.Get("operating_schedule").String())
+				if err != nil {
+					log.Warnf("Ignoring the operating_schedule of %s. %s", d.Address, err)
+				} else {
+					schema.ApplyOperatingSchedule(res, s)
+				}
+			}
 			return res
 		}
 	}
@@ -76,11 +118,20 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 		Name:         d.Address,
 		ResourceType: d.Type,
 		Tags:         d.Tags,
+		Metadata:     d.Metadata,
 		IsSkipped:    true,
 		SkipMessage:  "This resource is not currently supported",
 	}
 }
 
+// setUsageProvenance sets the usage provenance of the cost components of the resource,
+// which must have just been created with the usage data.
+func (p *Parser) setUsageProvenance(d *schema.ResourceData, r *schema.Resource, u *schema.UsageData) {
+	schema.SetUsageProvenance(r, u, func(probe *schema.UsageData) *schema.Resource {
+		return p.createResource(d, probe)
+	})
+}
+
 func (p *Parser) parseJSONResources(parsePrior bool, baseResources []*schema.Resource, usage map[string]*schema.UsageData, parsed, providerConf, conf, vars gjson.Result) []*schema.Resource {
 	var resources []*schema.Resource
 	resources = append(resources, baseResources...)
@@ -96,23 +147,42 @@ func (p *Parser) parseJSONResources(parsePrior bool, baseResources []*schema.Res
 
 	resData := p.parseResourceData(providerConf, vals, conf, vars)
 
+	if !parsePrior {
+		p.addEstimatedResources(resData, usage, providerConf, conf, conf, vars, "")
+		p.resolveDataSources(resData, providerConf, conf, vars)
+	}
+
 	p.parseReferences(resData, conf)
 	p.loadInfracostProviderUsageData(usage, resData)
 	p.stripDataResources(resData)
 
 	for _, d := range resData {
-		var usageData *schema.UsageData
+		usageData := p.withUsageDefaults(d, p.withInferredUsage(d, resData, p.withFetchedUsage(d, schema.FindUsageData(usage, d.Address))))
 
-		if ud := usage[d.Address]; ud != nil {
-			usageData = ud
-		} else if strings.HasSuffix(d.Address, "]") {
-			lastIndexOfOpenBracket := strings.LastIndex(d.Address, "[")
+		if r := p.createResource(d, usageData); r != nil {
+			p.setUsageProvenance(d, r, usageData)
 
-			if arrayUsageData := usage[fmt.Sprintf("%s[*]", d.Address[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
-				usageData = arrayUsageData
+			if usageData != nil && usageData.HasRanges() && !r.IsSkipped {
+				r.UsageRange = &schema.ResourceUsageRange{
+					Low:  p.createResource(d, usageData.LowUsage()),
+					High: p.createResource(d, usageData.HighUsage()),
+				}
 			}
-		}
-		if r := p.createResource(d, usageData); r != nil {
+
+			if p.ctx.RunContext.Config.SyncUsageFile && !r.IsSkipped {
+				r.UsageCostComponents = p.usageCostComponents(d, usageData)
+			}
+
+			if change := p.ctx.RunContext.Config.UsageSensitivityChange; change > 0 && usageData != nil && !r.IsSkipped {
+				r.UsageSensitivity = make(map[string]*schema.ResourceUsageRange)
+				for _, k := range usageData.NumberKeys() {
+					r.UsageSensitivity[k] = &schema.ResourceUsageRange{
+						Low:  p.createResource(d, usageData.WithScaledValue(k, 1-change)),
+						High: p.createResource(d, usageData.WithScaledValue(k, 1+change)),
+					}
+				}
+			}
+
 			resources = append(resources, r)
 		}
 	}
@@ -128,6 +198,14 @@ func (p *Parser) parseJSON(j []byte, usage map[string]*schema.UsageData) ([]*sch
 	}
 
 	parsed := gjson.ParseBytes(j)
+
+	format := detectPlanFormat(parsed)
+	if err := format.check(); err != nil {
+		return baseResources, baseResources, err
+	}
+	format.logDetails()
+	p.ctx.SetContextValue("terraformJSONFormatVersion", format.FormatVersion)
+
 	providerConf := parsed.Get("configuration.provider_config")
 	conf := parsed.Get("configuration.root_module")
 	vars := parsed.Get("variables")
@@ -146,6 +224,7 @@ func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schem
 			if strings.HasPrefix(k, fmt.Sprintf("%s.", t)) {
 				d := schema.NewResourceData(t, "global", k, map[string]string{}, gjson.Result{})
 				if r := p.createResource(d, v); r != nil {
+					p.setUsageProvenance(d, r, v)
 					resources = append(resources, r)
 				}
 			}
@@ -160,25 +239,27 @@ func (p *Parser) parseResourceData(providerConf, planVals gjson.Result, conf gjs
 
 	for _, r := range planVals.Get("resources").Array() {
 		t := r.Get("type").String()
-		provider := r.Get("provider_name").String()
+		providerName := r.Get("provider_name").String()
 		addr := r.Get("address").String()
 		v := r.Get("values")
 
 		resConf := getConfJSON(conf, addr)
 
-		// Try getting the region from the ARN
-		region := resourceRegion(t, v)
+		provider := resolveProvider(addr, providerConf, vars, t, resConf)
 
-		// Otherwise use region from the provider conf
+		// Try getting the region from the ARN, otherwise use the region from the provider conf
+		region := resourceRegion(t, v)
 		if region == "" {
-			region = providerRegion(addr, providerConf, vars, t, resConf)
+			region = provider.Region
 		}
 
 		v = schema.AddRawValue(v, "region", region)
 
 		tags := parseTags(t, v)
 
-		resources[addr] = schema.NewResourceData(t, provider, addr, tags, v)
+		resources[addr] = schema.NewResourceData(t, providerName, addr, tags, v)
+		resources[addr].Metadata = parseModuleMetadata(conf, p.moduleManifest, addr)
+		addProviderMetadata(resources[addr], provider, region)
 	}
 
 	// Recursively add any resources for child modules
@@ -227,42 +308,125 @@ func resourceRegion(resourceType string, v gjson.Result) string {
 		return ""
 	}
 
-	arn := v.Get(arnAttr).String()
-	p := strings.Split(arn, ":")
-	if len(p) < 4 {
-		log.Debugf("Unexpected ARN format for %s", arn)
+	arn, err := awsresources.ParseARN(v.Get(arnAttr).String())
+	if err != nil {
+		log.Debugf("Could not get the region of %s from its ARN: %s", resourceType, err)
 		return ""
 	}
 
-	return p[3]
+	return arn.Region
 }
 
-func providerRegion(addr string, providerConf gjson.Result, vars gjson.Result, resourceType string, resConf gjson.Result) string {
-	var region string
+// providerInfo is the provider configuration that a resource is created with
+type providerInfo struct {
+	Key     string
+	Region  string
+	Account string
+}
 
-	providerKey := parseProviderKey(resConf)
-	if providerKey != "" {
-		region = parseRegion(providerConf, vars, providerKey)
-		// Note: if the provider is passed to a module using a different alias
-		// then there's no way to detect this so we just have to fallback to
-		// the default provider
-	}
+// resolveProvider finds the provider configuration for the resource, including aliased
+// providers, e.g. provider = aws.us_west, and providers defined in modules. The provider
+// config keys are checked in the following order:
+// the full key including the module, e.g. module.child:aws.us_west,
+// the key without the module, e.g. aws.us_west, and
+// the default provider for the resource type, e.g. aws.
+func resolveProvider(addr string, providerConf gjson.Result, vars gjson.Result, resourceType string, resConf gjson.Result) providerInfo {
+	providerPrefix := strings.Split(resourceType, "_")[0]
 
-	if region == "" {
-		// Try to get the provider key from the first part of the resource
-		providerPrefix := strings.Split(resourceType, "_")[0]
-		region = parseRegion(providerConf, vars, providerPrefix)
+	keys := make([]string, 0, 3)
+	if fullKey := resConf.Get("provider_config_key").String(); fullKey != "" {
+		keys = append(keys, fullKey)
 
-		if region == "" {
-			region = defaultProviderRegions[providerPrefix]
+		// Note: if an older version of Terraform is used and the provider is passed to a
+		// module using a different alias then there's no way to detect this so we just
+		// fallback to the provider with the same name in the root module
+		if k := parseProviderKey(resConf); k != fullKey {
+			keys = append(keys, k)
+		}
+	}
+	keys = append(keys, providerPrefix)
 
-			if region != "" {
-				log.Debugf("Falling back to default region (%s) for %s", region, addr)
+	for _, key := range keys {
+		region := parseRegion(providerConf, vars, key)
+		if region != "" {
+			return providerInfo{
+				Key:     key,
+				Region:  region,
+				Account: parseAccount(providerConf, vars, key),
 			}
 		}
 	}
 
-	return region
+	info := providerInfo{
+		Key:     keys[0],
+		Region:  defaultProviderRegions[providerPrefix],
+		Account: parseAccount(providerConf, vars, keys[0]),
+	}
+
+	if info.Region != "" {
+		log.Debugf("Falling back to default region (%s) for %s", info.Region, addr)
+	}
+
+	return info
+}
+
+// parseAccount returns the AWS account ID, Google project or Azure subscription that the
+// provider is configured with, if it can be found.
+func parseAccount(providerConf gjson.Result, vars gjson.Result, providerKey string) string {
+	key := gjsonEscape(providerKey)
+
+	roleARN := parseExpressionValue(providerConf.Get(fmt.Sprintf("%s.expressions.assume_role.0.role_arn", key)), vars)
+	if arn, err := awsresources.ParseARN(roleARN); err == nil && arn.Account != "" {
+		return arn.Account
+	}
+
+	if ids := providerConf.Get(fmt.Sprintf("%s.expressions.allowed_account_ids.constant_value", key)).Array(); len(ids) == 1 {
+		return ids[0].String()
+	}
+
+	for _, attr := range []string{"project", "subscription_id"} {
+		if v := parseExpressionValue(providerConf.Get(fmt.Sprintf("%s.expressions.%s", key, attr)), vars); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// parseExpressionValue returns the constant value of the expression or the value of the
+// variable it references.
+func parseExpressionValue(expr gjson.Result, vars gjson.Result) string {
+	if v := expr.Get("constant_value"); v.Exists() && !v.IsObject() && !v.IsArray() {
+		return v.String()
+	}
+
+	splitRef := strings.Split(expr.Get("references.0").String(), ".")
+	if splitRef[0] == "var" && len(splitRef) > 1 {
+		varContent := vars.Get(fmt.Sprintf("%s.value", strings.Join(splitRef[1:], ".")))
+		if !varContent.IsObject() && !varContent.IsArray() {
+			return varContent.String()
+		}
+	}
+
+	return ""
+}
+
+func addProviderMetadata(d *schema.ResourceData, provider providerInfo, region string) {
+	if d.Metadata == nil {
+		d.Metadata = make(map[string]string)
+	}
+
+	if region != "" {
+		d.Metadata["region"] = region
+	}
+
+	if provider.Key != "" {
+		d.Metadata["providerConfigKey"] = provider.Key
+	}
+
+	if provider.Account != "" {
+		d.Metadata["account"] = provider.Account
+	}
 }
 
 func parseProviderKey(resConf gjson.Result) string {
@@ -273,25 +437,7 @@ func parseProviderKey(resConf gjson.Result) string {
 }
 
 func parseRegion(providerConf gjson.Result, vars gjson.Result, providerKey string) string {
-	// Try to get constant value
-	region := providerConf.Get(fmt.Sprintf("%s.expressions.region.constant_value", gjsonEscape(providerKey))).String()
-	if region == "" {
-		// Try to get reference
-		refName := providerConf.Get(fmt.Sprintf("%s.expressions.region.references.0", gjsonEscape(providerKey))).String()
-		splitRef := strings.Split(refName, ".")
-
-		if splitRef[0] == "var" {
-			// Get the region from variables
-			varName := strings.Join(splitRef[1:], ".")
-			varContent := vars.Get(fmt.Sprintf("%s.value", varName))
-
-			if !varContent.IsObject() && !varContent.IsArray() {
-				region = varContent.String()
-			}
-		}
-	}
-
-	return region
+	return parseExpressionValue(providerConf.Get(fmt.Sprintf("%s.expressions.region", gjsonEscape(providerKey))), vars)
 }
 
 func (p *Parser) loadInfracostProviderUsageData(u map[string]*schema.UsageData, resData map[string]*schema.ResourceData) {
@@ -564,7 +710,22 @@ func removeAddressArrayPart(addr string) string {
 }
 
 func isAwsChina(d *schema.ResourceData) bool {
-	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
+	return strings.HasPrefix(d.Type, "aws_") && awsresources.RegionPartition(d.Get("region").String()) == awsresources.PartitionChina
+}
+
+func isAwsGovCloud(d *schema.ResourceData) bool {
+	return strings.HasPrefix(d.Type, "aws_") && awsresources.RegionPartition(d.Get("region").String()) == awsresources.PartitionGovCloud
+}
+
+// validateAwsRegion returns an error if an AWS resource has a region that isn't valid, so
+// it isn't priced using the prices of a different region.
+func validateAwsRegion(d *schema.ResourceData) error {
+	region := d.Get("region").String()
+	if !strings.HasPrefix(d.Type, "aws_") || region == "" {
+		return nil
+	}
+
+	return awsresources.ValidateRegion(region)
 }
 
 func containsString(a []string, s string) bool {
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/infracost/infracost/internal/config"
@@ -342,6 +343,16 @@ func TestCreateResource(t *testing.T) {
 				SkipMessage:  "This resource is not currently supported",
 			},
 		},
+		{
+			data: schema.NewResourceData("aws_instance", "aws", "aws_instance.invalid_region", nil, gjson.Parse(`{"region": "us-east"}`)),
+			expected: &schema.Resource{
+				Name:         "aws_instance.invalid_region",
+				ResourceType: "aws_instance",
+				IsSkipped:    true,
+				NoPrice:      false,
+				SkipMessage:  "Invalid AWS region us-east",
+			},
+		},
 	}
 
 	p := NewParser(config.EmptyProjectContext())
@@ -355,6 +366,26 @@ func TestCreateResource(t *testing.T) {
 	}
 }
 
+func TestResourceRegionPartitions(t *testing.T) {
+	tests := []struct {
+		arn      string
+		expected string
+	}{
+		{arn: "arn:aws:ec2:eu-west-1:123456789012:instance/i-1", expected: "eu-west-1"},
+		{arn: "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-1", expected: "us-gov-west-1"},
+		{arn: "arn:aws-cn:ec2:cn-north-1:123456789012:instance/i-1", expected: "cn-north-1"},
+		// The region doesn't match the partition
+		{arn: "arn:aws:ec2:cn-north-1:123456789012:instance/i-1", expected: ""},
+		{arn: "arn:aws-iso:ec2:us-iso-east-1:123456789012:instance/i-1", expected: ""},
+		{arn: "i-1", expected: ""},
+	}
+
+	for _, test := range tests {
+		v := gjson.Parse(fmt.Sprintf(`{"arn": %q}`, test.arn))
+		assert.Equal(t, test.expected, resourceRegion("aws_instance", v), test.arn)
+	}
+}
+
 func TestParseResourceData(t *testing.T) {
 	providerConf := gjson.Result{
 		Type: gjson.JSON,
@@ -809,3 +840,515 @@ func TestParseKnownModuleRefs(t *testing.T) {
 
 	assert.NotNil(t, resData[res.Address].References("launch_template"))
 }
+
+func TestParseResourceDataProviderMetadata(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {
+			"name": "aws",
+			"expressions": {
+				"region": {"constant_value": "us-east-1"}
+			}
+		},
+		"aws.us_west": {
+			"name": "aws",
+			"alias": "us_west",
+			"expressions": {
+				"region": {"constant_value": "us-west-2"},
+				"assume_role": [
+					{"role_arn": {"references": ["var.role_arn"]}}
+				]
+			}
+		},
+		"module.dr:aws": {
+			"name": "aws",
+			"module_address": "module.dr",
+			"expressions": {
+				"region": {"constant_value": "eu-west-1"},
+				"allowed_account_ids": {"constant_value": ["210987654321"]}
+			}
+		}
+	}`)
+
+	planVals := gjson.Parse(`{
+		"resources": [
+			{"address": "aws_instance.east", "type": "aws_instance", "provider_name": "aws", "values": {}},
+			{"address": "aws_instance.west", "type": "aws_instance", "provider_name": "aws", "values": {}}
+		],
+		"child_modules": [
+			{
+				"address": "module.dr",
+				"resources": [
+					{"address": "module.dr.aws_instance.dr", "type": "aws_instance", "provider_name": "aws", "values": {}}
+				]
+			}
+		]
+	}`)
+
+	conf := gjson.Parse(`{
+		"resources": [
+			{"address": "aws_instance.east", "type": "aws_instance", "provider_config_key": "aws"},
+			{"address": "aws_instance.west", "type": "aws_instance", "provider_config_key": "aws.us_west"}
+		],
+		"module_calls": {
+			"dr": {
+				"source": "./dr",
+				"module": {
+					"resources": [
+						{"address": "aws_instance.dr", "type": "aws_instance", "provider_config_key": "module.dr:aws"}
+					]
+				}
+			}
+		}
+	}`)
+
+	vars := gjson.Parse(`{
+		"role_arn": {"value": "arn:aws:iam::123456789012:role/infracost"}
+	}`)
+
+	p := NewParser(config.EmptyProjectContext())
+	actual := p.parseResourceData(providerConf, planVals, conf, vars)
+
+	assert.Equal(t, "us-east-1", actual["aws_instance.east"].Get("region").String())
+	assert.Equal(t, map[string]string{"region": "us-east-1", "providerConfigKey": "aws"}, actual["aws_instance.east"].Metadata)
+
+	assert.Equal(t, "us-west-2", actual["aws_instance.west"].Get("region").String())
+	assert.Equal(t, map[string]string{"region": "us-west-2", "providerConfigKey": "aws.us_west", "account": "123456789012"}, actual["aws_instance.west"].Metadata)
+
+	assert.Equal(t, "eu-west-1", actual["module.dr.aws_instance.dr"].Get("region").String())
+	assert.Equal(t, "module.dr:aws", actual["module.dr.aws_instance.dr"].Metadata["providerConfigKey"])
+	assert.Equal(t, "210987654321", actual["module.dr.aws_instance.dr"].Metadata["account"])
+}
+
+func TestResolveDataSources(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {"name": "aws", "expressions": {"region": {"constant_value": "eu-west-2"}}},
+		"google": {"name": "google", "expressions": {"region": {"constant_value": "europe-west1"}}}
+	}`)
+
+	planVals := gjson.Parse(`{
+		"resources": [
+			{"address": "aws_instance.web[0]", "type": "aws_instance", "provider_name": "aws", "values": {}},
+			{"address": "aws_instance.web[1]", "type": "aws_instance", "provider_name": "aws", "values": {}},
+			{"address": "aws_instance.known", "type": "aws_instance", "provider_name": "aws", "values": {"instance_type": "m5.large"}},
+			{"address": "google_compute_instance.app", "type": "google_compute_instance", "provider_name": "google", "values": {"boot_disk": [{"initialize_params": [{}]}]}}
+		]
+	}`)
+
+	conf := gjson.Parse(`{
+		"resources": [
+			{
+				"address": "aws_instance.web",
+				"mode": "managed",
+				"type": "aws_instance",
+				"expressions": {
+					"ami": {"references": ["data.aws_ami.windows.id", "data.aws_ami.windows"]},
+					"instance_type": {"references": ["data.aws_ec2_instance_type.web.instance_type", "data.aws_ec2_instance_type.web"]},
+					"availability_zone": {"references": ["data.aws_availability_zones.available.names", "data.aws_availability_zones.available", "count.index"]}
+				}
+			},
+			{
+				"address": "aws_instance.known",
+				"mode": "managed",
+				"type": "aws_instance",
+				"expressions": {
+					"instance_type": {"references": ["data.aws_ec2_instance_type.web.instance_type", "data.aws_ec2_instance_type.web"]}
+				}
+			},
+			{
+				"address": "google_compute_instance.app",
+				"mode": "managed",
+				"type": "google_compute_instance",
+				"expressions": {
+					"zone": {"references": ["data.google_compute_zones.available.names", "data.google_compute_zones.available"]},
+					"boot_disk": [{"initialize_params": [{"image": {"references": ["data.google_compute_image.debian.self_link", "data.google_compute_image.debian"]}}]}]
+				}
+			},
+			{
+				"address": "data.aws_ami.windows",
+				"mode": "data",
+				"type": "aws_ami",
+				"provider_config_key": "aws",
+				"expressions": {
+					"filter": [{"name": {"constant_value": "name"}, "values": {"constant_value": ["Windows_Server-2019-English-Full-Base-*"]}}]
+				}
+			},
+			{
+				"address": "data.aws_ec2_instance_type.web",
+				"mode": "data",
+				"type": "aws_ec2_instance_type",
+				"provider_config_key": "aws",
+				"expressions": {"instance_type": {"references": ["var.instance_type"]}}
+			},
+			{
+				"address": "data.aws_availability_zones.available",
+				"mode": "data",
+				"type": "aws_availability_zones",
+				"provider_config_key": "aws"
+			},
+			{
+				"address": "data.google_compute_zones.available",
+				"mode": "data",
+				"type": "google_compute_zones",
+				"provider_config_key": "google"
+			},
+			{
+				"address": "data.google_compute_image.debian",
+				"mode": "data",
+				"type": "google_compute_image",
+				"provider_config_key": "google",
+				"expressions": {"family": {"constant_value": "debian-11"}, "project": {"constant_value": "debian-cloud"}}
+			}
+		]
+	}`)
+
+	vars := gjson.Parse(`{"instance_type": {"value": "t3.medium"}}`)
+
+	p := NewParser(config.EmptyProjectContext())
+	resData := p.parseResourceData(providerConf, planVals, conf, vars)
+	p.resolveDataSources(resData, providerConf, conf, vars)
+
+	assert.Equal(t, "t3.medium", resData["aws_instance.web[0]"].Get("instance_type").String())
+	assert.Equal(t, "eu-west-2a", resData["aws_instance.web[0]"].Get("availability_zone").String())
+	assert.Equal(t, "eu-west-2b", resData["aws_instance.web[1]"].Get("availability_zone").String())
+	assert.Equal(t, "m5.large", resData["aws_instance.known"].Get("instance_type").String())
+
+	assert.Equal(t, "europe-west1-a", resData["google_compute_instance.app"].Get("zone").String())
+	assert.Equal(t, "projects/debian-cloud/global/images/family/debian-11", resData["google_compute_instance.app"].Get("boot_disk.0.initialize_params.0.image").String())
+
+	p.parseReferences(resData, conf)
+	amiRefs := resData["aws_instance.web[0]"].References("ami")
+	assert.Len(t, amiRefs, 1)
+	assert.Equal(t, "windows", amiRefs[0].Get("platform").String())
+}
+
+func TestAddEstimatedResources(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {"name": "aws", "expressions": {"region": {"constant_value": "eu-west-2"}}}
+	}`)
+
+	conf := gjson.Parse(`{
+		"resources": [
+			{
+				"address": "aws_instance.web",
+				"mode": "managed",
+				"type": "aws_instance",
+				"provider_config_key": "aws",
+				"expressions": {
+					"instance_type": {"references": ["var.instance_type"]},
+					"root_block_device": [{"volume_size": {"constant_value": 50}}]
+				},
+				"count_expression": {"references": ["aws_subnet.private"]}
+			},
+			{
+				"address": "aws_instance.unknown",
+				"mode": "managed",
+				"type": "aws_instance",
+				"provider_config_key": "aws",
+				"for_each_expression": {"references": ["aws_subnet.private"]}
+			},
+			{
+				"address": "aws_instance.disabled",
+				"mode": "managed",
+				"type": "aws_instance",
+				"provider_config_key": "aws",
+				"count_expression": {"references": ["var.enabled"]}
+			}
+		]
+	}`)
+
+	vars := gjson.Parse(`{"instance_type": {"value": "t3.medium"}}`)
+
+	usage := schema.NewUsageMap(map[string]interface{}{
+		"aws_instance.web[*]": map[string]interface{}{
+			"count_estimate": 3,
+		},
+	})
+
+	p := NewParser(config.EmptyProjectContext())
+	resData := map[string]*schema.ResourceData{}
+	p.addEstimatedResources(resData, usage, providerConf, conf, conf, vars, "")
+
+	assert.Len(t, resData, 3)
+
+	for _, addr := range []string{"aws_instance.web[0]", "aws_instance.web[1]", "aws_instance.web[2]"} {
+		d := resData[addr]
+		if assert.NotNil(t, d, addr) {
+			assert.Equal(t, "aws_instance", d.Type)
+			assert.Equal(t, "t3.medium", d.Get("instance_type").String())
+			assert.Equal(t, int64(50), d.Get("root_block_device.0.volume_size").Int())
+			assert.Equal(t, "eu-west-2", d.Get("region").String())
+			assert.Equal(t, "3", d.Metadata["countEstimate"])
+			assert.Equal(t, "usage_file", d.Metadata["countEstimateSource"])
+		}
+	}
+}
+
+func TestAddEstimatedResourcesProjectDefault(t *testing.T) {
+	providerConf := gjson.Parse(`{
+		"aws": {"name": "aws", "expressions": {"region": {"constant_value": "eu-west-2"}}}
+	}`)
+
+	conf := gjson.Parse(`{
+		"resources": [
+			{
+				"address": "aws_instance.web",
+				"mode": "managed",
+				"type": "aws_instance",
+				"provider_config_key": "aws",
+				"for_each_expression": {"references": ["aws_subnet.private"]}
+			},
+			{
+				"address": "aws_instance.disabled",
+				"mode": "managed",
+				"type": "aws_instance",
+				"provider_config_key": "aws",
+				"count_expression": {"references": ["var.enabled"]}
+			}
+		]
+	}`)
+
+	ctx := config.EmptyProjectContext()
+	ctx.ProjectConfig.CountEstimate = 2
+
+	p := NewParser(ctx)
+	resData := map[string]*schema.ResourceData{}
+	p.addEstimatedResources(resData, map[string]*schema.UsageData{}, providerConf, conf, conf, gjson.Result{}, "")
+
+	assert.Len(t, resData, 2)
+
+	for _, addr := range []string{"aws_instance.web[0]", "aws_instance.web[1]"} {
+		d := resData[addr]
+		if assert.NotNil(t, d, addr) {
+			assert.Equal(t, "2", d.Metadata["countEstimate"])
+			assert.Equal(t, "config_file", d.Metadata["countEstimateSource"])
+		}
+	}
+}
+
+func TestWithUsageDefaults(t *testing.T) {
+	ctx := config.EmptyProjectContext()
+	ctx.ProjectConfig.ReservedInstances = &config.ReservedInstances{
+		Type:          "standard",
+		Term:          "1_year",
+		PaymentOption: "partial_upfront",
+	}
+
+	p := NewParser(ctx)
+
+	d := schema.NewResourceData("aws_instance", "aws", "aws_instance.web", nil, gjson.Result{})
+	u := p.withUsageDefaults(d, nil)
+	if assert.NotNil(t, u) {
+		assert.Equal(t, "aws_instance.web", u.Address)
+		assert.Equal(t, "standard", u.Get("reserved_instance_type").String())
+		assert.Equal(t, "1_year", u.Get("reserved_instance_term").String())
+		assert.Equal(t, "partial_upfront", u.Get("reserved_instance_payment_option").String())
+	}
+
+	// The usage file takes precedence over the config file
+	existing := schema.NewUsageData("aws_instance.web", schema.ParseAttributes(map[string]interface{}{
+		"reserved_instance_term": "3_year",
+		"operating_system":       "windows",
+	}))
+	u = p.withUsageDefaults(d, existing)
+	assert.Equal(t, "3_year", u.Get("reserved_instance_term").String())
+	assert.Equal(t, "partial_upfront", u.Get("reserved_instance_payment_option").String())
+	assert.Equal(t, "windows", u.Get("operating_system").String())
+	assert.Nil(t, existing.Attributes["reserved_instance_payment_option"].Value())
+
+	other := schema.NewResourceData("aws_s3_bucket", "aws", "aws_s3_bucket.b", nil, gjson.Result{})
+	assert.Nil(t, p.withUsageDefaults(other, nil))
+}
+
+func TestWithUsageDefaultsAzureHybridBenefit(t *testing.T) {
+	ctx := config.EmptyProjectContext()
+	ctx.ProjectConfig.AzureHybridBenefit = true
+	ctx.ProjectConfig.ReservedInstances = &config.ReservedInstances{Term: "3_year"}
+
+	p := NewParser(ctx)
+
+	d := schema.NewResourceData("azurerm_windows_virtual_machine", "azurerm", "azurerm_windows_virtual_machine.vm", nil, gjson.Result{})
+	u := p.withUsageDefaults(d, nil)
+	if assert.NotNil(t, u) {
+		assert.True(t, u.Get("azure_hybrid_benefit").Bool())
+		assert.Equal(t, "3_year", u.Get("reserved_instance_term").String())
+	}
+
+	// The usage file can turn off Azure Hybrid Benefit for a resource
+	existing := schema.NewUsageData("azurerm_windows_virtual_machine.vm", schema.ParseAttributes(map[string]interface{}{
+		"azure_hybrid_benefit": false,
+	}))
+	u = p.withUsageDefaults(d, existing)
+	assert.False(t, u.Get("azure_hybrid_benefit").Bool())
+	assert.True(t, u.Get("azure_hybrid_benefit").Exists())
+
+	linux := schema.NewResourceData("azurerm_linux_virtual_machine", "azurerm", "azurerm_linux_virtual_machine.vm", nil, gjson.Result{})
+	u = p.withUsageDefaults(linux, nil)
+	assert.False(t, u.Get("azure_hybrid_benefit").Exists())
+	assert.Equal(t, "3_year", u.Get("reserved_instance_term").String())
+}
+
+func TestWithUsageDefaultsOperatingSchedules(t *testing.T) {
+	ctx := config.EmptyProjectContext()
+	ctx.ProjectConfig.OperatingSchedules = []*config.OperatingSchedule{
+		{Tags: map[string]string{"environment": "dev", "team": "api"}, Schedule: "mon-fri 9am-5pm"},
+		{Tags: map[string]string{"environment": "dev"}, Schedule: "weekdays 08:00-20:00"},
+	}
+
+	p := NewParser(ctx)
+
+	d := schema.NewResourceData("aws_instance", "aws", "aws_instance.web", map[string]string{"environment": "dev"}, gjson.Result{})
+	u := p.withUsageDefaults(d, nil)
+	if assert.NotNil(t, u) {
+		assert.Equal(t, "weekdays 08:00-20:00", u.Get("operating_schedule").String())
+	}
+
+	// The first matching schedule is used
+	d = schema.NewResourceData("aws_instance", "aws", "aws_instance.api", map[string]string{"environment": "dev", "team": "api"}, gjson.Result{})
+	assert.Equal(t, "mon-fri 9am-5pm", p.withUsageDefaults(d, nil).Get("operating_schedule").String())
+
+	// The usage file takes precedence over the config file
+	existing := schema.NewUsageData("aws_instance.web", schema.ParseAttributes(map[string]interface{}{
+		"operating_schedule": "daily",
+	}))
+	assert.Equal(t, "daily", p.withUsageDefaults(d, existing).Get("operating_schedule").String())
+
+	// Compute Engine instances get the monthly hours for their sustained use discount
+	gce := schema.NewResourceData("google_compute_instance", "google", "google_compute_instance.vm", map[string]string{"environment": "dev"}, gjson.Result{})
+	u = p.withUsageDefaults(gce, nil)
+	assert.Equal(t, 260.71, u.Get("monthly_hrs").Float())
+
+	prod := schema.NewResourceData("aws_instance", "aws", "aws_instance.prod", map[string]string{"environment": "prod"}, gjson.Result{})
+	assert.Nil(t, p.withUsageDefaults(prod, nil))
+}
+
+func TestCreateResourceOperatingSchedule(t *testing.T) {
+	p := NewParser(config.EmptyProjectContext())
+
+	d := schema.NewResourceData("aws_instance", "aws", "aws_instance.web", nil, gjson.Parse(`{"region":"us-east-1","instance_type":"m5.large","root_block_device":[{"volume_size":10}]}`))
+	u := schema.NewUsageData("aws_instance.web", schema.ParseAttributes(map[string]interface{}{
+		"operating_schedule": "weekdays 08:00-20:00",
+	}))
+
+	r := p.createResource(d, u)
+	for _, c := range r.CostComponents {
+		if c.Unit == "hours" {
+			assert.Equal(t, "0.3571", c.HourlyQuantity.StringFixed(4), c.Name)
+		}
+	}
+	for _, sub := range r.SubResources {
+		for _, c := range sub.CostComponents {
+			assert.NotEqual(t, "hours", c.Unit, c.Name)
+		}
+	}
+}
+
+type testUsageFetcher map[string]interface{}
+
+func (f testUsageFetcher) SupportsResourceType(resourceType string) bool {
+	return resourceType == "aws_lambda_function"
+}
+
+func (f testUsageFetcher) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	return f, nil
+}
+
+func TestWithFetchedUsage(t *testing.T) {
+	p := NewParser(config.EmptyProjectContext())
+	p.usageFetchers = []namedUsageFetcher{
+		{"metrics", schema.UsageProvenanceCloudMetric, testUsageFetcher{"monthly_requests": 100, "request_duration_ms": 200}},
+		{"billing", schema.UsageProvenanceBillingData, testUsageFetcher{"request_duration_ms": 300, "monthly_requests_per_second": 5}},
+	}
+
+	d := schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.fn", nil, gjson.Result{})
+	u := schema.NewUsageData("aws_lambda_function.fn", schema.ParseAttributes(map[string]interface{}{"monthly_requests": 50}))
+
+	merged := p.withFetchedUsage(d, u)
+	assert.Equal(t, int64(50), merged.Get("monthly_requests").Int())
+	assert.Equal(t, schema.UsageProvenanceUsageFile, merged.Provenance("monthly_requests"))
+	assert.Equal(t, int64(200), merged.Get("request_duration_ms").Int())
+	assert.Equal(t, schema.UsageProvenanceCloudMetric, merged.Provenance("request_duration_ms"))
+	assert.Equal(t, int64(5), merged.Get("monthly_requests_per_second").Int())
+	assert.Equal(t, schema.UsageProvenanceBillingData, merged.Provenance("monthly_requests_per_second"))
+
+	d = schema.NewResourceData("aws_s3_bucket", "aws", "aws_s3_bucket.bucket", nil, gjson.Result{})
+	assert.Nil(t, p.withFetchedUsage(d, nil))
+}
+
+func TestWithInferredUsage(t *testing.T) {
+	p := NewParser(config.EmptyProjectContext())
+
+	d := schema.NewResourceData("aws_autoscaling_group", "aws", "aws_autoscaling_group.asg", nil, gjson.Parse(`{"min_size":2,"max_size":10,"desired_capacity":null}`))
+	u := p.withInferredUsage(d, nil, nil)
+	assert.Equal(t, int64(2), u.Get("instances").Int())
+	assert.Equal(t, schema.UsageProvenanceInferred, u.Provenance("instances"))
+
+	// The usage file takes precedence
+	existing := schema.NewUsageData("aws_autoscaling_group.asg", schema.ParseAttributes(map[string]interface{}{"instances": 5}))
+	u = p.withInferredUsage(d, nil, existing)
+	assert.Equal(t, int64(5), u.Get("instances").Int())
+	assert.Equal(t, schema.UsageProvenanceUsageFile, u.Provenance("instances"))
+
+	d = schema.NewResourceData("aws_autoscaling_group", "aws", "aws_autoscaling_group.asg", nil, gjson.Parse(`{"min_size":2,"desired_capacity":4}`))
+	assert.Nil(t, p.withInferredUsage(d, nil, nil))
+
+	d = schema.NewResourceData("azurerm_kubernetes_cluster", "azurerm", "azurerm_kubernetes_cluster.aks", nil, gjson.Parse(`{"default_node_pool":[{"enable_auto_scaling":true,"min_count":3,"max_count":6}]}`))
+	u = p.withInferredUsage(d, nil, nil)
+	assert.Equal(t, int64(3), u.Get("default_node_pool.nodes").Int())
+
+	d = schema.NewResourceData("azurerm_kubernetes_cluster_node_pool", "azurerm", "azurerm_kubernetes_cluster_node_pool.pool", nil, gjson.Parse(`{"enable_auto_scaling":false,"node_count":1,"min_count":3}`))
+	assert.Nil(t, p.withInferredUsage(d, nil, nil))
+
+	resData := map[string]*schema.ResourceData{}
+	for _, addr := range []string{"aws_shield_protection.alb", "aws_shield_protection.cloudfront", "aws_shield_protection.eip"} {
+		resData[addr] = schema.NewResourceData("aws_shield_protection", "aws", addr, nil, gjson.Result{})
+	}
+	resData["aws_lb.alb"] = schema.NewResourceData("aws_lb", "aws", "aws_lb.alb", nil, gjson.Result{})
+
+	d = resData["aws_shield_protection.alb"]
+	u = p.withInferredUsage(d, resData, nil)
+	assert.Equal(t, int64(3), u.Get("protected_resources").Int())
+	assert.Equal(t, schema.UsageProvenanceInferred, u.Provenance("protected_resources"))
+
+	// The usage file takes precedence
+	existing = schema.NewUsageData("aws_shield_protection.alb", schema.ParseAttributes(map[string]interface{}{"protected_resources": 10}))
+	u = p.withInferredUsage(d, resData, existing)
+	assert.Equal(t, int64(10), u.Get("protected_resources").Int())
+}
+
+func TestUsageCostComponents(t *testing.T) {
+	p := NewParser(config.EmptyProjectContext())
+
+	d := schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.fn", nil, gjson.Parse(`{"region":"us-east-1","memory_size":1024}`))
+	assert.Equal(t, map[string][]string{
+		"monthly_requests":    {"Requests", "Duration"},
+		"request_duration_ms": {"Duration"},
+	}, p.usageCostComponents(d, nil))
+
+	// Keys with values aren't included
+	u := schema.NewUsageData("aws_lambda_function.fn", schema.ParseAttributes(map[string]interface{}{"monthly_requests": 1000}))
+	assert.Equal(t, map[string][]string{
+		"request_duration_ms": {"Duration"},
+	}, p.usageCostComponents(d, u))
+}
+
+func TestSetUsageProvenance(t *testing.T) {
+	p := NewParser(config.EmptyProjectContext())
+
+	// The storage comes from the size attribute and the I/O requests from the usage
+	d := schema.NewResourceData("aws_ebs_volume", "aws", "aws_ebs_volume.vol", nil, gjson.Parse(`{"region":"us-east-1","type":"standard","size":100}`))
+	u := schema.NewUsageData("aws_ebs_volume.vol", schema.ParseAttributes(map[string]interface{}{"monthly_standard_io_requests": 10000}))
+	u.SetProvenance("monthly_standard_io_requests", schema.UsageProvenanceCloudMetric)
+
+	r := p.createResource(d, u)
+	p.setUsageProvenance(d, r, u)
+
+	provenances := make(map[string]schema.UsageProvenance)
+	for _, c := range r.CostComponents {
+		provenances[c.Name] = c.UsageProvenance
+	}
+
+	assert.Equal(t, map[string]schema.UsageProvenance{
+		"Storage (magnetic)": "",
+		"I/O requests":       schema.UsageProvenanceCloudMetric,
+	}, provenances)
+}
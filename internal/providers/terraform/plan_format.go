@@ -0,0 +1,150 @@
+package terraform
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"golang.org/x/mod/semver"
+)
+
+// The JSON output format versions written by Terraform. Terraform only increments the
+// major version for breaking changes, so any 0.x or 1.x format can be parsed. Format 0.1 is
+// written by Terraform 0.12 to 0.15, 0.2 by Terraform 1.0 (adds sensitive_values), 1.0 by
+// Terraform 1.1 (adds relevant_attributes), 1.1 by Terraform 1.3 (adds precondition and
+// postcondition blocks) and 1.2 by Terraform 1.5 (adds checks).
+var minPlanFormatVersion = "v0.1"
+var maxPlanFormatMajorVersion = "v1"
+
+// planFormat contains the format details of a Terraform plan or state JSON file
+type planFormat struct {
+	FormatVersion    string
+	TerraformVersion string
+	HasChecks        bool
+	HasConditions    bool
+	HasSensitive     bool
+}
+
+func detectPlanFormat(parsed gjson.Result) planFormat {
+	f := planFormat{
+		FormatVersion:    parsed.Get("format_version").String(),
+		TerraformVersion: parsed.Get("terraform_version").String(),
+		HasChecks:        parsed.Get("checks").Exists(),
+		HasConditions:    hasConditionBlocks(parsed.Get("configuration.root_module")),
+		HasSensitive:     hasSensitiveValues(parsed),
+	}
+
+	return f
+}
+
+// check returns an error if the format version or the Terraform version are not supported.
+// Files without a format version, e.g. from older tooling, are parsed on a best effort basis.
+func (f planFormat) check() error {
+	if f.FormatVersion == "" {
+		log.Debugf("No format_version found in Terraform JSON, attempting to parse anyway")
+		return nil
+	}
+
+	v := semverString(f.FormatVersion)
+	if !semver.IsValid(v) {
+		return errors.Errorf("Invalid Terraform JSON format version %q", f.FormatVersion)
+	}
+
+	if semver.Compare(v, minPlanFormatVersion) < 0 || semver.Compare(semver.Major(v), maxPlanFormatMajorVersion) > 0 {
+		msg := "Unsupported Terraform JSON format version %s"
+		if f.TerraformVersion != "" {
+			msg += " (generated by Terraform " + f.TerraformVersion + ")"
+		}
+		msg += ". Supported format versions are %s to %s.x, please upgrade Infracost or use a supported Terraform version."
+
+		return errors.Errorf(msg, f.FormatVersion, strings.TrimPrefix(minPlanFormatVersion, "v"), strings.TrimPrefix(maxPlanFormatMajorVersion, "v"))
+	}
+
+	if f.TerraformVersion != "" {
+		tv := semverString(f.TerraformVersion)
+		if semver.IsValid(tv) && semver.Compare(tv, minTerraformVer) < 0 {
+			return errors.Errorf("Terraform %s is not supported. Please use Terraform version >= %s.", f.TerraformVersion, minTerraformVer)
+		}
+	}
+
+	return nil
+}
+
+func (f planFormat) logDetails() {
+	log.Debugf("Detected Terraform JSON format version %s from Terraform %s", f.FormatVersion, f.TerraformVersion)
+
+	if f.HasSensitive {
+		log.Debugf("Terraform JSON contains sensitive values, these are parsed the same as other values")
+	}
+
+	if f.HasConditions {
+		log.Debugf("Terraform JSON contains precondition or postcondition blocks, these are ignored")
+	}
+
+	if f.HasChecks {
+		log.Debugf("Terraform JSON contains check results, these are ignored")
+	}
+}
+
+func semverString(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+
+	return v
+}
+
+func hasSensitiveValues(parsed gjson.Result) bool {
+	found := false
+
+	for _, m := range []string{"planned_values.root_module", "values.root_module", "prior_state.values.root_module"} {
+		walkModuleResources(parsed.Get(m), func(r gjson.Result) {
+			if containsTrue(r.Get("sensitive_values")) {
+				found = true
+			}
+		})
+	}
+
+	return found
+}
+
+func containsTrue(v gjson.Result) bool {
+	if v.Type == gjson.True {
+		return true
+	}
+
+	found := false
+	v.ForEach(func(_, c gjson.Result) bool {
+		found = containsTrue(c)
+		return !found
+	})
+
+	return found
+}
+
+func hasConditionBlocks(conf gjson.Result) bool {
+	for _, r := range conf.Get("resources").Array() {
+		if r.Get("preconditions").Exists() || r.Get("postconditions").Exists() {
+			return true
+		}
+	}
+
+	for _, m := range conf.Get("module_calls").Map() {
+		if hasConditionBlocks(m.Get("module")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func walkModuleResources(module gjson.Result, f func(gjson.Result)) {
+	for _, r := range module.Get("resources").Array() {
+		f(r)
+	}
+
+	for _, m := range module.Get("child_modules").Array() {
+		walkModuleResources(m, f)
+	}
+}
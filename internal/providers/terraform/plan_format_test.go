@@ -0,0 +1,88 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestPlanFormatCheck(t *testing.T) {
+	tests := []struct {
+		formatVersion    string
+		terraformVersion string
+		valid            bool
+	}{
+		{"", "", true},
+		{"0.1", "0.12.31", true},
+		{"0.1", "0.14.8", true},
+		{"0.2", "1.0.11", true},
+		{"1.0", "1.1.9", true},
+		{"1.1", "1.3.0", true},
+		{"1.2", "1.5.7", true},
+		{"2.0", "2.0.0", false},
+		{"0.1", "0.11.14", false},
+		{"invalid", "", false},
+	}
+
+	for _, test := range tests {
+		f := planFormat{FormatVersion: test.formatVersion, TerraformVersion: test.terraformVersion}
+		err := f.check()
+		if test.valid {
+			assert.NoError(t, err, test.formatVersion)
+		} else {
+			assert.Error(t, err, test.formatVersion)
+		}
+	}
+}
+
+func TestDetectPlanFormat(t *testing.T) {
+	parsed := gjson.Parse(`{
+		"format_version": "1.2",
+		"terraform_version": "1.5.7",
+		"planned_values": {
+			"root_module": {
+				"child_modules": [
+					{
+						"resources": [
+							{
+								"address": "module.db.aws_db_instance.db",
+								"sensitive_values": { "tags": {}, "password": true }
+							}
+						]
+					}
+				]
+			}
+		},
+		"configuration": {
+			"root_module": {
+				"resources": [
+					{
+						"address": "aws_instance.web",
+						"postconditions": [{ "condition": {}, "error_message": {} }]
+					}
+				]
+			}
+		},
+		"checks": []
+	}`)
+
+	f := detectPlanFormat(parsed)
+	assert.Equal(t, "1.2", f.FormatVersion)
+	assert.Equal(t, "1.5.7", f.TerraformVersion)
+	assert.True(t, f.HasSensitive)
+	assert.True(t, f.HasConditions)
+	assert.True(t, f.HasChecks)
+
+	f = detectPlanFormat(gjson.Parse(`{
+		"format_version": "0.2",
+		"planned_values": {
+			"root_module": {
+				"resources": [{ "address": "aws_instance.web", "sensitive_values": { "tags": {} } }]
+			}
+		}
+	}`))
+	assert.False(t, f.HasSensitive)
+	assert.False(t, f.HasConditions)
+	assert.False(t, f.HasChecks)
+}
@@ -1,9 +1,16 @@
 package terraform
 
 import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
@@ -12,9 +19,16 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// The field number of terraform_version in Terraform's planfile protobuf message
+var planTerraformVersionField uint64 = 14
+
+// Cached plan JSON that hasn't been used for this long is removed from the cache
+const planJSONCacheTTL = 7 * 24 * time.Hour
+
 type PlanProvider struct {
 	*DirProvider
-	Path string
+	Path     string
+	cacheDir string
 }
 
 func NewPlanProvider(ctx *config.ProjectContext) schema.Provider {
@@ -23,6 +37,7 @@ func NewPlanProvider(ctx *config.ProjectContext) schema.Provider {
 	return &PlanProvider{
 		DirProvider: dirProvider,
 		Path:        ctx.ProjectConfig.Path,
+		cacheDir:    filepath.Join(config.UserCacheDir(), "plan_json"),
 	}
 }
 
@@ -54,35 +69,36 @@ func (p *PlanProvider) LoadResources(project *schema.Project, usage map[string]*
 }
 
 func (p *PlanProvider) generatePlanJSON() ([]byte, error) {
-	dir := filepath.Dir(p.Path)
-	planPath := filepath.Base(p.Path)
+	planVersion, err := planTerraformVersion(p.Path)
+	if err != nil {
+		log.Debugf("Could not read the Terraform version from plan file %s: %s", p.Path, err)
+	}
 
-	if !IsTerraformDir(dir) {
-		log.Debugf("%s is not a Terraform directory, checking current working directory", dir)
-		dir, err := os.Getwd()
+	var cachePath string
+	if !p.ctx.RunContext.Config.NoCache {
+		cachePath, err = p.cachePath(planVersion)
 		if err != nil {
-			return []byte{}, err
-		}
-		planPath = p.Path
-
-		if !IsTerraformDir(dir) {
-			return []byte{}, fmt.Errorf("%s %s.\n%s\n\n%s\n%s\n%s %s",
-				"Could not detect Terraform directory for",
-				p.Path,
-				"Either the current working directory or the plan file's parent directory must be a Terraform directory.",
-				"If the above does not work you can generate the plan JSON file with:",
-				ui.PrimaryString("terraform show -json tfplan.binary > plan.json"),
-				"and then run Infracost with",
-				ui.PrimaryString("--path=plan.json"),
-			)
+			log.Debugf("Could not generate cache path for plan file %s: %s", p.Path, err)
+		} else if j, ok := p.readCache(cachePath); ok {
+			return j, nil
 		}
 	}
 
+	dir, planPath, err := p.findTerraformDir()
+	if err != nil {
+		return []byte{}, err
+	}
+
 	if p.DirProvider != nil {
 		p.DirProvider.Path = dir
 	}
 
-	err := p.checks()
+	err = p.checks()
+	if err != nil {
+		return []byte{}, err
+	}
+
+	err = p.checkPlanVersion(planVersion)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -95,5 +111,250 @@ func (p *PlanProvider) generatePlanJSON() ([]byte, error) {
 		defer os.Remove(opts.TerraformConfigFile)
 	}
 
-	return p.runShow(opts, planPath)
+	j, err := p.runShow(opts, planPath)
+	if err != nil {
+		return j, err
+	}
+
+	if cachePath != "" {
+		p.writeCache(cachePath, j)
+	}
+
+	return j, nil
+}
+
+// findTerraformDir returns the Terraform directory to run terraform show in and the path
+// to the plan file relative to it. The plan file's parent directory is checked first, then
+// the current working directory and then the parent directories of the plan file.
+func (p *PlanProvider) findTerraformDir() (string, string, error) {
+	dir := filepath.Dir(p.Path)
+	if IsTerraformDir(dir) {
+		return dir, filepath.Base(p.Path), nil
+	}
+
+	log.Debugf("%s is not a Terraform directory, checking current working directory", dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	if IsTerraformDir(wd) {
+		return wd, p.Path, nil
+	}
+
+	absPath, err := filepath.Abs(p.Path)
+	if err == nil {
+		for d := filepath.Dir(filepath.Dir(absPath)); d != filepath.Dir(d); d = filepath.Dir(d) {
+			if IsTerraformDir(d) {
+				log.Debugf("Using parent directory %s as the Terraform directory for %s", d, p.Path)
+				return d, absPath, nil
+			}
+		}
+	}
+
+	return "", "", fmt.Errorf("%s %s.\n%s\n\n%s\n%s\n%s %s",
+		"Could not detect Terraform directory for",
+		p.Path,
+		"Either the current working directory or the plan file's parent directory must be a Terraform directory.",
+		"If the above does not work you can generate the plan JSON file with:",
+		ui.PrimaryString("terraform show -json tfplan.binary > plan.json"),
+		"and then run Infracost with",
+		ui.PrimaryString("--path=plan.json"),
+	)
+}
+
+// checkPlanVersion checks that the Terraform binary is the same version that created the plan
+// since Terraform can only read plan files it has created. If it isn't, we look for a version
+// specific Terraform binary on the PATH, e.g. terraform_1.0.0, before returning an error.
+func (p *PlanProvider) checkPlanVersion(planVersion string) error {
+	if planVersion == "" || p.terraformVersion == "" {
+		return nil
+	}
+
+	binaryVersion := strings.TrimPrefix(p.terraformVersion, "v")
+	if binaryVersion == planVersion {
+		return nil
+	}
+
+	for _, name := range []string{
+		fmt.Sprintf("terraform_%s", planVersion),
+		fmt.Sprintf("terraform-%s", planVersion),
+		fmt.Sprintf("terraform%s", planVersion),
+	} {
+		if path, err := exec.LookPath(name); err == nil {
+			log.Infof("Plan file was created by Terraform %s, using %s", planVersion, path)
+			p.TerraformBinary = path
+			p.terraformVersion = "v" + planVersion
+			p.ctx.SetContextValue("terraformVersion", p.terraformVersion)
+			return nil
+		}
+	}
+
+	return errors.Errorf("%s was created by Terraform %s but the Terraform binary \"%s\" is version %s.\n%s",
+		p.Path,
+		planVersion,
+		p.TerraformBinary,
+		binaryVersion,
+		"Set INFRACOST_TERRAFORM_BINARY to a Terraform binary with the same version as the one that created the plan.",
+	)
+}
+
+func (p *PlanProvider) cachePath(planVersion string) (string, error) {
+	b, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write(b)
+	h.Write([]byte(planVersion))
+
+	return filepath.Join(p.cacheDir, hex.EncodeToString(h.Sum(nil))+".json"), nil
+}
+
+// readCache returns the cached plan JSON unless the cache is being refreshed. Its modified
+// time is updated so plans that are still being used aren't pruned.
+func (p *PlanProvider) readCache(cachePath string) ([]byte, bool) {
+	if p.ctx.RunContext.Config.RefreshCache {
+		return nil, false
+	}
+
+	j, err := ioutil.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(cachePath, now, now)
+
+	log.Debugf("Using cached plan JSON from %s", cachePath)
+	p.ctx.SetContextValue("terraformPlanJSONCached", true)
+
+	return j, true
+}
+
+func (p *PlanProvider) writeCache(cachePath string, j []byte) {
+	err := os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err == nil {
+		err = ioutil.WriteFile(cachePath, j, 0600)
+	}
+
+	if err != nil {
+		log.Debugf("Could not cache plan JSON to %s: %s", cachePath, err)
+	}
+
+	pruneCache(filepath.Dir(cachePath), planJSONCacheTTL)
+}
+
+// pruneCache removes the files in the cache dir that haven't been modified within the TTL.
+func pruneCache(dir string, ttl time.Duration) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || time.Since(e.ModTime()) <= ttl {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if err := os.Remove(path); err != nil {
+			log.Debugf("Could not remove expired cached plan JSON %s: %s", path, err)
+		}
+	}
+}
+
+// planTerraformVersion reads the version of Terraform that created the plan file
+func planTerraformVersion(path string) (string, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.Name != "tfplan" {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+
+		return protobufStringField(b, planTerraformVersionField)
+	}
+
+	return "", errors.New("No tfplan found in plan file")
+}
+
+// protobufStringField returns the value of a top-level string field from an encoded protobuf
+// message. This avoids depending on Terraform's internal planfile protobuf definitions.
+func protobufStringField(b []byte, fieldNum uint64) (string, error) {
+	for len(b) > 0 {
+		key, n := protobufVarint(b)
+		if n == 0 {
+			return "", errors.New("Invalid protobuf field key")
+		}
+		b = b[n:]
+
+		var l uint64
+		switch key & 7 {
+		case 0:
+			_, n = protobufVarint(b)
+			if n == 0 {
+				return "", errors.New("Invalid protobuf varint")
+			}
+			l = uint64(n)
+		case 1:
+			l = 8
+		case 5:
+			l = 4
+		case 2:
+			v, n := protobufVarint(b)
+			if n == 0 {
+				return "", errors.New("Invalid protobuf length")
+			}
+			b = b[n:]
+
+			if v > uint64(len(b)) {
+				return "", errors.New("Invalid protobuf length")
+			}
+
+			if key>>3 == fieldNum {
+				return string(b[:v]), nil
+			}
+
+			l = v
+		default:
+			return "", errors.Errorf("Unsupported protobuf wire type %d", key&7)
+		}
+
+		if l > uint64(len(b)) {
+			return "", errors.New("Unexpected end of protobuf message")
+		}
+		b = b[l:]
+	}
+
+	return "", nil
+}
+
+func protobufVarint(b []byte) (uint64, int) {
+	var v uint64
+
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
 }
@@ -0,0 +1,94 @@
+package terraform
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtobufStringField(t *testing.T) {
+	msg := []byte{
+		0x08, 0x03, // field 1, varint 3
+		0x12, 0x02, 'a', 'b', // field 2, string "ab"
+		0x72, 0x05, '1', '.', '0', '.', '0', // field 14, string "1.0.0"
+	}
+
+	v, err := protobufStringField(msg, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", v)
+
+	v, err = protobufStringField(msg, 15)
+	assert.NoError(t, err)
+	assert.Equal(t, "", v)
+
+	_, err = protobufStringField([]byte{0x72, 0x05, '1'}, 14)
+	assert.Error(t, err)
+}
+
+func TestPlanTerraformVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.tfplan")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w := zip.NewWriter(f)
+	zf, err := w.Create("tfplan")
+	require.NoError(t, err)
+	_, err = zf.Write([]byte{0x08, 0x03, 0x72, 0x06, '0', '.', '1', '5', '.', '5'})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	v, err := planTerraformVersion(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.15.5", v)
+}
+
+func TestPlanProviderReadCache(t *testing.T) {
+	ctx := config.EmptyProjectContext()
+	p := &PlanProvider{DirProvider: &DirProvider{ctx: ctx}, cacheDir: t.TempDir()}
+
+	cachePath := filepath.Join(p.cacheDir, "plan.json")
+	_, ok := p.readCache(cachePath)
+	assert.False(t, ok)
+
+	old := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, os.WriteFile(cachePath, []byte(`{}`), 0600))
+	require.NoError(t, os.Chtimes(cachePath, old, old))
+
+	j, ok := p.readCache(cachePath)
+	assert.True(t, ok)
+	assert.Equal(t, `{}`, string(j))
+
+	// Reading the cache marks it as recently used
+	info, err := os.Stat(cachePath)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(old))
+
+	ctx.RunContext.Config.RefreshCache = true
+	_, ok = p.readCache(cachePath)
+	assert.False(t, ok)
+}
+
+func TestPruneCache(t *testing.T) {
+	dir := t.TempDir()
+
+	expired := filepath.Join(dir, "expired.json")
+	require.NoError(t, os.WriteFile(expired, []byte(`{}`), 0600))
+	old := time.Now().Add(-planJSONCacheTTL - time.Hour)
+	require.NoError(t, os.Chtimes(expired, old, old))
+
+	recent := filepath.Join(dir, "recent.json")
+	require.NoError(t, os.WriteFile(recent, []byte(`{}`), 0600))
+
+	pruneCache(dir, planJSONCacheTTL)
+
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, recent)
+}
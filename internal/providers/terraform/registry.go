@@ -6,9 +6,12 @@ import (
 
 	"github.com/infracost/infracost/internal/schema"
 
+	"github.com/infracost/infracost/internal/providers/terraform/alicloud"
 	"github.com/infracost/infracost/internal/providers/terraform/aws"
 	"github.com/infracost/infracost/internal/providers/terraform/azure"
 	"github.com/infracost/infracost/internal/providers/terraform/google"
+	"github.com/infracost/infracost/internal/providers/terraform/ibm"
+	"github.com/infracost/infracost/internal/providers/terraform/oci"
 )
 
 type ResourceRegistryMap map[string]*schema.RegistryItem
@@ -43,6 +46,27 @@ func GetResourceRegistryMap() *ResourceRegistryMap {
 		for _, registryItem := range createFreeResources(google.FreeResources) {
 			resourceRegistryMap[registryItem.Name] = registryItem
 		}
+
+		for _, registryItem := range alicloud.ResourceRegistry {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
+		for _, registryItem := range createFreeResources(alicloud.FreeResources) {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
+
+		for _, registryItem := range oci.ResourceRegistry {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
+		for _, registryItem := range createFreeResources(oci.FreeResources) {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
+
+		for _, registryItem := range ibm.ResourceRegistry {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
+		for _, registryItem := range createFreeResources(ibm.FreeResources) {
+			resourceRegistryMap[registryItem.Name] = registryItem
+		}
 	})
 
 	return &resourceRegistryMap
@@ -53,11 +77,22 @@ func GetUsageOnlyResources() []string {
 	r = append(r, aws.UsageOnlyResources...)
 	r = append(r, azure.UsageOnlyResources...)
 	r = append(r, google.UsageOnlyResources...)
+	r = append(r, alicloud.UsageOnlyResources...)
+	r = append(r, oci.UsageOnlyResources...)
+	r = append(r, ibm.UsageOnlyResources...)
 	return r
 }
 
+var supportedProviderPrefixes = []string{"aws_", "google_", "azurerm_", "alicloud_", "oci_", "ibm_"}
+
 func HasSupportedProvider(rType string) bool {
-	return strings.HasPrefix(rType, "aws_") || strings.HasPrefix(rType, "google_") || strings.HasPrefix(rType, "azurerm_")
+	for _, prefix := range supportedProviderPrefixes {
+		if strings.HasPrefix(rType, prefix) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func createFreeResources(l []string) []*schema.RegistryItem {
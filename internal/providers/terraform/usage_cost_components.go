@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/tidwall/gjson"
+)
+
+// usageCostComponents returns the names of the cost components that each usage key the
+// resource reads but has no value for affects. The keys are all set to 1, since a key can
+// only affect a cost component once another is set, e.g. the duration of a function once
+// its requests are, and then the resource is created again with each key set to 2 and the
+// quantities of its cost components are compared.
+func (p *Parser) usageCostComponents(d *schema.ResourceData, u *schema.UsageData) map[string][]string {
+	if u == nil {
+		u = schema.NewUsageData(d.Address, map[string]gjson.Result{})
+	}
+
+	// The keys the resource reads are recorded as it's created
+	p.createResource(d, u)
+	keys := missingUsageKeys(u, u.AccessedKeys())
+
+	base := u
+	for _, k := range keys {
+		base = base.WithValue(k, 1)
+	}
+	baseResource := p.createResource(d, base)
+	keys = missingUsageKeys(u, append(keys, base.AccessedKeys()...))
+
+	components := make(map[string][]string)
+	for _, k := range keys {
+		probe := p.createResource(d, base.WithValue(k, 2))
+		if names := schema.ChangedCostComponents(baseResource, probe); len(names) > 0 {
+			components[k] = names
+		}
+	}
+
+	return components
+}
+
+// missingUsageKeys returns the keys that don't have a value in the usage data, without
+// duplicates.
+func missingUsageKeys(u *schema.UsageData, keys []string) []string {
+	missing := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+
+	for _, k := range keys {
+		if seen[k] || u.Attributes[k].Type != gjson.Null {
+			continue
+		}
+
+		seen[k] = true
+		missing = append(missing, k)
+	}
+
+	return missing
+}
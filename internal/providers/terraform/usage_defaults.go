@@ -0,0 +1,142 @@
+package terraform
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/tidwall/gjson"
+)
+
+// Resource types that support the reserved_instance_* usage keys. The Azure resources
+// only use reserved_instance_term.
+var reservedInstanceResourceTypes = []string{
+	"aws_instance",
+	"aws_autoscaling_group",
+	"aws_eks_node_group",
+	"aws_db_instance",
+	"aws_elasticache_cluster",
+	"aws_elasticache_replication_group",
+	"aws_redshift_cluster",
+	"azurerm_linux_virtual_machine",
+	"azurerm_windows_virtual_machine",
+	"azurerm_virtual_machine",
+	"azurerm_linux_virtual_machine_scale_set",
+	"azurerm_windows_virtual_machine_scale_set",
+	"azurerm_virtual_machine_scale_set",
+	"azurerm_kubernetes_cluster",
+	"azurerm_kubernetes_cluster_node_pool",
+	"azurerm_mssql_database",
+}
+
+// Resource types that support the azure_hybrid_benefit usage key.
+var hybridBenefitResourceTypes = []string{
+	"azurerm_windows_virtual_machine",
+	"azurerm_virtual_machine",
+	"azurerm_windows_virtual_machine_scale_set",
+	"azurerm_virtual_machine_scale_set",
+	"azurerm_mssql_database",
+}
+
+// Resource types that support the operating_schedule usage key.
+var operatingScheduleResourceTypes = []string{
+	"aws_instance",
+	"aws_db_instance",
+	"google_compute_instance",
+	"azurerm_linux_virtual_machine",
+	"azurerm_windows_virtual_machine",
+	"azurerm_virtual_machine",
+}
+
+// withUsageDefaults returns the usage data of the resource with the reserved_instances,
+// azure_hybrid_benefit and operating_schedules options of the project in the config file
+// added, so all the resources of the project can be priced with them without listing them
+// in the usage file. The usage keys that are set for the resource in the usage file are
+// kept.
+func (p *Parser) withUsageDefaults(d *schema.ResourceData, u *schema.UsageData) *schema.UsageData {
+	defaults := map[string]interface{}{}
+
+	if ri := p.ctx.ProjectConfig.ReservedInstances; ri != nil && containsString(reservedInstanceResourceTypes, d.Type) {
+		for k, v := range map[string]string{
+			"reserved_instance_type":           ri.Type,
+			"reserved_instance_term":           ri.Term,
+			"reserved_instance_payment_option": ri.PaymentOption,
+		} {
+			if v != "" {
+				defaults[k] = v
+			}
+		}
+	}
+
+	if p.ctx.ProjectConfig.AzureHybridBenefit && containsString(hybridBenefitResourceTypes, d.Type) {
+		defaults["azure_hybrid_benefit"] = true
+	}
+
+	if containsString(operatingScheduleResourceTypes, d.Type) {
+		schedule := ""
+		if u != nil {
+			schedule = u.Get("operating_schedule").String()
+		}
+		if schedule == "" {
+			schedule = p.operatingScheduleForTags(d.Tags)
+		}
+
+		if schedule != "" {
+			defaults["operating_schedule"] = schedule
+
+			// Compute Engine instances use their monthly hours for the sustained use discount
+			if d.Type == "google_compute_instance" {
+				if s, err := schema.ParseOperatingSchedule(schedule); err == nil {
+					monthlyHours, _ := s.MonthlyHours().Round(2).Float64()
+					defaults["monthly_hrs"] = monthlyHours
+				}
+			}
+		}
+	}
+
+	if len(defaults) == 0 {
+		return u
+	}
+
+	address := d.Address
+	attributes := make(map[string]gjson.Result)
+	if u != nil {
+		address = u.Address
+		for k, v := range u.Attributes {
+			attributes[k] = v
+		}
+	}
+
+	for k, v := range schema.ParseAttributes(defaults) {
+		if attributes[k].Type == gjson.Null {
+			attributes[k] = v
+		}
+	}
+
+	merged := schema.NewUsageData(address, attributes)
+	if u != nil {
+		for k := range u.Attributes {
+			merged.CopyKey(u, k)
+		}
+	}
+
+	return merged
+}
+
+// operatingScheduleForTags returns the schedule of the first of the project's operating
+// schedules whose tags the resource has, or an empty string if none of them match.
+func (p *Parser) operatingScheduleForTags(tags map[string]string) string {
+	for _, s := range p.ctx.ProjectConfig.OperatingSchedules {
+		matches := true
+		for k, v := range s.Tags {
+			if tags[k] != v {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return s.Schedule
+		}
+	}
+
+	return ""
+}
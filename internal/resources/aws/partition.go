@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The AWS partitions that have separate regions, accounts and price lists.
+const (
+	PartitionStandard = "aws"
+	PartitionChina    = "aws-cn"
+	PartitionGovCloud = "aws-us-gov"
+)
+
+var regionRegex = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-\d+$`)
+
+// ARN is the parts of an Amazon Resource Name that are used for pricing.
+type ARN struct {
+	Partition string
+	Service   string
+	Region    string
+	Account   string
+}
+
+// ParseARN parses an ARN of the format arn:partition:service:region:account-id:resource.
+// The region and account are empty for global resources, e.g. IAM roles.
+func ParseARN(arn string) (ARN, error) {
+	p := strings.SplitN(arn, ":", 6)
+	if len(p) < 6 || p[0] != "arn" {
+		return ARN{}, fmt.Errorf("Invalid ARN %s", arn)
+	}
+
+	a := ARN{
+		Partition: p[1],
+		Service:   p[2],
+		Region:    p[3],
+		Account:   p[4],
+	}
+
+	if a.Partition != PartitionStandard && a.Partition != PartitionChina && a.Partition != PartitionGovCloud {
+		return ARN{}, fmt.Errorf("Unsupported partition %s in ARN %s", a.Partition, arn)
+	}
+
+	if a.Region != "" && RegionPartition(a.Region) != a.Partition {
+		return ARN{}, fmt.Errorf("Region %s is not in partition %s in ARN %s", a.Region, a.Partition, arn)
+	}
+
+	return a, nil
+}
+
+// RegionPartition returns the partition of the region, e.g. aws-us-gov for us-gov-west-1.
+func RegionPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionChina
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionGovCloud
+	default:
+		return PartitionStandard
+	}
+}
+
+// ValidateRegion returns an error if the region isn't the name of an AWS region, so the
+// resource isn't priced with the prices of the wrong region.
+func ValidateRegion(region string) error {
+	if !regionRegex.MatchString(region) {
+		return fmt.Errorf("Invalid AWS region %s", region)
+	}
+
+	return nil
+}
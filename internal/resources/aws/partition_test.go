@@ -0,0 +1,43 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseARN(t *testing.T) {
+	arn, err := ParseARN("arn:aws-us-gov:rds:us-gov-east-1:123456789012:db:mydb")
+	require.NoError(t, err)
+	assert.Equal(t, ARN{Partition: PartitionGovCloud, Service: "rds", Region: "us-gov-east-1", Account: "123456789012"}, arn)
+
+	arn, err = ParseARN("arn:aws-cn:iam::123456789012:role/infracost")
+	require.NoError(t, err)
+	assert.Equal(t, ARN{Partition: PartitionChina, Service: "iam", Account: "123456789012"}, arn)
+
+	_, err = ParseARN("arn:aws:ec2:us-gov-west-1:123456789012:instance/i-1")
+	assert.EqualError(t, err, "Region us-gov-west-1 is not in partition aws in ARN arn:aws:ec2:us-gov-west-1:123456789012:instance/i-1")
+
+	_, err = ParseARN("arn:aws-iso:ec2:us-iso-east-1:123456789012:instance/i-1")
+	assert.EqualError(t, err, "Unsupported partition aws-iso in ARN arn:aws-iso:ec2:us-iso-east-1:123456789012:instance/i-1")
+
+	_, err = ParseARN("i-1234")
+	assert.Error(t, err)
+}
+
+func TestRegionPartition(t *testing.T) {
+	assert.Equal(t, PartitionStandard, RegionPartition("us-east-1"))
+	assert.Equal(t, PartitionGovCloud, RegionPartition("us-gov-west-1"))
+	assert.Equal(t, PartitionChina, RegionPartition("cn-northwest-1"))
+}
+
+func TestValidateRegion(t *testing.T) {
+	for _, region := range []string{"us-east-1", "ap-southeast-5", "us-gov-west-1", "cn-north-1", "il-central-1"} {
+		assert.NoError(t, ValidateRegion(region), region)
+	}
+
+	for _, region := range []string{"us-east", "US-EAST-1", "eastus", "${var.region}"} {
+		assert.Error(t, ValidateRegion(region), region)
+	}
+}
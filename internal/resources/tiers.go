@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+)
+
+// Tier is one tier of a graduated price, e.g. "next 40TB". EndUsageAmount is the total
+// usage that the tier ends at, not the size of the tier, since that's how the Cloud Pricing
+// API prices are filtered. The last tier has an EndUsageAmount of 0 as it has no end.
+type Tier struct {
+	Name           string
+	EndUsageAmount int64
+}
+
+// EndUsageAmountFilter returns the value of the EndUsageAmount price filter for the tier.
+func (t Tier) EndUsageAmountFilter() string {
+	if t.EndUsageAmount == 0 {
+		return "Inf"
+	}
+
+	return fmt.Sprint(t.EndUsageAmount)
+}
+
+// TierQuantity is the part of a quantity that falls in a tier. StartUsageAmount is the
+// total usage that the tier starts at, which is the end of the previous tier.
+type TierQuantity struct {
+	Tier
+	StartUsageAmount int64
+	Quantity         *decimal.Decimal
+}
+
+// StartUsageAmountFilter returns the value of the StartUsageAmount price filter for the tier.
+func (t TierQuantity) StartUsageAmountFilter() string {
+	return fmt.Sprint(t.StartUsageAmount)
+}
+
+// SplitTiers splits the quantity over the tiers, filling each tier before the next one.
+// Tiers that none of the quantity falls in are left out, except the first tier which is
+// always returned so the price is still shown when there's no usage. If the quantity is
+// nil the first tier is returned with a nil quantity.
+func SplitTiers(quantity *decimal.Decimal, tiers []Tier) []TierQuantity {
+	if len(tiers) == 0 {
+		return []TierQuantity{}
+	}
+
+	if quantity == nil {
+		return []TierQuantity{{Tier: tiers[0]}}
+	}
+
+	result := make([]TierQuantity, 0, len(tiers))
+	remaining := *quantity
+	var start int64
+
+	for i, t := range tiers {
+		q := remaining
+		if t.EndUsageAmount != 0 {
+			q = decimal.Min(remaining, decimal.NewFromInt(t.EndUsageAmount-start))
+		}
+
+		if i > 0 && !q.IsPositive() {
+			break
+		}
+
+		result = append(result, TierQuantity{Tier: t, StartUsageAmount: start, Quantity: decimalPtr(q)})
+
+		remaining = remaining.Sub(q)
+		start = t.EndUsageAmount
+	}
+
+	return result
+}
+
+// TieredCostComponents returns a cost component for each tier that the quantity is split
+// over by SplitTiers. The costComponent function builds the cost component of a tier, and
+// should use the StartUsageAmountFilter or EndUsageAmountFilter of the tier in its price
+// filter, depending on which the prices of the service have.
+func TieredCostComponents(quantity *decimal.Decimal, tiers []Tier, costComponent func(t TierQuantity) *schema.CostComponent) []*schema.CostComponent {
+	tierQuantities := SplitTiers(quantity, tiers)
+
+	costComponents := make([]*schema.CostComponent, 0, len(tierQuantities))
+	for _, t := range tierQuantities {
+		costComponents = append(costComponents, costComponent(t))
+	}
+
+	return costComponents
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
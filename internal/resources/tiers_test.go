@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTiers(t *testing.T) {
+	tiers := []Tier{
+		{Name: "first 10TB", EndUsageAmount: 10240},
+		{Name: "next 40TB", EndUsageAmount: 51200},
+		{Name: "next 100TB", EndUsageAmount: 153600},
+		{Name: "over 150TB"},
+	}
+
+	tests := []struct {
+		quantity *decimal.Decimal
+		expected map[string]string
+	}{
+		{quantity: nil, expected: map[string]string{"first 10TB": ""}},
+		{quantity: decimalPtr(decimal.Zero), expected: map[string]string{"first 10TB": "0"}},
+		{quantity: decimalPtr(decimal.NewFromInt(5000)), expected: map[string]string{"first 10TB": "5000"}},
+		{quantity: decimalPtr(decimal.NewFromInt(10240)), expected: map[string]string{"first 10TB": "10240"}},
+		{quantity: decimalPtr(decimal.NewFromInt(60000)), expected: map[string]string{"first 10TB": "10240", "next 40TB": "40960", "next 100TB": "8800"}},
+		{quantity: decimalPtr(decimal.NewFromInt(200000)), expected: map[string]string{"first 10TB": "10240", "next 40TB": "40960", "next 100TB": "102400", "over 150TB": "46400"}},
+	}
+
+	for _, test := range tests {
+		actual := make(map[string]string)
+		for _, tq := range SplitTiers(test.quantity, tiers) {
+			actual[tq.Name] = ""
+			if tq.Quantity != nil {
+				actual[tq.Name] = tq.Quantity.String()
+			}
+		}
+
+		assert.Equal(t, test.expected, actual)
+	}
+}
+
+func TestTieredCostComponents(t *testing.T) {
+	tiers := []Tier{
+		{Name: "first 1M", EndUsageAmount: 1000000},
+		{Name: "over 1M"},
+	}
+
+	components := TieredCostComponents(decimalPtr(decimal.NewFromInt(1500000)), tiers, func(tier TierQuantity) *schema.CostComponent {
+		return &schema.CostComponent{
+			Name:            tier.Name,
+			MonthlyQuantity: tier.Quantity,
+			PriceFilter: &schema.PriceFilter{
+				StartUsageAmount: strPtr(tier.StartUsageAmountFilter()),
+				EndUsageAmount:   strPtr(tier.EndUsageAmountFilter()),
+			},
+		}
+	})
+
+	assert.Len(t, components, 2)
+	assert.Equal(t, "first 1M", components[0].Name)
+	assert.Equal(t, "0", *components[0].PriceFilter.StartUsageAmount)
+	assert.Equal(t, "1000000", *components[0].PriceFilter.EndUsageAmount)
+	assert.Equal(t, "1000000", components[0].MonthlyQuantity.String())
+	assert.Equal(t, "over 1M", components[1].Name)
+	assert.Equal(t, "1000000", *components[1].PriceFilter.StartUsageAmount)
+	assert.Equal(t, "Inf", *components[1].PriceFilter.EndUsageAmount)
+	assert.Equal(t, "500000", components[1].MonthlyQuantity.String())
+}
+
+func strPtr(s string) *string {
+	return &s
+}
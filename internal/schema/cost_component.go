@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -14,10 +16,16 @@ type CostComponent struct {
 	HourlyQuantity       *decimal.Decimal
 	MonthlyQuantity      *decimal.Decimal
 	MonthlyDiscountPerc  float64
+	UsageProvenance      UsageProvenance
 	price                decimal.Decimal
 	priceHash            string
+	priceEffectiveDate   *time.Time
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+
+	// PurchaseOption is how the compute capacity is bought, e.g. on_demand, spot,
+	// preemptible or reserved. It is only set for compute cost components.
+	PurchaseOption string
 }
 
 func (c *CostComponent) CalculateCosts() {
@@ -55,6 +63,16 @@ func (c *CostComponent) PriceHash() string {
 	return c.priceHash
 }
 
+// SetPriceEffectiveDate sets the date that the price from the Cloud Pricing API has been
+// effective since, which is used to tell if the price might be stale.
+func (c *CostComponent) SetPriceEffectiveDate(t *time.Time) {
+	c.priceEffectiveDate = t
+}
+
+func (c *CostComponent) PriceEffectiveDate() *time.Time {
+	return c.priceEffectiveDate
+}
+
 func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	return c.Price().Mul(c.UnitMultiplier)
 }
@@ -7,7 +7,58 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// CalculateDiff calculates the diff of past and current resources
+// DiffResources calculates the diff between two arbitrary sets of resources.
+// The costs of both sets of resources should already have been calculated.
+func DiffResources(past []*Resource, current []*Resource) []*Resource {
+	return calculateDiff(past, current)
+}
+
+// DiffProjects calculates the diff between two sets of projects, for example
+// projects loaded from a live scan and from a Terraform plan, or from two branches.
+// Projects are matched by name. The returned projects have their past resources set
+// from the matching past project and their resources set from the matching current
+// project. A project that only exists in one of the sets is diffed against an empty project.
+func DiffProjects(past []*Project, current []*Project) []*Project {
+	pastMap := make(map[string]*Project, len(past))
+	for _, p := range past {
+		pastMap[p.Name] = p
+	}
+
+	result := make([]*Project, 0, len(current))
+	seen := make(map[string]bool, len(current))
+
+	for _, c := range current {
+		seen[c.Name] = true
+
+		var pastResources []*Resource
+		if p, ok := pastMap[c.Name]; ok {
+			pastResources = p.Resources
+		}
+
+		result = append(result, newDiffProject(c.Name, c.Metadata, pastResources, c.Resources))
+	}
+
+	for _, p := range past {
+		if seen[p.Name] {
+			continue
+		}
+
+		result = append(result, newDiffProject(p.Name, p.Metadata, p.Resources, []*Resource{}))
+	}
+
+	return result
+}
+
+func newDiffProject(name string, metadata *ProjectMetadata, past []*Resource, current []*Resource) *Project {
+	p := NewProject(name, metadata)
+	p.PastResources = past
+	p.Resources = current
+	p.CalculateDiff()
+
+	return p
+}
+
+// calculateDiff calculates the diff of past and current resources
 func calculateDiff(past []*Resource, current []*Resource) []*Resource {
 	// There are many ways to calculate a diff between two sets of
 	// nested objects. The method used here is to create a nested
@@ -78,6 +129,7 @@ func diffResourcesByKey(resourceKey string, pastResMap, currentResMap map[string
 		SkipMessage:  baseResource.SkipMessage,
 		ResourceType: baseResource.ResourceType,
 		Tags:         baseResource.Tags,
+		Metadata:     baseResource.Metadata,
 
 		HourlyCost:  diffDecimals(current.HourlyCost, past.HourlyCost),
 		MonthlyCost: diffDecimals(current.MonthlyCost, past.MonthlyCost),
@@ -172,6 +224,8 @@ func diffCostComponentsByKey(key string, pastCCMap, currentCCMap map[string]*Cos
 		ProductFilter:        baseCostComponent.ProductFilter,
 		PriceFilter:          baseCostComponent.PriceFilter,
 		priceHash:            baseCostComponent.priceHash,
+		UsageProvenance:      baseCostComponent.UsageProvenance,
+		PurchaseOption:       baseCostComponent.PurchaseOption,
 
 		HourlyQuantity:      diffDecimals(current.HourlyQuantity, past.HourlyQuantity),
 		MonthlyQuantity:     diffDecimals(current.MonthlyQuantity, past.MonthlyQuantity),
@@ -289,3 +289,43 @@ func TestDiffCostComponentsByKey_bothNil(t *testing.T) {
 	changed, _ := diffCostComponentsByKey("random_resource", emptyRMap, emptyRMap)
 	assert.Equal(t, false, changed)
 }
+
+func TestDiffProjects(t *testing.T) {
+	rs1 := func(cost int64) *Resource {
+		return &Resource{
+			Name:        "rs1",
+			MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+			CostComponents: []*CostComponent{
+				{
+					Name:        "cc1",
+					MonthlyCost: decimalPtr(decimal.NewFromInt(cost)),
+				},
+			},
+		}
+	}
+
+	past := []*Project{
+		{Name: "proj1", Resources: []*Resource{rs1(10)}},
+		{Name: "proj2", Resources: []*Resource{rs1(5)}},
+	}
+	current := []*Project{
+		{Name: "proj1", Resources: []*Resource{rs1(30)}},
+		{Name: "proj3", Resources: []*Resource{rs1(7)}},
+	}
+
+	diff := DiffProjects(past, current)
+
+	assert.Len(t, diff, 3)
+
+	assert.Equal(t, "proj1", diff[0].Name)
+	assert.Len(t, diff[0].Diff, 1)
+	assert.Equal(t, decimalPtr(decimal.NewFromInt(20)), diff[0].Diff[0].MonthlyCost)
+
+	assert.Equal(t, "proj3", diff[1].Name)
+	assert.Empty(t, diff[1].PastResources)
+	assert.Equal(t, decimalPtr(decimal.NewFromInt(7)), diff[1].Diff[0].MonthlyCost)
+
+	assert.Equal(t, "proj2", diff[2].Name)
+	assert.Empty(t, diff[2].Resources)
+	assert.Equal(t, decimalPtr(decimal.NewFromInt(-5)), diff[2].Diff[0].MonthlyCost)
+}
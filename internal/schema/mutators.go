@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ResourceMutator adjusts a resource in place, e.g. to force an organization's instance
+// families or to add the cost of mandatory backups. Returning an error stops the run.
+type ResourceMutator func(*Resource) error
+
+var prePricingMutators []ResourceMutator
+var postPricingMutators []ResourceMutator
+
+// RegisterPrePricingMutator adds a mutator that is run on each resource after the resources
+// are parsed and before their prices are fetched, so changes to the cost components'
+// product and price filters are used when pricing the resource.
+func RegisterPrePricingMutator(m ResourceMutator) {
+	prePricingMutators = append(prePricingMutators, m)
+}
+
+// RegisterPostPricingMutator adds a mutator that is run on each resource after its prices
+// are fetched and its costs are calculated. The costs are calculated again afterwards so
+// any changes to the quantities or prices are included in the totals.
+func RegisterPostPricingMutator(m ResourceMutator) {
+	postPricingMutators = append(postPricingMutators, m)
+}
+
+// ResetMutators removes all the registered mutators
+func ResetMutators() {
+	prePricingMutators = nil
+	postPricingMutators = nil
+}
+
+// RunPrePricingMutators runs the pre-pricing mutators on the past and current resources of the project
+func RunPrePricingMutators(project *Project) error {
+	return runMutators(prePricingMutators, project)
+}
+
+// RunPostPricingMutators runs the post-pricing mutators on the past and current resources of the
+// project and recalculates the costs of the project.
+func RunPostPricingMutators(project *Project) error {
+	if len(postPricingMutators) == 0 {
+		return nil
+	}
+
+	err := runMutators(postPricingMutators, project)
+	if err != nil {
+		return err
+	}
+
+	CalculateCosts(project)
+
+	return nil
+}
+
+func runMutators(mutators []ResourceMutator, project *Project) error {
+	for _, m := range mutators {
+		for _, r := range project.AllResources() {
+			if err := m(r); err != nil {
+				return errors.Wrapf(err, "Error running resource mutator on %s", r.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMutators(t *testing.T) {
+	defer ResetMutators()
+
+	instanceType := "m5.large"
+	project := &Project{
+		Resources: []*Resource{
+			{
+				Name: "aws_instance.web",
+				CostComponents: []*CostComponent{
+					{
+						Name:           "Instance usage",
+						HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+						ProductFilter: &ProductFilter{
+							AttributeFilters: []*AttributeFilter{{Key: "instanceType", Value: &instanceType}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	RegisterPrePricingMutator(func(r *Resource) error {
+		for _, c := range r.CostComponents {
+			forced := "m6i.large"
+			c.ProductFilter.AttributeFilters[0].Value = &forced
+		}
+		return nil
+	})
+
+	RegisterPostPricingMutator(func(r *Resource) error {
+		backup := &CostComponent{
+			Name:            "Mandatory backup",
+			MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		}
+		backup.SetPrice(decimal.NewFromInt(5))
+		r.CostComponents = append(r.CostComponents, backup)
+		return nil
+	})
+
+	require.NoError(t, RunPrePricingMutators(project))
+	assert.Equal(t, "m6i.large", *project.Resources[0].CostComponents[0].ProductFilter.AttributeFilters[0].Value)
+
+	project.Resources[0].CostComponents[0].SetPrice(decimal.NewFromFloat(0.1))
+	CalculateCosts(project)
+
+	require.NoError(t, RunPostPricingMutators(project))
+	assert.Len(t, project.Resources[0].CostComponents, 2)
+	assert.Equal(t, "78", project.Resources[0].MonthlyCost.String())
+}
+
+func TestRunMutatorsError(t *testing.T) {
+	defer ResetMutators()
+
+	RegisterPrePricingMutator(func(r *Resource) error {
+		return errors.New("not allowed")
+	})
+
+	err := RunPrePricingMutators(&Project{Resources: []*Resource{{Name: "aws_instance.web"}}})
+	assert.EqualError(t, err, "Error running resource mutator on aws_instance.web: not allowed")
+}
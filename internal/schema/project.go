@@ -14,12 +14,18 @@ import (
 )
 
 type ProjectMetadata struct {
-	Path               string `json:"path"`
-	Type               string `json:"type"`
-	VCSRepoURL         string `json:"vcsRepoUrl,omitempty"`
-	VCSSubPath         string `json:"vcsSubPath,omitempty"`
-	VCSPullRequestURL  string `json:"vcsPullRequestUrl,omitempty"`
-	TerraformWorkspace string `json:"terraformWorkspace,omitempty"`
+	Path               string   `json:"path"`
+	Type               string   `json:"type"`
+	VCSRepoURL         string   `json:"vcsRepoUrl,omitempty"`
+	VCSSubPath         string   `json:"vcsSubPath,omitempty"`
+	VCSPullRequestURL  string   `json:"vcsPullRequestUrl,omitempty"`
+	TerraformWorkspace string   `json:"terraformWorkspace,omitempty"`
+	TerraformVarFiles  []string `json:"terraformVarFiles,omitempty"`
+	TerraformVarNames  []string `json:"terraformVarNames,omitempty"`
+	DetectionReason    string   `json:"detectionReason,omitempty"`
+	// UsageProfile is the built-in usage profile that was used for the resources without
+	// usage in the usage file
+	UsageProfile string `json:"usageProfile,omitempty"`
 }
 
 // Project contains the existing, planned state of
@@ -31,6 +37,10 @@ type Project struct {
 	Resources     []*Resource
 	Diff          []*Resource
 	HasDiff       bool
+	Sampling      *Sampling
+	SavingsPlans  []*SavingsPlanCoverage
+	// CommittedUseDiscounts is the coverage of the GCP commitments applied to the project
+	CommittedUseDiscounts []*CommittedUseDiscountCoverage
 }
 
 func NewProject(name string, metadata *ProjectMetadata) *Project {
@@ -49,6 +59,57 @@ func (p *Project) AllResources() []*Resource {
 	return resources
 }
 
+// UsageRangeProjects returns projects of the project's resources with the ones that have
+// usage ranges created with the low and high values of their ranges, so they can be priced
+// like the project. The other resources are copied, so savings plans and commitments are
+// allocated across all of the project's resources. They must be called before the project
+// is priced, and they're nil if none of the project's resources have usage ranges.
+func (p *Project) UsageRangeProjects() (*Project, *Project) {
+	hasRanges := false
+	low := make([]*Resource, 0, len(p.Resources))
+	high := make([]*Resource, 0, len(p.Resources))
+
+	for _, r := range p.Resources {
+		if r.UsageRange != nil {
+			hasRanges = true
+			low = append(low, r.UsageRange.Low)
+			high = append(high, r.UsageRange.High)
+			continue
+		}
+
+		low = append(low, r.copy())
+		high = append(high, r.copy())
+	}
+
+	if !hasRanges {
+		return nil, nil
+	}
+
+	return &Project{Name: p.Name, Metadata: p.Metadata, Resources: low},
+		&Project{Name: p.Name, Metadata: p.Metadata, Resources: high}
+}
+
+// UsageSensitivityProjects returns projects of the resources that were created with each
+// of their usage keys lowered and raised, so they can be priced like the project. Unlike the
+// usage range projects they only have the variants, since each resource has a variant for
+// each of its usage keys. They're nil if none of the project's resources have them.
+func (p *Project) UsageSensitivityProjects() (*Project, *Project) {
+	var low, high []*Resource
+	for _, r := range p.Resources {
+		for _, v := range r.UsageSensitivity {
+			low = append(low, v.Low)
+			high = append(high, v.High)
+		}
+	}
+
+	if len(low) == 0 {
+		return nil, nil
+	}
+
+	return &Project{Name: p.Name, Metadata: p.Metadata, Resources: low},
+		&Project{Name: p.Name, Metadata: p.Metadata, Resources: high}
+}
+
 // CalculateDiff calculates the diff of past and current resources
 func (p *Project) CalculateDiff() {
 	if p.HasDiff {
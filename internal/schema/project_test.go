@@ -26,3 +26,33 @@ func TestNameFromRepoURL(t *testing.T) {
 		assert.Equal(t, test.name, actual)
 	}
 }
+
+func TestUsageRangeProjects(t *testing.T) {
+	ranged := &Resource{
+		Name: "aws_lambda_function.ranged",
+		UsageRange: &ResourceUsageRange{
+			Low:  &Resource{Name: "aws_lambda_function.ranged"},
+			High: &Resource{Name: "aws_lambda_function.ranged"},
+		},
+	}
+	fixed := &Resource{
+		Name:           "aws_instance.fixed",
+		CostComponents: []*CostComponent{{Name: "Instance usage"}},
+	}
+
+	p := &Project{Name: "project", Resources: []*Resource{ranged, fixed}}
+	low, high := p.UsageRangeProjects()
+
+	assert.Equal(t, []*Resource{ranged.UsageRange.Low, low.Resources[1]}, low.Resources)
+	assert.Equal(t, []*Resource{ranged.UsageRange.High, high.Resources[1]}, high.Resources)
+
+	// The resources without ranges are copied so pricing the variants doesn't change them
+	assert.NotSame(t, fixed, low.Resources[1])
+	assert.NotSame(t, fixed.CostComponents[0], low.Resources[1].CostComponents[0])
+	assert.NotSame(t, low.Resources[1], high.Resources[1])
+	assert.Equal(t, "aws_instance.fixed", low.Resources[1].Name)
+
+	low, high = (&Project{Resources: []*Resource{fixed}}).UsageRangeProjects()
+	assert.Nil(t, low)
+	assert.Nil(t, high)
+}
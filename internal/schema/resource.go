@@ -21,13 +21,85 @@ type Resource struct {
 	SkipMessage    string
 	ResourceType   string
 	Tags           map[string]string
+	Metadata       map[string]string
 	UsageSchema    []*UsageSchemaItem
+	Warnings       []*Warning
+	// Usage is the values of the usage keys the resource was priced with, so they can be
+	// replayed in a later run.
+	Usage map[string]interface{}
+	// UsageRange is the resource created with the low and high values of the ranges in its
+	// usage, or nil if its usage doesn't have any ranges.
+	UsageRange *ResourceUsageRange
+	// UsageSensitivity is the resource created with each of the number usage keys lowered
+	// and raised, keyed by the usage key. It's only set by infracost sensitivity.
+	UsageSensitivity map[string]*ResourceUsageRange
+	// UsageCostComponents is the names of the cost components that each of the usage keys
+	// without a value affects. It's only set when the usage file is synced, so the keys
+	// can be documented.
+	UsageCostComponents map[string][]string
+}
+
+// ResourceUsageRange is a resource created with the low and high values of its usage ranges,
+// which are priced separately to get the range of the resource's cost.
+type ResourceUsageRange struct {
+	Low  *Resource
+	High *Resource
+}
+
+// Warning is a problem with the costs of a resource that didn't stop it from being
+// priced, e.g. a price that had to be taken from a different region.
+type Warning struct {
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	CostComponent string `json:"costComponent,omitempty"`
+}
+
+const (
+	WarningRegionFallback = "region_fallback"
+	WarningPriceNotFound  = "price_not_found"
+	WarningStalePrice     = "stale_price"
+)
+
+// copy returns a copy of the resource and its cost components that can be priced separately
+// from it. It's only used for unpriced resources, so the costs and warnings aren't copied.
+func (r *Resource) copy() *Resource {
+	c := *r
+	c.HourlyCost = nil
+	c.MonthlyCost = nil
+	c.Warnings = nil
+	c.UsageRange = nil
+	c.UsageSensitivity = nil
+
+	c.CostComponents = make([]*CostComponent, 0, len(r.CostComponents))
+	for _, cc := range r.CostComponents {
+		ccCopy := *cc
+		c.CostComponents = append(c.CostComponents, &ccCopy)
+	}
+
+	c.SubResources = make([]*Resource, 0, len(r.SubResources))
+	for _, s := range r.SubResources {
+		c.SubResources = append(c.SubResources, s.copy())
+	}
+
+	return &c
+}
+
+func (r *Resource) AddWarning(code string, costComponent string, message string) {
+	r.Warnings = append(r.Warnings, &Warning{
+		Code:          code,
+		Message:       message,
+		CostComponent: costComponent,
+	})
 }
 
 func CalculateCosts(project *Project) {
 	for _, r := range project.AllResources() {
 		r.CalculateCosts()
 	}
+
+	if project.Sampling != nil {
+		project.Sampling.calculateEstimates(project.Resources)
+	}
 }
 
 func (r *Resource) CalculateCosts() {
@@ -116,3 +188,44 @@ func MultiplyQuantities(resource *Resource, multiplier decimal.Decimal) {
 func decimalPtr(d decimal.Decimal) *decimal.Decimal {
 	return &d
 }
+
+// ChangedCostComponents returns the names of the cost components of the resource and its
+// sub resources whose quantities are different in the other resource, or that are only in
+// the other resource.
+func ChangedCostComponents(r *Resource, other *Resource) []string {
+	quantities := make(map[string][2]*decimal.Decimal)
+	for _, c := range allCostComponents(r) {
+		quantities[c.Name] = [2]*decimal.Decimal{c.HourlyQuantity, c.MonthlyQuantity}
+	}
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, c := range allCostComponents(other) {
+		q, ok := quantities[c.Name]
+		if seen[c.Name] || (ok && decimalsEqual(q[0], c.HourlyQuantity) && decimalsEqual(q[1], c.MonthlyQuantity)) {
+			continue
+		}
+
+		seen[c.Name] = true
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+func allCostComponents(r *Resource) []*CostComponent {
+	components := append([]*CostComponent{}, r.CostComponents...)
+	for _, s := range r.SubResources {
+		components = append(components, allCostComponents(s)...)
+	}
+
+	return components
+}
+
+func decimalsEqual(d1 *decimal.Decimal, d2 *decimal.Decimal) bool {
+	if d1 == nil || d2 == nil {
+		return d1 == d2
+	}
+
+	return d1.Equal(*d2)
+}
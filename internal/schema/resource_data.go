@@ -12,6 +12,7 @@ type ResourceData struct {
 	ProviderName  string
 	Address       string
 	Tags          map[string]string
+	Metadata      map[string]string
 	RawValues     gjson.Result
 	referencesMap map[string][]*ResourceData
 	CFResource    cloudformation.Resource
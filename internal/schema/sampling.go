@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// sampleConfidenceZ is the z-score used for the sampled estimate confidence intervals (95%)
+var sampleConfidenceZ = 1.96
+
+// SampledResourceType contains the extrapolated costs for a single resource type
+// when only a subset of its resources were priced.
+type SampledResourceType struct {
+	ResourceType         string           `json:"resourceType"`
+	TotalCount           int              `json:"totalCount"`
+	SampleCount          int              `json:"sampleCount"`
+	EstimatedMonthlyCost *decimal.Decimal `json:"estimatedMonthlyCost"`
+	LowerMonthlyCost     *decimal.Decimal `json:"lowerMonthlyCost"`
+	UpperMonthlyCost     *decimal.Decimal `json:"upperMonthlyCost"`
+	stdErr               float64
+}
+
+// Sampling contains the details of a sampled project. The costs are order-of-magnitude
+// estimates extrapolated from the priced resources, with a 95% confidence interval.
+type Sampling struct {
+	SampleSize           int                    `json:"sampleSize"`
+	ResourceTypes        []*SampledResourceType `json:"resourceTypes"`
+	EstimatedMonthlyCost *decimal.Decimal       `json:"estimatedMonthlyCost"`
+	LowerMonthlyCost     *decimal.Decimal       `json:"lowerMonthlyCost"`
+	UpperMonthlyCost     *decimal.Decimal       `json:"upperMonthlyCost"`
+}
+
+// Sample reduces the resources of the project so that at most size resources
+// of each resource type are priced. Resources are chosen by a hash of their name
+// so the same resources are picked for the past and current resources and across runs.
+func (p *Project) Sample(size int) {
+	if size <= 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, r := range p.Resources {
+		if !r.IsSkipped {
+			counts[r.ResourceType]++
+		}
+	}
+
+	sampling := &Sampling{
+		SampleSize:    size,
+		ResourceTypes: make([]*SampledResourceType, 0),
+	}
+
+	for t, c := range counts {
+		if c <= size {
+			continue
+		}
+
+		sampling.ResourceTypes = append(sampling.ResourceTypes, &SampledResourceType{
+			ResourceType: t,
+			TotalCount:   c,
+			SampleCount:  size,
+		})
+	}
+
+	if len(sampling.ResourceTypes) == 0 {
+		return
+	}
+
+	sort.Slice(sampling.ResourceTypes, func(i, j int) bool {
+		return sampling.ResourceTypes[i].ResourceType < sampling.ResourceTypes[j].ResourceType
+	})
+
+	p.Sampling = sampling
+	p.Resources = sampleResources(p.Resources, sampling, size)
+	p.PastResources = sampleResources(p.PastResources, sampling, size)
+}
+
+// calculateEstimates extrapolates the costs of the sampled resource types. For each
+// type the total is estimated as the mean sampled cost multiplied by the total count,
+// with the standard error adjusted using the finite population correction.
+func (s *Sampling) calculateEstimates(resources []*Resource) {
+	sampledTypes := s.resourceTypeMap()
+	costs := make(map[string][]float64)
+
+	exact := decimal.Zero
+	for _, r := range resources {
+		if r.IsSkipped {
+			continue
+		}
+
+		var cost decimal.Decimal
+		if r.MonthlyCost != nil {
+			cost = *r.MonthlyCost
+		}
+
+		if _, ok := sampledTypes[r.ResourceType]; ok {
+			f, _ := cost.Float64()
+			costs[r.ResourceType] = append(costs[r.ResourceType], f)
+		} else {
+			exact = exact.Add(cost)
+		}
+	}
+
+	total := exact
+	variance := 0.0
+
+	for _, t := range s.ResourceTypes {
+		n := float64(len(costs[t.ResourceType]))
+		if n == 0 {
+			t.EstimatedMonthlyCost = decimalPtr(decimal.Zero)
+			t.LowerMonthlyCost = decimalPtr(decimal.Zero)
+			t.UpperMonthlyCost = decimalPtr(decimal.Zero)
+			continue
+		}
+
+		mean, sd := meanAndStdDev(costs[t.ResourceType])
+		popN := float64(t.TotalCount)
+
+		fpc := 0.0
+		if popN > 1 {
+			fpc = math.Sqrt((popN - n) / (popN - 1))
+		}
+
+		t.stdErr = popN * sd / math.Sqrt(n) * fpc
+		estimate := decimal.NewFromFloat(popN * mean)
+
+		t.EstimatedMonthlyCost = decimalPtr(estimate)
+		t.LowerMonthlyCost, t.UpperMonthlyCost = confidenceInterval(estimate, t.stdErr)
+
+		total = total.Add(estimate)
+		variance += t.stdErr * t.stdErr
+	}
+
+	s.EstimatedMonthlyCost = decimalPtr(total)
+	s.LowerMonthlyCost, s.UpperMonthlyCost = confidenceInterval(total, math.Sqrt(variance))
+}
+
+func (s *Sampling) resourceTypeMap() map[string]*SampledResourceType {
+	m := make(map[string]*SampledResourceType, len(s.ResourceTypes))
+	for _, t := range s.ResourceTypes {
+		m[t.ResourceType] = t
+	}
+
+	return m
+}
+
+func sampleResources(resources []*Resource, sampling *Sampling, size int) []*Resource {
+	sampledTypes := sampling.resourceTypeMap()
+	byType := make(map[string][]*Resource)
+	result := make([]*Resource, 0, len(resources))
+
+	for _, r := range resources {
+		if _, ok := sampledTypes[r.ResourceType]; ok && !r.IsSkipped {
+			byType[r.ResourceType] = append(byType[r.ResourceType], r)
+		} else {
+			result = append(result, r)
+		}
+	}
+
+	for _, rs := range byType {
+		sort.Slice(rs, func(i, j int) bool {
+			return shortHash(rs[i].Name, 16) < shortHash(rs[j].Name, 16)
+		})
+
+		if len(rs) > size {
+			rs = rs[:size]
+		}
+
+		result = append(result, rs...)
+	}
+
+	return result
+}
+
+func meanAndStdDev(vals []float64) (float64, float64) {
+	if len(vals) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+
+	if len(vals) == 1 {
+		return mean, 0
+	}
+
+	sq := 0.0
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(sq / float64(len(vals)-1))
+}
+
+func confidenceInterval(estimate decimal.Decimal, stdErr float64) (*decimal.Decimal, *decimal.Decimal) {
+	margin := decimal.NewFromFloat(sampleConfidenceZ * stdErr)
+
+	lower := estimate.Sub(margin)
+	if lower.IsNegative() {
+		lower = decimal.Zero
+	}
+
+	return decimalPtr(lower), decimalPtr(estimate.Add(margin))
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectSample(t *testing.T) {
+	project := NewProject("test", &ProjectMetadata{})
+
+	for i := 0; i < 10; i++ {
+		project.Resources = append(project.Resources, &Resource{
+			Name:         fmt.Sprintf("aws_instance.web[%d]", i),
+			ResourceType: "aws_instance",
+		})
+	}
+	project.Resources = append(project.Resources,
+		&Resource{Name: "aws_nat_gateway.nat", ResourceType: "aws_nat_gateway"},
+		&Resource{Name: "aws_foo.bar[0]", ResourceType: "aws_foo", IsSkipped: true},
+		&Resource{Name: "aws_foo.bar[1]", ResourceType: "aws_foo", IsSkipped: true},
+	)
+
+	project.Sample(4)
+
+	assert.NotNil(t, project.Sampling)
+	assert.Len(t, project.Sampling.ResourceTypes, 1)
+	assert.Equal(t, "aws_instance", project.Sampling.ResourceTypes[0].ResourceType)
+	assert.Equal(t, 10, project.Sampling.ResourceTypes[0].TotalCount)
+	assert.Equal(t, 4, project.Sampling.ResourceTypes[0].SampleCount)
+	assert.Len(t, project.Resources, 7)
+}
+
+func TestProjectSampleNotNeeded(t *testing.T) {
+	project := NewProject("test", &ProjectMetadata{})
+	project.Resources = []*Resource{
+		{Name: "aws_instance.web[0]", ResourceType: "aws_instance"},
+		{Name: "aws_instance.web[1]", ResourceType: "aws_instance"},
+	}
+
+	project.Sample(4)
+
+	assert.Nil(t, project.Sampling)
+	assert.Len(t, project.Resources, 2)
+}
+
+func TestSamplingCalculateEstimates(t *testing.T) {
+	sampling := &Sampling{
+		SampleSize: 2,
+		ResourceTypes: []*SampledResourceType{
+			{ResourceType: "aws_instance", TotalCount: 4, SampleCount: 2},
+		},
+	}
+
+	resources := []*Resource{
+		{Name: "aws_instance.web[0]", ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+		{Name: "aws_instance.web[1]", ResourceType: "aws_instance", MonthlyCost: decimalPtr(decimal.NewFromInt(10))},
+		{Name: "aws_nat_gateway.nat", ResourceType: "aws_nat_gateway", MonthlyCost: decimalPtr(decimal.NewFromInt(5))},
+	}
+
+	sampling.calculateEstimates(resources)
+
+	assert.Equal(t, "40", sampling.ResourceTypes[0].EstimatedMonthlyCost.String())
+	assert.Equal(t, "40", sampling.ResourceTypes[0].LowerMonthlyCost.String())
+	assert.Equal(t, "40", sampling.ResourceTypes[0].UpperMonthlyCost.String())
+	assert.Equal(t, "45", sampling.EstimatedMonthlyCost.String())
+
+	resources[1].MonthlyCost = decimalPtr(decimal.NewFromInt(30))
+	sampling.calculateEstimates(resources)
+
+	assert.Equal(t, "85", sampling.EstimatedMonthlyCost.String())
+	assert.True(t, sampling.LowerMonthlyCost.LessThan(*sampling.EstimatedMonthlyCost))
+	assert.True(t, sampling.UpperMonthlyCost.GreaterThan(*sampling.EstimatedMonthlyCost))
+}
@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// SavingsPlanCoverage contains how much of the eligible on-demand compute of a project is
+// covered by a Savings Plan. The costs are monthly.
+type SavingsPlanCoverage struct {
+	Type              string           `json:"type"`
+	Term              string           `json:"term,omitempty"`
+	MonthlyCommitment *decimal.Decimal `json:"monthlyCommitment"`
+	// CoveredCost is the cost of the usage covered by the plan at the Savings Plan rates
+	CoveredCost *decimal.Decimal `json:"monthlyCoveredCost"`
+	// OnDemandCost is the cost of the eligible usage that isn't covered by the plan
+	OnDemandCost     *decimal.Decimal `json:"monthlyOnDemandCost"`
+	Savings          *decimal.Decimal `json:"monthlySavings"`
+	UnusedCommitment *decimal.Decimal `json:"monthlyUnusedCommitment"`
+}
+
+// CommittedUseDiscountCoverage contains how much of a GCP commitment is used by the
+// project, and the usage above the commitment that is priced on-demand. The costs are
+// monthly.
+type CommittedUseDiscountCoverage struct {
+	Region           string           `json:"region"`
+	Term             string           `json:"term"`
+	MachineSeries    string           `json:"machineSeries,omitempty"`
+	VCPUs            decimal.Decimal  `json:"vcpus"`
+	MemoryGB         decimal.Decimal  `json:"memoryGb"`
+	CoveredVCPUs     decimal.Decimal  `json:"coveredVcpus"`
+	CoveredMemoryGB  decimal.Decimal  `json:"coveredMemoryGb"`
+	OverflowVCPUs    decimal.Decimal  `json:"overflowVcpus"`
+	OverflowMemoryGB decimal.Decimal  `json:"overflowMemoryGb"`
+	Savings          *decimal.Decimal `json:"monthlySavings"`
+}
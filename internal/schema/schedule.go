@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const hoursPerWeek = 7 * 24
+
+var weekdays = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+var dayGroups = map[string][]int{
+	"daily":    {0, 1, 2, 3, 4, 5, 6},
+	"weekdays": {0, 1, 2, 3, 4},
+	"weekends": {5, 6},
+}
+
+// scheduleTimeRegex matches the times of a schedule, e.g. 08:00, 8am or 5:30pm.
+var scheduleTimeRegex = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
+// OperatingSchedule is when a resource runs each week, e.g. weekdays 08:00-20:00 for a dev
+// environment that's shut down at night and at weekends.
+type OperatingSchedule struct {
+	// HoursPerWeek is how many hours the resource runs each week.
+	HoursPerWeek decimal.Decimal
+}
+
+// ParseOperatingSchedule parses a schedule of the days and hours a resource runs, e.g.
+// "weekdays 08:00-20:00", "mon-fri 8am-8pm; sat 10am-2pm" or "weekends". The days can be
+// daily, weekdays, weekends, or days and ranges of days, e.g. mon,wed,fri or mon-thu.
+// Without hours the resource runs all day, and hours that end before they start run
+// overnight, e.g. 22:00-06:00.
+func ParseOperatingSchedule(s string) (*OperatingSchedule, error) {
+	hours := decimal.Zero
+
+	for _, part := range strings.Split(s, ";") {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) == 0 {
+			continue
+		}
+
+		days, err := parseScheduleDays(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid operating schedule %q: %s", s, err)
+		}
+
+		perDay := decimal.NewFromInt(24)
+		if len(fields) > 1 {
+			perDay, err = parseScheduleHours(strings.Join(fields[1:], ""))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid operating schedule %q: %s", s, err)
+			}
+		}
+
+		hours = hours.Add(perDay.Mul(decimal.NewFromInt(int64(len(days)))))
+	}
+
+	if hours.IsZero() {
+		return nil, fmt.Errorf("Invalid operating schedule %q: expected days and hours, e.g. weekdays 08:00-20:00", s)
+	}
+
+	if hours.GreaterThan(decimal.NewFromInt(hoursPerWeek)) {
+		return nil, fmt.Errorf("Invalid operating schedule %q: the hours add up to more than a week", s)
+	}
+
+	return &OperatingSchedule{HoursPerWeek: hours}, nil
+}
+
+// Fraction returns the fraction of the time that the resource runs.
+func (s *OperatingSchedule) Fraction() decimal.Decimal {
+	return s.HoursPerWeek.Div(decimal.NewFromInt(hoursPerWeek))
+}
+
+// MonthlyHours returns how many hours the resource runs each month.
+func (s *OperatingSchedule) MonthlyHours() decimal.Decimal {
+	return s.Fraction().Mul(HourToMonthUnitMultiplier)
+}
+
+// ApplyOperatingSchedule scales the cost components of the resource and its sub resources
+// that are priced by the hour the resource runs, e.g. its instance hours, by the fraction
+// of the time the schedule runs. Cost components for the storage, reservations and other
+// things that are charged whether it's running or not are left as they are.
+func ApplyOperatingSchedule(r *Resource, s *OperatingSchedule) {
+	for _, c := range r.CostComponents {
+		if c.HourlyQuantity == nil || c.Unit != "hours" || c.PurchaseOption == "reserved" {
+			continue
+		}
+
+		c.HourlyQuantity = decimalPtr(c.HourlyQuantity.Mul(s.Fraction()))
+		if c.MonthlyQuantity != nil {
+			c.MonthlyQuantity = decimalPtr(c.MonthlyQuantity.Mul(s.Fraction()))
+		}
+	}
+
+	for _, sub := range r.SubResources {
+		ApplyOperatingSchedule(sub, s)
+	}
+}
+
+func parseScheduleDays(s string) ([]int, error) {
+	if days, ok := dayGroups[s]; ok {
+		return days, nil
+	}
+
+	seen := make(map[int]bool)
+	days := make([]int, 0)
+
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+
+		start, err := parseScheduleDay(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+
+		end := start
+		if len(bounds) == 2 {
+			end, err = parseScheduleDay(bounds[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Ranges can wrap around the end of the week, e.g. fri-mon
+		for d := start; ; d = (d + 1) % 7 {
+			if seen[d] {
+				return nil, fmt.Errorf("%s is in the days more than once", weekdays[d])
+			}
+			seen[d] = true
+			days = append(days, d)
+
+			if d == end {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+func parseScheduleDay(s string) (int, error) {
+	for i, d := range weekdays {
+		if s == d || (len(s) > 3 && strings.HasPrefix(s, d) && strings.HasSuffix(s, "day")) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown day %q, expected daily, weekdays, weekends or days like mon-fri", s)
+}
+
+// parseScheduleHours returns the hours in a range of times, e.g. 08:00-20:00 or 8am-8pm.
+func parseScheduleHours(s string) (decimal.Decimal, error) {
+	bounds := strings.SplitN(s, "-", 2)
+	if len(bounds) != 2 {
+		return decimal.Zero, fmt.Errorf("expected hours like 08:00-20:00, got %q", s)
+	}
+
+	start, err := parseScheduleTime(bounds[0])
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	end, err := parseScheduleTime(bounds[1])
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if end.LessThanOrEqual(start) {
+		end = end.Add(decimal.NewFromInt(24))
+	}
+
+	return end.Sub(start), nil
+}
+
+// parseScheduleTime returns the hours since midnight of a time, e.g. 17.5 for 5:30pm.
+func parseScheduleTime(s string) (decimal.Decimal, error) {
+	m := scheduleTimeRegex.FindStringSubmatch(s)
+	if m == nil {
+		return decimal.Zero, fmt.Errorf("invalid time %q, expected a time like 08:00 or 8am", s)
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return decimal.Zero, fmt.Errorf("invalid time %q", s)
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 24 || (hour == 24 && minute > 0) {
+			return decimal.Zero, fmt.Errorf("invalid time %q", s)
+		}
+	}
+
+	if minute > 59 {
+		return decimal.Zero, fmt.Errorf("invalid time %q", s)
+	}
+
+	return decimal.NewFromInt(int64(hour)).Add(decimal.NewFromInt(int64(minute)).Div(decimal.NewFromInt(60))), nil
+}
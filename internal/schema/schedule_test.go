@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOperatingSchedule(t *testing.T) {
+	tests := []struct {
+		schedule     string
+		hoursPerWeek int64
+	}{
+		{"daily", 168},
+		{"weekdays", 120},
+		{"weekends", 48},
+		{"weekdays 08:00-20:00", 60},
+		{"Mon-Fri 8am-8pm", 60},
+		{"mon,wed,friday 9:00-17:00", 24},
+		{"fri-mon 00:00-24:00", 96},
+		{"daily 22:00-06:00", 56},
+		{"mon-fri 8am-6pm; sat 10am-2pm", 54},
+		{"weekdays 12am-12pm", 60},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseOperatingSchedule(tt.schedule)
+		require.NoError(t, err, tt.schedule)
+		assert.Equal(t, decimal.NewFromInt(tt.hoursPerWeek).String(), s.HoursPerWeek.String(), tt.schedule)
+	}
+
+	s, err := ParseOperatingSchedule("weekdays 08:30-17:00")
+	require.NoError(t, err)
+	assert.Equal(t, "42.5", s.HoursPerWeek.String())
+	assert.Equal(t, "0.2530", s.Fraction().StringFixed(4))
+	assert.Equal(t, "184.67", s.MonthlyHours().StringFixed(2))
+}
+
+func TestParseOperatingScheduleErrors(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"workdays 08:00-20:00",
+		"weekdays 08:00",
+		"weekdays 25:00-26:00",
+		"weekdays 13pm-2pm",
+		"daily; weekends",
+	} {
+		_, err := ParseOperatingSchedule(schedule)
+		assert.Error(t, err, schedule)
+	}
+}
+
+func TestApplyOperatingSchedule(t *testing.T) {
+	r := &Resource{
+		CostComponents: []*CostComponent{
+			{Name: "Instance usage", Unit: "hours", HourlyQuantity: decimalPtr(decimal.NewFromInt(2))},
+			{Name: "Reserved instance", Unit: "hours", HourlyQuantity: decimalPtr(decimal.NewFromInt(1)), PurchaseOption: "reserved"},
+			{Name: "Storage", Unit: "GB", MonthlyQuantity: decimalPtr(decimal.NewFromInt(10))},
+		},
+		SubResources: []*Resource{
+			{CostComponents: []*CostComponent{
+				{Name: "Accelerator", Unit: "hours", HourlyQuantity: decimalPtr(decimal.NewFromInt(1))},
+			}},
+		},
+	}
+
+	s, err := ParseOperatingSchedule("weekends")
+	require.NoError(t, err)
+	ApplyOperatingSchedule(r, s)
+
+	assert.Equal(t, "0.5714", r.CostComponents[0].HourlyQuantity.StringFixed(4))
+	assert.Equal(t, "1", r.CostComponents[1].HourlyQuantity.String())
+	assert.Equal(t, "10", r.CostComponents[2].MonthlyQuantity.String())
+	assert.Equal(t, "0.2857", r.SubResources[0].CostComponents[0].HourlyQuantity.StringFixed(4))
+}
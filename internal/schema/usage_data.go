@@ -3,8 +3,14 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
 
@@ -29,28 +35,232 @@ type UsageSchemaItem struct {
 	// Description   string
 }
 
+// UsageProvenance describes where a usage value came from
+type UsageProvenance string
+
+const (
+	UsageProvenanceUsageFile   UsageProvenance = "usage_file"
+	UsageProvenanceCloudMetric UsageProvenance = "cloud_metric"
+	UsageProvenanceBillingData UsageProvenance = "billing_data"
+	UsageProvenanceInferred    UsageProvenance = "inferred"
+	UsageProvenanceDefault     UsageProvenance = "default"
+	UsageProvenanceMissing     UsageProvenance = "missing"
+)
+
+// usageProvenanceWeakness orders the provenances from the strongest to the weakest input
+var usageProvenanceWeakness = map[UsageProvenance]int{
+	UsageProvenanceUsageFile:   0,
+	UsageProvenanceCloudMetric: 1,
+	UsageProvenanceBillingData: 2,
+	UsageProvenanceInferred:    3,
+	UsageProvenanceDefault:     4,
+	UsageProvenanceMissing:     5,
+}
+
+// WeakestUsageProvenance returns the weakest of the given provenances
+func WeakestUsageProvenance(provenances ...UsageProvenance) UsageProvenance {
+	var weakest UsageProvenance
+
+	for _, p := range provenances {
+		if weakest == "" || usageProvenanceWeakness[p] > usageProvenanceWeakness[weakest] {
+			weakest = p
+		}
+	}
+
+	return weakest
+}
+
+// UsageRange is the low and high values of a usage key whose value is a range, e.g. a min
+// and max or a p50 and p95. The value of the key is its expected value.
+type UsageRange struct {
+	Low  float64
+	High float64
+}
+
 type UsageData struct {
-	Address    string
-	Attributes map[string]gjson.Result
+	Address     string
+	Attributes  map[string]gjson.Result
+	provenances map[string]UsageProvenance
+	accessed    map[string]bool
+	ranges      map[string]UsageRange
 }
 
 func NewUsageData(address string, attributes map[string]gjson.Result) *UsageData {
 	return &UsageData{
-		Address:    address,
-		Attributes: attributes,
+		Address:     address,
+		Attributes:  attributes,
+		provenances: make(map[string]UsageProvenance),
+		accessed:    make(map[string]bool),
 	}
 }
 
 func (u *UsageData) Get(key string) gjson.Result {
+	if u.accessed == nil {
+		u.accessed = make(map[string]bool)
+	}
+
 	if u.Attributes[key].Type != gjson.Null {
+		u.accessed[key] = true
 		return u.Attributes[key]
 	} else if strings.Contains(key, "[") && strings.Contains(key, "]") {
 		key = convertArrayKeyToWildcard(key)
 	}
 
+	u.accessed[key] = true
 	return u.Attributes[key]
 }
 
+// SetProvenance sets where the value of the usage key came from. Keys default to
+// coming from the usage file.
+func (u *UsageData) SetProvenance(key string, provenance UsageProvenance) {
+	if u.provenances == nil {
+		u.provenances = make(map[string]UsageProvenance)
+	}
+
+	u.provenances[key] = provenance
+}
+
+// Provenance returns where the value of the usage key came from
+func (u *UsageData) Provenance(key string) UsageProvenance {
+	if u.Attributes[key].Type == gjson.Null {
+		return UsageProvenanceMissing
+	}
+
+	if p, ok := u.provenances[key]; ok {
+		return p
+	}
+
+	return UsageProvenanceUsageFile
+}
+
+// SetRange sets the low and high values of a usage key whose value is its expected value.
+func (u *UsageData) SetRange(key string, r UsageRange) {
+	if u.ranges == nil {
+		u.ranges = make(map[string]UsageRange)
+	}
+
+	u.ranges[key] = r
+}
+
+// Range returns the low and high values of the usage key if its value is a range.
+func (u *UsageData) Range(key string) (UsageRange, bool) {
+	r, ok := u.ranges[key]
+	return r, ok
+}
+
+// CopyKey sets the value, provenance and range of the usage key to the ones it has in the
+// other usage data.
+func (u *UsageData) CopyKey(from *UsageData, key string) {
+	u.Attributes[key] = from.Attributes[key]
+	u.SetProvenance(key, from.Provenance(key))
+
+	if r, ok := from.Range(key); ok {
+		u.SetRange(key, r)
+	} else {
+		delete(u.ranges, key)
+	}
+}
+
+// HasRanges returns true if any of the usage keys have a range.
+func (u *UsageData) HasRanges() bool {
+	return len(u.ranges) > 0
+}
+
+// LowUsage returns a copy of the usage data with the low values of its ranges.
+func (u *UsageData) LowUsage() *UsageData {
+	return u.withRangeValues(func(r UsageRange) float64 { return r.Low })
+}
+
+// HighUsage returns a copy of the usage data with the high values of its ranges.
+func (u *UsageData) HighUsage() *UsageData {
+	return u.withRangeValues(func(r UsageRange) float64 { return r.High })
+}
+
+func (u *UsageData) withRangeValues(value func(UsageRange) float64) *UsageData {
+	c := u.copyValues()
+	for k, r := range u.ranges {
+		c.Attributes[k] = gjson.Parse(decimal.NewFromFloat(value(r)).String())
+	}
+
+	return c
+}
+
+// NumberKeys returns the usage keys whose values are numbers, sorted by key.
+func (u *UsageData) NumberKeys() []string {
+	keys := make([]string, 0, len(u.Attributes))
+	for k, v := range u.Attributes {
+		if v.Type == gjson.Number {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// WithScaledValue returns a copy of the usage data with the value of the number usage key
+// multiplied by the factor. The values of the other keys are their expected values.
+func (u *UsageData) WithScaledValue(key string, factor float64) *UsageData {
+	c := u.copyValues()
+	v := decimal.NewFromFloat(u.Attributes[key].Float()).Mul(decimal.NewFromFloat(factor))
+	c.Attributes[key] = gjson.Parse(v.String())
+
+	return c
+}
+
+// WithValue returns a copy of the usage data with the value of the usage key set.
+func (u *UsageData) WithValue(key string, value interface{}) *UsageData {
+	c := u.copyValues()
+	for k, v := range ParseAttributes(map[string]interface{}{key: value}) {
+		c.Attributes[k] = v
+	}
+
+	return c
+}
+
+func (u *UsageData) copyValues() *UsageData {
+	attributes := make(map[string]gjson.Result, len(u.Attributes))
+	for k, v := range u.Attributes {
+		attributes[k] = v
+	}
+
+	c := NewUsageData(u.Address, attributes)
+	for k := range u.Attributes {
+		c.SetProvenance(k, u.Provenance(k))
+	}
+
+	return c
+}
+
+// ResetAccessed clears the record of which usage keys have been accessed
+func (u *UsageData) ResetAccessed() {
+	u.accessed = make(map[string]bool)
+}
+
+// AccessedKeys returns the usage keys that have been accessed, sorted by key.
+func (u *UsageData) AccessedKeys() []string {
+	keys := make([]string, 0, len(u.accessed))
+	for k := range u.accessed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Values returns the values of the usage keys. Nested keys are joined with dots, e.g.
+// standard.storage_gb.
+func (u *UsageData) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(u.Attributes))
+	for k, v := range u.Attributes {
+		if v.Type != gjson.Null {
+			values[k] = v.Value()
+		}
+	}
+
+	return values
+}
+
 func (u *UsageData) GetFloat(key string) *float64 {
 	if u.Get(key).Type != gjson.Null {
 		val := u.Get(key).Float()
@@ -93,6 +303,172 @@ func NewEmptyUsageMap() map[string]*UsageData {
 	return map[string]*UsageData{}
 }
 
+// FindUsageData returns the usage data for the resource address. An exact match is used if
+// there is one, otherwise the most specific wildcard key that matches the address, e.g.
+// aws_lambda_function.fn[*] or module.app[*].aws_s3_bucket.logs_*. The usage is merged
+// with the usage of the modules the resource is in, e.g. module.app.*, and then the default
+// usage of the resource's type, which is keyed by the type, e.g. aws_lambda_function. The
+// resource's usage takes precedence, then the usage of the innermost module.
+func FindUsageData(usage map[string]*UsageData, address string) *UsageData {
+	layers := make([]*UsageData, 0)
+
+	if defaults, ok := usage[ResourceTypeFromAddress(address)]; ok {
+		layers = append(layers, defaults)
+	}
+
+	keys := make([]string, 0)
+	for k := range usage {
+		if strings.HasSuffix(k, ".*") {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range ModuleUsageKeys(keys, address) {
+		layers = append(layers, usage[k])
+	}
+
+	u := findResourceUsageData(usage, address)
+	if len(layers) == 0 {
+		return u
+	}
+
+	mergedAddress := address
+	if u != nil {
+		mergedAddress = u.Address
+		layers = append(layers, u)
+	}
+
+	merged := NewUsageData(mergedAddress, make(map[string]gjson.Result))
+	for _, l := range layers {
+		for k := range l.Attributes {
+			merged.CopyKey(l, k)
+		}
+	}
+
+	return merged
+}
+
+func findResourceUsageData(usage map[string]*UsageData, address string) *UsageData {
+	if u, ok := usage[address]; ok {
+		return u
+	}
+
+	keys := make([]string, 0)
+	for k := range usage {
+		if strings.Contains(k, "*") {
+			keys = append(keys, k)
+		}
+	}
+
+	if k, ok := MatchUsageKey(keys, address); ok {
+		return usage[k]
+	}
+
+	return nil
+}
+
+var addressIndexRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// moduleUsageKeyRegex matches the keys of the usage of all the resources in a module, e.g.
+// module.app.* or module.app[*].module.db.*
+var moduleUsageKeyRegex = regexp.MustCompile(`(^|\.)module\.[^.\[]+(\[[^\]]*\])?\.\*$`)
+
+var moduleAddressRegex = regexp.MustCompile(`^module\.[^.\[]+(\[[^\]]*\])?\.`)
+
+// ModuleUsageKeys returns the keys of the usage of the modules that the resource address is
+// in, from the outermost module to the innermost. For each module the most specific of the
+// keys that match it is used, e.g. module.app[0].* over module.app[*].*.
+func ModuleUsageKeys(keys []string, address string) []string {
+	patterns := make([]string, 0)
+	for _, k := range keys {
+		if moduleUsageKeyRegex.MatchString(k) {
+			patterns = append(patterns, strings.TrimSuffix(k, ".*"))
+		}
+	}
+
+	matched := make([]string, 0)
+	if len(patterns) == 0 {
+		return matched
+	}
+
+	module := ""
+	rest := address
+	for {
+		m := moduleAddressRegex.FindString(rest)
+		if m == "" {
+			break
+		}
+
+		module += m
+		rest = rest[len(m):]
+
+		if k, ok := MatchUsageKey(patterns, strings.TrimSuffix(module, ".")); ok {
+			matched = append(matched, k+".*")
+		}
+	}
+
+	return matched
+}
+
+// ResourceTypeFromAddress returns the resource type of an address, e.g. aws_instance for
+// module.app[0].aws_instance.web["a.b"].
+func ResourceTypeFromAddress(address string) string {
+	parts := strings.Split(addressIndexRegex.ReplaceAllString(address, ""), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-2]
+}
+
+// MatchUsageKey returns the most specific of the usage keys that matches the resource
+// address. A [*] in a key matches any index, e.g. [0] or ["a"], and any other * matches
+// any part of a name, but not a dot. Keys with fewer wildcards are more specific, then keys
+// with more characters that aren't wildcards, so aws_s3_bucket.logs_app_* is used over
+// aws_s3_bucket.logs_*.
+func MatchUsageKey(keys []string, address string) (string, bool) {
+	var best string
+	bestWildcards, bestLiterals := 0, 0
+	found := false
+
+	for _, k := range keys {
+		if k == address {
+			return k, true
+		}
+
+		if !strings.Contains(k, "*") || !usageKeyRegex(k).MatchString(address) {
+			continue
+		}
+
+		wildcards := strings.Count(k, "*")
+		literals := len(k) - wildcards - 2*strings.Count(k, "[*]")
+
+		if !found || wildcards < bestWildcards || (wildcards == bestWildcards && (literals > bestLiterals || (literals == bestLiterals && k < best))) {
+			best, bestWildcards, bestLiterals, found = k, wildcards, literals, true
+		}
+	}
+
+	return best, found
+}
+
+var usageKeyRegexes sync.Map
+
+func usageKeyRegex(key string) *regexp.Regexp {
+	if r, ok := usageKeyRegexes.Load(key); ok {
+		return r.(*regexp.Regexp)
+	}
+
+	parts := strings.Split(key, "[*]")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, `[^.]*`)
+	}
+
+	r := regexp.MustCompile("^" + strings.Join(parts, `\[[^\]]*\]`) + "$")
+	usageKeyRegexes.Store(key, r)
+
+	return r
+}
+
 func ParseAttributes(i interface{}) map[string]gjson.Result {
 	a := make(map[string]gjson.Result)
 	for k, v := range flatten(i) {
@@ -125,3 +501,60 @@ func flattenHelper(i interface{}, keys []string, result map[string]interface{})
 		result[k] = v
 	}
 }
+
+// SetUsageProvenance sets the usage provenance of the cost components of r from the usage
+// keys that were accessed while creating it. Each key is attributed to the cost components it
+// feeds by calling create to create the resource again with the key changed, and comparing
+// their quantities. The cost components fed by keys with values are marked with the weakest
+// provenance of those keys, and the ones fed by keys without values are marked as defaults,
+// since the resource must have used its built-in defaults for them. Keys that aren't numbers,
+// e.g. an operating system, change prices rather than quantities so they aren't attributed.
+// Cost components without any quantity are marked as missing usage.
+func SetUsageProvenance(r *Resource, u *UsageData, create func(*UsageData) *Resource) {
+	provenances := make(map[string][]UsageProvenance)
+
+	if u != nil && len(u.accessed) > 0 {
+		// The warnings of the resource have already been logged when it was created
+		out := log.StandardLogger().Out
+		log.SetOutput(io.Discard)
+
+		for _, k := range u.AccessedKeys() {
+			provenance, names := usageKeyCostComponents(r, u, k, create)
+			for _, name := range names {
+				provenances[name] = append(provenances[name], provenance)
+			}
+		}
+
+		log.SetOutput(out)
+	}
+
+	setUsageProvenance(r, provenances)
+}
+
+// usageKeyCostComponents returns the provenance of the usage key and the names of the cost
+// components of r that it feeds. A key without a value is set to 1 and 2 since it might only
+// feed a cost component once it has a value.
+func usageKeyCostComponents(r *Resource, u *UsageData, key string, create func(*UsageData) *Resource) (UsageProvenance, []string) {
+	switch v := u.Attributes[key]; v.Type {
+	case gjson.Null:
+		return UsageProvenanceDefault, ChangedCostComponents(create(u.WithValue(key, 1)), create(u.WithValue(key, 2)))
+	case gjson.Number:
+		return u.Provenance(key), ChangedCostComponents(r, create(u.WithValue(key, v.Float()*2+1)))
+	default:
+		return "", nil
+	}
+}
+
+func setUsageProvenance(r *Resource, provenances map[string][]UsageProvenance) {
+	for _, c := range r.CostComponents {
+		if c.HourlyQuantity == nil && c.MonthlyQuantity == nil {
+			c.UsageProvenance = UsageProvenanceMissing
+		} else if p, ok := provenances[c.Name]; ok {
+			c.UsageProvenance = WeakestUsageProvenance(p...)
+		}
+	}
+
+	for _, s := range r.SubResources {
+		setUsageProvenance(s, provenances)
+	}
+}
@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+// newTestFunction creates a function like aws_lambda_function, whose storage comes from an
+// attribute and whose requests and duration come from the usage.
+func newTestFunction(u *UsageData) *Resource {
+	requests := u.GetFloat("monthly_requests")
+	duration := decimal.NewFromInt(400)
+	if v := u.GetFloat("request_duration_ms"); v != nil {
+		duration = decimal.NewFromFloat(*v)
+	}
+
+	var requestsQty, durationQty *decimal.Decimal
+	if requests != nil {
+		requestsQty = decimalPtr(decimal.NewFromFloat(*requests))
+		durationQty = decimalPtr(requestsQty.Mul(duration))
+	}
+
+	return &Resource{
+		Name: "aws_lambda_function.fn",
+		CostComponents: []*CostComponent{
+			{Name: "Requests", MonthlyQuantity: requestsQty},
+			{Name: "Duration", MonthlyQuantity: durationQty},
+			{Name: "Instance usage", HourlyQuantity: decimalPtr(decimal.NewFromInt(1))},
+		},
+		SubResources: []*Resource{
+			{
+				Name: "Storage",
+				CostComponents: []*CostComponent{
+					{Name: "Storage", MonthlyQuantity: decimalPtr(decimal.NewFromInt(10))},
+				},
+			},
+		},
+	}
+}
+
+func TestSetUsageProvenance(t *testing.T) {
+	u := NewUsageData("aws_lambda_function.fn", map[string]gjson.Result{
+		"monthly_requests":    gjson.Parse("1000"),
+		"request_duration_ms": gjson.Parse("200"),
+	})
+	u.SetProvenance("monthly_requests", UsageProvenanceCloudMetric)
+	u.SetProvenance("request_duration_ms", UsageProvenanceInferred)
+
+	r := newTestFunction(u)
+	SetUsageProvenance(r, u, newTestFunction)
+
+	assert.Equal(t, UsageProvenanceCloudMetric, r.CostComponents[0].UsageProvenance)
+	assert.Equal(t, UsageProvenanceInferred, r.CostComponents[1].UsageProvenance)
+	assert.Equal(t, UsageProvenance(""), r.CostComponents[2].UsageProvenance)
+	assert.Equal(t, UsageProvenance(""), r.SubResources[0].CostComponents[0].UsageProvenance)
+}
+
+func TestSetUsageProvenanceDefaults(t *testing.T) {
+	u := NewUsageData("aws_lambda_function.fn", map[string]gjson.Result{
+		"monthly_requests": gjson.Parse("1000"),
+	})
+
+	r := newTestFunction(u)
+	SetUsageProvenance(r, u, newTestFunction)
+
+	assert.Equal(t, UsageProvenanceUsageFile, r.CostComponents[0].UsageProvenance)
+	assert.Equal(t, UsageProvenanceDefault, r.CostComponents[1].UsageProvenance)
+
+	u = NewUsageData("aws_lambda_function.fn", map[string]gjson.Result{})
+	r = newTestFunction(u)
+	SetUsageProvenance(r, u, newTestFunction)
+
+	assert.Equal(t, UsageProvenanceMissing, r.CostComponents[0].UsageProvenance)
+	assert.Equal(t, UsageProvenanceMissing, r.CostComponents[1].UsageProvenance)
+	assert.Equal(t, UsageProvenance(""), r.SubResources[0].CostComponents[0].UsageProvenance)
+}
+
+func TestWeakestUsageProvenance(t *testing.T) {
+	assert.Equal(t, UsageProvenance(""), WeakestUsageProvenance())
+	assert.Equal(t, UsageProvenanceDefault, WeakestUsageProvenance(UsageProvenanceUsageFile, UsageProvenanceDefault, UsageProvenanceCloudMetric))
+}
+
+func TestFindUsageData(t *testing.T) {
+	usage := NewUsageMap(map[string]interface{}{
+		"aws_lambda_function.fn":                           map[string]interface{}{"monthly_requests": 1},
+		"aws_lambda_function.fn[*]":                        map[string]interface{}{"monthly_requests": 2},
+		"aws_lambda_function.fn[1]":                        map[string]interface{}{"monthly_requests": 3},
+		"module.app[*].aws_lambda_function.handler":        map[string]interface{}{"monthly_requests": 4},
+		"aws_s3_bucket.logs_*":                             map[string]interface{}{"storage_gb": 5},
+		"aws_s3_bucket.logs_app_*":                         map[string]interface{}{"storage_gb": 6},
+		"module.*.aws_s3_bucket.logs_*":                    map[string]interface{}{"storage_gb": 7},
+		"module.app[\"prod\"].aws_lambda_function.handler": map[string]interface{}{"monthly_requests": 8},
+	})
+
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"aws_lambda_function.fn", "aws_lambda_function.fn"},
+		{"aws_lambda_function.fn[0]", "aws_lambda_function.fn[*]"},
+		{`aws_lambda_function.fn["a.b"]`, "aws_lambda_function.fn[*]"},
+		{"aws_lambda_function.fn[1]", "aws_lambda_function.fn[1]"},
+		{"module.app[0].aws_lambda_function.handler", "module.app[*].aws_lambda_function.handler"},
+		{`module.app["prod"].aws_lambda_function.handler`, `module.app["prod"].aws_lambda_function.handler`},
+		{"aws_s3_bucket.logs_web", "aws_s3_bucket.logs_*"},
+		{"aws_s3_bucket.logs_app_1", "aws_s3_bucket.logs_app_*"},
+		{"module.shared.aws_s3_bucket.logs_web", "module.*.aws_s3_bucket.logs_*"},
+		{"aws_s3_bucket.data", ""},
+		{"module.a.module.b.aws_s3_bucket.logs_web", ""},
+	}
+
+	for _, tt := range tests {
+		u := FindUsageData(usage, tt.address)
+		if tt.expected == "" {
+			assert.Nil(t, u, tt.address)
+			continue
+		}
+
+		if assert.NotNil(t, u, tt.address) {
+			assert.Equal(t, tt.expected, u.Address, tt.address)
+		}
+	}
+}
+
+func TestFindUsageDataResourceTypeDefaults(t *testing.T) {
+	usage := NewUsageMap(map[string]interface{}{
+		"aws_lambda_function.fn": map[string]interface{}{"monthly_requests": 1},
+	})
+	usage["aws_lambda_function"] = NewUsageData("aws_lambda_function", ParseAttributes(map[string]interface{}{
+		"monthly_requests":    100,
+		"request_duration_ms": 500,
+	}))
+	usage["aws_lambda_function.fn"].SetProvenance("monthly_requests", UsageProvenanceCloudMetric)
+
+	u := FindUsageData(usage, "aws_lambda_function.fn")
+	assert.Equal(t, "aws_lambda_function.fn", u.Address)
+	assert.Equal(t, int64(1), u.Get("monthly_requests").Int())
+	assert.Equal(t, int64(500), u.Get("request_duration_ms").Int())
+	assert.Equal(t, UsageProvenanceCloudMetric, u.Provenance("monthly_requests"))
+
+	u = FindUsageData(usage, `module.app["a.b"].aws_lambda_function.other[0]`)
+	assert.Equal(t, `module.app["a.b"].aws_lambda_function.other[0]`, u.Address)
+	assert.Equal(t, int64(100), u.Get("monthly_requests").Int())
+
+	assert.Nil(t, FindUsageData(usage, "aws_s3_bucket.data"))
+}
+
+func TestFindUsageDataModuleUsage(t *testing.T) {
+	usage := NewUsageMap(map[string]interface{}{
+		"module.api.*":                      map[string]interface{}{"monthly_requests": 1, "request_duration_ms": 100, "storage_gb": 10},
+		"module.api.module.db.*":            map[string]interface{}{"storage_gb": 20},
+		"module.api.aws_lambda_function.fn": map[string]interface{}{"monthly_requests": 2},
+		"module.workers[*].*":               map[string]interface{}{"monthly_requests": 3},
+		"module.workers[\"a.b\"].*":         map[string]interface{}{"monthly_requests": 4},
+	})
+	usage["aws_lambda_function"] = NewUsageData("aws_lambda_function", ParseAttributes(map[string]interface{}{
+		"monthly_requests": 1000,
+		"memory_mb":        256,
+	}))
+
+	u := FindUsageData(usage, "module.api.aws_lambda_function.fn")
+	assert.Equal(t, "module.api.aws_lambda_function.fn", u.Address)
+	assert.Equal(t, int64(2), u.Get("monthly_requests").Int())
+	assert.Equal(t, int64(100), u.Get("request_duration_ms").Int())
+	assert.Equal(t, int64(256), u.Get("memory_mb").Int())
+
+	u = FindUsageData(usage, "module.api.aws_lambda_function.other")
+	assert.Equal(t, "module.api.aws_lambda_function.other", u.Address)
+	assert.Equal(t, int64(1), u.Get("monthly_requests").Int())
+
+	u = FindUsageData(usage, "module.api.module.db.aws_s3_bucket.data")
+	assert.Equal(t, int64(20), u.Get("storage_gb").Int())
+	assert.Equal(t, int64(1), u.Get("monthly_requests").Int())
+
+	u = FindUsageData(usage, "module.workers[0].aws_lambda_function.fn")
+	assert.Equal(t, int64(3), u.Get("monthly_requests").Int())
+
+	u = FindUsageData(usage, `module.workers["a.b"].aws_lambda_function.fn`)
+	assert.Equal(t, int64(4), u.Get("monthly_requests").Int())
+
+	assert.Nil(t, FindUsageData(usage, "module.apis.aws_s3_bucket.data"))
+	assert.Nil(t, FindUsageData(usage, "aws_s3_bucket.data"))
+}
+
+func TestUsageDataWithScaledValue(t *testing.T) {
+	u := NewUsageData("aws_lambda_function.fn", ParseAttributes(map[string]interface{}{
+		"monthly_requests":    1000000,
+		"request_duration_ms": 500,
+		"architecture":        "arm64",
+	}))
+	u.SetProvenance("request_duration_ms", UsageProvenanceCloudMetric)
+
+	assert.Equal(t, []string{"monthly_requests", "request_duration_ms"}, u.NumberKeys())
+
+	scaled := u.WithScaledValue("monthly_requests", 1.5)
+	assert.Equal(t, int64(1500000), scaled.Get("monthly_requests").Int())
+	assert.Equal(t, int64(500), scaled.Get("request_duration_ms").Int())
+	assert.Equal(t, UsageProvenanceCloudMetric, scaled.Provenance("request_duration_ms"))
+
+	// The usage data is left as it was
+	assert.Equal(t, int64(1000000), u.Get("monthly_requests").Int())
+}
@@ -0,0 +1,125 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// usageAnchorKey is the usage key of a resource that uses the usage of an anchor in the
+// usage_anchors of the usage file.
+const usageAnchorKey = "usage_anchor"
+
+// resolveUsageAnchors replaces the usage_anchor of the usage of each resource with a copy of
+// the usage of the anchor, so many resources can share the same usage and be updated in one
+// place. The resource's own keys override the anchor's, and each copy's expressions are
+// evaluated with the resource's keys.
+func resolveUsageAnchors(resources map[string]interface{}, anchors map[string]interface{}) error {
+	for name, usage := range resources {
+		m, ok := usage.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		anchorName, ok := m[usageAnchorKey]
+		if !ok {
+			continue
+		}
+
+		anchor, err := findUsageAnchor(anchors, anchorName)
+		if err != nil {
+			return fmt.Errorf("Invalid usage_anchor in the usage of %s: %s", name, err)
+		}
+
+		resources[name] = mergeAnchorUsage(anchor, m)
+	}
+
+	return nil
+}
+
+func findUsageAnchor(anchors map[string]interface{}, anchorName interface{}) (map[interface{}]interface{}, error) {
+	s, ok := anchorName.(string)
+	if !ok {
+		return nil, fmt.Errorf("should be the name of a usage anchor, got %v", anchorName)
+	}
+
+	anchor, ok := anchors[s]
+	if !ok {
+		names := make([]string, 0, len(anchors))
+		for n := range anchors {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("%s isn't in the usage_anchors%s", s, didYouMean(s, names))
+	}
+
+	m, ok := anchor.(map[interface{}]interface{})
+	if anchor != nil && !ok {
+		return nil, fmt.Errorf("the usage anchor %s should be a mapping of usage keys to their values", s)
+	}
+
+	if _, ok := m[usageAnchorKey]; ok {
+		return nil, fmt.Errorf("the usage anchor %s can't use another usage anchor", s)
+	}
+
+	return m, nil
+}
+
+// mergeAnchorUsage returns a copy of the anchor's usage with the resource's usage merged over
+// it. Nested mappings are merged key by key, except ranges which replace the anchor's value.
+func mergeAnchorUsage(anchor, usage map[interface{}]interface{}) map[interface{}]interface{} {
+	merged := copyUsageMapping(anchor)
+
+	for k, v := range usage {
+		if k == usageAnchorKey {
+			continue
+		}
+
+		existing, existingOk := merged[k].(map[interface{}]interface{})
+		m, ok := v.(map[interface{}]interface{})
+		if existingOk && ok && !isUsageRangeMapping(m) {
+			merged[k] = mergeAnchorUsage(existing, m)
+			continue
+		}
+
+		merged[k] = copyUsageValue(v)
+	}
+
+	return merged
+}
+
+func isUsageRangeMapping(m map[interface{}]interface{}) bool {
+	for k := range m {
+		if s, ok := k.(string); ok && containsString(usageRangeKeys, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyUsageMapping(m map[interface{}]interface{}) map[interface{}]interface{} {
+	c := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		c[k] = copyUsageValue(v)
+	}
+
+	return c
+}
+
+// copyUsageValue returns a deep copy of a usage value, since expressions are replaced with
+// their values in place.
+func copyUsageValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		return copyUsageMapping(v)
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = copyUsageValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
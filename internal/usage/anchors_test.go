@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsageAnchors(t *testing.T) {
+	usage, err := parseYAML([]byte(`version: 0.1
+variables:
+  daily_requests: 100k
+usage_anchors:
+  api_traffic:
+    monthly_requests: ${daily_requests} * 30
+    request_duration_ms: 250
+  logs:
+    standard:
+      storage_gb: 1 TiB
+      monthly_tier_1_requests: ${standard.storage_gb} * 10
+resource_type_default_usage:
+  aws_s3_bucket:
+    usage_anchor: logs
+resource_usage:
+  aws_lambda_function.orders:
+    usage_anchor: api_traffic
+  aws_lambda_function.payments:
+    usage_anchor: api_traffic
+    request_duration_ms: 500
+  aws_s3_bucket.archive:
+    usage_anchor: logs
+    standard:
+      storage_gb: 2 TiB
+`))
+	require.NoError(t, err)
+
+	orders := schema.FindUsageData(usage, "aws_lambda_function.orders")
+	assert.Equal(t, int64(3000000), orders.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), orders.Get("request_duration_ms").Int())
+	assert.False(t, orders.Get("usage_anchor").Exists())
+
+	payments := schema.FindUsageData(usage, "aws_lambda_function.payments")
+	assert.Equal(t, int64(3000000), payments.Get("monthly_requests").Int())
+	assert.Equal(t, int64(500), payments.Get("request_duration_ms").Int())
+
+	// The expressions of each copy of the anchor use the resource's own keys
+	archive := schema.FindUsageData(usage, "aws_s3_bucket.archive")
+	assert.Equal(t, int64(2048), archive.Get("standard.storage_gb").Int())
+	assert.Equal(t, int64(20480), archive.Get("standard.monthly_tier_1_requests").Int())
+
+	bucket := schema.FindUsageData(usage, "aws_s3_bucket")
+	assert.Equal(t, int64(1024), bucket.Get("standard.storage_gb").Int())
+	assert.Equal(t, int64(10240), bucket.Get("standard.monthly_tier_1_requests").Int())
+}
+
+func TestParseUsageAnchorsErrors(t *testing.T) {
+	_, err := parseYAML([]byte(`version: 0.1
+usage_anchors:
+  api_traffic:
+    monthly_requests: 1000
+resource_usage:
+  aws_lambda_function.fn:
+    usage_anchor: api_trafic
+`))
+	assert.EqualError(t, err, "Invalid usage_anchor in the usage of aws_lambda_function.fn: api_trafic isn't in the usage_anchors (did you mean api_traffic?)")
+
+	_, err = parseYAML([]byte(`version: 0.1
+usage_anchors:
+  base:
+    monthly_requests: 1000
+  api_traffic:
+    usage_anchor: base
+resource_usage:
+  aws_lambda_function.fn:
+    usage_anchor: api_traffic
+`))
+	assert.EqualError(t, err, "Invalid usage_anchor in the usage of aws_lambda_function.fn: the usage anchor api_traffic can't use another usage anchor")
+
+	// The anchor's keys are checked against the resource types that use it
+	_, err = parseYAML([]byte(`version: 0.1
+usage_anchors:
+  api_traffic:
+    monthly_requests: 1000
+    storage_gb: 10
+resource_usage:
+  aws_lambda_function.fn:
+    usage_anchor: api_traffic
+  aws_lambda_function.other:
+    usage_anchor: api_traffic
+`))
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"line 5: unknown key storage_gb for usage anchor api_traffic (used by aws_lambda_function)",
+	}, validationErr.Problems)
+}
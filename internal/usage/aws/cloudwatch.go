@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/pkg/errors"
+)
+
+const cloudWatchAPIVersion = "2010-08-01"
+
+// cloudWatchLookback is how far back the metrics are queried. The sums over this period
+// are used as the monthly usage.
+var cloudWatchLookback = 30 * 24 * time.Hour
+
+type dimension struct {
+	name  string
+	value string
+}
+
+type metricQuery struct {
+	region     string
+	namespace  string
+	metricName string
+	dimensions []dimension
+	// statistic is Sum or Average
+	statistic string
+}
+
+type getMetricStatisticsResponse struct {
+	Datapoints []struct {
+		Sum     float64 `xml:"Sum"`
+		Average float64 `xml:"Average"`
+	} `xml:"GetMetricStatisticsResult>Datapoints>member"`
+}
+
+// cloudWatchClient gets metric statistics with the CloudWatch query API.
+type cloudWatchClient struct {
+	creds awsauth.Credentials
+	// endpoint replaces the regional endpoints if it's set
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// getMetricStatistic returns the statistic of the metric over the lookback period. It
+// returns false if the metric has no datapoints, e.g. if the resource doesn't exist yet.
+func (c *cloudWatchClient) getMetricStatistic(q metricQuery) (float64, bool, error) {
+	end := c.now().UTC()
+	start := end.Add(-cloudWatchLookback)
+
+	form := url.Values{}
+	form.Set("Action", "GetMetricStatistics")
+	form.Set("Version", cloudWatchAPIVersion)
+	form.Set("Namespace", q.namespace)
+	form.Set("MetricName", q.metricName)
+	form.Set("StartTime", start.Format(time.RFC3339))
+	form.Set("EndTime", end.Format(time.RFC3339))
+	form.Set("Period", strconv.Itoa(int(cloudWatchLookback.Seconds())))
+	form.Set("Statistics.member.1", q.statistic)
+	for i, d := range q.dimensions {
+		form.Set(fmt.Sprintf("Dimensions.member.%d.Name", i+1), d.name)
+		form.Set(fmt.Sprintf("Dimensions.member.%d.Value", i+1), d.value)
+	}
+	body := []byte(form.Encode())
+
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://monitoring.%s.amazonaws.com/", q.region)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	awsauth.SignRequest(req, body, c.creds, q.region, "monitoring", c.now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error contacting CloudWatch")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Invalid response from CloudWatch")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, errors.Errorf("Invalid response from CloudWatch %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var r getMetricStatisticsResponse
+	if err := xml.Unmarshal(respBody, &r); err != nil {
+		return 0, false, errors.Wrap(err, "Invalid response from CloudWatch")
+	}
+
+	if len(r.Datapoints) == 0 {
+		return 0, false, nil
+	}
+
+	// The lookback can span two periods if it isn't aligned with them
+	value := 0.0
+	for _, d := range r.Datapoints {
+		if q.statistic == "Average" {
+			value += d.Average / float64(len(r.Datapoints))
+		} else {
+			value += d.Sum
+		}
+	}
+
+	return value, true, nil
+}
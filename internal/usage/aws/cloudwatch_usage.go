@@ -0,0 +1,171 @@
+package aws
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+)
+
+const bytesInGB = 1024 * 1024 * 1024
+
+// CloudWatchUsage fetches the usage of existing AWS resources from their CloudWatch
+// metrics for the last 30 days, so their usage-based costs are estimated from their actual
+// usage instead of the usage file.
+type CloudWatchUsage struct {
+	client *cloudWatchClient
+}
+
+// NewCloudWatchUsage returns a CloudWatchUsage that uses the AWS credentials in the
+// standard AWS_* environment variables. The endpoint replaces the regional CloudWatch
+// endpoints if it's set.
+func NewCloudWatchUsage(endpoint string) (*CloudWatchUsage, error) {
+	creds := awsauth.CredentialsFromEnv()
+	if !creds.IsSet() {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to fetch usage from CloudWatch")
+	}
+
+	return &CloudWatchUsage{
+		client: &cloudWatchClient{
+			creds:      creds,
+			endpoint:   endpoint,
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+			now:        time.Now,
+		},
+	}, nil
+}
+
+// SupportsResourceType returns true if usage can be fetched for the resource type.
+func (c *CloudWatchUsage) SupportsResourceType(resourceType string) bool {
+	switch resourceType {
+	case "aws_lambda_function", "aws_nat_gateway", "aws_s3_bucket", "aws_dynamodb_table":
+		return true
+	}
+
+	return false
+}
+
+// FetchUsage returns the usage keys of the resource that have metrics. Resources that
+// don't exist yet have no metrics, so nothing is returned for them.
+func (c *CloudWatchUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	region := d.Get("region").String()
+
+	switch d.Type {
+	case "aws_lambda_function":
+		return c.lambdaFunctionUsage(region, d.Get("function_name").String())
+	case "aws_nat_gateway":
+		return c.natGatewayUsage(region, d.Get("id").String())
+	case "aws_s3_bucket":
+		return c.s3BucketUsage(region, d.Get("bucket").String())
+	case "aws_dynamodb_table":
+		// Consumed capacity is only charged for on-demand tables
+		if d.Get("billing_mode").String() != "PAY_PER_REQUEST" {
+			return map[string]interface{}{}, nil
+		}
+		return c.dynamoDBTableUsage(region, d.Get("name").String())
+	}
+
+	return map[string]interface{}{}, nil
+}
+
+func (c *CloudWatchUsage) lambdaFunctionUsage(region, functionName string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if functionName == "" {
+		return usage, nil
+	}
+
+	dims := []dimension{{"FunctionName", functionName}}
+
+	invocations, ok, err := c.client.getMetricStatistic(metricQuery{region, "AWS/Lambda", "Invocations", dims, "Sum"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["monthly_requests"] = int64(math.Round(invocations))
+
+	if invocations == 0 {
+		return usage, nil
+	}
+
+	duration, ok, err := c.client.getMetricStatistic(metricQuery{region, "AWS/Lambda", "Duration", dims, "Sum"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["request_duration_ms"] = int64(math.Round(duration / invocations))
+
+	return usage, nil
+}
+
+func (c *CloudWatchUsage) natGatewayUsage(region, natGatewayID string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if natGatewayID == "" {
+		return usage, nil
+	}
+
+	dims := []dimension{{"NatGatewayId", natGatewayID}}
+
+	// Data processing is charged for the data in both directions between the source and
+	// the destination, so the data from the destination and to the destination is used
+	processed := 0.0
+	found := false
+	for _, metricName := range []string{"BytesOutToDestination", "BytesInFromDestination"} {
+		bytes, ok, err := c.client.getMetricStatistic(metricQuery{region, "AWS/NATGateway", metricName, dims, "Sum"})
+		if err != nil {
+			return usage, err
+		}
+
+		processed += bytes
+		found = found || ok
+	}
+
+	if found {
+		usage["monthly_data_processed_gb"] = processed / bytesInGB
+	}
+
+	return usage, nil
+}
+
+func (c *CloudWatchUsage) s3BucketUsage(region, bucket string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if bucket == "" {
+		return usage, nil
+	}
+
+	// The bucket size metrics are daily, so the average is the average size over the month
+	dims := []dimension{{"BucketName", bucket}, {"StorageType", "StandardStorage"}}
+
+	size, ok, err := c.client.getMetricStatistic(metricQuery{region, "AWS/S3", "BucketSizeBytes", dims, "Average"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["standard.storage_gb"] = int64(math.Ceil(size / bytesInGB))
+
+	return usage, nil
+}
+
+func (c *CloudWatchUsage) dynamoDBTableUsage(region, tableName string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if tableName == "" {
+		return usage, nil
+	}
+
+	dims := []dimension{{"TableName", tableName}}
+
+	for key, metricName := range map[string]string{
+		"monthly_read_request_units":  "ConsumedReadCapacityUnits",
+		"monthly_write_request_units": "ConsumedWriteCapacityUnits",
+	} {
+		units, ok, err := c.client.getMetricStatistic(metricQuery{region, "AWS/DynamoDB", metricName, dims, "Sum"})
+		if err != nil {
+			return usage, err
+		}
+
+		if ok {
+			usage[key] = int64(math.Round(units))
+		}
+	}
+
+	return usage, nil
+}
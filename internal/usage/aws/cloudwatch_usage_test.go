@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newTestCloudWatchUsage(t *testing.T, metrics map[string]string) *CloudWatchUsage {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "GetMetricStatistics", r.Form.Get("Action"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/monitoring/aws4_request")
+		assert.Equal(t, "2021-05-01T00:00:00Z", r.Form.Get("StartTime"))
+
+		key := fmt.Sprintf("%s/%s/%s", r.Form.Get("Namespace"), r.Form.Get("MetricName"), r.Form.Get("Dimensions.member.1.Value"))
+		fmt.Fprintf(w, `<GetMetricStatisticsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <GetMetricStatisticsResult>
+    <Datapoints>%s</Datapoints>
+    <Label>%s</Label>
+  </GetMetricStatisticsResult>
+</GetMetricStatisticsResponse>`, metrics[key], r.Form.Get("MetricName"))
+	}))
+	t.Cleanup(ts.Close)
+
+	return &CloudWatchUsage{
+		client: &cloudWatchClient{
+			creds:      awsauth.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"},
+			endpoint:   ts.URL,
+			httpClient: ts.Client(),
+			now: func() time.Time {
+				return time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+			},
+		},
+	}
+}
+
+func TestCloudWatchUsageLambdaFunction(t *testing.T) {
+	c := newTestCloudWatchUsage(t, map[string]string{
+		"AWS/Lambda/Invocations/fn": "<member><Sum>2000</Sum><Unit>Count</Unit></member>",
+		"AWS/Lambda/Duration/fn":    "<member><Sum>250000</Sum><Unit>Milliseconds</Unit></member><member><Sum>50000</Sum><Unit>Milliseconds</Unit></member>",
+	})
+
+	d := schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.fn", nil, gjson.Parse(`{"region":"us-east-1","function_name":"fn"}`))
+
+	usage, err := c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_requests":    int64(2000),
+		"request_duration_ms": int64(150),
+	}, usage)
+}
+
+func TestCloudWatchUsageS3Bucket(t *testing.T) {
+	c := newTestCloudWatchUsage(t, map[string]string{
+		"AWS/S3/BucketSizeBytes/assets": "<member><Average>1610612736</Average><Unit>Bytes</Unit></member>",
+	})
+
+	d := schema.NewResourceData("aws_s3_bucket", "aws", "aws_s3_bucket.assets", nil, gjson.Parse(`{"region":"us-east-1","bucket":"assets"}`))
+
+	usage, err := c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"standard.storage_gb": int64(2)}, usage)
+}
+
+func TestCloudWatchUsageNoDatapoints(t *testing.T) {
+	c := newTestCloudWatchUsage(t, map[string]string{})
+
+	d := schema.NewResourceData("aws_nat_gateway", "aws", "aws_nat_gateway.nat", nil, gjson.Parse(`{"region":"us-east-1","id":"nat-123"}`))
+
+	usage, err := c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
+
+func TestCloudWatchUsageProvisionedDynamoDBTable(t *testing.T) {
+	c := newTestCloudWatchUsage(t, map[string]string{
+		"AWS/DynamoDB/ConsumedReadCapacityUnits/table": "<member><Sum>1000</Sum></member>",
+	})
+
+	d := schema.NewResourceData("aws_dynamodb_table", "aws", "aws_dynamodb_table.table", nil, gjson.Parse(`{"region":"us-east-1","name":"table","billing_mode":"PROVISIONED"}`))
+
+	usage, err := c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
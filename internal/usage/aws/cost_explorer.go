@@ -0,0 +1,138 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Cost Explorer is a global service that's only served from us-east-1
+const (
+	costExplorerRegion   = "us-east-1"
+	costExplorerEndpoint = "https://ce.us-east-1.amazonaws.com/"
+)
+
+// costExplorerLookbackDays is how many days of usage are queried. Cost Explorer only has
+// resource level data for the last 14 days.
+var costExplorerLookbackDays = 14
+
+// costExplorerClient gets resource level usage with the Cost Explorer API. Resource level
+// data has to be enabled in the Cost Explorer settings of the account.
+type costExplorerClient struct {
+	creds awsauth.Credentials
+	// endpoint replaces the Cost Explorer endpoint if it's set
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// getUsageQuantities returns the usage quantities of the resources of the services over the
+// lookback period, keyed by service, resource ID and usage type, e.g. AWS Lambda,
+// arn:aws:lambda:us-east-1:123456789012:function:fn and USE1-Request.
+func (c *costExplorerClient) getUsageQuantities(services []string) (map[string]map[string]map[string]float64, error) {
+	end := c.now().UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -costExplorerLookbackDays)
+
+	quantities := make(map[string]map[string]map[string]float64)
+
+	// Each service is queried separately since its name isn't one of the group by keys
+	for _, service := range services {
+		quantities[service] = make(map[string]map[string]float64)
+
+		nextPageToken := ""
+		for {
+			payload := map[string]interface{}{
+				"TimePeriod": map[string]string{
+					"Start": start.Format("2006-01-02"),
+					"End":   end.Format("2006-01-02"),
+				},
+				"Granularity": "DAILY",
+				"Metrics":     []string{"UsageQuantity"},
+				"Filter": map[string]interface{}{
+					"Dimensions": map[string]interface{}{
+						"Key":    "SERVICE",
+						"Values": []string{service},
+					},
+				},
+				"GroupBy": []map[string]string{
+					{"Type": "DIMENSION", "Key": "RESOURCE_ID"},
+					{"Type": "DIMENSION", "Key": "USAGE_TYPE"},
+				},
+			}
+			if nextPageToken != "" {
+				payload["NextPageToken"] = nextPageToken
+			}
+
+			result, err := c.call("GetCostAndUsageWithResources", payload)
+			if err != nil {
+				return quantities, err
+			}
+
+			for _, group := range result.Get("ResultsByTime.#.Groups|@flatten").Array() {
+				keys := group.Get("Keys").Array()
+				if len(keys) != 2 {
+					continue
+				}
+
+				resourceID, usageType := keys[0].String(), keys[1].String()
+				if quantities[service][resourceID] == nil {
+					quantities[service][resourceID] = make(map[string]float64)
+				}
+				quantities[service][resourceID][usageType] += group.Get("Metrics.UsageQuantity.Amount").Float()
+			}
+
+			nextPageToken = result.Get("NextPageToken").String()
+			if nextPageToken == "" {
+				break
+			}
+		}
+	}
+
+	return quantities, nil
+}
+
+func (c *costExplorerClient) call(action string, payload interface{}) (gjson.Result, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error generating request body")
+	}
+
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = costExplorerEndpoint
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error generating request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSInsightsIndexService."+action)
+
+	awsauth.SignRequest(req, reqBody, c.creds, costExplorerRegion, "ce", c.now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error contacting Cost Explorer")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Invalid response from Cost Explorer")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return gjson.Result{}, errors.Errorf("Invalid response from %s %d: %s", action, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return gjson.ParseBytes(respBody), nil
+}
@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+)
+
+const hoursInMonth = 730
+
+// costExplorerServices are the Cost Explorer service names of the supported resource types
+var costExplorerServices = map[string]string{
+	"aws_lambda_function": "AWS Lambda",
+	"aws_s3_bucket":       "Amazon Simple Storage Service",
+	"aws_nat_gateway":     "EC2 - Other",
+	"aws_dynamodb_table":  "Amazon DynamoDB",
+}
+
+// CostExplorerUsage fetches the usage of existing AWS resources from their resource level
+// Cost & Usage data in Cost Explorer, so their usage-based costs are estimated from their
+// actual usage instead of the usage file. The usage of all the resources of the supported
+// services is fetched once and scaled from the last 14 days to a month.
+type CostExplorerUsage struct {
+	client *costExplorerClient
+
+	once       sync.Once
+	quantities map[string]map[string]map[string]float64
+	err        error
+}
+
+// NewCostExplorerUsage returns a CostExplorerUsage that uses the AWS credentials in the
+// standard AWS_* environment variables. The endpoint replaces the Cost Explorer endpoint
+// if it's set.
+func NewCostExplorerUsage(endpoint string) (*CostExplorerUsage, error) {
+	creds := awsauth.CredentialsFromEnv()
+	if !creds.IsSet() {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to fetch usage from Cost Explorer")
+	}
+
+	return &CostExplorerUsage{
+		client: &costExplorerClient{
+			creds:      creds,
+			endpoint:   endpoint,
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+			now:        time.Now,
+		},
+	}, nil
+}
+
+// SupportsResourceType returns true if usage can be fetched for the resource type.
+func (c *CostExplorerUsage) SupportsResourceType(resourceType string) bool {
+	_, ok := costExplorerServices[resourceType]
+	return ok
+}
+
+// FetchUsage returns the usage keys of the resource that it has usage for. Resources that
+// don't exist yet have no usage, so nothing is returned for them.
+func (c *CostExplorerUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	c.once.Do(func() {
+		services := make([]string, 0, len(costExplorerServices))
+		for _, s := range costExplorerServices {
+			services = append(services, s)
+		}
+		sort.Strings(services)
+
+		c.quantities, c.err = c.client.getUsageQuantities(services)
+	})
+
+	usage := map[string]interface{}{}
+	if c.err != nil {
+		return usage, c.err
+	}
+
+	quantities := c.resourceQuantities(d)
+	if quantities == nil {
+		return usage, nil
+	}
+
+	// Scale the usage over the lookback period to a month
+	scale := hoursInMonth / float64(costExplorerLookbackDays*24)
+	quantity := func(usageType string) (float64, bool) {
+		total, found := 0.0, false
+		for t, q := range quantities {
+			if t == usageType || strings.HasSuffix(t, "-"+usageType) {
+				total += q
+				found = true
+			}
+		}
+		return total * scale, found
+	}
+
+	switch d.Type {
+	case "aws_lambda_function":
+		requests, ok := quantity("Request")
+		if !ok {
+			break
+		}
+		usage["monthly_requests"] = int64(math.Round(requests))
+
+		memoryGB := float64(d.Get("memory_size").Int()) / 1024
+		if memoryGB == 0 {
+			memoryGB = 128.0 / 1024
+		}
+		if gbSeconds, ok := quantity("Lambda-GB-Second"); ok && requests > 0 {
+			usage["request_duration_ms"] = int64(math.Round(gbSeconds / memoryGB / requests * 1000))
+		}
+	case "aws_s3_bucket":
+		if storage, ok := quantity("TimedStorage-ByteHrs"); ok {
+			usage["standard.storage_gb"] = int64(math.Ceil(storage))
+		}
+		if requests, ok := quantity("Requests-Tier1"); ok {
+			usage["standard.monthly_tier_1_requests"] = int64(math.Round(requests))
+		}
+		if requests, ok := quantity("Requests-Tier2"); ok {
+			usage["standard.monthly_tier_2_requests"] = int64(math.Round(requests))
+		}
+	case "aws_nat_gateway":
+		if processed, ok := quantity("NatGateway-Bytes"); ok {
+			usage["monthly_data_processed_gb"] = processed
+		}
+	case "aws_dynamodb_table":
+		if storage, ok := quantity("TimedStorage-ByteHrs"); ok {
+			usage["storage_gb"] = int64(math.Ceil(storage))
+		}
+
+		// Request units are only charged for on-demand tables
+		if d.Get("billing_mode").String() == "PAY_PER_REQUEST" {
+			if units, ok := quantity("ReadRequestUnits"); ok {
+				usage["monthly_read_request_units"] = int64(math.Round(units))
+			}
+			if units, ok := quantity("WriteRequestUnits"); ok {
+				usage["monthly_write_request_units"] = int64(math.Round(units))
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// resourceQuantities returns the usage quantities of the resource by usage type. The
+// resource IDs are ARNs for some services and IDs or names for others, so the resource
+// is matched by any of them.
+func (c *CostExplorerUsage) resourceQuantities(d *schema.ResourceData) map[string]float64 {
+	var matches func(resourceID string) bool
+
+	switch d.Type {
+	case "aws_lambda_function":
+		arn, name := d.Get("arn").String(), d.Get("function_name").String()
+		matches = func(resourceID string) bool {
+			return (arn != "" && resourceID == arn) || (name != "" && strings.HasSuffix(resourceID, ":function:"+name))
+		}
+	case "aws_s3_bucket":
+		bucket := d.Get("bucket").String()
+		matches = func(resourceID string) bool {
+			return bucket != "" && resourceID == bucket
+		}
+	case "aws_nat_gateway":
+		id := d.Get("id").String()
+		matches = func(resourceID string) bool {
+			return id != "" && (resourceID == id || strings.HasSuffix(resourceID, "/"+id))
+		}
+	case "aws_dynamodb_table":
+		arn, name := d.Get("arn").String(), d.Get("name").String()
+		matches = func(resourceID string) bool {
+			return (arn != "" && resourceID == arn) || (name != "" && strings.HasSuffix(resourceID, ":table/"+name))
+		}
+	default:
+		return nil
+	}
+
+	for resourceID, quantities := range c.quantities[costExplorerServices[d.Type]] {
+		if matches(resourceID) {
+			return quantities
+		}
+	}
+
+	return nil
+}
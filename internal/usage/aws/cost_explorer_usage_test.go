@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/awsauth"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestCostExplorerUsage(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "AWSInsightsIndexService.GetCostAndUsageWithResources", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "2021-05-17", gjson.GetBytes(body, "TimePeriod.Start").String())
+		assert.Equal(t, "2021-05-31", gjson.GetBytes(body, "TimePeriod.End").String())
+
+		switch gjson.GetBytes(body, "Filter.Dimensions.Values.0").String() {
+		case "AWS Lambda":
+			if gjson.GetBytes(body, "NextPageToken").String() == "" {
+				_, _ = w.Write([]byte(`{"NextPageToken":"next","ResultsByTime":[{"Groups":[
+					{"Keys":["arn:aws:lambda:us-east-1:123456789012:function:fn","USE1-Request"],"Metrics":{"UsageQuantity":{"Amount":"1000","Unit":"Requests"}}},
+					{"Keys":["arn:aws:lambda:us-east-1:123456789012:function:other","USE1-Request"],"Metrics":{"UsageQuantity":{"Amount":"5","Unit":"Requests"}}}
+				]}]}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"ResultsByTime":[{"Groups":[
+				{"Keys":["arn:aws:lambda:us-east-1:123456789012:function:fn","USE1-Request"],"Metrics":{"UsageQuantity":{"Amount":"1016","Unit":"Requests"}}},
+				{"Keys":["arn:aws:lambda:us-east-1:123456789012:function:fn","USE1-Lambda-GB-Second"],"Metrics":{"UsageQuantity":{"Amount":"504","Unit":"Lambda-GB-Second"}}}
+			]}]}`))
+		case "Amazon DynamoDB":
+			_, _ = w.Write([]byte(`{"ResultsByTime":[{"Groups":[
+				{"Keys":["arn:aws:dynamodb:us-east-1:123456789012:table/table","TimedStorage-ByteHrs"],"Metrics":{"UsageQuantity":{"Amount":"4.2","Unit":"GB-Mo"}}},
+				{"Keys":["arn:aws:dynamodb:us-east-1:123456789012:table/table","ReadRequestUnits"],"Metrics":{"UsageQuantity":{"Amount":"336","Unit":"ReadRequestUnits"}}}
+			]}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"ResultsByTime":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	c := &CostExplorerUsage{
+		client: &costExplorerClient{
+			creds:      awsauth.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"},
+			endpoint:   ts.URL,
+			httpClient: ts.Client(),
+			now: func() time.Time {
+				return time.Date(2021, 5, 31, 15, 0, 0, 0, time.UTC)
+			},
+		},
+	}
+
+	// 2016 requests and 504 GB-seconds over 14 days is 4380 requests a month that take
+	// 500ms each with 512MB of memory
+	d := schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.fn", nil, gjson.Parse(`{"function_name":"fn","memory_size":512}`))
+	usage, err := c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_requests":    int64(4380),
+		"request_duration_ms": int64(500),
+	}, usage)
+
+	// Storage is scaled to the average over the month, and the request units aren't used
+	// for provisioned tables
+	d = schema.NewResourceData("aws_dynamodb_table", "aws", "aws_dynamodb_table.table", nil, gjson.Parse(`{"arn":"arn:aws:dynamodb:us-east-1:123456789012:table/table","billing_mode":"PROVISIONED"}`))
+	usage, err = c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"storage_gb": int64(10)}, usage)
+
+	d = schema.NewResourceData("aws_s3_bucket", "aws", "aws_s3_bucket.new", nil, gjson.Parse(`{"bucket":"new"}`))
+	usage, err = c.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	// The usage of all the services is only fetched once
+	assert.Equal(t, 5, requests)
+}
@@ -0,0 +1,95 @@
+package azure
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	loginEndpoint   = "https://login.microsoftonline.com"
+	managementScope = "https://management.azure.com/.default"
+)
+
+// tokenSource returns OAuth access tokens for the Azure Resource Manager API using the
+// client credentials of a service principal. The credentials are read from the same env
+// vars as the azurerm Terraform provider.
+type tokenSource struct {
+	tenantID     string
+	clientID     string
+	clientSecret string
+	// loginEndpoint replaces the Azure AD endpoint if it's set
+	loginEndpoint string
+	httpClient    *http.Client
+	now           func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+func newTokenSource() (*tokenSource, error) {
+	s := &tokenSource{
+		tenantID:     os.Getenv("ARM_TENANT_ID"),
+		clientID:     os.Getenv("ARM_CLIENT_ID"),
+		clientSecret: os.Getenv("ARM_CLIENT_SECRET"),
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+	}
+
+	if s.tenantID == "" || s.clientID == "" || s.clientSecret == "" {
+		return nil, errors.New("ARM_TENANT_ID, ARM_CLIENT_ID and ARM_CLIENT_SECRET must be set")
+	}
+
+	return s, nil
+}
+
+// token returns an access token, getting a new one if the previous one has expired.
+func (s *tokenSource) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && s.now().Before(s.expiry) {
+		return s.accessToken, nil
+	}
+
+	endpoint := s.loginEndpoint
+	if endpoint == "" {
+		endpoint = loginEndpoint
+	}
+
+	resp, err := s.httpClient.PostForm(fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(endpoint, "/"), url.PathEscape(s.tenantID)), url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"scope":         {managementScope},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting Azure access token")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid response getting Azure access token")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Invalid response getting Azure access token %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	r := gjson.ParseBytes(body)
+	s.accessToken = r.Get("access_token").String()
+	// Refresh the token a minute before it expires
+	s.expiry = s.now().Add(time.Duration(r.Get("expires_in").Int())*time.Second - time.Minute)
+
+	return s.accessToken, nil
+}
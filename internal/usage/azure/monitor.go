@@ -0,0 +1,107 @@
+package azure
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	managementEndpoint    = "https://management.azure.com"
+	monitorMetricsVersion = "2018-01-01"
+)
+
+// monitorLookback is how far back the metrics are queried. The totals over this period
+// are used as the monthly usage.
+var monitorLookback = 30 * 24 * time.Hour
+
+// monitorClient gets resource metrics with the Azure Monitor API.
+type monitorClient struct {
+	tokenSource *tokenSource
+	// endpoint replaces the Azure Resource Manager endpoint if it's set
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// getMetricValue returns the aggregation of the metric of the resource over the lookback
+// period. The metric is queried daily, Total aggregations are summed and Average
+// aggregations are averaged over the days. It returns false if the metric has no values,
+// e.g. if the resource doesn't exist yet.
+func (c *monitorClient) getMetricValue(resourceID, metricName, aggregation string) (float64, bool, error) {
+	end := c.now().UTC()
+	start := end.Add(-monitorLookback)
+
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = managementEndpoint
+	}
+
+	params := url.Values{}
+	params.Set("api-version", monitorMetricsVersion)
+	params.Set("metricnames", metricName)
+	params.Set("aggregation", aggregation)
+	params.Set("interval", "P1D")
+	params.Set("timespan", fmt.Sprintf("%s/%s", start.Format(time.RFC3339), end.Format(time.RFC3339)))
+
+	result, err := c.get(fmt.Sprintf("%s/%s/providers/Microsoft.Insights/metrics?%s", strings.TrimSuffix(endpoint, "/"), strings.TrimPrefix(resourceID, "/"), params.Encode()))
+	if err != nil {
+		return 0, false, err
+	}
+
+	key := strings.ToLower(aggregation)
+
+	total, count := 0.0, 0
+	for _, d := range result.Get("value.#.timeseries|@flatten|#.data|@flatten").Array() {
+		if v := d.Get(key); v.Exists() {
+			total += v.Float()
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, false, nil
+	}
+
+	if aggregation == "Average" {
+		return total / float64(count), true, nil
+	}
+
+	return total, true, nil
+}
+
+func (c *monitorClient) get(u string) (gjson.Result, error) {
+	token, err := c.tokenSource.token()
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error contacting Azure Monitor")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Invalid response from Azure Monitor")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return gjson.Result{}, errors.Errorf("Invalid response from Azure Monitor %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return gjson.ParseBytes(body), nil
+}
@@ -0,0 +1,127 @@
+package azure
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const bytesInGB = 1024 * 1024 * 1024
+
+// MonitorUsage fetches the usage of existing Azure resources from their Azure Monitor
+// metrics for the last 30 days, so their usage-based costs are estimated from their actual
+// usage instead of the usage file.
+type MonitorUsage struct {
+	client *monitorClient
+}
+
+// NewMonitorUsage returns a MonitorUsage that uses the same service principal credentials
+// as the azurerm Terraform provider. The endpoint replaces the Azure Resource Manager
+// endpoint if it's set.
+func NewMonitorUsage(endpoint string) (*MonitorUsage, error) {
+	tokenSource, err := newTokenSource()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MonitorUsage{
+		client: &monitorClient{
+			tokenSource: tokenSource,
+			endpoint:    endpoint,
+			httpClient:  &http.Client{Timeout: 30 * time.Second},
+			now:         time.Now,
+		},
+	}, nil
+}
+
+// SupportsResourceType returns true if usage can be fetched for the resource type.
+func (m *MonitorUsage) SupportsResourceType(resourceType string) bool {
+	switch resourceType {
+	case "azurerm_function_app", "azurerm_storage_account", "azurerm_nat_gateway":
+		return true
+	}
+
+	return false
+}
+
+// FetchUsage returns the usage keys of the resource that have metrics. The metrics are
+// looked up by the resource's ID, so nothing is returned for resources that don't exist yet.
+func (m *MonitorUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	id := d.Get("id").String()
+	if id == "" {
+		return usage, nil
+	}
+
+	switch d.Type {
+	case "azurerm_function_app":
+		return m.functionAppUsage(id)
+	case "azurerm_storage_account":
+		return m.storageAccountUsage(id)
+	case "azurerm_nat_gateway":
+		return m.natGatewayUsage(id)
+	}
+
+	return usage, nil
+}
+
+func (m *MonitorUsage) functionAppUsage(id string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	executions, ok, err := m.client.getMetricValue(id, "FunctionExecutionCount", "Total")
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["monthly_executions"] = int64(math.Round(executions))
+
+	if executions == 0 {
+		return usage, nil
+	}
+
+	responseTime, ok, err := m.client.getMetricValue(id, "AverageResponseTime", "Average")
+	if err != nil || !ok {
+		return usage, err
+	}
+	durationMs := math.Max(1, math.Round(responseTime*1000))
+
+	// The execution units are the MB-milliseconds that are billed, so the memory is the
+	// execution units of each execution over its duration, rounded up to 128MB like it's billed
+	units, ok, err := m.client.getMetricValue(id, "FunctionExecutionUnits", "Total")
+	if err != nil || !ok {
+		return usage, err
+	}
+
+	usage["execution_duration_ms"] = int64(durationMs)
+	usage["memory_mb"] = int64(math.Max(1, math.Ceil(units/executions/durationMs/128))) * 128
+
+	return usage, nil
+}
+
+func (m *MonitorUsage) storageAccountUsage(id string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	// Capacity is only for the blob service since that's how the storage account is priced
+	capacity, ok, err := m.client.getMetricValue(id+"/blobServices/default", "BlobCapacity", "Average")
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["storage_gb"] = int64(math.Ceil(capacity / bytesInGB))
+
+	return usage, nil
+}
+
+func (m *MonitorUsage) natGatewayUsage(id string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	// The byte count is of the data in both directions, which is all processed
+	processed, ok, err := m.client.getMetricValue(id, "ByteCount", "Total")
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["monthly_data_processed_gb"] = processed / bytesInGB
+
+	return usage, nil
+}
@@ -0,0 +1,83 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const functionAppID = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/fn"
+
+func TestMonitorUsage(t *testing.T) {
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenant/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		assert.Equal(t, managementScope, r.Form.Get("scope"))
+
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3599}`)
+	})
+
+	metrics := map[string]string{
+		"FunctionExecutionCount": `[{"total":600},{"total":400},{}]`,
+		"AverageResponseTime":    `[{"average":0.2},{"average":0.3}]`,
+		// 1000 executions of 250ms using 200MB
+		"FunctionExecutionUnits": `[{"total":50000000}]`,
+	}
+	mux.HandleFunc(functionAppID+"/providers/Microsoft.Insights/metrics", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.True(t, strings.HasPrefix(r.URL.Query().Get("timespan"), "2021-05-01T00:00:00Z/"))
+
+		metric := r.URL.Query().Get("metricnames")
+		fmt.Fprintf(w, `{"value":[{"name":{"value":%q},"timeseries":[{"data":%s}]}]}`, metric, metrics[metric])
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	m := &MonitorUsage{
+		client: &monitorClient{
+			tokenSource: &tokenSource{
+				tenantID:      "tenant",
+				clientID:      "client",
+				clientSecret:  "secret",
+				loginEndpoint: ts.URL,
+				httpClient:    ts.Client(),
+				now:           time.Now,
+			},
+			endpoint:   ts.URL,
+			httpClient: ts.Client(),
+			now: func() time.Time {
+				return time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+			},
+		},
+	}
+
+	d := schema.NewResourceData("azurerm_function_app", "azurerm", "azurerm_function_app.fn", nil, gjson.Parse(fmt.Sprintf(`{"id":%q}`, functionAppID)))
+	usage, err := m.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_executions":    int64(1000),
+		"execution_duration_ms": int64(250),
+		"memory_mb":             int64(256),
+	}, usage)
+
+	d = schema.NewResourceData("azurerm_nat_gateway", "azurerm", "azurerm_nat_gateway.new", nil, gjson.Parse(`{}`))
+	usage, err = m.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	// The access token is reused until it expires
+	assert.Equal(t, 1, tokens)
+}
@@ -0,0 +1,249 @@
+package datadog
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	defaultSite     = "datadoghq.com"
+	defaultLookback = 30 * 24 * time.Hour
+	defaultReducer  = "avg"
+)
+
+// Query is a Datadog metric query for a usage key of a resource type. The query is a Go
+// template that's run for each resource of the type. The points of each series of the
+// results are reduced with the reducer, e.g. sum for counts or avg for rates.
+type Query struct {
+	ResourceType string
+	UsageKey     string
+	Query        string
+	Reducer      string
+}
+
+type compiledQuery struct {
+	usageKey string
+	reducer  string
+	template *template.Template
+}
+
+// QueryUsage fetches the usage of resources from the results of Datadog metric queries, for
+// teams whose request volumes and data throughput are recorded in Datadog.
+type QueryUsage struct {
+	endpoint   string
+	apiKey     string
+	appKey     string
+	lookback   time.Duration
+	httpClient *http.Client
+	now        func() time.Time
+
+	// queries are the queries of each resource type
+	queries map[string][]*compiledQuery
+}
+
+// NewQueryUsage returns a QueryUsage for the Datadog site, e.g. datadoghq.eu. The queries are
+// run over the lookback before now.
+func NewQueryUsage(site, apiKey, appKey string, lookback time.Duration, queries []Query) (*QueryUsage, error) {
+	if apiKey == "" || appKey == "" {
+		return nil, errors.New("DD_API_KEY and DD_APP_KEY must be set")
+	}
+
+	if site == "" {
+		site = defaultSite
+	}
+
+	if lookback == 0 {
+		lookback = defaultLookback
+	}
+
+	q := &QueryUsage{
+		endpoint:   fmt.Sprintf("https://api.%s", strings.TrimPrefix(site, "api.")),
+		apiKey:     apiKey,
+		appKey:     appKey,
+		lookback:   lookback,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+		queries:    make(map[string][]*compiledQuery),
+	}
+
+	for _, query := range queries {
+		// The attr function is replaced with the resource's attributes when the query is run
+		t, err := template.New(query.UsageKey).Funcs(template.FuncMap{
+			"attr": func(string) string { return "" },
+		}).Parse(query.Query)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid query for %s of %s", query.UsageKey, query.ResourceType)
+		}
+
+		reducer := query.Reducer
+		if reducer == "" {
+			reducer = defaultReducer
+		}
+
+		q.queries[query.ResourceType] = append(q.queries[query.ResourceType], &compiledQuery{
+			usageKey: query.UsageKey,
+			reducer:  reducer,
+			template: t,
+		})
+	}
+
+	return q, nil
+}
+
+// SupportsResourceType returns true if there are queries for the resource type.
+func (q *QueryUsage) SupportsResourceType(resourceType string) bool {
+	return len(q.queries[resourceType]) > 0
+}
+
+// FetchUsage returns the usage keys of the resource whose queries have results.
+func (q *QueryUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	for _, query := range q.queries[d.Type] {
+		metricQuery, err := renderQuery(query.template, d)
+		if err != nil {
+			return usage, err
+		}
+
+		value, ok, err := q.query(metricQuery, query.reducer)
+		if err != nil {
+			return usage, errors.Wrapf(err, "Error querying %s", query.usageKey)
+		}
+		if ok {
+			usage[query.usageKey] = value
+		}
+	}
+
+	return usage, nil
+}
+
+func renderQuery(t *template.Template, d *schema.ResourceData) (string, error) {
+	data := map[string]string{
+		"Address": d.Address,
+		"Type":    d.Type,
+		"Name":    resourceName(d.Address),
+	}
+
+	var buf bytes.Buffer
+	err := template.Must(t.Clone()).Funcs(template.FuncMap{
+		"attr": func(key string) string { return d.Get(key).String() },
+	}).Execute(&buf, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error rendering query for %s", d.Address)
+	}
+
+	return buf.String(), nil
+}
+
+// resourceName returns the name of the resource in its address, e.g. api for
+// module.app.aws_lb.api[0].
+func resourceName(address string) string {
+	if i := strings.Index(address, "["); i != -1 {
+		address = address[:i]
+	}
+
+	parts := strings.Split(address, ".")
+	return parts[len(parts)-1]
+}
+
+// query runs a timeseries query over the lookback and returns the sum of the reduced values
+// of its series. It returns false if the query has no points, e.g. because the resource
+// doesn't exist yet.
+func (q *QueryUsage) query(metricQuery, reducer string) (float64, bool, error) {
+	to := q.now()
+	from := to.Add(-q.lookback)
+
+	params := url.Values{}
+	params.Set("query", metricQuery)
+	params.Set("from", fmt.Sprintf("%d", from.Unix()))
+	params.Set("to", fmt.Sprintf("%d", to.Unix()))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/query?%s", q.endpoint, params.Encode()), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("DD-API-KEY", q.apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", q.appKey)
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error contacting Datadog")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error reading Datadog response")
+	}
+
+	result := gjson.ParseBytes(body)
+	if resp.StatusCode != http.StatusOK || result.Get("status").String() == "error" {
+		msg := result.Get("error").String()
+		if msg == "" {
+			msg = result.Get("errors.0").String()
+		}
+
+		return 0, false, fmt.Errorf("Datadog returned status code %d: %s", resp.StatusCode, msg)
+	}
+
+	total, found := 0.0, false
+	for _, series := range result.Get("series").Array() {
+		// The points are [timestamp, value] pairs, with null values for gaps
+		points := make([]float64, 0)
+		for _, p := range series.Get("pointlist.#.1").Array() {
+			if p.Type == gjson.Number {
+				points = append(points, p.Float())
+			}
+		}
+
+		if len(points) == 0 {
+			continue
+		}
+
+		total += reduce(points, reducer)
+		found = true
+	}
+
+	return total, found, nil
+}
+
+func reduce(points []float64, reducer string) float64 {
+	v := points[0]
+
+	switch reducer {
+	case "sum", "avg":
+		for _, p := range points[1:] {
+			v += p
+		}
+
+		if reducer == "avg" {
+			v /= float64(len(points))
+		}
+	case "min":
+		for _, p := range points[1:] {
+			if p < v {
+				v = p
+			}
+		}
+	case "max":
+		for _, p := range points[1:] {
+			if p > v {
+				v = p
+			}
+		}
+	case "last":
+		v = points[len(points)-1]
+	}
+
+	return v
+}
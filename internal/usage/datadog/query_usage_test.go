@@ -0,0 +1,89 @@
+package datadog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestQueryUsage(t *testing.T) {
+	results := map[string]string{
+		`sum:trace.http.request.hits{service:orders}.as_count()`: `{"status":"ok","series":[{"pointlist":[[1619827200000,400000],[1619913600000,null],[1620000000000,500000]]},{"pointlist":[[1619827200000,100000]]}]}`,
+		`avg:trace.http.request.bytes{service:orders}`:           `{"status":"ok","series":[{"pointlist":[[1619827200000,10],[1620000000000,20]]}]}`,
+		`sum:trace.http.request.hits{service:new}.as_count()`:    `{"status":"ok","series":[]}`,
+		`avg:trace.http.request.bytes{service:new}`:              `{"status":"ok","series":[]}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "api-key", r.Header.Get("DD-API-KEY"))
+		assert.Equal(t, "app-key", r.Header.Get("DD-APPLICATION-KEY"))
+		assert.Equal(t, "1619827200", r.URL.Query().Get("from"))
+		assert.Equal(t, "1622419200", r.URL.Query().Get("to"))
+
+		result, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":["unexpected query"]}`)
+			return
+		}
+
+		fmt.Fprint(w, result)
+	}))
+	defer ts.Close()
+
+	q, err := NewQueryUsage("datadoghq.eu", "api-key", "app-key", 0, []Query{
+		{ResourceType: "aws_lb", UsageKey: "monthly_requests", Query: `sum:trace.http.request.hits{service:{{ attr "name" }}}.as_count()`, Reducer: "sum"},
+		{ResourceType: "aws_lb", UsageKey: "request_size_kb", Query: `avg:trace.http.request.bytes{service:{{ attr "name" }}}`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.datadoghq.eu", q.endpoint)
+
+	q.endpoint = ts.URL
+	q.httpClient = ts.Client()
+	q.now = func() time.Time {
+		return time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	assert.True(t, q.SupportsResourceType("aws_lb"))
+	assert.False(t, q.SupportsResourceType("aws_lambda_function"))
+
+	d := schema.NewResourceData("aws_lb", "aws", "aws_lb.orders", nil, gjson.Parse(`{"name":"orders"}`))
+	usage, err := q.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_requests": float64(1000000),
+		"request_size_kb":  float64(15),
+	}, usage)
+
+	d = schema.NewResourceData("aws_lb", "aws", "aws_lb.new", nil, gjson.Parse(`{"name":"new"}`))
+	usage, err = q.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	d = schema.NewResourceData("aws_lb", "aws", "aws_lb.other", nil, gjson.Parse(`{"name":"other"}`))
+	_, err = q.FetchUsage(d)
+	assert.EqualError(t, err, "Error querying monthly_requests: Datadog returned status code 400: unexpected query")
+}
+
+func TestReduce(t *testing.T) {
+	points := []float64{3, 1, 4, 2}
+
+	assert.Equal(t, 10.0, reduce(points, "sum"))
+	assert.Equal(t, 2.5, reduce(points, "avg"))
+	assert.Equal(t, 1.0, reduce(points, "min"))
+	assert.Equal(t, 4.0, reduce(points, "max"))
+	assert.Equal(t, 2.0, reduce(points, "last"))
+}
+
+func TestNewQueryUsageWithoutKeys(t *testing.T) {
+	_, err := NewQueryUsage("", "api-key", "", 0, nil)
+	assert.EqualError(t, err, "DD_API_KEY and DD_APP_KEY must be set")
+}
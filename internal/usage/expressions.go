@@ -0,0 +1,307 @@
+package usage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// usageExpressionRefRegex matches the references in an expression, e.g. ${daily_requests}.
+var usageExpressionRefRegex = regexp.MustCompile(`\$\{\s*([\w.\[\]]+)\s*\}`)
+
+// isUsageExpression returns true if a usage value is an expression, e.g.
+// "${daily_requests} * 30", rather than a number with a unit.
+func isUsageExpression(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.Contains(s, "${")
+}
+
+// usageValueRef is where a usage value is in the parsed usage file, so it can be replaced
+// with the value of its expression.
+type usageValueRef struct {
+	m map[interface{}]interface{}
+	k interface{}
+}
+
+// usageExpressionEvaluator evaluates the expressions in the usage of a resource or in the
+// variables of a usage file. References are to the resource's other keys, with dots for
+// nested keys, e.g. ${standard.storage_gb}, or to the variables.
+type usageExpressionEvaluator struct {
+	values     map[string]usageValueRef
+	variables  map[string]float64
+	evaluating map[string]bool
+}
+
+func newUsageExpressionEvaluator(usage interface{}, variables map[string]float64) *usageExpressionEvaluator {
+	e := &usageExpressionEvaluator{
+		values:     make(map[string]usageValueRef),
+		variables:  variables,
+		evaluating: make(map[string]bool),
+	}
+	e.addValues("", usage)
+
+	return e
+}
+
+func (e *usageExpressionEvaluator) addValues(prefix string, usage interface{}) {
+	m, ok := usage.(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+
+	for k, v := range m {
+		key := prefix + fmt.Sprint(k)
+		if _, ok := v.(map[interface{}]interface{}); ok {
+			e.addValues(key+".", v)
+			continue
+		}
+
+		e.values[key] = usageValueRef{m: m, k: k}
+	}
+}
+
+// evaluateAll replaces the expressions with their values.
+func (e *usageExpressionEvaluator) evaluateAll() error {
+	for key, ref := range e.values {
+		if !isUsageExpression(ref.m[ref.k]) {
+			continue
+		}
+
+		if _, err := e.evaluate(key); err != nil {
+			return fmt.Errorf("%s %s", key, err)
+		}
+	}
+
+	return nil
+}
+
+// evaluate returns the number value of the key, evaluating it first if it's an expression.
+func (e *usageExpressionEvaluator) evaluate(key string) (float64, error) {
+	ref := e.values[key]
+
+	switch v := ref.m[ref.k].(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		if !isUsageExpression(v) {
+			return normalizeUsageValue(key, v)
+		}
+
+		if e.evaluating[key] {
+			return 0, fmt.Errorf("refers to itself")
+		}
+		e.evaluating[key] = true
+		defer delete(e.evaluating, key)
+
+		n, err := evaluateUsageExpression(v, e.lookup)
+		if err != nil {
+			return 0, err
+		}
+
+		ref.m[ref.k] = n
+		return n, nil
+	default:
+		return 0, fmt.Errorf("should be a number, got %v", v)
+	}
+}
+
+func (e *usageExpressionEvaluator) lookup(name string) (float64, error) {
+	if _, ok := e.values[name]; ok {
+		n, err := e.evaluate(name)
+		if err != nil {
+			return 0, fmt.Errorf("refers to %s, which %s", name, err)
+		}
+
+		return n, nil
+	}
+
+	if n, ok := e.variables[name]; ok {
+		return n, nil
+	}
+
+	return 0, fmt.Errorf("refers to %s, which isn't a usage key or variable", name)
+}
+
+// evaluateUsageVariables returns the values of the variables of a usage file. Variables can
+// have units like usage keys, in the units of their names, and be expressions of the other
+// variables.
+func evaluateUsageVariables(variables map[string]interface{}) (map[string]float64, error) {
+	m := make(map[interface{}]interface{}, len(variables))
+	for k, v := range variables {
+		m[k] = v
+	}
+
+	e := newUsageExpressionEvaluator(m, nil)
+
+	values := make(map[string]float64, len(e.values))
+	for name := range e.values {
+		n, err := e.evaluate(name)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid variable %s: %s", name, err)
+		}
+
+		values[name] = n
+	}
+
+	return values, nil
+}
+
+// evaluateUsageExpressions replaces the expressions in the usage of each resource with
+// their values.
+func evaluateUsageExpressions(resources map[string]interface{}, variables map[string]float64) error {
+	for name, usage := range resources {
+		if err := newUsageExpressionEvaluator(usage, variables).evaluateAll(); err != nil {
+			return fmt.Errorf("Invalid expression in the usage of %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// evaluateUsageExpression evaluates an expression of numbers and references with +, -, *,
+// / and parentheses, e.g. "(${daily_requests} + 1000) * 30".
+func evaluateUsageExpression(expr string, lookup func(string) (float64, error)) (float64, error) {
+	p := &usageExpressionParser{expr: expr, lookup: lookup}
+
+	n, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.expr) {
+		return 0, fmt.Errorf("has an unexpected %q in %q", p.expr[p.pos], expr)
+	}
+
+	return n, nil
+}
+
+type usageExpressionParser struct {
+	expr   string
+	pos    int
+	lookup func(string) (float64, error)
+}
+
+func (p *usageExpressionParser) skipSpaces() {
+	for p.pos < len(p.expr) && p.expr[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *usageExpressionParser) next() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.expr) {
+		return 0
+	}
+
+	return p.expr[p.pos]
+}
+
+func (p *usageExpressionParser) parseSum() (float64, error) {
+	n, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op := p.next()
+		if op != '+' && op != '-' {
+			return n, nil
+		}
+		p.pos++
+
+		m, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '+' {
+			n += m
+		} else {
+			n -= m
+		}
+	}
+}
+
+func (p *usageExpressionParser) parseProduct() (float64, error) {
+	n, err := p.parseOperand()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		op := p.next()
+		if op != '*' && op != '/' {
+			return n, nil
+		}
+		p.pos++
+
+		m, err := p.parseOperand()
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '*' {
+			n *= m
+		} else if m == 0 {
+			return 0, fmt.Errorf("divides by zero in %q", p.expr)
+		} else {
+			n /= m
+		}
+	}
+}
+
+func (p *usageExpressionParser) parseOperand() (float64, error) {
+	switch c := p.next(); {
+	case c == '-':
+		p.pos++
+		n, err := p.parseOperand()
+		return -n, err
+	case c == '(':
+		p.pos++
+		n, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+
+		if p.next() != ')' {
+			return 0, fmt.Errorf("is missing a ) in %q", p.expr)
+		}
+		p.pos++
+
+		return n, nil
+	case c == '$':
+		loc := usageExpressionRefRegex.FindStringSubmatchIndex(p.expr[p.pos:])
+		if loc == nil || loc[0] != 0 {
+			return 0, fmt.Errorf("has an invalid reference in %q", p.expr)
+		}
+
+		name := p.expr[p.pos+loc[2] : p.pos+loc[3]]
+		p.pos += loc[1]
+
+		return p.lookup(name)
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.expr) && (p.expr[p.pos] == '.' || (p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9')) {
+			p.pos++
+		}
+
+		n, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+		if err != nil {
+			return 0, fmt.Errorf("has an invalid number %q in %q", p.expr[start:p.pos], p.expr)
+		}
+
+		return n, nil
+	case c == 0:
+		return 0, fmt.Errorf("ends unexpectedly in %q", p.expr)
+	default:
+		return 0, fmt.Errorf("has an unexpected %q in %q", c, p.expr)
+	}
+}
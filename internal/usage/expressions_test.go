@@ -0,0 +1,104 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateUsageExpression(t *testing.T) {
+	lookup := func(name string) (float64, error) {
+		return map[string]float64{"daily_requests": 1000, "zero": 0}[name], nil
+	}
+
+	tests := []struct {
+		expr     string
+		expected float64
+		err      string
+	}{
+		{expr: "${daily_requests} * 30", expected: 30000},
+		{expr: "${ daily_requests }*30", expected: 30000},
+		{expr: "(${daily_requests} + 500) * 2 / 4", expected: 750},
+		{expr: "-${daily_requests} + 1.5", expected: -998.5},
+		{expr: "${daily_requests} - 100 - 100", expected: 800},
+		{expr: "${daily_requests} *", err: `ends unexpectedly in "${daily_requests} *"`},
+		{expr: "(${daily_requests} * 2", err: `is missing a ) in "(${daily_requests} * 2"`},
+		{expr: "${daily_requests} / ${zero}", err: `divides by zero in "${daily_requests} / ${zero}"`},
+		{expr: "${daily_requests} x 2", err: `has an unexpected 'x' in "${daily_requests} x 2"`},
+		{expr: "${daily-requests}", err: `has an invalid reference in "${daily-requests}"`},
+		{expr: "1.2.3 * ${zero}", err: `has an invalid number "1.2.3" in "1.2.3 * ${zero}"`},
+	}
+
+	for _, tt := range tests {
+		actual, err := evaluateUsageExpression(tt.expr, lookup)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.expr)
+			continue
+		}
+
+		require.NoError(t, err, tt.expr)
+		assert.InDelta(t, tt.expected, actual, 1e-9, tt.expr)
+	}
+}
+
+func TestParseUsageExpressions(t *testing.T) {
+	_, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: ${duration_ms}
+`))
+	assert.EqualError(t, err, "Invalid expression in the usage of aws_lambda_function: request_duration_ms refers to duration_ms, which isn't a usage key or variable")
+
+	usage, err := parseYAML([]byte(`version: 0.1
+variables:
+  daily_requests: 100k
+  peak_daily_requests: ${daily_requests} * 2
+resource_usage:
+  aws_lambda_function.fn:
+    monthly_requests: ${daily_requests} * 30
+    request_duration_ms: 250
+  aws_lambda_function.peak:
+    monthly_requests: {min: "${daily_requests} * 30", max: "${peak_daily_requests} * 30"}
+    request_duration_ms: ${monthly_requests.max} / 24000
+  aws_s3_bucket.bucket:
+    standard:
+      storage_gb: 1 TiB
+      monthly_tier_1_requests: ${standard.storage_gb} * 10
+`))
+	require.NoError(t, err)
+
+	fn := schema.FindUsageData(usage, "aws_lambda_function.fn")
+	assert.Equal(t, int64(3000000), fn.Get("monthly_requests").Int())
+
+	peak := schema.FindUsageData(usage, "aws_lambda_function.peak")
+	assert.Equal(t, int64(4500000), peak.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), peak.Get("request_duration_ms").Int())
+	r, ok := peak.Range("monthly_requests")
+	require.True(t, ok)
+	assert.Equal(t, schema.UsageRange{Low: 3000000, High: 6000000}, r)
+
+	// References to values with units use the value in the unit of the key
+	bucket := schema.FindUsageData(usage, "aws_s3_bucket.bucket")
+	assert.Equal(t, int64(10240), bucket.Get("standard.monthly_tier_1_requests").Int())
+}
+
+func TestParseUsageExpressionCycles(t *testing.T) {
+	_, err := parseYAML([]byte(`version: 0.1
+resource_usage:
+  aws_lambda_function.fn:
+    monthly_requests: ${request_duration_ms} * 2
+    request_duration_ms: ${monthly_requests} / 2
+`))
+	require.Error(t, err)
+	assert.Regexp(t, `which refers to (monthly_requests|request_duration_ms), which refers to itself`, err.Error())
+
+	_, err = parseYAML([]byte(`version: 0.1
+variables:
+  a: ${b}
+  b: ${a}
+`))
+	require.Error(t, err)
+	assert.Regexp(t, `Invalid variable (a|b): refers to (a|b), which refers to (a|b), which refers to itself`, err.Error())
+}
@@ -0,0 +1,167 @@
+package google
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const monitoringReadScope = "https://www.googleapis.com/auth/monitoring.read"
+
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// tokenSource returns OAuth access tokens for the Google Cloud APIs. The credentials are
+// read from the same env vars as the google Terraform provider: an access token in
+// GOOGLE_OAUTH_ACCESS_TOKEN, or a service account key in GOOGLE_CREDENTIALS or the file
+// in GOOGLE_APPLICATION_CREDENTIALS.
+type tokenSource struct {
+	accessToken string
+	key         *serviceAccountKey
+	httpClient  *http.Client
+	now         func() time.Time
+
+	mu     sync.Mutex
+	expiry time.Time
+}
+
+func newTokenSource() (*tokenSource, error) {
+	s := &tokenSource{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+	}
+
+	if t := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); t != "" {
+		s.accessToken = t
+		return s, nil
+	}
+
+	j := []byte(os.Getenv("GOOGLE_CREDENTIALS"))
+	if len(j) == 0 {
+		path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		if path == "" {
+			return nil, errors.New("GOOGLE_OAUTH_ACCESS_TOKEN, GOOGLE_CREDENTIALS or GOOGLE_APPLICATION_CREDENTIALS must be set")
+		}
+
+		var err error
+		j, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading Google credentials file")
+		}
+	}
+
+	key := &serviceAccountKey{}
+	if err := json.Unmarshal(j, key); err != nil {
+		return nil, errors.Wrap(err, "Error parsing Google credentials")
+	}
+	if key.Type != "service_account" {
+		return nil, errors.Errorf("Google credentials of type %s are not supported, use a service account key", key.Type)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	s.key = key
+
+	return s, nil
+}
+
+// token returns an access token, exchanging a signed JWT of the service account for a new
+// one if the previous one has expired.
+func (s *tokenSource) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key == nil || (s.accessToken != "" && s.now().Before(s.expiry)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.signedJWT()
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.PostForm(s.key.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Error getting Google access token")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid response getting Google access token")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Invalid response getting Google access token %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	r := gjson.ParseBytes(body)
+	s.accessToken = r.Get("access_token").String()
+	// Refresh the token a minute before it expires
+	s.expiry = s.now().Add(time.Duration(r.Get("expires_in").Int())*time.Second - time.Minute)
+
+	return s.accessToken, nil
+}
+
+func (s *tokenSource) signedJWT() (string, error) {
+	block, _ := pem.Decode([]byte(s.key.PrivateKey))
+	if block == nil {
+		return "", errors.New("Invalid private key in Google credentials")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid private key in Google credentials")
+	}
+
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("Invalid private key in Google credentials, expected an RSA key")
+	}
+
+	now := s.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   s.key.ClientEmail,
+		"scope": monitoringReadScope,
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", errors.Wrap(err, "Error signing Google credentials JWT")
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
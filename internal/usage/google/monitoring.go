@@ -0,0 +1,130 @@
+package google
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const monitoringEndpoint = "https://monitoring.googleapis.com"
+
+// monitoringLookback is how far back the metrics are queried. The sums over this period
+// are used as the monthly usage.
+var monitoringLookback = 30 * 24 * time.Hour
+
+type timeSeriesQuery struct {
+	project string
+	// filter selects the metric and the resource, e.g. metric.type="..." AND resource.labels.bucket_name="..."
+	filter string
+	// aligner is how each series is aligned over the lookback, e.g. ALIGN_SUM, ALIGN_MEAN
+	// or ALIGN_DELTA for distributions
+	aligner string
+}
+
+// monitoringClient lists time series with the Cloud Monitoring API.
+type monitoringClient struct {
+	tokenSource *tokenSource
+	// endpoint replaces the Cloud Monitoring endpoint if it's set
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// getTimeSeriesValue returns the value of the time series over the lookback period. The
+// values of the series, e.g. the series of each storage class of a bucket, are summed, and
+// distributions are averaged over all the series. It returns false if there are no points,
+// e.g. if the resource doesn't exist yet.
+func (c *monitoringClient) getTimeSeriesValue(q timeSeriesQuery) (float64, bool, error) {
+	end := c.now().UTC()
+	start := end.Add(-monitoringLookback)
+
+	endpoint := c.endpoint
+	if endpoint == "" {
+		endpoint = monitoringEndpoint
+	}
+
+	params := url.Values{}
+	params.Set("filter", q.filter)
+	params.Set("interval.startTime", start.Format(time.RFC3339))
+	params.Set("interval.endTime", end.Format(time.RFC3339))
+	params.Set("aggregation.alignmentPeriod", fmt.Sprintf("%ds", int(monitoringLookback.Seconds())))
+	params.Set("aggregation.perSeriesAligner", q.aligner)
+
+	total, distributionCount, distributionSum := 0.0, 0.0, 0.0
+	found, isDistribution := false, false
+
+	for {
+		result, err := c.get(fmt.Sprintf("%s/v3/projects/%s/timeSeries?%s", strings.TrimSuffix(endpoint, "/"), url.PathEscape(q.project), params.Encode()))
+		if err != nil {
+			return 0, false, err
+		}
+
+		for _, p := range result.Get("timeSeries.#.points|@flatten").Array() {
+			found = true
+
+			value := p.Get("value")
+			switch {
+			case value.Get("distributionValue").Exists():
+				isDistribution = true
+				count := value.Get("distributionValue.count").Float()
+				distributionCount += count
+				distributionSum += count * value.Get("distributionValue.mean").Float()
+			case value.Get("int64Value").Exists():
+				total += value.Get("int64Value").Float()
+			default:
+				total += value.Get("doubleValue").Float()
+			}
+		}
+
+		pageToken := result.Get("nextPageToken").String()
+		if pageToken == "" {
+			break
+		}
+		params.Set("pageToken", pageToken)
+	}
+
+	if isDistribution {
+		if distributionCount == 0 {
+			return 0, false, nil
+		}
+		return distributionSum / distributionCount, true, nil
+	}
+
+	return total, found, nil
+}
+
+func (c *monitoringClient) get(u string) (gjson.Result, error) {
+	token, err := c.tokenSource.token()
+	if err != nil {
+		return gjson.Result{}, err
+	}
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error generating request")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Error contacting Cloud Monitoring")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return gjson.Result{}, errors.Wrap(err, "Invalid response from Cloud Monitoring")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return gjson.Result{}, errors.Errorf("Invalid response from Cloud Monitoring %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return gjson.ParseBytes(body), nil
+}
@@ -0,0 +1,145 @@
+package google
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const bytesInGB = 1024 * 1024 * 1024
+
+// MonitoringUsage fetches the usage of existing Google Cloud resources from their Cloud
+// Monitoring metrics for the last 30 days, so their usage-based costs are estimated from
+// their actual usage instead of the usage file.
+type MonitoringUsage struct {
+	client *monitoringClient
+}
+
+// NewMonitoringUsage returns a MonitoringUsage that uses the same credentials as the
+// google Terraform provider. The endpoint replaces the Cloud Monitoring endpoint if it's set.
+func NewMonitoringUsage(endpoint string) (*MonitoringUsage, error) {
+	tokenSource, err := newTokenSource()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MonitoringUsage{
+		client: &monitoringClient{
+			tokenSource: tokenSource,
+			endpoint:    endpoint,
+			httpClient:  &http.Client{Timeout: 30 * time.Second},
+			now:         time.Now,
+		},
+	}, nil
+}
+
+// SupportsResourceType returns true if usage can be fetched for the resource type.
+func (m *MonitoringUsage) SupportsResourceType(resourceType string) bool {
+	switch resourceType {
+	case "google_cloudfunctions_function", "google_storage_bucket", "google_sql_database_instance":
+		return true
+	}
+
+	return false
+}
+
+// FetchUsage returns the usage keys of the resource that have metrics. Resources that
+// don't exist yet have no metrics, so nothing is returned for them.
+func (m *MonitoringUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	project := d.Get("project").String()
+	if project == "" {
+		project = os.Getenv("GOOGLE_PROJECT")
+	}
+	if project == "" {
+		return usage, nil
+	}
+
+	switch d.Type {
+	case "google_cloudfunctions_function":
+		return m.cloudFunctionUsage(project, d.Get("name").String())
+	case "google_storage_bucket":
+		return m.storageBucketUsage(project, d.Get("name").String())
+	case "google_sql_database_instance":
+		return m.sqlDatabaseInstanceUsage(project, d.Get("name").String())
+	}
+
+	return usage, nil
+}
+
+func (m *MonitoringUsage) cloudFunctionUsage(project, name string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if name == "" {
+		return usage, nil
+	}
+
+	filter := func(metric string) string {
+		return fmt.Sprintf(`metric.type="cloudfunctions.googleapis.com/function/%s" AND resource.labels.function_name=%q`, metric, name)
+	}
+
+	invocations, ok, err := m.client.getTimeSeriesValue(timeSeriesQuery{project, filter("execution_count"), "ALIGN_SUM"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["monthly_function_invocations"] = int64(math.Round(invocations))
+
+	// The execution times are a distribution in nanoseconds
+	duration, ok, err := m.client.getTimeSeriesValue(timeSeriesQuery{project, filter("execution_times"), "ALIGN_DELTA"})
+	if err != nil {
+		return usage, err
+	}
+	if ok {
+		usage["request_duration_ms"] = int64(math.Round(duration / float64(time.Millisecond)))
+	}
+
+	egress, ok, err := m.client.getTimeSeriesValue(timeSeriesQuery{project, filter("network_egress"), "ALIGN_SUM"})
+	if err != nil {
+		return usage, err
+	}
+	if ok {
+		usage["monthly_outbound_data_gb"] = int64(math.Ceil(egress / bytesInGB))
+	}
+
+	return usage, nil
+}
+
+func (m *MonitoringUsage) storageBucketUsage(project, bucket string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if bucket == "" {
+		return usage, nil
+	}
+
+	filter := fmt.Sprintf(`metric.type="storage.googleapis.com/storage/total_bytes" AND resource.labels.bucket_name=%q`, bucket)
+
+	size, ok, err := m.client.getTimeSeriesValue(timeSeriesQuery{project, filter, "ALIGN_MEAN"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["storage_gb"] = int64(math.Ceil(size / bytesInGB))
+
+	return usage, nil
+}
+
+func (m *MonitoringUsage) sqlDatabaseInstanceUsage(project, name string) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+	if name == "" {
+		return usage, nil
+	}
+
+	filter := fmt.Sprintf(`metric.type="cloudsql.googleapis.com/database/disk/bytes_used" AND resource.labels.database_id=%q`, project+":"+name)
+
+	// The disk size is priced from the instance's settings, so the data used is the
+	// estimate of the backup storage, since backups are the size of the data
+	size, ok, err := m.client.getTimeSeriesValue(timeSeriesQuery{project, filter, "ALIGN_MEAN"})
+	if err != nil || !ok {
+		return usage, err
+	}
+	usage["backup_storage_gb"] = int64(math.Ceil(size / bytesInGB))
+
+	return usage, nil
+}
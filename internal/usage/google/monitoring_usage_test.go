@@ -0,0 +1,118 @@
+package google
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestMonitoringUsage(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		require.NoError(t, r.ParseForm())
+
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature))
+
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.Equal(t, "infracost@project.iam.gserviceaccount.com", gjson.GetBytes(claims, "iss").String())
+		assert.Equal(t, monitoringReadScope, gjson.GetBytes(claims, "scope").String())
+
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+	})
+
+	series := map[string]string{
+		"execution_count": `{"timeSeries":[{"points":[{"value":{"int64Value":"1200"}}]},{"points":[{"value":{"int64Value":"300"}}]}]}`,
+		"execution_times": `{"timeSeries":[
+			{"points":[{"value":{"distributionValue":{"count":"1200","mean":200000000}}}]},
+			{"points":[{"value":{"distributionValue":{"count":"300","mean":50000000}}}]}
+		]}`,
+		"network_egress": `{}`,
+		"total_bytes":    `{"timeSeries":[{"points":[{"value":{"doubleValue":1073741824}}]},{"points":[{"value":{"doubleValue":536870912}}]}]}`,
+	}
+	mux.HandleFunc("/v3/projects/project/timeSeries", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "2021-05-01T00:00:00Z", r.URL.Query().Get("interval.startTime"))
+
+		for metric, s := range series {
+			if strings.Contains(r.URL.Query().Get("filter"), "/"+metric+`"`) {
+				fmt.Fprint(w, s)
+				return
+			}
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	m := &MonitoringUsage{
+		client: &monitoringClient{
+			tokenSource: &tokenSource{
+				key: &serviceAccountKey{
+					Type:        "service_account",
+					ClientEmail: "infracost@project.iam.gserviceaccount.com",
+					PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+					TokenURI:    ts.URL + "/token",
+				},
+				httpClient: ts.Client(),
+				now:        time.Now,
+			},
+			endpoint:   ts.URL,
+			httpClient: ts.Client(),
+			now: func() time.Time {
+				return time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+			},
+		},
+	}
+
+	d := schema.NewResourceData("google_cloudfunctions_function", "google", "google_cloudfunctions_function.fn", nil, gjson.Parse(`{"project":"project","name":"fn"}`))
+	usage, err := m.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_function_invocations": int64(1500),
+		"request_duration_ms":          int64(170),
+	}, usage)
+
+	d = schema.NewResourceData("google_storage_bucket", "google", "google_storage_bucket.bucket", nil, gjson.Parse(`{"project":"project","name":"bucket"}`))
+	usage, err = m.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"storage_gb": int64(2)}, usage)
+
+	d = schema.NewResourceData("google_sql_database_instance", "google", "google_sql_database_instance.new", nil, gjson.Parse(`{"project":"project","name":"new"}`))
+	usage, err = m.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	// The access token is reused until it expires
+	assert.Equal(t, 1, tokens)
+}
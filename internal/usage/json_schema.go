@@ -0,0 +1,141 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JSONSchema returns the usage schema as a JSON Schema, which editors can use to check and
+// complete usage files in YAML or JSON. The usage of each resource address is matched by its
+// resource type.
+func JSONSchema() ([]byte, error) {
+	usageSchema, err := loadUsageSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTypes := make([]string, 0, len(usageSchema))
+	for resourceType := range usageSchema {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	resourceUsage := make(map[string]interface{}, len(resourceTypes))
+	typeDefaultUsage := make(map[string]interface{}, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		s := jsonSchemaObject(usageSchema[resourceType], "", true)
+
+		// Resource addresses can have module names before the type and an index after the name
+		pattern := fmt.Sprintf(`^(.+\.)?%s\.[^.\[]+(\[.*\])?$`, regexp.QuoteMeta(resourceType))
+		resourceUsage[pattern] = s
+		typeDefaultUsage[fmt.Sprintf("^%s$", regexp.QuoteMeta(resourceType))] = s
+	}
+
+	j, err := json.MarshalIndent(map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"title":                "Infracost usage file",
+		"type":                 "object",
+		"required":             []string{"version"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"version": map[string]interface{}{
+				"type": "string",
+				"enum": []string{maxUsageFileVersion},
+			},
+			"resource_usage": map[string]interface{}{
+				"type":              []string{"object", "null"},
+				"patternProperties": resourceUsage,
+			},
+			"resource_type_default_usage": map[string]interface{}{
+				"type":              []string{"object", "null"},
+				"patternProperties": typeDefaultUsage,
+			},
+			"variables": map[string]interface{}{
+				"type":                 []string{"object", "null"},
+				"additionalProperties": map[string]interface{}{"type": []string{"number", "string"}},
+			},
+			// Anchors are checked against the resource types that use them when the file is loaded
+			"usage_anchors": map[string]interface{}{
+				"type":                 []string{"object", "null"},
+				"additionalProperties": map[string]interface{}{"type": []string{"object", "null"}},
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshaling usage JSON Schema")
+	}
+
+	return j, nil
+}
+
+// jsonSchemaObject returns the JSON Schema of the keys with the prefix.
+func jsonSchemaObject(keys map[string]*usageKeySchema, prefix string, global bool) map[string]interface{} {
+	properties := make(map[string]interface{})
+	patternProperties := make(map[string]interface{})
+
+	for key, k := range keys {
+		name := strings.TrimPrefix(key, prefix)
+		if !strings.HasPrefix(key, prefix) || strings.Contains(name, ".") {
+			continue
+		}
+
+		// The usage of the first of a list of blocks, e.g. node_pool[0], applies to any of them
+		if usageKeyIndexRegex.MatchString(name) {
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(name), `\[0\]`, `\[\d+\]`)
+			patternProperties["^"+pattern+"$"] = jsonSchemaValue(keys, key, k)
+			continue
+		}
+
+		properties[name] = jsonSchemaValue(keys, key, k)
+	}
+
+	if global {
+		for key, k := range globalUsageKeys {
+			properties[key] = jsonSchemaValue(keys, key, k)
+		}
+	}
+
+	s := map[string]interface{}{
+		"type":                 []string{"object", "null"},
+		"additionalProperties": false,
+		"properties":           properties,
+	}
+	if len(patternProperties) > 0 {
+		s["patternProperties"] = patternProperties
+	}
+
+	return s
+}
+
+func jsonSchemaValue(keys map[string]*usageKeySchema, key string, k *usageKeySchema) map[string]interface{} {
+	switch k.kind {
+	case mappingKind:
+		return jsonSchemaObject(keys, key+".", false)
+	case stringKind:
+		s := map[string]interface{}{"type": []string{"string", "null"}}
+		// The options aren't an enum since they're matched case-insensitively
+		if len(k.options) > 0 {
+			s["examples"] = k.options
+		}
+		return s
+	case numberKind:
+		// Numbers can also be strings with a unit, e.g. "500 GiB", or ranges
+		rangeValues := make(map[string]interface{}, len(usageRangeKeys))
+		for _, rk := range usageRangeKeys {
+			rangeValues[rk] = map[string]interface{}{"type": []string{"number", "string"}}
+		}
+
+		return map[string]interface{}{
+			"type":                 []string{"number", "string", "object", "null"},
+			"properties":           rangeValues,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]interface{}{"type": []string{k.kind, "null"}}
+	}
+}
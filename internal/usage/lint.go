@@ -0,0 +1,222 @@
+package usage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// LintProblem is a problem with a usage file. Line is 0 for problems with resources that
+// aren't in the usage file.
+type LintProblem struct {
+	Line    int
+	Message string
+}
+
+func (p *LintProblem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Linter finds the problems with a usage file that make the estimates of a project less
+// accurate, such as usage for resources that have been removed or renamed. Unlike loading
+// the usage file, it doesn't stop at keys that don't match the usage schema so all the
+// problems can be reported at once.
+type Linter struct {
+	doc   *yamlv3.Node
+	usage map[string]*schema.UsageData
+}
+
+func NewLinter(usageFilePath string) (*Linter, error) {
+	out, err := ioutil.ReadFile(usageFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading usage file")
+	}
+	out = jsonAsYAML(out)
+
+	usage, err := parseUsageMap(out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing usage file")
+	}
+
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(out, doc); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing usage file")
+	}
+
+	return &Linter{doc: doc, usage: usage}, nil
+}
+
+// UsageData returns the usage data of the usage file to load the project's resources with.
+func (l *Linter) UsageData() map[string]*schema.UsageData {
+	return l.usage
+}
+
+// Lint returns the problems with the usage file for the resources of a project, which should
+// have been loaded with the usage data of the linter. These are the keys that don't match the
+// usage schema or are deprecated, the usage of resources and resource types that aren't in
+// the project, and the resources that have usage-based cost components without any usage.
+func (l *Linter) Lint(resources []*schema.Resource) ([]*LintProblem, error) {
+	if l.doc.Kind != yamlv3.DocumentNode || len(l.doc.Content) == 0 {
+		return l.lintResources(resources, nil), nil
+	}
+
+	usageSchema, err := loadUsageSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &usageValidator{schema: usageSchema}
+	v.validateFile(l.doc.Content[0])
+
+	problems := append(v.problems, v.deprecations...)
+
+	resourceUsage := findMappingValue(l.doc.Content[0], "resource_usage")
+	if resourceUsage != nil && resourceUsage.Kind == yamlv3.MappingNode {
+		problems = append(problems, l.lintAddresses(resourceUsage, resources)...)
+	}
+
+	typeDefaults := findMappingValue(l.doc.Content[0], "resource_type_default_usage")
+	if typeDefaults != nil && typeDefaults.Kind == yamlv3.MappingNode {
+		problems = append(problems, l.lintResourceTypes(typeDefaults, resources)...)
+	}
+
+	problems = append(problems, l.lintResources(resources, resourceUsage)...)
+
+	// Problems are in the order of their lines, with the resources that aren't in the
+	// usage file last
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line == 0 || problems[j].Line == 0 {
+			return problems[j].Line == 0 && problems[i].Line != 0
+		}
+
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems, nil
+}
+
+// lintAddresses returns a problem for each resource address in resource_usage that doesn't
+// match any of the resources, either exactly, with a wildcard or as the usage of a module.
+func (l *Linter) lintAddresses(resourceUsage *yamlv3.Node, resources []*schema.Resource) []*LintProblem {
+	keys := mappingKeys(resourceUsage)
+
+	// Wildcard keys that are overridden by a more specific key for all their resources aren't
+	// reported since they'd be used if the specific key was removed
+	matched := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		for _, r := range resources {
+			_, ok := schema.MatchUsageKey([]string{k}, r.Name)
+			if ok || len(schema.ModuleUsageKeys([]string{k}, r.Name)) > 0 {
+				matched[k] = true
+				break
+			}
+		}
+	}
+
+	problems := make([]*LintProblem, 0)
+	for i := 0; i+1 < len(resourceUsage.Content); i += 2 {
+		key := resourceUsage.Content[i]
+		if !matched[key.Value] {
+			problems = append(problems, &LintProblem{
+				Line:    key.Line,
+				Message: fmt.Sprintf("%s doesn't match any resource, it might have been removed or renamed", key.Value),
+			})
+		}
+	}
+
+	return problems
+}
+
+// lintResourceTypes returns a problem for each resource type in resource_type_default_usage
+// that none of the resources are.
+func (l *Linter) lintResourceTypes(typeDefaults *yamlv3.Node, resources []*schema.Resource) []*LintProblem {
+	resourceTypes := make(map[string]bool)
+	for _, r := range resources {
+		resourceTypes[resourceType(r)] = true
+	}
+
+	problems := make([]*LintProblem, 0)
+	for i := 0; i+1 < len(typeDefaults.Content); i += 2 {
+		key := typeDefaults.Content[i]
+		if !resourceTypes[key.Value] {
+			problems = append(problems, &LintProblem{
+				Line:    key.Line,
+				Message: fmt.Sprintf("%s in resource_type_default_usage doesn't match any resource", key.Value),
+			})
+		}
+	}
+
+	return problems
+}
+
+// lintResources returns a problem for each resource that has usage-based cost components
+// without any usage. The problem is on the line of the resource's usage if it has some.
+func (l *Linter) lintResources(resources []*schema.Resource, resourceUsage *yamlv3.Node) []*LintProblem {
+	sorted := make([]*schema.Resource, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var keys []string
+	if resourceUsage != nil {
+		keys = mappingKeys(resourceUsage)
+	}
+
+	problems := make([]*LintProblem, 0)
+	for _, r := range sorted {
+		if r.IsSkipped || r.NoPrice {
+			continue
+		}
+
+		missing := missingUsageCostComponents(r, "")
+		if len(missing) == 0 {
+			continue
+		}
+
+		p := &LintProblem{Message: fmt.Sprintf("%s has no usage for: %s", r.Name, strings.Join(missing, ", "))}
+		if k, ok := schema.MatchUsageKey(keys, r.Name); ok {
+			p.Line = resourceUsage.Content[indexOf(keys, k)*2].Line
+		}
+
+		problems = append(problems, p)
+	}
+
+	return problems
+}
+
+// missingUsageCostComponents returns the names of the cost components of the resource and
+// its sub resources that need usage but don't have it.
+func missingUsageCostComponents(r *schema.Resource, prefix string) []string {
+	names := make([]string, 0)
+	for _, c := range r.CostComponents {
+		if c.UsageProvenance == schema.UsageProvenanceMissing {
+			names = append(names, prefix+c.Name)
+		}
+	}
+
+	for _, s := range r.SubResources {
+		names = append(names, missingUsageCostComponents(s, prefix+s.Name+" / ")...)
+	}
+
+	return names
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
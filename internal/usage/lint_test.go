@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+resource_type_default_usage:
+  aws_s3_bucket:
+    standard:
+      storage_gb: 10
+resource_usage:
+  aws_lambda_function.workers[*]:
+    monthly_requests: 100
+  aws_lambda_function.workers[0]:
+    monthly_requests: 200
+  aws_lambda_function.old:
+    monthly_request: 5
+  aws_lambda_function.partial:
+    monthly_requests: 5
+  module.api.*:
+    monthly_requests: 5
+  module.removed.*:
+    monthly_requests: 5
+`), 0600))
+
+	linter, err := NewLinter(usageFile)
+	require.NoError(t, err)
+	assert.Contains(t, linter.UsageData(), "aws_lambda_function.workers[0]")
+
+	resources := []*schema.Resource{
+		{Name: "aws_lambda_function.workers[0]", ResourceType: "aws_lambda_function"},
+		{Name: "aws_lambda_function.workers[1]", ResourceType: "aws_lambda_function"},
+		{Name: "module.api.module.db.aws_lambda_function.fn", ResourceType: "aws_lambda_function"},
+		{
+			Name:         "aws_lambda_function.partial",
+			ResourceType: "aws_lambda_function",
+			CostComponents: []*schema.CostComponent{
+				{Name: "Requests", UsageProvenance: schema.UsageProvenanceUsageFile},
+				{Name: "Duration", UsageProvenance: schema.UsageProvenanceMissing},
+			},
+		},
+		{
+			Name:         "aws_instance.web",
+			ResourceType: "aws_instance",
+			SubResources: []*schema.Resource{
+				{
+					Name:           "root_block_device",
+					CostComponents: []*schema.CostComponent{{Name: "Storage", UsageProvenance: schema.UsageProvenanceMissing}},
+				},
+			},
+		},
+		{
+			Name:           "aws_lambda_function.skipped",
+			IsSkipped:      true,
+			CostComponents: []*schema.CostComponent{{Name: "Requests", UsageProvenance: schema.UsageProvenanceMissing}},
+		},
+	}
+
+	problems, err := linter.Lint(resources)
+	require.NoError(t, err)
+
+	actual := make([]string, 0, len(problems))
+	for _, p := range problems {
+		actual = append(actual, p.String())
+	}
+
+	assert.Equal(t, []string{
+		"line 3: aws_s3_bucket in resource_type_default_usage doesn't match any resource",
+		"line 11: aws_lambda_function.old doesn't match any resource, it might have been removed or renamed",
+		"line 12: unknown key monthly_request for aws_lambda_function.old (did you mean monthly_requests?)",
+		"line 13: aws_lambda_function.partial has no usage for: Duration",
+		"line 17: module.removed.* doesn't match any resource, it might have been removed or renamed",
+		"aws_instance.web has no usage for: root_block_device / Storage",
+	}, actual)
+}
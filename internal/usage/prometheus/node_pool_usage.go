@@ -0,0 +1,159 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultNodePoolLookback = "30d"
+
+// nodePool is how the nodes of a Terraform node pool resource are found in the metrics of
+// its cluster, by the node label with the node pool's name.
+type nodePool struct {
+	// label is the node label with the name of the node pool, as kube-state-metrics exports
+	// it on kube_node_labels
+	label string
+	// nameAttr is the Terraform attribute with the name of the node pool
+	nameAttr string
+	// usageKey is the usage key of the node count, with dots for nested keys
+	usageKey string
+	// perZone is true if the node count is per zone of the node pool
+	perZone bool
+}
+
+var nodePools = map[string]nodePool{
+	"aws_eks_node_group": {
+		label:    "label_eks_amazonaws_com_nodegroup",
+		nameAttr: "node_group_name",
+		usageKey: "instances",
+	},
+	"google_container_node_pool": {
+		label:    "label_cloud_google_com_gke_nodepool",
+		nameAttr: "name",
+		usageKey: "nodes",
+		perZone:  true,
+	},
+	"azurerm_kubernetes_cluster": {
+		label:    "label_agentpool",
+		nameAttr: "default_node_pool.0.name",
+		usageKey: "default_node_pool.nodes",
+	},
+	"azurerm_kubernetes_cluster_node_pool": {
+		label:    "label_agentpool",
+		nameAttr: "name",
+		usageKey: "nodes",
+	},
+}
+
+// NodePoolUsage fetches the node counts of Kubernetes node pools from the kube-state-metrics
+// of their clusters. The node pools are priced with their average node count over the
+// lookback instead of their declared sizes, which autoscaling node pools are rarely at. If a
+// target utilization is set they're priced with the node count that gets their average CPU
+// requests to it instead.
+type NodePoolUsage struct {
+	prometheus        *QueryUsage
+	lookback          string
+	targetUtilization float64
+}
+
+// NewNodePoolUsage returns a NodePoolUsage for the Prometheus server at the endpoint. The
+// token is sent as a bearer token if it's set.
+func NewNodePoolUsage(endpoint, token, lookback string, targetUtilization float64) (*NodePoolUsage, error) {
+	if lookback == "" {
+		lookback = defaultNodePoolLookback
+	}
+
+	q, err := NewQueryUsage(endpoint, token, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NodePoolUsage{
+		prometheus:        q,
+		lookback:          lookback,
+		targetUtilization: targetUtilization,
+	}, nil
+}
+
+// SupportsResourceType returns true if the resource type is a node pool.
+func (n *NodePoolUsage) SupportsResourceType(resourceType string) bool {
+	_, ok := nodePools[resourceType]
+	return ok
+}
+
+// FetchUsage returns the node count of the node pool. It returns no usage if the node pool
+// has no nodes in the metrics, e.g. because it doesn't exist yet.
+func (n *NodePoolUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	pool := nodePools[d.Type]
+	name := d.Get(pool.nameAttr).String()
+	if name == "" {
+		return usage, nil
+	}
+
+	selector := fmt.Sprintf(`kube_node_labels{%s=%q}`, pool.label, name)
+
+	nodes, ok, err := n.prometheus.query(fmt.Sprintf(`avg_over_time(count(%s)[%s:1h])`, selector, n.lookback))
+	if err != nil {
+		return usage, errors.Wrap(err, "Error querying the node count")
+	}
+	if !ok || nodes == 0 {
+		return usage, nil
+	}
+
+	if pool.perZone {
+		zones, ok, err := n.prometheus.query(fmt.Sprintf(`count(count by (label_topology_kubernetes_io_zone) (%s))`, selector))
+		if err != nil {
+			return usage, errors.Wrap(err, "Error querying the zones")
+		}
+		if ok && zones > 0 {
+			nodes /= zones
+		}
+	}
+
+	count := math.Ceil(nodes)
+
+	if n.targetUtilization > 0 {
+		utilization, ok, err := n.prometheus.query(fmt.Sprintf(
+			`avg_over_time((sum(kube_pod_container_resource_requests{resource="cpu"} * on(node) group_left() %[1]s) / sum(kube_node_status_allocatable{resource="cpu"} * on(node) group_left() %[1]s))[%[2]s:1h])`,
+			selector, n.lookback,
+		))
+		if err != nil {
+			return usage, errors.Wrap(err, "Error querying the CPU utilization")
+		}
+
+		if ok {
+			count = math.Max(1, math.Ceil(nodes*utilization/n.targetUtilization))
+			log.Infof("%s averaged %.1f nodes at %.0f%% CPU requests, pricing the %.0f nodes that would be at %.0f%%",
+				d.Address, nodes, utilization*100, count, n.targetUtilization*100)
+		}
+	}
+
+	setNestedUsage(usage, pool.usageKey, int64(count))
+
+	return usage, nil
+}
+
+// setNestedUsage sets the usage key, creating the mappings of nested keys, e.g.
+// default_node_pool.nodes.
+func setNestedUsage(usage map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+
+	m := usage
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[p] = next
+		}
+		m = next
+	}
+
+	m[parts[len(parts)-1]] = value
+}
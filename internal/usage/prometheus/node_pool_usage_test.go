@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestNodePoolUsage(t *testing.T) {
+	results := map[string]string{
+		`avg_over_time(count(kube_node_labels{label_eks_amazonaws_com_nodegroup="workers"})[7d:1h])`:                         `[{"metric":{},"value":[1622419200,"4.2"]}]`,
+		`avg_over_time(count(kube_node_labels{label_cloud_google_com_gke_nodepool="pool"})[7d:1h])`:                          `[{"metric":{},"value":[1622419200,"9"]}]`,
+		`count(count by (label_topology_kubernetes_io_zone) (kube_node_labels{label_cloud_google_com_gke_nodepool="pool"}))`: `[{"metric":{},"value":[1622419200,"3"]}]`,
+		`avg_over_time(count(kube_node_labels{label_agentpool="system"})[7d:1h])`:                                            `[{"metric":{},"value":[1622419200,"2"]}]`,
+		`avg_over_time(count(kube_node_labels{label_agentpool="new"})[7d:1h])`:                                               `[]`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if strings.Contains(query, "kube_pod_container_resource_requests") {
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1622419200,"0.4"]}]}}`)
+			return
+		}
+
+		result, ok := results[query]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"status":"error","error":"unexpected query %s"}`, query)
+			return
+		}
+
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+	}))
+	defer ts.Close()
+
+	n, err := NewNodePoolUsage(ts.URL, "", "7d", 0)
+	require.NoError(t, err)
+	n.prometheus.httpClient = ts.Client()
+
+	assert.True(t, n.SupportsResourceType("aws_eks_node_group"))
+	assert.False(t, n.SupportsResourceType("aws_instance"))
+
+	tests := []struct {
+		resourceType string
+		attributes   string
+		expected     map[string]interface{}
+	}{
+		{"aws_eks_node_group", `{"node_group_name":"workers"}`, map[string]interface{}{"instances": int64(5)}},
+		{"google_container_node_pool", `{"name":"pool"}`, map[string]interface{}{"nodes": int64(3)}},
+		{"azurerm_kubernetes_cluster", `{"default_node_pool":[{"name":"system"}]}`, map[string]interface{}{"default_node_pool": map[string]interface{}{"nodes": int64(2)}}},
+		{"azurerm_kubernetes_cluster_node_pool", `{"name":"new"}`, map[string]interface{}{}},
+		{"azurerm_kubernetes_cluster_node_pool", `{}`, map[string]interface{}{}},
+	}
+
+	for _, tt := range tests {
+		d := schema.NewResourceData(tt.resourceType, "", tt.resourceType+".pool", nil, gjson.Parse(tt.attributes))
+		usage, err := n.FetchUsage(d)
+		require.NoError(t, err, tt.attributes)
+		assert.Equal(t, tt.expected, usage, tt.attributes)
+	}
+
+	// With a target utilization the node pools are right-sized to it
+	n.targetUtilization = 0.8
+
+	d := schema.NewResourceData("aws_eks_node_group", "aws", "aws_eks_node_group.workers", nil, gjson.Parse(`{"node_group_name":"workers"}`))
+	usage, err := n.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"instances": int64(3)}, usage)
+
+	d = schema.NewResourceData("azurerm_kubernetes_cluster", "azurerm", "azurerm_kubernetes_cluster.cluster", nil, gjson.Parse(`{"default_node_pool":[{"name":"system"}]}`))
+	usage, err = n.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"default_node_pool": map[string]interface{}{"nodes": int64(1)}}, usage)
+}
@@ -0,0 +1,176 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Query is a PromQL query for a usage key of a resource type. The query is a Go template
+// that's run for each resource of the type.
+type Query struct {
+	ResourceType string
+	UsageKey     string
+	Query        string
+}
+
+type compiledQuery struct {
+	usageKey string
+	template *template.Template
+}
+
+// QueryUsage fetches the usage of resources from the results of PromQL queries, so the
+// estimates can be driven by the metrics that are already collected for the services that
+// use the resources.
+type QueryUsage struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+	now        func() time.Time
+
+	// queries are the queries of each resource type
+	queries map[string][]*compiledQuery
+}
+
+// NewQueryUsage returns a QueryUsage for the Prometheus server at the endpoint. The token is
+// sent as a bearer token if it's set.
+func NewQueryUsage(endpoint, token string, queries []Query) (*QueryUsage, error) {
+	q := &QueryUsage{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+		queries:    make(map[string][]*compiledQuery),
+	}
+
+	for _, query := range queries {
+		// The attr function is replaced with the resource's attributes when the query is run
+		t, err := template.New(query.UsageKey).Funcs(template.FuncMap{
+			"attr": func(string) string { return "" },
+		}).Parse(query.Query)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid query for %s of %s", query.UsageKey, query.ResourceType)
+		}
+
+		q.queries[query.ResourceType] = append(q.queries[query.ResourceType], &compiledQuery{
+			usageKey: query.UsageKey,
+			template: t,
+		})
+	}
+
+	return q, nil
+}
+
+// SupportsResourceType returns true if there are queries for the resource type.
+func (q *QueryUsage) SupportsResourceType(resourceType string) bool {
+	return len(q.queries[resourceType]) > 0
+}
+
+// FetchUsage returns the usage keys of the resource whose queries have results. The results
+// of queries that return several series are summed.
+func (q *QueryUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	for _, query := range q.queries[d.Type] {
+		promQL, err := renderQuery(query.template, d)
+		if err != nil {
+			return usage, err
+		}
+
+		value, ok, err := q.query(promQL)
+		if err != nil {
+			return usage, errors.Wrapf(err, "Error querying %s", query.usageKey)
+		}
+		if ok {
+			usage[query.usageKey] = value
+		}
+	}
+
+	return usage, nil
+}
+
+func renderQuery(t *template.Template, d *schema.ResourceData) (string, error) {
+	data := map[string]string{
+		"Address": d.Address,
+		"Type":    d.Type,
+		"Name":    resourceName(d.Address),
+	}
+
+	var buf bytes.Buffer
+	err := template.Must(t.Clone()).Funcs(template.FuncMap{
+		"attr": func(key string) string { return d.Get(key).String() },
+	}).Execute(&buf, data)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error rendering query for %s", d.Address)
+	}
+
+	return buf.String(), nil
+}
+
+// resourceName returns the name of the resource in its address, e.g. api for
+// module.app.aws_api_gateway_rest_api.api[0].
+func resourceName(address string) string {
+	if i := strings.Index(address, "["); i != -1 {
+		address = address[:i]
+	}
+
+	parts := strings.Split(address, ".")
+	return parts[len(parts)-1]
+}
+
+// query runs an instant query and returns the sum of its results. It returns false if the
+// query has no results, e.g. because the resource doesn't exist yet.
+func (q *QueryUsage) query(promQL string) (float64, bool, error) {
+	params := url.Values{}
+	params.Set("query", promQL)
+	params.Set("time", fmt.Sprintf("%d", q.now().Unix()))
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/query?%s", q.endpoint, params.Encode()), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if q.token != "" {
+		req.Header.Set("Authorization", "Bearer "+q.token)
+	}
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error contacting Prometheus")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error reading Prometheus response")
+	}
+
+	result := gjson.ParseBytes(body)
+	if resp.StatusCode != http.StatusOK || result.Get("status").String() != "success" {
+		return 0, false, fmt.Errorf("Prometheus returned status code %d: %s", resp.StatusCode, result.Get("error").String())
+	}
+
+	// Scalar results have a single value and vector results have a value for each series
+	values := []gjson.Result{result.Get("data.result.1")}
+	if result.Get("data.resultType").String() == "vector" {
+		values = result.Get("data.result.#.value.1").Array()
+	}
+
+	total, found := 0.0, false
+	for _, v := range values {
+		if v.Exists() {
+			total += v.Float()
+			found = true
+		}
+	}
+
+	return total, found, nil
+}
@@ -0,0 +1,76 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestQueryUsage(t *testing.T) {
+	results := map[string]string{
+		`sum(increase(api_requests_total{api="orders"}[30d]))`: `{"resultType":"vector","result":[{"metric":{"code":"200"},"value":[1622419200,"900000"]},{"metric":{"code":"500"},"value":[1622419200,"100000"]}]}`,
+		`avg(api_response_bytes{api="orders"}) / 1024`:         `{"resultType":"scalar","result":[1622419200,"12.5"]}`,
+		`sum(increase(api_requests_total{api="new"}[30d]))`:    `{"resultType":"vector","result":[]}`,
+		`avg(api_response_bytes{api="new"}) / 1024`:            `{"resultType":"vector","result":[]}`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "1622419200", r.URL.Query().Get("time"))
+
+		result, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","error":"unexpected query"}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, result)
+	}))
+	defer ts.Close()
+
+	q, err := NewQueryUsage(ts.URL+"/", "token", []Query{
+		{ResourceType: "aws_api_gateway_rest_api", UsageKey: "monthly_requests", Query: `sum(increase(api_requests_total{api="{{ attr "name" }}"}[30d]))`},
+		{ResourceType: "aws_api_gateway_rest_api", UsageKey: "response_size_kb", Query: `avg(api_response_bytes{api="{{ attr "name" }}"}) / 1024`},
+	})
+	require.NoError(t, err)
+	q.httpClient = ts.Client()
+	q.now = func() time.Time {
+		return time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	assert.True(t, q.SupportsResourceType("aws_api_gateway_rest_api"))
+	assert.False(t, q.SupportsResourceType("aws_lambda_function"))
+
+	d := schema.NewResourceData("aws_api_gateway_rest_api", "aws", "aws_api_gateway_rest_api.orders", nil, gjson.Parse(`{"name":"orders"}`))
+	usage, err := q.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"monthly_requests": float64(1000000),
+		"response_size_kb": 12.5,
+	}, usage)
+
+	d = schema.NewResourceData("aws_api_gateway_rest_api", "aws", "aws_api_gateway_rest_api.new", nil, gjson.Parse(`{"name":"new"}`))
+	usage, err = q.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	d = schema.NewResourceData("aws_api_gateway_rest_api", "aws", "aws_api_gateway_rest_api.other", nil, gjson.Parse(`{"name":"other"}`))
+	_, err = q.FetchUsage(d)
+	assert.EqualError(t, err, "Error querying monthly_requests: Prometheus returned status code 400: unexpected query")
+}
+
+func TestNewQueryUsageInvalidTemplate(t *testing.T) {
+	_, err := NewQueryUsage("http://localhost:9090", "", []Query{
+		{ResourceType: "aws_lambda_function", UsageKey: "monthly_requests", Query: `sum(requests{fn="{{ .Name }"})`},
+	})
+	assert.Error(t, err)
+}
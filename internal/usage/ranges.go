@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// usageRangeKeys are the keys of a usage value that's a range instead of a number, e.g.
+// monthly_requests: {min: 1M, max: 5M} or monthly_requests: {p50: 2M, p95: 4M}.
+var usageRangeKeys = []string{"min", "expected", "max", "p50", "p95"}
+
+// parseUsageRange returns the expected value and the range of a usage key whose value is a
+// range. The low value is the min, or the p50 if there's no min, and the high value is the
+// max, or the p95 if there's no max. The expected value is the expected, the p50, or the
+// middle of the range, in that order. The values can have units like any other usage value.
+func parseUsageRange(key string, values map[string]string) (float64, schema.UsageRange, error) {
+	parsed := make(map[string]float64, len(values))
+
+	for k, v := range values {
+		if !containsString(usageRangeKeys, k) {
+			return 0, schema.UsageRange{}, fmt.Errorf("has unknown key %s in its range, expected %s", k, strings.Join(usageRangeKeys, ", "))
+		}
+
+		n, err := normalizeUsageValue(key, v)
+		if err != nil {
+			return 0, schema.UsageRange{}, fmt.Errorf("%s %s", k, err)
+		}
+
+		parsed[k] = n
+	}
+
+	low, hasLow := firstValue(parsed, "min", "p50")
+	high, hasHigh := firstValue(parsed, "max", "p95")
+	if !hasLow || !hasHigh {
+		return 0, schema.UsageRange{}, fmt.Errorf("should be a number, or a range with a min and max or a p50 and p95")
+	}
+
+	expected, ok := firstValue(parsed, "expected", "p50")
+	if !ok {
+		expected = (low + high) / 2
+	}
+
+	if low > expected || expected > high {
+		return 0, schema.UsageRange{}, fmt.Errorf("should have a range with low ≤ expected ≤ high values, got %v, %v and %v", low, expected, high)
+	}
+
+	return expected, schema.UsageRange{Low: low, High: high}, nil
+}
+
+func firstValue(values map[string]float64, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
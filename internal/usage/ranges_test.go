@@ -0,0 +1,63 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUsageRange(t *testing.T) {
+	tests := []struct {
+		values   map[string]string
+		expected float64
+		r        schema.UsageRange
+		err      string
+	}{
+		{values: map[string]string{"min": "1M", "max": "5M"}, expected: 3000000, r: schema.UsageRange{Low: 1000000, High: 5000000}},
+		{values: map[string]string{"min": "1M", "expected": "2M", "max": "5M"}, expected: 2000000, r: schema.UsageRange{Low: 1000000, High: 5000000}},
+		{values: map[string]string{"p50": "2M", "p95": "4M"}, expected: 2000000, r: schema.UsageRange{Low: 2000000, High: 4000000}},
+		{values: map[string]string{"min": "1M"}, err: "should be a number, or a range with a min and max or a p50 and p95"},
+		{values: map[string]string{"min": "1M", "max": "5M", "avg": "2M"}, err: "has unknown key avg in its range, expected min, expected, max, p50, p95"},
+		{values: map[string]string{"min": "5M", "max": "1M"}, err: "should have a range with low ≤ expected ≤ high values, got 5e+06, 3e+06 and 1e+06"},
+		{values: map[string]string{"min": "lots", "max": "1M"}, err: `min should be a number, got "lots"`},
+	}
+
+	for _, tt := range tests {
+		expected, r, err := parseUsageRange("monthly_requests", tt.values)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, expected)
+		assert.Equal(t, tt.r, r)
+	}
+}
+
+func TestParseUsageRanges(t *testing.T) {
+	usage, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: {min: 100, max: 300}
+resource_usage:
+  aws_lambda_function.fn:
+    monthly_requests: {p50: 2M, p95: 4M}
+`))
+	require.NoError(t, err)
+
+	fn := schema.FindUsageData(usage, "aws_lambda_function.fn")
+	require.True(t, fn.HasRanges())
+	assert.Equal(t, int64(2000000), fn.Get("monthly_requests").Int())
+	assert.Equal(t, int64(200), fn.Get("request_duration_ms").Int())
+
+	low := fn.LowUsage()
+	assert.Equal(t, int64(2000000), low.Get("monthly_requests").Int())
+	assert.Equal(t, int64(100), low.Get("request_duration_ms").Int())
+
+	high := fn.HighUsage()
+	assert.Equal(t, int64(4000000), high.Get("monthly_requests").Int())
+	assert.Equal(t, int64(300), high.Get("request_duration_ms").Int())
+}
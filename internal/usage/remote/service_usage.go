@@ -0,0 +1,126 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// identifierAttributes are the attributes of a resource that are sent to the service so it
+// can find the resource in its telemetry. The other attributes aren't sent since they can
+// have secrets, e.g. database passwords.
+var identifierAttributes = []string{"id", "arn", "name", "self_link"}
+
+// usageRequest is the body of the request for the usage of a resource.
+type usageRequest struct {
+	Address     string            `json:"address"`
+	Type        string            `json:"type"`
+	Provider    string            `json:"provider"`
+	Identifiers map[string]string `json:"identifiers,omitempty"`
+}
+
+// ServiceUsage fetches the usage of resources from an HTTP service. Each resource is sent in
+// a POST request and the service responds with its usage keys, e.g.
+// {"usage": {"monthly_requests": 1000000}}. A 404 or an empty usage means the service has
+// no usage for the resource.
+type ServiceUsage struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+
+	// resourceTypes are the resource types the service is asked about, or nil for all.
+	resourceTypes map[string]bool
+}
+
+// NewServiceUsage returns a ServiceUsage for the service at the endpoint. The token is sent as
+// a bearer token if it's set.
+func NewServiceUsage(endpoint, token string, resourceTypes []string) *ServiceUsage {
+	s := &ServiceUsage{
+		endpoint:   endpoint,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if len(resourceTypes) > 0 {
+		s.resourceTypes = make(map[string]bool, len(resourceTypes))
+		for _, t := range resourceTypes {
+			s.resourceTypes[t] = true
+		}
+	}
+
+	return s
+}
+
+// SupportsResourceType returns true if the service is asked about resources of the type.
+func (s *ServiceUsage) SupportsResourceType(resourceType string) bool {
+	return s.resourceTypes == nil || s.resourceTypes[resourceType]
+}
+
+// FetchUsage returns the usage of the resource from the service.
+func (s *ServiceUsage) FetchUsage(d *schema.ResourceData) (map[string]interface{}, error) {
+	usage := map[string]interface{}{}
+
+	reqBody := usageRequest{
+		Address:     d.Address,
+		Type:        d.Type,
+		Provider:    d.ProviderName,
+		Identifiers: make(map[string]string),
+	}
+	for _, attr := range identifierAttributes {
+		if v := d.Get(attr); v.Type == gjson.String && v.String() != "" {
+			reqBody.Identifiers[attr] = v.String()
+		}
+	}
+
+	b, err := json.Marshal(reqBody)
+	if err != nil {
+		return usage, errors.Wrap(err, "Error encoding usage request")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return usage, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return usage, errors.Wrap(err, "Error contacting usage service")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return usage, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return usage, errors.Wrap(err, "Error reading usage service response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return usage, fmt.Errorf("Usage service returned status code %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	result := gjson.ParseBytes(body).Get("usage")
+	if !result.IsObject() {
+		return usage, nil
+	}
+
+	result.ForEach(func(key, value gjson.Result) bool {
+		usage[key.String()] = value.Value()
+		return true
+	})
+
+	return usage, nil
+}
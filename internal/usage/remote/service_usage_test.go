@@ -0,0 +1,70 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestServiceUsage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var req usageRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch req.Address {
+		case "aws_lambda_function.api":
+			assert.Equal(t, "aws_lambda_function", req.Type)
+			assert.Equal(t, "aws", req.Provider)
+			assert.Equal(t, map[string]string{"name": "api"}, req.Identifiers)
+			fmt.Fprint(w, `{"usage":{"monthly_requests":1000000,"request_duration_ms":250}}`)
+		case "aws_s3_bucket.assets":
+			fmt.Fprint(w, `{"usage":{"standard":{"storage_gb":500}}}`)
+		case "aws_lambda_function.error":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "database unavailable\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := NewServiceUsage(ts.URL, "token", nil)
+	s.httpClient = ts.Client()
+
+	assert.True(t, s.SupportsResourceType("aws_lambda_function"))
+
+	d := schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.api", nil, gjson.Parse(`{"name":"api","environment":[{"variables":{"PASSWORD":"secret"}}]}`))
+	usage, err := s.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"monthly_requests": float64(1000000), "request_duration_ms": float64(250)}, usage)
+
+	d = schema.NewResourceData("aws_s3_bucket", "aws", "aws_s3_bucket.assets", nil, gjson.Result{})
+	usage, err = s.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"standard": map[string]interface{}{"storage_gb": float64(500)}}, usage)
+
+	d = schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.new", nil, gjson.Result{})
+	usage, err = s.FetchUsage(d)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	d = schema.NewResourceData("aws_lambda_function", "aws", "aws_lambda_function.error", nil, gjson.Result{})
+	_, err = s.FetchUsage(d)
+	assert.EqualError(t, err, "Usage service returned status code 500: database unavailable")
+}
+
+func TestServiceUsageResourceTypes(t *testing.T) {
+	s := NewServiceUsage("http://localhost:8080", "", []string{"aws_lambda_function"})
+	assert.True(t, s.SupportsResourceType("aws_lambda_function"))
+	assert.False(t, s.SupportsResourceType("aws_s3_bucket"))
+}
@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"io/ioutil"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// ReplayedUsage is the usage that the resources of each project were priced with in a
+// previous run, from its JSON output.
+type ReplayedUsage struct {
+	projects map[string]map[string]*schema.UsageData
+	// names are the names of the projects in the order of the JSON output.
+	names []string
+}
+
+// LoadReplayedUsage loads the usage of the resources in the JSON output of a previous run.
+// It returns an empty ReplayedUsage if the path is empty.
+func LoadReplayedUsage(path string) (*ReplayedUsage, error) {
+	r := &ReplayedUsage{projects: make(map[string]map[string]*schema.UsageData)}
+	if path == "" {
+		return r, nil
+	}
+
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r, errors.Wrap(err, "Error reading usage JSON")
+	}
+
+	if !gjson.ValidBytes(out) {
+		return r, errors.Errorf("Error parsing usage JSON: %s is not valid JSON", path)
+	}
+
+	parsed := gjson.ParseBytes(out)
+	if !parsed.Get("projects").IsArray() {
+		return r, errors.Errorf("Error parsing usage JSON: %s is not the JSON output of infracost", path)
+	}
+
+	for _, p := range parsed.Get("projects").Array() {
+		name := p.Get("name").String()
+		if _, ok := r.projects[name]; !ok {
+			r.names = append(r.names, name)
+			r.projects[name] = make(map[string]*schema.UsageData)
+		}
+
+		for _, res := range p.Get("breakdown.resources").Array() {
+			if !res.Get("usage").IsObject() {
+				continue
+			}
+
+			address := res.Get("name").String()
+			r.projects[name][address] = schema.NewUsageData(address, schema.ParseAttributes(res.Get("usage").Value()))
+		}
+	}
+
+	return r, nil
+}
+
+// ProjectUsage returns the replayed usage of the project with the name. If the previous run
+// only had one project its usage is used for any project, since the name can change between
+// runs, e.g. when the path of a plan JSON file is different.
+func (r *ReplayedUsage) ProjectUsage(name string) map[string]*schema.UsageData {
+	if u, ok := r.projects[name]; ok {
+		return u
+	}
+
+	if len(r.names) == 1 {
+		return r.projects[r.names[0]]
+	}
+
+	return map[string]*schema.UsageData{}
+}
@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReplayedUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+  "version": "0.2",
+  "projects": [
+    {
+      "name": "infracost/infracost/examples/api",
+      "breakdown": {
+        "resources": [
+          {"name": "aws_lambda_function.api", "usage": {"monthly_requests": 1000000, "request_duration_ms": 250}},
+          {"name": "aws_s3_bucket.assets", "usage": {"standard.storage_gb": 500}},
+          {"name": "aws_instance.web"}
+        ]
+      }
+    },
+    {
+      "name": "infracost/infracost/examples/web",
+      "breakdown": {
+        "resources": [
+          {"name": "aws_lambda_function.api", "usage": {"monthly_requests": 50}}
+        ]
+      }
+    }
+  ]
+}`), 0600))
+
+	replayed, err := LoadReplayedUsage(path)
+	require.NoError(t, err)
+
+	api := replayed.ProjectUsage("infracost/infracost/examples/api")
+	assert.Len(t, api, 2)
+	assert.Equal(t, int64(1000000), schema.FindUsageData(api, "aws_lambda_function.api").Get("monthly_requests").Int())
+	assert.Equal(t, int64(500), schema.FindUsageData(api, "aws_s3_bucket.assets").Get("standard.storage_gb").Int())
+
+	web := replayed.ProjectUsage("infracost/infracost/examples/web")
+	assert.Equal(t, int64(50), schema.FindUsageData(web, "aws_lambda_function.api").Get("monthly_requests").Int())
+
+	assert.Empty(t, replayed.ProjectUsage("infracost/infracost/examples/other"))
+}
+
+func TestLoadReplayedUsageSingleProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"projects": [{"name": "plan.json", "breakdown": {"resources": [
+  {"name": "aws_lambda_function.api", "usage": {"monthly_requests": 1000}}
+]}}]}`), 0600))
+
+	replayed, err := LoadReplayedUsage(path)
+	require.NoError(t, err)
+
+	// The usage of the only project is used whatever the project is called now
+	u := MergeUsageData(replayed.ProjectUsage("other-plan.json"), map[string]*schema.UsageData{})
+	assert.Equal(t, int64(1000), schema.FindUsageData(u, "aws_lambda_function.api").Get("monthly_requests").Int())
+}
+
+func TestLoadReplayedUsageInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"resource_usage": {}}`), 0600))
+
+	_, err := LoadReplayedUsage(path)
+	assert.EqualError(t, err, "Error parsing usage JSON: "+path+" is not the JSON output of infracost")
+
+	replayed, err := LoadReplayedUsage("")
+	require.NoError(t, err)
+	assert.Empty(t, replayed.ProjectUsage("plan.json"))
+}
@@ -0,0 +1,461 @@
+package usage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// usageKey is a usage key of a resource type. Nested keys are joined with dots, e.g.
+// monthly_egress_data_transfer_gb.worldwide.
+type usageKey struct {
+	key          string
+	valueType    schema.UsageVariableType
+	defaultValue interface{}
+	// description is the comment of the key in the reference usage file, which usually
+	// says what the units are, e.g. "# Total size of bucket in GB."
+	description string
+}
+
+// SyncUsageData adds the usage keys of the project's resources that are missing from the
+// usage file. Existing values and comments, and resources that aren't in the project, are
+// left as they are so the file can be synced again as the project changes. New keys are
+// added with their default value and commented with their description from the reference
+// usage file, their unit, the cost components they affect and the value that's assumed if
+// they're unset.
+func SyncUsageData(project *schema.Project, usageFilePath string) error {
+	return SyncUsageDataWithBase(project, usageFilePath, nil)
+}
+
+// SyncUsageDataWithBase syncs a usage file that's merged over a base usage file. Keys that the
+// base usage file has for a resource aren't added, since they'd override the shared values.
+func SyncUsageDataWithBase(project *schema.Project, usageFilePath string, baseUsage map[string]*schema.UsageData) error {
+	if usageFilePath == "" {
+		return nil
+	}
+
+	referenceKeys, err := loadReferenceUsageKeys()
+	if err != nil {
+		return err
+	}
+
+	doc, err := loadUsageFileNode(usageFilePath)
+	if err != nil {
+		return err
+	}
+
+	anchors := findMappingValue(doc.Content[0], "usage_anchors")
+	added := syncResourcesUsage(resourceUsageNode(doc), anchors, project.Resources, referenceKeys, typeDefaultKeys(doc, anchors), baseUsage)
+	log.Debugf("Added %d usage keys to %s", added, usageFilePath)
+
+	out, err := encodeUsageFileNode(doc, isJSONUsageFile(usageFilePath))
+	if err != nil {
+		return errors.Wrap(err, "Error writing usage file")
+	}
+
+	return ioutil.WriteFile(usageFilePath, out, 0600)
+}
+
+// encodeUsageFileNode returns the usage file as YAML, or as JSON for JSON usage files. JSON
+// has no comments so the descriptions of the keys aren't written.
+func encodeUsageFileNode(doc *yamlv3.Node, asJSON bool) ([]byte, error) {
+	if asJSON {
+		var v interface{}
+		if err := doc.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(out, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// syncResourcesUsage adds the missing usage keys of the resources to the resource_usage
+// mapping and returns how many were added. New resources are added in name order after the
+// existing ones. Keys that are in the resource_type_default_usage of the resource's type or
+// the usage of a module the resource is in aren't added, since a value for the resource
+// would override them. Neither are the keys that the base usage or the usage anchor of the
+// resource have.
+func syncResourcesUsage(resourceUsage *yamlv3.Node, anchors *yamlv3.Node, resources []*schema.Resource, referenceKeys map[string][]*usageKey, typeDefaults map[string]map[string]bool, baseUsage map[string]*schema.UsageData) int {
+	sorted := make([]*schema.Resource, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	added := 0
+	for _, r := range sorted {
+		inherited := make(map[string]bool)
+		for k := range typeDefaults[resourceType(r)] {
+			inherited[k] = true
+		}
+		for _, moduleKey := range schema.ModuleUsageKeys(mappingKeys(resourceUsage), r.Name) {
+			moduleUsage := findMappingValue(resourceUsage, moduleKey)
+			for _, k := range append(flattenUsageKeys("", moduleUsage), anchorUsageKeys(anchors, moduleUsage)...) {
+				inherited[k.key] = true
+			}
+		}
+		if base := schema.FindUsageData(baseUsage, r.Name); base != nil {
+			for k := range base.Attributes {
+				inherited[k] = true
+			}
+		}
+
+		// Keys are added to the wildcard usage that applies to the resource if it has one,
+		// since usage for the resource itself would override the wildcard usage
+		name := r.Name
+		if k, ok := schema.MatchUsageKey(mappingKeys(resourceUsage), name); ok {
+			name = k
+		}
+
+		for _, k := range anchorUsageKeys(anchors, findMappingValue(resourceUsage, name)) {
+			inherited[k.key] = true
+		}
+
+		keys := make([]*usageKey, 0)
+		for _, k := range resourceUsageKeys(r, referenceKeys) {
+			if !inherited[k.key] {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		resourceNode := mappingValue(resourceUsage, name)
+		for _, k := range keys {
+			if addUsageKey(resourceNode, k, usageKeyComment(k, r.UsageCostComponents[k.key], r.UsageSchema != nil)) {
+				added++
+			}
+		}
+	}
+
+	return added
+}
+
+// resourceUsageKeys returns the usage keys of the resource. Resources that don't define a
+// usage schema use the keys of their resource type in the reference usage file.
+func resourceUsageKeys(r *schema.Resource, referenceKeys map[string][]*usageKey) []*usageKey {
+	if r.IsSkipped || r.NoPrice {
+		return nil
+	}
+
+	refKeys := referenceKeys[resourceType(r)]
+	if r.UsageSchema == nil {
+		return refKeys
+	}
+
+	descriptions := make(map[string]string, len(refKeys))
+	for _, k := range refKeys {
+		descriptions[k.key] = k.description
+	}
+
+	keys := make([]*usageKey, 0, len(r.UsageSchema))
+	for _, item := range r.UsageSchema {
+		keys = append(keys, &usageKey{
+			key:          item.Key,
+			valueType:    item.ValueType,
+			defaultValue: item.DefaultValue,
+			description:  descriptions[item.Key],
+		})
+	}
+
+	return keys
+}
+
+// addUsageKey adds the key to the resource's usage with the comment if it doesn't have a
+// value already.
+func addUsageKey(resourceNode *yamlv3.Node, k *usageKey, comment string) bool {
+	parts := strings.Split(k.key, ".")
+
+	node := resourceNode
+	for _, p := range parts[:len(parts)-1] {
+		node = mappingValue(node, p)
+	}
+
+	leaf := parts[len(parts)-1]
+	if findMappingValue(node, leaf) != nil {
+		return false
+	}
+
+	// Numbers are left untagged so they're written without a tag, e.g. !!float 0
+	value := &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: fmt.Sprint(k.defaultValue), LineComment: comment}
+	if k.valueType == schema.String {
+		value.Tag = "!!str"
+	}
+	if k.defaultValue == nil {
+		value.Tag, value.Value = "!!null", "null"
+	}
+
+	node.Content = append(node.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: leaf}, value)
+
+	return true
+}
+
+// usageKeyComment returns the comment of a new usage key, e.g. "# Monthly requests to the
+// Lambda function. Unit: count per month. Affects: Requests. Assumed 0 if unset." The
+// default is only known if the resource defines its usage schema, otherwise the value is
+// just a placeholder or an example option from the reference usage file.
+func usageKeyComment(k *usageKey, costComponents []string, hasUsageSchema bool) string {
+	parts := make([]string, 0, 4)
+
+	if d := strings.TrimSpace(strings.TrimPrefix(k.description, "#")); d != "" {
+		if !strings.HasSuffix(d, ".") {
+			d += "."
+		}
+		parts = append(parts, d)
+	}
+
+	if k.valueType != schema.String {
+		if u := keyUnitName(k.key); u != "" {
+			parts = append(parts, fmt.Sprintf("Unit: %s.", u))
+		}
+	}
+
+	if len(costComponents) > 0 {
+		parts = append(parts, fmt.Sprintf("Affects: %s.", strings.Join(costComponents, ", ")))
+	}
+
+	if hasUsageSchema && k.defaultValue != nil {
+		parts = append(parts, fmt.Sprintf("Assumed %v if unset.", k.defaultValue))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "# " + strings.Join(parts, " ")
+}
+
+// findMappingValue returns the value of the key in a mapping node, or nil if the key isn't in it.
+func findMappingValue(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func mappingKeys(mapping *yamlv3.Node) []string {
+	keys := make([]string, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, mapping.Content[i].Value)
+	}
+
+	return keys
+}
+
+// mappingValue returns the mapping value of the key in a mapping node, adding it if the key
+// doesn't exist. Empty values, e.g. a resource with no usage keys, are replaced with a mapping.
+func mappingValue(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	value := findMappingValue(mapping, key)
+	if value == nil {
+		value = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		mapping.Content = append(mapping.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: key}, value)
+	}
+
+	if value.Kind != yamlv3.MappingNode {
+		value.Kind = yamlv3.MappingNode
+		value.Tag = "!!map"
+		value.Value = ""
+	}
+
+	// Write empty mappings that new keys are added to, e.g. resource_usage: {}, as blocks
+	value.Style &^= yamlv3.FlowStyle
+
+	return value
+}
+
+// loadUsageFileNode loads the usage file as a YAML document so its comments and key order
+// are kept when it's written again.
+func loadUsageFileNode(usageFilePath string) (*yamlv3.Node, error) {
+	out, err := ioutil.ReadFile(usageFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "Error reading usage file")
+	}
+
+	doc := &yamlv3.Node{}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := yamlv3.Unmarshal(jsonAsYAML(out), doc); err != nil {
+			return nil, errors.Wrapf(err, "Error parsing usage file")
+		}
+	}
+
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		doc = &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	if findMappingValue(root, "version") == nil {
+		root.Content = append([]*yamlv3.Node{
+			{Kind: yamlv3.ScalarNode, Value: "version"},
+			{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: maxUsageFileVersion},
+		}, root.Content...)
+	}
+
+	return doc, nil
+}
+
+func resourceType(r *schema.Resource) string {
+	if r.ResourceType != "" {
+		return r.ResourceType
+	}
+
+	// This handles module names appearing in the resource name too
+	parts := strings.Split(r.Name, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-2]
+}
+
+// typeDefaultKeys returns the usage keys in the resource_type_default_usage of the usage
+// file for each resource type, including the keys of the usage anchors they use.
+func typeDefaultKeys(doc *yamlv3.Node, anchors *yamlv3.Node) map[string]map[string]bool {
+	keys := make(map[string]map[string]bool)
+
+	typeDefaults := findMappingValue(doc.Content[0], "resource_type_default_usage")
+	if typeDefaults == nil {
+		return keys
+	}
+
+	for i := 0; i+1 < len(typeDefaults.Content); i += 2 {
+		resourceType := typeDefaults.Content[i].Value
+		keys[resourceType] = make(map[string]bool)
+
+		usage := typeDefaults.Content[i+1]
+		for _, k := range append(flattenUsageKeys("", usage), anchorUsageKeys(anchors, usage)...) {
+			keys[resourceType][k.key] = true
+		}
+	}
+
+	return keys
+}
+
+// anchorUsageKeys returns the usage keys of the usage anchor that the usage uses, or none if
+// it doesn't use one.
+func anchorUsageKeys(anchors *yamlv3.Node, usage *yamlv3.Node) []*usageKey {
+	if anchors == nil || usage == nil || usage.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	name := findMappingValue(usage, usageAnchorKey)
+	if name == nil {
+		return nil
+	}
+
+	anchor := findMappingValue(anchors, name.Value)
+	if anchor == nil {
+		return nil
+	}
+
+	return flattenUsageKeys("", anchor)
+}
+
+func resourceUsageNode(doc *yamlv3.Node) *yamlv3.Node {
+	return mappingValue(doc.Content[0], "resource_usage")
+}
+
+// loadReferenceUsageKeys returns the usage keys of each resource type in the reference
+// usage file, in the order they're in the file.
+func loadReferenceUsageKeys() (map[string][]*usageKey, error) {
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(*infracost.GetReferenceUsageFileContents(), doc); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing reference usage file")
+	}
+
+	referenceKeys := make(map[string][]*usageKey)
+	if len(doc.Content) == 0 {
+		return referenceKeys, nil
+	}
+
+	resourceUsage := findMappingValue(doc.Content[0], "resource_usage")
+	if resourceUsage == nil {
+		return referenceKeys, nil
+	}
+
+	for i := 0; i+1 < len(resourceUsage.Content); i += 2 {
+		resourceType := strings.Split(resourceUsage.Content[i].Value, ".")[0]
+
+		seen := make(map[string]bool)
+		for _, k := range referenceKeys[resourceType] {
+			seen[k.key] = true
+		}
+
+		for _, k := range flattenUsageKeys("", resourceUsage.Content[i+1]) {
+			if !seen[k.key] {
+				referenceKeys[resourceType] = append(referenceKeys[resourceType], k)
+			}
+		}
+	}
+
+	return referenceKeys, nil
+}
+
+// flattenUsageKeys returns the keys of the usage of a resource with nested keys joined with
+// dots. Example string values are used as the defaults, since they're the options, e.g.
+// on_demand, and numbers default to 0.
+func flattenUsageKeys(prefix string, node *yamlv3.Node) []*usageKey {
+	keys := make([]*usageKey, 0)
+	if node.Kind != yamlv3.MappingNode {
+		return keys
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := prefix + node.Content[i].Value
+		value := node.Content[i+1]
+
+		if value.Kind == yamlv3.MappingNode {
+			keys = append(keys, flattenUsageKeys(key+".", value)...)
+			continue
+		}
+
+		k := &usageKey{
+			key:          key,
+			valueType:    schema.Int64,
+			defaultValue: 0,
+			description:  value.LineComment,
+		}
+		if k.description == "" {
+			k.description = node.Content[i].LineComment
+		}
+
+		switch value.Tag {
+		case "!!str":
+			k.valueType = schema.String
+			k.defaultValue = value.Value
+		case "!!float":
+			k.valueType = schema.Float64
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,205 @@
+package usage
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncUsageData(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+resource_usage:
+  # Our production bucket
+  google_storage_bucket.assets:
+    storage_gb: 2000 # Measured in June
+  aws_lambda_function.workers[*]:
+    monthly_requests: 5000
+  aws_instance.removed:
+    operating_system: linux
+`), 0600))
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "google_storage_bucket.assets", ResourceType: "google_storage_bucket"},
+		{Name: "aws_lambda_function.workers[0]", ResourceType: "aws_lambda_function"},
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function"},
+		{Name: "aws_vpc.main", ResourceType: "aws_vpc", NoPrice: true},
+	}}
+
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 0.1
+resource_usage:
+  # Our production bucket
+  google_storage_bucket.assets:
+    storage_gb: 2000 # Measured in June
+    monthly_class_a_operations: 0 # Monthly number of class A operations (object adds, bucket/object list). Unit: count per month.
+    monthly_class_b_operations: 0 # Monthly number of class B operations (object gets, retrieve bucket/object metadata). Unit: count per month.
+    monthly_data_retrieval_gb: 0 # Monthly amount of data retrieved in GB. Unit: GiB per month.
+    monthly_egress_data_transfer_gb:
+      same_continent: 0 # Same continent. Unit: GiB per month.
+      worldwide: 0 # Worldwide excluding Asia, Australia. Unit: GiB per month.
+      asia: 0 # Asia excluding China, but including Hong Kong. Unit: GiB per month.
+      china: 0 # China excluding Hong Kong. Unit: GiB per month.
+      australia: 0 # Australia. Unit: GiB per month.
+  aws_lambda_function.workers[*]:
+    monthly_requests: 5000
+    request_duration_ms: 0 # Average duration of each request in milliseconds. Unit: milliseconds.
+  aws_instance.removed:
+    operating_system: linux
+  aws_lambda_function.api:
+    monthly_requests: 0 # Monthly requests to the Lambda function. Unit: count per month.
+    request_duration_ms: 0 # Average duration of each request in milliseconds. Unit: milliseconds.
+`, string(out))
+
+	// Syncing again doesn't change the file
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	again, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(out), string(again))
+}
+
+func TestSyncUsageDataNewFile(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "aws_nat_gateway.nat", ResourceType: "aws_nat_gateway", UsageSchema: []*schema.UsageSchemaItem{
+			{Key: "monthly_data_processed_gb", ValueType: schema.Float64, DefaultValue: 0},
+		}, UsageCostComponents: map[string][]string{
+			"monthly_data_processed_gb": {"Data processed"},
+		}},
+	}}
+
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: "0.1"
+resource_usage:
+  aws_nat_gateway.nat:
+    monthly_data_processed_gb: 0 # Monthly data processed by the NAT Gateway in GB. Unit: GiB per month. Affects: Data processed. Assumed 0 if unset.
+`, string(out))
+}
+
+func TestSyncUsageDataResourceTypeDefaults(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 500
+resource_usage: {}
+`), 0600))
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function"},
+	}}
+
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 500
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests: 0 # Monthly requests to the Lambda function. Unit: count per month.
+`, string(out))
+}
+
+func TestSyncUsageDataModuleUsage(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+resource_usage:
+  module.api.*:
+    request_duration_ms: 500
+`), 0600))
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "module.api.aws_lambda_function.fn", ResourceType: "aws_lambda_function"},
+	}}
+
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 0.1
+resource_usage:
+  module.api.*:
+    request_duration_ms: 500
+  module.api.aws_lambda_function.fn:
+    monthly_requests: 0 # Monthly requests to the Lambda function. Unit: count per month.
+`, string(out))
+}
+
+func TestSyncUsageDataUsageAnchors(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+usage_anchors:
+  api_traffic:
+    monthly_requests: 1000
+resource_usage:
+  aws_lambda_function.api:
+    usage_anchor: api_traffic
+`), 0600))
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function"},
+	}}
+
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 0.1
+usage_anchors:
+  api_traffic:
+    monthly_requests: 1000
+resource_usage:
+  aws_lambda_function.api:
+    usage_anchor: api_traffic
+    request_duration_ms: 0 # Average duration of each request in milliseconds. Unit: milliseconds.
+`, string(out))
+}
+
+func TestSyncUsageDataWithBase(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.yml")
+	require.NoError(t, ioutil.WriteFile(usageFile, []byte(`version: 0.1
+resource_usage: {}
+`), 0600))
+
+	baseUsage, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 500
+`))
+	require.NoError(t, err)
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function"},
+	}}
+
+	require.NoError(t, SyncUsageDataWithBase(project, usageFile, baseUsage))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 0.1
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests: 0 # Monthly requests to the Lambda function. Unit: count per month.
+`, string(out))
+}
@@ -0,0 +1,353 @@
+package usage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// The dimensions of the units of usage keys and values
+const (
+	countDimension    = "count"
+	dataDimension     = "data"
+	durationDimension = "duration"
+)
+
+// hoursPerMonth is the number of hours in a month that the monthly costs are for.
+const hoursPerMonth = 730
+
+type unit struct {
+	dimension string
+	// factor converts the unit to the base unit of its dimension, which is bytes for data
+	// and seconds for durations.
+	factor float64
+}
+
+// The units of usage keys are the suffix of the key, e.g. GB for storage_gb. Data keys
+// are in GiB, TiB etc. since that's what the cloud providers bill in, even when their
+// pricing pages say GB.
+var keyUnits = map[string]*unit{
+	"kb":      {dataDimension, 1 << 10},
+	"mb":      {dataDimension, 1 << 20},
+	"gb":      {dataDimension, 1 << 30},
+	"tb":      {dataDimension, 1 << 40},
+	"ms":      {durationDimension, 0.001},
+	"sec":     {durationDimension, 1},
+	"secs":    {durationDimension, 1},
+	"seconds": {durationDimension, 1},
+	"mins":    {durationDimension, 60},
+	"minutes": {durationDimension, 60},
+	"hr":      {durationDimension, 3600},
+	"hrs":     {durationDimension, 3600},
+	"hours":   {durationDimension, 3600},
+}
+
+// valueUnits are the units that can be used in usage values. Decimal and binary data units
+// are different, e.g. 1 GB is 10^9 bytes and 1 GiB is 2^30 bytes. They're matched without
+// case, except for the multipliers.
+var valueUnits = map[string]*unit{
+	"b":       {dataDimension, 1},
+	"byte":    {dataDimension, 1},
+	"bytes":   {dataDimension, 1},
+	"kb":      {dataDimension, 1e3},
+	"mb":      {dataDimension, 1e6},
+	"gb":      {dataDimension, 1e9},
+	"tb":      {dataDimension, 1e12},
+	"pb":      {dataDimension, 1e15},
+	"kib":     {dataDimension, 1 << 10},
+	"mib":     {dataDimension, 1 << 20},
+	"gib":     {dataDimension, 1 << 30},
+	"tib":     {dataDimension, 1 << 40},
+	"pib":     {dataDimension, 1 << 50},
+	"ki":      {dataDimension, 1 << 10},
+	"mi":      {dataDimension, 1 << 20},
+	"gi":      {dataDimension, 1 << 30},
+	"ti":      {dataDimension, 1 << 40},
+	"ms":      {durationDimension, 0.001},
+	"s":       {durationDimension, 1},
+	"sec":     {durationDimension, 1},
+	"secs":    {durationDimension, 1},
+	"second":  {durationDimension, 1},
+	"seconds": {durationDimension, 1},
+	"min":     {durationDimension, 60},
+	"mins":    {durationDimension, 60},
+	"minute":  {durationDimension, 60},
+	"minutes": {durationDimension, 60},
+	"h":       {durationDimension, 3600},
+	"hr":      {durationDimension, 3600},
+	"hrs":     {durationDimension, 3600},
+	"hour":    {durationDimension, 3600},
+	"hours":   {durationDimension, 3600},
+	"d":       {durationDimension, 24 * 3600},
+	"day":     {durationDimension, 24 * 3600},
+	"days":    {durationDimension, 24 * 3600},
+	"month":   {durationDimension, hoursPerMonth * 3600},
+	"months":  {durationDimension, hoursPerMonth * 3600},
+}
+
+// periods are the number of hours in the periods that usage can be given per, e.g.
+// "12 hrs/day" or "2M requests per month".
+var periods = map[string]float64{
+	"s":      1.0 / 3600,
+	"sec":    1.0 / 3600,
+	"second": 1.0 / 3600,
+	"min":    1.0 / 60,
+	"minute": 1.0 / 60,
+	"h":      1,
+	"hr":     1,
+	"hour":   1,
+	"d":      24,
+	"day":    24,
+	"week":   24 * 7,
+	"mo":     hoursPerMonth,
+	"month":  hoursPerMonth,
+	"yr":     hoursPerMonth * 12,
+	"year":   hoursPerMonth * 12,
+}
+
+// multipliers can be put between a number and its unit, e.g. "2M requests" or "1.5k GB".
+var multipliers = map[string]float64{
+	"k":        1e3,
+	"K":        1e3,
+	"thousand": 1e3,
+	"M":        1e6,
+	"million":  1e6,
+	"bn":       1e9,
+	"billion":  1e9,
+}
+
+// A number followed by the rest of the value, e.g. "2M requests/day"
+var numberWithUnitRegex = regexp.MustCompile(`^([-+]?(?:\d[\d,]*)?\.?\d+(?:[eE][-+]?\d+)?)\s*(.*)$`)
+
+// The period of a value, e.g. "/day" or "per day"
+var valuePeriodRegex = regexp.MustCompile(`(?i)\s*(?:/|\bper\s+)\s*([a-z]+)$`)
+
+// keyUnit returns the unit and period of a usage key. The unit is the suffix of the key or
+// of the mapping it's in, e.g. GB for monthly_data_processed_gb.worldwide, or a count for
+// keys without a unit. The period is month for monthly keys, the period of keys that are
+// a rate, e.g. hour for capacity_units_per_hr, or empty.
+func keyUnit(key string) (*unit, string) {
+	parts := strings.Split(usageKeyIndexRegex.ReplaceAllString(key, ""), ".")
+
+	u := &unit{dimension: countDimension, factor: 1}
+	period := ""
+
+	for i := len(parts) - 1; i >= 0; i-- {
+		words := strings.Split(parts[i], "_")
+
+		if len(words) > 2 && words[len(words)-2] == "per" {
+			if _, ok := periods[singular(words[len(words)-1])]; ok && period == "" {
+				period = singular(words[len(words)-1])
+				continue
+			}
+		}
+
+		if ku, ok := keyUnits[words[len(words)-1]]; ok && len(words) > 1 && u.dimension == countDimension {
+			u = ku
+		}
+
+		if words[0] == "monthly" && period == "" {
+			period = "month"
+		}
+	}
+
+	return u, period
+}
+
+// normalizeUsageValue converts a usage value with a unit, e.g. "500 GiB" or "12 hrs/day", to
+// a number in the unit of the key. Values without a unit are numbers in the unit of the
+// key already. Count keys accept any unit that isn't data or a duration, e.g. "2M requests".
+func normalizeUsageValue(key, value string) (float64, error) {
+	v := strings.TrimSpace(value)
+
+	valuePeriod := ""
+	if m := valuePeriodRegex.FindStringSubmatch(v); m != nil {
+		valuePeriod = singular(strings.ToLower(m[1]))
+		if _, ok := periods[valuePeriod]; !ok {
+			return 0, fmt.Errorf("unknown period %q", m[1])
+		}
+
+		v = strings.TrimSpace(v[:len(v)-len(m[0])])
+	}
+
+	m := numberWithUnitRegex.FindStringSubmatch(v)
+	if m == nil {
+		return 0, fmt.Errorf("should be a number, got %q", value)
+	}
+
+	n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("should be a number, got %q", value)
+	}
+
+	words := strings.Fields(m[2])
+	if len(words) > 0 {
+		if f, ok := multipliers[words[0]]; ok {
+			n *= f
+			words = words[1:]
+		}
+	}
+
+	if len(words) > 1 {
+		return 0, fmt.Errorf("should be a number with a single unit, got %q", value)
+	}
+
+	ku, keyPeriod := keyUnit(key)
+
+	if len(words) == 1 {
+		vu, ok := valueUnits[strings.ToLower(words[0])]
+
+		switch {
+		case ku.dimension == countDimension && ok:
+			return 0, fmt.Errorf("should be a count, got %q", value)
+		case ku.dimension == countDimension:
+			// Any other word is the noun of the count, e.g. requests
+		case !ok:
+			return 0, fmt.Errorf("should be a number of %s, got %q", describeUnit(ku), value)
+		case vu.dimension != ku.dimension:
+			return 0, fmt.Errorf("should be a number of %s, got %q", describeUnit(ku), value)
+		default:
+			n = n * vu.factor / ku.factor
+		}
+	}
+
+	if valuePeriod != "" {
+		if keyPeriod == "" {
+			return 0, fmt.Errorf("isn't a rate, got %q", value)
+		}
+
+		n = n * periods[keyPeriod] / periods[valuePeriod]
+	}
+
+	return n, nil
+}
+
+// singular returns the singular of a period, e.g. day for days.
+func singular(s string) string {
+	if len(s) > 2 && strings.HasSuffix(s, "s") {
+		return strings.TrimSuffix(s, "s")
+	}
+
+	return s
+}
+
+// keyUnitNames are the names of the units of usage keys used in errors.
+var keyUnitNames = []struct {
+	name string
+	unit *unit
+}{
+	{"KiB", keyUnits["kb"]},
+	{"MiB", keyUnits["mb"]},
+	{"GiB", keyUnits["gb"]},
+	{"TiB", keyUnits["tb"]},
+	{"milliseconds", keyUnits["ms"]},
+	{"seconds", keyUnits["secs"]},
+	{"minutes", keyUnits["mins"]},
+	{"hours", keyUnits["hrs"]},
+}
+
+// keyUnitName returns the name of the unit of a usage key, e.g. GiB per month, or an empty
+// string for keys that are counts of something.
+func keyUnitName(key string) string {
+	u, period := keyUnit(key)
+
+	name := ""
+	for _, n := range keyUnitNames {
+		if *n.unit == *u {
+			name = n.name
+		}
+	}
+
+	if name == "" && period == "" {
+		return ""
+	}
+	if name == "" {
+		name = "count"
+	}
+	if period != "" {
+		name += " per " + period
+	}
+
+	return name
+}
+
+func describeUnit(u *unit) string {
+	for _, n := range keyUnitNames {
+		if *n.unit == *u {
+			return fmt.Sprintf("%s (%s)", u.dimension, n.name)
+		}
+	}
+
+	return u.dimension
+}
+
+// normalizeUsageValues replaces the values with units in the usage of each resource with
+// numbers in the units of their keys, and the values that are ranges with their expected
+// values. It returns the ranges of the keys of each resource. Values that can't be converted
+// are left for the validation of the usage file to report.
+func normalizeUsageValues(resources map[string]interface{}, resourceType func(string) string, usageSchema map[string]map[string]*usageKeySchema) map[string]map[string]schema.UsageRange {
+	ranges := make(map[string]map[string]schema.UsageRange)
+
+	for name, usage := range resources {
+		keys, ok := usageSchema[resourceType(name)]
+		if !ok {
+			keys = map[string]*usageKeySchema{}
+		}
+
+		resourceRanges := make(map[string]schema.UsageRange)
+		normalizeMapValues("", usage, keys, resourceRanges)
+		if len(resourceRanges) > 0 {
+			ranges[name] = resourceRanges
+		}
+	}
+
+	return ranges
+}
+
+func normalizeMapValues(prefix string, usage interface{}, keys map[string]*usageKeySchema, ranges map[string]schema.UsageRange) {
+	m, ok := usage.(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+
+	for k, v := range m {
+		key := prefix + fmt.Sprint(k)
+		schemaKey := usageKeyIndexRegex.ReplaceAllString(key, "[0]")
+
+		ks, ok := keys[schemaKey]
+		if !ok && prefix == "" {
+			ks, ok = globalUsageKeys[schemaKey]
+		}
+		isNumber := ok && ks.kind == numberKind
+
+		if nested, ok := v.(map[interface{}]interface{}); ok {
+			if !isNumber {
+				normalizeMapValues(key+".", v, keys, ranges)
+				continue
+			}
+
+			values := make(map[string]string, len(nested))
+			for rk, rv := range nested {
+				values[fmt.Sprint(rk)] = fmt.Sprint(rv)
+			}
+
+			if expected, r, err := parseUsageRange(key, values); err == nil {
+				m[k] = expected
+				ranges[key] = r
+			}
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok || !isNumber {
+			continue
+		}
+
+		if n, err := normalizeUsageValue(key, s); err == nil {
+			m[k] = n
+		}
+	}
+}
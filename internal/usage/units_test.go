@@ -0,0 +1,83 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeUsageValue(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected float64
+		err      string
+	}{
+		{key: "storage_gb", value: "500", expected: 500},
+		{key: "storage_gb", value: "500 GiB", expected: 500},
+		{key: "storage_gb", value: "2TiB", expected: 2048},
+		{key: "storage_gb", value: "1 Gi", expected: 1},
+		{key: "storage_gb", value: "1,000,000,000 bytes", expected: 0.9313225746154785},
+		{key: "standard.storage_gb", value: "1024 MiB", expected: 1},
+		{key: "monthly_data_processed_gb.worldwide", value: "1 TiB", expected: 1024},
+		{key: "request_duration_ms", value: "1.5 s", expected: 1500},
+		{key: "monthly_requests", value: "2M requests", expected: 2000000},
+		{key: "monthly_requests", value: "2 million", expected: 2000000},
+		{key: "monthly_requests", value: "1k requests/day", expected: 30416.666666666664},
+		{key: "monthly_requests", value: "10 per hour", expected: 7300},
+		{key: "monthly_hrs", value: "12 hrs/day", expected: 365},
+		{key: "monthly_hrs", value: "1 month", expected: 730},
+		{key: "monthly_build_mins", value: "2 hours", expected: 120},
+		{key: "capacity_units_per_hr", value: "240 units/day", expected: 10},
+		{key: "read_requests_per_sec", value: "60 per minute", expected: 1},
+		{key: "storage_gb", value: "lots", err: `should be a number, got "lots"`},
+		{key: "storage_gb", value: "10 hours", err: `should be a number of data (GiB), got "10 hours"`},
+		{key: "storage_gb", value: "10 GB/day", err: `isn't a rate, got "10 GB/day"`},
+		{key: "monthly_requests", value: "10 GB", err: `should be a count, got "10 GB"`},
+		{key: "monthly_requests", value: "10 big requests", err: `should be a number with a single unit, got "10 big requests"`},
+		{key: "monthly_requests", value: "10/fortnight", err: `unknown period "fortnight"`},
+	}
+
+	for _, tt := range tests {
+		actual, err := normalizeUsageValue(tt.key, tt.value)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.value)
+			continue
+		}
+
+		require.NoError(t, err, tt.value)
+		assert.InDelta(t, tt.expected, actual, 1e-9, tt.value)
+	}
+}
+
+func TestParseUsageValuesWithUnits(t *testing.T) {
+	usage, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 0.25s
+resource_usage:
+  aws_lambda_function.fn:
+    monthly_requests: 2M requests
+    count_estimate: 2 instances
+  aws_s3_bucket.bucket:
+    standard:
+      storage_gb: 1.5 TiB
+  aws_vpc.main:
+    monthly_data_gb: 2 GiB
+`))
+	require.NoError(t, err)
+
+	fn := schema.FindUsageData(usage, "aws_lambda_function.fn")
+	assert.Equal(t, int64(2000000), fn.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), fn.Get("request_duration_ms").Int())
+	assert.Equal(t, int64(2), fn.Get("count_estimate").Int())
+
+	bucket := schema.FindUsageData(usage, "aws_s3_bucket.bucket")
+	assert.Equal(t, int64(1536), bucket.Get("standard.storage_gb").Int())
+
+	// The keys of resources that aren't in the usage schema are left as they are
+	vpc := schema.FindUsageData(usage, "aws_vpc.main")
+	assert.Equal(t, "2 GiB", vpc.Get("monthly_data_gb").String())
+}
@@ -1,16 +1,18 @@
 package usage
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"sort"
+	"path/filepath"
 	"strings"
 
-	"github.com/infracost/infracost"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
 	"golang.org/x/mod/semver"
 	"gopkg.in/yaml.v2"
 )
@@ -21,170 +23,15 @@ const maxUsageFileVersion = "0.1"
 type UsageFile struct { // nolint:golint
 	Version       string                 `yaml:"version"`
 	ResourceUsage map[string]interface{} `yaml:"resource_usage"`
-}
-
-type SchemaItem struct {
-	Key          string
-	ValueType    schema.UsageVariableType
-	DefaultValue interface{}
-}
-
-func SyncUsageData(project *schema.Project, existingUsageData map[string]*schema.UsageData, usageFilePath string) error {
-	if usageFilePath == "" {
-		return nil
-	}
-	usageSchema, err := loadUsageSchema()
-	if err != nil {
-		return err
-	}
-	syncedResourcesUsage := syncResourcesUsage(project.Resources, usageSchema, existingUsageData)
-	// yaml.MapSlice is used to maintain the order of keys, so re-running
-	// the code won't change the output.
-	syncedUsageData := yaml.MapSlice{
-		{Key: "version", Value: 0.1},
-		{Key: "resource_usage", Value: syncedResourcesUsage},
-	}
-	d, err := yaml.Marshal(syncedUsageData)
-	if err != nil {
-		return err
-	}
-	err = ioutil.WriteFile(usageFilePath, d, 0600)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func syncResourcesUsage(resources []*schema.Resource, usageSchema map[string][]*SchemaItem, existingUsageData map[string]*schema.UsageData) yaml.MapSlice {
-	syncedResourceUsage := make(map[string]interface{})
-	for _, resource := range resources {
-		resourceName := resource.Name
-		resourceUSchema := resource.UsageSchema
-		if resource.UsageSchema == nil {
-			// There is no explicitly defined UsageSchema for this resource.  Use the old way and create one from
-			// infracost-usage-example.yml.
-			resourceTypeNames := strings.Split(resourceName, ".")
-			if len(resourceTypeNames) < 2 {
-				// It's a resource with no name
-				continue
-			}
-			// This handles module names appearing in the resourceName too
-			resourceTypeName := resourceTypeNames[len(resourceTypeNames)-2]
-			schemaItems, ok := usageSchema[resourceTypeName]
-			if !ok {
-				continue
-			}
-
-			resourceUSchema = make([]*schema.UsageSchemaItem, 0, len(schemaItems))
-			for _, s := range schemaItems {
-				resourceUSchema = append(resourceUSchema, &schema.UsageSchemaItem{
-					Key:          s.Key,
-					DefaultValue: s.DefaultValue,
-					ValueType:    s.ValueType,
-				})
-			}
-		}
-
-		resourceUsage := make(map[string]interface{})
-		for _, usageSchemaItem := range resourceUSchema {
-			usageKey := usageSchemaItem.Key
-			usageValueType := usageSchemaItem.ValueType
-			var usageValue interface{}
-			usageValue = usageSchemaItem.DefaultValue
-			if existingUsage, ok := existingUsageData[resourceName]; ok {
-				switch usageValueType {
-				case schema.Float64:
-					usageValue = existingUsage.Get(usageKey).Float()
-				case schema.Int64:
-					usageValue = existingUsage.Get(usageKey).Int()
-				case schema.String:
-					usageValue = existingUsage.Get(usageKey).String()
-				}
-			}
-			resourceUsage[usageKey] = usageValue
-		}
-		syncedResourceUsage[resourceName] = unFlattenHelper(resourceUsage)
-	}
-	// yaml.MapSlice is used to maintain the order of keys, so re-running
-	// the code won't change the output.
-	result := mapToSortedMapSlice(syncedResourceUsage)
-	return result
-}
-
-func loadUsageSchema() (map[string][]*SchemaItem, error) {
-	usageSchema := make(map[string][]*SchemaItem)
-	usageData, err := loadReferenceFile()
-	if err != nil {
-		return usageSchema, err
-	}
-	for _, resUsageData := range usageData {
-		resourceTypeName := strings.Split(resUsageData.Address, ".")[0]
-		usageSchema[resourceTypeName] = make([]*SchemaItem, 0)
-		for usageKeyName, usageRawResult := range resUsageData.Attributes {
-			var defaultValue interface{}
-			usageValueType := schema.Int64
-			defaultValue = 0
-			usageRawValue := usageRawResult.Value()
-			if _, ok := usageRawValue.(string); ok {
-				usageValueType = schema.String
-				defaultValue = usageRawResult.String()
-			}
-			usageSchema[resourceTypeName] = append(usageSchema[resourceTypeName], &SchemaItem{
-				Key:          usageKeyName,
-				ValueType:    usageValueType,
-				DefaultValue: defaultValue,
-			})
-		}
-	}
-	return usageSchema, nil
-}
-
-func unFlattenHelper(input map[string]interface{}) map[string]interface{} {
-	result := make(map[string]interface{})
-	for k, v := range input {
-		rootMap := &result
-		splittedKey := strings.Split(k, ".")
-		for it := 0; it < len(splittedKey)-1; it++ {
-			key := splittedKey[it]
-			if _, ok := (*rootMap)[key]; !ok {
-				(*rootMap)[key] = make(map[string]interface{})
-			}
-			casted := (*rootMap)[key].(map[string]interface{})
-			rootMap = &casted
-		}
-		key := splittedKey[len(splittedKey)-1]
-		(*rootMap)[key] = v
-	}
-	return result
-}
-
-func mapToSortedMapSlice(input map[string]interface{}) yaml.MapSlice {
-	result := make(yaml.MapSlice, 0)
-	// sort keys of the input to maintain same output for different runs.
-	keys := make([]string, 0)
-	for k := range input {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	// Iterate over sorted keys
-	for _, k := range keys {
-		v := input[k]
-		if casted, ok := v.(map[string]interface{}); ok {
-			result = append(result, yaml.MapItem{Key: k, Value: mapToSortedMapSlice(casted)})
-		} else {
-			result = append(result, yaml.MapItem{Key: k, Value: v})
-		}
-	}
-	return result
-}
-
-func loadReferenceFile() (map[string]*schema.UsageData, error) {
-	referenceUsageFileContents := infracost.GetReferenceUsageFileContents()
-	usageData, err := parseYAML(*referenceUsageFileContents)
-	if err != nil {
-		return usageData, errors.Wrapf(err, "Error parsing usage file")
-	}
-	return usageData, nil
+	// ResourceTypeDefaultUsage is the usage of all the resources of a type, e.g.
+	// aws_lambda_function, which the usage of a resource in ResourceUsage overrides.
+	ResourceTypeDefaultUsage map[string]interface{} `yaml:"resource_type_default_usage"`
+	// Variables are numbers that usage values can refer to in expressions, e.g.
+	// monthly_requests: ${daily_requests} * 30.
+	Variables map[string]interface{} `yaml:"variables"`
+	// UsageAnchors are named usage that resources can use with usage_anchor, so the same
+	// estimates can be shared by many resources.
+	UsageAnchors map[string]interface{} `yaml:"usage_anchors"`
 }
 
 func LoadFromFile(usageFilePath string, createIfNotExisting bool) (map[string]*schema.UsageData, error) {
@@ -202,6 +49,12 @@ func LoadFromFile(usageFilePath string, createIfNotExisting bool) (map[string]*s
 				{Key: "resource_usage", Value: make(map[string]interface{})},
 			}
 			d, err := yaml.Marshal(fileContent)
+			if isJSONUsageFile(usageFilePath) {
+				d, err = json.MarshalIndent(map[string]interface{}{
+					"version":        "0.1",
+					"resource_usage": make(map[string]interface{}),
+				}, "", "  ")
+			}
 			if err != nil {
 				return usageData, errors.Wrapf(err, "Error creating usage file")
 			}
@@ -227,7 +80,54 @@ func LoadFromFile(usageFilePath string, createIfNotExisting bool) (map[string]*s
 	return usageData, nil
 }
 
+// MergeUsageData merges the usage of a project over a base usage that's shared by several
+// projects. The usage of a resource address, wildcard or resource type that's in both is
+// merged key by key, with the project's values taking precedence.
+func MergeUsageData(base map[string]*schema.UsageData, usage map[string]*schema.UsageData) map[string]*schema.UsageData {
+	merged := make(map[string]*schema.UsageData, len(base)+len(usage))
+
+	for k, u := range base {
+		merged[k] = u
+	}
+
+	for k, u := range usage {
+		b, ok := merged[k]
+		if !ok {
+			merged[k] = u
+			continue
+		}
+
+		m := schema.NewUsageData(k, make(map[string]gjson.Result, len(b.Attributes)+len(u.Attributes)))
+		for key := range b.Attributes {
+			m.CopyKey(b, key)
+		}
+		for key := range u.Attributes {
+			m.CopyKey(u, key)
+		}
+
+		merged[k] = m
+	}
+
+	return merged
+}
+
+// parseYAML parses a usage file in YAML or JSON and checks it against the usage schema.
 func parseYAML(y []byte) (map[string]*schema.UsageData, error) {
+	y = jsonAsYAML(y)
+
+	usageMap, err := parseUsageMap(y)
+	if err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	if err := validateUsageFile(y); err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	return usageMap, nil
+}
+
+func parseUsageMap(y []byte) (map[string]*schema.UsageData, error) {
 	var usageFile UsageFile
 
 	err := yaml.Unmarshal(y, &usageFile)
@@ -239,8 +139,60 @@ func parseYAML(y []byte) (map[string]*schema.UsageData, error) {
 		return map[string]*schema.UsageData{}, fmt.Errorf("Invalid usage file version. Supported versions are %s ≤ x ≤ %s", minUsageFileVersion, maxUsageFileVersion)
 	}
 
+	usageSchema, err := loadUsageSchema()
+	if err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	variables, err := evaluateUsageVariables(usageFile.Variables)
+	if err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	// Anchors are resolved before expressions so each resource's copy is evaluated with its
+	// own keys
+	if err := resolveUsageAnchors(usageFile.ResourceUsage, usageFile.UsageAnchors); err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+	if err := resolveUsageAnchors(usageFile.ResourceTypeDefaultUsage, usageFile.UsageAnchors); err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	// Expressions are evaluated first so their values can be ranges or have units too
+	if err := evaluateUsageExpressions(usageFile.ResourceUsage, variables); err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+	if err := evaluateUsageExpressions(usageFile.ResourceTypeDefaultUsage, variables); err != nil {
+		return map[string]*schema.UsageData{}, err
+	}
+
+	// Values with units, e.g. "500 GiB", are converted to the units of their keys
+	ranges := normalizeUsageValues(usageFile.ResourceUsage, schema.ResourceTypeFromAddress, usageSchema)
+	typeDefaultRanges := normalizeUsageValues(usageFile.ResourceTypeDefaultUsage, func(resourceType string) string {
+		return resourceType
+	}, usageSchema)
+	for resourceType, r := range typeDefaultRanges {
+		ranges[resourceType] = r
+	}
+
 	usageMap := schema.NewUsageMap(usageFile.ResourceUsage)
 
+	// The resource type defaults are keyed by the resource type, which can't clash with the
+	// resource addresses since they always have a name after the type
+	for resourceType, v := range usageFile.ResourceTypeDefaultUsage {
+		if strings.Contains(resourceType, ".") {
+			return map[string]*schema.UsageData{}, fmt.Errorf("Invalid resource type %s in resource_type_default_usage, use resource_usage for the usage of a resource", resourceType)
+		}
+
+		usageMap[resourceType] = schema.NewUsageData(resourceType, schema.ParseAttributes(v))
+	}
+
+	for name, keyRanges := range ranges {
+		for k, r := range keyRanges {
+			usageMap[name].SetRange(k, r)
+		}
+	}
+
 	return usageMap, nil
 }
 
@@ -250,3 +202,23 @@ func checkVersion(v string) bool {
 	}
 	return semver.Compare(v, "v"+minUsageFileVersion) >= 0 && semver.Compare(v, "v"+maxUsageFileVersion) <= 0
 }
+
+// isJSONUsageFile returns true if the usage file should be written as JSON.
+func isJSONUsageFile(usageFilePath string) bool {
+	return strings.EqualFold(filepath.Ext(usageFilePath), ".json")
+}
+
+func isJSON(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("{"))
+}
+
+// jsonAsYAML returns a usage file in JSON as YAML, since JSON is a subset of YAML apart from
+// tabs, which can only be whitespace between the tokens of JSON so they're replaced with
+// spaces. Usage files in YAML are returned as they are.
+func jsonAsYAML(b []byte) []byte {
+	if !isJSON(b) {
+		return b
+	}
+
+	return bytes.ReplaceAll(b, []byte("\t"), []byte(" "))
+}
@@ -0,0 +1,52 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeUsageData(t *testing.T) {
+	base, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: 1000
+    request_duration_ms: 500
+resource_usage:
+  aws_lambda_function.shared:
+    monthly_requests: 5000
+  aws_lambda_function.api:
+    monthly_requests: 2000
+    request_duration_ms: 100
+`))
+	require.NoError(t, err)
+
+	usage, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    request_duration_ms: 250
+resource_usage:
+  aws_lambda_function.api:
+    monthly_requests: 3000
+`))
+	require.NoError(t, err)
+
+	merged := MergeUsageData(base, usage)
+
+	shared := schema.FindUsageData(merged, "aws_lambda_function.shared")
+	assert.Equal(t, int64(5000), shared.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), shared.Get("request_duration_ms").Int())
+
+	api := schema.FindUsageData(merged, "aws_lambda_function.api")
+	assert.Equal(t, int64(3000), api.Get("monthly_requests").Int())
+	assert.Equal(t, int64(100), api.Get("request_duration_ms").Int())
+
+	other := schema.FindUsageData(merged, "aws_lambda_function.other")
+	assert.Equal(t, int64(1000), other.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), other.Get("request_duration_ms").Int())
+
+	// The base usage isn't changed
+	assert.Equal(t, int64(2000), schema.FindUsageData(base, "aws_lambda_function.api").Get("monthly_requests").Int())
+}
@@ -0,0 +1,81 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type usageProfilesFile struct {
+	Version string `yaml:"version"`
+	// UsageProfiles are the default usage of each resource type of each profile, keyed by
+	// the profile name and resource type.
+	UsageProfiles map[string]map[string]interface{} `yaml:"usage_profiles"`
+}
+
+// UsageProfileNames returns the names of the built-in usage profiles.
+func UsageProfileNames() ([]string, error) {
+	profiles, err := loadUsageProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ApplyUsageProfile adds the usage of the built-in usage profile to the usage as the default
+// usage of each resource type. The resource type defaults and resource usage from the usage
+// file take precedence over the profile.
+func ApplyUsageProfile(usage map[string]*schema.UsageData, profile string) error {
+	profiles, err := loadUsageProfiles()
+	if err != nil {
+		return err
+	}
+
+	resourceTypes, ok := profiles[profile]
+	if !ok {
+		names, _ := UsageProfileNames()
+		return fmt.Errorf("Invalid usage profile %s, expected one of: %s", profile, strings.Join(names, ", "))
+	}
+
+	for resourceType, v := range resourceTypes {
+		attributes := schema.ParseAttributes(v)
+
+		merged := schema.NewUsageData(resourceType, attributes)
+		for k := range attributes {
+			merged.SetProvenance(k, schema.UsageProvenanceDefault)
+		}
+
+		if existing, ok := usage[resourceType]; ok {
+			for k := range existing.Attributes {
+				merged.CopyKey(existing, k)
+			}
+		}
+
+		usage[resourceType] = merged
+	}
+
+	return nil
+}
+
+func loadUsageProfiles() (map[string]map[string]interface{}, error) {
+	var f usageProfilesFile
+
+	err := yaml.Unmarshal(*infracost.GetUsageProfilesFileContents(), &f)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing usage profiles")
+	}
+
+	return f.UsageProfiles, nil
+}
@@ -0,0 +1,41 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageProfileNames(t *testing.T) {
+	names, err := UsageProfileNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"high", "low", "medium"}, names)
+}
+
+func TestApplyUsageProfile(t *testing.T) {
+	usage, err := parseYAML([]byte(`version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: 5
+resource_usage:
+  aws_lambda_function.fn:
+    request_duration_ms: 10
+`))
+	require.NoError(t, err)
+
+	require.NoError(t, ApplyUsageProfile(usage, "medium"))
+
+	u := schema.FindUsageData(usage, "aws_lambda_function.fn")
+	assert.Equal(t, int64(5), u.Get("monthly_requests").Int())
+	assert.Equal(t, schema.UsageProvenanceUsageFile, u.Provenance("monthly_requests"))
+	assert.Equal(t, int64(10), u.Get("request_duration_ms").Int())
+	assert.Equal(t, schema.UsageProvenanceUsageFile, u.Provenance("request_duration_ms"))
+
+	u = schema.FindUsageData(usage, "aws_s3_bucket.bucket")
+	assert.Equal(t, int64(100), u.Get("standard.storage_gb").Int())
+	assert.Equal(t, schema.UsageProvenanceDefault, u.Provenance("standard.storage_gb"))
+
+	assert.EqualError(t, ApplyUsageProfile(usage, "huge"), "Invalid usage profile huge, expected one of: high, low, medium")
+}
@@ -0,0 +1,482 @@
+package usage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/infracost/infracost"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// The kinds of values of usage keys
+const (
+	numberKind  = "number"
+	stringKind  = "string"
+	booleanKind = "boolean"
+	mappingKind = "mapping"
+)
+
+// usageFileKeys are the keys allowed at the top of a usage file.
+var usageFileKeys = []string{"version", "resource_usage", "resource_type_default_usage", "variables", "usage_anchors"}
+
+// globalUsageKeys are the usage keys that any resource can have, since they're handled by
+// the parser instead of the resources.
+var globalUsageKeys = map[string]*usageKeySchema{
+	"count_estimate": {kind: numberKind},
+	usageAnchorKey:   {kind: stringKind},
+}
+
+// The options of a key are listed in its description in the reference usage file, e.g.
+// "# Term for Reserved Instances, can be: 1_year, 3_year."
+var usageKeyOptionsRegex = regexp.MustCompile(`can be: ([\w-]+(?:, [\w-]+)*)`)
+
+// Deprecated keys are kept in the reference usage file until they're removed, with the key
+// that replaces them in their description, e.g. "# Deprecated, use storage_gb instead."
+var deprecatedUsageKeyRegex = regexp.MustCompile(`Deprecated, use ([\w.\[\]]+) instead`)
+
+// The reference usage file has the usage of the first of a list of blocks, e.g. node_pool[0]
+var usageKeyIndexRegex = regexp.MustCompile(`\[\d+\]`)
+
+// usageKeySchema is the schema of a usage key of a resource type.
+type usageKeySchema struct {
+	kind string
+	// options are the values allowed for the key, or empty if any value of its kind is.
+	options []string
+	// replacedBy is the key to use instead of a deprecated key.
+	replacedBy string
+}
+
+// ValidationError is the error for a usage file that doesn't match the usage schema. It
+// has all the problems with the file so they can be fixed at once.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid usage file:\n  %s", strings.Join(e.Problems, "\n  "))
+}
+
+// validateUsageFile checks the keys and values of a usage file against the usage schema,
+// which is the keys of each resource type in the reference usage file. The usage of
+// resource types that aren't in the reference usage file isn't checked.
+func validateUsageFile(y []byte) error {
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(y, doc); err != nil {
+		return errors.Wrap(err, "Error parsing usage YAML")
+	}
+
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+
+	usageSchema, err := loadUsageSchema()
+	if err != nil {
+		return err
+	}
+
+	v := &usageValidator{schema: usageSchema}
+	v.validateFile(doc.Content[0])
+
+	for _, p := range v.deprecations {
+		log.Warn(p.String())
+	}
+
+	if len(v.problems) > 0 {
+		problems := make([]string, 0, len(v.problems))
+		for _, p := range v.problems {
+			problems = append(problems, p.String())
+		}
+
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+type usageValidator struct {
+	// schema is the schema of the usage keys of each resource type. Nested keys are joined
+	// with dots, e.g. standard.storage_gb.
+	schema       map[string]map[string]*usageKeySchema
+	problems     []*LintProblem
+	deprecations []*LintProblem
+	// anchors are the usage_anchors of the file, which are checked against the usage schema
+	// of each resource type that uses them
+	anchors *yamlv3.Node
+	// anchorTypes are the resource types each anchor has been checked for
+	anchorTypes map[string]map[string]bool
+}
+
+func (v *usageValidator) addProblem(node *yamlv3.Node, format string, args ...interface{}) {
+	v.problems = append(v.problems, &LintProblem{Line: node.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *usageValidator) validateFile(root *yamlv3.Node) {
+	if root.Kind != yamlv3.MappingNode {
+		v.addProblem(root, "expected a mapping of %s", strings.Join(usageFileKeys, ", "))
+		return
+	}
+
+	v.anchors = findMappingValue(root, "usage_anchors")
+	v.anchorTypes = make(map[string]map[string]bool)
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		switch key.Value {
+		case "version", "variables":
+		case "usage_anchors":
+			v.validateAnchors(value)
+		case "resource_usage":
+			v.validateResources(value, schema.ResourceTypeFromAddress)
+		case "resource_type_default_usage":
+			v.validateResources(value, func(resourceType string) string {
+				return resourceType
+			})
+		default:
+			v.addProblem(key, "unknown key %s%s", key.Value, didYouMean(key.Value, usageFileKeys))
+		}
+	}
+}
+
+// validateResources checks the usage of each resource in a mapping of resource addresses or
+// types to their usage.
+func (v *usageValidator) validateResources(resources *yamlv3.Node, resourceType func(string) string) {
+	if isNull(resources) {
+		return
+	}
+
+	if resources.Kind != yamlv3.MappingNode {
+		v.addProblem(resources, "expected a mapping of resources to their usage")
+		return
+	}
+
+	for i := 0; i+1 < len(resources.Content); i += 2 {
+		name, usage := resources.Content[i], resources.Content[i+1]
+		if isNull(usage) {
+			continue
+		}
+
+		if usage.Kind != yamlv3.MappingNode {
+			v.addProblem(usage, "the usage of %s should be a mapping of usage keys to their values", name.Value)
+			continue
+		}
+
+		keys, ok := v.schema[resourceType(name.Value)]
+		if !ok {
+			continue
+		}
+
+		v.validateUsage(name.Value, "", usage, keys)
+
+		if anchorName := findMappingValue(usage, usageAnchorKey); anchorName != nil {
+			v.validateAnchorUsage(anchorName.Value, resourceType(name.Value), keys)
+		}
+	}
+}
+
+// validateAnchors checks that the usage_anchors are a mapping of names to usage. Their
+// keys are checked for each resource type that uses them.
+func (v *usageValidator) validateAnchors(anchors *yamlv3.Node) {
+	if isNull(anchors) {
+		return
+	}
+
+	if anchors.Kind != yamlv3.MappingNode {
+		v.addProblem(anchors, "expected a mapping of usage anchors to their usage")
+		return
+	}
+
+	for i := 0; i+1 < len(anchors.Content); i += 2 {
+		name, usage := anchors.Content[i], anchors.Content[i+1]
+		if !isNull(usage) && usage.Kind != yamlv3.MappingNode {
+			v.addProblem(usage, "the usage anchor %s should be a mapping of usage keys to their values", name.Value)
+		}
+	}
+}
+
+// validateAnchorUsage checks the usage of an anchor against the usage schema of a resource
+// type that uses it. Anchors that aren't in the file are reported when the file is parsed.
+func (v *usageValidator) validateAnchorUsage(anchorName, resourceType string, keys map[string]*usageKeySchema) {
+	if v.anchorTypes[anchorName][resourceType] || v.anchors == nil || v.anchors.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	if _, ok := v.anchorTypes[anchorName]; !ok {
+		v.anchorTypes[anchorName] = make(map[string]bool)
+	}
+	v.anchorTypes[anchorName][resourceType] = true
+
+	usage := findMappingValue(v.anchors, anchorName)
+	if usage == nil || usage.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	v.validateUsage(fmt.Sprintf("usage anchor %s (used by %s)", anchorName, resourceType), "", usage, keys)
+}
+
+// validateUsage checks the usage keys of a resource. The prefix is the schema key of the
+// mapping the keys are in, e.g. node_pool[0]. for the keys of any node pool.
+func (v *usageValidator) validateUsage(name, prefix string, usage *yamlv3.Node, keys map[string]*usageKeySchema) {
+	for i := 0; i+1 < len(usage.Content); i += 2 {
+		key, value := usage.Content[i], usage.Content[i+1]
+		fullKey := prefix + key.Value
+		schemaKey := prefix + usageKeyIndexRegex.ReplaceAllString(key.Value, "[0]")
+
+		k, ok := keys[schemaKey]
+		if !ok && prefix == "" {
+			k, ok = globalUsageKeys[schemaKey]
+		}
+
+		if !ok {
+			v.addProblem(key, "unknown key %s for %s%s", fullKey, name, didYouMean(schemaKey, schemaKeys(keys, prefix)))
+			continue
+		}
+
+		if k.replacedBy != "" {
+			v.deprecations = append(v.deprecations, &LintProblem{
+				Line:    key.Line,
+				Message: fmt.Sprintf("%s of %s is deprecated, use %s instead", fullKey, name, k.replacedBy),
+			})
+		}
+
+		if isNull(value) {
+			continue
+		}
+
+		if k.kind == mappingKind {
+			if value.Kind != yamlv3.MappingNode {
+				v.addProblem(value, "%s of %s should be a mapping of usage keys to their values", fullKey, name)
+				continue
+			}
+
+			v.validateUsage(name, schemaKey+".", value, keys)
+			continue
+		}
+
+		v.validateValue(name, fullKey, value, k)
+	}
+}
+
+func (v *usageValidator) validateValue(name, key string, value *yamlv3.Node, k *usageKeySchema) {
+	if value.Kind == yamlv3.MappingNode && k.kind == numberKind {
+		v.validateRange(name, key, value)
+		return
+	}
+
+	if value.Kind != yamlv3.ScalarNode {
+		v.addProblem(value, "%s of %s should be a %s", key, name, k.kind)
+		return
+	}
+
+	switch k.kind {
+	case numberKind:
+		// Expressions are checked when they're evaluated
+		if value.Tag == "!!int" || value.Tag == "!!float" || isUsageExpression(value.Value) {
+			return
+		}
+
+		if _, err := normalizeUsageValue(key, value.Value); err != nil {
+			v.addProblem(value, "%s of %s %s", key, name, err)
+		}
+	case booleanKind:
+		if value.Tag != "!!bool" {
+			v.addProblem(value, "%s of %s should be true or false, got %q", key, name, value.Value)
+		}
+	case stringKind:
+		if value.Tag != "!!str" {
+			v.addProblem(value, "%s of %s should be a string, got %s", key, name, value.Value)
+			return
+		}
+
+		if len(k.options) > 0 && !containsFold(k.options, value.Value) {
+			v.addProblem(value, "%s of %s should be one of %s, got %q", key, name, strings.Join(k.options, ", "), value.Value)
+		}
+
+		if key == "operating_schedule" {
+			if _, err := schema.ParseOperatingSchedule(value.Value); err != nil {
+				v.addProblem(value, "%s of %s is invalid. %s", key, name, err)
+			}
+		}
+	}
+}
+
+// validateRange checks a number usage key whose value is a range, e.g. {min: 10, max: 100}.
+func (v *usageValidator) validateRange(name, key string, value *yamlv3.Node) {
+	values := make(map[string]string, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i+1].Kind != yamlv3.ScalarNode {
+			v.addProblem(value.Content[i+1], "%s of %s should have a number for the %s of its range", key, name, value.Content[i].Value)
+			return
+		}
+
+		// Ranges with expressions are checked when they're evaluated
+		if isUsageExpression(value.Content[i+1].Value) {
+			return
+		}
+
+		values[value.Content[i].Value] = value.Content[i+1].Value
+	}
+
+	if _, _, err := parseUsageRange(key, values); err != nil {
+		v.addProblem(value, "%s of %s %s", key, name, err)
+	}
+}
+
+// loadUsageSchema returns the schema of the usage keys of each resource type in the
+// reference usage file.
+func loadUsageSchema() (map[string]map[string]*usageKeySchema, error) {
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(*infracost.GetReferenceUsageFileContents(), doc); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing reference usage file")
+	}
+
+	usageSchema := make(map[string]map[string]*usageKeySchema)
+	if len(doc.Content) == 0 {
+		return usageSchema, nil
+	}
+
+	resourceUsage := findMappingValue(doc.Content[0], "resource_usage")
+	if resourceUsage == nil {
+		return usageSchema, nil
+	}
+
+	for i := 0; i+1 < len(resourceUsage.Content); i += 2 {
+		resourceType := schema.ResourceTypeFromAddress(resourceUsage.Content[i].Value)
+		if _, ok := usageSchema[resourceType]; !ok {
+			usageSchema[resourceType] = make(map[string]*usageKeySchema)
+		}
+
+		addUsageKeySchemas(usageSchema[resourceType], "", resourceUsage.Content[i+1])
+	}
+
+	return usageSchema, nil
+}
+
+func addUsageKeySchemas(keys map[string]*usageKeySchema, prefix string, node *yamlv3.Node) {
+	if node.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := prefix + node.Content[i].Value
+		value := node.Content[i+1]
+
+		if value.Kind == yamlv3.MappingNode {
+			keys[key] = &usageKeySchema{kind: mappingKind}
+			addUsageKeySchemas(keys, key+".", value)
+			continue
+		}
+
+		if _, ok := keys[key]; ok {
+			continue
+		}
+
+		k := &usageKeySchema{kind: numberKind}
+		switch value.Tag {
+		case "!!str":
+			k.kind = stringKind
+		case "!!bool":
+			k.kind = booleanKind
+		}
+
+		description := value.LineComment
+		if description == "" {
+			description = node.Content[i].LineComment
+		}
+		if m := usageKeyOptionsRegex.FindStringSubmatch(description); m != nil && k.kind == stringKind {
+			k.options = strings.Split(m[1], ", ")
+		}
+		if m := deprecatedUsageKeyRegex.FindStringSubmatch(description); m != nil {
+			k.replacedBy = m[1]
+		}
+
+		keys[key] = k
+	}
+}
+
+// schemaKeys returns the keys with the prefix, without the prefix.
+func schemaKeys(keys map[string]*usageKeySchema, prefix string) []string {
+	matching := make([]string, 0)
+	for k := range keys {
+		if strings.HasPrefix(k, prefix) && !strings.Contains(strings.TrimPrefix(k, prefix), ".") {
+			matching = append(matching, k)
+		}
+	}
+
+	sort.Strings(matching)
+
+	return matching
+}
+
+// didYouMean returns a suggestion of the closest of the keys to a misspelt key, or an empty
+// string if none of them are close.
+func didYouMean(key string, keys []string) string {
+	best, bestDistance := "", 0
+	for _, k := range keys {
+		d := levenshteinDistance(key, k)
+		if best == "" || d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+
+	if best == "" || bestDistance > len(key)/3+1 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+func levenshteinDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = curr
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func isNull(node *yamlv3.Node) bool {
+	return node.Kind == yamlv3.ScalarNode && node.Tag == "!!null"
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package usage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestValidateUsageFile(t *testing.T) {
+	err := validateUsageFile([]byte(`version: 0.1
+resource_usage:
+  aws_lambda_function.fn:
+    monthly_request: 100
+    request_duration_ms: 250ms
+    monthly_requests: 2 GB
+  aws_s3_bucket.bucket:
+    standard:
+      storage_gb: lots
+    count_estimate: 2
+  aws_instance.web:
+    operating_system: linux
+    purchase_option: reserved
+    operating_schedule: workdays 9-5
+  google_container_cluster.cluster:
+    node_pool[1].nodes: 4
+  azurerm_windows_virtual_machine.vm:
+    azure_hybrid_benefit: "yes"
+  aws_vpc.main:
+    anything: 1
+resource_type_default_usage:
+  aws_dynamodb_table:
+    monthly_write_request_units: [1, 2]
+resources_usage:
+`))
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		"line 4: unknown key monthly_request for aws_lambda_function.fn (did you mean monthly_requests?)",
+		`line 6: monthly_requests of aws_lambda_function.fn should be a count, got "2 GB"`,
+		`line 9: standard.storage_gb of aws_s3_bucket.bucket should be a number, got "lots"`,
+		`line 13: purchase_option of aws_instance.web should be one of on_demand, spot, got "reserved"`,
+		`line 14: operating_schedule of aws_instance.web is invalid. Invalid operating schedule "workdays 9-5": unknown day "workdays", expected daily, weekdays, weekends or days like mon-fri`,
+		`line 18: azure_hybrid_benefit of azurerm_windows_virtual_machine.vm should be true or false, got "yes"`,
+		"line 23: monthly_write_request_units of aws_dynamodb_table should be a number",
+		"line 24: unknown key resources_usage (did you mean resource_usage?)",
+	}, validationErr.Problems)
+}
+
+func TestParseJSON(t *testing.T) {
+	usage, err := parseYAML([]byte(`{
+	"version": "0.1",
+	"resource_usage": {
+		"aws_lambda_function.fn": {
+			"monthly_requests": 100,
+			"request_duration_ms": 250
+		}
+	}
+}`))
+	require.NoError(t, err)
+
+	u := schema.FindUsageData(usage, "aws_lambda_function.fn")
+	assert.Equal(t, int64(100), u.Get("monthly_requests").Int())
+	assert.Equal(t, int64(250), u.Get("request_duration_ms").Int())
+
+	_, err = parseYAML([]byte(`{
+	"version": "0.1",
+	"resource_usage": {
+		"aws_lambda_function.fn": {
+			"monthly_requests": "100 k",
+			"request_duration_ms": "1.5 GB"
+		}
+	}
+}`))
+	assert.EqualError(t, err, `Invalid usage file:
+  line 6: request_duration_ms of aws_lambda_function.fn should be a number of duration (milliseconds), got "1.5 GB"`)
+}
+
+func TestSyncUsageDataJSON(t *testing.T) {
+	usageFile := filepath.Join(t.TempDir(), "infracost-usage.json")
+
+	_, err := LoadFromFile(usageFile, true)
+	require.NoError(t, err)
+
+	project := &schema.Project{Resources: []*schema.Resource{
+		{Name: "aws_lambda_function.api", ResourceType: "aws_lambda_function"},
+	}}
+	require.NoError(t, SyncUsageData(project, usageFile))
+
+	out, err := ioutil.ReadFile(usageFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+  "resource_usage": {
+    "aws_lambda_function.api": {
+      "monthly_requests": 0,
+      "request_duration_ms": 0
+    }
+  },
+  "version": "0.1"
+}
+`, string(out))
+}
+
+func TestUsageProfilesMatchUsageSchema(t *testing.T) {
+	profiles, err := loadUsageProfiles()
+	require.NoError(t, err)
+
+	for name, profile := range profiles {
+		y, err := yaml.Marshal(map[string]interface{}{
+			"version":                     "0.1",
+			"resource_type_default_usage": profile,
+		})
+		require.NoError(t, err)
+
+		assert.NoError(t, validateUsageFile(y), "usage profile %s", name)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	j, err := JSONSchema()
+	require.NoError(t, err)
+
+	var s struct {
+		Properties map[string]struct {
+			PatternProperties map[string]struct {
+				Properties map[string]map[string]interface{}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(j, &s))
+
+	lambda := s.Properties["resource_usage"].PatternProperties[`^(.+\.)?aws_lambda_function\.[^.\[]+(\[.*\])?$`]
+	assert.Equal(t, []interface{}{"number", "string", "object", "null"}, lambda.Properties["monthly_requests"]["type"])
+	assert.Equal(t, []interface{}{"number", "string", "object", "null"}, lambda.Properties["count_estimate"]["type"])
+}
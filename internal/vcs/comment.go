@@ -0,0 +1,128 @@
+package vcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Comment is a cost estimate posted to a pull request or commit
+type Comment struct {
+	// Summary is Markdown that is always shown
+	Summary string
+	// Output is the Infracost output, which is shown in a code block
+	Output string
+}
+
+// Markdown returns the comment as Markdown. If collapsible is true the output is put in
+// an HTML details element, for platforms that render HTML in comments.
+func (c Comment) Markdown(collapsible bool) string {
+	var b strings.Builder
+
+	b.WriteString(c.Summary)
+	b.WriteString("\n\n")
+
+	if collapsible {
+		b.WriteString("<details>\n  <summary><strong>Infracost output</strong></summary>\n\n")
+	} else {
+		b.WriteString("**Infracost output:**\n\n")
+	}
+
+	fmt.Fprintf(&b, "```\n%s\n```\n", strings.TrimSuffix(c.Output, "\n"))
+
+	if collapsible {
+		b.WriteString("</details>\n")
+	}
+
+	return b.String()
+}
+
+// CommentTarget is where a comment is posted. The comment is posted to the pull request if
+// it is set, otherwise to the commit.
+type CommentTarget struct {
+	PullRequest string
+	Commit      string
+}
+
+// CommentDriver posts comments to a VCS platform. Drivers are registered with
+// RegisterCommentDriver and are selected by name or by the repository's remote URL.
+type CommentDriver interface {
+	Name() string
+	// Matches returns true if the remote is hosted on the driver's platform
+	Matches(r Remote) bool
+	PostComment(r Remote, target CommentTarget, c Comment) error
+}
+
+var commentDrivers []CommentDriver
+
+// RegisterCommentDriver adds a driver. Drivers are matched against remotes in the order they
+// are registered.
+func RegisterCommentDriver(d CommentDriver) {
+	commentDrivers = append(commentDrivers, d)
+}
+
+// CommentDriverNames returns the names of the registered drivers
+func CommentDriverNames() []string {
+	names := make([]string, 0, len(commentDrivers))
+	for _, d := range commentDrivers {
+		names = append(names, d.Name())
+	}
+
+	return names
+}
+
+// CommentDriverByName returns the registered driver with the name
+func CommentDriverByName(name string) (CommentDriver, error) {
+	for _, d := range commentDrivers {
+		if strings.EqualFold(d.Name(), name) {
+			return d, nil
+		}
+	}
+
+	return nil, errors.Errorf("Unknown VCS platform %s, supported platforms are: %s", name, strings.Join(CommentDriverNames(), ", "))
+}
+
+// CommentDriverForRemote returns the first registered driver that matches the remote
+func CommentDriverForRemote(r Remote) (CommentDriver, error) {
+	for _, d := range commentDrivers {
+		if d.Matches(r) {
+			return d, nil
+		}
+	}
+
+	return nil, errors.Errorf("Could not detect the VCS platform of %s, set it to one of: %s", r.Host, strings.Join(CommentDriverNames(), ", "))
+}
+
+// postJSON posts the body as JSON and returns an error if the response isn't successful
+func postJSON(url string, body interface{}, setAuth func(req *http.Request)) error {
+	j, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "Error generating request body")
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	if err != nil {
+		return errors.Wrap(err, "Error generating request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	setAuth(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error posting comment")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("Error posting comment: %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package vcs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterCommentDriver(bitbucketCommentDriver{})
+}
+
+// bitbucketCommentDriver posts comments to Bitbucket Cloud. BITBUCKET_TOKEN must be set to
+// "username:app_password" with access to read repositories and pull requests.
+type bitbucketCommentDriver struct{}
+
+func (bitbucketCommentDriver) Name() string {
+	return "bitbucket"
+}
+
+func (bitbucketCommentDriver) Matches(r Remote) bool {
+	return r.Host == "bitbucket.org"
+}
+
+func (bitbucketCommentDriver) PostComment(r Remote, target CommentTarget, c Comment) error {
+	token := os.Getenv("BITBUCKET_TOKEN")
+	if token == "" {
+		return errors.New("BITBUCKET_TOKEN is required to post comments to Bitbucket")
+	}
+
+	user, password := token, ""
+	if i := strings.Index(token, ":"); i != -1 {
+		user, password = token[:i], token[i+1:]
+	}
+
+	apiURL := os.Getenv("BITBUCKET_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.bitbucket.org/2.0"
+	}
+
+	url := fmt.Sprintf("%s/repositories/%s/commit/%s/comments", strings.TrimSuffix(apiURL, "/"), r.Path, target.Commit)
+	if target.PullRequest != "" {
+		url = fmt.Sprintf("%s/repositories/%s/pullrequests/%s/comments", strings.TrimSuffix(apiURL, "/"), r.Path, target.PullRequest)
+	}
+
+	// Bitbucket doesn't render HTML in comments
+	body := map[string]interface{}{
+		"content": map[string]string{"raw": c.Markdown(false)},
+	}
+
+	return postJSON(url, body, func(req *http.Request) {
+		req.SetBasicAuth(user, password)
+	})
+}
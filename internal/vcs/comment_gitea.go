@@ -0,0 +1,48 @@
+package vcs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterCommentDriver(giteaCommentDriver{})
+}
+
+// giteaCommentDriver posts comments to Gitea, including Codeberg, using GITEA_TOKEN
+type giteaCommentDriver struct{}
+
+func (giteaCommentDriver) Name() string {
+	return "gitea"
+}
+
+func (giteaCommentDriver) Matches(r Remote) bool {
+	return r.Host == "codeberg.org" || strings.Contains(r.Host, "gitea")
+}
+
+func (giteaCommentDriver) PostComment(r Remote, target CommentTarget, c Comment) error {
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		return errors.New("GITEA_TOKEN is required to post comments to Gitea")
+	}
+
+	// Gitea's API doesn't support commit comments
+	if target.PullRequest == "" {
+		return errors.New("Gitea only supports posting comments to pull requests")
+	}
+
+	apiURL := os.Getenv("GITEA_API_URL")
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/api/v1", r.Host)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%s/comments", strings.TrimSuffix(apiURL, "/"), r.Path, target.PullRequest)
+
+	return postJSON(url, map[string]string{"body": c.Markdown(true)}, func(req *http.Request) {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	})
+}
@@ -0,0 +1,49 @@
+package vcs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterCommentDriver(githubCommentDriver{})
+}
+
+// githubCommentDriver posts comments to GitHub and GitHub Enterprise using GITHUB_TOKEN
+type githubCommentDriver struct{}
+
+func (githubCommentDriver) Name() string {
+	return "github"
+}
+
+func (githubCommentDriver) Matches(r Remote) bool {
+	return r.Host == "github.com" || strings.Contains(r.Host, "github")
+}
+
+func (githubCommentDriver) PostComment(r Remote, target CommentTarget, c Comment) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return errors.New("GITHUB_TOKEN is required to post comments to GitHub")
+	}
+
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+		if r.Host != "github.com" {
+			apiURL = fmt.Sprintf("https://%s/api/v3", r.Host)
+		}
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/commits/%s/comments", strings.TrimSuffix(apiURL, "/"), r.Path, target.Commit)
+	if target.PullRequest != "" {
+		url = fmt.Sprintf("%s/repos/%s/issues/%s/comments", strings.TrimSuffix(apiURL, "/"), r.Path, target.PullRequest)
+	}
+
+	return postJSON(url, map[string]string{"body": c.Markdown(true)}, func(req *http.Request) {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	})
+}
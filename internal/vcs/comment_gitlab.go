@@ -0,0 +1,50 @@
+package vcs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterCommentDriver(gitlabCommentDriver{})
+}
+
+// gitlabCommentDriver posts comments to GitLab and self-managed GitLab using GITLAB_TOKEN
+type gitlabCommentDriver struct{}
+
+func (gitlabCommentDriver) Name() string {
+	return "gitlab"
+}
+
+func (gitlabCommentDriver) Matches(r Remote) bool {
+	return strings.Contains(r.Host, "gitlab")
+}
+
+func (gitlabCommentDriver) PostComment(r Remote, target CommentTarget, c Comment) error {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return errors.New("GITLAB_TOKEN is required to post comments to GitLab")
+	}
+
+	apiURL := os.Getenv("GITLAB_API_URL")
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/api/v4", r.Host)
+	}
+
+	project := fmt.Sprintf("%s/projects/%s", strings.TrimSuffix(apiURL, "/"), url.PathEscape(r.Path))
+
+	setAuth := func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	if target.PullRequest != "" {
+		return postJSON(fmt.Sprintf("%s/merge_requests/%s/notes", project, target.PullRequest), map[string]string{"body": c.Markdown(true)}, setAuth)
+	}
+
+	return postJSON(fmt.Sprintf("%s/repository/commits/%s/comments", project, target.Commit), map[string]string{"note": c.Markdown(true)}, setAuth)
+}
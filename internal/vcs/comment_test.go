@@ -0,0 +1,126 @@
+package vcs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		url   string
+		host  string
+		path  string
+		owner string
+	}{
+		{"https://github.com/org/repo", "github.com", "org/repo", "org"},
+		{"https://github.com/org/repo.git", "github.com", "org/repo", "org"},
+		{"git@github.com:org/repo.git", "github.com", "org/repo", "org"},
+		{"ssh://git@gitlab.example.com:2222/org/group/repo.git", "gitlab.example.com", "org/group/repo", "org/group"},
+		{"git::https://bitbucket.org/org/repo", "bitbucket.org", "org/repo", "org"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRemote(tt.url)
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.host, r.Host, tt.url)
+		assert.Equal(t, tt.path, r.Path, tt.url)
+		assert.Equal(t, tt.owner, r.Owner(), tt.url)
+		assert.Equal(t, "repo", r.Name(), tt.url)
+	}
+
+	_, err := ParseRemote("https://github.com")
+	assert.Error(t, err)
+}
+
+func TestCommentDriverForRemote(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo":         "github",
+		"https://github.example.com/org/repo": "github",
+		"git@gitlab.com:org/repo.git":         "gitlab",
+		"https://bitbucket.org/org/repo":      "bitbucket",
+		"https://codeberg.org/org/repo":       "gitea",
+	}
+
+	for u, name := range tests {
+		r, err := ParseRemote(u)
+		require.NoError(t, err)
+
+		d, err := CommentDriverForRemote(r)
+		require.NoError(t, err, u)
+		assert.Equal(t, name, d.Name(), u)
+	}
+
+	r, _ := ParseRemote("https://git.example.com/org/repo")
+	_, err := CommentDriverForRemote(r)
+	assert.Error(t, err)
+}
+
+func TestGitHubPostComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/org/repo/issues/3/comments", r.URL.Path)
+		assert.Equal(t, "token abc", r.Header.Get("Authorization"))
+
+		var d map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&d))
+		assert.Contains(t, d["body"], "<details>")
+		assert.Contains(t, d["body"], "summary text")
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	os.Setenv("GITHUB_TOKEN", "abc")
+	os.Setenv("GITHUB_API_URL", server.URL)
+	defer os.Unsetenv("GITHUB_TOKEN")
+	defer os.Unsetenv("GITHUB_API_URL")
+
+	r, _ := ParseRemote("https://github.com/org/repo")
+	err := githubCommentDriver{}.PostComment(r, CommentTarget{PullRequest: "3"}, Comment{Summary: "summary text", Output: "output"})
+	require.NoError(t, err)
+}
+
+func TestGitLabPostCommitComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/org%2Fgroup%2Frepo/repository/commits/abc123/comments", r.URL.EscapedPath())
+		assert.Equal(t, "abc", r.Header.Get("PRIVATE-TOKEN"))
+
+		var d map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&d))
+		assert.Contains(t, d["note"], "summary text")
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	os.Setenv("GITLAB_TOKEN", "abc")
+	os.Setenv("GITLAB_API_URL", server.URL)
+	defer os.Unsetenv("GITLAB_TOKEN")
+	defer os.Unsetenv("GITLAB_API_URL")
+
+	r, _ := ParseRemote("git@gitlab.com:org/group/repo.git")
+	err := gitlabCommentDriver{}.PostComment(r, CommentTarget{Commit: "abc123"}, Comment{Summary: "summary text", Output: "output"})
+	require.NoError(t, err)
+}
+
+func TestPostCommentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer server.Close()
+
+	os.Setenv("GITHUB_TOKEN", "abc")
+	os.Setenv("GITHUB_API_URL", server.URL)
+	defer os.Unsetenv("GITHUB_TOKEN")
+	defer os.Unsetenv("GITHUB_API_URL")
+
+	r, _ := ParseRemote("https://github.com/org/repo")
+	err := githubCommentDriver{}.PostComment(r, CommentTarget{PullRequest: "3"}, Comment{})
+	assert.EqualError(t, err, `Error posting comment: 404 Not Found: {"message": "Not Found"}`)
+}
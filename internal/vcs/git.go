@@ -0,0 +1,106 @@
+package vcs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// GitSource is a git repository with an optional subdirectory and ref, using the same
+// format as Terraform module sources, e.g. git::https://github.com/org/repo.git//stacks/prod?ref=main
+type GitSource struct {
+	URL    string
+	SubDir string
+	Ref    string
+}
+
+func (s GitSource) String() string {
+	str := fmt.Sprintf("git::%s", s.URL)
+
+	if s.SubDir != "" {
+		str += fmt.Sprintf("//%s", s.SubDir)
+	}
+
+	if s.Ref != "" {
+		str += fmt.Sprintf("?ref=%s", url.QueryEscape(s.Ref))
+	}
+
+	return str
+}
+
+// IsGitSource returns true if the source starts with git:: or is the URL of a .git repo
+func IsGitSource(source string) bool {
+	return strings.HasPrefix(source, "git::") || strings.HasSuffix(strings.SplitN(source, "?", 2)[0], ".git")
+}
+
+// ParseGitSource parses the source into the repository URL, subdirectory and ref
+func ParseGitSource(source string) (GitSource, error) {
+	source = strings.TrimPrefix(source, "git::")
+	ref := ""
+
+	if i := strings.Index(source, "?"); i != -1 {
+		q, err := url.ParseQuery(source[i+1:])
+		if err != nil {
+			return GitSource{}, errors.Wrapf(err, "Invalid git source %s", source)
+		}
+		ref = q.Get("ref")
+		source = source[:i]
+	}
+
+	// The subdirectory is separated by // after the scheme's ://
+	start := 0
+	if i := strings.Index(source, "://"); i != -1 {
+		start = i + 3
+	}
+
+	subDir := ""
+	if i := strings.Index(source[start:], "//"); i != -1 {
+		subDir = source[start+i+2:]
+		source = source[:start+i]
+	}
+
+	if strings.Contains(subDir, "..") {
+		return GitSource{}, errors.Errorf("Invalid git subdirectory %s", subDir)
+	}
+
+	return GitSource{URL: source, SubDir: subDir, Ref: ref}, nil
+}
+
+// Clone clones the repository at the ref into dir and returns the path of the subdirectory
+func Clone(src GitSource, dir string) (string, error) {
+	args := []string{"clone", "--depth", "1"}
+	if src.Ref != "" {
+		args = append(args, "--branch", src.Ref)
+	}
+	args = append(args, src.URL, dir)
+
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil && src.Ref != "" {
+		// The ref might be a commit SHA which can't be used with --branch
+		log.Debugf("Shallow clone of %s failed, cloning full repo: %s", src.URL, out)
+		_ = os.RemoveAll(dir)
+
+		out, err = exec.Command("git", "clone", src.URL, dir).CombinedOutput()
+		if err == nil {
+			cmd := exec.Command("git", "checkout", src.Ref)
+			cmd.Dir = dir
+			out, err = cmd.CombinedOutput()
+		}
+	}
+	if err != nil {
+		return "", errors.Errorf("Error cloning %s: %s", src.URL, strings.TrimSpace(string(out)))
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(src.SubDir))
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Errorf("Directory %s does not exist in %s", src.SubDir, src.URL)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,33 @@
+package vcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGitSource(t *testing.T) {
+	assert.True(t, IsGitSource("git::https://github.com/org/repo//stacks/prod?ref=main"))
+	assert.True(t, IsGitSource("https://github.com/org/repo.git?ref=main"))
+	assert.False(t, IsGitSource("examples/terraform"))
+	assert.False(t, IsGitSource("oci://ghcr.io/org/pack:1.0.0"))
+}
+
+func TestParseGitSource(t *testing.T) {
+	src, err := ParseGitSource("git::https://github.com/org/repo//stacks/prod?ref=main")
+	require.NoError(t, err)
+	assert.Equal(t, GitSource{URL: "https://github.com/org/repo", SubDir: "stacks/prod", Ref: "main"}, src)
+	assert.Equal(t, "git::https://github.com/org/repo//stacks/prod?ref=main", src.String())
+
+	src, err = ParseGitSource("https://github.com/org/packs.git//finops?ref=v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, GitSource{URL: "https://github.com/org/packs.git", SubDir: "finops", Ref: "v1.2.0"}, src)
+
+	src, err = ParseGitSource("git@github.com:org/packs.git")
+	require.NoError(t, err)
+	assert.Equal(t, GitSource{URL: "git@github.com:org/packs.git"}, src)
+
+	_, err = ParseGitSource("https://github.com/org/packs.git//../etc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package vcs
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Remote is a git remote URL split into its host and repository path, e.g.
+// git@github.com:org/repo.git has the host github.com and the path org/repo
+type Remote struct {
+	URL  string
+	Host string
+	Path string
+}
+
+// Owner returns the namespace of the repository, e.g. org in org/repo. For GitLab
+// subgroups this includes the subgroups, e.g. org/group in org/group/repo.
+func (r Remote) Owner() string {
+	i := strings.LastIndex(r.Path, "/")
+	if i == -1 {
+		return ""
+	}
+
+	return r.Path[:i]
+}
+
+// Name returns the name of the repository, e.g. repo in org/repo
+func (r Remote) Name() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// ParseRemote parses HTTP(S), SSH and scp-like git remote URLs
+func ParseRemote(remoteURL string) (Remote, error) {
+	s := strings.TrimSpace(strings.TrimPrefix(remoteURL, "git::"))
+
+	var host, path string
+
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return Remote{}, errors.Wrapf(err, "Invalid remote URL %s", remoteURL)
+		}
+
+		host = u.Hostname()
+		path = u.Path
+	} else if i := strings.Index(s, ":"); i != -1 {
+		// scp-like syntax, e.g. git@github.com:org/repo.git
+		host = s[:i]
+		if j := strings.LastIndex(host, "@"); j != -1 {
+			host = host[j+1:]
+		}
+		path = s[i+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, errors.Errorf("Invalid remote URL %s, expected the URL of a repository", remoteURL)
+	}
+
+	return Remote{URL: remoteURL, Host: strings.ToLower(host), Path: path}, nil
+}
@@ -5,6 +5,13 @@ import _ "embed" // nolint:golint
 //go:embed infracost-usage-example.yml
 var referenceUsageFileContents []byte
 
+//go:embed infracost-usage-profiles.yml
+var usageProfilesFileContents []byte
+
 func GetReferenceUsageFileContents() *[]byte {
 	return &referenceUsageFileContents
 }
+
+func GetUsageProfilesFileContents() *[]byte {
+	return &usageProfilesFileContents
+}
@@ -0,0 +1,83 @@
+// Package pricing lets programs that build on Infracost add their own sources of prices,
+// e.g. an internal rate card, that can be selected with the pricing_source config.
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+
+	"github.com/tidwall/gjson"
+)
+
+// Config is the Infracost config that a source is created from.
+type Config = config.Config
+
+// ProductFilter is the product filter of a price query.
+type ProductFilter = schema.ProductFilter
+
+// PriceFilter is the price filter of a price query.
+type PriceFilter = schema.PriceFilter
+
+// GraphQLQuery is a price query for the Cloud Pricing API.
+type GraphQLQuery struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// Source looks up the prices for price queries. The Cloud Pricing API is the default
+// source, but other sources can be registered with RegisterSource and selected with the
+// pricing_source config.
+type Source interface {
+	// Query returns a result for each query in the same order as the queries. Each result
+	// has the same format as the Cloud Pricing API, e.g.
+	// {"data": {"products": [{"prices": [{"priceHash": "...", "USD": "0.1"}]}]}}.
+	// The product and price filters of a query can be got using QueryFilters.
+	Query(queries []GraphQLQuery) ([]gjson.Result, error)
+}
+
+// SourceFunc creates a pricing source from the config.
+type SourceFunc func(cfg *Config) (Source, error)
+
+var sources = map[string]SourceFunc{}
+var sourcesMu sync.Mutex
+
+// RegisterSource adds a pricing source that can be selected with the pricing_source config.
+// It should be called from an init function.
+func RegisterSource(name string, f SourceFunc) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	sources[name] = f
+}
+
+// NewSource creates the registered pricing source with the name.
+func NewSource(name string, cfg *Config) (Source, error) {
+	sourcesMu.Lock()
+	f, ok := sources[name]
+	sourcesMu.Unlock()
+
+	if !ok {
+		names := make([]string, 0, len(sources))
+		for n := range sources {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("Unknown pricing source %s. Registered sources: %s", name, strings.Join(names, ", "))
+	}
+
+	return f(cfg)
+}
+
+// QueryFilters returns the product and price filters of a price query.
+func QueryFilters(q GraphQLQuery) (*ProductFilter, *PriceFilter) {
+	productFilter, _ := q.Variables["productFilter"].(*schema.ProductFilter)
+	priceFilter, _ := q.Variables["priceFilter"].(*schema.PriceFilter)
+
+	return productFilter, priceFilter
+}
@@ -0,0 +1,49 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type fixedSource struct{}
+
+func (s *fixedSource) Query(queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+	for range queries {
+		results = append(results, gjson.Parse(`{"data": {"products": [{"prices": [{"priceHash": "fixed", "USD": "1"}]}]}}`))
+	}
+
+	return results, nil
+}
+
+func TestNewSource(t *testing.T) {
+	RegisterSource("test-fixed", func(cfg *Config) (Source, error) {
+		return &fixedSource{}, nil
+	})
+
+	s, err := NewSource("test-fixed", config.DefaultConfig())
+	require.NoError(t, err)
+
+	results, err := s.Query([]GraphQLQuery{{}})
+	require.NoError(t, err)
+	assert.Equal(t, "1", results[0].Get("data.products.0.prices.0.USD").String())
+
+	_, err = NewSource("missing", config.DefaultConfig())
+	assert.EqualError(t, err, "Unknown pricing source missing. Registered sources: test-fixed")
+}
+
+func TestQueryFilters(t *testing.T) {
+	region := "us-east-1"
+	q := GraphQLQuery{Variables: map[string]interface{}{
+		"productFilter": &schema.ProductFilter{Region: &region},
+	}}
+
+	productFilter, priceFilter := QueryFilters(q)
+	assert.Equal(t, "us-east-1", *productFilter.Region)
+	assert.Nil(t, priceFilter)
+}
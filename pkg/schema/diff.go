@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DiffResources calculates the diff between two arbitrary sets of resources.
+// The costs of both sets of resources should already have been calculated.
+func DiffResources(past []*Resource, current []*Resource) []*Resource {
+	return schema.DiffResources(past, current)
+}
+
+// DiffProjects calculates the diff between two sets of projects, for example
+// projects loaded from a live scan and from a Terraform plan, or from two branches.
+// Projects are matched by name. The returned projects have their past resources set
+// from the matching past project and their resources set from the matching current
+// project. A project that only exists in one of the sets is diffed against an empty project.
+func DiffProjects(past []*Project, current []*Project) []*Project {
+	return schema.DiffProjects(past, current)
+}
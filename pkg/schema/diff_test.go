@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProjects(t *testing.T) {
+	web := func(cost int64) *Resource {
+		c := decimal.NewFromInt(cost)
+		return &Resource{
+			Name:           "aws_instance.web",
+			MonthlyCost:    &c,
+			CostComponents: []*CostComponent{{Name: "Instance usage", MonthlyCost: &c}},
+		}
+	}
+
+	diff := DiffProjects(
+		[]*Project{{Name: "app", Resources: []*Resource{web(10)}}},
+		[]*Project{{Name: "app", Resources: []*Resource{web(15)}}},
+	)
+
+	require.Len(t, diff, 1)
+	require.Len(t, diff[0].Diff, 1)
+	assert.Equal(t, "aws_instance.web", diff[0].Diff[0].Name)
+	assert.True(t, decimal.NewFromInt(5).Equal(*diff[0].Diff[0].MonthlyCost))
+}
@@ -0,0 +1,25 @@
+package schema
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ResourceMutator adjusts a resource in place, e.g. to force an organization's instance
+// families or to add the cost of mandatory backups. Returning an error stops the run.
+type ResourceMutator = schema.ResourceMutator
+
+// RegisterPrePricingMutator adds a mutator that is run on each resource after the resources
+// are parsed and before their prices are fetched, so changes to the cost components'
+// product and price filters are used when pricing the resource. It should be called from
+// an init function.
+func RegisterPrePricingMutator(m ResourceMutator) {
+	schema.RegisterPrePricingMutator(m)
+}
+
+// RegisterPostPricingMutator adds a mutator that is run on each resource after its prices
+// are fetched and its costs are calculated. The costs are calculated again afterwards so
+// any changes to the quantities or prices are included in the totals. It should be called
+// from an init function.
+func RegisterPostPricingMutator(m ResourceMutator) {
+	schema.RegisterPostPricingMutator(m)
+}
@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterMutators(t *testing.T) {
+	defer schema.ResetMutators()
+
+	var ran []string
+	RegisterPrePricingMutator(func(r *Resource) error {
+		ran = append(ran, "pre "+r.Name)
+		return nil
+	})
+	RegisterPostPricingMutator(func(r *Resource) error {
+		ran = append(ran, "post "+r.Name)
+		return nil
+	})
+
+	project := &Project{Resources: []*Resource{{Name: "aws_instance.web"}}}
+	require.NoError(t, schema.RunPrePricingMutators(project))
+	require.NoError(t, schema.RunPostPricingMutators(project))
+
+	assert.Equal(t, []string{"pre aws_instance.web", "post aws_instance.web"}, ran)
+}
@@ -0,0 +1,28 @@
+// Package schema lets programs that build on Infracost work with its resources, e.g. to
+// adjust them with mutators before and after they're priced.
+package schema
+
+import (
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// Project is the past and planned resources of a project and the diff between them.
+type Project = schema.Project
+
+// ProjectMetadata is the metadata of a project, e.g. its path and VCS repo.
+type ProjectMetadata = schema.ProjectMetadata
+
+// Resource is a priced resource with its cost components and sub resources.
+type Resource = schema.Resource
+
+// CostComponent is a line item of a resource's cost.
+type CostComponent = schema.CostComponent
+
+// ProductFilter is the filter used to find the product of a cost component.
+type ProductFilter = schema.ProductFilter
+
+// AttributeFilter is a filter on an attribute of a product.
+type AttributeFilter = schema.AttributeFilter
+
+// PriceFilter is the filter used to find the price of a cost component's product.
+type PriceFilter = schema.PriceFilter
@@ -0,0 +1,54 @@
+#!/usr/bin/env bash
+
+# This script downloads a subset of prices from the Cloud Pricing API so they can be embedded
+# in the Infracost binary using the embedpricing build tag. The subset is read from a file with
+# lines in the form "<vendor name> <service> <region>", see scripts/embedded_pricing_subset.txt.
+# Requires curl, jq and INFRACOST_API_KEY to be set.
+
+set -euo pipefail
+
+subset_file=${1:-scripts/embedded_pricing_subset.txt}
+out_file=${2:-internal/apiclient/embedded_pricing.json}
+endpoint=${INFRACOST_PRICING_API_ENDPOINT:-https://pricing.api.infracost.io}
+
+if [ -z "${INFRACOST_API_KEY:-}" ]; then
+  echo "INFRACOST_API_KEY must be set" >&2
+  exit 1
+fi
+
+query='query($filter: ProductFilter!) {
+  products(filter: $filter) {
+    vendorName service productFamily region sku
+    attributes { key value }
+    prices {
+      priceHash USD CNY purchaseOption unit description startUsageAmount endUsageAmount
+      termLength termPurchaseOption termOfferingClass effectiveDateStart
+    }
+  }
+}'
+
+tmp_dir=$(mktemp -d)
+trap 'rm -rf "$tmp_dir"' EXIT
+
+i=0
+while read -r vendor service region; do
+  # Skip comments and empty lines
+  if [ -z "$vendor" ] || [[ "$vendor" == \#* ]]; then
+    continue
+  fi
+
+  echo "Downloading prices for $vendor $service $region" >&2
+
+  body=$(jq -n --arg q "$query" --arg v "$vendor" --arg s "$service" --arg r "$region" \
+    '{query: $q, variables: {filter: {vendorName: $v, service: $s, region: $r}}}')
+
+  curl -sSf -X POST "$endpoint/graphql" \
+    -H "Content-Type: application/json" \
+    -H "X-Api-Key: $INFRACOST_API_KEY" \
+    -d "$body" | jq '.data.products' > "$tmp_dir/$i.json"
+
+  i=$((i + 1))
+done < "$subset_file"
+
+jq -s 'add // []' "$tmp_dir"/*.json > "$out_file"
+echo "Saved $(jq length "$out_file") products to $out_file" >&2